@@ -0,0 +1,261 @@
+// Command admin runs operational tasks against the database through the same
+// service layer as the API, so the usual validation applies and every change is
+// written to the audit log.
+//
+//	go run ./cmd/admin --actor ops@example.nhs.uk users create --email a@example.nhs.uk --name "A Person" --role nhs_staff
+//	go run ./cmd/admin users deactivate --email someone@example.com --reason "Duplicate account" --dry-run
+//	go run ./cmd/admin users reactivate --id 5b1c... --reason "Suspended in error"
+//	go run ./cmd/admin --actor ops@example.nhs.uk users import --file new-starters.csv --dry-run
+//	go run ./cmd/admin --dry-run catalog import --type services --file services.csv
+//	go run ./cmd/admin hsds import --file regional-directory.json
+//
+// With --dry-run, targets are looked up and the planned change is printed, but
+// nothing is written and nothing is audited.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/perinatal-mental-health-app/backend/internal/audit"
+	"github.com/perinatal-mental-health-app/backend/internal/auth"
+	"github.com/perinatal-mental-health-app/backend/internal/careteam"
+	"github.com/perinatal-mental-health-app/backend/internal/catalog"
+	config "github.com/perinatal-mental-health-app/backend/internal/configs"
+	db2 "github.com/perinatal-mental-health-app/backend/internal/db"
+	"github.com/perinatal-mental-health-app/backend/internal/encryption"
+	"github.com/perinatal-mental-health-app/backend/internal/hsds"
+	"github.com/perinatal-mental-health-app/backend/internal/httpcache"
+	"github.com/perinatal-mental-health-app/backend/internal/jobs"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"github.com/perinatal-mental-health-app/backend/internal/mail"
+	"github.com/perinatal-mental-health-app/backend/internal/onboarding"
+	"github.com/perinatal-mental-health-app/backend/internal/privacy"
+	"github.com/perinatal-mental-health-app/backend/internal/resources"
+	"github.com/perinatal-mental-health-app/backend/internal/services"
+	"github.com/perinatal-mental-health-app/backend/internal/support_groups"
+	"github.com/perinatal-mental-health-app/backend/internal/user"
+)
+
+// userAgent identifies CLI actions in the audit log
+const userAgent = "admin-cli"
+
+// command runs one subcommand with its own arguments
+type command func(ctx context.Context, a *admin, args []string) error
+
+var commands = map[string]map[string]command{
+	"users": {
+		"list":           listUsers,
+		"create":         createUser,
+		"reset-password": resetPassword,
+		"deactivate":     deactivateUser,
+		"reactivate":     reactivateUser,
+		"import":         importUsers,
+	},
+	"roles": {
+		"set": setRole,
+	},
+	"data-requests": {
+		"list":    listDataRequests,
+		"process": processDataRequest,
+		"reject":  rejectDataRequest,
+	},
+	"resources": {
+		"toggle-featured": toggleFeatured,
+	},
+	"catalog": {
+		"export": exportCatalog,
+		"import": importCatalog,
+	},
+	"hsds": {
+		"import": importHSDS,
+	},
+	"tokens": {
+		"revoke": revokeTokens,
+	},
+}
+
+// admin holds the services and the invocation-wide settings
+type admin struct {
+	dryRun    bool
+	actorID   string
+	actorRole string
+	requestID string
+	out       io.Writer
+
+	recorder   audit.Recorder
+	versions   httpcache.Store
+	auth       auth.Service
+	users      user.Service
+	onboarding onboarding.Service
+	privacy    privacy.Service
+	resources  resources.Service
+	catalog    catalog.Service
+	hsds       hsds.Service
+}
+
+func main() {
+	flag.Usage = usage
+	dryRun := flag.Bool("dry-run", false, "print what would change without writing anything")
+	actor := flag.String("actor", "", "email of the staff account the actions are recorded against")
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	run, ok := commands[args[0]][args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", strings.Join(args[:2], " "))
+		usage()
+		os.Exit(2)
+	}
+
+	logger.Init()
+	cfg := config.Load()
+
+	db := db2.Init(cfg)
+	defer db.Close()
+
+	masterKeys, indexKey, err := encryption.MasterKeysFromConfig(cfg)
+	if err != nil {
+		log.Fatalf("Failed to load encryption keys: %v", err)
+	}
+	keyring := encryption.NewKeyring(encryption.NewStore(db), masterKeys, indexKey)
+
+	mailer, err := mail.SenderFromConfig(cfg)
+	if err != nil {
+		log.Fatalf("Invalid mail configuration: %v", err)
+	}
+
+	// Commands read back their own changes, so nothing goes to a replica
+	primary := db2.NewHandle(db, nil, 0)
+
+	versions := httpcache.NewStore(primary)
+	jobsService := jobs.NewService(jobs.NewStore(db))
+	resourcesService := resources.NewService(resources.NewStore(primary))
+	servicesService := services.NewService(services.NewStore(primary))
+	catalogService := catalog.NewService(
+		catalog.NewStore(db),
+		versions,
+		servicesService,
+		resourcesService,
+		support_groups.NewService(support_groups.NewStore(primary)),
+	)
+
+	a := &admin{
+		dryRun:     *dryRun,
+		requestID:  uuid.New().String(),
+		out:        os.Stdout,
+		recorder:   audit.NewService(audit.NewStore(db)),
+		versions:   versions,
+		auth:       auth.NewService(auth.NewStore(db, keyring), *auth.NewJWTService(cfg.JWTSecret), jobsService),
+		users:      user.NewService(user.NewStore(db, keyring), jobsService, careteam.NewService(careteam.NewStore(db, keyring)), mailer),
+		onboarding: onboarding.NewService(onboarding.NewStore(db), jobsService, mailer),
+		privacy:    privacy.NewService(privacy.NewStore(db, keyring), jobsService),
+		resources:  resourcesService,
+		catalog:    catalogService,
+		hsds:       hsds.NewService(servicesService, catalogService),
+	}
+
+	ctx := context.Background()
+	if *actor != "" {
+		operator, err := a.users.GetUserByEmail(ctx, *actor)
+		if err != nil {
+			log.Fatalf("Unknown actor %s: %v", *actor, err)
+		}
+		a.actorID = operator.ID
+		a.actorRole = string(operator.Role)
+	}
+
+	if err := run(ctx, a, args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: admin [--dry-run] [--actor email] <command> <subcommand> [flags]\n\ncommands:\n")
+
+	groups := make([]string, 0, len(commands))
+	for group := range commands {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		names := make([]string, 0, len(commands[group]))
+		for name := range commands[group] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(os.Stderr, "  %-14s %s\n", group, strings.Join(names, ", "))
+	}
+
+	fmt.Fprintf(os.Stderr, "\nglobal flags:\n")
+	flag.PrintDefaults()
+}
+
+// flags returns a flag set for a subcommand. --dry-run is accepted after the
+// subcommand as well as before it.
+func (a *admin) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.BoolVar(&a.dryRun, "dry-run", a.dryRun, "print what would change without writing anything")
+	return fs
+}
+
+// plan prints the change about to be made and reports whether to go ahead
+func (a *admin) plan(format string, args ...interface{}) bool {
+	if a.dryRun {
+		fmt.Fprintf(a.out, "[dry-run] would "+format+"\n", args...)
+		return false
+	}
+	return true
+}
+
+// record writes the outcome of an action to the audit log. StatusCode is 200
+// when the action succeeded and 500 when it failed.
+func (a *admin) record(ctx context.Context, action, targetType, targetID string, fields []string, actionErr error) {
+	status := http.StatusOK
+	if actionErr != nil {
+		status = http.StatusInternalServerError
+	}
+
+	entry := &audit.Entry{
+		ActorID:       a.actorID,
+		ActorRole:     a.actorRole,
+		Action:        action,
+		TargetType:    targetType,
+		TargetID:      targetID,
+		FieldsChanged: fields,
+		RequestID:     a.requestID,
+		UserAgent:     userAgent,
+		StatusCode:    status,
+	}
+
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if err := a.recorder.Record(saveCtx, entry); err != nil {
+		log.Printf("Failed to write audit entry for %s: %v", action, err)
+	}
+}
+
+// required fails when any of the named flags were left empty
+func required(fs *flag.FlagSet, names ...string) error {
+	for _, name := range names {
+		if fs.Lookup(name).Value.String() == "" {
+			return fmt.Errorf("--%s is required", name)
+		}
+	}
+	return nil
+}
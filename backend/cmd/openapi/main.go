@@ -0,0 +1,33 @@
+// Command openapi writes the generated OpenAPI document to a file or stdout.
+//
+//	go run ./cmd/openapi -o ../docs/api-docs/openapi.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/perinatal-mental-health-app/backend/internal/routes"
+)
+
+func main() {
+	output := flag.String("o", "", "output file (defaults to stdout)")
+	flag.Parse()
+
+	body, err := json.MarshalIndent(routes.APIDocument(), "", "  ")
+	if err != nil {
+		log.Fatalf("failed to encode OpenAPI document: %v", err)
+	}
+	body = append(body, '\n')
+
+	if *output == "" {
+		os.Stdout.Write(body)
+		return
+	}
+
+	if err := os.WriteFile(*output, body, 0o644); err != nil {
+		log.Fatalf("failed to write %s: %v", *output, err)
+	}
+}
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"context"
+	"flag"
+
 	db2 "github.com/perinatal-mental-health-app/backend/internal/db"
 	"github.com/perinatal-mental-health-app/backend/internal/logger"
 	"log"
@@ -9,26 +12,108 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	config "github.com/perinatal-mental-health-app/backend/internal/configs"
+	"github.com/perinatal-mental-health-app/backend/internal/encryption"
+	"github.com/perinatal-mental-health-app/backend/internal/events"
+	"github.com/perinatal-mental-health-app/backend/internal/jobs"
+	"github.com/perinatal-mental-health-app/backend/internal/mail"
 	custommiddleware "github.com/perinatal-mental-health-app/backend/internal/middleware"
 	"github.com/perinatal-mental-health-app/backend/internal/routes"
 )
 
 func main() {
+	demo := flag.Bool("demo", false, "run on seeded in-memory stores without a database")
+	flag.Parse()
+
 	e := echo.New()
-	
+
 	logger.Init()
 	// Load configuration
 	cfg := config.Load()
 
+	if *demo {
+		runDemo(e, cfg)
+		return
+	}
+
 	// Initialize database
 	db := db2.Init(cfg)
-	defer db.Close()
+	dbHandle := db2.NewHandle(db, db2.InitReplica(cfg), cfg.DBReplicaMaxLag)
+	defer dbHandle.Close()
+
+	// Field encryption: data keys are wrapped by the configured master key
+	masterKeys, indexKey, err := encryption.MasterKeysFromConfig(cfg)
+	if err != nil {
+		log.Fatalf("Failed to load encryption keys: %v", err)
+	}
+	encryptionStore := encryption.NewStore(db)
+	keyring := encryption.NewKeyring(encryptionStore, masterKeys, indexKey)
+	reencryptor := encryption.NewReencryptor(encryptionStore, keyring)
+
+	mailer, err := mail.SenderFromConfig(cfg)
+	if err != nil {
+		log.Fatalf("Invalid mail configuration: %v", err)
+	}
+
+	// Move values onto the active data key in the background
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reencryptor.Start(ctx, cfg.ReencryptionInterval)
+
+	// Send reads back to the primary while the replica is behind
+	go dbHandle.MonitorLag(ctx, cfg.DBReplicaLagCheckInterval)
+
+	// Run background jobs and event delivery in-process unless a separate worker handles them
+	if cfg.WorkerEnabled {
+		queues, err := jobs.ParseQueues(cfg.JobQueues)
+		if err != nil {
+			log.Fatalf("Invalid job queue configuration: %v", err)
+		}
+		worker := jobs.NewWorker(jobs.NewStore(db), queues, cfg.JobPollInterval)
+		routes.RegisterJobs(worker, db, keyring, mailer)
+		go worker.Start(ctx)
+
+		dispatcher := events.NewDispatcher(events.NewStore(db), cfg.EventPollInterval)
+		routes.RegisterSubscribers(dispatcher, db, keyring)
+		go dispatcher.Start(ctx)
+	}
+
+	useMiddleware(e)
 
+	// Register routes
+	routes.Register(e, dbHandle, cfg, keyring, reencryptor, mailer)
+
+	start(e)
+}
+
+// runDemo serves the API from in-memory stores; nothing outlives the process
+func runDemo(e *echo.Echo, cfg *config.Config) {
+	queues, err := jobs.ParseQueues(cfg.JobQueues)
+	if err != nil {
+		log.Fatalf("Invalid job queue configuration: %v", err)
+	}
+
+	useMiddleware(e)
+
+	worker, err := routes.RegisterDemo(e, cfg, queues)
+	if err != nil {
+		log.Fatalf("Failed to start demo mode: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go worker.Start(ctx)
+
+	log.Printf("Demo mode: sign in with parent@demo.local, professional@demo.local or staff@demo.local and password %q", routes.DemoPassword)
+	start(e)
+}
+
+func useMiddleware(e *echo.Echo) {
 	// CORS middleware
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: []string{"*"}, // Configure properly for production
-		AllowMethods: []string{echo.GET, echo.HEAD, echo.PUT, echo.PATCH, echo.POST, echo.DELETE},
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
+		AllowOrigins:  []string{"*"}, // Configure properly for production
+		AllowMethods:  []string{echo.GET, echo.HEAD, echo.PUT, echo.PATCH, echo.POST, echo.DELETE},
+		AllowHeaders:  []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, custommiddleware.HeaderIdempotencyKey, "If-None-Match"},
+		ExposeHeaders: []string{custommiddleware.HeaderIdempotentReplayed, "ETag"},
 	}))
 
 	// Security middleware
@@ -40,10 +125,9 @@ func main() {
 
 	// Request validation middleware
 	e.Use(middleware.BodyLimit("10M"))
+}
 
-	// Register routes
-	routes.Register(e, db, cfg)
-
+func start(e *echo.Echo) {
 	// Determine port
 	port := os.Getenv("PORT")
 	if port == "" {
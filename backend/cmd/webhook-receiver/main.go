@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/webhooks"
+)
+
+// A local receiver for testing webhook subscriptions. It verifies each
+// delivery's signature and logs the payload:
+//
+//	go run ./cmd/webhook-receiver -secret whsec_... -addr :9000
+//
+// then point a subscription at http://localhost:9000/ and send a ping.
+func main() {
+	addr := flag.String("addr", ":9000", "address to listen on")
+	secret := flag.String("secret", os.Getenv("WEBHOOK_SECRET"), "signing secret returned when the subscription was created")
+	tolerance := flag.Duration("tolerance", 5*time.Minute, "maximum age of a delivery timestamp")
+	fail := flag.Bool("fail", false, "respond 500 to every delivery to exercise retries")
+	flag.Parse()
+
+	if *secret == "" {
+		log.Fatal("A signing secret is required (-secret or WEBHOOK_SECRET)")
+	}
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+
+		err = webhooks.VerifySignature(*secret, r.Header.Get(webhooks.HeaderSignature), r.Header.Get(webhooks.HeaderTimestamp), body, *tolerance, time.Now())
+		if err != nil {
+			log.Printf("Rejected delivery %s: %v", r.Header.Get(webhooks.HeaderID), err)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		log.Printf("Delivery %s (%s): %s", r.Header.Get(webhooks.HeaderID), r.Header.Get(webhooks.HeaderEvent), body)
+
+		if *fail {
+			http.Error(w, "failing on purpose", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	log.Printf("Listening for webhooks on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	config "github.com/perinatal-mental-health-app/backend/internal/configs"
+	db2 "github.com/perinatal-mental-health-app/backend/internal/db"
+	"github.com/perinatal-mental-health-app/backend/internal/encryption"
+	"github.com/perinatal-mental-health-app/backend/internal/events"
+	"github.com/perinatal-mental-health-app/backend/internal/jobs"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"github.com/perinatal-mental-health-app/backend/internal/mail"
+	"github.com/perinatal-mental-health-app/backend/internal/routes"
+)
+
+// Runs the background job worker and event dispatcher on their own, for deployments that set
+// WORKER_ENABLED=false on the API servers.
+func main() {
+	logger.Init()
+	// Load configuration
+	cfg := config.Load()
+
+	// Initialize database
+	db := db2.Init(cfg)
+	defer db.Close()
+
+	// Jobs read and write encrypted fields, so they need the same keys as the server
+	masterKeys, indexKey, err := encryption.MasterKeysFromConfig(cfg)
+	if err != nil {
+		log.Fatalf("Failed to load encryption keys: %v", err)
+	}
+	keyring := encryption.NewKeyring(encryption.NewStore(db), masterKeys, indexKey)
+
+	mailer, err := mail.SenderFromConfig(cfg)
+	if err != nil {
+		log.Fatalf("Invalid mail configuration: %v", err)
+	}
+
+	queues, err := jobs.ParseQueues(cfg.JobQueues)
+	if err != nil {
+		log.Fatalf("Invalid job queue configuration: %v", err)
+	}
+
+	worker := jobs.NewWorker(jobs.NewStore(db), queues, cfg.JobPollInterval)
+	routes.RegisterJobs(worker, db, keyring, mailer)
+
+	dispatcher := events.NewDispatcher(events.NewStore(db), cfg.EventPollInterval)
+	routes.RegisterSubscribers(dispatcher, db, keyring)
+
+	// Stop claiming jobs on SIGINT/SIGTERM and let running jobs finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go dispatcher.Start(ctx)
+	worker.Start(ctx)
+}
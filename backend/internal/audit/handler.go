@@ -0,0 +1,178 @@
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
+)
+
+type handler struct {
+	service Service
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// ListEntries retrieves a filtered, paginated view of the audit log (admin only)
+func (h *handler) ListEntries(c echo.Context) error {
+	req, err := parseListEntriesRequest(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	entries, err := h.service.ListEntries(c.Request().Context(), req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, entries)
+}
+
+// ExportEntries downloads every matching entry as CSV or JSON (admin only).
+// Exports include the hashes so the chain can be verified offline.
+func (h *handler) ExportEntries(c echo.Context) error {
+	req, err := parseListEntriesRequest(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	format := c.QueryParam("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Format must be json or csv",
+		})
+	}
+
+	filename := fmt.Sprintf("audit-log-%s.%s", time.Now().UTC().Format("20060102T150405Z"), format)
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+	if format == "csv" {
+		res.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+		res.WriteHeader(http.StatusOK)
+
+		writer := csv.NewWriter(res)
+		writer.Write([]string{
+			"sequence", "id", "created_at", "actor_id", "actor_role", "action", "target_type", "target_id",
+			"fields_changed", "request_id", "ip_address", "user_agent", "status_code", "prev_hash", "hash",
+		})
+
+		err = h.service.ExportEntries(c.Request().Context(), req, func(entry *Entry) error {
+			return writer.Write([]string{
+				strconv.FormatInt(entry.Sequence, 10), entry.ID, entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+				entry.ActorID, entry.ActorRole, entry.Action, entry.TargetType, entry.TargetID,
+				strings.Join(entry.FieldsChanged, ";"), entry.RequestID, entry.IPAddress, entry.UserAgent,
+				strconv.Itoa(entry.StatusCode), entry.PrevHash, entry.Hash,
+			})
+		})
+		writer.Flush()
+		return err
+	}
+
+	res.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	res.WriteHeader(http.StatusOK)
+
+	// Stream a JSON array rather than buffering the whole log
+	encoder := json.NewEncoder(res)
+	separator := "["
+	err = h.service.ExportEntries(c.Request().Context(), req, func(entry *Entry) error {
+		if _, err := res.Write([]byte(separator)); err != nil {
+			return err
+		}
+		separator = ","
+		return encoder.Encode(entry)
+	})
+	if separator == "[" {
+		res.Write([]byte("["))
+	}
+	res.Write([]byte("]\n"))
+	return err
+}
+
+// VerifyChain recomputes the hash chain and reports the first broken link (admin only)
+func (h *handler) VerifyChain(c echo.Context) error {
+	result, err := h.service.VerifyChain(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Helper functions
+
+func parseListEntriesRequest(c echo.Context) (*ListEntriesRequest, error) {
+	page := 1
+	if p := c.QueryParam("page"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+			page = parsed
+		}
+	}
+
+	pageSize := 20
+	if ps := c.QueryParam("page_size"); ps != "" {
+		if parsed, err := strconv.Atoi(ps); err == nil && parsed > 0 && parsed <= 100 {
+			pageSize = parsed
+		}
+	}
+
+	from, err := parseTimeParam(c.QueryParam("from"))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid from date")
+	}
+	to, err := parseTimeParam(c.QueryParam("to"))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid to date")
+	}
+
+	return &ListEntriesRequest{
+		Page:       page,
+		PageSize:   pageSize,
+		ActorID:    c.QueryParam("actor_id"),
+		Action:     c.QueryParam("action"),
+		TargetType: c.QueryParam("target_type"),
+		TargetID:   c.QueryParam("target_id"),
+		RequestID:  c.QueryParam("request_id"),
+		From:       from,
+		To:         to,
+		Params:     pagination.ParseQuery(c),
+	}, nil
+}
+
+// parseTimeParam accepts an RFC 3339 timestamp or a plain date
+func parseTimeParam(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,36 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Recorder appends entries to the audit log
+type Recorder interface {
+	Record(ctx context.Context, entry *Entry) error
+}
+
+// Service defines the interface for audit business logic
+type Service interface {
+	Recorder
+	ListEntries(ctx context.Context, req *ListEntriesRequest) (*ListEntriesResponse, error)
+	ExportEntries(ctx context.Context, req *ListEntriesRequest, fn func(*Entry) error) error
+	VerifyChain(ctx context.Context) (*VerifyChainResponse, error)
+}
+
+// Store defines the interface for audit data persistence
+type Store interface {
+	// Append assigns the entry its sequence, timestamp and hashes and writes it
+	Append(ctx context.Context, entry *Entry) error
+	ListEntries(ctx context.Context, req *ListEntriesRequest) (*ListEntriesResponse, error)
+	// StreamEntries calls fn for each matching entry in chain order
+	StreamEntries(ctx context.Context, req *ListEntriesRequest, fn func(*Entry) error) error
+}
+
+// Handler defines the interface for audit HTTP handlers
+type Handler interface {
+	ListEntries(c echo.Context) error
+	ExportEntries(c echo.Context) error
+	VerifyChain(c echo.Context) error
+}
@@ -0,0 +1,280 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
+)
+
+// GenesisHash is the prev_hash of the first entry in the chain
+var GenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// Actions recorded in the audit log
+const (
+	ActionUserList             = "user.list"
+	ActionUserSearch           = "user.search"
+	ActionUserRead             = "user.read"
+	ActionUserProfileRead      = "user.profile_read"
+	ActionUserUpdate           = "user.update"
+	ActionUserDeactivate       = "user.deactivate"
+	ActionUserSuspend          = "user.suspend"
+	ActionUserReactivate       = "user.reactivate"
+	ActionUserScheduleDeletion = "user.schedule_deletion"
+	ActionUserStatusRead       = "user.status_read"
+	ActionUserCreate           = "user.create"
+	ActionUserPasswordReset    = "user.password_reset"
+	ActionUserRoleUpdate       = "user.role_update"
+	ActionUserTokensRevoke     = "user.tokens_revoke"
+	ActionUserImport           = "user.import"
+	ActionUserEmailChange      = "user.email_change"
+	ActionUserMergePreview     = "user.merge_preview"
+	ActionUserMerge            = "user.merge"
+
+	ActionReferralCreate       = "referral.create"
+	ActionReferralRead         = "referral.read"
+	ActionReferralListByItem   = "referral.list_by_item"
+	ActionReferralUpdate       = "referral.update"
+	ActionReferralStatusUpdate = "referral.status_update"
+	ActionReferralDelete       = "referral.delete"
+
+	ActionFeedbackList         = "feedback.list"
+	ActionFeedbackRead         = "feedback.read"
+	ActionFeedbackStatusUpdate = "feedback.status_update"
+
+	ActionPrivacyExport             = "privacy.export"
+	ActionPrivacyDataDownload       = "privacy.data_download_request"
+	ActionPrivacyAccountDeletion    = "privacy.account_deletion_request"
+	ActionPrivacyDataRequestList    = "privacy.data_request_list"
+	ActionPrivacyDataRequestProcess = "privacy.data_request_process"
+	ActionPrivacyDataRequestReject  = "privacy.data_request_reject"
+
+	ActionServiceCreate = "service.create"
+	ActionServiceUpdate = "service.update"
+	ActionServiceDelete = "service.delete"
+
+	ActionResourceCreate         = "resource.create"
+	ActionResourceUpdate         = "resource.update"
+	ActionResourceDelete         = "resource.delete"
+	ActionResourceToggleFeatured = "resource.toggle_featured"
+
+	ActionSupportGroupCreate       = "support_group.create"
+	ActionSupportGroupUpdate       = "support_group.update"
+	ActionSupportGroupDelete       = "support_group.delete"
+	ActionSupportGroupMemberRemove = "support_group.member_remove"
+
+	ActionAuditList   = "audit.list"
+	ActionAuditExport = "audit.export"
+	ActionAuditVerify = "audit.verify"
+
+	ActionEncryptionRotateKey = "encryption.rotate_key"
+
+	ActionOrganisationCreate       = "organisation.create"
+	ActionOrganisationUpdate       = "organisation.update"
+	ActionOrganisationMemberAdd    = "organisation.member_add"
+	ActionOrganisationMemberRemove = "organisation.member_remove"
+
+	ActionJobRetry = "job.retry"
+
+	ActionWebhookCreate       = "webhook.create"
+	ActionWebhookUpdate       = "webhook.update"
+	ActionWebhookDelete       = "webhook.delete"
+	ActionWebhookRotateSecret = "webhook.rotate_secret"
+	ActionWebhookPing         = "webhook.ping"
+	ActionWebhookReplay       = "webhook.replay"
+
+	ActionCatalogExport = "catalog.export"
+	ActionCatalogImport = "catalog.import"
+
+	ActionCareTeamInvite        = "care_team.invite"
+	ActionCareTeamAccept        = "care_team.accept"
+	ActionCareTeamUpdateSharing = "care_team.update_sharing"
+	ActionCareTeamEnd           = "care_team.end"
+	ActionCareTeamAssign        = "care_team.assign"
+	ActionCareTeamList          = "care_team.list"
+	ActionCareTeamBreakGlass    = "care_team.break_glass"
+
+	ActionCaseloadList = "caseload.list"
+
+	ActionEmergencyContactList = "emergency_contact.list"
+
+	ActionSupporterInvite       = "supporter.invite"
+	ActionSupporterAccept       = "supporter.accept"
+	ActionSupporterUpdateShares = "supporter.update_shares"
+	ActionSupporterRevoke       = "supporter.revoke"
+	ActionSupporterView         = "supporter.view"
+)
+
+// Target entity types
+const (
+	TargetUser          = "user"
+	TargetReferral      = "referral"
+	TargetFeedback      = "feedback"
+	TargetPrivacy       = "privacy"
+	TargetService       = "service"
+	TargetResource      = "resource"
+	TargetSupportGroup  = "support_group"
+	TargetAuditLog      = "audit_log"
+	TargetEncryptionKey = "encryption_key"
+	TargetOrganisation  = "organisation"
+	TargetJob           = "job"
+	TargetWebhook       = "webhook"
+	TargetCatalog       = "catalog"
+	TargetCareTeam      = "care_team_relationship"
+	TargetBreakGlass    = "break_glass_access"
+	TargetSupporterLink = "supporter_link"
+)
+
+// Entry represents a single audit log record
+type Entry struct {
+	ID            string    `json:"id" db:"id"`
+	Sequence      int64     `json:"sequence" db:"sequence"`
+	ActorID       string    `json:"actor_id" db:"actor_id"`
+	ActorRole     string    `json:"actor_role" db:"actor_role"`
+	Action        string    `json:"action" db:"action"`
+	TargetType    string    `json:"target_type" db:"target_type"`
+	TargetID      string    `json:"target_id" db:"target_id"`
+	FieldsChanged []string  `json:"fields_changed" db:"fields_changed"`
+	RequestID     string    `json:"request_id" db:"request_id"`
+	IPAddress     string    `json:"ip_address" db:"ip_address"`
+	UserAgent     string    `json:"user_agent" db:"user_agent"`
+	StatusCode    int       `json:"status_code" db:"status_code"`
+	PrevHash      string    `json:"prev_hash" db:"prev_hash"`
+	Hash          string    `json:"hash" db:"hash"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// Snapshot loads the current state of a target so the audit middleware can
+// record which of its fields a write changed
+type Snapshot func(ctx context.Context, targetID string) (interface{}, error)
+
+// Snapshots holds the loaders for each target type that has one
+type Snapshots map[string]Snapshot
+
+// ChangedFields compares two snapshots of a target by their JSON encoding and
+// returns the sorted paths of the fields that differ, with nested objects
+// joined by dots. Only field names are returned, never values.
+func ChangedFields(before, after interface{}) []string {
+	previous, current := flattenJSON(before), flattenJSON(after)
+
+	var changed []string
+	for path, value := range current {
+		if path == "updated_at" {
+			continue
+		}
+		if old, ok := previous[path]; !ok || !reflect.DeepEqual(old, value) {
+			changed = append(changed, path)
+		}
+	}
+	for path := range previous {
+		if _, ok := current[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+
+	sort.Strings(changed)
+	return changed
+}
+
+// ComputeHash returns the SHA-256 over the previous hash and every recorded field.
+// Timestamps are hashed in UTC at microsecond precision, as stored by Postgres.
+func (e *Entry) ComputeHash() string {
+	fields := e.FieldsChanged
+	if fields == nil {
+		fields = []string{}
+	}
+
+	payload, _ := json.Marshal([]interface{}{
+		e.Sequence,
+		e.ActorID,
+		e.ActorRole,
+		e.Action,
+		e.TargetType,
+		e.TargetID,
+		fields,
+		e.RequestID,
+		e.IPAddress,
+		e.UserAgent,
+		e.StatusCode,
+		e.CreatedAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+	})
+
+	hash := sha256.New()
+	hash.Write([]byte(e.PrevHash))
+	hash.Write(payload)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// ListEntriesRequest filters the audit log. From and To bound created_at.
+type ListEntriesRequest struct {
+	Page       int        `json:"page" validate:"min=1"`
+	PageSize   int        `json:"page_size" validate:"min=1,max=100"`
+	ActorID    string     `json:"actor_id,omitempty"`
+	Action     string     `json:"action,omitempty"`
+	TargetType string     `json:"target_type,omitempty"`
+	TargetID   string     `json:"target_id,omitempty"`
+	RequestID  string     `json:"request_id,omitempty"`
+	From       *time.Time `json:"from,omitempty"`
+	To         *time.Time `json:"to,omitempty"`
+	pagination.Params
+}
+
+// ListEntriesResponse represents the response for listing audit entries
+type ListEntriesResponse struct {
+	Entries    []Entry `json:"entries"`
+	Total      *int64  `json:"total,omitempty"`
+	Page       int     `json:"page"`
+	PageSize   int     `json:"page_size"`
+	TotalPages *int    `json:"total_pages,omitempty"`
+	NextCursor *string `json:"next_cursor,omitempty"`
+	PrevCursor *string `json:"prev_cursor,omitempty"`
+}
+
+// VerifyChainResponse reports the result of re-computing the hash chain
+type VerifyChainResponse struct {
+	Valid                bool    `json:"valid"`
+	EntriesChecked       int64   `json:"entries_checked"`
+	FirstInvalidSequence *int64  `json:"first_invalid_sequence,omitempty"`
+	Reason               *string `json:"reason,omitempty"`
+}
+
+// Helper functions
+
+// flattenJSON maps each leaf of v's JSON encoding to its dotted path. Arrays
+// are leaves, so a changed list is reported once under its own name.
+func flattenJSON(v interface{}) map[string]interface{} {
+	leaves := make(map[string]interface{})
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return leaves
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return leaves
+	}
+
+	var walk func(prefix string, value interface{})
+	walk = func(prefix string, value interface{}) {
+		object, ok := value.(map[string]interface{})
+		if !ok || (len(object) == 0 && prefix != "") {
+			leaves[prefix] = value
+			return
+		}
+		for key, child := range object {
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			walk(key, child)
+		}
+	}
+	walk("", decoded)
+
+	return leaves
+}
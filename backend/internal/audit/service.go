@@ -0,0 +1,80 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+)
+
+type service struct {
+	store Store
+}
+
+func NewService(store Store) Service {
+	return &service{
+		store: store,
+	}
+}
+
+// Record appends an entry to the hash chain
+func (s *service) Record(ctx context.Context, entry *Entry) error {
+	if entry.Action == "" || entry.TargetType == "" {
+		return fmt.Errorf("audit entry requires an action and target type")
+	}
+
+	return s.store.Append(ctx, entry)
+}
+
+// ListEntries retrieves a page of audit entries
+func (s *service) ListEntries(ctx context.Context, req *ListEntriesRequest) (*ListEntriesResponse, error) {
+	if req.From != nil && req.To != nil && !req.From.Before(*req.To) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+
+	return s.store.ListEntries(ctx, req)
+}
+
+// ExportEntries streams every matching entry in chain order
+func (s *service) ExportEntries(ctx context.Context, req *ListEntriesRequest, fn func(*Entry) error) error {
+	if req.From != nil && req.To != nil && !req.From.Before(*req.To) {
+		return fmt.Errorf("from must be before to")
+	}
+
+	return s.store.StreamEntries(ctx, req, fn)
+}
+
+// VerifyChain walks the whole log, checking sequence continuity, the link to the
+// previous entry and each entry's own hash. It stops at the first broken link.
+func (s *service) VerifyChain(ctx context.Context) (*VerifyChainResponse, error) {
+	result := &VerifyChainResponse{Valid: true}
+	expectedPrev := GenesisHash
+
+	errStop := fmt.Errorf("chain broken")
+	err := s.store.StreamEntries(ctx, &ListEntriesRequest{}, func(entry *Entry) error {
+		var reason string
+		switch {
+		case entry.Sequence != result.EntriesChecked+1:
+			reason = fmt.Sprintf("expected sequence %d", result.EntriesChecked+1)
+		case entry.PrevHash != expectedPrev:
+			reason = "prev_hash does not match the preceding entry"
+		case entry.Hash != entry.ComputeHash():
+			reason = "hash does not match the entry contents"
+		}
+
+		if reason != "" {
+			sequence := entry.Sequence
+			result.Valid = false
+			result.FirstInvalidSequence = &sequence
+			result.Reason = &reason
+			return errStop
+		}
+
+		result.EntriesChecked++
+		expectedPrev = entry.Hash
+		return nil
+	})
+	if err != nil && err != errStop {
+		return nil, fmt.Errorf("failed to verify audit chain: %w", err)
+	}
+
+	return result, nil
+}
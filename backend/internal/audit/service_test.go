@@ -0,0 +1,133 @@
+package audit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// chainStore keeps the log in memory, chaining entries as the Postgres store does
+type chainStore struct {
+	entries []*Entry
+}
+
+func (s *chainStore) Append(ctx context.Context, entry *Entry) error {
+	entry.Sequence = int64(len(s.entries) + 1)
+	entry.PrevHash = GenesisHash
+	if len(s.entries) > 0 {
+		entry.PrevHash = s.entries[len(s.entries)-1].Hash
+	}
+	entry.CreatedAt = time.Now()
+	entry.Hash = entry.ComputeHash()
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *chainStore) ListEntries(ctx context.Context, req *ListEntriesRequest) (*ListEntriesResponse, error) {
+	return &ListEntriesResponse{}, nil
+}
+
+func (s *chainStore) StreamEntries(ctx context.Context, req *ListEntriesRequest, fn func(*Entry) error) error {
+	for _, entry := range s.entries {
+		copied := *entry
+		if err := fn(&copied); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newChain(t *testing.T, length int) (*chainStore, Service) {
+	t.Helper()
+	store := &chainStore{}
+	svc := NewService(store)
+	for i := 0; i < length; i++ {
+		err := svc.Record(context.Background(), &Entry{
+			ActorID:       "staff-1",
+			Action:        ActionUserUpdate,
+			TargetType:    TargetUser,
+			TargetID:      "user-1",
+			FieldsChanged: []string{"phone_number"},
+			StatusCode:    200,
+		})
+		if err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+	return store, svc
+}
+
+func TestVerifyChain(t *testing.T) {
+	_, svc := newChain(t, 3)
+
+	result, err := svc.VerifyChain(context.Background())
+	if err != nil {
+		t.Fatalf("VerifyChain() error = %v", err)
+	}
+	if !result.Valid || result.EntriesChecked != 3 {
+		t.Errorf("VerifyChain() = %+v, want a valid chain of 3", result)
+	}
+}
+
+func TestVerifyChainDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name         string
+		tamper       func(entries []*Entry) []*Entry
+		wantSequence int64
+	}{
+		{"edited field", func(entries []*Entry) []*Entry {
+			entries[1].ActorID = "staff-2"
+			return entries
+		}, 2},
+		{"edited and rehashed", func(entries []*Entry) []*Entry {
+			entries[1].TargetID = "user-2"
+			entries[1].Hash = entries[1].ComputeHash()
+			return entries
+		}, 3},
+		{"deleted entry", func(entries []*Entry) []*Entry {
+			return append(entries[:1], entries[2:]...)
+		}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, svc := newChain(t, 3)
+			store.entries = tt.tamper(store.entries)
+
+			result, err := svc.VerifyChain(context.Background())
+			if err != nil {
+				t.Fatalf("VerifyChain() error = %v", err)
+			}
+			if result.Valid || result.FirstInvalidSequence == nil || *result.FirstInvalidSequence != tt.wantSequence {
+				t.Errorf("VerifyChain() = %+v, want invalid at sequence %d", result, tt.wantSequence)
+			}
+		})
+	}
+}
+
+func TestChangedFields(t *testing.T) {
+	type address struct {
+		City     string `json:"city"`
+		Postcode string `json:"postcode"`
+	}
+	type profile struct {
+		Name      string    `json:"name"`
+		Phone     *string   `json:"phone"`
+		Tags      []string  `json:"tags"`
+		Address   address   `json:"address"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+
+	phone := "07700 900000"
+	before := profile{Name: "Sam", Tags: []string{"a"}, Address: address{City: "Leeds", Postcode: "LS1"}}
+	after := profile{Name: "Sam", Phone: &phone, Tags: []string{"a", "b"}, Address: address{City: "York", Postcode: "LS1"}, UpdatedAt: time.Now()}
+
+	want := []string{"address.city", "phone", "tags"}
+	if got := ChangedFields(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("ChangedFields() = %v, want %v", got, want)
+	}
+	if got := ChangedFields(before, before); len(got) != 0 {
+		t.Errorf("ChangedFields() for an unchanged target = %v, want none", got)
+	}
+}
@@ -0,0 +1,251 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
+)
+
+type store struct {
+	db *pgxpool.Pool
+}
+
+func NewStore(db *pgxpool.Pool) Store {
+	return &store{
+		db: db,
+	}
+}
+
+// entriesKeyset is the stable sort order used for cursor pagination
+var entriesKeyset = pagination.Keyset{SortColumn: "created_at", IDColumn: "id"}
+
+const entryColumns = `id, sequence, actor_id, actor_role, action, target_type, target_id, fields_changed,
+	       request_id, ip_address, user_agent, status_code, prev_hash, hash, created_at`
+
+// Append links the entry to the current head of the chain. The advisory lock
+// serialises writers so every entry sees the hash of its predecessor.
+func (s *store) Append(ctx context.Context, entry *Entry) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('audit_log'))`); err != nil {
+		return fmt.Errorf("failed to lock audit log: %w", err)
+	}
+
+	var lastSequence int64
+	prevHash := GenesisHash
+	err = tx.QueryRow(ctx, `SELECT sequence, hash FROM audit_log ORDER BY sequence DESC LIMIT 1`).Scan(&lastSequence, &prevHash)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("failed to read audit log head: %w", err)
+	}
+
+	if entry.FieldsChanged == nil {
+		entry.FieldsChanged = []string{}
+	}
+	entry.Sequence = lastSequence + 1
+	entry.PrevHash = prevHash
+	entry.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	entry.Hash = entry.ComputeHash()
+
+	query := `
+		INSERT INTO audit_log (sequence, actor_id, actor_role, action, target_type, target_id, fields_changed,
+		                       request_id, ip_address, user_agent, status_code, prev_hash, hash, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING id`
+
+	err = tx.QueryRow(ctx, query,
+		entry.Sequence, entry.ActorID, entry.ActorRole, entry.Action, entry.TargetType, entry.TargetID,
+		entry.FieldsChanged, entry.RequestID, entry.IPAddress, entry.UserAgent, entry.StatusCode,
+		entry.PrevHash, entry.Hash, entry.CreatedAt,
+	).Scan(&entry.ID)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit entry: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// ListEntries retrieves audit entries, newest first
+func (s *store) ListEntries(ctx context.Context, req *ListEntriesRequest) (*ListEntriesResponse, error) {
+	cursor, err := req.DecodeCursor()
+	if err != nil {
+		return nil, err
+	}
+
+	offset := 0
+	if cursor == nil {
+		offset = (req.Page - 1) * req.PageSize
+	}
+
+	whereClause, args, argIndex := entriesFilter(req)
+
+	response := &ListEntriesResponse{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	// Count total
+	if req.WantTotal() {
+		countQuery := fmt.Sprintf("SELECT COUNT(*) FROM audit_log %s", whereSQL(whereClause))
+		var total int64
+		err := s.db.QueryRow(ctx, countQuery, args...).Scan(&total)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count audit entries: %w", err)
+		}
+
+		totalPages := pagination.TotalPages(total, req.PageSize)
+		response.Total = &total
+		response.TotalPages = &totalPages
+	}
+
+	// Continue from the cursor position
+	if cursor != nil {
+		keysetSQL, keysetArgs := entriesKeyset.Where(cursor, argIndex)
+		whereClause = append(whereClause, keysetSQL)
+		args = append(args, keysetArgs...)
+		argIndex += len(keysetArgs)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM audit_log
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`, entryColumns, whereSQL(whereClause), entriesKeyset.OrderBy(cursor), argIndex, argIndex+1)
+
+	// Read one extra row to detect a further page
+	args = append(args, req.PageSize+1, offset)
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	response.Entries, response.NextCursor, response.PrevCursor = pagination.Paginate(entries, req.PageSize, cursor, offset, entryCursor)
+
+	return response, nil
+}
+
+// StreamEntries calls fn for each matching entry in ascending sequence order
+func (s *store) StreamEntries(ctx context.Context, req *ListEntriesRequest, fn func(*Entry) error) error {
+	whereClause, args, _ := entriesFilter(req)
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM audit_log
+		%s
+		ORDER BY sequence ASC`, entryColumns, whereSQL(whereClause))
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return nil
+}
+
+// Helper functions
+
+func entriesFilter(req *ListEntriesRequest) ([]string, []interface{}, int) {
+	var whereClause []string
+	var args []interface{}
+	argIndex := 1
+
+	addFilter := func(column string, value interface{}) {
+		whereClause = append(whereClause, fmt.Sprintf("%s $%d", column, argIndex))
+		args = append(args, value)
+		argIndex++
+	}
+
+	if req.ActorID != "" {
+		addFilter("actor_id =", req.ActorID)
+	}
+	if req.Action != "" {
+		addFilter("action =", req.Action)
+	}
+	if req.TargetType != "" {
+		addFilter("target_type =", req.TargetType)
+	}
+	if req.TargetID != "" {
+		addFilter("target_id =", req.TargetID)
+	}
+	if req.RequestID != "" {
+		addFilter("request_id =", req.RequestID)
+	}
+	if req.From != nil {
+		addFilter("created_at >=", *req.From)
+	}
+	if req.To != nil {
+		addFilter("created_at <", *req.To)
+	}
+
+	return whereClause, args, argIndex
+}
+
+func whereSQL(whereClause []string) string {
+	if len(whereClause) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(whereClause, " AND ")
+}
+
+func scanEntry(row pgx.Row) (*Entry, error) {
+	var entry Entry
+	err := row.Scan(
+		&entry.ID, &entry.Sequence, &entry.ActorID, &entry.ActorRole, &entry.Action,
+		&entry.TargetType, &entry.TargetID, &entry.FieldsChanged, &entry.RequestID,
+		&entry.IPAddress, &entry.UserAgent, &entry.StatusCode, &entry.PrevHash,
+		&entry.Hash, &entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// entryCursor returns the keyset position of an audit entry
+func entryCursor(entry Entry) pagination.Cursor {
+	return pagination.Cursor{SortValue: entry.CreatedAt, ID: entry.ID}
+}
@@ -44,6 +44,11 @@ func (h *handler) Register(c echo.Context) error {
 		})
 	}
 
+	// A guest registering upgrades their session instead of starting afresh
+	if isGuest, _ := c.Get("guest").(bool); isGuest {
+		req.GuestID, _ = c.Get("user_id").(string)
+	}
+
 	authResp, err := h.service.Register(c.Request().Context(), &req)
 	if err != nil {
 		return c.JSON(http.StatusConflict, map[string]string{
@@ -54,6 +59,18 @@ func (h *handler) Register(c echo.Context) error {
 	return c.JSON(http.StatusCreated, authResp)
 }
 
+// StartGuestSession starts an anonymous guest session
+func (h *handler) StartGuestSession(c echo.Context) error {
+	authResp, err := h.service.StartGuestSession(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusCreated, authResp)
+}
+
 // RefreshToken refreshes an authentication token
 func (h *handler) RefreshToken(c echo.Context) error {
 	var req RefreshTokenRequest
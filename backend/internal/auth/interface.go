@@ -9,11 +9,20 @@ import (
 // Service defines the interface for user business logic
 type Service interface {
 	Login(ctx context.Context, req *LoginRequest) (*AuthResponse, error)
+	// Register creates an account, or upgrades the guest in req.GuestID to one
 	Register(ctx context.Context, req *RegisterRequest) (*AuthResponse, error)
+	StartGuestSession(ctx context.Context) (*AuthResponse, error)
 	RefreshToken(ctx context.Context, req *RefreshTokenRequest) (*AuthResponse, error)
 	ForgotPassword(ctx context.Context, req *ForgotPasswordRequest) error
 	ResetPassword(ctx context.Context, req *ResetPasswordRequest) error
 	ChangePassword(ctx context.Context, userID string, req *ChangePasswordRequest) error
+
+	// Operator methods, used by the admin CLI
+	SetPassword(ctx context.Context, userID, newPassword string) error
+	RevokeTokens(ctx context.Context, userID string) error
+
+	// ExpireGuest is the handler for JobExpireGuest
+	ExpireGuest(ctx context.Context, job ExpireGuestJob) error
 }
 
 // Store defines the interface for user data persistence
@@ -26,12 +35,23 @@ type Store interface {
 	GetUserPasswordHash(ctx context.Context, userID string) (string, error)
 	UpdateUserPassword(ctx context.Context, userID, passwordHash string) error
 	CreateUserWithProfile(ctx context.Context, user *User, phoneNumber, address *string, dateOfBirth *time.Time) error
+	RevokeTokens(ctx context.Context, userID string) error
+	TokensRevokedAt(ctx context.Context, userID string) (*time.Time, error)
+	CreateGuest(ctx context.Context, user *User) error
+	ExtendGuest(ctx context.Context, userID string, expiresAt time.Time) error
+	// UpgradeGuest turns a guest into a full account, keeping its ID and data.
+	// It fails with ErrGuestNotFound if the user is no longer a guest.
+	UpgradeGuest(ctx context.Context, user *User, phoneNumber, address *string, dateOfBirth *time.Time) error
+	// DeleteExpiredGuest deletes a guest whose data expired before now,
+	// reporting whether it did
+	DeleteExpiredGuest(ctx context.Context, userID string, now time.Time) (bool, error)
 }
 
 // Handler defines the interface for user HTTP handlers
 type Handler interface {
 	Login(c echo.Context) error
 	Register(c echo.Context) error
+	StartGuestSession(c echo.Context) error
 	RefreshToken(c echo.Context) error
 	ForgotPassword(c echo.Context) error
 	ResetPassword(c echo.Context) error
@@ -1,20 +1,47 @@
 package auth
 
 import (
+	"context"
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
 type JWTService struct {
-	secretKey []byte
+	secretKey   []byte
+	revocations *revocationCache
 }
 
+// RevocationChecker reports when a user's tokens were last revoked
+type RevocationChecker interface {
+	TokensRevokedAt(ctx context.Context, userID string) (*time.Time, error)
+}
+
+// ErrTokenRevoked is returned for tokens issued before the user's tokens were revoked
+var ErrTokenRevoked = errors.New("token has been revoked")
+
+// ScopeGuest limits a token to the routes open to guests
+const ScopeGuest = "guest"
+
+// Tokens name what they are for in their audience, so a refresh token can't be
+// sent as an access token or the other way round
+const (
+	AudienceAccess  = "access"
+	AudienceRefresh = "refresh"
+)
+
+// RevocationCacheTTL is how long a user's revocation time is reused before it
+// is looked up again. Revocations made through this process apply at once; ones
+// made elsewhere can take this long to reach tokens already issued.
+const RevocationCacheTTL = 30 * time.Second
+
 type Claims struct {
 	UserID string `json:"user_id"`
 	Email  string `json:"email"`
 	Role   string `json:"role"`
+	Scope  string `json:"scope,omitempty"` // Empty for full accounts
 	jwt.RegisteredClaims
 }
 
@@ -24,6 +51,16 @@ func NewJWTService(secretKey string) *JWTService {
 	}
 }
 
+// UseRevocations makes CheckRevoked consult checker, caching each answer for
+// RevocationCacheTTL. Call it before the service is copied into auth.NewService.
+func (j *JWTService) UseRevocations(checker RevocationChecker) {
+	j.revocations = &revocationCache{
+		checker: checker,
+		ttl:     RevocationCacheTTL,
+		entries: make(map[string]revocationEntry),
+	}
+}
+
 // GenerateToken generates a new JWT token for the user
 func (j *JWTService) GenerateToken(userID, email, role string) (string, time.Time, error) {
 	expirationTime := time.Now().Add(24 * time.Hour)
@@ -33,6 +70,7 @@ func (j *JWTService) GenerateToken(userID, email, role string) (string, time.Tim
 		Email:  email,
 		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{AudienceAccess},
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
@@ -55,6 +93,7 @@ func (j *JWTService) GenerateRefreshToken(userID string) (string, time.Time, err
 	claims := &Claims{
 		UserID: userID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{AudienceRefresh},
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
@@ -70,8 +109,63 @@ func (j *JWTService) GenerateRefreshToken(userID string) (string, time.Time, err
 	return tokenString, expirationTime, nil
 }
 
-// ValidateToken validates a JWT token and returns the claims
+// GenerateGuestToken generates a guest-scoped JWT token (valid for 24 hours)
+func (j *JWTService) GenerateGuestToken(userID string) (string, time.Time, error) {
+	expirationTime := time.Now().Add(24 * time.Hour)
+
+	claims := &Claims{
+		UserID: userID,
+		Role:   string(RoleServiceUser),
+		Scope:  ScopeGuest,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{AudienceAccess},
+			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString(j.secretKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return tokenString, expirationTime, nil
+}
+
+// GenerateGuestRefreshToken generates a guest-scoped refresh token that lasts
+// until the guest's data expires, so a returning guest can pick up where they
+// left off
+func (j *JWTService) GenerateGuestRefreshToken(userID string, expiresAt time.Time) (string, error) {
+	claims := &Claims{
+		UserID: userID,
+		Scope:  ScopeGuest,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{AudienceRefresh},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(j.secretKey)
+}
+
+// ValidateToken validates an access token and returns the claims. Refresh
+// tokens are rejected.
 func (j *JWTService) ValidateToken(tokenString string) (*Claims, error) {
+	return j.parse(tokenString, AudienceAccess)
+}
+
+// ValidateRefreshToken validates a refresh token and returns the claims.
+// Access tokens are rejected.
+func (j *JWTService) ValidateRefreshToken(tokenString string) (*Claims, error) {
+	return j.parse(tokenString, AudienceRefresh)
+}
+
+func (j *JWTService) parse(tokenString, audience string) (*Claims, error) {
 	claims := &Claims{}
 
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
@@ -79,7 +173,7 @@ func (j *JWTService) ValidateToken(tokenString string) (*Claims, error) {
 			return nil, errors.New("invalid token signing method")
 		}
 		return j.secretKey, nil
-	})
+	}, jwt.WithAudience(audience))
 
 	if err != nil {
 		return nil, err
@@ -91,3 +185,94 @@ func (j *JWTService) ValidateToken(tokenString string) (*Claims, error) {
 
 	return claims, nil
 }
+
+// CheckRevoked returns ErrTokenRevoked if the token was issued before the user's
+// tokens were last revoked. IssuedAt only has second precision, so a token issued
+// in the same second as the revocation is treated as revoked.
+func (j *JWTService) CheckRevoked(ctx context.Context, claims *Claims) error {
+	if j.revocations == nil {
+		return nil
+	}
+
+	revokedAt, err := j.revocations.get(ctx, claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	if issuedBeforeRevocation(claims, revokedAt) {
+		return ErrTokenRevoked
+	}
+
+	return nil
+}
+
+// forgetRevocation drops the cached revocation time for the user, so a
+// revocation made through this process applies to their next request
+func (j *JWTService) forgetRevocation(userID string) {
+	if j.revocations != nil {
+		j.revocations.forget(userID)
+	}
+}
+
+// Helper functions
+
+func issuedBeforeRevocation(claims *Claims, revokedAt *time.Time) bool {
+	if revokedAt == nil {
+		return false
+	}
+	return claims.IssuedAt == nil || !claims.IssuedAt.After(revokedAt.Truncate(time.Second))
+}
+
+// revocationCache remembers each user's revocation time for ttl, so checking a
+// token doesn't cost a query on every request
+type revocationCache struct {
+	checker RevocationChecker
+	ttl     time.Duration
+
+	mu      sync.Mutex
+	entries map[string]revocationEntry
+	pruned  time.Time
+}
+
+type revocationEntry struct {
+	revokedAt *time.Time
+	fetchedAt time.Time
+}
+
+func (c *revocationCache) get(ctx context.Context, userID string) (*time.Time, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[userID]
+	c.mu.Unlock()
+	if ok && now.Sub(entry.fetchedAt) < c.ttl {
+		return entry.revokedAt, nil
+	}
+
+	revokedAt, err := c.checker.TokensRevokedAt(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[userID] = revocationEntry{revokedAt: revokedAt, fetchedAt: now}
+
+	// Drop expired entries so the map doesn't grow unbounded
+	if now.Sub(c.pruned) > c.ttl {
+		for key, cached := range c.entries {
+			if now.Sub(cached.fetchedAt) >= c.ttl {
+				delete(c.entries, key)
+			}
+		}
+		c.pruned = now
+	}
+
+	return revokedAt, nil
+}
+
+func (c *revocationCache) forget(userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, userID)
+}
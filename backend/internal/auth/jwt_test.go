@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type fixedRevocations map[string]time.Time
+
+func (r fixedRevocations) TokensRevokedAt(ctx context.Context, userID string) (*time.Time, error) {
+	if revokedAt, ok := r[userID]; ok {
+		return &revokedAt, nil
+	}
+	return nil, nil
+}
+
+func TestCheckRevoked(t *testing.T) {
+	revokedAt := time.Date(2024, 3, 15, 12, 0, 0, 500_000_000, time.UTC)
+	jwtService := NewJWTService("test-secret")
+	jwtService.UseRevocations(fixedRevocations{"revoked": revokedAt})
+
+	tests := []struct {
+		name     string
+		userID   string
+		issuedAt time.Time
+		wantErr  bool
+	}{
+		{"never revoked", "active", revokedAt.Add(-time.Hour), false},
+		{"issued before revocation", "revoked", revokedAt.Add(-time.Hour), true},
+		{"issued in the same second", "revoked", revokedAt.Truncate(time.Second), true},
+		{"issued after revocation", "revoked", revokedAt.Add(time.Second), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := &Claims{
+				UserID:           tt.userID,
+				RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(tt.issuedAt)},
+			}
+			err := jwtService.CheckRevoked(context.Background(), claims)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckRevoked() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTokensOnlyValidateForTheirAudience(t *testing.T) {
+	jwtService := NewJWTService("test-secret")
+
+	access, _, _ := jwtService.GenerateToken("user-1", "parent@example.com", string(RoleServiceUser))
+	refresh, _, _ := jwtService.GenerateRefreshToken("user-1")
+	guestRefresh, _ := jwtService.GenerateGuestRefreshToken("guest-1", time.Now().Add(GuestRetention))
+
+	if _, err := jwtService.ValidateToken(access); err != nil {
+		t.Errorf("ValidateToken() of an access token error = %v", err)
+	}
+	for name, token := range map[string]string{"refresh": refresh, "guest refresh": guestRefresh} {
+		if _, err := jwtService.ValidateToken(token); err == nil {
+			t.Errorf("ValidateToken() accepted a %s token", name)
+		}
+		if _, err := jwtService.ValidateRefreshToken(token); err != nil {
+			t.Errorf("ValidateRefreshToken() of a %s token error = %v", name, err)
+		}
+	}
+	if _, err := jwtService.ValidateRefreshToken(access); err == nil {
+		t.Error("ValidateRefreshToken() accepted an access token")
+	}
+}
+
+// countingRevocations counts lookups so tests can see when the cache is used
+type countingRevocations struct {
+	fixedRevocations
+	lookups int
+}
+
+func (r *countingRevocations) TokensRevokedAt(ctx context.Context, userID string) (*time.Time, error) {
+	r.lookups++
+	return r.fixedRevocations.TokensRevokedAt(ctx, userID)
+}
+
+func TestCheckRevokedCachesLookups(t *testing.T) {
+	ctx := context.Background()
+	checker := &countingRevocations{fixedRevocations: fixedRevocations{}}
+	jwtService := NewJWTService("test-secret")
+	jwtService.UseRevocations(checker)
+
+	claims := &Claims{
+		UserID:           "user-1",
+		RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))},
+	}
+	for i := 0; i < 3; i++ {
+		if err := jwtService.CheckRevoked(ctx, claims); err != nil {
+			t.Fatalf("CheckRevoked() error = %v", err)
+		}
+	}
+	if checker.lookups != 1 {
+		t.Errorf("CheckRevoked() looked up revocations %d times, want 1", checker.lookups)
+	}
+
+	// Revoking through this process applies to the next request
+	checker.fixedRevocations["user-1"] = time.Now()
+	jwtService.forgetRevocation("user-1")
+	if err := jwtService.CheckRevoked(ctx, claims); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("CheckRevoked() after revocation error = %v, want ErrTokenRevoked", err)
+	}
+}
@@ -0,0 +1,229 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+)
+
+// memoryStore keeps users in memory for tests and demo mode. Domain events are
+// not published.
+type memoryStore struct {
+	db *memdb.DB
+}
+
+func NewMemoryStore(db *memdb.DB) Store {
+	return &memoryStore{
+		db: db,
+	}
+}
+
+// GetUserByEmail retrieves a user by email
+func (s *memoryStore) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	row, ok := s.db.UserByEmail(email)
+	if !ok {
+		return nil, fmt.Errorf("user not found")
+	}
+	return userFromRow(row), nil
+}
+
+// GetUserByID retrieves a user by ID
+func (s *memoryStore) GetUserByID(ctx context.Context, userID string) (*User, error) {
+	row, ok := s.db.User(userID)
+	if !ok {
+		return nil, fmt.Errorf("user not found")
+	}
+	return userFromRow(row), nil
+}
+
+// CreateUser creates a new user with an empty profile
+func (s *memoryStore) CreateUser(ctx context.Context, user *User) error {
+	return s.CreateUserWithProfile(ctx, user, nil, nil, nil)
+}
+
+// UpdateLastLogin updates the user's last login time
+func (s *memoryStore) UpdateLastLogin(ctx context.Context, userID string) error {
+	now := time.Now()
+	s.db.UpdateUser(userID, func(row *memdb.User) bool {
+		row.LastLoginAt = &now
+		row.UpdatedAt = now
+		return true
+	})
+	return nil
+}
+
+// UpdatePassword updates the user's password
+func (s *memoryStore) UpdatePassword(ctx context.Context, userID, passwordHash string) error {
+	s.db.UpdateUser(userID, func(row *memdb.User) bool {
+		row.PasswordHash = passwordHash
+		row.UpdatedAt = time.Now()
+		return true
+	})
+	return nil
+}
+
+// GetUserPasswordHash retrieves an active user's password hash
+func (s *memoryStore) GetUserPasswordHash(ctx context.Context, userID string) (string, error) {
+	row, ok := s.db.User(userID)
+	if !ok || !row.IsActive {
+		return "", fmt.Errorf("user not found")
+	}
+	return row.PasswordHash, nil
+}
+
+// UpdateUserPassword updates an active user's password
+func (s *memoryStore) UpdateUserPassword(ctx context.Context, userID, passwordHash string) error {
+	s.db.UpdateUser(userID, func(row *memdb.User) bool {
+		if !row.IsActive {
+			return false
+		}
+		row.PasswordHash = passwordHash
+		row.UpdatedAt = time.Now()
+		return true
+	})
+	return nil
+}
+
+// RevokeTokens invalidates every token issued to the user before now
+func (s *memoryStore) RevokeTokens(ctx context.Context, userID string) error {
+	now := time.Now()
+	_, ok := s.db.UpdateUser(userID, func(row *memdb.User) bool {
+		row.TokensRevokedAt = &now
+		row.UpdatedAt = now
+		return true
+	})
+	if !ok {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// TokensRevokedAt returns when the user's tokens were last revoked, or nil if never
+func (s *memoryStore) TokensRevokedAt(ctx context.Context, userID string) (*time.Time, error) {
+	row, ok := s.db.User(userID)
+	if !ok {
+		return nil, fmt.Errorf("user not found")
+	}
+	return row.TokensRevokedAt, nil
+}
+
+// CreateUserWithProfile creates a new user with profile information
+func (s *memoryStore) CreateUserWithProfile(ctx context.Context, user *User, phoneNumber, address *string, dateOfBirth *time.Time) error {
+	err := s.db.InsertUser(memdb.User{
+		ID:           user.ID,
+		Email:        user.Email,
+		FullName:     user.FullName,
+		Role:         string(user.Role),
+		PasswordHash: user.PasswordHash,
+		IsActive:     user.IsActive,
+		CreatedAt:    user.CreatedAt,
+		UpdatedAt:    user.UpdatedAt,
+	}, memdb.Profile{
+		PhoneNumber: phoneNumber,
+		Address:     address,
+		DateOfBirth: dateOfBirth,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return nil
+}
+
+// CreateGuest creates a guest with an empty profile
+func (s *memoryStore) CreateGuest(ctx context.Context, user *User) error {
+	err := s.db.InsertUser(memdb.User{
+		ID:             user.ID,
+		Email:          user.Email,
+		FullName:       user.FullName,
+		Role:           string(user.Role),
+		IsActive:       user.IsActive,
+		IsGuest:        true,
+		GuestExpiresAt: user.GuestExpiresAt,
+		CreatedAt:      user.CreatedAt,
+		UpdatedAt:      user.UpdatedAt,
+	}, memdb.Profile{
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create guest: %w", err)
+	}
+
+	return nil
+}
+
+// ExtendGuest moves a guest's expiry
+func (s *memoryStore) ExtendGuest(ctx context.Context, userID string, expiresAt time.Time) error {
+	_, ok := s.db.UpdateUser(userID, func(row *memdb.User) bool {
+		if !row.IsGuest {
+			return false
+		}
+		row.GuestExpiresAt = &expiresAt
+		row.UpdatedAt = time.Now()
+		return true
+	})
+	if !ok {
+		return ErrGuestNotFound
+	}
+	return nil
+}
+
+// UpgradeGuest turns a guest into a full account with profile information
+func (s *memoryStore) UpgradeGuest(ctx context.Context, user *User, phoneNumber, address *string, dateOfBirth *time.Time) error {
+	_, ok := s.db.UpdateUser(user.ID, func(row *memdb.User) bool {
+		if !row.IsGuest || !row.IsActive {
+			return false
+		}
+		row.Email = user.Email
+		row.FullName = user.FullName
+		row.Role = string(user.Role)
+		row.PasswordHash = user.PasswordHash
+		row.IsGuest = false
+		row.GuestExpiresAt = nil
+		row.UpdatedAt = user.UpdatedAt
+		return true
+	})
+	if !ok {
+		return ErrGuestNotFound
+	}
+
+	s.db.UpdateProfile(user.ID, func(profile *memdb.Profile) {
+		profile.PhoneNumber = phoneNumber
+		profile.Address = address
+		profile.DateOfBirth = dateOfBirth
+		profile.UpdatedAt = user.UpdatedAt
+	})
+	return nil
+}
+
+// DeleteExpiredGuest deletes a guest whose data expired before now. Rows kept
+// by other in-memory stores are not cascaded.
+func (s *memoryStore) DeleteExpiredGuest(ctx context.Context, userID string, now time.Time) (bool, error) {
+	return s.db.DeleteUserIf(userID, func(row memdb.User) bool {
+		return row.IsGuest && row.GuestExpiresAt != nil && !row.GuestExpiresAt.After(now)
+	}), nil
+}
+
+// Helper functions
+
+func userFromRow(row memdb.User) *User {
+	return &User{
+		ID:              row.ID,
+		Email:           row.Email,
+		FullName:        row.FullName,
+		Role:            UserRole(row.Role),
+		PasswordHash:    row.PasswordHash,
+		IsActive:        row.IsActive,
+		LastLoginAt:     row.LastLoginAt,
+		TokensRevokedAt: row.TokensRevokedAt,
+		IsGuest:         row.IsGuest,
+		GuestExpiresAt:  row.GuestExpiresAt,
+		CreatedAt:       row.CreatedAt,
+		UpdatedAt:       row.UpdatedAt,
+	}
+}
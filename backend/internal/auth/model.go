@@ -1,9 +1,26 @@
 package auth
 
 import (
+	"errors"
 	"time"
 )
 
+// Guests can use the app without an account until their data expires. Each
+// token refresh pushes the expiry back by GuestRetention.
+const (
+	GuestRetention = 30 * 24 * time.Hour
+	guestName      = "Guest"
+)
+
+// Background job that deletes a guest once their retention period has passed
+const (
+	JobQueue       = "privacy"
+	JobExpireGuest = "auth.expire_guest"
+)
+
+// ErrGuestNotFound is returned when upgrading a session that is no longer a guest
+var ErrGuestNotFound = errors.New("guest session not found")
+
 // LoginRequest represents the login request payload
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
@@ -19,6 +36,8 @@ type RegisterRequest struct {
 	PhoneNumber *string `json:"phone_number,omitempty"`
 	Address     *string `json:"address,omitempty"`
 	DateOfBirth *string `json:"date_of_birth,omitempty"` // Expected format: YYYY-MM-DD
+	// GuestID is set from a guest token, so the guest's data carries over to the new account
+	GuestID string `json:"-"`
 }
 
 // AuthResponse represents the authentication response
@@ -35,6 +54,7 @@ type UserInfo struct {
 	Email    string `json:"email"`
 	FullName string `json:"full_name"`
 	Role     string `json:"role"`
+	IsGuest  bool   `json:"is_guest"`
 }
 
 // RefreshTokenRequest represents the refresh token request
@@ -55,15 +75,18 @@ type ResetPasswordRequest struct {
 
 // User represents a user in the auth system
 type User struct {
-	ID           string     `json:"id" db:"id"`
-	Email        string     `json:"email" db:"email"`
-	FullName     string     `json:"full_name" db:"full_name"`
-	Role         UserRole   `json:"role" db:"role"`
-	PasswordHash string     `json:"-" db:"password_hash"`
-	IsActive     bool       `json:"is_active" db:"is_active"`
-	LastLoginAt  *time.Time `json:"last_login_at,omitempty" db:"last_login_at"`
-	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+	ID              string     `json:"id" db:"id"`
+	Email           string     `json:"email" db:"email"`
+	FullName        string     `json:"full_name" db:"full_name"`
+	Role            UserRole   `json:"role" db:"role"`
+	PasswordHash    string     `json:"-" db:"password_hash"`
+	IsActive        bool       `json:"is_active" db:"is_active"`
+	LastLoginAt     *time.Time `json:"last_login_at,omitempty" db:"last_login_at"`
+	TokensRevokedAt *time.Time `json:"-" db:"tokens_revoked_at"`
+	IsGuest         bool       `json:"is_guest" db:"is_guest"`
+	GuestExpiresAt  *time.Time `json:"guest_expires_at,omitempty" db:"guest_expires_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 type ChangePasswordRequest struct {
@@ -71,6 +94,11 @@ type ChangePasswordRequest struct {
 	NewPassword     string `json:"new_password" validate:"required,min=8"`
 }
 
+// ExpireGuestJob is the payload of JobExpireGuest
+type ExpireGuestJob struct {
+	UserID string `json:"user_id"`
+}
+
 // UserRole represents user roles
 type UserRole string
 
@@ -2,22 +2,28 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/perinatal-mental-health-app/backend/internal/jobs"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type service struct {
 	store      Store
 	jwtService JWTService
+	queue      jobs.Enqueuer
 }
 
-func NewService(store Store, jwtService JWTService) Service {
+func NewService(store Store, jwtService JWTService, queue jobs.Enqueuer) Service {
 	return &service{
 		store:      store,
 		jwtService: jwtService,
+		queue:      queue,
 	}
 }
 
@@ -75,8 +81,13 @@ func (s *service) Login(ctx context.Context, req *LoginRequest) (*AuthResponse,
 // Register creates a new user account
 // Update the Register method in backend/internal/auth/service.go
 
-// Register creates a new user account with profile information
+// Register creates a new user account with profile information. A guest
+// registering keeps their user ID, so their journal and bookmarks come with them.
 func (s *service) Register(ctx context.Context, req *RegisterRequest) (*AuthResponse, error) {
+	if req.GuestID != "" && UserRole(req.Role) != RoleServiceUser {
+		return nil, fmt.Errorf("guest sessions can only be upgraded to a service user account")
+	}
+
 	// Check if user already exists
 	existingUser, _ := s.store.GetUserByEmail(ctx, req.Email)
 	if existingUser != nil {
@@ -91,6 +102,9 @@ func (s *service) Register(ctx context.Context, req *RegisterRequest) (*AuthResp
 
 	// Create user
 	userID := uuid.New().String()
+	if req.GuestID != "" {
+		userID = req.GuestID
+	}
 	user := &User{
 		ID:           userID,
 		Email:        req.Email,
@@ -110,7 +124,14 @@ func (s *service) Register(ctx context.Context, req *RegisterRequest) (*AuthResp
 		}
 	}
 
-	err = s.store.CreateUserWithProfile(ctx, user, req.PhoneNumber, req.Address, dateOfBirth)
+	if req.GuestID != "" {
+		err = s.store.UpgradeGuest(ctx, user, req.PhoneNumber, req.Address, dateOfBirth)
+		if errors.Is(err, ErrGuestNotFound) {
+			return nil, err
+		}
+	} else {
+		err = s.store.CreateUserWithProfile(ctx, user, req.PhoneNumber, req.Address, dateOfBirth)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
@@ -140,10 +161,41 @@ func (s *service) Register(ctx context.Context, req *RegisterRequest) (*AuthResp
 	}, nil
 }
 
+// StartGuestSession creates a guest and returns guest-scoped tokens. Guests
+// can browse, bookmark and keep a journal; their data is deleted if they don't
+// come back within GuestRetention.
+func (s *service) StartGuestSession(ctx context.Context) (*AuthResponse, error) {
+	now := time.Now()
+	expiresAt := now.Add(GuestRetention)
+	userID := uuid.New().String()
+
+	// The placeholder email keeps the users row valid and can't receive mail
+	err := s.store.CreateGuest(ctx, &User{
+		ID:             userID,
+		Email:          "guest-" + userID + "@guest.invalid",
+		FullName:       guestName,
+		Role:           RoleServiceUser,
+		IsActive:       true,
+		IsGuest:        true,
+		GuestExpiresAt: &expiresAt,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create guest: %w", err)
+	}
+
+	if err := s.scheduleGuestExpiry(ctx, userID, expiresAt); err != nil {
+		return nil, err
+	}
+
+	return s.guestResponse(userID, expiresAt)
+}
+
 // RefreshToken refreshes an authentication token
 func (s *service) RefreshToken(ctx context.Context, req *RefreshTokenRequest) (*AuthResponse, error) {
 	// Validate the refresh token
-	claims, err := s.jwtService.ValidateToken(req.RefreshToken)
+	claims, err := s.jwtService.ValidateRefreshToken(req.RefreshToken)
 	if err != nil {
 		return nil, fmt.Errorf("invalid refresh token")
 	}
@@ -158,6 +210,20 @@ func (s *service) RefreshToken(ctx context.Context, req *RefreshTokenRequest) (*
 		return nil, fmt.Errorf("account is deactivated")
 	}
 
+	if issuedBeforeRevocation(claims, user.TokensRevokedAt) {
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+
+	// A returning guest keeps their data for another retention period. The
+	// expiry job already queued reschedules itself when it finds the new date.
+	if user.IsGuest {
+		expiresAt := time.Now().Add(GuestRetention)
+		if err := s.store.ExtendGuest(ctx, user.ID, expiresAt); err != nil {
+			return nil, fmt.Errorf("failed to extend guest session: %w", err)
+		}
+		return s.guestResponse(user.ID, expiresAt)
+	}
+
 	// Generate new tokens
 	token, expiresAt, err := s.jwtService.GenerateToken(user.ID, user.Email, string(user.Role))
 	if err != nil {
@@ -253,3 +319,98 @@ func (s *service) ChangePassword(ctx context.Context, userID string, req *Change
 
 	return nil
 }
+
+// SetPassword replaces a user's password without the current one and revokes
+// their existing tokens
+func (s *service) SetPassword(ctx context.Context, userID, newPassword string) error {
+	if len(newPassword) < 8 {
+		return fmt.Errorf("password must be at least 8 characters")
+	}
+
+	if _, err := s.store.GetUserByID(ctx, userID); err != nil {
+		return fmt.Errorf("user not found")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password")
+	}
+
+	if err := s.store.UpdatePassword(ctx, userID, string(hashedPassword)); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	return s.RevokeTokens(ctx, userID)
+}
+
+// RevokeTokens signs the user out everywhere by invalidating every token issued so far
+func (s *service) RevokeTokens(ctx context.Context, userID string) error {
+	if err := s.store.RevokeTokens(ctx, userID); err != nil {
+		return err
+	}
+
+	s.jwtService.forgetRevocation(userID)
+	return nil
+}
+
+// ExpireGuest is the job handler that deletes a guest, and everything they
+// saved, once their retention period has passed. Guests who registered are
+// left alone, and guests who came back are checked again at their new expiry.
+func (s *service) ExpireGuest(ctx context.Context, job ExpireGuestJob) error {
+	user, err := s.store.GetUserByID(ctx, job.UserID)
+	if err != nil || !user.IsGuest || user.GuestExpiresAt == nil {
+		// Already deleted or upgraded
+		return nil
+	}
+
+	if user.GuestExpiresAt.After(time.Now()) {
+		return s.scheduleGuestExpiry(ctx, user.ID, *user.GuestExpiresAt)
+	}
+
+	deleted, err := s.store.DeleteExpiredGuest(ctx, user.ID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if deleted {
+		logger.Info("Deleted expired guest", zap.String("user_id", user.ID))
+	}
+	return nil
+}
+
+// Helper functions
+
+func (s *service) scheduleGuestExpiry(ctx context.Context, userID string, expiresAt time.Time) error {
+	_, err := s.queue.Enqueue(ctx, JobExpireGuest, ExpireGuestJob{UserID: userID}, &jobs.EnqueueOptions{
+		Queue: JobQueue,
+		RunAt: expiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to schedule guest expiry: %w", err)
+	}
+	return nil
+}
+
+func (s *service) guestResponse(userID string, expiresAt time.Time) (*AuthResponse, error) {
+	token, tokenExpiresAt, err := s.jwtService.GenerateGuestToken(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token")
+	}
+
+	refreshToken, err := s.jwtService.GenerateGuestRefreshToken(userID, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token")
+	}
+
+	return &AuthResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresAt:    tokenExpiresAt,
+		User: UserInfo{
+			ID:       userID,
+			FullName: guestName,
+			Role:     string(RoleServiceUser),
+			IsGuest:  true,
+		},
+	}, nil
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/jobs"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+)
+
+// recordedJobs keeps the run time of each enqueued job
+type recordedJobs []time.Time
+
+func (r *recordedJobs) Enqueue(ctx context.Context, jobType string, payload interface{}, opts *jobs.EnqueueOptions) (*jobs.Job, error) {
+	*r = append(*r, opts.RunAt)
+	return &jobs.Job{JobType: jobType, RunAt: opts.RunAt}, nil
+}
+
+func TestGuestExpiry(t *testing.T) {
+	logger.Init()
+	ctx := context.Background()
+	store := NewMemoryStore(memdb.New())
+	var queued recordedJobs
+	svc := NewService(store, *NewJWTService("test-secret"), &queued)
+
+	guest, err := svc.StartGuestSession(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !guest.User.IsGuest || len(queued) != 1 {
+		t.Fatalf("StartGuestSession() = %+v with %d jobs queued, want a guest with its expiry queued", guest.User, len(queued))
+	}
+
+	// Coming back pushes the expiry out, so the job due at the old expiry
+	// requeues itself instead of deleting the guest
+	refreshed, err := svc.RefreshToken(ctx, &RefreshTokenRequest{RefreshToken: guest.RefreshToken})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !refreshed.User.IsGuest {
+		t.Errorf("RefreshToken() of a guest = %+v, want a guest", refreshed.User)
+	}
+	if err := svc.ExpireGuest(ctx, ExpireGuestJob{UserID: guest.User.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.GetUserByID(ctx, guest.User.ID); err != nil {
+		t.Fatalf("ExpireGuest() before the expiry deleted the guest: %v", err)
+	}
+	if len(queued) != 2 || !queued[1].After(queued[0]) {
+		t.Errorf("ExpireGuest() before the expiry queued %v, want a later check", queued)
+	}
+
+	if err := store.ExtendGuest(ctx, guest.User.ID, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.ExpireGuest(ctx, ExpireGuestJob{UserID: guest.User.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.GetUserByID(ctx, guest.User.ID); err == nil {
+		t.Error("ExpireGuest() after the expiry kept the guest")
+	}
+
+	// Guests who registered are never expired
+	upgraded, err := svc.StartGuestSession(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = svc.Register(ctx, &RegisterRequest{
+		Email: "parent@example.com", Password: "a-long-password", FullName: "Sam Parent",
+		Role: string(RoleServiceUser), GuestID: upgraded.User.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.ExtendGuest(ctx, upgraded.User.ID, time.Now().Add(-time.Minute)); err != ErrGuestNotFound {
+		t.Errorf("ExtendGuest() after Register() error = %v, want ErrGuestNotFound", err)
+	}
+	if err := svc.ExpireGuest(ctx, ExpireGuestJob{UserID: upgraded.User.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.GetUserByID(ctx, upgraded.User.ID); err != nil {
+		t.Errorf("ExpireGuest() deleted a registered account: %v", err)
+	}
+}
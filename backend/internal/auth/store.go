@@ -6,22 +6,27 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/perinatal-mental-health-app/backend/internal/encryption"
+	"github.com/perinatal-mental-health-app/backend/internal/events"
 )
 
 type store struct {
-	db *pgxpool.Pool
+	db     *pgxpool.Pool
+	cipher encryption.Cipher
 }
 
-func NewStore(db *pgxpool.Pool) Store {
+func NewStore(db *pgxpool.Pool, cipher encryption.Cipher) Store {
 	return &store{
-		db: db,
+		db:     db,
+		cipher: cipher,
 	}
 }
 
 // GetUserByEmail retrieves a user by email
 func (s *store) GetUserByEmail(ctx context.Context, email string) (*User, error) {
 	query := `
-		SELECT id, email, full_name, role, password_hash, is_active, last_login_at, created_at, updated_at
+		SELECT id, email, full_name, role, password_hash, is_active, last_login_at, tokens_revoked_at, is_guest,
+		       guest_expires_at, created_at, updated_at
 		FROM users 
 		WHERE email = $1
 	`
@@ -35,6 +40,9 @@ func (s *store) GetUserByEmail(ctx context.Context, email string) (*User, error)
 		&user.PasswordHash,
 		&user.IsActive,
 		&user.LastLoginAt,
+		&user.TokensRevokedAt,
+		&user.IsGuest,
+		&user.GuestExpiresAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -49,7 +57,8 @@ func (s *store) GetUserByEmail(ctx context.Context, email string) (*User, error)
 // GetUserByID retrieves a user by ID
 func (s *store) GetUserByID(ctx context.Context, userID string) (*User, error) {
 	query := `
-		SELECT id, email, full_name, role, password_hash, is_active, last_login_at, created_at, updated_at
+		SELECT id, email, full_name, role, password_hash, is_active, last_login_at, tokens_revoked_at, is_guest,
+		       guest_expires_at, created_at, updated_at
 		FROM users 
 		WHERE id = $1
 	`
@@ -63,6 +72,9 @@ func (s *store) GetUserByID(ctx context.Context, userID string) (*User, error) {
 		&user.PasswordHash,
 		&user.IsActive,
 		&user.LastLoginAt,
+		&user.TokensRevokedAt,
+		&user.IsGuest,
+		&user.GuestExpiresAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -159,6 +171,155 @@ func (s *store) UpdateUserPassword(ctx context.Context, userID, passwordHash str
 	return err
 }
 
+// RevokeTokens invalidates every token issued to the user before now
+func (s *store) RevokeTokens(ctx context.Context, userID string) error {
+	query := `
+		UPDATE users 
+		SET tokens_revoked_at = $1, updated_at = $1
+		WHERE id = $2
+	`
+
+	result, err := s.db.Exec(ctx, query, time.Now(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke tokens: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	return nil
+}
+
+// TokensRevokedAt returns when the user's tokens were last revoked, or nil if never
+func (s *store) TokensRevokedAt(ctx context.Context, userID string) (*time.Time, error) {
+	query := `SELECT tokens_revoked_at FROM users WHERE id = $1`
+
+	var revokedAt *time.Time
+	if err := s.db.QueryRow(ctx, query, userID).Scan(&revokedAt); err != nil {
+		return nil, err
+	}
+
+	return revokedAt, nil
+}
+
+// CreateGuest creates a guest with an empty profile
+func (s *store) CreateGuest(ctx context.Context, user *User) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// An empty hash never matches a password, so guests can't log in
+	_, err = tx.Exec(ctx, `
+		INSERT INTO users (id, email, full_name, role, password_hash, is_active, is_guest, guest_expires_at,
+		                   created_at, updated_at)
+		VALUES ($1, $2, $3, $4, '', $5, true, $6, $7, $8)
+	`, user.ID, user.Email, user.FullName, user.Role, user.IsActive, user.GuestExpiresAt, user.CreatedAt, user.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create guest: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO user_profiles (user_id, created_at, updated_at)
+		VALUES ($1, $2, $3)
+	`, user.ID, user.CreatedAt, user.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create user profile: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ExtendGuest moves a guest's expiry
+func (s *store) ExtendGuest(ctx context.Context, userID string, expiresAt time.Time) error {
+	query := `
+		UPDATE users 
+		SET guest_expires_at = $1, updated_at = $2
+		WHERE id = $3 AND is_guest = true
+	`
+
+	result, err := s.db.Exec(ctx, query, expiresAt, time.Now(), userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrGuestNotFound
+	}
+
+	return nil
+}
+
+// UpgradeGuest turns a guest into a full account with profile information.
+// The guest's ID is kept, so everything they saved stays theirs.
+func (s *store) UpgradeGuest(ctx context.Context, user *User, phoneNumber, address *string, dateOfBirth *time.Time) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `
+		UPDATE users 
+		SET email = $1, full_name = $2, role = $3, password_hash = $4, is_guest = false,
+		    guest_expires_at = NULL, updated_at = $5
+		WHERE id = $6 AND is_guest = true AND is_active = true
+	`, user.Email, user.FullName, user.Role, user.PasswordHash, user.UpdatedAt, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to upgrade guest: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrGuestNotFound
+	}
+
+	sealedPhone, phoneIndex, sealedAddress, sealedDateOfBirth, err := s.sealProfile(ctx, phoneNumber, address, dateOfBirth)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE user_profiles 
+		SET phone_number = $1, phone_number_bidx = $2, address = $3, date_of_birth = $4, updated_at = $5
+		WHERE user_id = $6
+	`, sealedPhone, phoneIndex, sealedAddress, sealedDateOfBirth, user.UpdatedAt, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update user profile: %w", err)
+	}
+
+	err = events.Publish(ctx, tx, events.UserRegistered, user.ID, events.UserRegisteredPayload{
+		UserID: user.ID,
+		Role:   string(user.Role),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpiredGuest deletes a guest whose data expired before now. Their
+// profile, journal and bookmarks go with them.
+func (s *store) DeleteExpiredGuest(ctx context.Context, userID string, now time.Time) (bool, error) {
+	query := `DELETE FROM users WHERE id = $1 AND is_guest = true AND guest_expires_at <= $2`
+
+	result, err := s.db.Exec(ctx, query, userID, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete guest: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
 // Add this method to backend/internal/auth/store.go
 
 // CreateUserWithProfile creates a new user with profile information
@@ -190,17 +351,23 @@ func (s *store) CreateUserWithProfile(ctx context.Context, user *User, phoneNumb
 		return fmt.Errorf("failed to create user: %w", err)
 	}
 
+	sealedPhone, phoneIndex, sealedAddress, sealedDateOfBirth, err := s.sealProfile(ctx, phoneNumber, address, dateOfBirth)
+	if err != nil {
+		return err
+	}
+
 	// Create user profile with additional information
 	profileQuery := `
-		INSERT INTO user_profiles (user_id, phone_number, address, date_of_birth, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO user_profiles (user_id, phone_number, phone_number_bidx, address, date_of_birth, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 
 	_, err = tx.Exec(ctx, profileQuery,
 		user.ID,
-		phoneNumber,
-		address,
-		dateOfBirth,
+		sealedPhone,
+		phoneIndex,
+		sealedAddress,
+		sealedDateOfBirth,
 		user.CreatedAt,
 		user.UpdatedAt,
 	)
@@ -208,6 +375,14 @@ func (s *store) CreateUserWithProfile(ctx context.Context, user *User, phoneNumb
 		return fmt.Errorf("failed to create user profile: %w", err)
 	}
 
+	err = events.Publish(ctx, tx, events.UserRegistered, user.ID, events.UserRegisteredPayload{
+		UserID: user.ID,
+		Role:   string(user.Role),
+	})
+	if err != nil {
+		return err
+	}
+
 	// Commit transaction
 	err = tx.Commit(ctx)
 	if err != nil {
@@ -216,3 +391,35 @@ func (s *store) CreateUserWithProfile(ctx context.Context, user *User, phoneNumb
 
 	return nil
 }
+
+// Helper functions
+
+// sealProfile encrypts the profile fields before they reach the database and
+// computes the phone number's blind index
+func (s *store) sealProfile(ctx context.Context, phoneNumber, address *string, dateOfBirth *time.Time) (sealedPhone, phoneIndex, sealedAddress, sealedDateOfBirth *string, err error) {
+	if phoneNumber != nil {
+		sealed, err := s.cipher.Encrypt(ctx, *phoneNumber)
+		if err != nil {
+			return nil, nil, nil, nil, fmt.Errorf("failed to encrypt phone number: %w", err)
+		}
+		sealedPhone = &sealed
+		if index := s.cipher.BlindIndex(encryption.NormalizePhone(*phoneNumber)); index != "" {
+			phoneIndex = &index
+		}
+	}
+	if address != nil {
+		sealed, err := s.cipher.Encrypt(ctx, *address)
+		if err != nil {
+			return nil, nil, nil, nil, fmt.Errorf("failed to encrypt address: %w", err)
+		}
+		sealedAddress = &sealed
+	}
+	if dateOfBirth != nil {
+		sealed, err := s.cipher.Encrypt(ctx, dateOfBirth.Format("2006-01-02"))
+		if err != nil {
+			return nil, nil, nil, nil, fmt.Errorf("failed to encrypt date of birth: %w", err)
+		}
+		sealedDateOfBirth = &sealed
+	}
+	return sealedPhone, phoneIndex, sealedAddress, sealedDateOfBirth, nil
+}
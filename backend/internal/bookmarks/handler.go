@@ -0,0 +1,76 @@
+package bookmarks
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	service Service
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// ListBookmarks lists the current user's bookmarks
+func (h *handler) ListBookmarks(c echo.Context) error {
+	bookmarks, err := h.service.ListBookmarks(c.Request().Context(), getUserIDFromContext(c), c.QueryParam("item_type"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, bookmarks)
+}
+
+// CreateBookmark bookmarks a service, resource or support group
+func (h *handler) CreateBookmark(c echo.Context) error {
+	var req CreateBookmarkRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	bookmark, err := h.service.CreateBookmark(c.Request().Context(), getUserIDFromContext(c), &req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, bookmark)
+}
+
+// DeleteBookmark removes one of the current user's bookmarks
+func (h *handler) DeleteBookmark(c echo.Context) error {
+	if err := h.service.DeleteBookmark(c.Request().Context(), getUserIDFromContext(c), c.Param("id")); err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Bookmark removed successfully",
+	})
+}
+
+// Helper functions
+
+func getUserIDFromContext(c echo.Context) string {
+	if userID, ok := c.Get("user_id").(string); ok {
+		return userID
+	}
+	return ""
+}
+
+func errorResponse(c echo.Context, err error) error {
+	status := http.StatusBadRequest
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrItemNotFound) {
+		status = http.StatusNotFound
+	}
+
+	return c.JSON(status, map[string]string{
+		"error": err.Error(),
+	})
+}
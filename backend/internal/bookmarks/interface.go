@@ -0,0 +1,33 @@
+package bookmarks
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Service defines the interface for bookmark business logic
+type Service interface {
+	ListBookmarks(ctx context.Context, userID, itemType string) (*ListBookmarksResponse, error)
+	// CreateBookmark saves an item; bookmarking it again returns the existing bookmark
+	CreateBookmark(ctx context.Context, userID string, req *CreateBookmarkRequest) (*Bookmark, error)
+	DeleteBookmark(ctx context.Context, userID, bookmarkID string) error
+}
+
+// Store defines the interface for bookmark data persistence
+type Store interface {
+	// ListBookmarks lists a user's bookmarks of active items, newest first. An
+	// empty itemType lists every type.
+	ListBookmarks(ctx context.Context, userID, itemType string) ([]Bookmark, error)
+	CreateBookmark(ctx context.Context, bookmark *Bookmark) (*Bookmark, error)
+	DeleteBookmark(ctx context.Context, userID, bookmarkID string) error
+	// ItemTitle returns the title of an active catalog item, or ErrItemNotFound
+	ItemTitle(ctx context.Context, itemType, itemID string) (string, error)
+}
+
+// Handler defines the interface for bookmark HTTP handlers
+type Handler interface {
+	ListBookmarks(c echo.Context) error
+	CreateBookmark(c echo.Context) error
+	DeleteBookmark(c echo.Context) error
+}
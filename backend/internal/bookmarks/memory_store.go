@@ -0,0 +1,89 @@
+package bookmarks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+)
+
+// memoryStore keeps bookmarks in memory for tests and demo mode. Item titles
+// are read from the shared catalog items.
+type memoryStore struct {
+	db        *memdb.DB
+	mu        sync.RWMutex
+	bookmarks map[string]Bookmark
+}
+
+func NewMemoryStore(db *memdb.DB) Store {
+	return &memoryStore{
+		db:        db,
+		bookmarks: make(map[string]Bookmark),
+	}
+}
+
+// ListBookmarks lists a user's bookmarks of active items, newest first
+func (s *memoryStore) ListBookmarks(ctx context.Context, userID, itemType string) ([]Bookmark, error) {
+	s.mu.RLock()
+	bookmarks := []Bookmark{}
+	for _, bookmark := range s.bookmarks {
+		if bookmark.UserID != userID || (itemType != "" && bookmark.ItemType != itemType) {
+			continue
+		}
+		if item, ok := s.db.Item(bookmark.ItemType, bookmark.ItemID); ok && item.IsActive {
+			bookmark.ItemTitle = item.Title
+			bookmarks = append(bookmarks, bookmark)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(bookmarks, func(i, j int) bool {
+		if bookmarks[i].CreatedAt.Equal(bookmarks[j].CreatedAt) {
+			return bookmarks[i].ID > bookmarks[j].ID
+		}
+		return bookmarks[i].CreatedAt.After(bookmarks[j].CreatedAt)
+	})
+
+	return bookmarks, nil
+}
+
+// CreateBookmark saves a bookmark, or returns the user's existing bookmark of the item
+func (s *memoryStore) CreateBookmark(ctx context.Context, bookmark *Bookmark) (*Bookmark, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.bookmarks {
+		if existing.UserID == bookmark.UserID && existing.ItemType == bookmark.ItemType && existing.ItemID == bookmark.ItemID {
+			return &existing, nil
+		}
+	}
+
+	saved := *bookmark
+	saved.ItemTitle = ""
+	s.bookmarks[saved.ID] = saved
+	return &saved, nil
+}
+
+// DeleteBookmark removes one of a user's bookmarks
+func (s *memoryStore) DeleteBookmark(ctx context.Context, userID, bookmarkID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bookmark, ok := s.bookmarks[bookmarkID]
+	if !ok || bookmark.UserID != userID {
+		return ErrNotFound
+	}
+	delete(s.bookmarks, bookmarkID)
+
+	return nil
+}
+
+// ItemTitle returns the title of an active catalog item
+func (s *memoryStore) ItemTitle(ctx context.Context, itemType, itemID string) (string, error) {
+	item, ok := s.db.Item(itemType, itemID)
+	if !ok || !item.IsActive {
+		return "", ErrItemNotFound
+	}
+	return item.Title, nil
+}
@@ -0,0 +1,47 @@
+// Package bookmarks keeps the services, resources and support groups a user
+// has saved to come back to. Guests can bookmark too.
+package bookmarks
+
+import (
+	"errors"
+	"time"
+)
+
+// Catalog item types that can be bookmarked, matching referral types
+const (
+	ItemService      = "service"
+	ItemResource     = "resource"
+	ItemSupportGroup = "support_group"
+)
+
+// ItemTypes lists the valid item types
+var ItemTypes = []string{ItemService, ItemResource, ItemSupportGroup}
+
+var (
+	// ErrNotFound is returned for bookmarks that don't exist or belong to someone else
+	ErrNotFound = errors.New("bookmark not found")
+
+	// ErrItemNotFound is returned when bookmarking an item that doesn't exist or is inactive
+	ErrItemNotFound = errors.New("item not found")
+)
+
+// Bookmark is a catalog item a user has saved
+type Bookmark struct {
+	ID        string    `json:"id" db:"id"`
+	UserID    string    `json:"user_id" db:"user_id"`
+	ItemType  string    `json:"item_type" db:"item_type"`
+	ItemID    string    `json:"item_id" db:"item_id"`
+	ItemTitle string    `json:"item_title" db:"item_title"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// CreateBookmarkRequest represents the request to bookmark an item
+type CreateBookmarkRequest struct {
+	ItemType string `json:"item_type" validate:"required"`
+	ItemID   string `json:"item_id" validate:"required"`
+}
+
+// ListBookmarksResponse represents a user's bookmarks
+type ListBookmarksResponse struct {
+	Bookmarks []Bookmark `json:"bookmarks"`
+}
@@ -0,0 +1,81 @@
+package bookmarks
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type service struct {
+	store Store
+}
+
+func NewService(store Store) Service {
+	return &service{
+		store: store,
+	}
+}
+
+// ListBookmarks lists a user's bookmarks, optionally of one item type
+func (s *service) ListBookmarks(ctx context.Context, userID, itemType string) (*ListBookmarksResponse, error) {
+	if itemType != "" {
+		if err := validateItemType(itemType); err != nil {
+			return nil, err
+		}
+	}
+
+	bookmarks, err := s.store.ListBookmarks(ctx, userID, itemType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListBookmarksResponse{Bookmarks: bookmarks}, nil
+}
+
+// CreateBookmark saves an active catalog item
+func (s *service) CreateBookmark(ctx context.Context, userID string, req *CreateBookmarkRequest) (*Bookmark, error) {
+	if err := validateItemType(req.ItemType); err != nil {
+		return nil, err
+	}
+	itemID := strings.TrimSpace(req.ItemID)
+	if itemID == "" {
+		return nil, fmt.Errorf("item_id is required")
+	}
+
+	title, err := s.store.ItemTitle(ctx, req.ItemType, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmark, err := s.store.CreateBookmark(ctx, &Bookmark{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		ItemType:  req.ItemType,
+		ItemID:    itemID,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bookmark.ItemTitle = title
+	return bookmark, nil
+}
+
+// DeleteBookmark removes one of the user's bookmarks
+func (s *service) DeleteBookmark(ctx context.Context, userID, bookmarkID string) error {
+	return s.store.DeleteBookmark(ctx, userID, bookmarkID)
+}
+
+// Helper functions
+
+func validateItemType(itemType string) error {
+	if !slices.Contains(ItemTypes, itemType) {
+		return fmt.Errorf("invalid item_type: %s (must be one of %s)", itemType, strings.Join(ItemTypes, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package bookmarks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type store struct {
+	db *pgxpool.Pool
+}
+
+func NewStore(db *pgxpool.Pool) Store {
+	return &store{
+		db: db,
+	}
+}
+
+// ListBookmarks lists a user's bookmarks of active items, newest first
+func (s *store) ListBookmarks(ctx context.Context, userID, itemType string) ([]Bookmark, error) {
+	query := `
+		SELECT b.id, b.user_id, b.item_type, b.item_id,
+		       COALESCE(s.name, res.title, sg.name) as item_title, b.created_at
+		FROM bookmarks b
+		LEFT JOIN services s ON b.item_type = 'service' AND b.item_id = s.id AND s.is_active = true
+		LEFT JOIN resources res ON b.item_type = 'resource' AND b.item_id = res.id AND res.is_active = true
+		LEFT JOIN support_groups sg ON b.item_type = 'support_group' AND b.item_id = sg.id AND sg.is_active = true
+		WHERE b.user_id = $1 AND ($2 = '' OR b.item_type = $2)
+		  AND COALESCE(s.id, res.id, sg.id) IS NOT NULL
+		ORDER BY b.created_at DESC, b.id DESC
+	`
+
+	rows, err := s.db.Query(ctx, query, userID, itemType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bookmarks: %w", err)
+	}
+	defer rows.Close()
+
+	bookmarks := []Bookmark{}
+	for rows.Next() {
+		var bookmark Bookmark
+		err := rows.Scan(&bookmark.ID, &bookmark.UserID, &bookmark.ItemType, &bookmark.ItemID,
+			&bookmark.ItemTitle, &bookmark.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan bookmark: %w", err)
+		}
+		bookmarks = append(bookmarks, bookmark)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return bookmarks, nil
+}
+
+// CreateBookmark saves a bookmark, or returns the user's existing bookmark of the item
+func (s *store) CreateBookmark(ctx context.Context, bookmark *Bookmark) (*Bookmark, error) {
+	query := `
+		INSERT INTO bookmarks (id, user_id, item_type, item_id, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, item_type, item_id) DO NOTHING
+	`
+
+	_, err := s.db.Exec(ctx, query, bookmark.ID, bookmark.UserID, bookmark.ItemType, bookmark.ItemID, bookmark.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bookmark: %w", err)
+	}
+
+	var saved Bookmark
+	err = s.db.QueryRow(ctx, `
+		SELECT id, user_id, item_type, item_id, created_at
+		FROM bookmarks
+		WHERE user_id = $1 AND item_type = $2 AND item_id = $3
+	`, bookmark.UserID, bookmark.ItemType, bookmark.ItemID).Scan(
+		&saved.ID, &saved.UserID, &saved.ItemType, &saved.ItemID, &saved.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bookmark: %w", err)
+	}
+
+	return &saved, nil
+}
+
+// DeleteBookmark removes one of a user's bookmarks
+func (s *store) DeleteBookmark(ctx context.Context, userID, bookmarkID string) error {
+	if _, err := uuid.Parse(bookmarkID); err != nil {
+		return ErrNotFound
+	}
+
+	result, err := s.db.Exec(ctx, `DELETE FROM bookmarks WHERE id = $1 AND user_id = $2`, bookmarkID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete bookmark: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// ItemTitle returns the title of an active catalog item
+func (s *store) ItemTitle(ctx context.Context, itemType, itemID string) (string, error) {
+	if _, err := uuid.Parse(itemID); err != nil {
+		return "", ErrItemNotFound
+	}
+
+	var query string
+	switch itemType {
+	case ItemService:
+		query = `SELECT name FROM services WHERE id = $1 AND is_active = true`
+	case ItemResource:
+		query = `SELECT title FROM resources WHERE id = $1 AND is_active = true`
+	case ItemSupportGroup:
+		query = `SELECT name FROM support_groups WHERE id = $1 AND is_active = true`
+	default:
+		return "", fmt.Errorf("invalid item_type: %s", itemType)
+	}
+
+	var title string
+	if err := s.db.QueryRow(ctx, query, itemID).Scan(&title); err != nil {
+		if err == pgx.ErrNoRows {
+			return "", ErrItemNotFound
+		}
+		return "", fmt.Errorf("failed to get item: %w", err)
+	}
+
+	return title, nil
+}
@@ -0,0 +1,193 @@
+package careteam
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	service Service
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// Invite invites a service user to add the caller to their care team
+func (h *handler) Invite(c echo.Context) error {
+	var req InviteRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	relationship, err := h.service.Invite(c.Request().Context(), getUserIDFromContext(c), &req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, relationship)
+}
+
+// ListForProfessional lists the caller's care-team relationships
+func (h *handler) ListForProfessional(c echo.Context) error {
+	relationships, err := h.service.ListForProfessional(c.Request().Context(), getUserIDFromContext(c), c.QueryParam("status"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, relationships)
+}
+
+// BreakGlass declares emergency access to a service user's records
+func (h *handler) BreakGlass(c echo.Context) error {
+	var req BreakGlassRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	grant, err := h.service.BreakGlass(c.Request().Context(), getUserIDFromContext(c), &req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, grant)
+}
+
+// ListMyCareTeam lists the current user's care-team relationships
+func (h *handler) ListMyCareTeam(c echo.Context) error {
+	relationships, err := h.service.ListForServiceUser(c.Request().Context(), getUserIDFromContext(c))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, relationships)
+}
+
+// Accept accepts a care-team invitation
+func (h *handler) Accept(c echo.Context) error {
+	relationship, err := h.service.Accept(c.Request().Context(), getUserIDFromContext(c), c.Param("id"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, relationship)
+}
+
+// UpdateSharing changes what the current user shares with a care-team member
+func (h *handler) UpdateSharing(c echo.Context) error {
+	var req UpdateSharingRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	relationship, err := h.service.UpdateSharing(c.Request().Context(), getUserIDFromContext(c), c.Param("id"), &req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, relationship)
+}
+
+// End ends, declines or withdraws a relationship the caller is a party to
+func (h *handler) End(c echo.Context) error {
+	var req EndRelationshipRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	relationship, err := h.service.End(c.Request().Context(), getUserIDFromContext(c), c.Param("id"), &req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, relationship)
+}
+
+// Assign assigns a professional to a service user's care team (admin only)
+func (h *handler) Assign(c echo.Context) error {
+	var req AssignRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	relationship, err := h.service.Assign(c.Request().Context(), getUserIDFromContext(c), &req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, relationship)
+}
+
+// ListRelationships lists relationships by professional, service user or status (admin only)
+func (h *handler) ListRelationships(c echo.Context) error {
+	filter := &RelationshipFilter{
+		ProfessionalID: c.QueryParam("professional_id"),
+		ServiceUserID:  c.QueryParam("service_user_id"),
+	}
+	if status := c.QueryParam("status"); status != "" {
+		filter.Statuses = []string{status}
+	}
+
+	relationships, err := h.service.ListRelationships(c.Request().Context(), filter)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, relationships)
+}
+
+// AdminEnd ends any relationship (admin only)
+func (h *handler) AdminEnd(c echo.Context) error {
+	var req EndRelationshipRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	relationship, err := h.service.AdminEnd(c.Request().Context(), getUserIDFromContext(c), c.Param("id"), &req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, relationship)
+}
+
+// Helper functions
+
+func getUserIDFromContext(c echo.Context) string {
+	if userID, ok := c.Get("user_id").(string); ok {
+		return userID
+	}
+	return ""
+}
+
+func errorResponse(c echo.Context, err error) error {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, ErrNoAccess):
+		status = http.StatusForbidden
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrUserNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrAlreadyLinked), errors.Is(err, ErrStatusChanged):
+		status = http.StatusConflict
+	}
+
+	return c.JSON(status, map[string]string{
+		"error": err.Error(),
+	})
+}
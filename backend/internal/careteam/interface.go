@@ -0,0 +1,80 @@
+package careteam
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AccessChecker decides whether a caller may see a service user's records
+type AccessChecker interface {
+	// CheckAccess returns how actorID may access subjectID's records. It fails
+	// with ErrNoAccess for service users the actor has no current relationship
+	// or break-glass access to. Staff accounts are not gated.
+	CheckAccess(ctx context.Context, actorID, subjectID string) (*Access, error)
+}
+
+// Service defines the interface for care-team business logic
+type Service interface {
+	AccessChecker
+
+	// Professionals
+	Invite(ctx context.Context, professionalID string, req *InviteRequest) (*Relationship, error)
+	ListForProfessional(ctx context.Context, professionalID, status string) (*ListRelationshipsResponse, error)
+	BreakGlass(ctx context.Context, staffID string, req *BreakGlassRequest) (*BreakGlassGrant, error)
+	// ServiceUserIDs returns the service users a professional currently cares for
+	ServiceUserIDs(ctx context.Context, professionalID string) ([]string, error)
+
+	// Service users
+	ListForServiceUser(ctx context.Context, serviceUserID string) (*ListRelationshipsResponse, error)
+	Accept(ctx context.Context, serviceUserID, relationshipID string) (*Relationship, error)
+	UpdateSharing(ctx context.Context, serviceUserID, relationshipID string, req *UpdateSharingRequest) (*Relationship, error)
+
+	// End ends or declines a relationship the caller is a party to
+	End(ctx context.Context, userID, relationshipID string, req *EndRelationshipRequest) (*Relationship, error)
+
+	// Admins
+	Assign(ctx context.Context, adminID string, req *AssignRequest) (*Relationship, error)
+	ListRelationships(ctx context.Context, filter *RelationshipFilter) (*ListRelationshipsResponse, error)
+	AdminEnd(ctx context.Context, adminID, relationshipID string, req *EndRelationshipRequest) (*Relationship, error)
+}
+
+// Store defines the interface for care-team data persistence
+type Store interface {
+	CreateRelationship(ctx context.Context, relationship *Relationship) (*Relationship, error)
+	GetRelationship(ctx context.Context, relationshipID string) (*Relationship, error)
+	ListRelationships(ctx context.Context, filter *RelationshipFilter) ([]Relationship, error)
+	// UpdateRelationship saves the relationship if its status is still
+	// fromStatus, and fails with ErrStatusChanged otherwise
+	UpdateRelationship(ctx context.Context, relationship *Relationship, fromStatus string) (*Relationship, error)
+	// FindCurrentRelationship returns the relationship granting access at the
+	// given time, or nil if there is none
+	FindCurrentRelationship(ctx context.Context, professionalID, serviceUserID string, at time.Time) (*Relationship, error)
+	CreateBreakGlass(ctx context.Context, grant *BreakGlassGrant) (*BreakGlassGrant, error)
+	// FindBreakGlass returns an unexpired grant, or nil if there is none
+	FindBreakGlass(ctx context.Context, staffID, serviceUserID string, at time.Time) (*BreakGlassGrant, error)
+	// GetUserRole returns the role of an active user, or ErrUserNotFound
+	GetUserRole(ctx context.Context, userID string) (string, error)
+}
+
+// Handler defines the interface for care-team HTTP handlers
+type Handler interface {
+	// Professional methods
+	Invite(c echo.Context) error
+	ListForProfessional(c echo.Context) error
+	BreakGlass(c echo.Context) error
+
+	// Service user methods
+	ListMyCareTeam(c echo.Context) error
+	Accept(c echo.Context) error
+	UpdateSharing(c echo.Context) error
+
+	// End is shared by both parties
+	End(c echo.Context) error
+
+	// Admin methods
+	Assign(c echo.Context) error
+	ListRelationships(c echo.Context) error
+	AdminEnd(c echo.Context) error
+}
@@ -0,0 +1,169 @@
+package careteam
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+)
+
+// memoryStore keeps care-team relationships and break-glass grants in memory
+// for tests and demo mode. Names and roles are read from the shared users.
+type memoryStore struct {
+	db            *memdb.DB
+	mu            sync.RWMutex
+	relationships map[string]Relationship
+	grants        []BreakGlassGrant
+}
+
+func NewMemoryStore(db *memdb.DB) Store {
+	return &memoryStore{
+		db:            db,
+		relationships: make(map[string]Relationship),
+	}
+}
+
+// CreateRelationship creates a relationship, failing with ErrAlreadyLinked if
+// the pair already has a pending or active one
+func (s *memoryStore) CreateRelationship(ctx context.Context, relationship *Relationship) (*Relationship, error) {
+	s.mu.Lock()
+	for _, existing := range s.relationships {
+		if existing.ProfessionalID == relationship.ProfessionalID && existing.ServiceUserID == relationship.ServiceUserID &&
+			(existing.Status == StatusPending || existing.Status == StatusActive) {
+			s.mu.Unlock()
+			return nil, ErrAlreadyLinked
+		}
+	}
+	s.relationships[relationship.ID] = *relationship
+	s.mu.Unlock()
+
+	return s.GetRelationship(ctx, relationship.ID)
+}
+
+// GetRelationship retrieves a relationship with both parties' names
+func (s *memoryStore) GetRelationship(ctx context.Context, relationshipID string) (*Relationship, error) {
+	s.mu.RLock()
+	relationship, ok := s.relationships[relationshipID]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	s.withNames(&relationship)
+	return &relationship, nil
+}
+
+// ListRelationships retrieves relationships, newest first
+func (s *memoryStore) ListRelationships(ctx context.Context, filter *RelationshipFilter) ([]Relationship, error) {
+	s.mu.RLock()
+	var relationships []Relationship
+	for _, relationship := range s.relationships {
+		if (filter.ProfessionalID == "" || relationship.ProfessionalID == filter.ProfessionalID) &&
+			(filter.ServiceUserID == "" || relationship.ServiceUserID == filter.ServiceUserID) &&
+			(len(filter.Statuses) == 0 || slices.Contains(filter.Statuses, relationship.Status)) {
+			relationships = append(relationships, relationship)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(relationships, func(i, j int) bool {
+		if relationships[i].CreatedAt.Equal(relationships[j].CreatedAt) {
+			return relationships[i].ID > relationships[j].ID
+		}
+		return relationships[i].CreatedAt.After(relationships[j].CreatedAt)
+	})
+	for i := range relationships {
+		s.withNames(&relationships[i])
+	}
+
+	return relationships, nil
+}
+
+// UpdateRelationship saves the status, sharing and end details of a relationship
+func (s *memoryStore) UpdateRelationship(ctx context.Context, relationship *Relationship, fromStatus string) (*Relationship, error) {
+	s.mu.Lock()
+	existing, ok := s.relationships[relationship.ID]
+	if !ok || existing.Status != fromStatus {
+		s.mu.Unlock()
+		return nil, ErrStatusChanged
+	}
+
+	existing.Status = relationship.Status
+	existing.ShareJourney = relationship.ShareJourney
+	existing.EndsAt = relationship.EndsAt
+	existing.AcceptedAt = relationship.AcceptedAt
+	existing.EndedAt = relationship.EndedAt
+	existing.EndedBy = relationship.EndedBy
+	existing.EndReason = relationship.EndReason
+	existing.UpdatedAt = time.Now()
+	s.relationships[relationship.ID] = existing
+	s.mu.Unlock()
+
+	return s.GetRelationship(ctx, relationship.ID)
+}
+
+// FindCurrentRelationship returns the relationship granting access at the given time
+func (s *memoryStore) FindCurrentRelationship(ctx context.Context, professionalID, serviceUserID string, at time.Time) (*Relationship, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, relationship := range s.relationships {
+		if relationship.ProfessionalID == professionalID && relationship.ServiceUserID == serviceUserID && relationship.Current(at) {
+			s.withNames(&relationship)
+			return &relationship, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// CreateBreakGlass records a break-glass grant
+func (s *memoryStore) CreateBreakGlass(ctx context.Context, grant *BreakGlassGrant) (*BreakGlassGrant, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.grants = append(s.grants, *grant)
+	created := *grant
+	return &created, nil
+}
+
+// FindBreakGlass returns the staff member's latest unexpired grant for the service user
+func (s *memoryStore) FindBreakGlass(ctx context.Context, staffID, serviceUserID string, at time.Time) (*BreakGlassGrant, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var latest *BreakGlassGrant
+	for i := range s.grants {
+		grant := s.grants[i]
+		if grant.StaffID == staffID && grant.ServiceUserID == serviceUserID && grant.ExpiresAt.After(at) &&
+			(latest == nil || grant.ExpiresAt.After(latest.ExpiresAt)) {
+			latest = &grant
+		}
+	}
+
+	return latest, nil
+}
+
+// GetUserRole returns the role of an active user
+func (s *memoryStore) GetUserRole(ctx context.Context, userID string) (string, error) {
+	user, ok := s.db.User(userID)
+	if !ok || !user.IsActive {
+		return "", ErrUserNotFound
+	}
+
+	return user.Role, nil
+}
+
+// Helper functions
+
+func (s *memoryStore) withNames(relationship *Relationship) {
+	if professional, ok := s.db.User(relationship.ProfessionalID); ok {
+		relationship.ProfessionalName = professional.FullName
+	}
+	if serviceUser, ok := s.db.User(relationship.ServiceUserID); ok {
+		relationship.ServiceUserName = serviceUser.FullName
+	}
+}
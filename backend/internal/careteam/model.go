@@ -0,0 +1,153 @@
+package careteam
+
+import (
+	"errors"
+	"time"
+)
+
+// Relationship statuses. An invitation stays pending until the service user
+// accepts or declines it; admin assignments start active.
+const (
+	StatusPending  = "pending"
+	StatusActive   = "active"
+	StatusDeclined = "declined"
+	StatusEnded    = "ended"
+)
+
+// How a relationship was set up
+const (
+	SourceInvite = "invite"
+	SourceAdmin  = "admin"
+)
+
+// Ways a caller can be allowed to see a service user's records
+const (
+	AccessSelf       = "self"
+	AccessCareTeam   = "care_team"
+	AccessBreakGlass = "break_glass"
+	// AccessNotRestricted applies to staff accounts, which aren't gated by care teams
+	AccessNotRestricted = "not_restricted"
+)
+
+// BreakGlassDuration is how long emergency access lasts once declared
+const BreakGlassDuration = time.Hour
+
+// CareTeamRoles are the user roles that can be members of a care team
+var CareTeamRoles = []string{"professional", "nhs_staff"}
+
+const roleServiceUser = "service_user"
+
+var (
+	// ErrNoAccess is returned when a caller has neither a current care-team
+	// relationship nor break-glass access to a service user
+	ErrNoAccess = errors.New("no active care-team relationship with this service user")
+
+	// ErrNotFound is returned for relationships that don't exist or that the
+	// caller isn't a party to
+	ErrNotFound = errors.New("care-team relationship not found")
+
+	// ErrAlreadyLinked is returned when a professional already has a pending or
+	// active relationship with the service user
+	ErrAlreadyLinked = errors.New("a pending or active care-team relationship already exists")
+
+	// ErrStatusChanged is returned when a relationship changed status between
+	// being read and being saved
+	ErrStatusChanged = errors.New("care-team relationship was changed by someone else; reload and try again")
+
+	// ErrUserNotFound is returned when a user in a request doesn't exist
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// Relationship links a professional to a service user they care for
+type Relationship struct {
+	ID               string     `json:"id" db:"id"`
+	ProfessionalID   string     `json:"professional_id" db:"professional_id"`
+	ProfessionalName string     `json:"professional_name" db:"professional_name"`
+	ServiceUserID    string     `json:"service_user_id" db:"service_user_id"`
+	ServiceUserName  string     `json:"service_user_name" db:"service_user_name"`
+	Status           string     `json:"status" db:"status"`
+	Source           string     `json:"source" db:"source"`
+	Reason           string     `json:"reason" db:"reason" encrypt:"true"`
+	ShareJourney     bool       `json:"share_journey" db:"share_journey"`
+	StartsAt         time.Time  `json:"starts_at" db:"starts_at"`
+	EndsAt           *time.Time `json:"ends_at,omitempty" db:"ends_at"`
+	IsCurrent        bool       `json:"is_current" db:"-"`
+	CreatedBy        *string    `json:"created_by,omitempty" db:"created_by"`
+	AcceptedAt       *time.Time `json:"accepted_at,omitempty" db:"accepted_at"`
+	EndedAt          *time.Time `json:"ended_at,omitempty" db:"ended_at"`
+	EndedBy          *string    `json:"ended_by,omitempty" db:"ended_by"`
+	EndReason        *string    `json:"end_reason,omitempty" db:"end_reason" encrypt:"true"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Current reports whether the relationship grants access at the given time
+func (r *Relationship) Current(at time.Time) bool {
+	return r.Status == StatusActive && !r.StartsAt.After(at) && (r.EndsAt == nil || r.EndsAt.After(at))
+}
+
+// BreakGlassGrant is time-limited emergency access to a service user's records
+// outside any care-team relationship
+type BreakGlassGrant struct {
+	ID            string    `json:"id" db:"id"`
+	StaffID       string    `json:"staff_id" db:"staff_id"`
+	ServiceUserID string    `json:"service_user_id" db:"service_user_id"`
+	Reason        string    `json:"reason" db:"reason" encrypt:"true"`
+	ExpiresAt     time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// Access explains why a caller may see a service user's records
+type Access struct {
+	Via            string  `json:"via"`
+	RelationshipID *string `json:"relationship_id,omitempty"`
+	BreakGlassID   *string `json:"break_glass_id,omitempty"`
+	// ShareJourney is whether the service user shares their journey with this
+	// care-team member; it is always true for the service user themselves
+	ShareJourney bool `json:"share_journey"`
+}
+
+// RelationshipFilter selects relationships for listing
+type RelationshipFilter struct {
+	ProfessionalID string
+	ServiceUserID  string
+	Statuses       []string
+}
+
+// InviteRequest represents a professional's invitation to join a service user's care team
+type InviteRequest struct {
+	ServiceUserID string     `json:"service_user_id" validate:"required,uuid"`
+	Reason        string     `json:"reason" validate:"required,min=3,max=1000"`
+	StartsAt      *time.Time `json:"starts_at,omitempty"`
+	EndsAt        *time.Time `json:"ends_at,omitempty"`
+}
+
+// AssignRequest represents an admin assigning a professional to a service user
+type AssignRequest struct {
+	ProfessionalID string     `json:"professional_id" validate:"required,uuid"`
+	ServiceUserID  string     `json:"service_user_id" validate:"required,uuid"`
+	Reason         string     `json:"reason" validate:"required,min=3,max=1000"`
+	StartsAt       *time.Time `json:"starts_at,omitempty"`
+	EndsAt         *time.Time `json:"ends_at,omitempty"`
+}
+
+// UpdateSharingRequest represents a service user's sharing choices for one relationship
+type UpdateSharingRequest struct {
+	ShareJourney *bool `json:"share_journey,omitempty"`
+}
+
+// EndRelationshipRequest represents the request to end or decline a relationship
+type EndRelationshipRequest struct {
+	Reason *string `json:"reason,omitempty" validate:"omitempty,max=1000"`
+}
+
+// BreakGlassRequest represents a staff member declaring emergency access
+type BreakGlassRequest struct {
+	ServiceUserID string `json:"service_user_id" validate:"required,uuid"`
+	Reason        string `json:"reason" validate:"required,min=10,max=1000"`
+}
+
+// ListRelationshipsResponse represents a list of care-team relationships
+type ListRelationshipsResponse struct {
+	Relationships []Relationship `json:"relationships"`
+}
@@ -0,0 +1,382 @@
+package careteam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type service struct {
+	store Store
+}
+
+func NewService(store Store) Service {
+	return &service{
+		store: store,
+	}
+}
+
+// CheckAccess returns how actorID may access subjectID's records
+func (s *service) CheckAccess(ctx context.Context, actorID, subjectID string) (*Access, error) {
+	if actorID == "" || subjectID == "" {
+		return nil, ErrNoAccess
+	}
+	if actorID == subjectID {
+		return &Access{Via: AccessSelf, ShareJourney: true}, nil
+	}
+
+	role, err := s.store.GetUserRole(ctx, subjectID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, ErrNoAccess
+		}
+		return nil, err
+	}
+	if role != roleServiceUser {
+		return &Access{Via: AccessNotRestricted}, nil
+	}
+
+	now := time.Now()
+	relationship, err := s.store.FindCurrentRelationship(ctx, actorID, subjectID, now)
+	if err != nil {
+		return nil, err
+	}
+	if relationship != nil {
+		return &Access{Via: AccessCareTeam, RelationshipID: &relationship.ID, ShareJourney: relationship.ShareJourney}, nil
+	}
+
+	grant, err := s.store.FindBreakGlass(ctx, actorID, subjectID, now)
+	if err != nil {
+		return nil, err
+	}
+	if grant != nil {
+		return &Access{Via: AccessBreakGlass, BreakGlassID: &grant.ID}, nil
+	}
+
+	return nil, ErrNoAccess
+}
+
+// Invite creates a pending relationship for the service user to accept
+func (s *service) Invite(ctx context.Context, professionalID string, req *InviteRequest) (*Relationship, error) {
+	now := time.Now()
+	relationship, err := s.newRelationship(ctx, professionalID, req.ServiceUserID, req.Reason, req.StartsAt, req.EndsAt, now)
+	if err != nil {
+		return nil, err
+	}
+	relationship.Status = StatusPending
+	relationship.Source = SourceInvite
+	relationship.CreatedBy = &professionalID
+
+	created, err := s.store.CreateRelationship(ctx, relationship)
+	if err != nil {
+		return nil, err
+	}
+
+	return present(created, now), nil
+}
+
+// ListForProfessional lists a professional's relationships, pending and active
+// ones by default
+func (s *service) ListForProfessional(ctx context.Context, professionalID, status string) (*ListRelationshipsResponse, error) {
+	statuses := []string{StatusPending, StatusActive}
+	if status != "" {
+		if !isValidStatus(status) {
+			return nil, fmt.Errorf("invalid status: %s", status)
+		}
+		statuses = []string{status}
+	}
+
+	return s.list(ctx, &RelationshipFilter{ProfessionalID: professionalID, Statuses: statuses})
+}
+
+// BreakGlass gives a staff member time-limited access to a service user's
+// records without a care-team relationship. The declared reason is kept with
+// the grant for review.
+func (s *service) BreakGlass(ctx context.Context, staffID string, req *BreakGlassRequest) (*BreakGlassGrant, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if len(reason) < 10 || len(reason) > 1000 {
+		return nil, fmt.Errorf("reason must be between 10 and 1000 characters")
+	}
+	if err := s.requireRole(ctx, staffID, CareTeamRoles...); err != nil {
+		return nil, err
+	}
+	if err := s.requireRole(ctx, req.ServiceUserID, roleServiceUser); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return s.store.CreateBreakGlass(ctx, &BreakGlassGrant{
+		ID:            uuid.New().String(),
+		StaffID:       staffID,
+		ServiceUserID: req.ServiceUserID,
+		Reason:        reason,
+		ExpiresAt:     now.Add(BreakGlassDuration),
+		CreatedAt:     now,
+	})
+}
+
+// ServiceUserIDs returns the service users a professional currently cares for
+func (s *service) ServiceUserIDs(ctx context.Context, professionalID string) ([]string, error) {
+	relationships, err := s.store.ListRelationships(ctx, &RelationshipFilter{ProfessionalID: professionalID, Statuses: []string{StatusActive}})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	ids := make([]string, 0, len(relationships))
+	for i := range relationships {
+		if relationships[i].Current(now) {
+			ids = append(ids, relationships[i].ServiceUserID)
+		}
+	}
+
+	return ids, nil
+}
+
+// ListForServiceUser lists every relationship of a service user, including
+// ended ones, so they can see who has had access
+func (s *service) ListForServiceUser(ctx context.Context, serviceUserID string) (*ListRelationshipsResponse, error) {
+	return s.list(ctx, &RelationshipFilter{ServiceUserID: serviceUserID})
+}
+
+// Accept activates a pending invitation
+func (s *service) Accept(ctx context.Context, serviceUserID, relationshipID string) (*Relationship, error) {
+	relationship, err := s.getForServiceUser(ctx, serviceUserID, relationshipID)
+	if err != nil {
+		return nil, err
+	}
+	if relationship.Status != StatusPending {
+		return nil, fmt.Errorf("only pending invitations can be accepted")
+	}
+
+	now := time.Now()
+	relationship.Status = StatusActive
+	relationship.AcceptedAt = &now
+
+	updated, err := s.store.UpdateRelationship(ctx, relationship, StatusPending)
+	if err != nil {
+		return nil, err
+	}
+
+	return present(updated, now), nil
+}
+
+// UpdateSharing records what the service user shares with a care-team member
+func (s *service) UpdateSharing(ctx context.Context, serviceUserID, relationshipID string, req *UpdateSharingRequest) (*Relationship, error) {
+	relationship, err := s.getForServiceUser(ctx, serviceUserID, relationshipID)
+	if err != nil {
+		return nil, err
+	}
+	if relationship.Status != StatusPending && relationship.Status != StatusActive {
+		return nil, fmt.Errorf("sharing can't be changed on a %s relationship", relationship.Status)
+	}
+
+	if req.ShareJourney != nil {
+		relationship.ShareJourney = *req.ShareJourney
+	}
+
+	updated, err := s.store.UpdateRelationship(ctx, relationship, relationship.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	return present(updated, time.Now()), nil
+}
+
+// End ends an active relationship or declines or withdraws a pending one
+func (s *service) End(ctx context.Context, userID, relationshipID string, req *EndRelationshipRequest) (*Relationship, error) {
+	relationship, err := s.store.GetRelationship(ctx, relationshipID)
+	if err != nil {
+		return nil, err
+	}
+	if relationship.ProfessionalID != userID && relationship.ServiceUserID != userID {
+		return nil, ErrNotFound
+	}
+
+	return s.end(ctx, userID, relationship, req)
+}
+
+// Assign creates an active relationship on behalf of both parties
+func (s *service) Assign(ctx context.Context, adminID string, req *AssignRequest) (*Relationship, error) {
+	now := time.Now()
+	relationship, err := s.newRelationship(ctx, req.ProfessionalID, req.ServiceUserID, req.Reason, req.StartsAt, req.EndsAt, now)
+	if err != nil {
+		return nil, err
+	}
+	relationship.Status = StatusActive
+	relationship.Source = SourceAdmin
+	relationship.CreatedBy = &adminID
+
+	created, err := s.store.CreateRelationship(ctx, relationship)
+	if err != nil {
+		return nil, err
+	}
+
+	return present(created, now), nil
+}
+
+// ListRelationships lists relationships across all users
+func (s *service) ListRelationships(ctx context.Context, filter *RelationshipFilter) (*ListRelationshipsResponse, error) {
+	for _, status := range filter.Statuses {
+		if !isValidStatus(status) {
+			return nil, fmt.Errorf("invalid status: %s", status)
+		}
+	}
+
+	return s.list(ctx, filter)
+}
+
+// AdminEnd ends any open relationship
+func (s *service) AdminEnd(ctx context.Context, adminID, relationshipID string, req *EndRelationshipRequest) (*Relationship, error) {
+	relationship, err := s.store.GetRelationship(ctx, relationshipID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.end(ctx, adminID, relationship, req)
+}
+
+// Helper functions
+
+// newRelationship validates the parties and dates of a new relationship
+func (s *service) newRelationship(ctx context.Context, professionalID, serviceUserID, reason string, startsAt, endsAt *time.Time, now time.Time) (*Relationship, error) {
+	reason = strings.TrimSpace(reason)
+	if len(reason) < 3 || len(reason) > 1000 {
+		return nil, fmt.Errorf("reason must be between 3 and 1000 characters")
+	}
+
+	start := now
+	if startsAt != nil {
+		start = *startsAt
+	}
+	if endsAt != nil && (!endsAt.After(start) || !endsAt.After(now)) {
+		return nil, fmt.Errorf("ends_at must be after starts_at and in the future")
+	}
+
+	if err := s.requireRole(ctx, professionalID, CareTeamRoles...); err != nil {
+		return nil, fmt.Errorf("professional: %w", err)
+	}
+	if err := s.requireRole(ctx, serviceUserID, roleServiceUser); err != nil {
+		return nil, fmt.Errorf("service user: %w", err)
+	}
+
+	return &Relationship{
+		ID:             uuid.New().String(),
+		ProfessionalID: professionalID,
+		ServiceUserID:  serviceUserID,
+		Reason:         reason,
+		StartsAt:       start,
+		EndsAt:         endsAt,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// requireRole checks that the user exists and has one of the roles
+func (s *service) requireRole(ctx context.Context, userID string, roles ...string) error {
+	if _, err := uuid.Parse(userID); err != nil {
+		return ErrUserNotFound
+	}
+
+	role, err := s.store.GetUserRole(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(roles, role) {
+		return fmt.Errorf("user has role %s, want %s", role, strings.Join(roles, " or "))
+	}
+
+	return nil
+}
+
+func (s *service) getForServiceUser(ctx context.Context, serviceUserID, relationshipID string) (*Relationship, error) {
+	relationship, err := s.store.GetRelationship(ctx, relationshipID)
+	if err != nil {
+		return nil, err
+	}
+	if relationship.ServiceUserID != serviceUserID {
+		return nil, ErrNotFound
+	}
+
+	return relationship, nil
+}
+
+func (s *service) end(ctx context.Context, actorID string, relationship *Relationship, req *EndRelationshipRequest) (*Relationship, error) {
+	fromStatus := relationship.Status
+	switch {
+	case fromStatus == StatusActive:
+		relationship.Status = StatusEnded
+	case fromStatus == StatusPending && actorID == relationship.ServiceUserID:
+		relationship.Status = StatusDeclined
+	case fromStatus == StatusPending:
+		relationship.Status = StatusEnded
+	default:
+		return nil, fmt.Errorf("relationship is already %s", fromStatus)
+	}
+
+	now := time.Now()
+	relationship.EndedAt = &now
+	relationship.EndedBy = &actorID
+	if req != nil && req.Reason != nil {
+		reason := strings.TrimSpace(*req.Reason)
+		if len(reason) > 1000 {
+			return nil, fmt.Errorf("reason must be at most 1000 characters")
+		}
+		if reason != "" {
+			relationship.EndReason = &reason
+		}
+	}
+	// An open-ended or future end date is brought forward to now
+	if relationship.EndsAt == nil || relationship.EndsAt.After(now) {
+		if relationship.StartsAt.Before(now) {
+			relationship.EndsAt = &now
+		} else {
+			relationship.EndsAt = nil
+		}
+	}
+
+	updated, err := s.store.UpdateRelationship(ctx, relationship, fromStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	return present(updated, now), nil
+}
+
+func (s *service) list(ctx context.Context, filter *RelationshipFilter) (*ListRelationshipsResponse, error) {
+	relationships, err := s.store.ListRelationships(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := make([]Relationship, len(relationships))
+	for i := range relationships {
+		result[i] = *present(&relationships[i], now)
+	}
+
+	return &ListRelationshipsResponse{Relationships: result}, nil
+}
+
+// present fills in IsCurrent and reports active relationships past their end
+// date as ended
+func present(relationship *Relationship, now time.Time) *Relationship {
+	relationship.IsCurrent = relationship.Current(now)
+	if relationship.Status == StatusActive && relationship.EndsAt != nil && !relationship.EndsAt.After(now) {
+		relationship.Status = StatusEnded
+	}
+	return relationship
+}
+
+func isValidStatus(status string) bool {
+	switch status {
+	case StatusPending, StatusActive, StatusDeclined, StatusEnded:
+		return true
+	}
+	return false
+}
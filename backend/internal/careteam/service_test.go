@@ -0,0 +1,208 @@
+package careteam
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+)
+
+const (
+	professional = "11111111-1111-1111-1111-111111111111"
+	parent       = "22222222-2222-2222-2222-222222222222"
+	otherParent  = "33333333-3333-3333-3333-333333333333"
+	staff        = "44444444-4444-4444-4444-444444444444"
+)
+
+func newTestService(t *testing.T) Service {
+	t.Helper()
+
+	db := memdb.New()
+	now := time.Now()
+	for _, u := range []memdb.User{
+		{ID: professional, FullName: "Pat Professional", Email: "pro@example.com", Role: "professional", IsActive: true},
+		{ID: parent, FullName: "Sam Parent", Email: "parent@example.com", Role: "service_user", IsActive: true},
+		{ID: otherParent, FullName: "Alex Parent", Email: "alex@example.com", Role: "service_user", IsActive: true},
+		{ID: staff, FullName: "Nia Staff", Email: "staff@example.com", Role: "nhs_staff", IsActive: true},
+	} {
+		u.CreatedAt, u.UpdatedAt = now, now
+		if err := db.InsertUser(u, memdb.Profile{UserID: u.ID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return NewService(NewMemoryStore(db))
+}
+
+func TestInvitationLifecycle(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t)
+
+	invitation, err := svc.Invite(ctx, professional, &InviteRequest{ServiceUserID: parent, Reason: "Health visitor"})
+	if err != nil {
+		t.Fatalf("Invite() error = %v", err)
+	}
+	if invitation.Status != StatusPending || invitation.IsCurrent {
+		t.Fatalf("Invite() = %+v, want pending and not current", invitation)
+	}
+	if _, err := svc.Invite(ctx, professional, &InviteRequest{ServiceUserID: parent, Reason: "Health visitor"}); !errors.Is(err, ErrAlreadyLinked) {
+		t.Errorf("second Invite() error = %v, want ErrAlreadyLinked", err)
+	}
+	if _, err := svc.CheckAccess(ctx, professional, parent); !errors.Is(err, ErrNoAccess) {
+		t.Errorf("CheckAccess() before accepting error = %v, want ErrNoAccess", err)
+	}
+
+	if _, err := svc.Accept(ctx, otherParent, invitation.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Accept() by another service user error = %v, want ErrNotFound", err)
+	}
+	accepted, err := svc.Accept(ctx, parent, invitation.ID)
+	if err != nil {
+		t.Fatalf("Accept() error = %v", err)
+	}
+	if accepted.Status != StatusActive || !accepted.IsCurrent || accepted.AcceptedAt == nil {
+		t.Errorf("Accept() = %+v, want active and current", accepted)
+	}
+
+	share := true
+	if _, err := svc.UpdateSharing(ctx, parent, invitation.ID, &UpdateSharingRequest{ShareJourney: &share}); err != nil {
+		t.Fatalf("UpdateSharing() error = %v", err)
+	}
+	access, err := svc.CheckAccess(ctx, professional, parent)
+	if err != nil {
+		t.Fatalf("CheckAccess() error = %v", err)
+	}
+	if access.Via != AccessCareTeam || !access.ShareJourney {
+		t.Errorf("CheckAccess() = %+v, want care team access with journey shared", access)
+	}
+
+	ids, err := svc.ServiceUserIDs(ctx, professional)
+	if err != nil || len(ids) != 1 || ids[0] != parent {
+		t.Errorf("ServiceUserIDs() = %v, %v, want [%s]", ids, err, parent)
+	}
+
+	ended, err := svc.End(ctx, parent, invitation.ID, nil)
+	if err != nil {
+		t.Fatalf("End() error = %v", err)
+	}
+	if ended.Status != StatusEnded || ended.IsCurrent || ended.EndedBy == nil || *ended.EndedBy != parent {
+		t.Errorf("End() = %+v, want ended by the service user", ended)
+	}
+	if _, err := svc.CheckAccess(ctx, professional, parent); !errors.Is(err, ErrNoAccess) {
+		t.Errorf("CheckAccess() after ending error = %v, want ErrNoAccess", err)
+	}
+
+	// The pair can be linked again once the old relationship has ended
+	if _, err := svc.Invite(ctx, professional, &InviteRequest{ServiceUserID: parent, Reason: "Returning to the team"}); err != nil {
+		t.Errorf("Invite() after ending error = %v", err)
+	}
+}
+
+func TestEndPendingInvitation(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		by         string
+		wantStatus string
+	}{
+		{"declined by service user", parent, StatusDeclined},
+		{"withdrawn by professional", professional, StatusEnded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestService(t)
+			invitation, err := svc.Invite(ctx, professional, &InviteRequest{ServiceUserID: parent, Reason: "Health visitor"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ended, err := svc.End(ctx, tt.by, invitation.ID, nil)
+			if err != nil {
+				t.Fatalf("End() error = %v", err)
+			}
+			if ended.Status != tt.wantStatus {
+				t.Errorf("End() status = %s, want %s", ended.Status, tt.wantStatus)
+			}
+			if _, err := svc.End(ctx, tt.by, invitation.ID, nil); err == nil {
+				t.Error("End() twice succeeded, want error")
+			}
+			if _, err := svc.End(ctx, otherParent, invitation.ID, nil); !errors.Is(err, ErrNotFound) {
+				t.Errorf("End() by a stranger error = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestCheckAccess(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t)
+
+	future := time.Now().Add(24 * time.Hour)
+	if _, err := svc.Assign(ctx, staff, &AssignRequest{ProfessionalID: professional, ServiceUserID: otherParent, Reason: "Booked for next week", StartsAt: &future}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		actor   string
+		subject string
+		wantVia string
+		wantErr error
+	}{
+		{"self", parent, parent, AccessSelf, nil},
+		{"staff subject is not restricted", parent, staff, AccessNotRestricted, nil},
+		{"no relationship", professional, parent, "", ErrNoAccess},
+		{"relationship not started yet", professional, otherParent, "", ErrNoAccess},
+		{"other service user", parent, otherParent, "", ErrNoAccess},
+		{"unknown subject", professional, "55555555-5555-5555-5555-555555555555", "", ErrNoAccess},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			access, err := svc.CheckAccess(ctx, tt.actor, tt.subject)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("CheckAccess() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CheckAccess() error = %v", err)
+			}
+			if access.Via != tt.wantVia {
+				t.Errorf("CheckAccess() via = %s, want %s", access.Via, tt.wantVia)
+			}
+		})
+	}
+}
+
+func TestBreakGlass(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t)
+
+	if _, err := svc.BreakGlass(ctx, staff, &BreakGlassRequest{ServiceUserID: parent, Reason: "too short"}); err == nil {
+		t.Error("BreakGlass() with a short reason succeeded, want error")
+	}
+	if _, err := svc.BreakGlass(ctx, otherParent, &BreakGlassRequest{ServiceUserID: parent, Reason: "Crisis call out of hours"}); err == nil {
+		t.Error("BreakGlass() by a service user succeeded, want error")
+	}
+
+	grant, err := svc.BreakGlass(ctx, staff, &BreakGlassRequest{ServiceUserID: parent, Reason: "Crisis call out of hours"})
+	if err != nil {
+		t.Fatalf("BreakGlass() error = %v", err)
+	}
+	if until := time.Until(grant.ExpiresAt); until <= 0 || until > BreakGlassDuration {
+		t.Errorf("BreakGlass() expires in %s, want within %s", until, BreakGlassDuration)
+	}
+
+	access, err := svc.CheckAccess(ctx, staff, parent)
+	if err != nil {
+		t.Fatalf("CheckAccess() error = %v", err)
+	}
+	if access.Via != AccessBreakGlass || access.BreakGlassID == nil || *access.BreakGlassID != grant.ID || access.ShareJourney {
+		t.Errorf("CheckAccess() = %+v, want break-glass access without journey", access)
+	}
+}
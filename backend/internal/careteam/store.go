@@ -0,0 +1,294 @@
+package careteam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/perinatal-mental-health-app/backend/internal/encryption"
+)
+
+type store struct {
+	db     *pgxpool.Pool
+	cipher encryption.Cipher
+}
+
+func NewStore(db *pgxpool.Pool, cipher encryption.Cipher) Store {
+	return &store{
+		db:     db,
+		cipher: cipher,
+	}
+}
+
+const relationshipColumns = `r.id, r.professional_id, professional.full_name, r.service_user_id, service_user.full_name,
+	r.status, r.source, r.reason, r.share_journey, r.starts_at, r.ends_at, r.created_by, r.accepted_at,
+	r.ended_at, r.ended_by, r.end_reason, r.created_at, r.updated_at`
+
+const relationshipTables = `care_team_relationships r
+	JOIN users professional ON professional.id = r.professional_id
+	JOIN users service_user ON service_user.id = r.service_user_id`
+
+// CreateRelationship creates a relationship, failing with ErrAlreadyLinked if
+// the pair already has a pending or active one
+func (s *store) CreateRelationship(ctx context.Context, relationship *Relationship) (*Relationship, error) {
+	reason, err := s.cipher.Encrypt(ctx, relationship.Reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt care-team reason: %w", err)
+	}
+
+	query := `
+		INSERT INTO care_team_relationships (id, professional_id, service_user_id, status, source, reason,
+		                                     share_journey, starts_at, ends_at, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	_, err = s.db.Exec(ctx, query,
+		relationship.ID, relationship.ProfessionalID, relationship.ServiceUserID, relationship.Status,
+		relationship.Source, reason, relationship.ShareJourney, relationship.StartsAt, relationship.EndsAt,
+		relationship.CreatedBy, relationship.CreatedAt, relationship.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrAlreadyLinked
+		}
+		return nil, fmt.Errorf("failed to create care-team relationship: %w", err)
+	}
+
+	return s.GetRelationship(ctx, relationship.ID)
+}
+
+// GetRelationship retrieves a relationship with both parties' names
+func (s *store) GetRelationship(ctx context.Context, relationshipID string) (*Relationship, error) {
+	if _, err := uuid.Parse(relationshipID); err != nil {
+		return nil, ErrNotFound
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE r.id = $1`, relationshipColumns, relationshipTables)
+
+	var relationship Relationship
+	if err := scanRelationship(s.db.QueryRow(ctx, query, relationshipID), &relationship); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get care-team relationship: %w", err)
+	}
+	if err := s.cipher.DecryptFields(ctx, &relationship); err != nil {
+		return nil, fmt.Errorf("failed to decrypt care-team relationship: %w", err)
+	}
+
+	return &relationship, nil
+}
+
+// ListRelationships retrieves relationships, newest first
+func (s *store) ListRelationships(ctx context.Context, filter *RelationshipFilter) ([]Relationship, error) {
+	var whereClause []string
+	var args []interface{}
+	argIndex := 1
+
+	if filter.ProfessionalID != "" {
+		whereClause = append(whereClause, fmt.Sprintf("r.professional_id = $%d", argIndex))
+		args = append(args, filter.ProfessionalID)
+		argIndex++
+	}
+
+	if filter.ServiceUserID != "" {
+		whereClause = append(whereClause, fmt.Sprintf("r.service_user_id = $%d", argIndex))
+		args = append(args, filter.ServiceUserID)
+		argIndex++
+	}
+
+	if len(filter.Statuses) > 0 {
+		whereClause = append(whereClause, fmt.Sprintf("r.status = ANY($%d)", argIndex))
+		args = append(args, filter.Statuses)
+		argIndex++
+	}
+
+	whereSQL := ""
+	if len(whereClause) > 0 {
+		whereSQL = "WHERE " + strings.Join(whereClause, " AND ")
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM %s %s ORDER BY r.created_at DESC, r.id DESC`, relationshipColumns, relationshipTables, whereSQL)
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list care-team relationships: %w", err)
+	}
+	defer rows.Close()
+
+	var relationships []Relationship
+	for rows.Next() {
+		var relationship Relationship
+		if err := scanRelationship(rows, &relationship); err != nil {
+			return nil, fmt.Errorf("failed to scan care-team relationship: %w", err)
+		}
+		if err := s.cipher.DecryptFields(ctx, &relationship); err != nil {
+			return nil, fmt.Errorf("failed to decrypt care-team relationship: %w", err)
+		}
+		relationships = append(relationships, relationship)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return relationships, nil
+}
+
+// UpdateRelationship saves the status, sharing and end details of a relationship
+func (s *store) UpdateRelationship(ctx context.Context, relationship *Relationship, fromStatus string) (*Relationship, error) {
+	var endReason *string
+	if relationship.EndReason != nil {
+		sealed, err := s.cipher.Encrypt(ctx, *relationship.EndReason)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt care-team end reason: %w", err)
+		}
+		endReason = &sealed
+	}
+
+	query := `
+		UPDATE care_team_relationships
+		SET status = $2, share_journey = $3, ends_at = $4, accepted_at = $5,
+		    ended_at = $6, ended_by = $7, end_reason = $8
+		WHERE id = $1 AND status = $9
+	`
+
+	result, err := s.db.Exec(ctx, query,
+		relationship.ID, relationship.Status, relationship.ShareJourney, relationship.EndsAt,
+		relationship.AcceptedAt, relationship.EndedAt, relationship.EndedBy, endReason, fromStatus,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update care-team relationship: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return nil, ErrStatusChanged
+	}
+
+	return s.GetRelationship(ctx, relationship.ID)
+}
+
+// FindCurrentRelationship returns the relationship granting access at the given time
+func (s *store) FindCurrentRelationship(ctx context.Context, professionalID, serviceUserID string, at time.Time) (*Relationship, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE r.professional_id = $1 AND r.service_user_id = $2 AND r.status = 'active'
+		  AND r.starts_at <= $3 AND (r.ends_at IS NULL OR r.ends_at > $3)
+		LIMIT 1
+	`, relationshipColumns, relationshipTables)
+
+	var relationship Relationship
+	if err := scanRelationship(s.db.QueryRow(ctx, query, professionalID, serviceUserID, at), &relationship); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find care-team relationship: %w", err)
+	}
+	if err := s.cipher.DecryptFields(ctx, &relationship); err != nil {
+		return nil, fmt.Errorf("failed to decrypt care-team relationship: %w", err)
+	}
+
+	return &relationship, nil
+}
+
+// CreateBreakGlass records a break-glass grant
+func (s *store) CreateBreakGlass(ctx context.Context, grant *BreakGlassGrant) (*BreakGlassGrant, error) {
+	reason, err := s.cipher.Encrypt(ctx, grant.Reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt break-glass reason: %w", err)
+	}
+
+	query := `
+		INSERT INTO break_glass_access (id, staff_id, service_user_id, reason, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err = s.db.Exec(ctx, query, grant.ID, grant.StaffID, grant.ServiceUserID, reason, grant.ExpiresAt, grant.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create break-glass access: %w", err)
+	}
+
+	created := *grant
+	return &created, nil
+}
+
+// FindBreakGlass returns the staff member's latest unexpired grant for the service user
+func (s *store) FindBreakGlass(ctx context.Context, staffID, serviceUserID string, at time.Time) (*BreakGlassGrant, error) {
+	query := `
+		SELECT id, staff_id, service_user_id, reason, expires_at, created_at
+		FROM break_glass_access
+		WHERE staff_id = $1 AND service_user_id = $2 AND expires_at > $3
+		ORDER BY expires_at DESC
+		LIMIT 1
+	`
+
+	var grant BreakGlassGrant
+	err := s.db.QueryRow(ctx, query, staffID, serviceUserID, at).Scan(
+		&grant.ID, &grant.StaffID, &grant.ServiceUserID, &grant.Reason, &grant.ExpiresAt, &grant.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find break-glass access: %w", err)
+	}
+	if err := s.cipher.DecryptFields(ctx, &grant); err != nil {
+		return nil, fmt.Errorf("failed to decrypt break-glass access: %w", err)
+	}
+
+	return &grant, nil
+}
+
+// GetUserRole returns the role of an active user
+func (s *store) GetUserRole(ctx context.Context, userID string) (string, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return "", ErrUserNotFound
+	}
+
+	var role string
+	err := s.db.QueryRow(ctx, `SELECT role FROM users WHERE id = $1 AND is_active = true`, userID).Scan(&role)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", ErrUserNotFound
+		}
+		return "", fmt.Errorf("failed to get user role: %w", err)
+	}
+
+	return role, nil
+}
+
+// Helper functions
+
+func scanRelationship(row pgx.Row, relationship *Relationship) error {
+	return row.Scan(
+		&relationship.ID,
+		&relationship.ProfessionalID,
+		&relationship.ProfessionalName,
+		&relationship.ServiceUserID,
+		&relationship.ServiceUserName,
+		&relationship.Status,
+		&relationship.Source,
+		&relationship.Reason,
+		&relationship.ShareJourney,
+		&relationship.StartsAt,
+		&relationship.EndsAt,
+		&relationship.CreatedBy,
+		&relationship.AcceptedAt,
+		&relationship.EndedAt,
+		&relationship.EndedBy,
+		&relationship.EndReason,
+		&relationship.CreatedAt,
+		&relationship.UpdatedAt,
+	)
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
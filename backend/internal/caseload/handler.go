@@ -0,0 +1,60 @@
+package caseload
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	service Service
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// ListCaseload lists the caller's caseload
+func (h *handler) ListCaseload(c echo.Context) error {
+	userID, ok := c.Get("user_id").(string)
+	if !ok || userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "User not authenticated",
+		})
+	}
+
+	req := &ListCaseloadRequest{
+		Sort:  c.QueryParam("sort"),
+		Order: c.QueryParam("order"),
+	}
+	if u := c.QueryParam("urgent"); u != "" {
+		parsed, err := strconv.ParseBool(u)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "urgent must be true or false",
+			})
+		}
+		req.UrgentOnly = parsed
+	}
+	if d := c.QueryParam("no_check_in_days"); d != "" {
+		parsed, err := strconv.Atoi(d)
+		if err != nil || parsed < 1 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "no_check_in_days must be a positive number",
+			})
+		}
+		req.NoCheckInDays = parsed
+	}
+
+	caseload, err := h.service.ListCaseload(c.Request().Context(), userID, req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, caseload)
+}
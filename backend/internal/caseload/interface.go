@@ -0,0 +1,18 @@
+package caseload
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Service defines the interface for the professional caseload overview
+type Service interface {
+	// ListCaseload lists the service users a professional currently cares for
+	ListCaseload(ctx context.Context, professionalID string, req *ListCaseloadRequest) (*ListCaseloadResponse, error)
+}
+
+// Handler defines the interface for caseload HTTP handlers
+type Handler interface {
+	ListCaseload(c echo.Context) error
+}
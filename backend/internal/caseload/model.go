@@ -0,0 +1,73 @@
+package caseload
+
+import (
+	"time"
+)
+
+// Caseload sort orders
+const (
+	SortName                 = "name"
+	SortLastCheckIn          = "last_check_in"
+	SortOutstandingReferrals = "outstanding_referrals"
+)
+
+// Sort directions
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// Entry summarises one service user in a professional's caseload
+type Entry struct {
+	ServiceUserID   string `json:"service_user_id"`
+	ServiceUserName string `json:"service_user_name"`
+	RelationshipID  string `json:"relationship_id"`
+	ShareJourney    bool   `json:"share_journey"`
+
+	// LastCheckIn is the date of the latest journey entry
+	LastCheckIn      *time.Time `json:"last_check_in,omitempty"`
+	DaysSinceCheckIn *int       `json:"days_since_check_in,omitempty"`
+	// MoodTrend is only filled in when the service user shares their journey
+	MoodTrend *string `json:"mood_trend,omitempty"`
+
+	OutstandingReferrals []ReferralSummary `json:"outstanding_referrals"`
+	HasUrgentReferral    bool              `json:"has_urgent_referral"`
+	UpcomingSessions     []GroupSession    `json:"upcoming_sessions"`
+}
+
+// ReferralSummary is a pending or viewed referral sent to a service user
+type ReferralSummary struct {
+	ID           string    `json:"id"`
+	ReferralType string    `json:"referral_type"`
+	ItemID       string    `json:"item_id"`
+	ItemTitle    string    `json:"item_title"`
+	Status       string    `json:"status"`
+	IsUrgent     bool      `json:"is_urgent"`
+	ReferredBy   string    `json:"referred_by"`
+	ReferrerName string    `json:"referrer_name"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// GroupSession is a support group a service user attends, with its meeting time
+type GroupSession struct {
+	GroupID     string  `json:"group_id"`
+	Name        string  `json:"name"`
+	Platform    string  `json:"platform"`
+	MeetingTime *string `json:"meeting_time,omitempty"`
+}
+
+// ListCaseloadRequest represents the filters and sort order for the caseload
+type ListCaseloadRequest struct {
+	// UrgentOnly keeps service users with an outstanding urgent referral
+	UrgentOnly bool `json:"urgent_only"`
+	// NoCheckInDays keeps service users who haven't checked in for at least this many days
+	NoCheckInDays int    `json:"no_check_in_days" validate:"omitempty,min=1,max=365"`
+	Sort          string `json:"sort" validate:"omitempty,oneof=name last_check_in outstanding_referrals"`
+	Order         string `json:"order" validate:"omitempty,oneof=asc desc"`
+}
+
+// ListCaseloadResponse represents a professional's caseload
+type ListCaseloadResponse struct {
+	Caseload []Entry `json:"caseload"`
+	Total    int     `json:"total"`
+}
@@ -0,0 +1,218 @@
+package caseload
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/careteam"
+	"github.com/perinatal-mental-health-app/backend/internal/journey"
+	"github.com/perinatal-mental-health-app/backend/internal/referrals"
+	"github.com/perinatal-mental-health-app/backend/internal/support_groups"
+)
+
+type service struct {
+	careTeam      careteam.Service
+	journey       journey.Service
+	referrals     referrals.Service
+	supportGroups support_groups.Service
+}
+
+func NewService(careTeamService careteam.Service, journeyService journey.Service, referralsService referrals.Service, supportGroupsService support_groups.Service) Service {
+	return &service{
+		careTeam:      careTeamService,
+		journey:       journeyService,
+		referrals:     referralsService,
+		supportGroups: supportGroupsService,
+	}
+}
+
+// ListCaseload lists the service users a professional currently cares for.
+// Check-ins, referrals and groups are each loaded for the whole caseload in
+// one call, so the cost doesn't grow with the number of service users.
+func (s *service) ListCaseload(ctx context.Context, professionalID string, req *ListCaseloadRequest) (*ListCaseloadResponse, error) {
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+
+	relationships, err := s.careTeam.ListForProfessional(ctx, professionalID, careteam.StatusActive)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(relationships.Relationships))
+	userIDs := make([]string, 0, len(relationships.Relationships))
+	for _, relationship := range relationships.Relationships {
+		if !relationship.IsCurrent {
+			continue
+		}
+		entries = append(entries, Entry{
+			ServiceUserID:        relationship.ServiceUserID,
+			ServiceUserName:      relationship.ServiceUserName,
+			RelationshipID:       relationship.ID,
+			ShareJourney:         relationship.ShareJourney,
+			OutstandingReferrals: []ReferralSummary{},
+			UpcomingSessions:     []GroupSession{},
+		})
+		userIDs = append(userIDs, relationship.ServiceUserID)
+	}
+
+	checkIns, err := s.journey.GetCheckInSummaries(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load check-ins: %w", err)
+	}
+	outstanding, err := s.referrals.ListOutstandingReferrals(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load referrals: %w", err)
+	}
+	groups, err := s.supportGroups.GetGroupsForUsers(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load support groups: %w", err)
+	}
+
+	referralsByUser := make(map[string][]ReferralSummary)
+	for _, referral := range outstanding {
+		referralsByUser[referral.ReferredTo] = append(referralsByUser[referral.ReferredTo], summariseReferral(referral))
+	}
+
+	now := time.Now()
+	result := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		if checkIn, ok := checkIns[entry.ServiceUserID]; ok {
+			entry.LastCheckIn = checkIn.LastCheckIn
+			if checkIn.LastCheckIn != nil {
+				days := int(now.Sub(*checkIn.LastCheckIn).Hours() / 24)
+				entry.DaysSinceCheckIn = &days
+			}
+			if entry.ShareJourney {
+				trend := checkIn.MoodTrend
+				entry.MoodTrend = &trend
+			}
+		}
+
+		if userReferrals, ok := referralsByUser[entry.ServiceUserID]; ok {
+			entry.OutstandingReferrals = userReferrals
+			for _, referral := range userReferrals {
+				entry.HasUrgentReferral = entry.HasUrgentReferral || referral.IsUrgent
+			}
+		}
+
+		for _, group := range groups[entry.ServiceUserID] {
+			entry.UpcomingSessions = append(entry.UpcomingSessions, GroupSession{
+				GroupID:     group.ID,
+				Name:        group.Name,
+				Platform:    group.Platform,
+				MeetingTime: group.MeetingTime,
+			})
+		}
+
+		if req.UrgentOnly && !entry.HasUrgentReferral {
+			continue
+		}
+		if req.NoCheckInDays > 0 && entry.DaysSinceCheckIn != nil && *entry.DaysSinceCheckIn < req.NoCheckInDays {
+			continue
+		}
+
+		result = append(result, entry)
+	}
+
+	sortEntries(result, req.Sort, req.Order)
+
+	return &ListCaseloadResponse{
+		Caseload: result,
+		Total:    len(result),
+	}, nil
+}
+
+// Helper functions
+
+func validateRequest(req *ListCaseloadRequest) error {
+	if req.NoCheckInDays < 0 || req.NoCheckInDays > 365 {
+		return fmt.Errorf("no_check_in_days must be between 1 and 365")
+	}
+
+	switch req.Sort {
+	case "":
+		req.Sort = SortName
+	case SortName, SortLastCheckIn, SortOutstandingReferrals:
+	default:
+		return fmt.Errorf("invalid sort: %s", req.Sort)
+	}
+
+	switch req.Order {
+	case "":
+		// Most outstanding referrals first; otherwise alphabetical, or longest
+		// since checking in first
+		req.Order = OrderAsc
+		if req.Sort == SortOutstandingReferrals {
+			req.Order = OrderDesc
+		}
+	case OrderAsc, OrderDesc:
+	default:
+		return fmt.Errorf("invalid order: %s", req.Order)
+	}
+
+	return nil
+}
+
+func summariseReferral(referral referrals.Referral) ReferralSummary {
+	summary := ReferralSummary{
+		ID:           referral.ID,
+		ReferralType: referral.ReferralType,
+		ItemID:       referral.ItemID,
+		Status:       referral.Status,
+		IsUrgent:     referral.IsUrgent,
+		ReferredBy:   referral.ReferredBy,
+		CreatedAt:    referral.CreatedAt,
+	}
+	if referral.ItemTitle != nil {
+		summary.ItemTitle = *referral.ItemTitle
+	}
+	if referral.ReferrerName != nil {
+		summary.ReferrerName = *referral.ReferrerName
+	}
+
+	return summary
+}
+
+// sortEntries sorts the caseload, breaking ties by name. Service users who have
+// never checked in count as the longest since checking in.
+func sortEntries(entries []Entry, by, order string) {
+	compare := func(a, b *Entry) int {
+		switch by {
+		case SortLastCheckIn:
+			switch {
+			case a.LastCheckIn == nil && b.LastCheckIn == nil:
+				return 0
+			case a.LastCheckIn == nil:
+				return -1
+			case b.LastCheckIn == nil:
+				return 1
+			}
+			return a.LastCheckIn.Compare(*b.LastCheckIn)
+		case SortOutstandingReferrals:
+			return len(a.OutstandingReferrals) - len(b.OutstandingReferrals)
+		}
+		return compareNames(a, b)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		c := compare(&entries[i], &entries[j])
+		if c == 0 {
+			return compareNames(&entries[i], &entries[j]) < 0
+		}
+		if order == OrderDesc {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+func compareNames(a, b *Entry) int {
+	if c := strings.Compare(strings.ToLower(a.ServiceUserName), strings.ToLower(b.ServiceUserName)); c != 0 {
+		return c
+	}
+	return strings.Compare(a.ServiceUserID, b.ServiceUserID)
+}
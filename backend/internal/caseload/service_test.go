@@ -0,0 +1,201 @@
+package caseload
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/careteam"
+	"github.com/perinatal-mental-health-app/backend/internal/jobs"
+	"github.com/perinatal-mental-health-app/backend/internal/journey"
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+	"github.com/perinatal-mental-health-app/backend/internal/referrals"
+	"github.com/perinatal-mental-health-app/backend/internal/support_groups"
+)
+
+const (
+	professional = "11111111-1111-1111-1111-111111111111"
+	amara        = "22222222-2222-2222-2222-222222222222" // shares journey, urgent referral, in a group
+	beth         = "33333333-3333-3333-3333-333333333333" // never checked in
+	cara         = "44444444-4444-4444-4444-444444444444" // checked in 10 days ago, doesn't share journey
+	unlinked     = "55555555-5555-5555-5555-555555555555"
+	serviceID    = "66666666-6666-6666-6666-666666666666"
+)
+
+func newTestService(t *testing.T) Service {
+	t.Helper()
+	ctx := context.Background()
+
+	db := memdb.New()
+	now := time.Now()
+	for _, u := range []memdb.User{
+		{ID: professional, FullName: "Pat Professional", Email: "pro@example.com", Role: "professional", IsActive: true},
+		{ID: amara, FullName: "Amara", Email: "amara@example.com", Role: "service_user", IsActive: true},
+		{ID: beth, FullName: "Beth", Email: "beth@example.com", Role: "service_user", IsActive: true},
+		{ID: cara, FullName: "Cara", Email: "cara@example.com", Role: "service_user", IsActive: true},
+		{ID: unlinked, FullName: "Dana", Email: "dana@example.com", Role: "service_user", IsActive: true},
+	} {
+		u.CreatedAt, u.UpdatedAt = now, now
+		if err := db.InsertUser(u, memdb.Profile{UserID: u.ID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db.PutItem(memdb.Item{Type: memdb.ItemService, ID: serviceID, Title: "Talking Therapies", IsActive: true})
+
+	careTeam := careteam.NewService(careteam.NewMemoryStore(db))
+	for _, serviceUserID := range []string{amara, beth, cara} {
+		relationship, err := careTeam.Assign(ctx, professional, &careteam.AssignRequest{ProfessionalID: professional, ServiceUserID: serviceUserID, Reason: "Health visitor"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if serviceUserID == amara {
+			share := true
+			if _, err := careTeam.UpdateSharing(ctx, amara, relationship.ID, &careteam.UpdateSharingRequest{ShareJourney: &share}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	journeyStore := journey.NewMemoryStore()
+	for _, entry := range []struct {
+		userID  string
+		daysAgo int
+		mood    int
+	}{
+		{amara, 2, 4},
+		{amara, 20, 2},
+		{cara, 10, 3},
+		{unlinked, 1, 1},
+	} {
+		if _, err := journeyStore.CreateJourneyEntry(ctx, entry.userID, now.AddDate(0, 0, -entry.daysAgo), &journey.CreateJourneyEntryRequest{MoodRating: entry.mood}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	referralsStore := referrals.NewMemoryStore(db)
+	for i, referral := range []referrals.Referral{
+		{ReferredTo: amara, IsUrgent: true, Status: string(referrals.StatusPending)},
+		{ReferredTo: cara, Status: string(referrals.StatusViewed)},
+		{ReferredTo: cara, Status: string(referrals.StatusAccepted)},
+		{ReferredTo: unlinked, IsUrgent: true, Status: string(referrals.StatusPending)},
+	} {
+		referral.ID = string(rune('a'+i)) + "0000000-0000-0000-0000-000000000000"
+		referral.ReferredBy = professional
+		referral.ReferralType = string(referrals.TypeService)
+		referral.ItemID = serviceID
+		referral.Reason = "Recommended after our appointment"
+		referral.CreatedAt, referral.UpdatedAt = now, now
+		if _, err := referralsStore.CreateReferral(ctx, &referral); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	groupsStore := support_groups.NewMemoryStore(db)
+	meetingTime := "Tuesdays 10:00"
+	group, err := groupsStore.CreateSupportGroup(ctx, &support_groups.CreateSupportGroupRequest{Name: "New Parents Circle", Description: "Weekly group", Category: "postnatal", Platform: "online", MeetingTime: &meetingTime})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := groupsStore.JoinGroup(ctx, amara, group.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewService(
+		careTeam,
+		journey.NewService(journeyStore, jobs.NewService(jobs.NewMemoryStore())),
+		referrals.NewService(referralsStore, careTeam),
+		support_groups.NewService(groupsStore),
+	)
+}
+
+func TestListCaseload(t *testing.T) {
+	svc := newTestService(t)
+
+	caseload, err := svc.ListCaseload(context.Background(), professional, &ListCaseloadRequest{})
+	if err != nil {
+		t.Fatalf("ListCaseload() error = %v", err)
+	}
+	if caseload.Total != 3 || len(caseload.Caseload) != 3 {
+		t.Fatalf("ListCaseload() total = %d, want 3", caseload.Total)
+	}
+
+	byID := make(map[string]Entry)
+	for _, entry := range caseload.Caseload {
+		byID[entry.ServiceUserID] = entry
+	}
+
+	a := byID[amara]
+	if a.MoodTrend == nil || *a.MoodTrend != "improving" {
+		t.Errorf("amara mood trend = %v, want improving", a.MoodTrend)
+	}
+	if !a.HasUrgentReferral || len(a.OutstandingReferrals) != 1 || a.OutstandingReferrals[0].ItemTitle != "Talking Therapies" {
+		t.Errorf("amara referrals = %+v, want one urgent Talking Therapies referral", a.OutstandingReferrals)
+	}
+	if len(a.UpcomingSessions) != 1 || a.UpcomingSessions[0].MeetingTime == nil || *a.UpcomingSessions[0].MeetingTime != "Tuesdays 10:00" {
+		t.Errorf("amara sessions = %+v, want New Parents Circle on Tuesdays", a.UpcomingSessions)
+	}
+
+	if b := byID[beth]; b.LastCheckIn != nil || b.MoodTrend != nil || len(b.OutstandingReferrals) != 0 {
+		t.Errorf("beth = %+v, want no check-ins or referrals", b)
+	}
+
+	c := byID[cara]
+	if c.DaysSinceCheckIn == nil || *c.DaysSinceCheckIn != 10 {
+		t.Errorf("cara days since check-in = %v, want 10", c.DaysSinceCheckIn)
+	}
+	if c.MoodTrend != nil {
+		t.Errorf("cara mood trend = %s, want hidden without consent", *c.MoodTrend)
+	}
+	if len(c.OutstandingReferrals) != 1 || c.HasUrgentReferral {
+		t.Errorf("cara referrals = %+v, want one non-urgent outstanding referral", c.OutstandingReferrals)
+	}
+}
+
+func TestListCaseloadFiltersAndSorting(t *testing.T) {
+	svc := newTestService(t)
+
+	tests := []struct {
+		name    string
+		req     ListCaseloadRequest
+		want    []string
+		wantErr bool
+	}{
+		{"default sorts by name", ListCaseloadRequest{}, []string{amara, beth, cara}, false},
+		{"name descending", ListCaseloadRequest{Sort: SortName, Order: OrderDesc}, []string{cara, beth, amara}, false},
+		{"longest since check-in first", ListCaseloadRequest{Sort: SortLastCheckIn}, []string{beth, cara, amara}, false},
+		{"most referrals first", ListCaseloadRequest{Sort: SortOutstandingReferrals}, []string{amara, cara, beth}, false},
+		{"urgent only", ListCaseloadRequest{UrgentOnly: true}, []string{amara}, false},
+		{"no check-in for a week", ListCaseloadRequest{NoCheckInDays: 7}, []string{beth, cara}, false},
+		{"no check-in for a fortnight", ListCaseloadRequest{NoCheckInDays: 14}, []string{beth}, false},
+		{"invalid sort", ListCaseloadRequest{Sort: "mood"}, nil, true},
+		{"invalid order", ListCaseloadRequest{Order: "sideways"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caseload, err := svc.ListCaseload(context.Background(), professional, &tt.req)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("ListCaseload() succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListCaseload() error = %v", err)
+			}
+
+			var got []string
+			for _, entry := range caseload.Caseload {
+				got = append(got, entry.ServiceUserID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ListCaseload() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("ListCaseload() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
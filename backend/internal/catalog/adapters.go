@@ -0,0 +1,215 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/resources"
+	"github.com/perinatal-mental-health-app/backend/internal/services"
+	"github.com/perinatal-mental-health-app/backend/internal/support_groups"
+)
+
+// listPageSize is the largest page the list services accept
+const listPageSize = 100
+
+// collection adapts one catalog module to import and export. Rows reach it as
+// the JSON body of a create request, so the module's own validation applies.
+type collection struct {
+	columns  []column
+	validate func(scope organisations.Scope, body []byte) error
+	create   func(ctx context.Context, scope organisations.Scope, body []byte) (string, error)
+	update   func(ctx context.Context, scope organisations.Scope, itemID string, body []byte) error
+	list     func(ctx context.Context, page int) ([]interface{}, error)
+}
+
+func servicesCollection(svc services.Service) collection {
+	return collection{
+		columns: []column{
+			{"external_ref", kindText},
+			{"name", kindText},
+			{"description", kindText},
+			{"provider_name", kindText},
+			{"contact_email", kindText},
+			{"contact_phone", kindText},
+			{"website_url", kindText},
+			{"address", kindText},
+			{"service_type", kindText},
+			{"eligibility_criteria", kindText},
+			{"organisation_id", kindText},
+			{"perinatal_stages", kindList},
+		},
+		validate: func(scope organisations.Scope, body []byte) error {
+			var req services.CreateServiceRequest
+			if err := decodeStrict(body, &req); err != nil {
+				return err
+			}
+			return svc.ValidateService(scope, &req)
+		},
+		create: func(ctx context.Context, scope organisations.Scope, body []byte) (string, error) {
+			var req services.CreateServiceRequest
+			if err := decodeStrict(body, &req); err != nil {
+				return "", err
+			}
+			created, err := svc.CreateService(ctx, scope, &req)
+			if err != nil {
+				return "", err
+			}
+			return created.ID, nil
+		},
+		update: func(ctx context.Context, scope organisations.Scope, itemID string, body []byte) error {
+			var req services.UpdateServiceRequest
+			if err := decodeStrict(body, &req); err != nil {
+				return err
+			}
+			_, err := svc.UpdateServiceByUUID(ctx, scope, itemID, &req)
+			return err
+		},
+		list: func(ctx context.Context, page int) ([]interface{}, error) {
+			resp, err := svc.ListServices(ctx, &services.ListServicesRequest{Page: page, PageSize: listPageSize})
+			if err != nil {
+				return nil, err
+			}
+			items := make([]interface{}, len(resp.Services))
+			for i := range resp.Services {
+				items[i] = resp.Services[i]
+			}
+			return items, nil
+		},
+	}
+}
+
+func resourcesCollection(svc resources.Service) collection {
+	return collection{
+		columns: []column{
+			{"external_ref", kindText},
+			{"title", kindText},
+			{"description", kindText},
+			{"content", kindText},
+			{"resource_type", kindText},
+			{"url", kindText},
+			{"author", kindText},
+			{"tags", kindList},
+			{"target_audience", kindText},
+			{"estimated_read_time", kindInt},
+			{"is_featured", kindBool},
+			{"perinatal_stages", kindList},
+		},
+		validate: func(scope organisations.Scope, body []byte) error {
+			var req resources.CreateResourceRequest
+			if err := decodeStrict(body, &req); err != nil {
+				return err
+			}
+			return svc.ValidateResource(&req)
+		},
+		create: func(ctx context.Context, scope organisations.Scope, body []byte) (string, error) {
+			var req resources.CreateResourceRequest
+			if err := decodeStrict(body, &req); err != nil {
+				return "", err
+			}
+			created, err := svc.CreateResource(ctx, &req)
+			if err != nil {
+				return "", err
+			}
+			return created.ID, nil
+		},
+		update: func(ctx context.Context, scope organisations.Scope, itemID string, body []byte) error {
+			var req resources.UpdateResourceRequest
+			if err := decodeStrict(body, &req); err != nil {
+				return err
+			}
+			_, err := svc.UpdateResource(ctx, itemID, &req)
+			return err
+		},
+		list: func(ctx context.Context, page int) ([]interface{}, error) {
+			resp, err := svc.ListResources(ctx, &resources.ListResourcesRequest{Page: page, PageSize: listPageSize})
+			if err != nil {
+				return nil, err
+			}
+			items := make([]interface{}, len(resp.Resources))
+			for i := range resp.Resources {
+				items[i] = resp.Resources[i]
+			}
+			return items, nil
+		},
+	}
+}
+
+func supportGroupsCollection(svc support_groups.Service) collection {
+	return collection{
+		columns: []column{
+			{"external_ref", kindText},
+			{"name", kindText},
+			{"description", kindText},
+			{"category", kindText},
+			{"platform", kindText},
+			{"doctor_info", kindText},
+			{"url", kindText},
+			{"guidelines", kindText},
+			{"meeting_time", kindText},
+			{"max_members", kindInt},
+			{"organisation_id", kindText},
+			{"perinatal_stages", kindList},
+		},
+		validate: func(scope organisations.Scope, body []byte) error {
+			var req support_groups.CreateSupportGroupRequest
+			if err := decodeStrict(body, &req); err != nil {
+				return err
+			}
+			return svc.ValidateSupportGroup(scope, &req)
+		},
+		create: func(ctx context.Context, scope organisations.Scope, body []byte) (string, error) {
+			var req support_groups.CreateSupportGroupRequest
+			if err := decodeStrict(body, &req); err != nil {
+				return "", err
+			}
+			created, err := svc.CreateSupportGroup(ctx, scope, &req)
+			if err != nil {
+				return "", err
+			}
+			return created.ID, nil
+		},
+		update: func(ctx context.Context, scope organisations.Scope, itemID string, body []byte) error {
+			var req support_groups.UpdateSupportGroupRequest
+			if err := decodeStrict(body, &req); err != nil {
+				return err
+			}
+			_, err := svc.UpdateSupportGroup(ctx, scope, itemID, &req)
+			return err
+		},
+		list: func(ctx context.Context, page int) ([]interface{}, error) {
+			resp, err := svc.ListSupportGroups(ctx, page, listPageSize, "", "", "")
+			if err != nil {
+				return nil, err
+			}
+			items := make([]interface{}, len(resp.SupportGroups))
+			for i := range resp.SupportGroups {
+				items[i] = resp.SupportGroups[i]
+			}
+			return items, nil
+		},
+	}
+}
+
+// listAll pages through every active item in a collection
+func (c collection) listAll(ctx context.Context) ([]interface{}, error) {
+	var all []interface{}
+	for page := 1; ; page++ {
+		items, err := c.list(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if len(items) < listPageSize {
+			return all, nil
+		}
+	}
+}
+
+// decodeStrict decodes a row body, rejecting fields the request doesn't have
+func decodeStrict(body []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
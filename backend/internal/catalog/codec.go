@@ -0,0 +1,278 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// columnKind says how a CSV cell maps to a JSON value
+type columnKind int
+
+const (
+	kindText columnKind = iota
+	kindInt
+	kindBool
+	kindList // Values separated by listSeparator
+)
+
+// listSeparator joins list values in a single CSV cell
+const listSeparator = ";"
+
+// column is one field of a collection's import and export files
+type column struct {
+	name string
+	kind columnKind
+}
+
+// record is one row of an import file as JSON values keyed by column. A row
+// that couldn't be read has err set instead.
+type record struct {
+	fields map[string]interface{}
+	err    error
+}
+
+// decodeRecords reads an import file in the given format
+func decodeRecords(format string, columns []column, body io.Reader) ([]record, error) {
+	switch format {
+	case FormatCSV:
+		return decodeCSV(columns, body)
+	case FormatJSON:
+		return decodeJSON(body)
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", ErrInvalidFile, format)
+	}
+}
+
+// decodeCSV reads a CSV file with a header row. Columns may appear in any order
+// and may be left out; blank cells are treated as missing.
+func decodeCSV(columns []column, body io.Reader) ([]record, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: missing header row", ErrInvalidFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+
+	kinds := make(map[string]columnKind, len(columns))
+	for _, col := range columns {
+		kinds[col.name] = col.kind
+	}
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if _, ok := kinds[name]; !ok {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidFile, name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: duplicate column %q", ErrInvalidFile, name)
+		}
+		seen[name] = true
+		header[i] = name
+	}
+	if !seen["external_ref"] {
+		return nil, fmt.Errorf("%w: missing external_ref column", ErrInvalidFile)
+	}
+
+	var records []record
+	for {
+		cells, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if len(records) == MaxImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidFile, MaxImportRows)
+		}
+		if err != nil {
+			// Quoting errors only affect the row they occur on
+			if _, ok := err.(*csv.ParseError); !ok {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+			}
+			records = append(records, record{err: err})
+			continue
+		}
+		if len(cells) != len(header) {
+			records = append(records, record{err: fmt.Errorf("expected %d columns, got %d", len(header), len(cells))})
+			continue
+		}
+
+		records = append(records, parseCells(header, kinds, cells))
+	}
+}
+
+// parseCells converts the cells of one CSV row to JSON values
+func parseCells(header []string, kinds map[string]columnKind, cells []string) record {
+	fields := make(map[string]interface{}, len(cells))
+	for i, cell := range cells {
+		cell = strings.TrimSpace(cell)
+		if cell == "" {
+			continue
+		}
+
+		name := header[i]
+		switch kinds[name] {
+		case kindInt:
+			n, err := strconv.Atoi(cell)
+			if err != nil {
+				return record{err: fmt.Errorf("%s must be a whole number", name)}
+			}
+			fields[name] = n
+		case kindBool:
+			b, err := strconv.ParseBool(cell)
+			if err != nil {
+				return record{err: fmt.Errorf("%s must be true or false", name)}
+			}
+			fields[name] = b
+		case kindList:
+			var values []string
+			for _, value := range strings.Split(cell, listSeparator) {
+				if value = strings.TrimSpace(value); value != "" {
+					values = append(values, value)
+				}
+			}
+			fields[name] = values
+		default:
+			fields[name] = cell
+		}
+	}
+
+	return record{fields: fields}
+}
+
+// decodeJSON reads a JSON array of objects keyed by column
+func decodeJSON(body io.Reader) ([]record, error) {
+	var elements []json.RawMessage
+	if err := json.NewDecoder(body).Decode(&elements); err != nil {
+		return nil, fmt.Errorf("%w: expected a JSON array: %w", ErrInvalidFile, err)
+	}
+	if len(elements) > MaxImportRows {
+		return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidFile, MaxImportRows)
+	}
+
+	records := make([]record, len(elements))
+	for i, element := range elements {
+		var fields map[string]interface{}
+		if err := json.Unmarshal(element, &fields); err != nil || fields == nil {
+			records[i] = record{err: fmt.Errorf("expected a JSON object")}
+			continue
+		}
+		records[i] = record{fields: fields}
+	}
+
+	return records, nil
+}
+
+// encodeItems writes items in the given format, keeping only the collection's
+// columns. refs supplies each item's external_ref by ID.
+func encodeItems(format string, columns []column, items []interface{}, refs map[string]string, w io.Writer) error {
+	rows := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		row, err := itemFields(item)
+		if err != nil {
+			return err
+		}
+
+		id, _ := row["id"].(string)
+		out := make(map[string]interface{}, len(columns))
+		for _, col := range columns {
+			if value, ok := row[col.name]; ok && value != nil {
+				out[col.name] = value
+			}
+		}
+		out["external_ref"] = exportRef(refs, id)
+		rows = append(rows, out)
+	}
+
+	switch format {
+	case FormatCSV:
+		return encodeCSV(columns, rows, w)
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(rows)
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// encodeCSV writes rows under a header of every column
+func encodeCSV(columns []column, rows []map[string]interface{}, w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.name
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	cells := make([]string, len(columns))
+	for _, row := range rows {
+		for i, col := range columns {
+			cells[i] = formatCell(row[col.name])
+		}
+		if err := writer.Write(cells); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// Helper functions
+
+// itemFields converts a catalog model to JSON values keyed by field name
+func itemFields(item interface{}) (map[string]interface{}, error) {
+	body, err := json.Marshal(item)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode item: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("failed to encode item: %w", err)
+	}
+
+	return fields, nil
+}
+
+// exportRef returns the external_ref for an item. Items that were never
+// imported are exported under their ID, which import also recognises.
+func exportRef(refs map[string]string, itemID string) string {
+	if ref, ok := refs[itemID]; ok {
+		return ref
+	}
+	return itemID
+}
+
+// formatCell renders a JSON value as a CSV cell
+func formatCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []interface{}:
+		values := make([]string, len(v))
+		for i, item := range v {
+			values[i] = formatCell(item)
+		}
+		return strings.Join(values, listSeparator)
+	default:
+		return fmt.Sprint(v)
+	}
+}
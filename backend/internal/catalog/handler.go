@@ -0,0 +1,115 @@
+package catalog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+)
+
+// maxImportBytes caps the size of an uploaded import file
+const maxImportBytes = 10 << 20
+
+type handler struct {
+	service Service
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// ImportCatalog creates or updates catalog items from a CSV or JSON file sent as
+// the request body (admin only). With dry_run=true the report is produced
+// without writing anything.
+func (h *handler) ImportCatalog(c echo.Context) error {
+	format := c.QueryParam("format")
+	if format == "" {
+		format = formatFromContentType(c.Request().Header.Get(echo.HeaderContentType))
+	}
+
+	dryRun := false
+	if d := c.QueryParam("dry_run"); d != "" {
+		parsed, err := strconv.ParseBool(d)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "dry_run must be true or false",
+			})
+		}
+		dryRun = parsed
+	}
+
+	body := http.MaxBytesReader(c.Response(), c.Request().Body, maxImportBytes)
+	report, err := h.service.Import(c.Request().Context(), organisations.ScopeFromContext(c), collectionParam(c), format, dryRun, body)
+	if err != nil {
+		return c.JSON(statusForError(err), map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
+// ExportCatalog downloads every active item in a collection as CSV or JSON (admin only)
+func (h *handler) ExportCatalog(c echo.Context) error {
+	format := c.QueryParam("format")
+	if format == "" {
+		format = FormatJSON
+	}
+	collection := collectionParam(c)
+
+	// Buffered so a failure part way through still gets an error status
+	var buf bytes.Buffer
+	if err := h.service.Export(c.Request().Context(), collection, format, &buf); err != nil {
+		return c.JSON(statusForError(err), map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	contentType := echo.MIMEApplicationJSONCharsetUTF8
+	if format == FormatCSV {
+		contentType = "text/csv; charset=utf-8"
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", collection+"."+format))
+
+	return c.Blob(http.StatusOK, contentType, buf.Bytes())
+}
+
+// Helper functions
+
+// collectionParam reads the collection from the path, where support groups are
+// written support-groups as in the rest of the API
+func collectionParam(c echo.Context) string {
+	return strings.ReplaceAll(c.Param("collection"), "-", "_")
+}
+
+// formatFromContentType picks the import format when none is given in the query
+func formatFromContentType(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "text/csv" {
+		return FormatCSV
+	}
+	return FormatJSON
+}
+
+// statusForError maps import and export errors to HTTP statuses
+func statusForError(err error) int {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.Is(err, ErrUnknownCollection):
+		return http.StatusNotFound
+	case errors.As(err, &tooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrInvalidFile):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,30 @@
+package catalog
+
+import (
+	"context"
+	"io"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+)
+
+// Service defines the interface for bulk catalog import and export
+type Service interface {
+	// Import creates or updates one item per row, matching rows to items by
+	// external_ref. Rows that fail are reported and don't stop the others.
+	Import(ctx context.Context, scope organisations.Scope, collection, format string, dryRun bool, body io.Reader) (*ImportReport, error)
+	// Export writes every active item in the collection in a form Import accepts
+	Export(ctx context.Context, collection, format string, w io.Writer) error
+}
+
+// Store defines the interface for external reference persistence
+type Store interface {
+	ListExternalRefs(ctx context.Context, collection string) ([]ExternalRef, error)
+	SaveExternalRef(ctx context.Context, collection, externalRef, itemID string) error
+}
+
+// Handler defines the interface for catalog import and export HTTP handlers
+type Handler interface {
+	ImportCatalog(c echo.Context) error
+	ExportCatalog(c echo.Context) error
+}
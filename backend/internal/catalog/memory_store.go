@@ -0,0 +1,64 @@
+package catalog
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// memoryStore keeps external references in memory for tests and demo mode
+type memoryStore struct {
+	mu   sync.Mutex
+	refs map[string]map[string]ExternalRef // collection -> external_ref -> ref
+}
+
+func NewMemoryStore() Store {
+	return &memoryStore{
+		refs: make(map[string]map[string]ExternalRef),
+	}
+}
+
+// ListExternalRefs retrieves every external reference recorded for a collection
+func (s *memoryStore) ListExternalRefs(ctx context.Context, collection string) ([]ExternalRef, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	refs := make([]ExternalRef, 0, len(s.refs[collection]))
+	for _, ref := range s.refs[collection] {
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].ExternalRef < refs[j].ExternalRef })
+
+	return refs, nil
+}
+
+// SaveExternalRef points an external reference at an item, replacing any
+// reference the item had before
+func (s *memoryStore) SaveExternalRef(ctx context.Context, collection, externalRef, itemID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	refs, ok := s.refs[collection]
+	if !ok {
+		refs = make(map[string]ExternalRef)
+		s.refs[collection] = refs
+	}
+
+	for key, ref := range refs {
+		if ref.ItemID == itemID && key != externalRef {
+			delete(refs, key)
+		}
+	}
+
+	now := time.Now()
+	ref, ok := refs[externalRef]
+	if !ok {
+		ref = ExternalRef{Collection: collection, ExternalRef: externalRef, CreatedAt: now}
+	}
+	ref.ItemID = itemID
+	ref.UpdatedAt = now
+	refs[externalRef] = ref
+
+	return nil
+}
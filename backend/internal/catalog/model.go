@@ -0,0 +1,70 @@
+package catalog
+
+import (
+	"errors"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/httpcache"
+)
+
+// Collections that can be imported and exported, named as in catalog_versions
+const (
+	CollectionServices      = httpcache.CollectionServices
+	CollectionResources     = httpcache.CollectionResources
+	CollectionSupportGroups = httpcache.CollectionSupportGroups
+)
+
+// File formats accepted by import and produced by export
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// Row outcomes in an import report
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionError  = "error"
+)
+
+// MaxImportRows caps the number of rows in one import file
+const MaxImportRows = 5000
+
+var (
+	// ErrUnknownCollection is returned for a collection that can't be imported or exported
+	ErrUnknownCollection = errors.New("unknown catalog collection")
+	// ErrInvalidFile is returned when an import file can't be read as a whole.
+	// Problems with individual rows are reported per row instead.
+	ErrInvalidFile = errors.New("invalid import file")
+)
+
+// ExternalRef links the reference a partner uses for a catalog item to its ID
+type ExternalRef struct {
+	Collection  string    `json:"collection" db:"collection"`
+	ExternalRef string    `json:"external_ref" db:"external_ref"`
+	ItemID      string    `json:"item_id" db:"item_id"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ImportReport describes what an import did, or would do on a dry run
+type ImportReport struct {
+	Collection string      `json:"collection"`
+	Format     string      `json:"format"`
+	DryRun     bool        `json:"dry_run"`
+	Total      int         `json:"total"`
+	Created    int         `json:"created"`
+	Updated    int         `json:"updated"`
+	Failed     int         `json:"failed"`
+	Rows       []RowResult `json:"rows"`
+}
+
+// RowResult is the outcome for one row of an import file
+type RowResult struct {
+	// Row is the 1-based position of the row in the file, not counting the CSV header
+	Row         int     `json:"row"`
+	ExternalRef string  `json:"external_ref,omitempty"`
+	Action      string  `json:"action"`
+	ItemID      *string `json:"item_id,omitempty"`
+	Error       *string `json:"error,omitempty"`
+}
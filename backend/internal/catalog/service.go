@@ -0,0 +1,260 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/httpcache"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/resources"
+	"github.com/perinatal-mental-health-app/backend/internal/services"
+	"github.com/perinatal-mental-health-app/backend/internal/support_groups"
+	"go.uber.org/zap"
+)
+
+type service struct {
+	store       Store
+	versions    httpcache.Store
+	collections map[string]collection
+}
+
+func NewService(store Store, versions httpcache.Store, servicesService services.Service, resourcesService resources.Service, supportGroupsService support_groups.Service) Service {
+	return &service{
+		store:    store,
+		versions: versions,
+		collections: map[string]collection{
+			CollectionServices:      servicesCollection(servicesService),
+			CollectionResources:     resourcesCollection(resourcesService),
+			CollectionSupportGroups: supportGroupsCollection(supportGroupsService),
+		},
+	}
+}
+
+// Import creates or updates one item per row of the file. Each row is validated
+// and written on its own, so a bad row is reported without holding back the rest.
+// On a dry run every row is validated and matched but nothing is written.
+func (s *service) Import(ctx context.Context, scope organisations.Scope, name, format string, dryRun bool, body io.Reader) (*ImportReport, error) {
+	coll, ok := s.collections[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCollection, name)
+	}
+
+	records, err := decodeRecords(format, coll.columns, body)
+	if err != nil {
+		return nil, err
+	}
+
+	itemIDs, err := s.itemIDsByRef(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Refs not yet recorded may be item IDs from an export of unimported items
+	var existing map[string]bool
+
+	report := &ImportReport{
+		Collection: name,
+		Format:     format,
+		DryRun:     dryRun,
+		Total:      len(records),
+		Rows:       make([]RowResult, 0, len(records)),
+	}
+	firstRow := make(map[string]int, len(records))
+
+	for i, rec := range records {
+		result := RowResult{Row: i + 1, Action: ActionError}
+
+		ref, rowBody, err := rowRequest(rec)
+		result.ExternalRef = ref
+		if err == nil {
+			if first, duplicate := firstRow[ref]; duplicate {
+				err = fmt.Errorf("external_ref %q already used on row %d", ref, first)
+			} else {
+				firstRow[ref] = result.Row
+				err = coll.validate(scope, rowBody)
+			}
+		}
+
+		itemID, known := itemIDs[ref]
+		if err == nil && !known {
+			if existing == nil {
+				if existing, err = s.activeItemIDs(ctx, coll); err != nil {
+					return nil, err
+				}
+			}
+			if existing[ref] {
+				itemID = ref
+			}
+		}
+
+		action := ActionCreate
+		if itemID != "" {
+			action = ActionUpdate
+		}
+		if err == nil && !dryRun {
+			itemID, err = s.applyRow(ctx, scope, name, coll, ref, itemID, known, rowBody)
+		}
+
+		switch {
+		case err != nil:
+			message := strings.TrimPrefix(err.Error(), "json: ")
+			result.Error = &message
+			report.Failed++
+		case action == ActionCreate:
+			report.Created++
+		default:
+			report.Updated++
+		}
+		if err == nil {
+			result.Action = action
+			if itemID != "" {
+				result.ItemID = &itemID
+			}
+		}
+
+		report.Rows = append(report.Rows, result)
+	}
+
+	if !dryRun && report.Created+report.Updated > 0 {
+		s.bumpVersion(name)
+	}
+
+	return report, nil
+}
+
+// Export writes every active item in the collection in a form Import accepts
+func (s *service) Export(ctx context.Context, name, format string, w io.Writer) error {
+	coll, ok := s.collections[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownCollection, name)
+	}
+	if format != FormatCSV && format != FormatJSON {
+		return fmt.Errorf("%w: unsupported format %q", ErrInvalidFile, format)
+	}
+
+	items, err := coll.listAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", name, err)
+	}
+
+	refs, err := s.store.ListExternalRefs(ctx, name)
+	if err != nil {
+		return err
+	}
+	refsByItem := make(map[string]string, len(refs))
+	for _, ref := range refs {
+		refsByItem[ref.ItemID] = ref.ExternalRef
+	}
+
+	return encodeItems(format, coll.columns, items, refsByItem, w)
+}
+
+// Helper functions
+
+// applyRow writes one validated row and returns the item's ID. A created item,
+// or an existing item first matched by ID, has the row's external_ref recorded
+// against it.
+func (s *service) applyRow(ctx context.Context, scope organisations.Scope, name string, coll collection, ref, itemID string, known bool, body []byte) (string, error) {
+	if itemID != "" {
+		if err := coll.update(ctx, scope, itemID, body); err != nil {
+			return "", err
+		}
+		if !known {
+			return itemID, s.store.SaveExternalRef(ctx, name, ref, itemID)
+		}
+		return itemID, nil
+	}
+
+	createdID, err := coll.create(ctx, scope, body)
+	if err != nil {
+		return "", err
+	}
+	if err := s.store.SaveExternalRef(ctx, name, ref, createdID); err != nil {
+		return "", fmt.Errorf("created item %s but failed to record external_ref: %w", createdID, err)
+	}
+
+	return createdID, nil
+}
+
+// itemIDsByRef maps each recorded external_ref in a collection to its item
+func (s *service) itemIDsByRef(ctx context.Context, name string) (map[string]string, error) {
+	refs, err := s.store.ListExternalRefs(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	itemIDs := make(map[string]string, len(refs))
+	for _, ref := range refs {
+		itemIDs[ref.ExternalRef] = ref.ItemID
+	}
+
+	return itemIDs, nil
+}
+
+// activeItemIDs returns the IDs of every active item in a collection
+func (s *service) activeItemIDs(ctx context.Context, coll collection) (map[string]bool, error) {
+	items, err := coll.listAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list existing items: %w", err)
+	}
+
+	ids := make(map[string]bool, len(items))
+	for _, item := range items {
+		fields, err := itemFields(item)
+		if err != nil {
+			return nil, err
+		}
+		if id, ok := fields["id"].(string); ok {
+			ids[id] = true
+		}
+	}
+
+	return ids, nil
+}
+
+// bumpVersion invalidates cached catalog responses after an import wrote something
+func (s *service) bumpVersion(name string) {
+	// The rows have already been written, so don't tie the bump to the request context
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := s.versions.BumpVersion(ctx, name); err != nil {
+		logger.Error("Failed to bump catalog version", zap.String("collection", name), zap.Error(err))
+	}
+}
+
+// rowRequest splits a record into its external_ref and the JSON body of a
+// create or update request
+func rowRequest(rec record) (string, []byte, error) {
+	if rec.err != nil {
+		return "", nil, rec.err
+	}
+
+	ref, _ := rec.fields["external_ref"].(string)
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", nil, fmt.Errorf("external_ref is required")
+	}
+	if len(ref) > 255 {
+		return ref, nil, fmt.Errorf("external_ref cannot exceed 255 characters")
+	}
+
+	fields := make(map[string]interface{}, len(rec.fields))
+	for key, value := range rec.fields {
+		if key != "external_ref" {
+			fields[key] = value
+		}
+	}
+
+	body, err := json.Marshal(fields)
+	if err != nil {
+		return ref, nil, fmt.Errorf("failed to encode row: %w", err)
+	}
+
+	return ref, body, nil
+}
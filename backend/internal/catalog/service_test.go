@@ -0,0 +1,193 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/perinatal-mental-health-app/backend/internal/httpcache"
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/resources"
+	"github.com/perinatal-mental-health-app/backend/internal/services"
+	"github.com/perinatal-mental-health-app/backend/internal/support_groups"
+)
+
+var allScope = organisations.Scope{All: true}
+
+func newTestService() (Service, resources.Service, httpcache.Store) {
+	db := memdb.New()
+	versions := httpcache.NewMemoryStore()
+	resourcesService := resources.NewService(resources.NewMemoryStore(db))
+	svc := NewService(
+		NewMemoryStore(),
+		versions,
+		services.NewService(services.NewMemoryStore(db)),
+		resourcesService,
+		support_groups.NewService(support_groups.NewMemoryStore(db)),
+	)
+	return svc, resourcesService, versions
+}
+
+const resourcesCSV = `external_ref,title,description,content,resource_type,target_audience,tags,estimated_read_time
+res-1,Sleep and low mood,How sleep affects mood,Full article,article,new_mothers,sleep; mood,5
+res-2,Partner guide,Supporting a partner,Full article,article,partners,,
+res-3,Broken,Missing content,,article,general,,
+res-1,Duplicate,Same ref again,Full article,article,general,,
+res-4,Bad time,Read time isn't a number,Full article,article,general,,soon
+`
+
+func TestImportDryRun(t *testing.T) {
+	ctx := context.Background()
+	svc, resourcesService, versions := newTestService()
+
+	report, err := svc.Import(ctx, allScope, CollectionResources, FormatCSV, true, strings.NewReader(resourcesCSV))
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	if report.Total != 5 || report.Created != 2 || report.Updated != 0 || report.Failed != 3 {
+		t.Errorf("Import() totals = %d/%d/%d/%d, want 5 rows, 2 created, 0 updated, 3 failed", report.Total, report.Created, report.Updated, report.Failed)
+	}
+
+	wantErrors := map[int]string{
+		3: "content is required",
+		4: "already used on row 1",
+		5: "whole number",
+	}
+	for _, row := range report.Rows {
+		want, failed := wantErrors[row.Row]
+		if !failed {
+			if row.Action != ActionCreate || row.Error != nil {
+				t.Errorf("row %d = %s %v, want create", row.Row, row.Action, row.Error)
+			}
+			continue
+		}
+		if row.Action != ActionError || row.Error == nil || !strings.Contains(*row.Error, want) {
+			t.Errorf("row %d error = %v, want %q", row.Row, row.Error, want)
+		}
+	}
+
+	listed, err := resourcesService.ListResources(ctx, &resources.ListResourcesRequest{Page: 1, PageSize: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed.Resources) != 0 {
+		t.Errorf("dry run created %d resources", len(listed.Resources))
+	}
+	if version, _ := versions.GetVersion(ctx, CollectionResources); version.Version != 1 {
+		t.Errorf("dry run bumped the catalog version to %d", version.Version)
+	}
+}
+
+func TestImportUpsertsByExternalRef(t *testing.T) {
+	ctx := context.Background()
+	svc, resourcesService, versions := newTestService()
+
+	first := `[
+		{"external_ref": "res-1", "title": "Sleep and low mood", "description": "How sleep affects mood", "content": "Full article", "resource_type": "article", "target_audience": "new_mothers", "author": "Perinatal team"},
+		{"external_ref": "res-2", "title": "Partner guide", "description": "Supporting a partner", "content": "Full article", "resource_type": "article", "target_audience": "partners"}
+	]`
+	report, err := svc.Import(ctx, allScope, CollectionResources, FormatJSON, false, strings.NewReader(first))
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if report.Created != 2 || report.Failed != 0 {
+		t.Fatalf("first Import() = %+v, want 2 created", report)
+	}
+
+	// res-1 leaves author blank, which keeps the existing value
+	second := `external_ref,title,description,content,resource_type,target_audience,author
+res-1,Sleep and your mood,How sleep affects mood,Full article,article,new_mothers,
+res-3,Feeding support,Where to get help,Full article,article,new_mothers,
+`
+	report, err = svc.Import(ctx, allScope, CollectionResources, FormatCSV, false, strings.NewReader(second))
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if report.Created != 1 || report.Updated != 1 || report.Failed != 0 {
+		t.Fatalf("second Import() = %+v, want 1 created, 1 updated", report)
+	}
+
+	updated, err := resourcesService.GetResource(ctx, *report.Rows[0].ItemID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Title != "Sleep and your mood" || updated.Author == nil || *updated.Author != "Perinatal team" {
+		t.Errorf("updated resource = %q by %v, want new title and the original author", updated.Title, updated.Author)
+	}
+
+	listed, err := resourcesService.ListResources(ctx, &resources.ListResourcesRequest{Page: 1, PageSize: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed.Resources) != 3 {
+		t.Errorf("ListResources() = %d resources, want 3", len(listed.Resources))
+	}
+	if version, _ := versions.GetVersion(ctx, CollectionResources); version.Version != 3 {
+		t.Errorf("catalog version = %d, want 3 after two imports", version.Version)
+	}
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestService()
+
+	// Exported rows keep their external_ref, so importing an export updates in place
+	rows := `external_ref,name,description,provider_name,contact_email,service_type
+svc-1,Talking Therapies,Counselling for new parents,NHS Trust,help@example.nhs.uk,in_person
+`
+	if _, err := svc.Import(ctx, allScope, CollectionServices, FormatCSV, false, strings.NewReader(rows)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range []string{FormatCSV, FormatJSON} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := svc.Export(ctx, CollectionServices, format, &buf); err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+			if !strings.Contains(buf.String(), "svc-1") || !strings.Contains(buf.String(), "Talking Therapies") {
+				t.Errorf("Export() = %s, want svc-1", buf.String())
+			}
+
+			report, err := svc.Import(ctx, allScope, CollectionServices, format, true, &buf)
+			if err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+			if report.Total != 1 || report.Updated != 1 {
+				t.Errorf("re-import = %+v, want the exported row to update", report)
+			}
+		})
+	}
+}
+
+func TestImportRejectsInvalidFiles(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestService()
+
+	tests := []struct {
+		name       string
+		collection string
+		format     string
+		body       string
+		wantErr    error
+	}{
+		{"unknown collection", "referrals", FormatJSON, `[]`, ErrUnknownCollection},
+		{"unknown format", CollectionResources, "xml", `<resources/>`, ErrInvalidFile},
+		{"unknown column", CollectionResources, FormatCSV, "external_ref,colour\nres-1,red\n", ErrInvalidFile},
+		{"missing external_ref column", CollectionResources, FormatCSV, "title\nSleep\n", ErrInvalidFile},
+		{"not an array", CollectionResources, FormatJSON, `{"external_ref": "res-1"}`, ErrInvalidFile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Import(ctx, allScope, tt.collection, tt.format, true, strings.NewReader(tt.body))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Import() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
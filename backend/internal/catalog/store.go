@@ -0,0 +1,77 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type store struct {
+	db *pgxpool.Pool
+}
+
+func NewStore(db *pgxpool.Pool) Store {
+	return &store{
+		db: db,
+	}
+}
+
+// ListExternalRefs retrieves every external reference recorded for a collection
+func (s *store) ListExternalRefs(ctx context.Context, collection string) ([]ExternalRef, error) {
+	query := `
+		SELECT collection, external_ref, item_id, created_at, updated_at
+		FROM catalog_external_refs
+		WHERE collection = $1
+		ORDER BY external_ref
+	`
+
+	rows, err := s.db.Query(ctx, query, collection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list external refs: %w", err)
+	}
+	defer rows.Close()
+
+	var refs []ExternalRef
+	for rows.Next() {
+		var ref ExternalRef
+		if err := rows.Scan(&ref.Collection, &ref.ExternalRef, &ref.ItemID, &ref.CreatedAt, &ref.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan external ref: %w", err)
+		}
+		refs = append(refs, ref)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate external refs: %w", err)
+	}
+
+	return refs, nil
+}
+
+// SaveExternalRef points an external reference at an item, replacing any
+// reference the item had before
+func (s *store) SaveExternalRef(ctx context.Context, collection, externalRef, itemID string) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `DELETE FROM catalog_external_refs WHERE collection = $1 AND item_id = $2 AND external_ref <> $3`, collection, itemID, externalRef)
+	if err != nil {
+		return fmt.Errorf("failed to clear previous external ref: %w", err)
+	}
+
+	query := `
+		INSERT INTO catalog_external_refs (collection, external_ref, item_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (collection, external_ref) DO UPDATE
+		SET item_id = EXCLUDED.item_id, updated_at = CURRENT_TIMESTAMP
+	`
+
+	if _, err := tx.Exec(ctx, query, collection, externalRef, itemID); err != nil {
+		return fmt.Errorf("failed to save external ref: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
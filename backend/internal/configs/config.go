@@ -7,6 +7,7 @@ import (
 	"net"
 	"os"
 	"strconv"
+	"time"
 )
 
 type Config struct {
@@ -17,6 +18,43 @@ type Config struct {
 	DBName     string
 	DBSSLMode  string
 	JWTSecret  string
+
+	// Read replica. When DBReplicaURL is set, read-only catalog and stats queries
+	// run on the replica unless it is more than DBReplicaMaxLag behind the
+	// primary, which is checked every DBReplicaLagCheckInterval.
+	DBReplicaURL              string
+	DBReplicaMaxLag           time.Duration
+	DBReplicaLagCheckInterval time.Duration
+
+	// IdempotencyTTL is how long a stored Idempotency-Key response can be replayed
+	IdempotencyTTL time.Duration
+
+	// Field encryption. The master key (base64, 32 bytes) comes from the env or a
+	// key file; previous master keys are only used to unwrap data keys until they
+	// have been re-wrapped.
+	EncryptionMasterKey          string
+	EncryptionKeyFile            string
+	EncryptionPreviousMasterKeys string
+	EncryptionIndexKey           string
+	ReencryptionInterval         time.Duration
+
+	// Background jobs. JobQueues lists "queue:concurrency" pairs the worker
+	// polls; each limit caps the jobs running on that queue across the whole
+	// deployment, so every instance should use the same value. Set
+	// WORKER_ENABLED=false to run workers only via cmd/worker.
+	// The domain event dispatcher runs wherever the worker does.
+	WorkerEnabled     bool
+	JobQueues         string
+	JobPollInterval   time.Duration
+	EventPollInterval time.Duration
+
+	// Email is sent through the SMTP relay at MailSMTPHost. Without one, emails
+	// are logged without their contents and never delivered.
+	MailSMTPHost     string
+	MailSMTPPort     int
+	MailSMTPUsername string
+	MailSMTPPassword string
+	MailFrom         string
 }
 
 func Load() *Config {
@@ -34,6 +72,16 @@ func Load() *Config {
 	viper.AddConfigPath("./cfg")
 	viper.AutomaticEnv()
 
+	viper.SetDefault("DB_REPLICA_MAX_LAG", "5s")
+	viper.SetDefault("DB_REPLICA_LAG_CHECK_INTERVAL", "2s")
+	viper.SetDefault("IDEMPOTENCY_TTL", "24h")
+	viper.SetDefault("REENCRYPTION_INTERVAL", "1h")
+	viper.SetDefault("WORKER_ENABLED", true)
+	viper.SetDefault("JOB_QUEUES", "default:4,privacy:1,journey:2,webhooks:4")
+	viper.SetDefault("JOB_POLL_INTERVAL", "1s")
+	viper.SetDefault("EVENT_POLL_INTERVAL", "1s")
+	viper.SetDefault("MAIL_SMTP_PORT", 587)
+
 	if err := viper.ReadInConfig(); err != nil {
 		logger.Error(fmt.Sprintf("No config file found: ./cfg/%s.env. Using environment variables...", configFile))
 	} else {
@@ -48,6 +96,29 @@ func Load() *Config {
 		DBName:     viper.GetString("DB_NAME"),
 		DBSSLMode:  viper.GetString("DB_SSLMODE"),
 		JWTSecret:  viper.GetString("JWT_SECRET"),
+
+		DBReplicaURL:              viper.GetString("DB_REPLICA_URL"),
+		DBReplicaMaxLag:           viper.GetDuration("DB_REPLICA_MAX_LAG"),
+		DBReplicaLagCheckInterval: viper.GetDuration("DB_REPLICA_LAG_CHECK_INTERVAL"),
+
+		IdempotencyTTL: viper.GetDuration("IDEMPOTENCY_TTL"),
+
+		EncryptionMasterKey:          viper.GetString("ENCRYPTION_MASTER_KEY"),
+		EncryptionKeyFile:            viper.GetString("ENCRYPTION_KEY_FILE"),
+		EncryptionPreviousMasterKeys: viper.GetString("ENCRYPTION_PREVIOUS_MASTER_KEYS"),
+		EncryptionIndexKey:           viper.GetString("ENCRYPTION_INDEX_KEY"),
+		ReencryptionInterval:         viper.GetDuration("REENCRYPTION_INTERVAL"),
+
+		WorkerEnabled:     viper.GetBool("WORKER_ENABLED"),
+		JobQueues:         viper.GetString("JOB_QUEUES"),
+		JobPollInterval:   viper.GetDuration("JOB_POLL_INTERVAL"),
+		EventPollInterval: viper.GetDuration("EVENT_POLL_INTERVAL"),
+
+		MailSMTPHost:     viper.GetString("MAIL_SMTP_HOST"),
+		MailSMTPPort:     viper.GetInt("MAIL_SMTP_PORT"),
+		MailSMTPUsername: viper.GetString("MAIL_SMTP_USERNAME"),
+		MailSMTPPassword: viper.GetString("MAIL_SMTP_PASSWORD"),
+		MailFrom:         viper.GetString("MAIL_FROM"),
 	}
 }
 
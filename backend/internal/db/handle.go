@@ -0,0 +1,237 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/perinatal-mental-health-app/backend/internal/configs"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"go.uber.org/zap"
+)
+
+// Querier runs read-only queries; both the primary and replica pools satisfy it
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Handle wraps the primary pool and an optional read replica. Query, QueryRow,
+// Exec and Begin always use the primary; stores call Reader for read-only
+// queries that can tolerate replication lag.
+type Handle struct {
+	primary *pgxpool.Pool
+	replica *pgxpool.Pool
+	maxLag  time.Duration
+
+	// replicaReady is set while the replica is known to be within maxLag. It
+	// starts unset so reads use the primary until the first lag check passes.
+	replicaReady atomic.Bool
+}
+
+// NewHandle creates a handle over the primary pool. replica may be nil, in
+// which case every query runs on the primary.
+func NewHandle(primary, replica *pgxpool.Pool, maxLag time.Duration) *Handle {
+	return &Handle{
+		primary: primary,
+		replica: replica,
+		maxLag:  maxLag,
+	}
+}
+
+// InitReplica connects to the configured read replica. It returns nil when no
+// replica is configured or it can't be reached, so reads fall back to the primary.
+func InitReplica(cfg *config.Config) *pgxpool.Pool {
+	if cfg.DBReplicaURL == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	replica, err := pgxpool.New(ctx, cfg.DBReplicaURL)
+	if err != nil {
+		logger.Error("Failed to connect to PostgreSQL replica; reads will use the primary", zap.Error(err))
+		return nil
+	}
+
+	if err = replica.Ping(ctx); err != nil {
+		logger.Error("PostgreSQL replica ping failed; reads will use the primary", zap.Error(err))
+		replica.Close()
+		return nil
+	}
+
+	logger.Info("Connected to PostgreSQL replica")
+
+	return replica
+}
+
+// Primary returns the primary pool
+func (h *Handle) Primary() *pgxpool.Pool {
+	return h.primary
+}
+
+// HasReplica reports whether a read replica is configured
+func (h *Handle) HasReplica() bool {
+	return h.replica != nil
+}
+
+// Reader returns the pool a read-only query should run on: the replica when one
+// is configured and caught up, otherwise the primary. Contexts marked with
+// WithPrimary always read from the primary, and contexts from PinReader keep
+// the pool chosen when they were pinned.
+func (h *Handle) Reader(ctx context.Context) Querier {
+	if pinned, ok := ctx.Value(readerKey{}).(Querier); ok {
+		return pinned
+	}
+	if h.replica == nil || !h.replicaReady.Load() || UsesPrimary(ctx) {
+		return h.primary
+	}
+	return h.replica
+}
+
+// PinReader fixes the pool Reader returns for ctx to the one it would use now,
+// so every read in a request comes from the same database even if the replica
+// falls behind or catches up part way through
+func (h *Handle) PinReader(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readerKey{}, h.Reader(ctx))
+}
+
+// RecordWrite notes that the client just wrote, so any instance can send its
+// next reads to the primary
+func (h *Handle) RecordWrite(ctx context.Context, client string) error {
+	_, err := h.primary.Exec(ctx, `
+		INSERT INTO recent_writes (client_key, written_at)
+		VALUES ($1, NOW())
+		ON CONFLICT (client_key) DO UPDATE SET written_at = EXCLUDED.written_at
+	`, client)
+	if err != nil {
+		return fmt.Errorf("failed to record write: %w", err)
+	}
+
+	return nil
+}
+
+// WroteSince reports whether the client has written since the given time
+func (h *Handle) WroteSince(ctx context.Context, client string, since time.Time) (bool, error) {
+	var recent bool
+	err := h.primary.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM recent_writes WHERE client_key = $1 AND written_at > $2)
+	`, client, since).Scan(&recent)
+	if err != nil {
+		return false, fmt.Errorf("failed to check recent writes: %w", err)
+	}
+
+	return recent, nil
+}
+
+// PruneWrites forgets writes made before the cutoff
+func (h *Handle) PruneWrites(ctx context.Context, writtenBefore time.Time) error {
+	_, err := h.primary.Exec(ctx, `DELETE FROM recent_writes WHERE written_at < $1`, writtenBefore)
+	if err != nil {
+		return fmt.Errorf("failed to prune recent writes: %w", err)
+	}
+
+	return nil
+}
+
+func (h *Handle) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return h.primary.Query(ctx, sql, args...)
+}
+
+func (h *Handle) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return h.primary.QueryRow(ctx, sql, args...)
+}
+
+func (h *Handle) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return h.primary.Exec(ctx, sql, args...)
+}
+
+func (h *Handle) Begin(ctx context.Context) (pgx.Tx, error) {
+	return h.primary.Begin(ctx)
+}
+
+// MonitorLag checks the replica's replay lag every interval until ctx is
+// cancelled, sending reads to the primary while it is more than maxLag behind
+// or can't be queried
+func (h *Handle) MonitorLag(ctx context.Context, interval time.Duration) {
+	if h.replica == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		h.checkLag(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close closes the primary and replica pools
+func (h *Handle) Close() {
+	if h.replica != nil {
+		h.replica.Close()
+	}
+	h.primary.Close()
+}
+
+// Helper functions
+
+// replicaLagQuery returns how far behind the primary the replica is in seconds.
+// A replica that has replayed everything it received is treated as caught up,
+// since the last replay timestamp only moves when the primary commits.
+const replicaLagQuery = `
+	SELECT CASE
+		WHEN NOT pg_is_in_recovery() THEN 0
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END::float8
+`
+
+func (h *Handle) checkLag(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var seconds float64
+	err := h.replica.QueryRow(ctx, replicaLagQuery).Scan(&seconds)
+	lag := time.Duration(seconds * float64(time.Second))
+	ready := err == nil && lag <= h.maxLag
+
+	if h.replicaReady.Swap(ready) == ready || ctx.Err() == context.Canceled {
+		return
+	}
+	switch {
+	case ready:
+		logger.Info("PostgreSQL replica caught up; reads use the replica", zap.Duration("lag", lag))
+	case err != nil:
+		logger.Error("PostgreSQL replica lag check failed; reads use the primary", zap.Error(err))
+	default:
+		logger.Error("PostgreSQL replica is behind; reads use the primary", zap.Duration("lag", lag), zap.Duration("max_lag", h.maxLag))
+	}
+}
+
+type primaryKey struct{}
+
+type readerKey struct{}
+
+// WithPrimary marks ctx so that reads through Handle.Reader use the primary,
+// for requests that must see their own writes
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// UsesPrimary reports whether ctx was marked with WithPrimary
+func UsesPrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
+}
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// newPool opens a pool that connects lazily, so it never touches a database
+func newPool(t *testing.T, port string) *pgxpool.Pool {
+	t.Helper()
+	pool, err := pgxpool.New(context.Background(), "postgres://app@localhost:"+port+"/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestReader(t *testing.T) {
+	primary, replica := newPool(t, "5432"), newPool(t, "5433")
+
+	tests := []struct {
+		name    string
+		handle  *Handle
+		ready   bool
+		ctx     context.Context
+		replica bool
+	}{
+		{name: "no replica", handle: NewHandle(primary, nil, time.Second), ready: true, ctx: context.Background()},
+		{name: "replica not yet checked", handle: NewHandle(primary, replica, time.Second), ctx: context.Background()},
+		{name: "replica caught up", handle: NewHandle(primary, replica, time.Second), ready: true, ctx: context.Background(), replica: true},
+		{name: "request needs its own writes", handle: NewHandle(primary, replica, time.Second), ready: true, ctx: WithPrimary(context.Background())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.handle.replicaReady.Store(tt.ready)
+
+			want := Querier(primary)
+			if tt.replica {
+				want = replica
+			}
+			if got := tt.handle.Reader(tt.ctx); got != want {
+				t.Errorf("Reader() used the wrong pool, want replica = %v", tt.replica)
+			}
+		})
+	}
+}
+
+func TestPinReaderKeepsTheFirstChoice(t *testing.T) {
+	primary, replica := newPool(t, "5432"), newPool(t, "5433")
+	handle := NewHandle(primary, replica, time.Second)
+	handle.replicaReady.Store(true)
+
+	pinned := handle.PinReader(context.Background())
+
+	// The replica falling behind mid-request doesn't move reads already pinned to it
+	handle.replicaReady.Store(false)
+	if handle.Reader(pinned) != Querier(replica) {
+		t.Error("Reader() left the pinned replica")
+	}
+	if handle.Reader(context.Background()) != Querier(primary) {
+		t.Error("Reader() used the lagging replica for an unpinned request")
+	}
+}
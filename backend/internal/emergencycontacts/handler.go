@@ -0,0 +1,119 @@
+package emergencycontacts
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/careteam"
+)
+
+type handler struct {
+	service Service
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// ListContacts lists the current user's emergency contacts
+func (h *handler) ListContacts(c echo.Context) error {
+	contacts, err := h.service.ListContacts(c.Request().Context(), getUserIDFromContext(c))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, contacts)
+}
+
+// GetContact retrieves one of the current user's emergency contacts
+func (h *handler) GetContact(c echo.Context) error {
+	contact, err := h.service.GetContact(c.Request().Context(), getUserIDFromContext(c), c.Param("id"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, contact)
+}
+
+// CreateContact adds an emergency contact for the current user
+func (h *handler) CreateContact(c echo.Context) error {
+	var req CreateContactRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	contact, err := h.service.CreateContact(c.Request().Context(), getUserIDFromContext(c), &req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, contact)
+}
+
+// UpdateContact updates one of the current user's emergency contacts
+func (h *handler) UpdateContact(c echo.Context) error {
+	var req UpdateContactRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	contact, err := h.service.UpdateContact(c.Request().Context(), getUserIDFromContext(c), c.Param("id"), &req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, contact)
+}
+
+// DeleteContact removes one of the current user's emergency contacts
+func (h *handler) DeleteContact(c echo.Context) error {
+	if err := h.service.DeleteContact(c.Request().Context(), getUserIDFromContext(c), c.Param("id")); err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Emergency contact deleted successfully",
+	})
+}
+
+// ListForUser lists the contacts a service user lets their care team get in touch with
+func (h *handler) ListForUser(c echo.Context) error {
+	contacts, err := h.service.ListForCareTeam(c.Request().Context(), getUserIDFromContext(c), c.Param("id"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, contacts)
+}
+
+// Helper functions
+
+func getUserIDFromContext(c echo.Context) string {
+	if userID, ok := c.Get("user_id").(string); ok {
+		return userID
+	}
+	return ""
+}
+
+func errorResponse(c echo.Context, err error) error {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, careteam.ErrNoAccess):
+		status = http.StatusForbidden
+	case errors.Is(err, ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrTooManyContacts):
+		status = http.StatusConflict
+	}
+
+	return c.JSON(status, map[string]string{
+		"error": err.Error(),
+	})
+}
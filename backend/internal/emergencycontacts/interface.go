@@ -0,0 +1,46 @@
+package emergencycontacts
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Service defines the interface for emergency contact business logic
+type Service interface {
+	// Service users
+	ListContacts(ctx context.Context, userID string) (*ListContactsResponse, error)
+	GetContact(ctx context.Context, userID, contactID string) (*Contact, error)
+	CreateContact(ctx context.Context, userID string, req *CreateContactRequest) (*Contact, error)
+	UpdateContact(ctx context.Context, userID, contactID string, req *UpdateContactRequest) (*Contact, error)
+	DeleteContact(ctx context.Context, userID, contactID string) error
+
+	// ListForCareTeam lists the contacts a user has agreed their care team may
+	// get in touch with, for a caller with care-team or break-glass access
+	ListForCareTeam(ctx context.Context, actorID, userID string) (*ListContactsResponse, error)
+}
+
+// Store defines the interface for emergency contact data persistence
+type Store interface {
+	// ListContacts lists a user's contacts, oldest first
+	ListContacts(ctx context.Context, userID string) ([]Contact, error)
+	GetContact(ctx context.Context, userID, contactID string) (*Contact, error)
+	// CreateContact adds a contact, failing with ErrTooManyContacts if the user
+	// already has MaxContacts
+	CreateContact(ctx context.Context, contact *Contact) (*Contact, error)
+	UpdateContact(ctx context.Context, contact *Contact) (*Contact, error)
+	DeleteContact(ctx context.Context, userID, contactID string) error
+}
+
+// Handler defines the interface for emergency contact HTTP handlers
+type Handler interface {
+	// Service user methods
+	ListContacts(c echo.Context) error
+	GetContact(c echo.Context) error
+	CreateContact(c echo.Context) error
+	UpdateContact(c echo.Context) error
+	DeleteContact(c echo.Context) error
+
+	// Staff methods
+	ListForUser(c echo.Context) error
+}
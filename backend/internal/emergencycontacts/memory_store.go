@@ -0,0 +1,110 @@
+package emergencycontacts
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// memoryStore keeps emergency contacts in memory for tests and demo mode
+type memoryStore struct {
+	mu       sync.RWMutex
+	contacts map[string]Contact
+}
+
+func NewMemoryStore() Store {
+	return &memoryStore{
+		contacts: make(map[string]Contact),
+	}
+}
+
+// ListContacts lists a user's contacts, oldest first
+func (s *memoryStore) ListContacts(ctx context.Context, userID string) ([]Contact, error) {
+	s.mu.RLock()
+	contacts := []Contact{}
+	for _, contact := range s.contacts {
+		if contact.UserID == userID {
+			contacts = append(contacts, copyContact(contact))
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(contacts, func(i, j int) bool {
+		if contacts[i].CreatedAt.Equal(contacts[j].CreatedAt) {
+			return contacts[i].ID < contacts[j].ID
+		}
+		return contacts[i].CreatedAt.Before(contacts[j].CreatedAt)
+	})
+
+	return contacts, nil
+}
+
+// GetContact retrieves one of a user's contacts
+func (s *memoryStore) GetContact(ctx context.Context, userID, contactID string) (*Contact, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	contact, ok := s.contacts[contactID]
+	if !ok || contact.UserID != userID {
+		return nil, ErrNotFound
+	}
+
+	contact = copyContact(contact)
+	return &contact, nil
+}
+
+// CreateContact adds a contact, failing with ErrTooManyContacts if the user
+// already has MaxContacts
+func (s *memoryStore) CreateContact(ctx context.Context, contact *Contact) (*Contact, error) {
+	s.mu.Lock()
+	count := 0
+	for _, existing := range s.contacts {
+		if existing.UserID == contact.UserID {
+			count++
+		}
+	}
+	if count >= MaxContacts {
+		s.mu.Unlock()
+		return nil, ErrTooManyContacts
+	}
+	s.contacts[contact.ID] = copyContact(*contact)
+	s.mu.Unlock()
+
+	return s.GetContact(ctx, contact.UserID, contact.ID)
+}
+
+// UpdateContact saves every field of a contact
+func (s *memoryStore) UpdateContact(ctx context.Context, contact *Contact) (*Contact, error) {
+	s.mu.Lock()
+	existing, ok := s.contacts[contact.ID]
+	if !ok || existing.UserID != contact.UserID {
+		s.mu.Unlock()
+		return nil, ErrNotFound
+	}
+	s.contacts[contact.ID] = copyContact(*contact)
+	s.mu.Unlock()
+
+	return s.GetContact(ctx, contact.UserID, contact.ID)
+}
+
+// DeleteContact removes one of a user's contacts
+func (s *memoryStore) DeleteContact(ctx context.Context, userID, contactID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	contact, ok := s.contacts[contactID]
+	if !ok || contact.UserID != userID {
+		return ErrNotFound
+	}
+	delete(s.contacts, contactID)
+
+	return nil
+}
+
+// Helper functions
+
+// copyContact keeps callers from sharing the stored circumstances
+func copyContact(contact Contact) Contact {
+	contact.ContactCircumstances = append([]string{}, contact.ContactCircumstances...)
+	return contact
+}
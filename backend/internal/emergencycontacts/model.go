@@ -0,0 +1,87 @@
+// Package emergencycontacts keeps the people a service user can be reached
+// through, and whether their care team may contact each of them.
+package emergencycontacts
+
+import (
+	"errors"
+	"time"
+)
+
+// Ways an emergency contact prefers to be reached
+const (
+	ChannelPhone = "phone"
+	ChannelSMS   = "sms"
+	ChannelEmail = "email"
+)
+
+// Circumstances in which a service user lets their care team get in touch
+// with a contact
+const (
+	CircumstanceCrisis             = "crisis"              // Immediate concern for the service user's or baby's safety
+	CircumstanceUnreachable        = "unreachable"         // The service user can't be reached
+	CircumstanceMissedAppointments = "missed_appointments" // Appointments are repeatedly missed
+	CircumstanceHospitalAdmission  = "hospital_admission"  // The service user is admitted to hospital
+)
+
+// Circumstances lists the valid contact circumstances
+var Circumstances = []string{CircumstanceCrisis, CircumstanceUnreachable, CircumstanceMissedAppointments, CircumstanceHospitalAdmission}
+
+// MaxContacts bounds the number of emergency contacts one user can keep
+const MaxContacts = 5
+
+var (
+	// ErrNotFound is returned for contacts that don't exist or belong to someone else
+	ErrNotFound = errors.New("emergency contact not found")
+
+	// ErrTooManyContacts is returned when a user already has MaxContacts contacts
+	ErrTooManyContacts = errors.New("too many emergency contacts")
+)
+
+// Contact is one of a service user's emergency contacts
+type Contact struct {
+	ID               string  `json:"id" db:"id"`
+	UserID           string  `json:"user_id" db:"user_id"`
+	Name             string  `json:"name" db:"name" encrypt:"true"`
+	Relationship     string  `json:"relationship" db:"relationship" encrypt:"true"`
+	Phone            *string `json:"phone,omitempty" db:"phone" encrypt:"true"`
+	Email            *string `json:"email,omitempty" db:"email" encrypt:"true"`
+	PreferredChannel string  `json:"preferred_channel" db:"preferred_channel"`
+	// CareTeamMayContact records the service user's consent for their care
+	// team to get in touch with this person, limited to ContactCircumstances
+	CareTeamMayContact   bool       `json:"care_team_may_contact" db:"care_team_may_contact"`
+	ContactCircumstances []string   `json:"contact_circumstances" db:"contact_circumstances"`
+	ConsentNotes         *string    `json:"consent_notes,omitempty" db:"consent_notes" encrypt:"true"`
+	ConsentUpdatedAt     *time.Time `json:"consent_updated_at,omitempty" db:"consent_updated_at"`
+	CreatedAt            time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CreateContactRequest represents the request to add an emergency contact
+type CreateContactRequest struct {
+	Name                 string   `json:"name" validate:"required,min=1,max=255"`
+	Relationship         string   `json:"relationship" validate:"required,min=1,max=100"`
+	Phone                *string  `json:"phone,omitempty" validate:"omitempty,max=32"`
+	Email                *string  `json:"email,omitempty" validate:"omitempty,email"`
+	PreferredChannel     string   `json:"preferred_channel" validate:"required,oneof=phone sms email"`
+	CareTeamMayContact   bool     `json:"care_team_may_contact"`
+	ContactCircumstances []string `json:"contact_circumstances,omitempty" validate:"omitempty,dive,oneof=crisis unreachable missed_appointments hospital_admission"`
+	ConsentNotes         *string  `json:"consent_notes,omitempty" validate:"omitempty,max=1000"`
+}
+
+// UpdateContactRequest represents the request to update an emergency contact.
+// An empty string clears the phone, email or consent notes.
+type UpdateContactRequest struct {
+	Name                 *string  `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	Relationship         *string  `json:"relationship,omitempty" validate:"omitempty,min=1,max=100"`
+	Phone                *string  `json:"phone,omitempty" validate:"omitempty,max=32"`
+	Email                *string  `json:"email,omitempty" validate:"omitempty,email"`
+	PreferredChannel     *string  `json:"preferred_channel,omitempty" validate:"omitempty,oneof=phone sms email"`
+	CareTeamMayContact   *bool    `json:"care_team_may_contact,omitempty"`
+	ContactCircumstances []string `json:"contact_circumstances,omitempty" validate:"omitempty,dive,oneof=crisis unreachable missed_appointments hospital_admission"`
+	ConsentNotes         *string  `json:"consent_notes,omitempty" validate:"omitempty,max=1000"`
+}
+
+// ListContactsResponse represents a list of emergency contacts
+type ListContactsResponse struct {
+	Contacts []Contact `json:"contacts"`
+}
@@ -0,0 +1,240 @@
+package emergencycontacts
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/perinatal-mental-health-app/backend/internal/careteam"
+	"github.com/perinatal-mental-health-app/backend/internal/encryption"
+)
+
+type service struct {
+	store    Store
+	careTeam careteam.AccessChecker
+}
+
+func NewService(store Store, careTeam careteam.AccessChecker) Service {
+	return &service{
+		store:    store,
+		careTeam: careTeam,
+	}
+}
+
+// ListContacts lists the user's emergency contacts
+func (s *service) ListContacts(ctx context.Context, userID string) (*ListContactsResponse, error) {
+	contacts, err := s.store.ListContacts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListContactsResponse{Contacts: contacts}, nil
+}
+
+// GetContact retrieves one of the user's emergency contacts
+func (s *service) GetContact(ctx context.Context, userID, contactID string) (*Contact, error) {
+	return s.store.GetContact(ctx, userID, contactID)
+}
+
+// CreateContact adds an emergency contact. The consent choice is recorded with
+// the time it was made, even when the care team may not get in touch.
+func (s *service) CreateContact(ctx context.Context, userID string, req *CreateContactRequest) (*Contact, error) {
+	now := time.Now()
+	contact := &Contact{
+		ID:                   uuid.New().String(),
+		UserID:               userID,
+		Name:                 strings.TrimSpace(req.Name),
+		Relationship:         strings.TrimSpace(req.Relationship),
+		Phone:                trimOptional(req.Phone),
+		Email:                trimOptional(req.Email),
+		PreferredChannel:     req.PreferredChannel,
+		CareTeamMayContact:   req.CareTeamMayContact,
+		ContactCircumstances: uniqueCircumstances(req.ContactCircumstances),
+		ConsentNotes:         trimOptional(req.ConsentNotes),
+		ConsentUpdatedAt:     &now,
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}
+	if err := validateContact(contact); err != nil {
+		return nil, err
+	}
+
+	return s.store.CreateContact(ctx, contact)
+}
+
+// UpdateContact changes an emergency contact. Changing the consent flag or
+// circumstances records when the choice was made.
+func (s *service) UpdateContact(ctx context.Context, userID, contactID string, req *UpdateContactRequest) (*Contact, error) {
+	contact, err := s.store.GetContact(ctx, userID, contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		contact.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Relationship != nil {
+		contact.Relationship = strings.TrimSpace(*req.Relationship)
+	}
+	if req.Phone != nil {
+		contact.Phone = trimOptional(req.Phone)
+	}
+	if req.Email != nil {
+		contact.Email = trimOptional(req.Email)
+	}
+	if req.PreferredChannel != nil {
+		contact.PreferredChannel = *req.PreferredChannel
+	}
+	if req.ConsentNotes != nil {
+		contact.ConsentNotes = trimOptional(req.ConsentNotes)
+	}
+
+	consentChanged := false
+	if req.CareTeamMayContact != nil && *req.CareTeamMayContact != contact.CareTeamMayContact {
+		contact.CareTeamMayContact = *req.CareTeamMayContact
+		consentChanged = true
+	}
+	if req.ContactCircumstances != nil {
+		circumstances := uniqueCircumstances(req.ContactCircumstances)
+		if !slices.Equal(circumstances, contact.ContactCircumstances) {
+			contact.ContactCircumstances = circumstances
+			consentChanged = true
+		}
+	}
+	// Withdrawing consent withdraws it for every circumstance
+	if !contact.CareTeamMayContact && req.ContactCircumstances == nil {
+		contact.ContactCircumstances = []string{}
+	}
+
+	now := time.Now()
+	if consentChanged {
+		contact.ConsentUpdatedAt = &now
+	}
+	contact.UpdatedAt = now
+
+	if err := validateContact(contact); err != nil {
+		return nil, err
+	}
+
+	return s.store.UpdateContact(ctx, contact)
+}
+
+// DeleteContact removes an emergency contact
+func (s *service) DeleteContact(ctx context.Context, userID, contactID string) error {
+	return s.store.DeleteContact(ctx, userID, contactID)
+}
+
+// ListForCareTeam lists the contacts a user has agreed their care team may get
+// in touch with. Users looking at their own contacts see them all.
+func (s *service) ListForCareTeam(ctx context.Context, actorID, userID string) (*ListContactsResponse, error) {
+	access, err := s.careTeam.CheckAccess(ctx, actorID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	contacts, err := s.store.ListContacts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if access.Via == careteam.AccessSelf {
+		return &ListContactsResponse{Contacts: contacts}, nil
+	}
+
+	consented := []Contact{}
+	for _, contact := range contacts {
+		if contact.CareTeamMayContact {
+			consented = append(consented, contact)
+		}
+	}
+
+	return &ListContactsResponse{Contacts: consented}, nil
+}
+
+// Helper functions
+
+func validateContact(contact *Contact) error {
+	if contact.Name == "" || len(contact.Name) > 255 {
+		return fmt.Errorf("name must be between 1 and 255 characters")
+	}
+	if contact.Relationship == "" || len(contact.Relationship) > 100 {
+		return fmt.Errorf("relationship must be between 1 and 100 characters")
+	}
+
+	if contact.Phone == nil && contact.Email == nil {
+		return fmt.Errorf("a phone number or email address is required")
+	}
+	if contact.Phone != nil {
+		if digits := len(strings.TrimPrefix(encryption.NormalizePhone(*contact.Phone), "+")); digits < 7 || digits > 15 {
+			return fmt.Errorf("invalid phone number")
+		}
+	}
+	if contact.Email != nil {
+		if address, err := mail.ParseAddress(*contact.Email); err != nil || address.Address != *contact.Email {
+			return fmt.Errorf("invalid email address")
+		}
+	}
+
+	switch contact.PreferredChannel {
+	case ChannelPhone, ChannelSMS:
+		if contact.Phone == nil {
+			return fmt.Errorf("a phone number is required to be contacted by %s", contact.PreferredChannel)
+		}
+	case ChannelEmail:
+		if contact.Email == nil {
+			return fmt.Errorf("an email address is required to be contacted by email")
+		}
+	default:
+		return fmt.Errorf("invalid preferred channel: %s", contact.PreferredChannel)
+	}
+
+	for _, circumstance := range contact.ContactCircumstances {
+		if !slices.Contains(Circumstances, circumstance) {
+			return fmt.Errorf("invalid contact circumstance: %s", circumstance)
+		}
+	}
+	if contact.CareTeamMayContact && len(contact.ContactCircumstances) == 0 {
+		return fmt.Errorf("contact_circumstances must say when the care team may get in touch")
+	}
+	if !contact.CareTeamMayContact && len(contact.ContactCircumstances) > 0 {
+		return fmt.Errorf("contact_circumstances can only be given when the care team may get in touch")
+	}
+
+	if contact.ConsentNotes != nil && len(*contact.ConsentNotes) > 1000 {
+		return fmt.Errorf("consent_notes must be at most 1000 characters")
+	}
+
+	return nil
+}
+
+// uniqueCircumstances returns the circumstances without repeats, in the order
+// of Circumstances so changes can be compared
+func uniqueCircumstances(circumstances []string) []string {
+	unique := []string{}
+	for _, circumstance := range Circumstances {
+		if slices.Contains(circumstances, circumstance) {
+			unique = append(unique, circumstance)
+		}
+	}
+	for _, circumstance := range circumstances {
+		// Unknown values are kept so validation can reject them
+		if !slices.Contains(Circumstances, circumstance) && !slices.Contains(unique, circumstance) {
+			unique = append(unique, circumstance)
+		}
+	}
+	return unique
+}
+
+func trimOptional(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
@@ -0,0 +1,130 @@
+package emergencycontacts
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/careteam"
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+)
+
+const (
+	professional = "11111111-1111-1111-1111-111111111111"
+	parent       = "22222222-2222-2222-2222-222222222222"
+	otherParent  = "33333333-3333-3333-3333-333333333333"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestContactValidation(t *testing.T) {
+	svc := NewService(NewMemoryStore(), careteam.NewService(careteam.NewMemoryStore(memdb.New())))
+	valid := func() CreateContactRequest {
+		return CreateContactRequest{Name: "Jo", Relationship: "Sister", Phone: strPtr("07700 900123"), PreferredChannel: ChannelSMS}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*CreateContactRequest)
+	}{
+		{"no way to reach them", func(req *CreateContactRequest) { req.Phone = nil }},
+		{"short phone number", func(req *CreateContactRequest) { req.Phone = strPtr("12345") }},
+		{"email preferred without an email", func(req *CreateContactRequest) { req.PreferredChannel = ChannelEmail }},
+		{"bad email", func(req *CreateContactRequest) { req.Email = strPtr("jo at example") }},
+		{"consent without circumstances", func(req *CreateContactRequest) { req.CareTeamMayContact = true }},
+		{"circumstances without consent", func(req *CreateContactRequest) { req.ContactCircumstances = []string{CircumstanceCrisis} }},
+		{"unknown circumstance", func(req *CreateContactRequest) {
+			req.CareTeamMayContact = true
+			req.ContactCircumstances = []string{"birthdays"}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid()
+			tt.modify(&req)
+			if _, err := svc.CreateContact(context.Background(), parent, &req); err == nil {
+				t.Errorf("CreateContact(%+v) succeeded, want a validation error", req)
+			}
+		})
+	}
+
+	req := valid()
+	if _, err := svc.CreateContact(context.Background(), parent, &req); err != nil {
+		t.Errorf("CreateContact() error = %v", err)
+	}
+}
+
+func TestConsentGatesCareTeamAccess(t *testing.T) {
+	ctx := context.Background()
+	db := memdb.New()
+	now := time.Now()
+	for _, u := range []memdb.User{
+		{ID: professional, FullName: "Pat Professional", Email: "pro@example.com", Role: "professional", IsActive: true},
+		{ID: parent, FullName: "Sam Parent", Email: "parent@example.com", Role: "service_user", IsActive: true},
+		{ID: otherParent, FullName: "Alex Parent", Email: "alex@example.com", Role: "service_user", IsActive: true},
+	} {
+		u.CreatedAt, u.UpdatedAt = now, now
+		if err := db.InsertUser(u, memdb.Profile{UserID: u.ID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	careTeam := careteam.NewService(careteam.NewMemoryStore(db))
+	svc := NewService(NewMemoryStore(), careTeam)
+
+	partner, err := svc.CreateContact(ctx, parent, &CreateContactRequest{
+		Name: "Chris", Relationship: "Partner", Phone: strPtr("+44 7700 900456"), PreferredChannel: ChannelPhone,
+		CareTeamMayContact: true, ContactCircumstances: []string{CircumstanceUnreachable, CircumstanceCrisis, CircumstanceCrisis},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(partner.ContactCircumstances) != 2 || partner.ContactCircumstances[0] != CircumstanceCrisis {
+		t.Errorf("CreateContact() circumstances = %v, want them once each in a fixed order", partner.ContactCircumstances)
+	}
+	if _, err := svc.CreateContact(ctx, parent, &CreateContactRequest{Name: "Mum", Relationship: "Mother", Email: strPtr("mum@example.com"), PreferredChannel: ChannelEmail}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.ListForCareTeam(ctx, professional, parent); !errors.Is(err, careteam.ErrNoAccess) {
+		t.Fatalf("ListForCareTeam() without a relationship error = %v, want ErrNoAccess", err)
+	}
+	invitation, err := careTeam.Invite(ctx, professional, &careteam.InviteRequest{ServiceUserID: parent, Reason: "Health visitor"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := careTeam.Accept(ctx, parent, invitation.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	shared, err := svc.ListForCareTeam(ctx, professional, parent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shared.Contacts) != 1 || shared.Contacts[0].ID != partner.ID {
+		t.Errorf("ListForCareTeam() = %+v, want only the contact the care team may get in touch with", shared.Contacts)
+	}
+	if own, _ := svc.ListForCareTeam(ctx, parent, parent); len(own.Contacts) != 2 {
+		t.Errorf("ListForCareTeam() for the user themselves = %d contacts, want 2", len(own.Contacts))
+	}
+
+	// Withdrawing consent clears the circumstances and hides the contact
+	updated, err := svc.UpdateContact(ctx, parent, partner.ID, &UpdateContactRequest{CareTeamMayContact: new(bool)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.CareTeamMayContact || len(updated.ContactCircumstances) != 0 || updated.ConsentUpdatedAt.Before(*partner.ConsentUpdatedAt) {
+		t.Errorf("UpdateContact() = %+v, want consent withdrawn", updated)
+	}
+	if shared, _ := svc.ListForCareTeam(ctx, professional, parent); len(shared.Contacts) != 0 {
+		t.Errorf("ListForCareTeam() after withdrawal = %+v, want none", shared.Contacts)
+	}
+
+	if _, err := svc.GetContact(ctx, otherParent, partner.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetContact() by another user error = %v, want ErrNotFound", err)
+	}
+	if err := svc.DeleteContact(ctx, otherParent, partner.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DeleteContact() by another user error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,196 @@
+package emergencycontacts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/perinatal-mental-health-app/backend/internal/encryption"
+)
+
+type store struct {
+	db     *pgxpool.Pool
+	cipher encryption.Cipher
+}
+
+func NewStore(db *pgxpool.Pool, cipher encryption.Cipher) Store {
+	return &store{
+		db:     db,
+		cipher: cipher,
+	}
+}
+
+const contactColumns = `id, user_id, name, relationship, phone, email, preferred_channel, care_team_may_contact,
+	contact_circumstances, consent_notes, consent_updated_at, created_at, updated_at`
+
+// ListContacts lists a user's contacts, oldest first
+func (s *store) ListContacts(ctx context.Context, userID string) ([]Contact, error) {
+	query := fmt.Sprintf(`SELECT %s FROM emergency_contacts WHERE user_id = $1 ORDER BY created_at, id`, contactColumns)
+
+	rows, err := s.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list emergency contacts: %w", err)
+	}
+	defer rows.Close()
+
+	contacts := []Contact{}
+	for rows.Next() {
+		var contact Contact
+		if err := scanContact(rows, &contact); err != nil {
+			return nil, fmt.Errorf("failed to scan emergency contact: %w", err)
+		}
+		if err := s.cipher.DecryptFields(ctx, &contact); err != nil {
+			return nil, fmt.Errorf("failed to decrypt emergency contact: %w", err)
+		}
+		contacts = append(contacts, contact)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return contacts, nil
+}
+
+// GetContact retrieves one of a user's contacts
+func (s *store) GetContact(ctx context.Context, userID, contactID string) (*Contact, error) {
+	if _, err := uuid.Parse(contactID); err != nil {
+		return nil, ErrNotFound
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM emergency_contacts WHERE id = $1 AND user_id = $2`, contactColumns)
+
+	var contact Contact
+	if err := scanContact(s.db.QueryRow(ctx, query, contactID, userID), &contact); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get emergency contact: %w", err)
+	}
+	if err := s.cipher.DecryptFields(ctx, &contact); err != nil {
+		return nil, fmt.Errorf("failed to decrypt emergency contact: %w", err)
+	}
+
+	return &contact, nil
+}
+
+// CreateContact adds a contact, failing with ErrTooManyContacts if the user
+// already has MaxContacts
+func (s *store) CreateContact(ctx context.Context, contact *Contact) (*Contact, error) {
+	sealed := *contact
+	if err := s.cipher.EncryptFields(ctx, &sealed); err != nil {
+		return nil, fmt.Errorf("failed to encrypt emergency contact: %w", err)
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Locking the user serialises concurrent adds so the limit holds
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM users WHERE id = $1 FOR UPDATE`, contact.UserID); err != nil {
+		return nil, fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	var count int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM emergency_contacts WHERE user_id = $1`, contact.UserID).Scan(&count); err != nil {
+		return nil, fmt.Errorf("failed to count emergency contacts: %w", err)
+	}
+	if count >= MaxContacts {
+		return nil, ErrTooManyContacts
+	}
+
+	query := `
+		INSERT INTO emergency_contacts (id, user_id, name, relationship, phone, email, preferred_channel,
+		                                care_team_may_contact, contact_circumstances, consent_notes,
+		                                consent_updated_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+
+	_, err = tx.Exec(ctx, query,
+		sealed.ID, sealed.UserID, sealed.Name, sealed.Relationship, sealed.Phone, sealed.Email,
+		sealed.PreferredChannel, sealed.CareTeamMayContact, sealed.ContactCircumstances, sealed.ConsentNotes,
+		sealed.ConsentUpdatedAt, sealed.CreatedAt, sealed.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create emergency contact: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit emergency contact: %w", err)
+	}
+
+	return s.GetContact(ctx, contact.UserID, contact.ID)
+}
+
+// UpdateContact saves every field of a contact
+func (s *store) UpdateContact(ctx context.Context, contact *Contact) (*Contact, error) {
+	sealed := *contact
+	if err := s.cipher.EncryptFields(ctx, &sealed); err != nil {
+		return nil, fmt.Errorf("failed to encrypt emergency contact: %w", err)
+	}
+
+	query := `
+		UPDATE emergency_contacts
+		SET name = $3, relationship = $4, phone = $5, email = $6, preferred_channel = $7,
+		    care_team_may_contact = $8, contact_circumstances = $9, consent_notes = $10,
+		    consent_updated_at = $11, updated_at = $12
+		WHERE id = $1 AND user_id = $2
+	`
+
+	result, err := s.db.Exec(ctx, query,
+		sealed.ID, sealed.UserID, sealed.Name, sealed.Relationship, sealed.Phone, sealed.Email,
+		sealed.PreferredChannel, sealed.CareTeamMayContact, sealed.ContactCircumstances, sealed.ConsentNotes,
+		sealed.ConsentUpdatedAt, sealed.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update emergency contact: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return nil, ErrNotFound
+	}
+
+	return s.GetContact(ctx, contact.UserID, contact.ID)
+}
+
+// DeleteContact removes one of a user's contacts
+func (s *store) DeleteContact(ctx context.Context, userID, contactID string) error {
+	if _, err := uuid.Parse(contactID); err != nil {
+		return ErrNotFound
+	}
+
+	result, err := s.db.Exec(ctx, `DELETE FROM emergency_contacts WHERE id = $1 AND user_id = $2`, contactID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete emergency contact: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Helper functions
+
+func scanContact(row pgx.Row, contact *Contact) error {
+	return row.Scan(
+		&contact.ID,
+		&contact.UserID,
+		&contact.Name,
+		&contact.Relationship,
+		&contact.Phone,
+		&contact.Email,
+		&contact.PreferredChannel,
+		&contact.CareTeamMayContact,
+		&contact.ContactCircumstances,
+		&contact.ConsentNotes,
+		&contact.ConsentUpdatedAt,
+		&contact.CreatedAt,
+		&contact.UpdatedAt,
+	)
+}
@@ -0,0 +1,41 @@
+package encryption
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	service Service
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// RotateKey activates a new data key (admin only)
+func (h *handler) RotateKey(c echo.Context) error {
+	result, err := h.service.RotateKey(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetKeyStatus reports data keys and re-encryption progress (admin only)
+func (h *handler) GetKeyStatus(c echo.Context) error {
+	status, err := h.service.GetKeyStatus(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, status)
+}
@@ -0,0 +1,47 @@
+package encryption
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Cipher seals and opens sensitive fields at the store layer. Struct fields tagged
+// `encrypt:"true"` (string or *string) are handled by EncryptFields and DecryptFields.
+type Cipher interface {
+	Encrypt(ctx context.Context, plaintext string) (string, error)
+	Decrypt(ctx context.Context, value string) (string, error)
+	EncryptFields(ctx context.Context, v interface{}) error
+	DecryptFields(ctx context.Context, v interface{}) error
+	// BlindIndex returns a keyed hash of the value for equality lookups
+	BlindIndex(value string) string
+}
+
+// Service defines the interface for key management
+type Service interface {
+	RotateKey(ctx context.Context) (*RotateKeyResponse, error)
+	GetKeyStatus(ctx context.Context) (*KeyStatusResponse, error)
+}
+
+// Store defines the interface for data key and encrypted value persistence
+type Store interface {
+	ListKeys(ctx context.Context) ([]DataKey, error)
+	CreateKey(ctx context.Context, key *DataKey) error
+	// RotateKey demotes the active key to decrypt-only and inserts the new active key
+	RotateKey(ctx context.Context, key *DataKey) ([]string, error)
+	UpdateWrappedKey(ctx context.Context, keyID string, wrappedKey []byte, masterKeyID string) error
+	SetKeyStatus(ctx context.Context, keyID, status string) error
+
+	// ListStaleValues returns values not sealed with the active key, including legacy plaintext
+	ListStaleValues(ctx context.Context, column EncryptedColumn, activeKeyID string, limit int) ([]StoredValue, error)
+	// ReplaceValue swaps a value only if it is unchanged since it was read
+	ReplaceValue(ctx context.Context, column EncryptedColumn, value StoredValue, sealed string, blindIndex *string) (bool, error)
+	// CountValuesByKey counts non-empty values per data key ID; plaintext is counted under ""
+	CountValuesByKey(ctx context.Context, column EncryptedColumn) (map[string]int64, error)
+}
+
+// Handler defines the interface for key management HTTP handlers
+type Handler interface {
+	RotateKey(c echo.Context) error
+	GetKeyStatus(c echo.Context) error
+}
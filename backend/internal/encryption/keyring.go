@@ -0,0 +1,492 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	config "github.com/perinatal-mental-health-app/backend/internal/configs"
+)
+
+const (
+	// ciphertextPrefix marks sealed values: enc:v1:<data key id>:<base64 nonce+ciphertext>
+	ciphertextPrefix = "enc:v1:"
+
+	// keyRefreshInterval bounds how long an instance keeps sealing with a key
+	// that another instance has rotated out. Values sealed in that window stay
+	// readable and are picked up by re-encryption.
+	keyRefreshInterval = time.Minute
+)
+
+// MasterKey wraps data keys. Its ID is derived from the key material.
+type MasterKey struct {
+	ID  string
+	key []byte
+}
+
+// NewMasterKey validates a 256-bit master key
+func NewMasterKey(key []byte) (MasterKey, error) {
+	if len(key) != 32 {
+		return MasterKey{}, fmt.Errorf("master key must be 32 bytes, got %d", len(key))
+	}
+
+	sum := sha256.Sum256(key)
+	return MasterKey{ID: hex.EncodeToString(sum[:8]), key: key}, nil
+}
+
+// MasterKeysFromConfig loads the current master key from ENCRYPTION_MASTER_KEY or
+// ENCRYPTION_KEY_FILE, followed by any ENCRYPTION_PREVIOUS_MASTER_KEYS. Keys are
+// base64 encoded; the blind index key is derived from the current master key
+// unless ENCRYPTION_INDEX_KEY is set.
+func MasterKeysFromConfig(cfg *config.Config) ([]MasterKey, []byte, error) {
+	encoded := cfg.EncryptionMasterKey
+	if cfg.EncryptionKeyFile != "" {
+		if encoded != "" {
+			return nil, nil, fmt.Errorf("set only one of ENCRYPTION_MASTER_KEY and ENCRYPTION_KEY_FILE")
+		}
+		contents, err := os.ReadFile(cfg.EncryptionKeyFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read encryption key file: %w", err)
+		}
+		encoded = string(contents)
+	}
+	if strings.TrimSpace(encoded) == "" {
+		return nil, nil, fmt.Errorf("no encryption master key configured")
+	}
+
+	var masters []MasterKey
+	for i, value := range append([]string{encoded}, strings.Split(cfg.EncryptionPreviousMasterKeys, ",")...) {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		raw, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid master key %d: %w", i, err)
+		}
+		master, err := NewMasterKey(raw)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid master key %d: %w", i, err)
+		}
+		masters = append(masters, master)
+	}
+
+	indexKey := deriveKey(masters[0].key, "blind-index")
+	if cfg.EncryptionIndexKey != "" {
+		raw, err := base64.StdEncoding.DecodeString(cfg.EncryptionIndexKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid blind index key: %w", err)
+		}
+		indexKey = raw
+	}
+
+	return masters, indexKey, nil
+}
+
+// Keyring implements Cipher with envelope encryption: values are sealed with
+// AES-GCM data keys that are stored wrapped by the current master key. Keys are
+// loaded lazily on first use and reloaded every keyRefreshInterval.
+type Keyring struct {
+	store    Store
+	masters  []MasterKey
+	indexKey []byte
+
+	mu       sync.RWMutex
+	loadedAt time.Time
+	keys     map[string]cipher.AEAD
+	activeID string
+}
+
+func NewKeyring(store Store, masters []MasterKey, indexKey []byte) *Keyring {
+	return &Keyring{
+		store:    store,
+		masters:  masters,
+		indexKey: indexKey,
+		keys:     make(map[string]cipher.AEAD),
+	}
+}
+
+// Encrypt seals the plaintext with the active data key. Empty values are
+// stored as-is so "no value" stays distinguishable without a key lookup.
+func (k *Keyring) Encrypt(ctx context.Context, plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	keyID, err := k.ActiveKeyID(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	k.mu.RLock()
+	aead := k.keys[keyID]
+	k.mu.RUnlock()
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(keyID))
+	return ciphertextPrefix + keyID + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a sealed value. Values without the ciphertext prefix are legacy
+// plaintext awaiting re-encryption and are returned unchanged.
+func (k *Keyring) Decrypt(ctx context.Context, value string) (string, error) {
+	keyID, payload, ok := parseCiphertext(value)
+	if !ok {
+		return value, nil
+	}
+
+	aead, err := k.key(ctx, keyID)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("malformed ciphertext")
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(keyID))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// EncryptFields seals every non-empty field tagged `encrypt:"true"` in place
+func (k *Keyring) EncryptFields(ctx context.Context, v interface{}) error {
+	return eachTaggedField(v, func(value string) (string, error) {
+		if _, _, sealed := parseCiphertext(value); sealed {
+			return value, nil
+		}
+		return k.Encrypt(ctx, value)
+	})
+}
+
+// DecryptFields opens every field tagged `encrypt:"true"` in place
+func (k *Keyring) DecryptFields(ctx context.Context, v interface{}) error {
+	return eachTaggedField(v, func(value string) (string, error) {
+		return k.Decrypt(ctx, value)
+	})
+}
+
+// BlindIndex returns a hex HMAC-SHA256 of the value, or "" for an empty value
+func (k *Keyring) BlindIndex(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, k.indexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ActiveKeyID returns the key new values are sealed with, creating the first
+// data key if none exists yet
+func (k *Keyring) ActiveKeyID(ctx context.Context) (string, error) {
+	if err := k.load(ctx, false); err != nil {
+		return "", err
+	}
+
+	k.mu.RLock()
+	activeID := k.activeID
+	k.mu.RUnlock()
+	if activeID != "" {
+		return activeID, nil
+	}
+
+	key, err := k.newDataKey()
+	if err != nil {
+		return "", err
+	}
+	if err := k.store.CreateKey(ctx, key); err != nil {
+		// Another instance may have created the first key concurrently
+		if reloadErr := k.load(ctx, true); reloadErr != nil {
+			return "", fmt.Errorf("failed to create data key: %w", err)
+		}
+	} else if err := k.load(ctx, true); err != nil {
+		return "", err
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if k.activeID == "" {
+		return "", fmt.Errorf("no active data key")
+	}
+	return k.activeID, nil
+}
+
+// Rotate makes a fresh data key active. Existing values stay readable with the
+// previous keys until re-encryption rewrites them.
+func (k *Keyring) Rotate(ctx context.Context) (*DataKey, []string, error) {
+	key, err := k.newDataKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	demoted, err := k.store.RotateKey(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := k.load(ctx, true); err != nil {
+		return nil, nil, err
+	}
+
+	return key, demoted, nil
+}
+
+// Rewrap re-wraps data keys still wrapped by a previous master key with the
+// current one, returning how many keys changed
+func (k *Keyring) Rewrap(ctx context.Context) (int, error) {
+	keys, err := k.store.ListKeys(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	current := k.masters[0]
+	rewrapped := 0
+	for _, key := range keys {
+		if key.MasterKeyID == current.ID {
+			continue
+		}
+
+		raw, err := k.unwrap(key)
+		if err != nil {
+			return rewrapped, err
+		}
+		wrapped, err := seal(current.key, raw, []byte(key.ID))
+		if err != nil {
+			return rewrapped, err
+		}
+		if err := k.store.UpdateWrappedKey(ctx, key.ID, wrapped, current.ID); err != nil {
+			return rewrapped, err
+		}
+		rewrapped++
+	}
+
+	return rewrapped, nil
+}
+
+// MasterKeyID returns the ID of the master key new data keys are wrapped with
+func (k *Keyring) MasterKeyID() string {
+	return k.masters[0].ID
+}
+
+// Helper functions
+
+// load reads and unwraps every data key. Without force it only does so when the
+// keys are missing or older than keyRefreshInterval, which is how a rotation on
+// another instance reaches this one.
+func (k *Keyring) load(ctx context.Context, force bool) error {
+	k.mu.RLock()
+	fresh := !k.loadedAt.IsZero() && time.Since(k.loadedAt) < keyRefreshInterval
+	k.mu.RUnlock()
+	if fresh && !force {
+		return nil
+	}
+
+	keys, err := k.store.ListKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load data keys: %w", err)
+	}
+
+	aeads := make(map[string]cipher.AEAD, len(keys))
+	activeID := ""
+	for _, key := range keys {
+		raw, err := k.unwrap(key)
+		if err != nil {
+			return err
+		}
+		aead, err := newAEAD(raw)
+		if err != nil {
+			return err
+		}
+		aeads[key.ID] = aead
+		if key.Status == KeyStatusActive {
+			activeID = key.ID
+		}
+	}
+
+	k.mu.Lock()
+	k.keys = aeads
+	k.activeID = activeID
+	k.loadedAt = time.Now()
+	k.mu.Unlock()
+
+	return nil
+}
+
+func (k *Keyring) key(ctx context.Context, keyID string) (cipher.AEAD, error) {
+	if err := k.load(ctx, false); err != nil {
+		return nil, err
+	}
+
+	k.mu.RLock()
+	aead, ok := k.keys[keyID]
+	k.mu.RUnlock()
+	if ok {
+		return aead, nil
+	}
+
+	// The key may have been created by another instance since we loaded
+	if err := k.load(ctx, true); err != nil {
+		return nil, err
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if aead, ok := k.keys[keyID]; ok {
+		return aead, nil
+	}
+	return nil, fmt.Errorf("unknown data key %s", keyID)
+}
+
+func (k *Keyring) unwrap(key DataKey) ([]byte, error) {
+	for _, master := range k.masters {
+		if master.ID != key.MasterKeyID {
+			continue
+		}
+		raw, err := open(master.key, key.WrappedKey, []byte(key.ID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to unwrap data key %s: %w", key.ID, err)
+		}
+		return raw, nil
+	}
+	return nil, fmt.Errorf("data key %s is wrapped by unknown master key %s", key.ID, key.MasterKeyID)
+}
+
+func (k *Keyring) newDataKey() (*DataKey, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	id := uuid.New().String()
+	wrapped, err := seal(k.masters[0].key, raw, []byte(id))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &DataKey{
+		ID:          id,
+		WrappedKey:  wrapped,
+		MasterKeyID: k.masters[0].ID,
+		Status:      KeyStatusActive,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts with AES-GCM, prefixing the random nonce
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key, sealed, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("malformed ciphertext")
+	}
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], additionalData)
+}
+
+func deriveKey(master []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, master)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+// parseCiphertext splits a sealed value into its key ID and payload
+func parseCiphertext(value string) (keyID, payload string, ok bool) {
+	if !strings.HasPrefix(value, ciphertextPrefix) {
+		return "", "", false
+	}
+	keyID, payload, ok = strings.Cut(strings.TrimPrefix(value, ciphertextPrefix), ":")
+	return keyID, payload, ok && keyID != ""
+}
+
+// KeyIDOf returns the data key a value is sealed with, or "" for plaintext
+func KeyIDOf(value string) string {
+	keyID, _, _ := parseCiphertext(value)
+	return keyID
+}
+
+// eachTaggedField applies fn to every non-empty string or *string field tagged
+// `encrypt:"true"` on the struct pointed to by v
+func eachTaggedField(v interface{}, fn func(string) (string, error)) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("expected a pointer to a struct, got %T", v)
+	}
+	rv = rv.Elem()
+
+	for i := 0; i < rv.NumField(); i++ {
+		if rv.Type().Field(i).Tag.Get("encrypt") != "true" {
+			continue
+		}
+
+		field := rv.Field(i)
+		switch {
+		case field.Kind() == reflect.String:
+			if field.String() == "" {
+				continue
+			}
+			out, err := fn(field.String())
+			if err != nil {
+				return err
+			}
+			field.SetString(out)
+		case field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.String:
+			if field.IsNil() || field.Elem().String() == "" {
+				continue
+			}
+			out, err := fn(field.Elem().String())
+			if err != nil {
+				return err
+			}
+			field.Set(reflect.ValueOf(&out))
+		default:
+			return fmt.Errorf("field %s tagged for encryption must be a string", rv.Type().Field(i).Name)
+		}
+	}
+
+	return nil
+}
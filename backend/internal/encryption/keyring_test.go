@@ -0,0 +1,156 @@
+package encryption
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// keyStore keeps data keys in memory; the value methods are unused here
+type keyStore struct {
+	Store
+	mu   sync.Mutex
+	keys []DataKey
+}
+
+func (s *keyStore) ListKeys(ctx context.Context) ([]DataKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]DataKey{}, s.keys...), nil
+}
+
+func (s *keyStore) CreateKey(ctx context.Context, key *DataKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = append(s.keys, *key)
+	return nil
+}
+
+func (s *keyStore) RotateKey(ctx context.Context, key *DataKey) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var demoted []string
+	for i := range s.keys {
+		if s.keys[i].Status == KeyStatusActive {
+			s.keys[i].Status = KeyStatusDecryptOnly
+			demoted = append(demoted, s.keys[i].ID)
+		}
+	}
+	s.keys = append(s.keys, *key)
+	return demoted, nil
+}
+
+func (s *keyStore) UpdateWrappedKey(ctx context.Context, keyID string, wrappedKey []byte, masterKeyID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.keys {
+		if s.keys[i].ID == keyID {
+			s.keys[i].WrappedKey, s.keys[i].MasterKeyID = wrappedKey, masterKeyID
+		}
+	}
+	return nil
+}
+
+func newTestKeyring(t *testing.T, store Store, seeds ...byte) *Keyring {
+	t.Helper()
+	var masters []MasterKey
+	for _, seed := range seeds {
+		master, err := NewMasterKey([]byte(strings.Repeat(string(rune(seed)), 32)))
+		if err != nil {
+			t.Fatalf("NewMasterKey() error = %v", err)
+		}
+		masters = append(masters, master)
+	}
+	return NewKeyring(store, masters, make([]byte, 32))
+}
+
+func TestKeyringRotateKeepsOldValuesReadable(t *testing.T) {
+	ctx := context.Background()
+	keyring := newTestKeyring(t, &keyStore{}, 'a')
+
+	sealed, err := keyring.Encrypt(ctx, "07700 900000")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if !strings.HasPrefix(sealed, ciphertextPrefix) || strings.Contains(sealed, "07700") {
+		t.Fatalf("Encrypt() = %q, want a sealed value", sealed)
+	}
+
+	key, demoted, err := keyring.Rotate(ctx)
+	if err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	if len(demoted) != 1 || demoted[0] != KeyIDOf(sealed) {
+		t.Errorf("Rotate() demoted %v, want the previous key %s", demoted, KeyIDOf(sealed))
+	}
+
+	resealed, err := keyring.Encrypt(ctx, "07700 900000")
+	if err != nil {
+		t.Fatalf("Encrypt() after rotation error = %v", err)
+	}
+	if KeyIDOf(resealed) != key.ID {
+		t.Errorf("Encrypt() after rotation used key %s, want %s", KeyIDOf(resealed), key.ID)
+	}
+
+	for _, value := range []string{sealed, resealed} {
+		if plaintext, err := keyring.Decrypt(ctx, value); err != nil || plaintext != "07700 900000" {
+			t.Errorf("Decrypt() = (%q, %v), want the original value", plaintext, err)
+		}
+	}
+
+	if plaintext, _ := keyring.Decrypt(ctx, "legacy plaintext"); plaintext != "legacy plaintext" {
+		t.Errorf("Decrypt() of plaintext = %q, want it unchanged", plaintext)
+	}
+}
+
+func TestKeyringRewrapWithNewMasterKey(t *testing.T) {
+	ctx := context.Background()
+	store := &keyStore{}
+	sealed, err := newTestKeyring(t, store, 'a').Encrypt(ctx, "secret")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	// The old master key is kept as a previous key while data keys are re-wrapped
+	rotated := newTestKeyring(t, store, 'b', 'a')
+	if count, err := rotated.Rewrap(ctx); err != nil || count != 1 {
+		t.Fatalf("Rewrap() = (%d, %v), want 1 key re-wrapped", count, err)
+	}
+
+	withoutOld := newTestKeyring(t, store, 'b')
+	if plaintext, err := withoutOld.Decrypt(ctx, sealed); err != nil || plaintext != "secret" {
+		t.Errorf("Decrypt() with only the new master key = (%q, %v), want the original value", plaintext, err)
+	}
+}
+
+func TestKeyringPicksUpRotationOnAnotherInstance(t *testing.T) {
+	ctx := context.Background()
+	store := &keyStore{}
+	first, second := newTestKeyring(t, store, 'a'), newTestKeyring(t, store, 'a')
+
+	before, err := second.ActiveKeyID(ctx)
+	if err != nil {
+		t.Fatalf("ActiveKeyID() error = %v", err)
+	}
+
+	key, _, err := first.Rotate(ctx)
+	if err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+
+	if cached, _ := second.ActiveKeyID(ctx); cached != before {
+		t.Fatalf("ActiveKeyID() straight after another instance rotated = %s, want the cached %s", cached, before)
+	}
+
+	// Once the refresh interval has passed the new key is used
+	second.mu.Lock()
+	second.loadedAt = second.loadedAt.Add(-keyRefreshInterval - time.Second)
+	second.mu.Unlock()
+
+	if active, _ := second.ActiveKeyID(ctx); active != key.ID {
+		t.Errorf("ActiveKeyID() after the refresh interval = %s, want %s", active, key.ID)
+	}
+}
@@ -0,0 +1,120 @@
+package encryption
+
+import (
+	"strings"
+	"time"
+)
+
+// Data key statuses. New values are always sealed with the active key; older keys
+// stay available for decryption until re-encryption has moved every value off them.
+const (
+	KeyStatusActive      = "active"
+	KeyStatusDecryptOnly = "decrypt_only"
+	KeyStatusRetired     = "retired"
+)
+
+// DataKey is an AES-256 key wrapped by a master key
+type DataKey struct {
+	ID          string    `json:"id" db:"id"`
+	WrappedKey  []byte    `json:"-" db:"wrapped_key"`
+	MasterKeyID string    `json:"master_key_id" db:"master_key_id"`
+	Status      string    `json:"status" db:"status"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// EncryptedColumn describes a column holding sealed values, so background
+// re-encryption can find and rewrite them. BlindIndexColumn, when set, holds an
+// HMAC of the normalised plaintext for equality lookups.
+type EncryptedColumn struct {
+	Table            string
+	KeyColumn        string
+	Column           string
+	BlindIndexColumn string
+	Normalize        func(string) string
+}
+
+// Name returns the qualified column name
+func (c EncryptedColumn) Name() string {
+	return c.Table + "." + c.Column
+}
+
+// EncryptedColumns lists every column sealed at the store layer
+var EncryptedColumns = []EncryptedColumn{
+	{Table: "account_status_changes", KeyColumn: "id", Column: "reason"},
+	{Table: "break_glass_access", KeyColumn: "id", Column: "reason"},
+	{Table: "care_team_relationships", KeyColumn: "id", Column: "reason"},
+	{Table: "care_team_relationships", KeyColumn: "id", Column: "end_reason"},
+	{Table: "crisis_plans", KeyColumn: "user_id", Column: "warning_signs"},
+	{Table: "crisis_plans", KeyColumn: "user_id", Column: "coping_strategies"},
+	{Table: "crisis_plans", KeyColumn: "user_id", Column: "how_to_help"},
+	{Table: "crisis_plans", KeyColumn: "user_id", Column: "professional_contacts"},
+	{Table: "emergency_contacts", KeyColumn: "id", Column: "name"},
+	{Table: "emergency_contacts", KeyColumn: "id", Column: "relationship"},
+	{Table: "emergency_contacts", KeyColumn: "id", Column: "phone"},
+	{Table: "emergency_contacts", KeyColumn: "id", Column: "email"},
+	{Table: "emergency_contacts", KeyColumn: "id", Column: "consent_notes"},
+	{Table: "journey_entries", KeyColumn: "id", Column: "notes"},
+	{Table: "journey_entries", KeyColumn: "id", Column: "gratitude_note"},
+	{Table: "referrals", KeyColumn: "id", Column: "reason"},
+	{Table: "user_profiles", KeyColumn: "user_id", Column: "phone_number", BlindIndexColumn: "phone_number_bidx", Normalize: NormalizePhone},
+	{Table: "user_profiles", KeyColumn: "user_id", Column: "date_of_birth"},
+	{Table: "user_profiles", KeyColumn: "user_id", Column: "address"},
+	{Table: "user_profiles", KeyColumn: "user_id", Column: "emergency_contact"},
+	{Table: "user_profiles", KeyColumn: "user_id", Column: "perinatal_details"},
+	{Table: "webhook_subscriptions", KeyColumn: "id", Column: "secret"},
+	{Table: "webhook_subscriptions", KeyColumn: "id", Column: "previous_secret"},
+}
+
+// StoredValue is a single encrypted (or legacy plaintext) cell
+type StoredValue struct {
+	Key   string
+	Value string
+}
+
+// KeyUsage reports how many stored values are sealed with a data key
+type KeyUsage struct {
+	KeyID       string    `json:"key_id"`
+	Status      string    `json:"status"`
+	MasterKeyID string    `json:"master_key_id"`
+	Values      int64     `json:"values"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ReencryptionRun summarises a background re-encryption pass
+type ReencryptionRun struct {
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	Rewritten   int64      `json:"rewritten"`
+	Failed      int64      `json:"failed"`
+	KeysRetired int        `json:"keys_retired"`
+	Error       *string    `json:"error,omitempty"`
+}
+
+// KeyStatusResponse represents the state of field encryption keys
+type KeyStatusResponse struct {
+	ActiveKeyID     string           `json:"active_key_id"`
+	MasterKeyID     string           `json:"master_key_id"`
+	Keys            []KeyUsage       `json:"keys"`
+	PlaintextValues int64            `json:"plaintext_values"`
+	Running         bool             `json:"running"`
+	LastRun         *ReencryptionRun `json:"last_run,omitempty"`
+}
+
+// RotateKeyResponse represents the result of rotating the active data key
+type RotateKeyResponse struct {
+	ActiveKeyID    string   `json:"active_key_id"`
+	DecryptOnlyIDs []string `json:"decrypt_only_key_ids"`
+	Message        string   `json:"message"`
+}
+
+// NormalizePhone strips formatting so equivalent numbers share a blind index
+func NormalizePhone(phone string) string {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(phone) {
+		if (r >= '0' && r <= '9') || (r == '+' && i == 0) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
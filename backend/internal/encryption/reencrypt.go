@@ -0,0 +1,222 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"go.uber.org/zap"
+)
+
+const reencryptionBatchSize = 200
+
+// Reencryptor moves stored values onto the active data key in the background.
+// Each pass re-wraps data keys held under an old master key, seals legacy
+// plaintext, rewrites values sealed with older keys and retires keys that no
+// longer protect anything.
+type Reencryptor struct {
+	store   Store
+	keyring *Keyring
+	trigger chan struct{}
+
+	mu      sync.Mutex
+	running bool
+	lastRun *ReencryptionRun
+}
+
+func NewReencryptor(store Store, keyring *Keyring) *Reencryptor {
+	return &Reencryptor{
+		store:   store,
+		keyring: keyring,
+		trigger: make(chan struct{}, 1),
+	}
+}
+
+// Start runs a pass immediately, then every interval or when triggered, until
+// the context is cancelled
+func (r *Reencryptor) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RunOnce(ctx); err != nil {
+			logger.Error("Field re-encryption pass failed", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.trigger:
+		}
+	}
+}
+
+// Trigger requests a pass without waiting for the next interval
+func (r *Reencryptor) Trigger() {
+	select {
+	case r.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Status reports whether a pass is running and how the last one finished
+func (r *Reencryptor) Status() (bool, *ReencryptionRun) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.running, r.lastRun
+}
+
+// RunOnce performs a single re-encryption pass
+func (r *Reencryptor) RunOnce(ctx context.Context) (*ReencryptionRun, error) {
+	r.mu.Lock()
+	if r.running {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("re-encryption already running")
+	}
+	r.running = true
+	run := &ReencryptionRun{StartedAt: time.Now()}
+	r.mu.Unlock()
+
+	err := r.run(ctx, run)
+
+	finished := time.Now()
+	run.FinishedAt = &finished
+	if err != nil {
+		message := err.Error()
+		run.Error = &message
+	}
+
+	r.mu.Lock()
+	r.running = false
+	r.lastRun = run
+	r.mu.Unlock()
+
+	if run.Rewritten > 0 || run.Failed > 0 || run.KeysRetired > 0 {
+		logger.Info("Field re-encryption pass finished",
+			zap.Int64("rewritten", run.Rewritten),
+			zap.Int64("failed", run.Failed),
+			zap.Int("keys_retired", run.KeysRetired),
+		)
+	}
+
+	return run, err
+}
+
+func (r *Reencryptor) run(ctx context.Context, run *ReencryptionRun) error {
+	if _, err := r.keyring.Rewrap(ctx); err != nil {
+		return fmt.Errorf("failed to re-wrap data keys: %w", err)
+	}
+
+	activeKeyID, err := r.keyring.ActiveKeyID(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, column := range EncryptedColumns {
+		if err := r.reencryptColumn(ctx, column, activeKeyID, run); err != nil {
+			return err
+		}
+	}
+
+	return r.retireUnusedKeys(ctx, run)
+}
+
+func (r *Reencryptor) reencryptColumn(ctx context.Context, column EncryptedColumn, activeKeyID string, run *ReencryptionRun) error {
+	for {
+		values, err := r.store.ListStaleValues(ctx, column, activeKeyID, reencryptionBatchSize)
+		if err != nil {
+			return err
+		}
+
+		progressed := false
+		for _, value := range values {
+			ok, err := r.reencryptValue(ctx, column, value)
+			if err != nil {
+				run.Failed++
+				logger.Error("Failed to re-encrypt value",
+					zap.Error(err),
+					zap.String("column", column.Name()),
+					zap.String("key", value.Key),
+				)
+				continue
+			}
+			if ok {
+				run.Rewritten++
+				progressed = true
+			}
+		}
+
+		// Stop when the column is done, or when a batch made no progress so
+		// values that cannot be decrypted are not retried forever
+		if len(values) < reencryptionBatchSize || !progressed {
+			return nil
+		}
+	}
+}
+
+func (r *Reencryptor) reencryptValue(ctx context.Context, column EncryptedColumn, value StoredValue) (bool, error) {
+	plaintext, err := r.keyring.Decrypt(ctx, value.Value)
+	if err != nil {
+		return false, err
+	}
+
+	sealed, err := r.keyring.Encrypt(ctx, plaintext)
+	if err != nil {
+		return false, err
+	}
+
+	var blindIndex *string
+	if column.BlindIndexColumn != "" {
+		normalized := plaintext
+		if column.Normalize != nil {
+			normalized = column.Normalize(plaintext)
+		}
+		if index := r.keyring.BlindIndex(normalized); index != "" {
+			blindIndex = &index
+		}
+	}
+
+	return r.store.ReplaceValue(ctx, column, value, sealed, blindIndex)
+}
+
+func (r *Reencryptor) retireUnusedKeys(ctx context.Context, run *ReencryptionRun) error {
+	usage, err := valuesByKey(ctx, r.store)
+	if err != nil {
+		return err
+	}
+
+	keys, err := r.store.ListKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if key.Status != KeyStatusDecryptOnly || usage[key.ID] > 0 {
+			continue
+		}
+		if err := r.store.SetKeyStatus(ctx, key.ID, KeyStatusRetired); err != nil {
+			return err
+		}
+		run.KeysRetired++
+	}
+
+	return nil
+}
+
+// valuesByKey totals stored values per data key across every encrypted column
+func valuesByKey(ctx context.Context, store Store) (map[string]int64, error) {
+	totals := make(map[string]int64)
+	for _, column := range EncryptedColumns {
+		counts, err := store.CountValuesByKey(ctx, column)
+		if err != nil {
+			return nil, err
+		}
+		for keyID, count := range counts {
+			totals[keyID] += count
+		}
+	}
+	return totals, nil
+}
@@ -0,0 +1,75 @@
+package encryption
+
+import (
+	"context"
+)
+
+type service struct {
+	store       Store
+	keyring     *Keyring
+	reencryptor *Reencryptor
+}
+
+func NewService(store Store, keyring *Keyring, reencryptor *Reencryptor) Service {
+	return &service{
+		store:       store,
+		keyring:     keyring,
+		reencryptor: reencryptor,
+	}
+}
+
+// RotateKey activates a new data key and starts re-encrypting existing values
+func (s *service) RotateKey(ctx context.Context) (*RotateKeyResponse, error) {
+	key, demoted, err := s.keyring.Rotate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.reencryptor.Trigger()
+
+	return &RotateKeyResponse{
+		ActiveKeyID:    key.ID,
+		DecryptOnlyIDs: demoted,
+		Message:        "Data key rotated; existing values are being re-encrypted in the background",
+	}, nil
+}
+
+// GetKeyStatus reports each data key with the number of values it still protects
+func (s *service) GetKeyStatus(ctx context.Context) (*KeyStatusResponse, error) {
+	activeKeyID, err := s.keyring.ActiveKeyID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s.store.ListKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := valuesByKey(ctx, s.store)
+	if err != nil {
+		return nil, err
+	}
+
+	running, lastRun := s.reencryptor.Status()
+	status := &KeyStatusResponse{
+		ActiveKeyID:     activeKeyID,
+		MasterKeyID:     s.keyring.MasterKeyID(),
+		Keys:            make([]KeyUsage, 0, len(keys)),
+		PlaintextValues: usage[""],
+		Running:         running,
+		LastRun:         lastRun,
+	}
+
+	for _, key := range keys {
+		status.Keys = append(status.Keys, KeyUsage{
+			KeyID:       key.ID,
+			Status:      key.Status,
+			MasterKeyID: key.MasterKeyID,
+			Values:      usage[key.ID],
+			CreatedAt:   key.CreatedAt,
+		})
+	}
+
+	return status, nil
+}
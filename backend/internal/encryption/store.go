@@ -0,0 +1,235 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type store struct {
+	db *pgxpool.Pool
+}
+
+func NewStore(db *pgxpool.Pool) Store {
+	return &store{
+		db: db,
+	}
+}
+
+// ListKeys retrieves every data key, oldest first
+func (s *store) ListKeys(ctx context.Context) ([]DataKey, error) {
+	query := `
+		SELECT id, wrapped_key, master_key_id, status, created_at, updated_at
+		FROM encryption_keys
+		ORDER BY created_at ASC
+	`
+
+	rows, err := s.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query data keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []DataKey
+	for rows.Next() {
+		var key DataKey
+		err := rows.Scan(&key.ID, &key.WrappedKey, &key.MasterKeyID, &key.Status, &key.CreatedAt, &key.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan data key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return keys, nil
+}
+
+// CreateKey inserts a data key. The partial unique index on active keys makes
+// concurrent creation of the first key fail for all but one caller.
+func (s *store) CreateKey(ctx context.Context, key *DataKey) error {
+	query := `
+		INSERT INTO encryption_keys (id, wrapped_key, master_key_id, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := s.db.Exec(ctx, query, key.ID, key.WrappedKey, key.MasterKeyID, key.Status, key.CreatedAt, key.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create data key: %w", err)
+	}
+
+	return nil
+}
+
+// RotateKey demotes the active key and inserts the new one in a single transaction
+func (s *store) RotateKey(ctx context.Context, key *DataKey) ([]string, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		UPDATE encryption_keys
+		SET status = $1, updated_at = $2
+		WHERE status = $3
+		RETURNING id
+	`, KeyStatusDecryptOnly, time.Now(), KeyStatusActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to demote active key: %w", err)
+	}
+
+	demoted, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to demote active key: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO encryption_keys (id, wrapped_key, master_key_id, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, key.ID, key.WrappedKey, key.MasterKeyID, key.Status, key.CreatedAt, key.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data key: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit key rotation: %w", err)
+	}
+
+	return demoted, nil
+}
+
+// UpdateWrappedKey stores a data key re-wrapped by a new master key
+func (s *store) UpdateWrappedKey(ctx context.Context, keyID string, wrappedKey []byte, masterKeyID string) error {
+	result, err := s.db.Exec(ctx, `
+		UPDATE encryption_keys
+		SET wrapped_key = $1, master_key_id = $2, updated_at = $3
+		WHERE id = $4
+	`, wrappedKey, masterKeyID, time.Now(), keyID)
+	if err != nil {
+		return fmt.Errorf("failed to update data key: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("data key not found")
+	}
+
+	return nil
+}
+
+// SetKeyStatus updates a data key's status
+func (s *store) SetKeyStatus(ctx context.Context, keyID, status string) error {
+	result, err := s.db.Exec(ctx, `
+		UPDATE encryption_keys
+		SET status = $1, updated_at = $2
+		WHERE id = $3
+	`, status, time.Now(), keyID)
+	if err != nil {
+		return fmt.Errorf("failed to update data key status: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("data key not found")
+	}
+
+	return nil
+}
+
+// ListStaleValues retrieves values that are plaintext or sealed with an older key
+func (s *store) ListStaleValues(ctx context.Context, column EncryptedColumn, activeKeyID string, limit int) ([]StoredValue, error) {
+	query := fmt.Sprintf(`
+		SELECT %s::text, %s
+		FROM %s
+		WHERE %s IS NOT NULL AND %s <> '' AND %s NOT LIKE $1
+		LIMIT $2
+	`, ident(column.KeyColumn), ident(column.Column), ident(column.Table),
+		ident(column.Column), ident(column.Column), ident(column.Column))
+
+	rows, err := s.db.Query(ctx, query, ciphertextPrefix+activeKeyID+":%", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", column.Name(), err)
+	}
+	defer rows.Close()
+
+	var values []StoredValue
+	for rows.Next() {
+		var value StoredValue
+		if err := rows.Scan(&value.Key, &value.Value); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", column.Name(), err)
+		}
+		values = append(values, value)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return values, nil
+}
+
+// ReplaceValue rewrites a value, and its blind index if the column has one,
+// only when the stored value still matches what was read
+func (s *store) ReplaceValue(ctx context.Context, column EncryptedColumn, value StoredValue, sealed string, blindIndex *string) (bool, error) {
+	setSQL := fmt.Sprintf("%s = $1", ident(column.Column))
+	args := []interface{}{sealed, value.Key, value.Value}
+	if column.BlindIndexColumn != "" {
+		setSQL += fmt.Sprintf(", %s = $4", ident(column.BlindIndexColumn))
+		args = append(args, blindIndex)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET %s
+		WHERE %s::text = $2 AND %s = $3
+	`, ident(column.Table), setSQL, ident(column.KeyColumn), ident(column.Column))
+
+	result, err := s.db.Exec(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to update %s: %w", column.Name(), err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// CountValuesByKey counts stored values grouped by the data key that sealed them
+func (s *store) CountValuesByKey(ctx context.Context, column EncryptedColumn) (map[string]int64, error) {
+	query := fmt.Sprintf(`
+		SELECT CASE WHEN %s LIKE $1 THEN split_part(%s, ':', 3) ELSE '' END AS key_id, COUNT(*)
+		FROM %s
+		WHERE %s IS NOT NULL AND %s <> ''
+		GROUP BY key_id
+	`, ident(column.Column), ident(column.Column), ident(column.Table), ident(column.Column), ident(column.Column))
+
+	rows, err := s.db.Query(ctx, query, ciphertextPrefix+"%")
+	if err != nil {
+		return nil, fmt.Errorf("failed to count %s: %w", column.Name(), err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var keyID string
+		var count int64
+		if err := rows.Scan(&keyID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan %s count: %w", column.Name(), err)
+		}
+		counts[keyID] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return counts, nil
+}
+
+// Helper functions
+
+func ident(name string) string {
+	return pgx.Identifier{name}.Sanitize()
+}
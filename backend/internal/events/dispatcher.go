@@ -0,0 +1,172 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/jobs"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	claimBatchSize = 50
+
+	// Events not finished within the lease are picked up again
+	deliveryLease = 5 * time.Minute
+	dispatchedTTL = 7 * 24 * time.Hour
+)
+
+// On adapts a function taking a typed payload into a Subscriber
+func On[T any](fn func(ctx context.Context, payload T) error) Subscriber {
+	return func(ctx context.Context, event *Event) error {
+		var payload T
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to decode %s payload: %w", event.EventType, err)
+		}
+		return fn(ctx, payload)
+	}
+}
+
+type subscription struct {
+	name string
+	fn   Subscriber
+}
+
+// Dispatcher delivers outbox events to the in-process subscribers. Each
+// subscriber's success is recorded, so a retry only re-runs the ones that failed.
+type Dispatcher struct {
+	store        Store
+	pollInterval time.Duration
+
+	mu            sync.RWMutex
+	subscriptions map[string][]subscription
+}
+
+func NewDispatcher(store Store, pollInterval time.Duration) *Dispatcher {
+	return &Dispatcher{
+		store:         store,
+		pollInterval:  pollInterval,
+		subscriptions: make(map[string][]subscription),
+	}
+}
+
+// Subscribe registers fn for an event type. The name identifies the
+// subscriber in the outbox and must be unique and stable across deploys.
+func (d *Dispatcher) Subscribe(eventType, name string, fn Subscriber) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.subscriptions[eventType] = append(d.subscriptions[eventType], subscription{name: name, fn: fn})
+}
+
+// Start polls the outbox until ctx is cancelled
+func (d *Dispatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	purge := time.NewTicker(time.Hour)
+	defer purge.Stop()
+
+	for {
+		// Keep draining while there is a backlog
+		for ctx.Err() == nil {
+			dispatched, err := d.RunOnce(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.Error("Failed to dispatch events", zap.Error(err))
+				}
+				break
+			}
+			if dispatched < claimBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-purge.C:
+			if _, err := d.store.PurgeDispatched(ctx, time.Now().Add(-dispatchedTTL)); err != nil {
+				logger.Error("Failed to purge dispatched events", zap.Error(err))
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce claims and delivers one batch of due events
+func (d *Dispatcher) RunOnce(ctx context.Context) (int, error) {
+	events, err := d.store.Claim(ctx, claimBatchSize, deliveryLease)
+	if err != nil {
+		return 0, err
+	}
+
+	// Once claimed, finish the batch even if shutdown starts
+	deliverCtx := context.WithoutCancel(ctx)
+	for i := range events {
+		d.deliver(deliverCtx, &events[i])
+	}
+
+	return len(events), nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, event *Event) {
+	d.mu.RLock()
+	subscriptions := d.subscriptions[event.EventType]
+	d.mu.RUnlock()
+
+	delivered := append([]string{}, event.DeliveredTo...)
+	var failures []string
+	for _, sub := range subscriptions {
+		if slices.Contains(delivered, sub.name) {
+			continue
+		}
+
+		if err := callSubscriber(ctx, sub, event); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", sub.name, err))
+			continue
+		}
+		delivered = append(delivered, sub.name)
+	}
+
+	if len(failures) == 0 {
+		if err := d.store.MarkDispatched(ctx, event.ID, delivered); err != nil {
+			logger.Error("Failed to mark event dispatched", zap.String("event_id", event.ID), zap.Error(err))
+		}
+		return
+	}
+
+	var retryAt *time.Time
+	if event.Attempts < MaxAttempts {
+		next := time.Now().Add(jobs.Backoff(event.Attempts))
+		retryAt = &next
+	}
+
+	lastError := strings.Join(failures, "; ")
+	logger.Error("Event delivery failed",
+		zap.String("event_id", event.ID),
+		zap.String("event_type", event.EventType),
+		zap.Int("attempt", event.Attempts),
+		zap.Bool("dead", retryAt == nil),
+		zap.String("error", lastError),
+	)
+
+	if err := d.store.MarkFailed(ctx, event.ID, delivered, lastError, retryAt); err != nil {
+		logger.Error("Failed to record event failure", zap.String("event_id", event.ID), zap.Error(err))
+	}
+}
+
+func callSubscriber(ctx context.Context, sub subscription, event *Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("subscriber panicked: %v", r)
+		}
+	}()
+
+	return sub.fn(ctx, event)
+}
@@ -0,0 +1,229 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+)
+
+// outboxStore leases events in memory as the Postgres store does
+type outboxStore struct {
+	mu     sync.Mutex
+	events []*Event
+}
+
+func (s *outboxStore) add(eventType string, payload interface{}) *Event {
+	encoded, _ := json.Marshal(payload)
+	event := &Event{
+		ID:            eventType + "-1",
+		EventType:     eventType,
+		Payload:       encoded,
+		Status:        StatusPending,
+		NextAttemptAt: time.Now(),
+	}
+	s.events = append(s.events, event)
+	return event
+}
+
+func (s *outboxStore) find(eventID string) *Event {
+	for _, event := range s.events {
+		if event.ID == eventID {
+			return event
+		}
+	}
+	return nil
+}
+
+func (s *outboxStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var claimed []Event
+	for _, event := range s.events {
+		if len(claimed) == limit || event.Status != StatusPending || event.NextAttemptAt.After(now) {
+			continue
+		}
+		event.Attempts++
+		event.NextAttemptAt = now.Add(lease)
+		claimed = append(claimed, *event)
+	}
+	return claimed, nil
+}
+
+func (s *outboxStore) MarkDispatched(ctx context.Context, eventID string, deliveredTo []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	event := s.find(eventID)
+	event.Status, event.DeliveredTo, event.DispatchedAt = StatusDispatched, deliveredTo, &now
+	return nil
+}
+
+func (s *outboxStore) MarkFailed(ctx context.Context, eventID string, deliveredTo []string, lastError string, retryAt *time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event := s.find(eventID)
+	event.DeliveredTo, event.LastError = deliveredTo, &lastError
+	if retryAt == nil {
+		event.Status = StatusDead
+		return nil
+	}
+	event.NextAttemptAt = *retryAt
+	return nil
+}
+
+func (s *outboxStore) PurgeDispatched(ctx context.Context, dispatchedBefore time.Time) (int64, error) {
+	return 0, nil
+}
+
+// makeDue brings a rescheduled event forward so the next run picks it up
+func (s *outboxStore) makeDue(event *Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	event.NextAttemptAt = time.Now()
+}
+
+func TestDispatcherDeliversTypedPayloads(t *testing.T) {
+	logger.Init()
+	store := &outboxStore{}
+	event := store.add(UserDeactivated, UserDeactivatedPayload{UserID: "user-1"})
+	store.add(GroupJoined, GroupMembershipPayload{UserID: "user-1", GroupID: "group-1"})
+
+	dispatcher := NewDispatcher(store, time.Second)
+	var deactivated []string
+	for _, name := range []string{"careteam.end", "support_groups.end"} {
+		dispatcher.Subscribe(UserDeactivated, name, On(func(ctx context.Context, payload UserDeactivatedPayload) error {
+			deactivated = append(deactivated, payload.UserID)
+			return nil
+		}))
+	}
+
+	dispatched, err := dispatcher.RunOnce(context.Background())
+	if err != nil || dispatched != 2 {
+		t.Fatalf("RunOnce() = (%d, %v), want 2 events", dispatched, err)
+	}
+
+	if !reflect.DeepEqual(deactivated, []string{"user-1", "user-1"}) {
+		t.Errorf("subscribers saw %v, want user-1 once each", deactivated)
+	}
+	if event.Status != StatusDispatched || !reflect.DeepEqual(event.DeliveredTo, []string{"careteam.end", "support_groups.end"}) {
+		t.Errorf("event = %s delivered to %v, want dispatched to both subscribers", event.Status, event.DeliveredTo)
+	}
+	if joined := store.find(GroupJoined + "-1"); joined.Status != StatusDispatched {
+		t.Errorf("event without subscribers = %s, want dispatched", joined.Status)
+	}
+}
+
+func TestDispatcherRetriesOnlyFailedSubscribers(t *testing.T) {
+	logger.Init()
+	store := &outboxStore{}
+	event := store.add(ReferralCreated, ReferralCreatedPayload{ReferralID: "referral-1"})
+
+	dispatcher := NewDispatcher(store, time.Second)
+	calls := map[string]int{}
+	dispatcher.Subscribe(ReferralCreated, "notify", func(ctx context.Context, event *Event) error {
+		calls["notify"]++
+		return nil
+	})
+	dispatcher.Subscribe(ReferralCreated, "webhooks", func(ctx context.Context, event *Event) error {
+		calls["webhooks"]++
+		if calls["webhooks"] == 1 {
+			return errors.New("queue unavailable")
+		}
+		return nil
+	})
+	dispatcher.Subscribe(ReferralCreated, "flaky", func(ctx context.Context, event *Event) error {
+		calls["flaky"]++
+		if calls["flaky"] == 1 {
+			panic("nil map")
+		}
+		return nil
+	})
+
+	ctx := context.Background()
+	if _, err := dispatcher.RunOnce(ctx); err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if event.Status != StatusPending || !reflect.DeepEqual(event.DeliveredTo, []string{"notify"}) {
+		t.Fatalf("event after a failure = %s delivered to %v, want pending with notify done", event.Status, event.DeliveredTo)
+	}
+	if !event.NextAttemptAt.After(time.Now()) || event.LastError == nil || !strings.Contains(*event.LastError, "webhooks: queue unavailable") || !strings.Contains(*event.LastError, "flaky: subscriber panicked") {
+		t.Errorf("event after a failure = next %v, error %v, want a later retry naming both failures", event.NextAttemptAt, event.LastError)
+	}
+
+	// Not yet due, so nothing is claimed
+	if dispatched, _ := dispatcher.RunOnce(ctx); dispatched != 0 {
+		t.Errorf("RunOnce() before the retry is due = %d events, want 0", dispatched)
+	}
+
+	store.makeDue(event)
+	if _, err := dispatcher.RunOnce(ctx); err != nil {
+		t.Fatalf("RunOnce() retry error = %v", err)
+	}
+	if event.Status != StatusDispatched {
+		t.Errorf("event after the retry = %s, want dispatched", event.Status)
+	}
+	if want := map[string]int{"notify": 1, "webhooks": 2, "flaky": 2}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("subscriber calls = %v, want %v", calls, want)
+	}
+}
+
+func TestDispatcherDeadLettersAfterMaxAttempts(t *testing.T) {
+	logger.Init()
+	store := &outboxStore{}
+	event := store.add(JourneyEntryCreated, JourneyEntryCreatedPayload{EntryID: "entry-1"})
+
+	dispatcher := NewDispatcher(store, time.Second)
+	dispatcher.Subscribe(JourneyEntryCreated, "milestones", func(ctx context.Context, event *Event) error {
+		return errors.New("always fails")
+	})
+
+	for attempt := 1; attempt <= MaxAttempts; attempt++ {
+		store.makeDue(event)
+		if dispatched, _ := dispatcher.RunOnce(context.Background()); dispatched != 1 {
+			t.Fatalf("attempt %d claimed %d events, want 1", attempt, dispatched)
+		}
+		if attempt < MaxAttempts && event.Status != StatusPending {
+			t.Fatalf("event after attempt %d = %s, want pending", attempt, event.Status)
+		}
+	}
+
+	if event.Status != StatusDead || event.Attempts != MaxAttempts {
+		t.Errorf("event = %s after %d attempts, want dead after %d", event.Status, event.Attempts, MaxAttempts)
+	}
+}
+
+// recordingExecer captures the statement Publish runs inside the caller's transaction
+type recordingExecer struct {
+	args []interface{}
+}
+
+func (e *recordingExecer) Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+	e.args = arguments
+	return pgconn.CommandTag{}, nil
+}
+
+func TestPublishEncodesPayload(t *testing.T) {
+	tx := &recordingExecer{}
+	if err := Publish(context.Background(), tx, GroupLeft, "group-1", GroupMembershipPayload{UserID: "user-1", GroupID: "group-1"}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	if len(tx.args) != 3 || tx.args[0] != GroupLeft || tx.args[1] != "group-1" {
+		t.Fatalf("Publish() args = %v", tx.args)
+	}
+	if payload := string(tx.args[2].([]byte)); payload != `{"user_id":"user-1","group_id":"group-1"}` {
+		t.Errorf("Publish() payload = %s", payload)
+	}
+}
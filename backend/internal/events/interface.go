@@ -0,0 +1,28 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Execer is satisfied by pgx.Tx and pgxpool.Pool. Stores pass their
+// transaction so the event is only recorded if the state change commits.
+type Execer interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+}
+
+// Subscriber reacts to an event. Delivery is at least once, so subscribers
+// must tolerate seeing the same event more than once.
+type Subscriber func(ctx context.Context, event *Event) error
+
+// Store defines the interface for outbox persistence used by the dispatcher
+type Store interface {
+	// Claim leases up to limit due events; they become due again if the lease expires undelivered
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]Event, error)
+	MarkDispatched(ctx context.Context, eventID string, deliveredTo []string) error
+	// MarkFailed records progress and reschedules the event at retryAt, or dead-letters it when retryAt is nil
+	MarkFailed(ctx context.Context, eventID string, deliveredTo []string, lastError string, retryAt *time.Time) error
+	PurgeDispatched(ctx context.Context, dispatchedBefore time.Time) (int64, error)
+}
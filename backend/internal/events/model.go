@@ -0,0 +1,88 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Domain event types. Payloads carry IDs and state, never free text or contact details.
+const (
+	ReferralCreated       = "referral.created"
+	ReferralStatusChanged = "referral.status_changed"
+	UserRegistered        = "user.registered"
+	UserDeactivated       = "user.deactivated"
+	GroupJoined           = "support_group.member_joined"
+	GroupLeft             = "support_group.member_left"
+	JourneyEntryCreated   = "journey.entry_created"
+)
+
+// Outbox statuses
+const (
+	StatusPending    = "pending"
+	StatusDispatched = "dispatched"
+	StatusDead       = "dead"
+)
+
+// MaxAttempts is how many times delivery is tried before an event is dead-lettered
+const MaxAttempts = 10
+
+// Event is a row in the outbox
+type Event struct {
+	ID            string          `json:"id" db:"id"`
+	EventType     string          `json:"event_type" db:"event_type"`
+	AggregateID   string          `json:"aggregate_id" db:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload" db:"payload"`
+	Status        string          `json:"status" db:"status"`
+	Attempts      int             `json:"attempts" db:"attempts"`
+	DeliveredTo   []string        `json:"delivered_to" db:"delivered_to"`
+	NextAttemptAt time.Time       `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError     *string         `json:"last_error,omitempty" db:"last_error"`
+	DispatchedAt  *time.Time      `json:"dispatched_at,omitempty" db:"dispatched_at"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// ReferralCreatedPayload is published when a referral is made
+type ReferralCreatedPayload struct {
+	ReferralID   string `json:"referral_id"`
+	ReferredBy   string `json:"referred_by"`
+	ReferredTo   string `json:"referred_to"`
+	ReferralType string `json:"referral_type"`
+	ItemID       string `json:"item_id"`
+	IsUrgent     bool   `json:"is_urgent"`
+}
+
+// ReferralStatusChangedPayload is published when a referral moves to a new status
+type ReferralStatusChangedPayload struct {
+	ReferralID     string `json:"referral_id"`
+	ReferredBy     string `json:"referred_by"`
+	ReferredTo     string `json:"referred_to"`
+	ReferralType   string `json:"referral_type"`
+	ItemID         string `json:"item_id"`
+	PreviousStatus string `json:"previous_status"`
+	Status         string `json:"status"`
+}
+
+// UserRegisteredPayload is published when an account is created
+type UserRegisteredPayload struct {
+	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+// UserDeactivatedPayload is published when an account is deactivated
+type UserDeactivatedPayload struct {
+	UserID string `json:"user_id"`
+}
+
+// GroupMembershipPayload is published when a user joins or leaves a support group
+type GroupMembershipPayload struct {
+	UserID  string `json:"user_id"`
+	GroupID string `json:"group_id"`
+}
+
+// JourneyEntryCreatedPayload is published when a journey entry is recorded
+type JourneyEntryCreatedPayload struct {
+	EntryID   string `json:"entry_id"`
+	UserID    string `json:"user_id"`
+	EntryDate string `json:"entry_date"`
+}
@@ -0,0 +1,25 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Publish writes an event to the outbox using the caller's transaction
+func Publish(ctx context.Context, tx Execer, eventType, aggregateID string, payload interface{}) error {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO outbox_events (event_type, aggregate_id, payload)
+		VALUES ($1, $2, $3)
+	`, eventType, aggregateID, encoded)
+	if err != nil {
+		return fmt.Errorf("failed to record %s event: %w", eventType, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type store struct {
+	db *pgxpool.Pool
+}
+
+func NewStore(db *pgxpool.Pool) Store {
+	return &store{
+		db: db,
+	}
+}
+
+// Claim pushes next_attempt_at past the lease so other dispatchers skip the
+// events while they are being delivered
+func (s *store) Claim(ctx context.Context, limit int, lease time.Duration) ([]Event, error) {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, next_attempt_at = $1
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE status = $2 AND next_attempt_at <= NOW()
+			ORDER BY created_at ASC, id ASC
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_type, aggregate_id, payload, status, attempts, delivered_to,
+		          next_attempt_at, last_error, dispatched_at, created_at, updated_at
+	`
+
+	rows, err := s.db.Query(ctx, query, time.Now().Add(lease), StatusPending, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		var event Event
+		err := rows.Scan(
+			&event.ID, &event.EventType, &event.AggregateID, &event.Payload, &event.Status,
+			&event.Attempts, &event.DeliveredTo, &event.NextAttemptAt, &event.LastError,
+			&event.DispatchedAt, &event.CreatedAt, &event.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	// RETURNING does not preserve the subquery order
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].CreatedAt.Equal(events[j].CreatedAt) {
+			return events[i].ID < events[j].ID
+		}
+		return events[i].CreatedAt.Before(events[j].CreatedAt)
+	})
+
+	return events, nil
+}
+
+// MarkDispatched records that every subscriber has handled the event
+func (s *store) MarkDispatched(ctx context.Context, eventID string, deliveredTo []string) error {
+	_, err := s.db.Exec(ctx, `
+		UPDATE outbox_events
+		SET status = $1, delivered_to = $2, dispatched_at = NOW(), last_error = NULL
+		WHERE id = $3
+	`, StatusDispatched, deliveredTo, eventID)
+	if err != nil {
+		return fmt.Errorf("failed to mark event dispatched: %w", err)
+	}
+
+	return nil
+}
+
+// MarkFailed keeps the subscribers that succeeded so a retry only re-runs the rest
+func (s *store) MarkFailed(ctx context.Context, eventID string, deliveredTo []string, lastError string, retryAt *time.Time) error {
+	status := StatusDead
+	nextAttempt := time.Now()
+	if retryAt != nil {
+		status = StatusPending
+		nextAttempt = *retryAt
+	}
+
+	_, err := s.db.Exec(ctx, `
+		UPDATE outbox_events
+		SET status = $1, delivered_to = $2, next_attempt_at = $3, last_error = $4
+		WHERE id = $5
+	`, status, deliveredTo, nextAttempt, lastError, eventID)
+	if err != nil {
+		return fmt.Errorf("failed to record event failure: %w", err)
+	}
+
+	return nil
+}
+
+// PurgeDispatched deletes delivered events older than the cutoff
+func (s *store) PurgeDispatched(ctx context.Context, dispatchedBefore time.Time) (int64, error) {
+	result, err := s.db.Exec(ctx, `
+		DELETE FROM outbox_events WHERE status = $1 AND dispatched_at < $2
+	`, StatusDispatched, dispatchedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge dispatched events: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -1,24 +1,27 @@
 package feedback
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
 )
 
-type Handler struct {
-	service FeedbackServiceInterface
+type handler struct {
+	service Service
 }
 
-func NewHandler(service FeedbackServiceInterface) *Handler {
-	return &Handler{
+func NewHandler(service Service) Handler {
+	return &handler{
 		service: service,
 	}
 }
 
 // CreateFeedback creates new feedback
-func (h *Handler) CreateFeedback(c echo.Context) error {
+func (h *handler) CreateFeedback(c echo.Context) error {
 	var req CreateFeedbackRequest
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
@@ -45,7 +48,7 @@ func (h *Handler) CreateFeedback(c echo.Context) error {
 }
 
 // ListFeedback retrieves a paginated list of feedback (admin only)
-func (h *Handler) ListFeedback(c echo.Context) error {
+func (h *handler) ListFeedback(c echo.Context) error {
 	// Parse query parameters
 	page := 1
 	if p := c.QueryParam("page"); p != "" {
@@ -63,8 +66,18 @@ func (h *Handler) ListFeedback(c echo.Context) error {
 
 	category := c.QueryParam("category")
 	rating := c.QueryParam("rating")
+	scope := organisations.ScopeFromContext(c)
 
-	feedback, err := h.service.ListFeedback(c.Request().Context(), page, pageSize, category, rating)
+	filter := &FeedbackFilter{
+		Category: category,
+		Rating:   rating,
+		Scope:    &scope,
+		Page:     page,
+		PageSize: pageSize,
+		Params:   pagination.ParseQuery(c),
+	}
+
+	feedback, err := h.service.ListFeedback(c.Request().Context(), filter)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": err.Error(),
@@ -75,8 +88,8 @@ func (h *Handler) ListFeedback(c echo.Context) error {
 }
 
 // GetFeedbackStats retrieves feedback statistics (admin only)
-func (h *Handler) GetFeedbackStats(c echo.Context) error {
-	stats, err := h.service.GetFeedbackStats(c.Request().Context())
+func (h *handler) GetFeedbackStats(c echo.Context) error {
+	stats, err := h.service.GetFeedbackStats(c.Request().Context(), organisations.ScopeFromContext(c))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": err.Error(),
@@ -87,7 +100,7 @@ func (h *Handler) GetFeedbackStats(c echo.Context) error {
 }
 
 // GetFeedback retrieves a single feedback by ID (admin only)
-func (h *Handler) GetFeedback(c echo.Context) error {
+func (h *handler) GetFeedback(c echo.Context) error {
 	feedbackID := c.Param("id")
 	if feedbackID == "" {
 		return c.JSON(http.StatusBadRequest, map[string]string{
@@ -95,7 +108,12 @@ func (h *Handler) GetFeedback(c echo.Context) error {
 		})
 	}
 
-	feedback, err := h.service.GetFeedbackByID(c.Request().Context(), feedbackID)
+	feedback, err := h.service.GetFeedbackByID(c.Request().Context(), organisations.ScopeFromContext(c), feedbackID)
+	if errors.Is(err, organisations.ErrOutOfScope) {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": err.Error(),
+		})
+	}
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{
 			"error": err.Error(),
@@ -106,7 +124,7 @@ func (h *Handler) GetFeedback(c echo.Context) error {
 }
 
 // UpdateFeedbackStatus updates the status of feedback (admin only)
-func (h *Handler) UpdateFeedbackStatus(c echo.Context) error {
+func (h *handler) UpdateFeedbackStatus(c echo.Context) error {
 	feedbackID := c.Param("id")
 	if feedbackID == "" {
 		return c.JSON(http.StatusBadRequest, map[string]string{
@@ -137,7 +155,7 @@ func (h *Handler) UpdateFeedbackStatus(c echo.Context) error {
 }
 
 // GetUserFeedback retrieves feedback for the current user
-func (h *Handler) GetUserFeedback(c echo.Context) error {
+func (h *handler) GetUserFeedback(c echo.Context) error {
 	userID := getUserIDFromContext(c)
 	if userID == "" {
 		return c.JSON(http.StatusUnauthorized, map[string]string{
@@ -160,7 +178,13 @@ func (h *Handler) GetUserFeedback(c echo.Context) error {
 		}
 	}
 
-	feedback, err := h.service.GetUserFeedback(c.Request().Context(), userID, page, pageSize)
+	filter := &FeedbackFilter{
+		Page:     page,
+		PageSize: pageSize,
+		Params:   pagination.ParseQuery(c),
+	}
+
+	feedback, err := h.service.GetUserFeedback(c.Request().Context(), userID, filter)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": err.Error(),
@@ -2,25 +2,38 @@ package feedback
 
 import (
 	"context"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
 )
 
-// FeedbackStoreInterface defines the interface for feedback storage operations
-type FeedbackStoreInterface interface {
+// Service defines the interface for feedback business logic
+type Service interface {
 	CreateFeedback(ctx context.Context, req *CreateFeedbackRequest, userID *string) (*Feedback, error)
-	ListFeedback(ctx context.Context, page, pageSize int, category, rating string) (*ListFeedbackResponse, error)
-	GetFeedbackStats(ctx context.Context) (*FeedbackStats, error)
-	GetFeedbackByID(ctx context.Context, feedbackID string) (*Feedback, error)
+	ListFeedback(ctx context.Context, filter *FeedbackFilter) (*ListFeedbackResponse, error)
+	GetFeedbackStats(ctx context.Context, scope organisations.Scope) (*FeedbackStats, error)
+	GetFeedbackByID(ctx context.Context, scope organisations.Scope, feedbackID string) (*Feedback, error)
 	UpdateFeedbackStatus(ctx context.Context, feedbackID string, isActive bool) error
-	GetUserFeedback(ctx context.Context, userID string, page, pageSize int) (*ListFeedbackResponse, error)
+	GetUserFeedback(ctx context.Context, userID string, filter *FeedbackFilter) (*ListFeedbackResponse, error)
+	ValidateFeedbackRequest(req *CreateFeedbackRequest) error
 }
 
-// FeedbackServiceInterface defines the interface for feedback business logic
-type FeedbackServiceInterface interface {
+// Store defines the interface for feedback data persistence
+type Store interface {
 	CreateFeedback(ctx context.Context, req *CreateFeedbackRequest, userID *string) (*Feedback, error)
-	ListFeedback(ctx context.Context, page, pageSize int, category, rating string) (*ListFeedbackResponse, error)
-	GetFeedbackStats(ctx context.Context) (*FeedbackStats, error)
+	ListFeedback(ctx context.Context, filter *FeedbackFilter) (*ListFeedbackResponse, error)
+	GetFeedbackStats(ctx context.Context, scope organisations.Scope) (*FeedbackStats, error)
 	GetFeedbackByID(ctx context.Context, feedbackID string) (*Feedback, error)
 	UpdateFeedbackStatus(ctx context.Context, feedbackID string, isActive bool) error
-	GetUserFeedback(ctx context.Context, userID string, page, pageSize int) (*ListFeedbackResponse, error)
-	ValidateFeedbackRequest(req *CreateFeedbackRequest) error
+	GetUserFeedback(ctx context.Context, userID string, filter *FeedbackFilter) (*ListFeedbackResponse, error)
+}
+
+// Handler defines the interface for feedback HTTP handlers
+type Handler interface {
+	CreateFeedback(c echo.Context) error
+	ListFeedback(c echo.Context) error
+	GetFeedbackStats(c echo.Context) error
+	GetFeedback(c echo.Context) error
+	UpdateFeedbackStatus(c echo.Context) error
+	GetUserFeedback(c echo.Context) error
 }
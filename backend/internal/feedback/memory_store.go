@@ -0,0 +1,197 @@
+package feedback
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
+)
+
+// memoryStore keeps feedback in memory for tests and demo mode
+type memoryStore struct {
+	mu       sync.RWMutex
+	feedback map[string]Feedback
+}
+
+func NewMemoryStore() Store {
+	return &memoryStore{
+		feedback: make(map[string]Feedback),
+	}
+}
+
+// CreateFeedback creates new feedback
+func (s *memoryStore) CreateFeedback(ctx context.Context, req *CreateFeedbackRequest, userID *string) (*Feedback, error) {
+	now := time.Now()
+	feedback := Feedback{
+		ID:             uuid.New().String(),
+		UserID:         userID,
+		Anonymous:      req.Anonymous,
+		Rating:         req.Rating,
+		Message:        req.Message,
+		Category:       req.Category,
+		OrganisationID: req.OrganisationID,
+		IsActive:       true,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	s.mu.Lock()
+	s.feedback[feedback.ID] = feedback
+	s.mu.Unlock()
+
+	return &feedback, nil
+}
+
+// ListFeedback retrieves a paginated list of active feedback with filters, newest first
+func (s *memoryStore) ListFeedback(ctx context.Context, filter *FeedbackFilter) (*ListFeedbackResponse, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 || filter.PageSize > 100 {
+		filter.PageSize = 20
+	}
+
+	cursor, err := filter.DecodeCursor()
+	if err != nil {
+		return nil, err
+	}
+
+	offset := 0
+	if cursor == nil {
+		offset = (filter.Page - 1) * filter.PageSize
+	}
+
+	matched := s.active(func(feedback Feedback) bool {
+		if filter.Category != "" && feedback.Category != filter.Category {
+			return false
+		}
+		if filter.Rating != "" && feedback.Rating != filter.Rating {
+			return false
+		}
+		if filter.Anonymous != nil && feedback.Anonymous != *filter.Anonymous {
+			return false
+		}
+		if filter.UserID != "" && (feedback.UserID == nil || *feedback.UserID != filter.UserID) {
+			return false
+		}
+		if filter.StartDate != nil && feedback.CreatedAt.Before(*filter.StartDate) {
+			return false
+		}
+		if filter.EndDate != nil && feedback.CreatedAt.After(*filter.EndDate) {
+			return false
+		}
+		return filter.Scope == nil || filter.Scope.Covers(feedback.OrganisationID)
+	})
+
+	response := &ListFeedbackResponse{
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	}
+
+	if filter.WantTotal() {
+		total := int64(len(matched))
+		totalPages := pagination.TotalPages(total, filter.PageSize)
+		response.Total = &total
+		response.TotalPages = &totalPages
+	}
+
+	window := pagination.Window(matched, cursor, offset, filter.PageSize, feedbackCursor)
+	response.Feedback, response.NextCursor, response.PrevCursor = pagination.Paginate(window, filter.PageSize, cursor, offset, feedbackCursor)
+
+	return response, nil
+}
+
+// GetFeedbackStats retrieves comprehensive feedback statistics
+func (s *memoryStore) GetFeedbackStats(ctx context.Context, scope organisations.Scope) (*FeedbackStats, error) {
+	stats := &FeedbackStats{
+		RatingBreakdown:   make(map[string]int64),
+		CategoryBreakdown: make(map[string]int64),
+	}
+
+	all := s.active(func(feedback Feedback) bool { return scope.Covers(feedback.OrganisationID) })
+
+	var totalRatingValue int64
+	for _, feedback := range all {
+		stats.TotalFeedback++
+		if feedback.Anonymous {
+			stats.TotalAnonymous++
+		} else {
+			stats.TotalAuthenticated++
+		}
+		stats.RatingBreakdown[feedback.Rating]++
+		stats.CategoryBreakdown[feedback.Category]++
+		totalRatingValue += int64(GetRatingValue(feedback.Rating))
+	}
+
+	if stats.TotalFeedback > 0 {
+		stats.AverageRating = float64(totalRatingValue) / float64(stats.TotalFeedback)
+	}
+
+	if len(all) > 10 {
+		all = all[:10]
+	}
+	stats.RecentFeedback = all
+
+	return stats, nil
+}
+
+// GetFeedbackByID retrieves a single active feedback by ID
+func (s *memoryStore) GetFeedbackByID(ctx context.Context, feedbackID string) (*Feedback, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	feedback, ok := s.feedback[feedbackID]
+	if !ok || !feedback.IsActive {
+		return nil, fmt.Errorf("feedback not found")
+	}
+
+	return &feedback, nil
+}
+
+// UpdateFeedbackStatus updates the status of a feedback (for admin use)
+func (s *memoryStore) UpdateFeedbackStatus(ctx context.Context, feedbackID string, isActive bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feedback, ok := s.feedback[feedbackID]
+	if !ok {
+		return fmt.Errorf("feedback not found")
+	}
+
+	feedback.IsActive = isActive
+	feedback.UpdatedAt = time.Now()
+	s.feedback[feedbackID] = feedback
+
+	return nil
+}
+
+// GetUserFeedback retrieves feedback submitted by a specific user
+func (s *memoryStore) GetUserFeedback(ctx context.Context, userID string, filter *FeedbackFilter) (*ListFeedbackResponse, error) {
+	filter.UserID = userID
+	return s.ListFeedback(ctx, filter)
+}
+
+// Helper functions
+
+// active returns the active feedback that matches, newest first
+func (s *memoryStore) active(match func(Feedback) bool) []Feedback {
+	s.mu.RLock()
+	var feedbacks []Feedback
+	for _, feedback := range s.feedback {
+		if feedback.IsActive && match(feedback) {
+			feedbacks = append(feedbacks, feedback)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(feedbacks, func(i, j int) bool {
+		return pagination.Compare(feedbackCursor(feedbacks[i]), feedbackCursor(feedbacks[j])) > 0
+	})
+
+	return feedbacks
+}
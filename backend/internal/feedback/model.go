@@ -2,19 +2,23 @@ package feedback
 
 import (
 	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
 )
 
 // Feedback represents user feedback
 type Feedback struct {
-	ID        string    `json:"id" db:"id"`
-	UserID    *string   `json:"user_id,omitempty" db:"user_id"`
-	Anonymous bool      `json:"anonymous" db:"anonymous"`
-	Rating    string    `json:"rating" db:"rating"`
-	Message   string    `json:"feedback" db:"feedback"`
-	Category  string    `json:"category" db:"category"`
-	IsActive  bool      `json:"is_active" db:"is_active"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID             string    `json:"id" db:"id"`
+	UserID         *string   `json:"user_id,omitempty" db:"user_id"`
+	Anonymous      bool      `json:"anonymous" db:"anonymous"`
+	Rating         string    `json:"rating" db:"rating"`
+	Message        string    `json:"feedback" db:"feedback"`
+	Category       string    `json:"category" db:"category"`
+	OrganisationID *string   `json:"organisation_id,omitempty" db:"organisation_id"`
+	IsActive       bool      `json:"is_active" db:"is_active"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
 }
 
 // FeedbackRating represents valid rating values
@@ -46,6 +50,8 @@ type CreateFeedbackRequest struct {
 	Rating    string `json:"rating" validate:"required"`
 	Message   string `json:"feedback" validate:"required,min=10,max=1000"`
 	Category  string `json:"category" validate:"required,oneof=general app_usability services support bug_report feature_request"`
+	// OrganisationID is set when the feedback is about a particular organisation's service or group
+	OrganisationID *string `json:"organisation_id,omitempty"`
 }
 
 // UpdateFeedbackRequest represents the request to update feedback
@@ -59,10 +65,12 @@ type UpdateFeedbackRequest struct {
 // ListFeedbackResponse represents the response for listing feedback
 type ListFeedbackResponse struct {
 	Feedback   []Feedback `json:"feedback"`
-	Total      int64      `json:"total"`
+	Total      *int64     `json:"total,omitempty"`
 	Page       int        `json:"page"`
 	PageSize   int        `json:"page_size"`
-	TotalPages int        `json:"total_pages"`
+	TotalPages *int       `json:"total_pages,omitempty"`
+	NextCursor *string    `json:"next_cursor,omitempty"`
+	PrevCursor *string    `json:"prev_cursor,omitempty"`
 }
 
 // FeedbackStats represents feedback statistics
@@ -85,8 +93,11 @@ type FeedbackFilter struct {
 	IsActive  *bool      `json:"is_active,omitempty"`
 	StartDate *time.Time `json:"start_date,omitempty"`
 	EndDate   *time.Time `json:"end_date,omitempty"`
-	Page      int        `json:"page"`
-	PageSize  int        `json:"page_size"`
+	// Scope limits admin listings to the caller's organisations; nil means unscoped
+	Scope    *organisations.Scope `json:"-"`
+	Page     int                  `json:"page"`
+	PageSize int                  `json:"page_size"`
+	pagination.Params
 }
 
 // FeedbackSummary represents a summary of feedback for dashboard
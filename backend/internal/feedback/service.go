@@ -4,20 +4,22 @@ import (
 	"context"
 	"fmt"
 	"strings"
+
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
 )
 
-type Service struct {
-	store FeedbackStoreInterface
+type service struct {
+	store Store
 }
 
-func NewService(store FeedbackStoreInterface) *Service {
-	return &Service{
+func NewService(store Store) Service {
+	return &service{
 		store: store,
 	}
 }
 
 // CreateFeedback creates new feedback
-func (s *Service) CreateFeedback(ctx context.Context, req *CreateFeedbackRequest, userID *string) (*Feedback, error) {
+func (s *service) CreateFeedback(ctx context.Context, req *CreateFeedbackRequest, userID *string) (*Feedback, error) {
 	// Validate the request
 	if err := s.ValidateFeedbackRequest(req); err != nil {
 		return nil, err
@@ -37,38 +39,47 @@ func (s *Service) CreateFeedback(ctx context.Context, req *CreateFeedbackRequest
 }
 
 // ListFeedback retrieves a paginated list of feedback
-func (s *Service) ListFeedback(ctx context.Context, page, pageSize int, category, rating string) (*ListFeedbackResponse, error) {
-	if page < 1 {
-		page = 1
+func (s *service) ListFeedback(ctx context.Context, filter *FeedbackFilter) (*ListFeedbackResponse, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
 	}
-	if pageSize < 1 || pageSize > 100 {
-		pageSize = 20
+	if filter.PageSize < 1 || filter.PageSize > 100 {
+		filter.PageSize = 20
 	}
 
 	// Validate rating if provided
-	if rating != "" && !isValidRating(rating) {
-		return nil, fmt.Errorf("invalid rating filter: %s", rating)
+	if filter.Rating != "" && !isValidRating(filter.Rating) {
+		return nil, fmt.Errorf("invalid rating filter: %s", filter.Rating)
 	}
 
-	return s.store.ListFeedback(ctx, page, pageSize, category, rating)
+	return s.store.ListFeedback(ctx, filter)
 }
 
-// GetFeedbackStats retrieves feedback statistics
-func (s *Service) GetFeedbackStats(ctx context.Context) (*FeedbackStats, error) {
-	return s.store.GetFeedbackStats(ctx)
+// GetFeedbackStats retrieves statistics for feedback within the caller's scope
+func (s *service) GetFeedbackStats(ctx context.Context, scope organisations.Scope) (*FeedbackStats, error) {
+	return s.store.GetFeedbackStats(ctx, scope)
 }
 
-// GetFeedbackByID retrieves a single feedback by ID
-func (s *Service) GetFeedbackByID(ctx context.Context, feedbackID string) (*Feedback, error) {
+// GetFeedbackByID retrieves a single feedback by ID within the caller's scope
+func (s *service) GetFeedbackByID(ctx context.Context, scope organisations.Scope, feedbackID string) (*Feedback, error) {
 	if feedbackID == "" {
 		return nil, fmt.Errorf("feedback ID is required")
 	}
 
-	return s.store.GetFeedbackByID(ctx, feedbackID)
+	feedback, err := s.store.GetFeedbackByID(ctx, feedbackID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !scope.Covers(feedback.OrganisationID) {
+		return nil, organisations.ErrOutOfScope
+	}
+
+	return feedback, nil
 }
 
 // UpdateFeedbackStatus updates the status of a feedback (admin operation)
-func (s *Service) UpdateFeedbackStatus(ctx context.Context, feedbackID string, isActive bool) error {
+func (s *service) UpdateFeedbackStatus(ctx context.Context, feedbackID string, isActive bool) error {
 	if feedbackID == "" {
 		return fmt.Errorf("feedback ID is required")
 	}
@@ -77,23 +88,23 @@ func (s *Service) UpdateFeedbackStatus(ctx context.Context, feedbackID string, i
 }
 
 // GetUserFeedback retrieves feedback submitted by a specific user
-func (s *Service) GetUserFeedback(ctx context.Context, userID string, page, pageSize int) (*ListFeedbackResponse, error) {
+func (s *service) GetUserFeedback(ctx context.Context, userID string, filter *FeedbackFilter) (*ListFeedbackResponse, error) {
 	if userID == "" {
 		return nil, fmt.Errorf("user ID is required")
 	}
 
-	if page < 1 {
-		page = 1
+	if filter.Page < 1 {
+		filter.Page = 1
 	}
-	if pageSize < 1 || pageSize > 100 {
-		pageSize = 20
+	if filter.PageSize < 1 || filter.PageSize > 100 {
+		filter.PageSize = 20
 	}
 
-	return s.store.GetUserFeedback(ctx, userID, page, pageSize)
+	return s.store.GetUserFeedback(ctx, userID, filter)
 }
 
 // ValidateFeedbackRequest validates the feedback request
-func (s *Service) ValidateFeedbackRequest(req *CreateFeedbackRequest) error {
+func (s *service) ValidateFeedbackRequest(req *CreateFeedbackRequest) error {
 	if req == nil {
 		return fmt.Errorf("feedback request is required")
 	}
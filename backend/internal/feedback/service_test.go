@@ -0,0 +1,124 @@
+package feedback
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+)
+
+const (
+	leedsTrust = "4b1d9a7e-1c2f-4e3a-8b5d-6f7a8b9c0d1e"
+	yorkTrust  = "9e8d7c6b-5a4f-4e3d-8c2b-1a0f9e8d7c6b"
+)
+
+var leedsScope = organisations.Scope{OrganisationIDs: []string{leedsTrust}}
+
+func newFeedback(rating string, organisationID *string) *CreateFeedbackRequest {
+	return &CreateFeedbackRequest{
+		Rating:         rating,
+		Message:        "The group sessions really helped",
+		Category:       "services",
+		OrganisationID: organisationID,
+	}
+}
+
+func TestCreateFeedbackValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(req *CreateFeedbackRequest)
+		wantErr string
+	}{
+		{"valid", func(req *CreateFeedbackRequest) {}, ""},
+		{"message too short", func(req *CreateFeedbackRequest) { req.Message = " thanks   " }, "at least 10 characters"},
+		{"message too long", func(req *CreateFeedbackRequest) { req.Message = strings.Repeat("a", 1001) }, "less than 1000 characters"},
+		{"unknown category", func(req *CreateFeedbackRequest) { req.Category = "complaint" }, "category"},
+		{"unknown rating", func(req *CreateFeedbackRequest) { req.Rating = "Excellent" }, "invalid rating"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(NewMemoryStore())
+			req := newFeedback(string(RatingSatisfied), nil)
+			tt.change(req)
+
+			_, err := svc.CreateFeedback(context.Background(), req, nil)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("CreateFeedback() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("CreateFeedback() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAnonymousFeedbackDropsUser(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(NewMemoryStore())
+	userID := "7d0c4a4e-2f7b-4b8e-9a51-3f6c1d2e8a10"
+
+	req := newFeedback(string(RatingNeutral), nil)
+	req.Anonymous = true
+	created, err := svc.CreateFeedback(ctx, req, &userID)
+	if err != nil {
+		t.Fatalf("CreateFeedback() error = %v", err)
+	}
+	if created.UserID != nil {
+		t.Errorf("anonymous feedback kept user %s", *created.UserID)
+	}
+
+	if mine, _ := svc.GetUserFeedback(ctx, userID, &FeedbackFilter{}); len(mine.Feedback) != 0 {
+		t.Errorf("GetUserFeedback() = %v, want anonymous feedback left out", mine.Feedback)
+	}
+}
+
+func TestFeedbackReadsStayWithinScope(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(NewMemoryStore())
+	leeds, york := leedsTrust, yorkTrust
+
+	var yorkFeedback *Feedback
+	for _, req := range []*CreateFeedbackRequest{
+		newFeedback(string(RatingVerySatisfied), &leeds),
+		newFeedback(string(RatingSatisfied), &leeds),
+		newFeedback(string(RatingVeryDissatisfied), &york),
+		newFeedback(string(RatingNeutral), nil),
+	} {
+		created, err := svc.CreateFeedback(ctx, req, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if created.OrganisationID != nil && *created.OrganisationID == yorkTrust {
+			yorkFeedback = created
+		}
+	}
+
+	stats, err := svc.GetFeedbackStats(ctx, leedsScope)
+	if err != nil {
+		t.Fatalf("GetFeedbackStats() error = %v", err)
+	}
+	if stats.TotalFeedback != 2 || len(stats.RecentFeedback) != 2 || stats.AverageRating != 4.5 {
+		t.Errorf("GetFeedbackStats() for Leeds = %+v, want its 2 entries averaging 4.5", stats)
+	}
+	for _, recent := range stats.RecentFeedback {
+		if recent.OrganisationID == nil || *recent.OrganisationID != leedsTrust {
+			t.Errorf("recent feedback for Leeds includes %+v", recent)
+		}
+	}
+
+	if all, _ := svc.GetFeedbackStats(ctx, organisations.Scope{All: true}); all.TotalFeedback != 4 {
+		t.Errorf("GetFeedbackStats() unscoped = %d entries, want 4", all.TotalFeedback)
+	}
+
+	if _, err := svc.GetFeedbackByID(ctx, leedsScope, yorkFeedback.ID); !errors.Is(err, organisations.ErrOutOfScope) {
+		t.Errorf("GetFeedbackByID() of York feedback error = %v, want ErrOutOfScope", err)
+	}
+
+	listed, err := svc.ListFeedback(ctx, &FeedbackFilter{Scope: &leedsScope})
+	if err != nil || len(listed.Feedback) != 2 {
+		t.Errorf("ListFeedback() for Leeds = (%v, %v), want 2 entries", listed, err)
+	}
+}
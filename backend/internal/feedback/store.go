@@ -7,36 +7,39 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/perinatal-mental-health-app/backend/internal/db"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
 )
 
-type Store struct {
-	db *pgxpool.Pool
+type store struct {
+	db *db.Handle
 }
 
-func NewStore(db *pgxpool.Pool) FeedbackStoreInterface {
-	return &Store{
+func NewStore(db *db.Handle) Store {
+	return &store{
 		db: db,
 	}
 }
 
 // CreateFeedback creates new feedback
-func (s *Store) CreateFeedback(ctx context.Context, req *CreateFeedbackRequest, userID *string) (*Feedback, error) {
+func (s *store) CreateFeedback(ctx context.Context, req *CreateFeedbackRequest, userID *string) (*Feedback, error) {
 	var feedback Feedback
 
 	query := `
-		INSERT INTO feedback (user_id, anonymous, rating, feedback, category, is_active)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, user_id, anonymous, rating, feedback, category, is_active, created_at, updated_at
+		INSERT INTO feedback (user_id, anonymous, rating, feedback, category, organisation_id, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, user_id, anonymous, rating, feedback, category, organisation_id, is_active, created_at, updated_at
 	`
 
-	err := s.db.QueryRow(ctx, query, userID, req.Anonymous, req.Rating, req.Message, req.Category, true).Scan(
+	err := s.db.QueryRow(ctx, query, userID, req.Anonymous, req.Rating, req.Message, req.Category, req.OrganisationID, true).Scan(
 		&feedback.ID,
 		&feedback.UserID,
 		&feedback.Anonymous,
 		&feedback.Rating,
 		&feedback.Message,
 		&feedback.Category,
+		&feedback.OrganisationID,
 		&feedback.IsActive,
 		&feedback.CreatedAt,
 		&feedback.UpdatedAt,
@@ -49,18 +52,16 @@ func (s *Store) CreateFeedback(ctx context.Context, req *CreateFeedbackRequest,
 	return &feedback, nil
 }
 
+// feedbackKeyset is the stable sort order used for cursor pagination
+var feedbackKeyset = pagination.Keyset{SortColumn: "f.created_at", IDColumn: "f.id"}
+
 // ListFeedback retrieves a paginated list of feedback with filters
-func (s *Store) ListFeedback(ctx context.Context, page, pageSize int, category, rating string) (*ListFeedbackResponse, error) {
-	return s.ListFeedbackWithFilter(ctx, &FeedbackFilter{
-		Category: category,
-		Rating:   rating,
-		Page:     page,
-		PageSize: pageSize,
-	})
+func (s *store) ListFeedback(ctx context.Context, filter *FeedbackFilter) (*ListFeedbackResponse, error) {
+	return s.ListFeedbackWithFilter(ctx, filter)
 }
 
 // ListFeedbackWithFilter retrieves a paginated list of feedback with advanced filters
-func (s *Store) ListFeedbackWithFilter(ctx context.Context, filter *FeedbackFilter) (*ListFeedbackResponse, error) {
+func (s *store) ListFeedbackWithFilter(ctx context.Context, filter *FeedbackFilter) (*ListFeedbackResponse, error) {
 	if filter.Page < 1 {
 		filter.Page = 1
 	}
@@ -68,7 +69,15 @@ func (s *Store) ListFeedbackWithFilter(ctx context.Context, filter *FeedbackFilt
 		filter.PageSize = 20
 	}
 
-	offset := (filter.Page - 1) * filter.PageSize
+	cursor, err := filter.DecodeCursor()
+	if err != nil {
+		return nil, err
+	}
+
+	offset := 0
+	if cursor == nil {
+		offset = (filter.Page - 1) * filter.PageSize
+	}
 
 	// Build WHERE clause for filters
 	var whereConditions []string
@@ -113,26 +122,56 @@ func (s *Store) ListFeedbackWithFilter(ctx context.Context, filter *FeedbackFilt
 		argIndex++
 	}
 
+	// Restrict staff to feedback about their organisations
+	if filter.Scope != nil {
+		if condition, scopeArgs := filter.Scope.Condition("f.organisation_id", argIndex); condition != "" {
+			whereConditions = append(whereConditions, condition)
+			args = append(args, scopeArgs...)
+			argIndex++
+		}
+	}
+
 	whereClause := "WHERE " + strings.Join(whereConditions, " AND ")
 
+	response := &ListFeedbackResponse{
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	}
+
 	// Count total records
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM feedback f %s", whereClause)
-	var total int64
-	err := s.db.QueryRow(ctx, countQuery, args...).Scan(&total)
-	if err != nil {
-		return nil, fmt.Errorf("failed to count feedback: %w", err)
+	if filter.WantTotal() {
+		countQuery := fmt.Sprintf("SELECT COUNT(*) FROM feedback f %s", whereClause)
+		var total int64
+		err := s.db.QueryRow(ctx, countQuery, args...).Scan(&total)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count feedback: %w", err)
+		}
+
+		totalPages := pagination.TotalPages(total, filter.PageSize)
+		response.Total = &total
+		response.TotalPages = &totalPages
+	}
+
+	// Continue from the cursor position
+	if cursor != nil {
+		keysetSQL, keysetArgs := feedbackKeyset.Where(cursor, argIndex)
+		whereConditions = append(whereConditions, keysetSQL)
+		args = append(args, keysetArgs...)
+		argIndex += len(keysetArgs)
+		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
 	}
 
 	// Get feedback records
 	listQuery := fmt.Sprintf(`
-		SELECT f.id, f.user_id, f.anonymous, f.rating, f.feedback, f.category, f.is_active, f.created_at, f.updated_at
+		SELECT f.id, f.user_id, f.anonymous, f.rating, f.feedback, f.category, f.organisation_id, f.is_active, f.created_at, f.updated_at
 		FROM feedback f
 		%s
-		ORDER BY f.created_at DESC
+		ORDER BY %s
 		LIMIT $%d OFFSET $%d
-	`, whereClause, argIndex, argIndex+1)
+	`, whereClause, feedbackKeyset.OrderBy(cursor), argIndex, argIndex+1)
 
-	args = append(args, filter.PageSize, offset)
+	// Read one extra row to detect a further page
+	args = append(args, filter.PageSize+1, offset)
 
 	rows, err := s.db.Query(ctx, listQuery, args...)
 	if err != nil {
@@ -150,6 +189,7 @@ func (s *Store) ListFeedbackWithFilter(ctx context.Context, filter *FeedbackFilt
 			&feedback.Rating,
 			&feedback.Message,
 			&feedback.Category,
+			&feedback.OrganisationID,
 			&feedback.IsActive,
 			&feedback.CreatedAt,
 			&feedback.UpdatedAt,
@@ -164,19 +204,13 @@ func (s *Store) ListFeedbackWithFilter(ctx context.Context, filter *FeedbackFilt
 		return nil, fmt.Errorf("error iterating feedback rows: %w", err)
 	}
 
-	totalPages := int((total + int64(filter.PageSize) - 1) / int64(filter.PageSize))
+	response.Feedback, response.NextCursor, response.PrevCursor = pagination.Paginate(feedbacks, filter.PageSize, cursor, offset, feedbackCursor)
 
-	return &ListFeedbackResponse{
-		Feedback:   feedbacks,
-		Total:      total,
-		Page:       filter.Page,
-		PageSize:   filter.PageSize,
-		TotalPages: totalPages,
-	}, nil
+	return response, nil
 }
 
 // ListFeedbackWithUser retrieves feedback with user information (for admin views)
-func (s *Store) ListFeedbackWithUser(ctx context.Context, filter *FeedbackFilter) (*ListFeedbackResponse, error) {
+func (s *store) ListFeedbackWithUser(ctx context.Context, filter *FeedbackFilter) (*ListFeedbackResponse, error) {
 	if filter.Page < 1 {
 		filter.Page = 1
 	}
@@ -184,7 +218,15 @@ func (s *Store) ListFeedbackWithUser(ctx context.Context, filter *FeedbackFilter
 		filter.PageSize = 20
 	}
 
-	offset := (filter.Page - 1) * filter.PageSize
+	cursor, err := filter.DecodeCursor()
+	if err != nil {
+		return nil, err
+	}
+
+	offset := 0
+	if cursor == nil {
+		offset = (filter.Page - 1) * filter.PageSize
+	}
 
 	// Build WHERE clause for filters
 	var whereConditions []string
@@ -229,34 +271,64 @@ func (s *Store) ListFeedbackWithUser(ctx context.Context, filter *FeedbackFilter
 		argIndex++
 	}
 
+	// Restrict staff to feedback about their organisations
+	if filter.Scope != nil {
+		if condition, scopeArgs := filter.Scope.Condition("f.organisation_id", argIndex); condition != "" {
+			whereConditions = append(whereConditions, condition)
+			args = append(args, scopeArgs...)
+			argIndex++
+		}
+	}
+
 	whereClause := "WHERE " + strings.Join(whereConditions, " AND ")
 
+	response := &ListFeedbackResponse{
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	}
+
 	// Count total records
-	countQuery := fmt.Sprintf(`
-		SELECT COUNT(*) 
-		FROM feedback f 
-		LEFT JOIN users u ON f.user_id = u.id 
-		%s
-	`, whereClause)
+	if filter.WantTotal() {
+		countQuery := fmt.Sprintf(`
+			SELECT COUNT(*) 
+			FROM feedback f 
+			LEFT JOIN users u ON f.user_id = u.id 
+			%s
+		`, whereClause)
+
+		var total int64
+		err := s.db.QueryRow(ctx, countQuery, args...).Scan(&total)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count feedback with user: %w", err)
+		}
 
-	var total int64
-	err := s.db.QueryRow(ctx, countQuery, args...).Scan(&total)
-	if err != nil {
-		return nil, fmt.Errorf("failed to count feedback with user: %w", err)
+		totalPages := pagination.TotalPages(total, filter.PageSize)
+		response.Total = &total
+		response.TotalPages = &totalPages
+	}
+
+	// Continue from the cursor position
+	if cursor != nil {
+		keysetSQL, keysetArgs := feedbackKeyset.Where(cursor, argIndex)
+		whereConditions = append(whereConditions, keysetSQL)
+		args = append(args, keysetArgs...)
+		argIndex += len(keysetArgs)
+		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
 	}
 
 	// Get feedback records with user information
 	listQuery := fmt.Sprintf(`
-		SELECT f.id, f.user_id, f.anonymous, f.rating, f.feedback, f.category, f.is_active, f.created_at, f.updated_at,
+		SELECT f.id, f.user_id, f.anonymous, f.rating, f.feedback, f.category, f.organisation_id, f.is_active, f.created_at, f.updated_at,
 		       u.full_name, u.email, u.role
 		FROM feedback f
 		LEFT JOIN users u ON f.user_id = u.id
 		%s
-		ORDER BY f.created_at DESC
+		ORDER BY %s
 		LIMIT $%d OFFSET $%d
-	`, whereClause, argIndex, argIndex+1)
+	`, whereClause, feedbackKeyset.OrderBy(cursor), argIndex, argIndex+1)
 
-	args = append(args, filter.PageSize, offset)
+	// Read one extra row to detect a further page
+	args = append(args, filter.PageSize+1, offset)
 
 	rows, err := s.db.Query(ctx, listQuery, args...)
 	if err != nil {
@@ -276,6 +348,7 @@ func (s *Store) ListFeedbackWithUser(ctx context.Context, filter *FeedbackFilter
 			&feedback.Rating,
 			&feedback.Message,
 			&feedback.Category,
+			&feedback.OrganisationID,
 			&feedback.IsActive,
 			&feedback.CreatedAt,
 			&feedback.UpdatedAt,
@@ -296,50 +369,51 @@ func (s *Store) ListFeedbackWithUser(ctx context.Context, filter *FeedbackFilter
 		return nil, fmt.Errorf("error iterating feedback with user rows: %w", err)
 	}
 
-	totalPages := int((total + int64(filter.PageSize) - 1) / int64(filter.PageSize))
+	response.Feedback, response.NextCursor, response.PrevCursor = pagination.Paginate(feedbacks, filter.PageSize, cursor, offset, feedbackCursor)
 
-	return &ListFeedbackResponse{
-		Feedback:   feedbacks,
-		Total:      total,
-		Page:       filter.Page,
-		PageSize:   filter.PageSize,
-		TotalPages: totalPages,
-	}, nil
+	return response, nil
 }
 
 // GetFeedbackStats retrieves comprehensive feedback statistics
-func (s *Store) GetFeedbackStats(ctx context.Context) (*FeedbackStats, error) {
+func (s *store) GetFeedbackStats(ctx context.Context, scope organisations.Scope) (*FeedbackStats, error) {
 	var stats FeedbackStats
 	stats.RatingBreakdown = make(map[string]int64)
 	stats.CategoryBreakdown = make(map[string]int64)
 
+	// Restrict staff to feedback about their organisations
+	whereClause := "WHERE is_active = true"
+	condition, args := scope.Condition("organisation_id", 1)
+	if condition != "" {
+		whereClause += " AND " + condition
+	}
+
 	// Get total feedback count
-	err := s.db.QueryRow(ctx, "SELECT COUNT(*) FROM feedback WHERE is_active = true").Scan(&stats.TotalFeedback)
+	err := s.db.Reader(ctx).QueryRow(ctx, "SELECT COUNT(*) FROM feedback "+whereClause, args...).Scan(&stats.TotalFeedback)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get total feedback count: %w", err)
 	}
 
 	// Get anonymous vs authenticated counts
-	anonymousQuery := `
+	anonymousQuery := fmt.Sprintf(`
 		SELECT 
-			SUM(CASE WHEN anonymous = true THEN 1 ELSE 0 END) as anonymous_count,
-			SUM(CASE WHEN anonymous = false THEN 1 ELSE 0 END) as authenticated_count
+			COALESCE(SUM(CASE WHEN anonymous = true THEN 1 ELSE 0 END), 0) as anonymous_count,
+			COALESCE(SUM(CASE WHEN anonymous = false THEN 1 ELSE 0 END), 0) as authenticated_count
 		FROM feedback 
-		WHERE is_active = true
-	`
-	err = s.db.QueryRow(ctx, anonymousQuery).Scan(&stats.TotalAnonymous, &stats.TotalAuthenticated)
+		%s
+	`, whereClause)
+	err = s.db.Reader(ctx).QueryRow(ctx, anonymousQuery, args...).Scan(&stats.TotalAnonymous, &stats.TotalAuthenticated)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get anonymous/authenticated counts: %w", err)
 	}
 
 	// Get rating breakdown and calculate average
-	ratingQuery := `
+	ratingQuery := fmt.Sprintf(`
 		SELECT rating, COUNT(*) 
 		FROM feedback 
-		WHERE is_active = true 
+		%s 
 		GROUP BY rating
-	`
-	rows, err := s.db.Query(ctx, ratingQuery)
+	`, whereClause)
+	rows, err := s.db.Reader(ctx).Query(ctx, ratingQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get rating breakdown: %w", err)
 	}
@@ -370,13 +444,13 @@ func (s *Store) GetFeedbackStats(ctx context.Context) (*FeedbackStats, error) {
 	}
 
 	// Get category breakdown
-	categoryQuery := `
+	categoryQuery := fmt.Sprintf(`
 		SELECT category, COUNT(*) 
 		FROM feedback 
-		WHERE is_active = true 
+		%s 
 		GROUP BY category
-	`
-	rows, err = s.db.Query(ctx, categoryQuery)
+	`, whereClause)
+	rows, err = s.db.Reader(ctx).Query(ctx, categoryQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get category breakdown: %w", err)
 	}
@@ -396,14 +470,14 @@ func (s *Store) GetFeedbackStats(ctx context.Context) (*FeedbackStats, error) {
 	}
 
 	// Get recent feedback (last 10)
-	recentQuery := `
-		SELECT id, user_id, anonymous, rating, feedback, category, is_active, created_at, updated_at
+	recentQuery := fmt.Sprintf(`
+		SELECT id, user_id, anonymous, rating, feedback, category, organisation_id, is_active, created_at, updated_at
 		FROM feedback
-		WHERE is_active = true
+		%s
 		ORDER BY created_at DESC
 		LIMIT 10
-	`
-	rows, err = s.db.Query(ctx, recentQuery)
+	`, whereClause)
+	rows, err = s.db.Reader(ctx).Query(ctx, recentQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get recent feedback: %w", err)
 	}
@@ -419,6 +493,7 @@ func (s *Store) GetFeedbackStats(ctx context.Context) (*FeedbackStats, error) {
 			&feedback.Rating,
 			&feedback.Message,
 			&feedback.Category,
+			&feedback.OrganisationID,
 			&feedback.IsActive,
 			&feedback.CreatedAt,
 			&feedback.UpdatedAt,
@@ -439,11 +514,11 @@ func (s *Store) GetFeedbackStats(ctx context.Context) (*FeedbackStats, error) {
 }
 
 // GetFeedbackByID retrieves a single feedback by ID
-func (s *Store) GetFeedbackByID(ctx context.Context, feedbackID string) (*Feedback, error) {
+func (s *store) GetFeedbackByID(ctx context.Context, feedbackID string) (*Feedback, error) {
 	var feedback Feedback
 
 	query := `
-		SELECT id, user_id, anonymous, rating, feedback, category, is_active, created_at, updated_at
+		SELECT id, user_id, anonymous, rating, feedback, category, organisation_id, is_active, created_at, updated_at
 		FROM feedback
 		WHERE id = $1 AND is_active = true
 	`
@@ -455,6 +530,7 @@ func (s *Store) GetFeedbackByID(ctx context.Context, feedbackID string) (*Feedba
 		&feedback.Rating,
 		&feedback.Message,
 		&feedback.Category,
+		&feedback.OrganisationID,
 		&feedback.IsActive,
 		&feedback.CreatedAt,
 		&feedback.UpdatedAt,
@@ -471,7 +547,7 @@ func (s *Store) GetFeedbackByID(ctx context.Context, feedbackID string) (*Feedba
 }
 
 // UpdateFeedbackStatus updates the status of a feedback (for admin use)
-func (s *Store) UpdateFeedbackStatus(ctx context.Context, feedbackID string, isActive bool) error {
+func (s *store) UpdateFeedbackStatus(ctx context.Context, feedbackID string, isActive bool) error {
 	query := `
 		UPDATE feedback 
 		SET is_active = $1, updated_at = CURRENT_TIMESTAMP
@@ -491,7 +567,7 @@ func (s *Store) UpdateFeedbackStatus(ctx context.Context, feedbackID string, isA
 }
 
 // UpdateFeedback updates feedback content (for user edits)
-func (s *Store) UpdateFeedback(ctx context.Context, feedbackID string, req *UpdateFeedbackRequest) (*Feedback, error) {
+func (s *store) UpdateFeedback(ctx context.Context, feedbackID string, req *UpdateFeedbackRequest) (*Feedback, error) {
 	// Build dynamic update query
 	var setParts []string
 	var args []interface{}
@@ -532,7 +608,7 @@ func (s *Store) UpdateFeedback(ctx context.Context, feedbackID string, req *Upda
 		UPDATE feedback 
 		SET %s
 		WHERE id = $%d
-		RETURNING id, user_id, anonymous, rating, feedback, category, is_active, created_at, updated_at
+		RETURNING id, user_id, anonymous, rating, feedback, category, organisation_id, is_active, created_at, updated_at
 	`, strings.Join(setParts, ", "), argIndex)
 
 	args = append(args, feedbackID)
@@ -545,6 +621,7 @@ func (s *Store) UpdateFeedback(ctx context.Context, feedbackID string, req *Upda
 		&feedback.Rating,
 		&feedback.Message,
 		&feedback.Category,
+		&feedback.OrganisationID,
 		&feedback.IsActive,
 		&feedback.CreatedAt,
 		&feedback.UpdatedAt,
@@ -561,75 +638,13 @@ func (s *Store) UpdateFeedback(ctx context.Context, feedbackID string, req *Upda
 }
 
 // GetUserFeedback retrieves feedback submitted by a specific user
-func (s *Store) GetUserFeedback(ctx context.Context, userID string, page, pageSize int) (*ListFeedbackResponse, error) {
-	if page < 1 {
-		page = 1
-	}
-	if pageSize < 1 || pageSize > 100 {
-		pageSize = 20
-	}
-
-	offset := (page - 1) * pageSize
-
-	// Count total records for this user
-	var total int64
-	err := s.db.QueryRow(ctx, "SELECT COUNT(*) FROM feedback WHERE user_id = $1 AND is_active = true", userID).Scan(&total)
-	if err != nil {
-		return nil, fmt.Errorf("failed to count user feedback: %w", err)
-	}
-
-	// Get feedback records for this user
-	query := `
-		SELECT id, user_id, anonymous, rating, feedback, category, is_active, created_at, updated_at
-		FROM feedback
-		WHERE user_id = $1 AND is_active = true
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
-	`
-
-	rows, err := s.db.Query(ctx, query, userID, pageSize, offset)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query user feedback: %w", err)
-	}
-	defer rows.Close()
-
-	var feedbacks []Feedback
-	for rows.Next() {
-		var feedback Feedback
-		err := rows.Scan(
-			&feedback.ID,
-			&feedback.UserID,
-			&feedback.Anonymous,
-			&feedback.Rating,
-			&feedback.Message,
-			&feedback.Category,
-			&feedback.IsActive,
-			&feedback.CreatedAt,
-			&feedback.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan user feedback: %w", err)
-		}
-		feedbacks = append(feedbacks, feedback)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating user feedback rows: %w", err)
-	}
-
-	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
-
-	return &ListFeedbackResponse{
-		Feedback:   feedbacks,
-		Total:      total,
-		Page:       page,
-		PageSize:   pageSize,
-		TotalPages: totalPages,
-	}, nil
+func (s *store) GetUserFeedback(ctx context.Context, userID string, filter *FeedbackFilter) (*ListFeedbackResponse, error) {
+	filter.UserID = userID
+	return s.ListFeedbackWithFilter(ctx, filter)
 }
 
 // DeleteFeedback soft deletes feedback by setting is_active to false
-func (s *Store) DeleteFeedback(ctx context.Context, feedbackID string) error {
+func (s *store) DeleteFeedback(ctx context.Context, feedbackID string) error {
 	query := `
 		UPDATE feedback 
 		SET is_active = false, updated_at = CURRENT_TIMESTAMP
@@ -649,7 +664,7 @@ func (s *Store) DeleteFeedback(ctx context.Context, feedbackID string) error {
 }
 
 // GetFeedbackSummary retrieves summary statistics for dashboard
-func (s *Store) GetFeedbackSummary(ctx context.Context) (*FeedbackSummary, error) {
+func (s *store) GetFeedbackSummary(ctx context.Context) (*FeedbackSummary, error) {
 	now := time.Now()
 	today := now.Truncate(24 * time.Hour)
 	weekAgo := now.AddDate(0, 0, -7)
@@ -708,7 +723,7 @@ func (s *Store) GetFeedbackSummary(ctx context.Context) (*FeedbackSummary, error
 }
 
 // GetFeedbackTrends retrieves feedback trends over time
-func (s *Store) GetFeedbackTrends(ctx context.Context, days int) ([]FeedbackTrend, error) {
+func (s *store) GetFeedbackTrends(ctx context.Context, days int) ([]FeedbackTrend, error) {
 	if days <= 0 {
 		days = 30
 	}
@@ -746,3 +761,8 @@ func (s *Store) GetFeedbackTrends(ctx context.Context, days int) ([]FeedbackTren
 
 	return trends, nil
 }
+
+// feedbackCursor returns the keyset position of a feedback entry
+func feedbackCursor(feedback Feedback) pagination.Cursor {
+	return pagination.Cursor{SortValue: feedback.CreatedAt, ID: feedback.ID}
+}
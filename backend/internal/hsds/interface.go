@@ -0,0 +1,25 @@
+package hsds
+
+import (
+	"context"
+	"io"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/catalog"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+)
+
+// Service defines the interface for the Open Referral HSDS view of the services directory
+type Service interface {
+	ListServices(ctx context.Context, page, perPage int) (*ServicePage, error)
+	GetService(ctx context.Context, serviceID string) (*ServiceRecord, error)
+	// Import adds or updates directory services from an HSDS JSON dump, matched
+	// by HSDS service ID
+	Import(ctx context.Context, scope organisations.Scope, dryRun bool, dump io.Reader) (*catalog.ImportReport, error)
+}
+
+// Handler defines the interface for HSDS HTTP handlers
+type Handler interface {
+	ListServices(c echo.Context) error
+	GetService(c echo.Context) error
+}
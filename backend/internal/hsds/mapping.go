@@ -0,0 +1,203 @@
+package hsds
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/perinatal-mental-health-app/backend/internal/services"
+)
+
+// idNamespace seeds the IDs of HSDS records we derive rather than store, such as
+// locations and phones, so they stay the same between requests
+var idNamespace = uuid.MustParse("6f1c2b8e-3d4a-4e1b-9c7f-2a5d8e0b4c13")
+
+// defaultCountry is the ISO country code given to addresses, which we hold as free text
+const defaultCountry = "GB"
+
+// FromService maps a directory service to an HSDS service
+func FromService(s *services.ServicesModel) ServiceRecord {
+	status := StatusActive
+	if !s.IsActive {
+		status = StatusInactive
+	}
+	lastModified := s.UpdatedAt
+
+	organization := Organization{
+		ID:   derivedID("organization", strings.ToLower(s.ProviderName)),
+		Name: s.ProviderName,
+	}
+	if s.OrganisationID != nil {
+		organization.ID = *s.OrganisationID
+	}
+
+	record := ServiceRecord{
+		ID:                     s.ID,
+		OrganizationID:         organization.ID,
+		Name:                   s.Name,
+		Description:            &s.Description,
+		URL:                    s.WebsiteURL,
+		Email:                  s.ContactEmail,
+		Status:                 status,
+		EligibilityDescription: s.EligibilityCriteria,
+		LastModified:           &lastModified,
+		Organization:           &organization,
+		Phones:                 []Phone{},
+		Contacts:               []Contact{},
+		Schedules:              []Schedule{},
+		ServiceAtLocations:     []ServiceAtLocation{},
+	}
+
+	if s.ContactPhone != nil && *s.ContactPhone != "" {
+		record.Phones = append(record.Phones, Phone{ID: derivedID(s.ID, "phone"), Number: *s.ContactPhone})
+	}
+	if s.ContactEmail != nil || len(record.Phones) > 0 {
+		name := "Enquiries"
+		record.Contacts = append(record.Contacts, Contact{
+			ID:     derivedID(s.ID, "contact"),
+			Name:   &name,
+			Email:  s.ContactEmail,
+			Phones: record.Phones,
+		})
+	}
+
+	if s.AvailabilityHours != "" {
+		hours := s.AvailabilityHours
+		record.Schedules = append(record.Schedules, Schedule{ID: derivedID(s.ID, "schedule"), ServiceID: s.ID, Description: &hours})
+	}
+
+	if s.ServiceType == "in_person" || s.ServiceType == "hybrid" {
+		location := Location{ID: derivedID(s.ID, LocationPhysical), LocationType: LocationPhysical, Addresses: []Address{}}
+		if s.Address != nil && *s.Address != "" {
+			location.Addresses = append(location.Addresses, Address{
+				ID:          derivedID(location.ID, "address"),
+				LocationID:  location.ID,
+				Address1:    *s.Address,
+				Country:     defaultCountry,
+				AddressType: LocationPhysical,
+			})
+		}
+		record.ServiceAtLocations = append(record.ServiceAtLocations, serviceAtLocation(s.ID, location))
+	}
+	if s.ServiceType == "online" || s.ServiceType == "hybrid" {
+		location := Location{ID: derivedID(s.ID, LocationVirtual), LocationType: LocationVirtual, URL: s.WebsiteURL, Addresses: []Address{}}
+		record.ServiceAtLocations = append(record.ServiceAtLocations, serviceAtLocation(s.ID, location))
+	}
+
+	return record
+}
+
+// ToCreateRequest maps an HSDS service to a request to add it to the directory.
+// Contact details fall back from the service to its contacts and organization,
+// and the service type follows from whether it has physical or virtual locations.
+func ToCreateRequest(r *ServiceRecord) *services.CreateServiceRequest {
+	req := &services.CreateServiceRequest{
+		Name:                strings.TrimSpace(r.Name),
+		ServiceType:         "in_person",
+		EligibilityCriteria: nonEmpty(r.EligibilityDescription),
+		WebsiteURL:          nonEmpty(r.URL),
+		ContactEmail:        nonEmpty(r.Email),
+	}
+	if description := nonEmpty(r.Description); description != nil {
+		req.Description = *description
+	}
+
+	if r.Organization != nil {
+		req.ProviderName = strings.TrimSpace(r.Organization.Name)
+		if req.WebsiteURL == nil {
+			req.WebsiteURL = nonEmpty(r.Organization.Website)
+		}
+		if req.ContactEmail == nil {
+			req.ContactEmail = nonEmpty(r.Organization.Email)
+		}
+	}
+
+	phones := append([]Phone{}, r.Phones...)
+	for _, contact := range r.Contacts {
+		if req.ContactEmail == nil {
+			req.ContactEmail = nonEmpty(contact.Email)
+		}
+		phones = append(phones, contact.Phones...)
+	}
+	for _, phone := range phones {
+		if req.ContactPhone = nonEmpty(&phone.Number); req.ContactPhone != nil {
+			break
+		}
+	}
+
+	physical, virtual := false, false
+	for _, sal := range r.ServiceAtLocations {
+		if sal.Location == nil {
+			continue
+		}
+		switch sal.Location.LocationType {
+		case LocationPhysical:
+			physical = true
+			if req.Address == nil {
+				req.Address = formatAddress(sal.Location.Addresses)
+			}
+		case LocationVirtual:
+			virtual = true
+			if req.WebsiteURL == nil {
+				req.WebsiteURL = nonEmpty(sal.Location.URL)
+			}
+		}
+	}
+	switch {
+	case physical && virtual:
+		req.ServiceType = "hybrid"
+	case virtual:
+		req.ServiceType = "online"
+	}
+
+	return req
+}
+
+// Helper functions
+
+// derivedID returns a stable ID for a record we don't store, such as a location
+func derivedID(parent, kind string) string {
+	return uuid.NewSHA1(idNamespace, []byte(parent+"/"+kind)).String()
+}
+
+func serviceAtLocation(serviceID string, location Location) ServiceAtLocation {
+	return ServiceAtLocation{
+		ID:         derivedID(location.ID, "service_at_location"),
+		ServiceID:  serviceID,
+		LocationID: location.ID,
+		Location:   &location,
+	}
+}
+
+// formatAddress joins the parts of the first address into the single line the
+// directory holds
+func formatAddress(addresses []Address) *string {
+	if len(addresses) == 0 {
+		return nil
+	}
+	a := addresses[0]
+
+	var parts []string
+	for _, part := range []*string{&a.Address1, a.Address2, &a.City, &a.StateProvince, &a.PostalCode} {
+		if value := nonEmpty(part); value != nil {
+			parts = append(parts, *value)
+		}
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+
+	address := strings.Join(parts, ", ")
+	return &address
+}
+
+// nonEmpty returns the trimmed value, or nil when it is missing or blank
+func nonEmpty(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
@@ -0,0 +1,121 @@
+package hsds
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/services"
+)
+
+func TestMappingRoundTrip(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+
+	tests := []struct {
+		name          string
+		model         services.ServicesModel
+		wantLocations []string
+	}{
+		{
+			name: "in person",
+			model: services.ServicesModel{
+				Name: "Perinatal Mental Health Team", Description: "Specialist care in pregnancy", ProviderName: "North Trust",
+				ContactPhone: strPtr("0161 000 0000"), Address: strPtr("1 Hospital Road, Leeds"), ServiceType: "in_person",
+				EligibilityCriteria: strPtr("Pregnant or up to one year after birth"),
+			},
+			wantLocations: []string{LocationPhysical},
+		},
+		{
+			name: "online",
+			model: services.ServicesModel{
+				Name: "Online CBT", Description: "Guided self help", ProviderName: "Talking Therapies",
+				ContactEmail: strPtr("cbt@example.nhs.uk"), WebsiteURL: strPtr("https://cbt.example.nhs.uk"), ServiceType: "online",
+			},
+			wantLocations: []string{LocationVirtual},
+		},
+		{
+			name: "hybrid",
+			model: services.ServicesModel{
+				Name: "Parent Support", Description: "Groups and calls", ProviderName: "Local Charity",
+				ContactEmail: strPtr("hello@example.org"), WebsiteURL: strPtr("https://example.org"), Address: strPtr("2 High Street"),
+				ServiceType: "hybrid",
+			},
+			wantLocations: []string{LocationPhysical, LocationVirtual},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.model.ID = "0d7f3c44-5b8e-4a7e-9f0a-3c6b2e1d9a10"
+			tt.model.IsActive = true
+			tt.model.UpdatedAt = time.Now()
+
+			record := FromService(&tt.model)
+			var locations []string
+			for _, sal := range record.ServiceAtLocations {
+				locations = append(locations, sal.Location.LocationType)
+			}
+			if !reflect.DeepEqual(locations, tt.wantLocations) {
+				t.Errorf("FromService() locations = %v, want %v", locations, tt.wantLocations)
+			}
+			if record.Status != StatusActive || record.Organization.Name != tt.model.ProviderName || record.OrganizationID != record.Organization.ID {
+				t.Errorf("FromService() = %+v, want an active service from %s", record, tt.model.ProviderName)
+			}
+			if again := FromService(&tt.model); !reflect.DeepEqual(again.ServiceAtLocations, record.ServiceAtLocations) {
+				t.Errorf("FromService() location IDs change between calls")
+			}
+
+			want := services.CreateServiceRequest{
+				Name:                tt.model.Name,
+				Description:         tt.model.Description,
+				ProviderName:        tt.model.ProviderName,
+				ContactEmail:        tt.model.ContactEmail,
+				ContactPhone:        tt.model.ContactPhone,
+				WebsiteURL:          tt.model.WebsiteURL,
+				Address:             tt.model.Address,
+				ServiceType:         tt.model.ServiceType,
+				EligibilityCriteria: tt.model.EligibilityCriteria,
+			}
+			if got := ToCreateRequest(&record); !reflect.DeepEqual(*got, want) {
+				t.Errorf("ToCreateRequest() = %+v, want %+v", *got, want)
+			}
+		})
+	}
+}
+
+func TestToCreateRequestFallbacks(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+
+	record := ServiceRecord{
+		ID:           "regional-42",
+		Name:         " Baby Bonding ",
+		Description:  strPtr("Sessions for new parents"),
+		Status:       StatusActive,
+		Organization: &Organization{ID: "org-1", Name: "Regional Council", Website: strPtr("https://council.example.gov.uk")},
+		Contacts: []Contact{
+			{ID: "c-1", Email: strPtr(" "), Phones: []Phone{{ID: "p-1", Number: "0113 000 0000"}}},
+			{ID: "c-2", Email: strPtr("families@example.gov.uk")},
+		},
+		ServiceAtLocations: []ServiceAtLocation{{
+			ID: "sal-1",
+			Location: &Location{ID: "loc-1", LocationType: LocationPhysical, Addresses: []Address{{
+				Address1: "Family Hub", City: "Leeds", PostalCode: "LS1 1AA", Country: "GB", AddressType: LocationPhysical,
+			}}},
+		}},
+	}
+
+	got := ToCreateRequest(&record)
+	want := services.CreateServiceRequest{
+		Name:         "Baby Bonding",
+		Description:  "Sessions for new parents",
+		ProviderName: "Regional Council",
+		ContactEmail: strPtr("families@example.gov.uk"),
+		ContactPhone: strPtr("0113 000 0000"),
+		WebsiteURL:   strPtr("https://council.example.gov.uk"),
+		Address:      strPtr("Family Hub, Leeds, LS1 1AA"),
+		ServiceType:  "in_person",
+	}
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("ToCreateRequest() = %+v, want %+v", *got, want)
+	}
+}
@@ -0,0 +1,114 @@
+package hsds
+
+import "time"
+
+// Service statuses defined by HSDS
+const (
+	StatusActive            = "active"
+	StatusInactive          = "inactive"
+	StatusDefunct           = "defunct"
+	StatusTemporarilyClosed = "temporarily closed"
+)
+
+// Location types defined by HSDS
+const (
+	LocationPhysical = "physical"
+	LocationPostal   = "postal"
+	LocationVirtual  = "virtual"
+)
+
+// ServiceRecord is an HSDS service with its related records nested, as served
+// by the HSDS API /services/{id} endpoint
+type ServiceRecord struct {
+	ID                     string              `json:"id"`
+	OrganizationID         string              `json:"organization_id"`
+	Name                   string              `json:"name"`
+	AlternateName          *string             `json:"alternate_name,omitempty"`
+	Description            *string             `json:"description,omitempty"`
+	URL                    *string             `json:"url,omitempty"`
+	Email                  *string             `json:"email,omitempty"`
+	Status                 string              `json:"status"`
+	EligibilityDescription *string             `json:"eligibility_description,omitempty"`
+	LastModified           *time.Time          `json:"last_modified,omitempty"`
+	Organization           *Organization       `json:"organization,omitempty"`
+	Phones                 []Phone             `json:"phones"`
+	Contacts               []Contact           `json:"contacts"`
+	Schedules              []Schedule          `json:"schedules"`
+	ServiceAtLocations     []ServiceAtLocation `json:"service_at_locations"`
+}
+
+// Organization is the HSDS organization that provides a service
+type Organization struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	Email       *string `json:"email,omitempty"`
+	Website     *string `json:"website,omitempty"`
+}
+
+// ServiceAtLocation links a service to a place it is delivered
+type ServiceAtLocation struct {
+	ID         string    `json:"id"`
+	ServiceID  string    `json:"service_id"`
+	LocationID string    `json:"location_id"`
+	Location   *Location `json:"location,omitempty"`
+}
+
+// Location is a physical or virtual place a service is delivered
+type Location struct {
+	ID           string    `json:"id"`
+	LocationType string    `json:"location_type"`
+	Name         *string   `json:"name,omitempty"`
+	URL          *string   `json:"url,omitempty"`
+	Addresses    []Address `json:"addresses"`
+}
+
+// Address is the postal address of a location
+type Address struct {
+	ID            string  `json:"id"`
+	LocationID    string  `json:"location_id"`
+	Address1      string  `json:"address_1"`
+	Address2      *string `json:"address_2,omitempty"`
+	City          string  `json:"city"`
+	Region        *string `json:"region,omitempty"`
+	StateProvince string  `json:"state_province"`
+	PostalCode    string  `json:"postal_code"`
+	Country       string  `json:"country"`
+	AddressType   string  `json:"address_type"`
+}
+
+// Phone is a telephone number for a service or contact
+type Phone struct {
+	ID          string  `json:"id"`
+	Number      string  `json:"number"`
+	Type        *string `json:"type,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// Contact is a named point of contact for a service
+type Contact struct {
+	ID     string  `json:"id"`
+	Name   *string `json:"name,omitempty"`
+	Title  *string `json:"title,omitempty"`
+	Email  *string `json:"email,omitempty"`
+	Phones []Phone `json:"phones"`
+}
+
+// Schedule describes when a service is available
+type Schedule struct {
+	ID          string  `json:"id"`
+	ServiceID   string  `json:"service_id"`
+	Description *string `json:"description,omitempty"`
+}
+
+// ServicePage is a page of services in the HSDS API envelope
+type ServicePage struct {
+	TotalItems int64           `json:"total_items"`
+	TotalPages int             `json:"total_pages"`
+	PageNumber int             `json:"page_number"`
+	Size       int             `json:"size"`
+	FirstPage  bool            `json:"first_page"`
+	LastPage   bool            `json:"last_page"`
+	Empty      bool            `json:"empty"`
+	Contents   []ServiceRecord `json:"contents"`
+}
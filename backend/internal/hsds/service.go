@@ -0,0 +1,192 @@
+package hsds
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/perinatal-mental-health-app/backend/internal/catalog"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
+	"github.com/perinatal-mental-health-app/backend/internal/services"
+)
+
+// FormatHSDS names HSDS dumps in import reports
+const FormatHSDS = "hsds"
+
+type service struct {
+	services services.Service
+	catalog  catalog.Service
+}
+
+func NewService(servicesService services.Service, catalogService catalog.Service) Service {
+	return &service{
+		services: servicesService,
+		catalog:  catalogService,
+	}
+}
+
+// ListServices retrieves a page of active services in HSDS form
+func (s *service) ListServices(ctx context.Context, page, perPage int) (*ServicePage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	resp, err := s.services.ListServices(ctx, &services.ListServicesRequest{Page: page, PageSize: perPage})
+	if err != nil {
+		return nil, err
+	}
+
+	total := int64(len(resp.Services))
+	if resp.Total != nil {
+		total = *resp.Total
+	}
+	totalPages := pagination.TotalPages(total, perPage)
+
+	result := &ServicePage{
+		TotalItems: total,
+		TotalPages: totalPages,
+		PageNumber: page,
+		Size:       len(resp.Services),
+		FirstPage:  page == 1,
+		LastPage:   page >= totalPages,
+		Empty:      len(resp.Services) == 0,
+		Contents:   make([]ServiceRecord, len(resp.Services)),
+	}
+	for i := range resp.Services {
+		result.Contents[i] = FromService(&resp.Services[i])
+	}
+
+	return result, nil
+}
+
+// GetService retrieves a service in HSDS form
+func (s *service) GetService(ctx context.Context, serviceID string) (*ServiceRecord, error) {
+	model, err := s.services.GetService(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	record := FromService(model)
+	return &record, nil
+}
+
+// Import maps each service in an HSDS dump to a directory service and runs it
+// through the catalog import, using the HSDS service ID as the external_ref.
+// Services that aren't active are reported as failed rows and not imported.
+func (s *service) Import(ctx context.Context, scope organisations.Scope, dryRun bool, dump io.Reader) (*catalog.ImportReport, error) {
+	elements, err := decodeDump(dump)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]interface{}
+	var positions []int // Position in the dump of each row passed to the catalog
+	var rejected []catalog.RowResult
+	firstRow := make(map[string]int, len(elements))
+	for i, element := range elements {
+		var record ServiceRecord
+		err := json.Unmarshal(element, &record)
+		if err == nil && strings.TrimSpace(record.ID) == "" {
+			err = fmt.Errorf("id is required")
+		}
+		if first, duplicate := firstRow[record.ID]; err == nil && duplicate {
+			err = fmt.Errorf("id %q already used on row %d", record.ID, first)
+		} else if err == nil {
+			firstRow[record.ID] = i + 1
+		}
+		if err == nil && record.Status != "" && record.Status != StatusActive {
+			err = fmt.Errorf("status is %q; only active services are imported", record.Status)
+		}
+		if err != nil {
+			message := strings.TrimPrefix(err.Error(), "json: ")
+			rejected = append(rejected, catalog.RowResult{Row: i + 1, ExternalRef: record.ID, Action: catalog.ActionError, Error: &message})
+			continue
+		}
+
+		row, err := requestFields(ToCreateRequest(&record))
+		if err != nil {
+			return nil, err
+		}
+		row["external_ref"] = record.ID
+		rows = append(rows, row)
+		positions = append(positions, i+1)
+	}
+
+	body, err := json.Marshal(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode services: %w", err)
+	}
+	if rows == nil {
+		body = []byte("[]")
+	}
+
+	report, err := s.catalog.Import(ctx, scope, catalog.CollectionServices, catalog.FormatJSON, dryRun, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	// Report rows against their position in the dump
+	for i := range report.Rows {
+		report.Rows[i].Row = positions[report.Rows[i].Row-1]
+	}
+	report.Rows = append(report.Rows, rejected...)
+	sort.Slice(report.Rows, func(i, j int) bool { return report.Rows[i].Row < report.Rows[j].Row })
+	report.Format = FormatHSDS
+	report.Total += len(rejected)
+	report.Failed += len(rejected)
+
+	return report, nil
+}
+
+// Helper functions
+
+// decodeDump reads the services from an HSDS dump, which is either a JSON array
+// of services or a saved page from an HSDS API with the services in contents
+func decodeDump(dump io.Reader) ([]json.RawMessage, error) {
+	body, err := io.ReadAll(dump)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", catalog.ErrInvalidFile, err)
+	}
+
+	var elements []json.RawMessage
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '{' {
+		var page struct {
+			Contents []json.RawMessage `json:"contents"`
+		}
+		if err := json.Unmarshal(trimmed, &page); err != nil {
+			return nil, fmt.Errorf("%w: %w", catalog.ErrInvalidFile, err)
+		}
+		elements = page.Contents
+	} else if err := json.Unmarshal(trimmed, &elements); err != nil {
+		return nil, fmt.Errorf("%w: expected a JSON array of HSDS services: %w", catalog.ErrInvalidFile, err)
+	}
+
+	if len(elements) > catalog.MaxImportRows {
+		return nil, fmt.Errorf("%w: more than %d services", catalog.ErrInvalidFile, catalog.MaxImportRows)
+	}
+
+	return elements, nil
+}
+
+// requestFields converts a create request to the JSON fields of a catalog row
+func requestFields(req *services.CreateServiceRequest) (map[string]interface{}, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode service: %w", err)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, fmt.Errorf("failed to encode service: %w", err)
+	}
+
+	return fields, nil
+}
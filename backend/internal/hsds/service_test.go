@@ -0,0 +1,80 @@
+package hsds
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/perinatal-mental-health-app/backend/internal/catalog"
+	"github.com/perinatal-mental-health-app/backend/internal/httpcache"
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/resources"
+	"github.com/perinatal-mental-health-app/backend/internal/services"
+	"github.com/perinatal-mental-health-app/backend/internal/support_groups"
+)
+
+const dump = `{"total_items": 4, "contents": [
+	{"id": "regional-1", "name": "Perinatal Team", "description": "Specialist care", "status": "active",
+	 "organization": {"id": "org-1", "name": "North Trust"}, "phones": [{"id": "p-1", "number": "0161 000 0000"}]},
+	{"id": "regional-2", "name": "Closed Group", "description": "No longer running", "status": "defunct",
+	 "organization": {"id": "org-1", "name": "North Trust"}, "email": "closed@example.org"},
+	{"id": "regional-3", "name": "No Contact", "description": "Missing contact details", "status": "active",
+	 "organization": {"id": "org-2", "name": "South Trust"}},
+	{"id": "regional-1", "name": "Perinatal Team", "description": "Repeated", "status": "active",
+	 "organization": {"id": "org-1", "name": "North Trust"}, "email": "team@example.org"}
+]}`
+
+func TestImport(t *testing.T) {
+	ctx := context.Background()
+	db := memdb.New()
+	servicesService := services.NewService(services.NewMemoryStore(db))
+	catalogService := catalog.NewService(
+		catalog.NewMemoryStore(),
+		httpcache.NewMemoryStore(),
+		servicesService,
+		resources.NewService(resources.NewMemoryStore(db)),
+		support_groups.NewService(support_groups.NewMemoryStore(db)),
+	)
+	svc := NewService(servicesService, catalogService)
+	scope := organisations.Scope{All: true}
+
+	report, err := svc.Import(ctx, scope, false, strings.NewReader(dump))
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if report.Total != 4 || report.Created != 1 || report.Failed != 3 {
+		t.Fatalf("Import() = %+v, want 1 created and 3 failed of 4", report)
+	}
+
+	wantErrors := map[int]string{
+		2: "only active services",
+		3: "contact method",
+		4: "already used on row 1",
+	}
+	for i, row := range report.Rows {
+		if row.Row != i+1 {
+			t.Errorf("report row %d numbered %d", i+1, row.Row)
+		}
+		if want, failed := wantErrors[row.Row]; failed && (row.Error == nil || !strings.Contains(*row.Error, want)) {
+			t.Errorf("row %d error = %v, want %q", row.Row, row.Error, want)
+		}
+	}
+
+	// Importing again updates the service matched by HSDS ID
+	report, err = svc.Import(ctx, scope, false, strings.NewReader(`[{"id": "regional-1", "name": "Perinatal Mental Health Team", "description": "Specialist care", "organization": {"id": "org-1", "name": "North Trust"}, "phones": [{"id": "p-1", "number": "0161 000 0000"}]}]`))
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if report.Updated != 1 {
+		t.Fatalf("re-import = %+v, want 1 updated", report)
+	}
+
+	page, err := svc.ListServices(ctx, 1, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.TotalItems != 1 || page.Contents[0].Name != "Perinatal Mental Health Team" || page.Contents[0].Organization.Name != "North Trust" {
+		t.Errorf("ListServices() = %+v, want the updated service", page)
+	}
+}
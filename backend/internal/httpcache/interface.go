@@ -0,0 +1,13 @@
+package httpcache
+
+import (
+	"context"
+	"time"
+)
+
+// Store defines the interface for catalog version persistence
+type Store interface {
+	GetVersion(ctx context.Context, collection string) (*CollectionVersion, error)
+	BumpVersion(ctx context.Context, collection string) (*CollectionVersion, error)
+	GetUpdatedAt(ctx context.Context, collection, id string) (*time.Time, error)
+}
@@ -0,0 +1,64 @@
+package httpcache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// memoryStore keeps collection versions in memory for tests and demo mode
+type memoryStore struct {
+	mu       sync.Mutex
+	versions map[string]CollectionVersion
+}
+
+// NewMemoryStore returns a store seeded with the catalog collections at version 1,
+// as the migration does
+func NewMemoryStore() Store {
+	now := time.Now()
+	versions := make(map[string]CollectionVersion)
+	for _, collection := range []string{CollectionServices, CollectionResources, CollectionSupportGroups} {
+		versions[collection] = CollectionVersion{Collection: collection, Version: 1, CreatedAt: now, UpdatedAt: now}
+	}
+
+	return &memoryStore{
+		versions: versions,
+	}
+}
+
+// GetVersion retrieves the current version of a collection
+func (s *memoryStore) GetVersion(ctx context.Context, collection string) (*CollectionVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	version, ok := s.versions[collection]
+	if !ok {
+		return nil, fmt.Errorf("collection version not found")
+	}
+
+	return &version, nil
+}
+
+// BumpVersion increments the version of a collection, creating it if needed
+func (s *memoryStore) BumpVersion(ctx context.Context, collection string) (*CollectionVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	version, ok := s.versions[collection]
+	if !ok {
+		version = CollectionVersion{Collection: collection, CreatedAt: now}
+	}
+	version.Version++
+	version.UpdatedAt = now
+	s.versions[collection] = version
+
+	return &version, nil
+}
+
+// GetUpdatedAt returns nil, so detail ETags in demo mode rely on the collection
+// version alone
+func (s *memoryStore) GetUpdatedAt(ctx context.Context, collection, id string) (*time.Time, error) {
+	return nil, nil
+}
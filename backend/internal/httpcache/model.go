@@ -0,0 +1,54 @@
+package httpcache
+
+import (
+	"fmt"
+	"time"
+)
+
+// Catalog collections whose versions drive ETags on the public routes
+const (
+	CollectionServices      = "services"
+	CollectionResources     = "resources"
+	CollectionSupportGroups = "support_groups"
+)
+
+// CollectionVersion represents the current version of a catalog collection
+type CollectionVersion struct {
+	Collection string    `json:"collection" db:"collection"`
+	Version    int64     `json:"version" db:"version"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Policy describes how a cached route validates and how long clients may keep it
+type Policy struct {
+	// MaxAge is sent as Cache-Control max-age
+	MaxAge time.Duration
+	// StaleWhileRevalidate lets clients serve a stale copy while they revalidate
+	StaleWhileRevalidate time.Duration
+	// Strong hashes the response body instead of deriving a weak ETag from the
+	// collection version. Use it where the payload changes without an admin write,
+	// such as view-count ordering.
+	Strong bool
+	// Row adds the updated_at of the record named by the :id path parameter to
+	// the weak ETag, so a detail route changes when its row does
+	Row bool
+}
+
+// Route policies for the public catalog
+var (
+	PolicyList     = Policy{MaxAge: time.Minute, StaleWhileRevalidate: 5 * time.Minute}
+	PolicyDetail   = Policy{MaxAge: 2 * time.Minute, StaleWhileRevalidate: 10 * time.Minute, Row: true}
+	PolicySearch   = Policy{MaxAge: 30 * time.Second}
+	PolicyFeatured = Policy{MaxAge: 5 * time.Minute, StaleWhileRevalidate: 15 * time.Minute}
+	PolicyPopular  = Policy{MaxAge: time.Minute, Strong: true}
+)
+
+// CacheControl renders the policy as a Cache-Control header value
+func (p Policy) CacheControl() string {
+	value := fmt.Sprintf("public, max-age=%d", int(p.MaxAge.Seconds()))
+	if p.StaleWhileRevalidate > 0 {
+		value += fmt.Sprintf(", stale-while-revalidate=%d", int(p.StaleWhileRevalidate.Seconds()))
+	}
+	return value
+}
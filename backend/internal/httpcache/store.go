@@ -0,0 +1,108 @@
+package httpcache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/perinatal-mental-health-app/backend/internal/db"
+)
+
+// store reads versions through the handle's reader, so a request pinned to
+// the replica builds its ETag from the same data as its response body
+type store struct {
+	db *db.Handle
+}
+
+func NewStore(db *db.Handle) Store {
+	return &store{
+		db: db,
+	}
+}
+
+// GetVersion retrieves the current version of a collection
+func (s *store) GetVersion(ctx context.Context, collection string) (*CollectionVersion, error) {
+	var version CollectionVersion
+
+	query := `
+		SELECT collection, version, created_at, updated_at
+		FROM catalog_versions
+		WHERE collection = $1
+	`
+
+	err := s.db.Reader(ctx).QueryRow(ctx, query, collection).Scan(
+		&version.Collection,
+		&version.Version,
+		&version.CreatedAt,
+		&version.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("collection version not found")
+		}
+		return nil, fmt.Errorf("failed to get collection version: %w", err)
+	}
+
+	return &version, nil
+}
+
+// BumpVersion increments the version of a collection, creating it if needed
+func (s *store) BumpVersion(ctx context.Context, collection string) (*CollectionVersion, error) {
+	var version CollectionVersion
+
+	query := `
+		INSERT INTO catalog_versions (collection, version)
+		VALUES ($1, 1)
+		ON CONFLICT (collection) DO UPDATE
+		SET version = catalog_versions.version + 1
+		RETURNING collection, version, created_at, updated_at
+	`
+
+	err := s.db.QueryRow(ctx, query, collection).Scan(
+		&version.Collection,
+		&version.Version,
+		&version.CreatedAt,
+		&version.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bump collection version: %w", err)
+	}
+
+	return &version, nil
+}
+
+// GetUpdatedAt retrieves when a catalog record last changed. It returns nil
+// for an ID that doesn't name a record, leaving the handler to answer 404.
+func (s *store) GetUpdatedAt(ctx context.Context, collection, id string) (*time.Time, error) {
+	table, ok := collectionTables[collection]
+	if !ok {
+		return nil, fmt.Errorf("unknown collection: %s", collection)
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, nil
+	}
+
+	var updatedAt time.Time
+	query := fmt.Sprintf(`SELECT updated_at FROM %s WHERE id = $1`, table)
+	err := s.db.Reader(ctx).QueryRow(ctx, query, id).Scan(&updatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get record updated_at: %w", err)
+	}
+
+	return &updatedAt, nil
+}
+
+// Helper functions
+
+// collectionTables maps each collection to its table. Table names can't be
+// bound as parameters, so only these are ever interpolated into queries.
+var collectionTables = map[string]string{
+	CollectionServices:      "services",
+	CollectionResources:     "resources",
+	CollectionSupportGroups: "support_groups",
+}
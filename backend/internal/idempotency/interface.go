@@ -0,0 +1,16 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// Store defines the interface for idempotency key persistence
+type Store interface {
+	// Reserve claims the key for the caller. When the key is already held by an
+	// unexpired record, that record is returned and reserved is false.
+	Reserve(ctx context.Context, userID, key, fingerprint string, ttl time.Duration) (existing *Record, reserved bool, err error)
+	Complete(ctx context.Context, userID, key string, statusCode int, contentType string, body []byte) error
+	Release(ctx context.Context, userID, key string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,97 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// memoryStore keeps idempotency records in memory for tests and demo mode
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+func NewMemoryStore() Store {
+	return &memoryStore{
+		records: make(map[string]Record),
+	}
+}
+
+// Reserve inserts a pending record for the key, taking over an expired one if present
+func (s *memoryStore) Reserve(ctx context.Context, userID, key, fingerprint string, ttl time.Duration) (*Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if existing, ok := s.records[recordKey(userID, key)]; ok && !existing.ExpiresAt.Before(now) {
+		return &existing, false, nil
+	}
+
+	s.records[recordKey(userID, key)] = Record{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   now.Add(ttl),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	return nil, true, nil
+}
+
+// Complete stores the response produced for a reserved key
+func (s *memoryStore) Complete(ctx context.Context, userID, key string, statusCode int, contentType string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[recordKey(userID, key)]
+	if !ok {
+		return fmt.Errorf("idempotency key not found")
+	}
+
+	record.StatusCode = &statusCode
+	record.ContentType = &contentType
+	record.ResponseBody = append([]byte(nil), body...)
+	record.UpdatedAt = time.Now()
+	s.records[recordKey(userID, key)] = record
+
+	return nil
+}
+
+// Release removes a pending record so the client may retry with the same key
+func (s *memoryStore) Release(ctx context.Context, userID, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record, ok := s.records[recordKey(userID, key)]; ok && !record.IsCompleted() {
+		delete(s.records, recordKey(userID, key))
+	}
+
+	return nil
+}
+
+// DeleteExpired purges records whose replay window has passed
+func (s *memoryStore) DeleteExpired(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	now := time.Now()
+	for id, record := range s.records {
+		if record.ExpiresAt.Before(now) {
+			delete(s.records, id)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+func recordKey(userID, key string) string {
+	return userID + "\x00" + key
+}
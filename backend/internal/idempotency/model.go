@@ -0,0 +1,30 @@
+package idempotency
+
+import (
+	"time"
+)
+
+// Expired records are deleted by a recurring worker task
+const (
+	TaskDeleteExpired  = "idempotency.delete_expired"
+	DeleteExpiredEvery = time.Hour
+)
+
+// Record represents a stored idempotent request and, once completed, its response
+type Record struct {
+	ID           string    `json:"id" db:"id"`
+	UserID       string    `json:"user_id" db:"user_id"`
+	Key          string    `json:"idempotency_key" db:"idempotency_key"`
+	Fingerprint  string    `json:"fingerprint" db:"fingerprint"`
+	StatusCode   *int      `json:"status_code,omitempty" db:"status_code"`
+	ContentType  *string   `json:"content_type,omitempty" db:"content_type"`
+	ResponseBody []byte    `json:"-" db:"response_body"` // Sealed by the store
+	ExpiresAt    time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// IsCompleted reports whether the original request finished and its response was stored
+func (r *Record) IsCompleted() bool {
+	return r.StatusCode != nil
+}
@@ -0,0 +1,153 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/perinatal-mental-health-app/backend/internal/encryption"
+)
+
+// store seals stored response bodies, which can hold anything a handler
+// returned, including fields that are encrypted in their own tables
+type store struct {
+	db     *pgxpool.Pool
+	cipher encryption.Cipher
+}
+
+func NewStore(db *pgxpool.Pool, cipher encryption.Cipher) Store {
+	return &store{
+		db:     db,
+		cipher: cipher,
+	}
+}
+
+// Reserve inserts a pending record for the key, taking over an expired one if present
+func (s *store) Reserve(ctx context.Context, userID, key, fingerprint string, ttl time.Duration) (*Record, bool, error) {
+	expiresAt := time.Now().Add(ttl)
+
+	query := `
+		INSERT INTO idempotency_keys (user_id, idempotency_key, fingerprint, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, idempotency_key) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint,
+		    status_code = NULL,
+		    content_type = NULL,
+		    response_body = NULL,
+		    expires_at = EXCLUDED.expires_at,
+		    created_at = CURRENT_TIMESTAMP
+		WHERE idempotency_keys.expires_at < CURRENT_TIMESTAMP
+		RETURNING id
+	`
+
+	var id string
+	err := s.db.QueryRow(ctx, query, userID, key, fingerprint, expiresAt).Scan(&id)
+	if err == nil {
+		return nil, true, nil
+	}
+	if err != pgx.ErrNoRows {
+		return nil, false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	// The key is held by a live record
+	existing, err := s.getRecord(ctx, userID, key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return existing, false, nil
+}
+
+// Complete stores the response produced for a reserved key
+func (s *store) Complete(ctx context.Context, userID, key string, statusCode int, contentType string, body []byte) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $1, content_type = $2, response_body = $3
+		WHERE user_id = $4 AND idempotency_key = $5
+	`
+
+	sealed, err := s.cipher.Encrypt(ctx, string(body))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt idempotent response: %w", err)
+	}
+
+	result, err := s.db.Exec(ctx, query, statusCode, contentType, []byte(sealed), userID, key)
+	if err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("idempotency key not found")
+	}
+
+	return nil
+}
+
+// Release removes a pending record so the client may retry with the same key
+func (s *store) Release(ctx context.Context, userID, key string) error {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE user_id = $1 AND idempotency_key = $2 AND status_code IS NULL
+	`
+
+	_, err := s.db.Exec(ctx, query, userID, key)
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired purges records whose replay window has passed
+func (s *store) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := s.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at < CURRENT_TIMESTAMP`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// Helper functions
+
+func (s *store) getRecord(ctx context.Context, userID, key string) (*Record, error) {
+	var record Record
+
+	query := `
+		SELECT id, user_id, idempotency_key, fingerprint, status_code, content_type, response_body,
+		       expires_at, created_at, updated_at
+		FROM idempotency_keys
+		WHERE user_id = $1 AND idempotency_key = $2
+	`
+
+	err := s.db.QueryRow(ctx, query, userID, key).Scan(
+		&record.ID,
+		&record.UserID,
+		&record.Key,
+		&record.Fingerprint,
+		&record.StatusCode,
+		&record.ContentType,
+		&record.ResponseBody,
+		&record.ExpiresAt,
+		&record.CreatedAt,
+		&record.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("idempotency key not found")
+		}
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+
+	if len(record.ResponseBody) > 0 {
+		body, err := s.cipher.Decrypt(ctx, string(record.ResponseBody))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt idempotent response: %w", err)
+		}
+		record.ResponseBody = []byte(body)
+	}
+
+	return &record, nil
+}
@@ -0,0 +1,127 @@
+package jobs
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
+)
+
+type handler struct {
+	service Service
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// ListJobs retrieves jobs filtered by queue, status and type (admin only)
+func (h *handler) ListJobs(c echo.Context) error {
+	page := 1
+	if p := c.QueryParam("page"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+			page = parsed
+		}
+	}
+
+	pageSize := 20
+	if ps := c.QueryParam("page_size"); ps != "" {
+		if parsed, err := strconv.Atoi(ps); err == nil && parsed > 0 && parsed <= 100 {
+			pageSize = parsed
+		}
+	}
+
+	status := c.QueryParam("status")
+	if status != "" && status != StatusPending && status != StatusRunning && status != StatusCompleted && status != StatusDead {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid status",
+		})
+	}
+
+	req := &ListJobsRequest{
+		Page:     page,
+		PageSize: pageSize,
+		Queue:    c.QueryParam("queue"),
+		Status:   status,
+		JobType:  c.QueryParam("job_type"),
+		Params:   pagination.ParseQuery(c),
+	}
+
+	jobs, err := h.service.ListJobs(c.Request().Context(), req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, jobs)
+}
+
+// GetJob retrieves a single job including its last error (admin only)
+func (h *handler) GetJob(c echo.Context) error {
+	jobID := c.Param("id")
+	if jobID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Job ID is required",
+		})
+	}
+
+	job, err := h.service.GetJob(c.Request().Context(), jobID)
+	if err != nil {
+		if err.Error() == "job not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Job not found",
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to get job",
+		})
+	}
+
+	return c.JSON(http.StatusOK, job)
+}
+
+// RetryJob puts a dead-lettered job back on its queue (admin only)
+func (h *handler) RetryJob(c echo.Context) error {
+	jobID := c.Param("id")
+	if jobID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Job ID is required",
+		})
+	}
+
+	job, err := h.service.RetryJob(c.Request().Context(), jobID)
+	if err != nil {
+		if err.Error() == "job not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Job not found",
+			})
+		}
+		if strings.HasPrefix(err.Error(), "cannot retry") || strings.Contains(err.Error(), "not retryable") {
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retry job",
+		})
+	}
+
+	return c.JSON(http.StatusOK, job)
+}
+
+// GetQueueStats reports pending, running and dead job counts per queue (admin only)
+func (h *handler) GetQueueStats(c echo.Context) error {
+	stats, err := h.service.GetQueueStats(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to get queue stats",
+		})
+	}
+
+	return c.JSON(http.StatusOK, stats)
+}
@@ -0,0 +1,53 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Enqueuer schedules background jobs. The payload is stored as JSON.
+type Enqueuer interface {
+	Enqueue(ctx context.Context, jobType string, payload interface{}, opts *EnqueueOptions) (*Job, error)
+}
+
+// Service defines the interface for job queue business logic
+type Service interface {
+	Enqueuer
+	ListJobs(ctx context.Context, req *ListJobsRequest) (*ListJobsResponse, error)
+	GetJob(ctx context.Context, jobID string) (*Job, error)
+	RetryJob(ctx context.Context, jobID string) (*Job, error)
+	GetQueueStats(ctx context.Context) (*QueueStatsResponse, error)
+}
+
+// Store defines the interface for job queue data persistence
+type Store interface {
+	Insert(ctx context.Context, job *Job) error
+	// Claim locks up to limit due jobs from the queue for the worker and marks
+	// them running, never letting more than queueLimit run on the queue across
+	// all workers
+	Claim(ctx context.Context, queue, workerID string, limit, queueLimit int) ([]Job, error)
+	// Heartbeat renews the worker's lock on a running job
+	Heartbeat(ctx context.Context, jobID, workerID string) error
+	// Complete and Fail return ErrLockLost unless the job is still running under the worker
+	Complete(ctx context.Context, jobID, workerID string) error
+	// Fail records the error and reschedules the job at retryAt, or dead-letters it when retryAt is nil
+	Fail(ctx context.Context, jobID, workerID, lastError string, retryAt *time.Time) error
+	// Retry puts a dead or pending job back on its queue to run now with fresh attempts
+	Retry(ctx context.Context, jobID string) (*Job, error)
+	// RequeueStale releases running jobs whose worker stopped without finishing them
+	RequeueStale(ctx context.Context, lockedBefore time.Time) (int64, error)
+	PurgeCompleted(ctx context.Context, completedBefore time.Time) (int64, error)
+	GetJob(ctx context.Context, jobID string) (*Job, error)
+	ListJobs(ctx context.Context, req *ListJobsRequest) (*ListJobsResponse, error)
+	GetQueueStats(ctx context.Context) ([]QueueStats, error)
+}
+
+// Handler defines the interface for job queue HTTP handlers
+type Handler interface {
+	ListJobs(c echo.Context) error
+	GetJob(c echo.Context) error
+	RetryJob(c echo.Context) error
+	GetQueueStats(c echo.Context) error
+}
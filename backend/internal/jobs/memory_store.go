@@ -0,0 +1,303 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
+)
+
+// memoryStore keeps the queue in memory for tests and demo mode. Jobs are lost
+// when the process exits.
+type memoryStore struct {
+	mu   sync.Mutex
+	jobs map[string]Job
+}
+
+func NewMemoryStore() Store {
+	return &memoryStore{
+		jobs: make(map[string]Job),
+	}
+}
+
+// Insert adds a pending job
+func (s *memoryStore) Insert(ctx context.Context, job *Job) error {
+	now := time.Now()
+	job.ID = uuid.New().String()
+	job.Status = StatusPending
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	s.mu.Lock()
+	s.jobs[job.ID] = *job
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Claim marks up to limit due jobs on the queue as running, oldest run_at
+// first, keeping the queue's running jobs within queueLimit
+func (s *memoryStore) Claim(ctx context.Context, queue, workerID string, limit, queueLimit int) ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var due []Job
+	running := 0
+	for _, job := range s.jobs {
+		if job.Queue != queue {
+			continue
+		}
+		if job.Status == StatusRunning {
+			running++
+		}
+		if job.Status == StatusPending && !job.RunAt.After(now) {
+			due = append(due, job)
+		}
+	}
+	limit = min(limit, queueLimit-running)
+	if limit <= 0 {
+		return nil, nil
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].RunAt.Equal(due[j].RunAt) {
+			return due[i].RunAt.Before(due[j].RunAt)
+		}
+		return due[i].CreatedAt.Before(due[j].CreatedAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	for i := range due {
+		due[i].Status = StatusRunning
+		due[i].Attempts++
+		due[i].LockedAt = &now
+		due[i].LockedBy = &workerID
+		due[i].UpdatedAt = now
+		s.jobs[due[i].ID] = due[i]
+	}
+
+	return due, nil
+}
+
+// Heartbeat moves the lock time forward so the job isn't requeued as stale
+func (s *memoryStore) Heartbeat(ctx context.Context, jobID, workerID string) error {
+	return s.updateLocked(jobID, workerID, func(job *Job) {
+		now := time.Now()
+		job.LockedAt = &now
+	})
+}
+
+// Complete marks a running job as done
+func (s *memoryStore) Complete(ctx context.Context, jobID, workerID string) error {
+	return s.updateLocked(jobID, workerID, func(job *Job) {
+		now := time.Now()
+		job.Status = StatusCompleted
+		job.CompletedAt = &now
+		job.LockedAt = nil
+		job.LockedBy = nil
+		job.LastError = nil
+	})
+}
+
+// Fail records a failed attempt
+func (s *memoryStore) Fail(ctx context.Context, jobID, workerID, lastError string, retryAt *time.Time) error {
+	return s.updateLocked(jobID, workerID, func(job *Job) {
+		job.Status = StatusDead
+		job.RunAt = time.Now()
+		if retryAt != nil {
+			job.Status = StatusPending
+			job.RunAt = *retryAt
+		}
+		job.LastError = &lastError
+		job.LockedAt = nil
+		job.LockedBy = nil
+	})
+}
+
+// Retry resets a dead or pending job so it runs again immediately
+func (s *memoryStore) Retry(ctx context.Context, jobID string) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[jobID]
+	if !ok || (job.Status != StatusDead && job.Status != StatusPending) {
+		return nil, fmt.Errorf("job not found or not retryable")
+	}
+
+	job.Status = StatusPending
+	job.Attempts = 0
+	job.RunAt = time.Now()
+	job.LockedAt = nil
+	job.LockedBy = nil
+	job.UpdatedAt = time.Now()
+	s.jobs[jobID] = job
+
+	return &job, nil
+}
+
+// RequeueStale returns abandoned running jobs to the queue
+func (s *memoryStore) RequeueStale(ctx context.Context, lockedBefore time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var requeued int64
+	lastError := "worker stopped before the job finished"
+	for id, job := range s.jobs {
+		if job.Status != StatusRunning || job.LockedAt == nil || !job.LockedAt.Before(lockedBefore) {
+			continue
+		}
+		job.Status = StatusPending
+		if job.Attempts >= job.MaxAttempts {
+			job.Status = StatusDead
+		}
+		job.RunAt = time.Now()
+		job.LockedAt = nil
+		job.LockedBy = nil
+		job.LastError = &lastError
+		job.UpdatedAt = time.Now()
+		s.jobs[id] = job
+		requeued++
+	}
+
+	return requeued, nil
+}
+
+// PurgeCompleted deletes finished jobs older than the cutoff
+func (s *memoryStore) PurgeCompleted(ctx context.Context, completedBefore time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for id, job := range s.jobs {
+		if job.Status == StatusCompleted && job.CompletedAt != nil && job.CompletedAt.Before(completedBefore) {
+			delete(s.jobs, id)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+// GetJob retrieves a job by ID
+func (s *memoryStore) GetJob(ctx context.Context, jobID string) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[jobID]
+	if !ok {
+		return nil, fmt.Errorf("job not found")
+	}
+
+	return &job, nil
+}
+
+// ListJobs retrieves a filtered, paginated list of jobs, newest first
+func (s *memoryStore) ListJobs(ctx context.Context, req *ListJobsRequest) (*ListJobsResponse, error) {
+	cursor, err := req.DecodeCursor()
+	if err != nil {
+		return nil, err
+	}
+
+	offset := 0
+	if cursor == nil {
+		offset = (req.Page - 1) * req.PageSize
+	}
+
+	s.mu.Lock()
+	var jobs []Job
+	for _, job := range s.jobs {
+		if (req.Queue == "" || job.Queue == req.Queue) &&
+			(req.Status == "" || job.Status == req.Status) &&
+			(req.JobType == "" || job.JobType == req.JobType) {
+			jobs = append(jobs, job)
+		}
+	}
+	s.mu.Unlock()
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return pagination.Compare(jobCursor(jobs[i]), jobCursor(jobs[j])) > 0
+	})
+
+	response := &ListJobsResponse{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	if req.WantTotal() {
+		total := int64(len(jobs))
+		totalPages := pagination.TotalPages(total, req.PageSize)
+		response.Total = &total
+		response.TotalPages = &totalPages
+	}
+
+	window := pagination.Window(jobs, cursor, offset, req.PageSize, jobCursor)
+	response.Jobs, response.NextCursor, response.PrevCursor = pagination.Paginate(window, req.PageSize, cursor, offset, jobCursor)
+
+	return response, nil
+}
+
+// GetQueueStats counts jobs per queue and status
+func (s *memoryStore) GetQueueStats(ctx context.Context) ([]QueueStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	byQueue := make(map[string]*QueueStats)
+	for _, job := range s.jobs {
+		queue, ok := byQueue[job.Queue]
+		if !ok {
+			queue = &QueueStats{Queue: job.Queue}
+			byQueue[job.Queue] = queue
+		}
+		switch job.Status {
+		case StatusPending:
+			if job.RunAt.After(now) {
+				queue.Scheduled++
+			} else {
+				queue.Pending++
+			}
+		case StatusRunning:
+			queue.Running++
+		case StatusCompleted:
+			queue.Completed++
+		case StatusDead:
+			queue.Dead++
+		}
+	}
+
+	var stats []QueueStats
+	for _, queue := range byQueue {
+		stats = append(stats, *queue)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Queue < stats[j].Queue
+	})
+
+	return stats, nil
+}
+
+// Helper functions
+
+func (s *memoryStore) updateLocked(jobID, workerID string, fn func(*Job)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[jobID]
+	if !ok || job.Status != StatusRunning || job.LockedBy == nil || *job.LockedBy != workerID {
+		return ErrLockLost
+	}
+	fn(&job)
+	job.UpdatedAt = time.Now()
+	s.jobs[jobID] = job
+	return nil
+}
+
+func jobCursor(job Job) pagination.Cursor {
+	return pagination.Cursor{SortValue: job.CreatedAt, ID: job.ID}
+}
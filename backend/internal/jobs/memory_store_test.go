@@ -0,0 +1,78 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStaleJobOutcomeBelongsToNewOwner(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	if err := store.Insert(ctx, &Job{Queue: QueueDefault, JobType: "export", MaxAttempts: 3, RunAt: time.Now()}); err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+
+	claimed, _ := store.Claim(ctx, QueueDefault, "worker-a", 1, 1)
+	if len(claimed) != 1 {
+		t.Fatalf("Claim() = %d jobs, want 1", len(claimed))
+	}
+	jobID := claimed[0].ID
+
+	if err := store.Heartbeat(ctx, jobID, "worker-a"); err != nil {
+		t.Fatalf("Heartbeat() by the owner error = %v", err)
+	}
+
+	// worker-a stops renewing its lock, so the job is requeued and claimed again
+	if requeued, _ := store.RequeueStale(ctx, time.Now().Add(time.Second)); requeued != 1 {
+		t.Fatalf("RequeueStale() = %d, want 1", requeued)
+	}
+	if reclaimed, _ := store.Claim(ctx, QueueDefault, "worker-b", 1, 1); len(reclaimed) != 1 {
+		t.Fatalf("Claim() after requeue = %d jobs, want 1", len(reclaimed))
+	}
+
+	if err := store.Heartbeat(ctx, jobID, "worker-a"); !errors.Is(err, ErrLockLost) {
+		t.Errorf("Heartbeat() by the old owner error = %v, want ErrLockLost", err)
+	}
+	if err := store.Complete(ctx, jobID, "worker-a"); !errors.Is(err, ErrLockLost) {
+		t.Errorf("Complete() by the old owner error = %v, want ErrLockLost", err)
+	}
+	if err := store.Fail(ctx, jobID, "worker-a", "timeout", nil); !errors.Is(err, ErrLockLost) {
+		t.Errorf("Fail() by the old owner error = %v, want ErrLockLost", err)
+	}
+
+	if err := store.Complete(ctx, jobID, "worker-b"); err != nil {
+		t.Fatalf("Complete() by the new owner error = %v", err)
+	}
+	if job, _ := store.GetJob(ctx, jobID); job.Status != StatusCompleted || job.Attempts != 2 {
+		t.Errorf("job = %s after %d attempts, want completed after 2", job.Status, job.Attempts)
+	}
+}
+
+func TestClaimKeepsQueueWithinLimitAcrossWorkers(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	for i := 0; i < 5; i++ {
+		if err := store.Insert(ctx, &Job{Queue: QueueDefault, JobType: "export", MaxAttempts: 3, RunAt: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Each worker has free slots of its own, but the queue allows three at once
+	first, _ := store.Claim(ctx, QueueDefault, "worker-a", 2, 3)
+	second, _ := store.Claim(ctx, QueueDefault, "worker-b", 2, 3)
+	if len(first) != 2 || len(second) != 1 {
+		t.Fatalf("Claim() = %d then %d jobs, want 2 then 1", len(first), len(second))
+	}
+	if third, _ := store.Claim(ctx, QueueDefault, "worker-c", 2, 3); len(third) != 0 {
+		t.Errorf("Claim() with the queue full = %d jobs, want none", len(third))
+	}
+
+	if err := store.Complete(ctx, first[0].ID, "worker-a"); err != nil {
+		t.Fatal(err)
+	}
+	if freed, _ := store.Claim(ctx, QueueDefault, "worker-c", 2, 3); len(freed) != 1 {
+		t.Errorf("Claim() after one finished = %d jobs, want 1", len(freed))
+	}
+}
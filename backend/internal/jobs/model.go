@@ -0,0 +1,91 @@
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
+)
+
+// Job statuses. Failed jobs go back to pending with a later run_at until they
+// run out of attempts and are dead-lettered.
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusDead      = "dead"
+)
+
+// ErrLockLost is returned when a job is no longer running under the worker,
+// usually because it was requeued as stale and claimed by another
+var ErrLockLost = errors.New("job is no longer locked by this worker")
+
+// QueueDefault is used when a job is enqueued without a queue
+const QueueDefault = "default"
+
+// DefaultMaxAttempts is used when a job is enqueued without a limit
+const DefaultMaxAttempts = 5
+
+// Job represents a unit of background work
+type Job struct {
+	ID          string          `json:"id" db:"id"`
+	Queue       string          `json:"queue" db:"queue"`
+	JobType     string          `json:"job_type" db:"job_type"`
+	Payload     json.RawMessage `json:"payload" db:"payload"`
+	Status      string          `json:"status" db:"status"`
+	Attempts    int             `json:"attempts" db:"attempts"`
+	MaxAttempts int             `json:"max_attempts" db:"max_attempts"`
+	RunAt       time.Time       `json:"run_at" db:"run_at"`
+	LockedAt    *time.Time      `json:"locked_at,omitempty" db:"locked_at"`
+	LockedBy    *string         `json:"locked_by,omitempty" db:"locked_by"`
+	LastError   *string         `json:"last_error,omitempty" db:"last_error"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// EnqueueOptions control where and when a job runs. The zero value runs the job
+// as soon as possible on the default queue.
+type EnqueueOptions struct {
+	Queue       string
+	RunAt       time.Time
+	Delay       time.Duration
+	MaxAttempts int
+}
+
+// ListJobsRequest filters the job listing
+type ListJobsRequest struct {
+	Page     int    `json:"page" validate:"min=1"`
+	PageSize int    `json:"page_size" validate:"min=1,max=100"`
+	Queue    string `json:"queue,omitempty"`
+	Status   string `json:"status,omitempty" validate:"omitempty,oneof=pending running completed dead"`
+	JobType  string `json:"job_type,omitempty"`
+	pagination.Params
+}
+
+// ListJobsResponse represents the response for listing jobs
+type ListJobsResponse struct {
+	Jobs       []Job   `json:"jobs"`
+	Total      *int64  `json:"total,omitempty"`
+	Page       int     `json:"page"`
+	PageSize   int     `json:"page_size"`
+	TotalPages *int    `json:"total_pages,omitempty"`
+	NextCursor *string `json:"next_cursor,omitempty"`
+	PrevCursor *string `json:"prev_cursor,omitempty"`
+}
+
+// QueueStats counts the jobs in one queue by status
+type QueueStats struct {
+	Queue     string `json:"queue"`
+	Pending   int64  `json:"pending"`
+	Scheduled int64  `json:"scheduled"` // Pending but not yet due
+	Running   int64  `json:"running"`
+	Completed int64  `json:"completed"`
+	Dead      int64  `json:"dead"`
+}
+
+// QueueStatsResponse represents the response for queue statistics
+type QueueStatsResponse struct {
+	Queues []QueueStats `json:"queues"`
+}
@@ -0,0 +1,97 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type service struct {
+	store Store
+}
+
+func NewService(store Store) Service {
+	return &service{
+		store: store,
+	}
+}
+
+// Enqueue stores a job for a worker to pick up
+func (s *service) Enqueue(ctx context.Context, jobType string, payload interface{}, opts *EnqueueOptions) (*Job, error) {
+	if jobType == "" {
+		return nil, fmt.Errorf("job type is required")
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job payload: %w", err)
+	}
+
+	job := &Job{
+		Queue:       QueueDefault,
+		JobType:     jobType,
+		Payload:     encoded,
+		MaxAttempts: DefaultMaxAttempts,
+		RunAt:       time.Now(),
+	}
+
+	if opts != nil {
+		if opts.Queue != "" {
+			job.Queue = opts.Queue
+		}
+		if opts.MaxAttempts > 0 {
+			job.MaxAttempts = opts.MaxAttempts
+		}
+		if !opts.RunAt.IsZero() {
+			job.RunAt = opts.RunAt
+		}
+		if opts.Delay > 0 {
+			job.RunAt = job.RunAt.Add(opts.Delay)
+		}
+	}
+
+	if err := s.store.Insert(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// ListJobs retrieves a page of jobs
+func (s *service) ListJobs(ctx context.Context, req *ListJobsRequest) (*ListJobsResponse, error) {
+	return s.store.ListJobs(ctx, req)
+}
+
+// GetJob retrieves a job by ID
+func (s *service) GetJob(ctx context.Context, jobID string) (*Job, error) {
+	return s.store.GetJob(ctx, jobID)
+}
+
+// RetryJob re-runs a dead-lettered job. Running and completed jobs are left alone.
+func (s *service) RetryJob(ctx context.Context, jobID string) (*Job, error) {
+	job, err := s.store.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Status == StatusRunning || job.Status == StatusCompleted {
+		return nil, fmt.Errorf("cannot retry a %s job", job.Status)
+	}
+
+	return s.store.Retry(ctx, jobID)
+}
+
+// GetQueueStats reports job counts for every queue
+func (s *service) GetQueueStats(ctx context.Context) (*QueueStatsResponse, error) {
+	stats, err := s.store.GetQueueStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if stats == nil {
+		stats = []QueueStats{}
+	}
+
+	return &QueueStatsResponse{Queues: stats}, nil
+}
@@ -0,0 +1,392 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
+)
+
+type store struct {
+	db *pgxpool.Pool
+}
+
+func NewStore(db *pgxpool.Pool) Store {
+	return &store{
+		db: db,
+	}
+}
+
+// jobsKeyset is the stable sort order used for cursor pagination
+var jobsKeyset = pagination.Keyset{SortColumn: "created_at", IDColumn: "id"}
+
+const jobColumns = `id, queue, job_type, payload, status, attempts, max_attempts, run_at, locked_at,
+	       locked_by, last_error, completed_at, created_at, updated_at`
+
+// Insert adds a pending job
+func (s *store) Insert(ctx context.Context, job *Job) error {
+	query := `
+		INSERT INTO jobs (queue, job_type, payload, status, max_attempts, run_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + jobColumns
+
+	row := s.db.QueryRow(ctx, query, job.Queue, job.JobType, job.Payload, StatusPending, job.MaxAttempts, job.RunAt)
+	if err := scanJobInto(row, job); err != nil {
+		return fmt.Errorf("failed to insert job: %w", err)
+	}
+
+	return nil
+}
+
+// Claim locks due jobs with SKIP LOCKED so concurrent workers never share a
+// job. Claims on a queue take a transaction-level advisory lock, so workers
+// count the queue's running jobs one at a time and together stay within
+// queueLimit.
+func (s *store) Claim(ctx context.Context, queue, workerID string, limit, queueLimit int) ([]Job, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('jobs.claim:' || $1))`, queue); err != nil {
+		return nil, fmt.Errorf("failed to lock queue: %w", err)
+	}
+
+	var running int
+	err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM jobs WHERE queue = $1 AND status = $2`, queue, StatusRunning).Scan(&running)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count running jobs: %w", err)
+	}
+	limit = min(limit, queueLimit-running)
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	query := `
+		UPDATE jobs
+		SET status = $1, attempts = attempts + 1, locked_at = NOW(), locked_by = $2
+		WHERE id IN (
+			SELECT id FROM jobs
+			WHERE queue = $3 AND status = $4 AND run_at <= NOW()
+			ORDER BY run_at ASC, created_at ASC
+			LIMIT $5
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + jobColumns
+
+	rows, err := tx.Query(ctx, query, StatusRunning, workerID, queue, StatusPending, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim jobs: %w", err)
+	}
+	claimed, err := scanJobs(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit job claim: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// Heartbeat moves the lock time forward so the job isn't requeued as stale
+func (s *store) Heartbeat(ctx context.Context, jobID, workerID string) error {
+	result, err := s.db.Exec(ctx, `
+		UPDATE jobs
+		SET locked_at = NOW()
+		WHERE id = $1 AND status = $2 AND locked_by = $3
+	`, jobID, StatusRunning, workerID)
+	if err != nil {
+		return fmt.Errorf("failed to renew job lock: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrLockLost
+	}
+
+	return nil
+}
+
+// Complete marks a running job as done
+func (s *store) Complete(ctx context.Context, jobID, workerID string) error {
+	result, err := s.db.Exec(ctx, `
+		UPDATE jobs
+		SET status = $1, completed_at = NOW(), locked_at = NULL, locked_by = NULL, last_error = NULL
+		WHERE id = $2 AND status = $3 AND locked_by = $4
+	`, StatusCompleted, jobID, StatusRunning, workerID)
+	if err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrLockLost
+	}
+
+	return nil
+}
+
+// Fail records a failed attempt
+func (s *store) Fail(ctx context.Context, jobID, workerID, lastError string, retryAt *time.Time) error {
+	status := StatusDead
+	runAt := time.Now()
+	if retryAt != nil {
+		status = StatusPending
+		runAt = *retryAt
+	}
+
+	result, err := s.db.Exec(ctx, `
+		UPDATE jobs
+		SET status = $1, run_at = $2, last_error = $3, locked_at = NULL, locked_by = NULL
+		WHERE id = $4 AND status = $5 AND locked_by = $6
+	`, status, runAt, lastError, jobID, StatusRunning, workerID)
+	if err != nil {
+		return fmt.Errorf("failed to record job failure: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrLockLost
+	}
+
+	return nil
+}
+
+// Retry resets a dead or pending job so it runs again immediately
+func (s *store) Retry(ctx context.Context, jobID string) (*Job, error) {
+	query := `
+		UPDATE jobs
+		SET status = $1, attempts = 0, run_at = NOW(), locked_at = NULL, locked_by = NULL
+		WHERE id = $2 AND status IN ($3, $1)
+		RETURNING ` + jobColumns
+
+	job, err := scanJob(s.db.QueryRow(ctx, query, StatusPending, jobID, StatusDead))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("job not found or not retryable")
+		}
+		return nil, fmt.Errorf("failed to retry job: %w", err)
+	}
+
+	return job, nil
+}
+
+// RequeueStale returns abandoned running jobs to the queue. The attempt they
+// were on still counts, so a job that keeps crashing its worker is dead-lettered.
+func (s *store) RequeueStale(ctx context.Context, lockedBefore time.Time) (int64, error) {
+	result, err := s.db.Exec(ctx, `
+		UPDATE jobs
+		SET status = CASE WHEN attempts >= max_attempts THEN $1 ELSE $2 END,
+		    run_at = NOW(), locked_at = NULL, locked_by = NULL,
+		    last_error = 'worker stopped before the job finished'
+		WHERE status = $3 AND locked_at < $4
+	`, StatusDead, StatusPending, StatusRunning, lockedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue stale jobs: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// PurgeCompleted deletes finished jobs older than the cutoff
+func (s *store) PurgeCompleted(ctx context.Context, completedBefore time.Time) (int64, error) {
+	result, err := s.db.Exec(ctx, `
+		DELETE FROM jobs WHERE status = $1 AND completed_at < $2
+	`, StatusCompleted, completedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge completed jobs: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// GetJob retrieves a job by ID
+func (s *store) GetJob(ctx context.Context, jobID string) (*Job, error) {
+	job, err := scanJob(s.db.QueryRow(ctx, `SELECT `+jobColumns+` FROM jobs WHERE id = $1`, jobID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("job not found")
+		}
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+
+	return job, nil
+}
+
+// ListJobs retrieves a filtered, paginated list of jobs, newest first
+func (s *store) ListJobs(ctx context.Context, req *ListJobsRequest) (*ListJobsResponse, error) {
+	cursor, err := req.DecodeCursor()
+	if err != nil {
+		return nil, err
+	}
+
+	offset := 0
+	if cursor == nil {
+		offset = (req.Page - 1) * req.PageSize
+	}
+
+	var whereClause []string
+	var args []interface{}
+	argIndex := 1
+
+	if req.Queue != "" {
+		whereClause = append(whereClause, fmt.Sprintf("queue = $%d", argIndex))
+		args = append(args, req.Queue)
+		argIndex++
+	}
+
+	if req.Status != "" {
+		whereClause = append(whereClause, fmt.Sprintf("status = $%d", argIndex))
+		args = append(args, req.Status)
+		argIndex++
+	}
+
+	if req.JobType != "" {
+		whereClause = append(whereClause, fmt.Sprintf("job_type = $%d", argIndex))
+		args = append(args, req.JobType)
+		argIndex++
+	}
+
+	whereSQL := ""
+	if len(whereClause) > 0 {
+		whereSQL = "WHERE " + strings.Join(whereClause, " AND ")
+	}
+
+	response := &ListJobsResponse{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	// Count total jobs
+	if req.WantTotal() {
+		var total int64
+		err := s.db.QueryRow(ctx, fmt.Sprintf(`SELECT COUNT(*) FROM jobs %s`, whereSQL), args...).Scan(&total)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count jobs: %w", err)
+		}
+
+		totalPages := pagination.TotalPages(total, req.PageSize)
+		response.Total = &total
+		response.TotalPages = &totalPages
+	}
+
+	// Continue from the cursor position
+	if cursor != nil {
+		keysetSQL, keysetArgs := jobsKeyset.Where(cursor, argIndex)
+		whereClause = append(whereClause, keysetSQL)
+		args = append(args, keysetArgs...)
+		argIndex += len(keysetArgs)
+		whereSQL = "WHERE " + strings.Join(whereClause, " AND ")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM jobs
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, jobColumns, whereSQL, jobsKeyset.OrderBy(cursor), argIndex, argIndex+1)
+
+	args = append(args, req.PageSize+1, offset)
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs, err := scanJobs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	response.Jobs, response.NextCursor, response.PrevCursor = pagination.Paginate(jobs, req.PageSize, cursor, offset,
+		func(job Job) pagination.Cursor {
+			return pagination.Cursor{SortValue: job.CreatedAt, ID: job.ID}
+		})
+
+	return response, nil
+}
+
+// GetQueueStats counts jobs per queue and status
+func (s *store) GetQueueStats(ctx context.Context) ([]QueueStats, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT queue,
+		       COUNT(CASE WHEN status = 'pending' AND run_at <= NOW() THEN 1 END) as pending,
+		       COUNT(CASE WHEN status = 'pending' AND run_at > NOW() THEN 1 END) as scheduled,
+		       COUNT(CASE WHEN status = 'running' THEN 1 END) as running,
+		       COUNT(CASE WHEN status = 'completed' THEN 1 END) as completed,
+		       COUNT(CASE WHEN status = 'dead' THEN 1 END) as dead
+		FROM jobs
+		GROUP BY queue
+		ORDER BY queue ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queue stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []QueueStats
+	for rows.Next() {
+		var queue QueueStats
+		err := rows.Scan(&queue.Queue, &queue.Pending, &queue.Scheduled, &queue.Running, &queue.Completed, &queue.Dead)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan queue stats: %w", err)
+		}
+		stats = append(stats, queue)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return stats, nil
+}
+
+// Helper functions
+
+func scanJob(row pgx.Row) (*Job, error) {
+	var job Job
+	if err := scanJobInto(row, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func scanJobInto(row pgx.Row, job *Job) error {
+	return row.Scan(
+		&job.ID,
+		&job.Queue,
+		&job.JobType,
+		&job.Payload,
+		&job.Status,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.RunAt,
+		&job.LockedAt,
+		&job.LockedBy,
+		&job.LastError,
+		&job.CompletedAt,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	)
+}
+
+func scanJobs(rows pgx.Rows) ([]Job, error) {
+	var jobs []Job
+	for rows.Next() {
+		var job Job
+		if err := scanJobInto(rows, &job); err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return jobs, nil
+}
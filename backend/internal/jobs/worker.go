@@ -0,0 +1,340 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	retryBaseDelay = 30 * time.Second
+	retryMaxDelay  = time.Hour
+
+	// Workers renew the lock on each running job every heartbeatEvery, so a job
+	// whose lock hasn't been renewed for staleLockTimeout belongs to a dead worker
+	heartbeatEvery   = time.Minute
+	staleLockTimeout = 5 * time.Minute
+	maintenanceEvery = time.Minute
+	completedTTL     = 7 * 24 * time.Hour
+)
+
+// HandlerFunc processes one job. Returning an error schedules a retry.
+type HandlerFunc func(ctx context.Context, job *Job) error
+
+// Handle adapts a function taking a typed payload into a HandlerFunc
+func Handle[T any](fn func(ctx context.Context, payload T) error) HandlerFunc {
+	return func(ctx context.Context, job *Job) error {
+		var payload T
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to decode %s payload: %w", job.JobType, err)
+		}
+		return fn(ctx, payload)
+	}
+}
+
+// TaskFunc is periodic housekeeping run by every worker instance
+type TaskFunc func(ctx context.Context) error
+
+// recurringTask is a TaskFunc and how often it runs
+type recurringTask struct {
+	name  string
+	every time.Duration
+	fn    TaskFunc
+}
+
+// Worker polls the configured queues and runs claimed jobs with their
+// registered handler. Each queue has its own concurrency limit, which caps the
+// jobs running on it across every worker polling it, so all instances should
+// be configured with the same limits. Jobs left running by a dead worker count
+// against the limit until they are requeued as stale.
+type Worker struct {
+	store        Store
+	id           string
+	queues       map[string]int
+	pollInterval time.Duration
+
+	mu       sync.RWMutex
+	handlers map[string]HandlerFunc
+	tasks    []recurringTask
+}
+
+func NewWorker(store Store, queues map[string]int, pollInterval time.Duration) *Worker {
+	hostname, _ := os.Hostname()
+	return &Worker{
+		store:        store,
+		id:           fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), uuid.NewString()[:8]),
+		queues:       queues,
+		pollInterval: pollInterval,
+		handlers:     make(map[string]HandlerFunc),
+	}
+}
+
+// Register sets the handler for a job type
+func (w *Worker) Register(jobType string, fn HandlerFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handlers[jobType] = fn
+}
+
+// Every runs fn at the given interval while the worker is running. Every
+// instance runs it, so it must be safe to run concurrently. Tasks must be
+// registered before Start.
+func (w *Worker) Every(name string, every time.Duration, fn TaskFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.tasks = append(w.tasks, recurringTask{name: name, every: every, fn: fn})
+}
+
+// Start runs the queue pollers until ctx is cancelled, then waits for
+// in-flight jobs to finish
+func (w *Worker) Start(ctx context.Context) {
+	logger.Info("Job worker started", zap.String("worker_id", w.id), zap.Any("queues", w.queues))
+
+	var wg sync.WaitGroup
+	for queue, concurrency := range w.queues {
+		wg.Add(1)
+		go func(queue string, concurrency int) {
+			defer wg.Done()
+			w.poll(ctx, queue, concurrency)
+		}(queue, concurrency)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w.maintain(ctx)
+	}()
+
+	w.mu.RLock()
+	for _, task := range w.tasks {
+		wg.Add(1)
+		go func(task recurringTask) {
+			defer wg.Done()
+			w.repeat(ctx, task)
+		}(task)
+	}
+	w.mu.RUnlock()
+
+	wg.Wait()
+	logger.Info("Job worker stopped", zap.String("worker_id", w.id))
+}
+
+// poll claims as many jobs as there are free slots on the queue, within
+// what the queue's limit leaves free across all workers
+func (w *Worker) poll(ctx context.Context, queue string, concurrency int) {
+	slots := make(chan struct{}, concurrency)
+	var running sync.WaitGroup
+	defer running.Wait()
+
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		free := concurrency - len(slots)
+		if free > 0 {
+			jobs, err := w.store.Claim(ctx, queue, w.id, free, concurrency)
+			if err != nil && ctx.Err() == nil {
+				logger.Error("Failed to claim jobs", zap.String("queue", queue), zap.Error(err))
+			}
+
+			for i := range jobs {
+				job := jobs[i]
+				slots <- struct{}{}
+				running.Add(1)
+				go func() {
+					defer func() {
+						<-slots
+						running.Done()
+					}()
+					// Finish the job even if shutdown starts mid-run
+					w.run(context.WithoutCancel(ctx), &job)
+				}()
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run executes one job and records the outcome. The outcome is dropped if
+// the job was requeued and claimed elsewhere while it ran.
+func (w *Worker) run(ctx context.Context, job *Job) {
+	jobCtx, cancel := context.WithCancel(ctx)
+	beating := make(chan struct{})
+	go func() {
+		defer close(beating)
+		w.heartbeat(jobCtx, job.ID, cancel)
+	}()
+
+	err := w.execute(jobCtx, job)
+	cancel()
+	<-beating
+
+	if err == nil {
+		if err := w.store.Complete(ctx, job.ID, w.id); err != nil {
+			logger.Error("Failed to mark job completed", zap.String("job_id", job.ID), zap.Error(err))
+		}
+		return
+	}
+
+	var retryAt *time.Time
+	if job.Attempts < job.MaxAttempts {
+		next := time.Now().Add(Backoff(job.Attempts))
+		retryAt = &next
+	}
+
+	logger.Error("Job failed",
+		zap.String("job_id", job.ID),
+		zap.String("job_type", job.JobType),
+		zap.Int("attempt", job.Attempts),
+		zap.Bool("dead", retryAt == nil),
+		zap.Error(err),
+	)
+
+	if err := w.store.Fail(ctx, job.ID, w.id, err.Error(), retryAt); err != nil {
+		logger.Error("Failed to record job failure", zap.String("job_id", job.ID), zap.Error(err))
+	}
+}
+
+// heartbeat renews the job's lock until ctx ends. If another worker has taken
+// the job over, lost is called to cancel this run.
+func (w *Worker) heartbeat(ctx context.Context, jobID string, lost context.CancelFunc) {
+	ticker := time.NewTicker(heartbeatEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := w.store.Heartbeat(ctx, jobID, w.id)
+		if errors.Is(err, ErrLockLost) {
+			logger.Error("Lost the lock on a running job", zap.String("job_id", jobID))
+			lost()
+			return
+		}
+		if err != nil && ctx.Err() == nil {
+			logger.Error("Failed to renew job lock", zap.String("job_id", jobID), zap.Error(err))
+		}
+	}
+}
+
+func (w *Worker) execute(ctx context.Context, job *Job) (err error) {
+	w.mu.RLock()
+	fn, ok := w.handlers[job.JobType]
+	w.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("no handler registered for job type %s", job.JobType)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	return fn(ctx, job)
+}
+
+// maintain requeues jobs abandoned by crashed workers and purges old completed jobs
+func (w *Worker) maintain(ctx context.Context) {
+	ticker := time.NewTicker(maintenanceEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if requeued, err := w.store.RequeueStale(ctx, time.Now().Add(-staleLockTimeout)); err != nil {
+			logger.Error("Failed to requeue stale jobs", zap.Error(err))
+		} else if requeued > 0 {
+			logger.Info("Requeued stale jobs", zap.Int64("count", requeued))
+		}
+
+		if _, err := w.store.PurgeCompleted(ctx, time.Now().Add(-completedTTL)); err != nil {
+			logger.Error("Failed to purge completed jobs", zap.Error(err))
+		}
+	}
+}
+
+// repeat runs a recurring task on its interval until ctx is cancelled
+func (w *Worker) repeat(ctx context.Context, task recurringTask) {
+	ticker := time.NewTicker(task.every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := task.fn(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("Recurring task failed", zap.String("task", task.name), zap.Error(err))
+		}
+	}
+}
+
+// Backoff returns the delay before retrying after the given attempt:
+// exponential from 30s, capped at an hour, with up to 20% jitter
+func Backoff(attempt int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempt && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+
+	return delay + time.Duration(rand.Int63n(int64(delay)/5+1))
+}
+
+// ParseQueues parses a "queue:concurrency" list such as "default:4,privacy:1".
+// A queue without a limit gets a concurrency of 1. Limits apply to the queue
+// across every worker.
+func ParseQueues(spec string) (map[string]int, error) {
+	queues := make(map[string]int)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, limit, found := strings.Cut(part, ":")
+		concurrency := 1
+		if found {
+			parsed, err := strconv.Atoi(strings.TrimSpace(limit))
+			if err != nil || parsed < 1 {
+				return nil, fmt.Errorf("invalid concurrency for queue %s", name)
+			}
+			concurrency = parsed
+		}
+		queues[strings.TrimSpace(name)] = concurrency
+	}
+
+	if len(queues) == 0 {
+		return nil, fmt.Errorf("no job queues configured")
+	}
+
+	return queues, nil
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
 )
 
 type handler struct {
@@ -183,7 +184,15 @@ func (h *handler) ListJourneyEntries(c echo.Context) error {
 		endDate = &ed
 	}
 
-	entries, err := h.service.ListJourneyEntries(c.Request().Context(), userID, page, pageSize, startDate, endDate)
+	req := &ListJourneyEntriesRequest{
+		Page:      page,
+		PageSize:  pageSize,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Params:    pagination.ParseQuery(c),
+	}
+
+	entries, err := h.service.ListJourneyEntries(c.Request().Context(), userID, req)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": err.Error(),
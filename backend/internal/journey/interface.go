@@ -15,7 +15,7 @@ type Service interface {
 	GetTodaysEntry(ctx context.Context, userID string) (*JourneyEntry, error)
 	UpdateJourneyEntry(ctx context.Context, userID, entryID string, req *UpdateJourneyEntryRequest) (*JourneyEntry, error)
 	DeleteJourneyEntry(ctx context.Context, userID, entryID string) error
-	ListJourneyEntries(ctx context.Context, userID string, page, pageSize int, startDate, endDate *string) (*ListJourneyEntriesResponse, error)
+	ListJourneyEntries(ctx context.Context, userID string, req *ListJourneyEntriesRequest) (*ListJourneyEntriesResponse, error)
 
 	// Journey Goals
 	CreateJourneyGoal(ctx context.Context, userID string, req *CreateJourneyGoalRequest) (*JourneyGoal, error)
@@ -26,7 +26,11 @@ type Service interface {
 	// Journey Analytics
 	GetJourneyStats(ctx context.Context, userID string) (*JourneyStats, error)
 	GetJourneyInsights(ctx context.Context, userID string) (*JourneyInsights, error)
+	// GetCheckInSummaries returns the check-in summary of each user with
+	// journey entries, keyed by user ID
+	GetCheckInSummaries(ctx context.Context, userIDs []string) (map[string]CheckInSummary, error)
 	ListJourneyMilestones(ctx context.Context, userID string, limit int) ([]JourneyMilestone, error)
+	CheckMilestones(ctx context.Context, job MilestoneCheckJob) error
 }
 
 // Store defines the interface for journey data persistence
@@ -37,7 +41,7 @@ type Store interface {
 	GetJourneyEntryByDate(ctx context.Context, userID, date string) (*JourneyEntry, error)
 	UpdateJourneyEntry(ctx context.Context, userID, entryID string, req *UpdateJourneyEntryRequest) (*JourneyEntry, error)
 	DeleteJourneyEntry(ctx context.Context, userID, entryID string) error
-	ListJourneyEntries(ctx context.Context, userID string, page, pageSize int, startDate, endDate *string) (*ListJourneyEntriesResponse, error)
+	ListJourneyEntries(ctx context.Context, userID string, req *ListJourneyEntriesRequest) (*ListJourneyEntriesResponse, error)
 
 	// Journey Goals
 	CreateJourneyGoal(ctx context.Context, userID string, req *CreateJourneyGoalRequest, targetDate *time.Time) (*JourneyGoal, error)
@@ -52,6 +56,7 @@ type Store interface {
 
 	// Journey Analytics
 	GetJourneyStats(ctx context.Context, userID string) (*JourneyStats, error)
+	GetCheckInSummaries(ctx context.Context, userIDs []string) (map[string]CheckInSummary, error)
 }
 
 // Handler defines the interface for journey HTTP handlers
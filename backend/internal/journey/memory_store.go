@@ -0,0 +1,464 @@
+package journey
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
+)
+
+// memoryStore keeps journey entries, goals and milestones in memory for tests
+// and demo mode. It does not publish domain events.
+type memoryStore struct {
+	mu         sync.RWMutex
+	entries    map[string]JourneyEntry
+	goals      map[string]JourneyGoal
+	milestones []JourneyMilestone
+}
+
+func NewMemoryStore() Store {
+	return &memoryStore{
+		entries: make(map[string]JourneyEntry),
+		goals:   make(map[string]JourneyGoal),
+	}
+}
+
+// Journey Entries Implementation
+
+func (s *memoryStore) CreateJourneyEntry(ctx context.Context, userID string, entryDate time.Time, req *CreateJourneyEntryRequest) (*JourneyEntry, error) {
+	existingEntry, err := s.GetJourneyEntryByDate(ctx, userID, entryDate.Format("2006-01-02"))
+	if err == nil && existingEntry != nil {
+		return nil, fmt.Errorf("entry already exists for this date")
+	}
+
+	entry := JourneyEntry{
+		ID:            uuid.New().String(),
+		UserID:        userID,
+		EntryDate:     entryDate,
+		MoodRating:    req.MoodRating,
+		AnxietyLevel:  req.AnxietyLevel,
+		SleepQuality:  req.SleepQuality,
+		EnergyLevel:   req.EnergyLevel,
+		Notes:         req.Notes,
+		Activities:    nonNil(req.Activities),
+		Symptoms:      nonNil(req.Symptoms),
+		GratitudeNote: req.GratitudeNote,
+		IsPrivate:     req.IsPrivate,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	s.mu.Lock()
+	s.entries[entry.ID] = entry
+	s.mu.Unlock()
+
+	return &entry, nil
+}
+
+func (s *memoryStore) GetJourneyEntryByID(ctx context.Context, userID, entryID string) (*JourneyEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.entries[entryID]
+	if !ok || entry.UserID != userID {
+		return nil, fmt.Errorf("journey entry not found")
+	}
+
+	return &entry, nil
+}
+
+func (s *memoryStore) GetJourneyEntryByDate(ctx context.Context, userID, date string) (*JourneyEntry, error) {
+	for _, entry := range s.userEntries(userID) {
+		if entry.EntryDate.Format("2006-01-02") == date {
+			return &entry, nil
+		}
+	}
+
+	return nil, fmt.Errorf("journey entry not found")
+}
+
+func (s *memoryStore) UpdateJourneyEntry(ctx context.Context, userID, entryID string, req *UpdateJourneyEntryRequest) (*JourneyEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[entryID]
+	if !ok || entry.UserID != userID {
+		return nil, fmt.Errorf("journey entry not found")
+	}
+
+	if req.MoodRating != nil {
+		entry.MoodRating = *req.MoodRating
+	}
+	if req.AnxietyLevel != nil {
+		entry.AnxietyLevel = req.AnxietyLevel
+	}
+	if req.SleepQuality != nil {
+		entry.SleepQuality = req.SleepQuality
+	}
+	if req.EnergyLevel != nil {
+		entry.EnergyLevel = req.EnergyLevel
+	}
+	if req.Notes != nil {
+		entry.Notes = req.Notes
+	}
+	if req.Activities != nil {
+		entry.Activities = req.Activities
+	}
+	if req.Symptoms != nil {
+		entry.Symptoms = req.Symptoms
+	}
+	if req.GratitudeNote != nil {
+		entry.GratitudeNote = req.GratitudeNote
+	}
+	if req.IsPrivate != nil {
+		entry.IsPrivate = *req.IsPrivate
+	}
+	entry.UpdatedAt = time.Now()
+	s.entries[entryID] = entry
+
+	return &entry, nil
+}
+
+func (s *memoryStore) DeleteJourneyEntry(ctx context.Context, userID, entryID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[entryID]
+	if !ok || entry.UserID != userID {
+		return fmt.Errorf("journey entry not found")
+	}
+	delete(s.entries, entryID)
+
+	return nil
+}
+
+func (s *memoryStore) ListJourneyEntries(ctx context.Context, userID string, req *ListJourneyEntriesRequest) (*ListJourneyEntriesResponse, error) {
+	cursor, err := req.DecodeCursor()
+	if err != nil {
+		return nil, err
+	}
+
+	offset := 0
+	if cursor == nil {
+		offset = (req.Page - 1) * req.PageSize
+	}
+
+	entries := make([]JourneyEntry, 0)
+	for _, entry := range s.userEntries(userID) {
+		date := entry.EntryDate.Format("2006-01-02")
+		if req.StartDate != nil && *req.StartDate != "" && date < *req.StartDate {
+			continue
+		}
+		if req.EndDate != nil && *req.EndDate != "" && date > *req.EndDate {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	response := &ListJourneyEntriesResponse{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	if req.WantTotal() {
+		total := int64(len(entries))
+		totalPages := pagination.TotalPages(total, req.PageSize)
+		response.Total = &total
+		response.TotalPages = &totalPages
+	}
+
+	window := pagination.Window(entries, cursor, offset, req.PageSize, entryCursor)
+	response.Entries, response.NextCursor, response.PrevCursor = pagination.Paginate(window, req.PageSize, cursor, offset, entryCursor)
+	if response.Entries == nil {
+		response.Entries = make([]JourneyEntry, 0)
+	}
+
+	return response, nil
+}
+
+// Journey Goals Implementation
+
+func (s *memoryStore) CreateJourneyGoal(ctx context.Context, userID string, req *CreateJourneyGoalRequest, targetDate *time.Time) (*JourneyGoal, error) {
+	goal := JourneyGoal{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		Title:       req.Title,
+		Description: req.Description,
+		TargetDate:  targetDate,
+		GoalType:    req.GoalType,
+		Status:      GoalStatusActive,
+		IsCompleted: false,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	s.mu.Lock()
+	s.goals[goal.ID] = goal
+	s.mu.Unlock()
+
+	return &goal, nil
+}
+
+func (s *memoryStore) UpdateJourneyGoal(ctx context.Context, userID, goalID string, req *UpdateJourneyGoalRequest, targetDate *time.Time) (*JourneyGoal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	goal, ok := s.goals[goalID]
+	if !ok || goal.UserID != userID {
+		return nil, fmt.Errorf("journey goal not found")
+	}
+
+	if req.Title != nil {
+		goal.Title = *req.Title
+	}
+	if req.Description != nil {
+		goal.Description = req.Description
+	}
+	if targetDate != nil {
+		goal.TargetDate = targetDate
+	}
+	if req.Status != nil {
+		goal.Status = *req.Status
+		if *req.Status == GoalStatusCompleted {
+			now := time.Now()
+			goal.IsCompleted = true
+			goal.CompletedAt = &now
+		} else {
+			goal.IsCompleted = false
+			goal.CompletedAt = nil
+		}
+	}
+	goal.UpdatedAt = time.Now()
+	s.goals[goalID] = goal
+
+	return &goal, nil
+}
+
+func (s *memoryStore) DeleteJourneyGoal(ctx context.Context, userID, goalID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	goal, ok := s.goals[goalID]
+	if !ok || goal.UserID != userID {
+		return fmt.Errorf("journey goal not found")
+	}
+	delete(s.goals, goalID)
+
+	return nil
+}
+
+func (s *memoryStore) ListJourneyGoals(ctx context.Context, userID string, status *string) ([]JourneyGoal, error) {
+	s.mu.RLock()
+	goals := make([]JourneyGoal, 0)
+	for _, goal := range s.goals {
+		if goal.UserID == userID && (status == nil || *status == "" || goal.Status == *status) {
+			goals = append(goals, goal)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(goals, func(i, j int) bool {
+		return goals[i].CreatedAt.After(goals[j].CreatedAt)
+	})
+
+	return goals, nil
+}
+
+// Journey Milestones Implementation
+
+func (s *memoryStore) CreateJourneyMilestone(ctx context.Context, milestone *JourneyMilestone) error {
+	if milestone.ID == "" {
+		milestone.ID = uuid.New().String()
+	}
+
+	s.mu.Lock()
+	s.milestones = append(s.milestones, *milestone)
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *memoryStore) ListJourneyMilestones(ctx context.Context, userID string, limit int) ([]JourneyMilestone, error) {
+	s.mu.RLock()
+	milestones := make([]JourneyMilestone, 0)
+	for _, milestone := range s.milestones {
+		if milestone.UserID == userID {
+			milestones = append(milestones, milestone)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.SliceStable(milestones, func(i, j int) bool {
+		return milestones[i].AchievedAt.After(milestones[j].AchievedAt)
+	})
+	if len(milestones) > limit {
+		milestones = milestones[:limit]
+	}
+
+	return milestones, nil
+}
+
+func (s *memoryStore) CheckMilestoneExists(ctx context.Context, userID, milestoneType string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, milestone := range s.milestones {
+		if milestone.UserID == userID && milestone.MilestoneType == milestoneType {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Journey Analytics Implementation
+
+func (s *memoryStore) GetJourneyStats(ctx context.Context, userID string) (*JourneyStats, error) {
+	stats := &JourneyStats{
+		MoodBreakdown:    make(map[string]int64),
+		WeeklyMoodData:   make([]DailyMoodData, 0, 7),
+		RecentMilestones: make([]JourneyMilestone, 0),
+	}
+
+	entries := s.userEntries(userID)
+	stats.TotalEntries = int64(len(entries))
+
+	dates := make([]time.Time, 0, len(entries))
+	moods := make(map[string]int)
+	var moodTotal int
+	var recent, previous []int
+	today := time.Now().Truncate(24 * time.Hour)
+	for _, entry := range entries {
+		dates = append(dates, entry.EntryDate)
+		moods[entry.EntryDate.Format("2006-01-02")] = entry.MoodRating
+		moodTotal += entry.MoodRating
+		stats.MoodBreakdown[fmt.Sprintf("%d", entry.MoodRating)]++
+
+		switch {
+		case !entry.EntryDate.Before(today.AddDate(0, 0, -14)):
+			recent = append(recent, entry.MoodRating)
+		case !entry.EntryDate.Before(today.AddDate(0, 0, -28)):
+			previous = append(previous, entry.MoodRating)
+		}
+	}
+
+	stats.CurrentStreak, stats.LongestStreak = streaks(dates, time.Now())
+	if len(entries) > 0 {
+		stats.AverageMood = float64(moodTotal) / float64(len(entries))
+	}
+
+	stats.MoodTrend = moodTrend(average(recent), average(previous))
+
+	s.mu.RLock()
+	for _, goal := range s.goals {
+		if goal.UserID != userID {
+			continue
+		}
+		switch goal.Status {
+		case GoalStatusCompleted:
+			stats.CompletedGoals++
+		case GoalStatusActive:
+			stats.ActiveGoals++
+		}
+	}
+	for _, milestone := range s.milestones {
+		if milestone.UserID == userID {
+			stats.TotalMilestones++
+		}
+	}
+	s.mu.RUnlock()
+
+	stats.RecentMilestones, _ = s.ListJourneyMilestones(ctx, userID, 3)
+
+	for i := 6; i >= 0; i-- {
+		dateStr := time.Now().AddDate(0, 0, -i).Format("2006-01-02")
+		dayData := DailyMoodData{Date: dateStr}
+		if rating, ok := moods[dateStr]; ok {
+			dayData.HasEntry = true
+			dayData.MoodRating = &rating
+		}
+		stats.WeeklyMoodData = append(stats.WeeklyMoodData, dayData)
+	}
+
+	return stats, nil
+}
+
+func (s *memoryStore) GetCheckInSummaries(ctx context.Context, userIDs []string) (map[string]CheckInSummary, error) {
+	summaries := make(map[string]CheckInSummary)
+	today := time.Now().Truncate(24 * time.Hour)
+	for _, userID := range userIDs {
+		entries := s.userEntries(userID)
+		if len(entries) == 0 {
+			continue
+		}
+
+		var recent, previous []int
+		for _, entry := range entries {
+			switch {
+			case entry.IsPrivate:
+			case !entry.EntryDate.Before(today.AddDate(0, 0, -14)):
+				recent = append(recent, entry.MoodRating)
+			case !entry.EntryDate.Before(today.AddDate(0, 0, -28)):
+				previous = append(previous, entry.MoodRating)
+			}
+		}
+
+		// Entries are sorted newest first
+		lastCheckIn := entries[0].EntryDate
+		summaries[userID] = CheckInSummary{
+			UserID:      userID,
+			LastCheckIn: &lastCheckIn,
+			MoodTrend:   moodTrend(average(recent), average(previous)),
+		}
+	}
+
+	return summaries, nil
+}
+
+// Helper functions
+
+// userEntries returns the user's entries, newest day first
+func (s *memoryStore) userEntries(userID string) []JourneyEntry {
+	s.mu.RLock()
+	var entries []JourneyEntry
+	for _, entry := range s.entries {
+		if entry.UserID == userID {
+			entries = append(entries, entry)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return pagination.Compare(entryCursor(entries[i]), entryCursor(entries[j])) > 0
+	})
+	return entries
+}
+
+func entryCursor(entry JourneyEntry) pagination.Cursor {
+	return pagination.Cursor{SortValue: entry.EntryDate, ID: entry.ID}
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return make([]string, 0)
+	}
+	return values
+}
+
+// average returns the mean of values, which is not valid when there are none
+func average(values []int) sql.NullFloat64 {
+	if len(values) == 0 {
+		return sql.NullFloat64{}
+	}
+
+	var total int
+	for _, value := range values {
+		total += value
+	}
+	return sql.NullFloat64{Float64: float64(total) / float64(len(values)), Valid: true}
+}
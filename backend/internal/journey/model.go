@@ -2,6 +2,8 @@ package journey
 
 import (
 	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
 )
 
 // JourneyEntry represents a daily journey entry
@@ -13,10 +15,10 @@ type JourneyEntry struct {
 	AnxietyLevel  *int      `json:"anxiety_level,omitempty" db:"anxiety_level"`
 	SleepQuality  *int      `json:"sleep_quality,omitempty" db:"sleep_quality"`
 	EnergyLevel   *int      `json:"energy_level,omitempty" db:"energy_level"`
-	Notes         *string   `json:"notes,omitempty" db:"notes"`
+	Notes         *string   `json:"notes,omitempty" db:"notes" encrypt:"true"`
 	Activities    []string  `json:"activities" db:"activities"`
 	Symptoms      []string  `json:"symptoms" db:"symptoms"`
-	GratitudeNote *string   `json:"gratitude_note,omitempty" db:"gratitude_note"`
+	GratitudeNote *string   `json:"gratitude_note,omitempty" db:"gratitude_note" encrypt:"true"`
 	IsPrivate     bool      `json:"is_private" db:"is_private"`
 	CreatedAt     time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
@@ -91,13 +93,24 @@ type UpdateJourneyGoalRequest struct {
 	Status      *string `json:"status,omitempty" validate:"omitempty,oneof=active completed paused cancelled"`
 }
 
+// ListJourneyEntriesRequest represents the request for listing journey entries
+type ListJourneyEntriesRequest struct {
+	Page      int     `json:"page" validate:"min=1"`
+	PageSize  int     `json:"page_size" validate:"min=1,max=100"`
+	StartDate *string `json:"start_date,omitempty"` // YYYY-MM-DD format
+	EndDate   *string `json:"end_date,omitempty"`   // YYYY-MM-DD format
+	pagination.Params
+}
+
 // ListJourneyEntriesResponse represents the response for listing journey entries
 type ListJourneyEntriesResponse struct {
 	Entries    []JourneyEntry `json:"entries"`
-	Total      int64          `json:"total"`
+	Total      *int64         `json:"total,omitempty"`
 	Page       int            `json:"page"`
 	PageSize   int            `json:"page_size"`
-	TotalPages int            `json:"total_pages"`
+	TotalPages *int           `json:"total_pages,omitempty"`
+	NextCursor *string        `json:"next_cursor,omitempty"`
+	PrevCursor *string        `json:"prev_cursor,omitempty"`
 }
 
 // JourneyStats represents journey statistics for a user
@@ -122,6 +135,14 @@ type DailyMoodData struct {
 	HasEntry   bool   `json:"has_entry"`
 }
 
+// CheckInSummary is a user's latest journey activity as shown to their care
+// team. The mood trend leaves out private entries.
+type CheckInSummary struct {
+	UserID      string     `json:"user_id"`
+	LastCheckIn *time.Time `json:"last_check_in,omitempty"`
+	MoodTrend   string     `json:"mood_trend"` // "improving", "stable", "declining"
+}
+
 // JourneyInsights represents insights for the user
 type JourneyInsights struct {
 	MoodPatterns    []string `json:"mood_patterns"`
@@ -158,6 +179,24 @@ const (
 	MilestoneYearComplete = "year_complete"
 )
 
+// Background job type and the queue it runs on
+const (
+	JobQueue           = "journey"
+	JobCheckMilestones = "journey.check_milestones"
+)
+
+// What prompted a milestone check
+const (
+	MilestoneTriggerEntry = "entry"
+	MilestoneTriggerGoal  = "goal"
+)
+
+// MilestoneCheckJob is the payload for a queued milestone check
+type MilestoneCheckJob struct {
+	UserID  string `json:"user_id"`
+	Trigger string `json:"trigger"`
+}
+
 // Helper methods for JourneyEntry
 func (je *JourneyEntry) GetMoodEmoji() string {
 	switch je.MoodRating {
@@ -4,15 +4,21 @@ import (
 	"context"
 	"fmt"
 	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/jobs"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"go.uber.org/zap"
 )
 
 type service struct {
 	store Store
+	queue jobs.Enqueuer
 }
 
-func NewService(store Store) Service {
+func NewService(store Store, queue jobs.Enqueuer) Service {
 	return &service{
 		store: store,
+		queue: queue,
 	}
 }
 
@@ -51,7 +57,7 @@ func (s *service) CreateJourneyEntry(ctx context.Context, userID string, req *Cr
 	}
 
 	// Check for milestones after creating entry
-	go s.checkAndAwardMilestones(context.Background(), userID)
+	s.queueMilestoneCheck(ctx, userID, MilestoneTriggerEntry)
 
 	return entry, nil
 }
@@ -99,19 +105,19 @@ func (s *service) DeleteJourneyEntry(ctx context.Context, userID, entryID string
 }
 
 // ListJourneyEntries retrieves a paginated list of journey entries for a user
-func (s *service) ListJourneyEntries(ctx context.Context, userID string, page, pageSize int, startDate, endDate *string) (*ListJourneyEntriesResponse, error) {
+func (s *service) ListJourneyEntries(ctx context.Context, userID string, req *ListJourneyEntriesRequest) (*ListJourneyEntriesResponse, error) {
 	if userID == "" {
 		return nil, fmt.Errorf("user ID is required")
 	}
 
-	if page < 1 {
-		page = 1
+	if req.Page < 1 {
+		req.Page = 1
 	}
-	if pageSize < 1 || pageSize > 100 {
-		pageSize = 30
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 30
 	}
 
-	return s.store.ListJourneyEntries(ctx, userID, page, pageSize, startDate, endDate)
+	return s.store.ListJourneyEntries(ctx, userID, req)
 }
 
 // CreateJourneyGoal creates a new journey goal
@@ -141,7 +147,7 @@ func (s *service) CreateJourneyGoal(ctx context.Context, userID string, req *Cre
 	}
 
 	// Check for first goal milestone
-	go s.checkFirstGoalMilestone(context.Background(), userID)
+	s.queueMilestoneCheck(ctx, userID, MilestoneTriggerGoal)
 
 	return goal, nil
 }
@@ -201,6 +207,15 @@ func (s *service) GetJourneyStats(ctx context.Context, userID string) (*JourneyS
 	return s.store.GetJourneyStats(ctx, userID)
 }
 
+// GetCheckInSummaries retrieves the check-in summaries of several users at once
+func (s *service) GetCheckInSummaries(ctx context.Context, userIDs []string) (map[string]CheckInSummary, error) {
+	if len(userIDs) == 0 {
+		return map[string]CheckInSummary{}, nil
+	}
+
+	return s.store.GetCheckInSummaries(ctx, userIDs)
+}
+
 // GetJourneyInsights generates insights for a user's journey
 func (s *service) GetJourneyInsights(ctx context.Context, userID string) (*JourneyInsights, error) {
 	if userID == "" {
@@ -337,114 +352,100 @@ func isValidGoalStatus(status string) bool {
 	return false
 }
 
-// Milestone checking functions (run in background)
-func (s *service) checkAndAwardMilestones(ctx context.Context, userID string) {
-	// Check for first entry milestone
-	s.checkFirstEntryMilestone(ctx, userID)
-
-	// Check for streak milestones
-	s.checkStreakMilestones(ctx, userID)
+// CheckMilestones awards any milestones the user has newly earned. It runs as a
+// background job, so errors are returned to have the job retried.
+func (s *service) CheckMilestones(ctx context.Context, job MilestoneCheckJob) error {
+	if job.Trigger == MilestoneTriggerGoal {
+		return s.checkFirstGoalMilestone(ctx, job.UserID)
+	}
 
-	// Check for mood stability milestone
-	s.checkMoodStabilityMilestone(ctx, userID)
-}
+	// Check for first entry milestone
+	if err := s.checkFirstEntryMilestone(ctx, job.UserID); err != nil {
+		return err
+	}
 
-func (s *service) checkFirstEntryMilestone(ctx context.Context, userID string) {
-	exists, err := s.store.CheckMilestoneExists(ctx, userID, MilestoneFirstEntry)
-	if err != nil || exists {
-		return
+	stats, err := s.store.GetJourneyStats(ctx, job.UserID)
+	if err != nil {
+		return err
 	}
 
-	milestone := &JourneyMilestone{
-		UserID:        userID,
-		MilestoneType: MilestoneFirstEntry,
-		Title:         "First Entry",
-		Description:   stringPtr("Congratulations on starting your mental health journey! 🌟"),
-		AchievedAt:    time.Now(),
-		CreatedAt:     time.Now(),
+	// Check for streak milestones
+	if err := s.checkStreakMilestones(ctx, job.UserID, stats); err != nil {
+		return err
 	}
 
-	s.store.CreateJourneyMilestone(ctx, milestone)
+	// Check for mood stability milestone
+	return s.checkMoodStabilityMilestone(ctx, job.UserID, stats)
 }
 
-func (s *service) checkStreakMilestones(ctx context.Context, userID string) {
-	stats, err := s.store.GetJourneyStats(ctx, userID)
+// Milestone checking functions (run in background)
+func (s *service) queueMilestoneCheck(ctx context.Context, userID, trigger string) {
+	_, err := s.queue.Enqueue(ctx, JobCheckMilestones, MilestoneCheckJob{
+		UserID:  userID,
+		Trigger: trigger,
+	}, &jobs.EnqueueOptions{Queue: JobQueue})
 	if err != nil {
-		return
+		// The entry or goal is saved; a missed milestone is awarded on the next check
+		logger.Error("Failed to queue milestone check", zap.String("user_id", userID), zap.Error(err))
 	}
+}
 
+func (s *service) checkFirstEntryMilestone(ctx context.Context, userID string) error {
+	return s.awardMilestone(ctx, userID, MilestoneFirstEntry, "First Entry",
+		"Congratulations on starting your mental health journey! 🌟")
+}
+
+func (s *service) checkStreakMilestones(ctx context.Context, userID string, stats *JourneyStats) error {
 	// Check for week streak milestone
 	if stats.CurrentStreak >= 7 {
-		exists, err := s.store.CheckMilestoneExists(ctx, userID, MilestoneWeekStreak)
-		if err == nil && !exists {
-			milestone := &JourneyMilestone{
-				UserID:        userID,
-				MilestoneType: MilestoneWeekStreak,
-				Title:         "7-Day Streak",
-				Description:   stringPtr("Amazing! You've maintained a 7-day streak! 🔥"),
-				AchievedAt:    time.Now(),
-				CreatedAt:     time.Now(),
-			}
-			s.store.CreateJourneyMilestone(ctx, milestone)
+		err := s.awardMilestone(ctx, userID, MilestoneWeekStreak, "7-Day Streak",
+			"Amazing! You've maintained a 7-day streak! 🔥")
+		if err != nil {
+			return err
 		}
 	}
 
 	// Check for month streak milestone
 	if stats.CurrentStreak >= 30 {
-		exists, err := s.store.CheckMilestoneExists(ctx, userID, MilestoneMonthStreak)
-		if err == nil && !exists {
-			milestone := &JourneyMilestone{
-				UserID:        userID,
-				MilestoneType: MilestoneMonthStreak,
-				Title:         "30-Day Streak",
-				Description:   stringPtr("Incredible! You've maintained a 30-day streak! 🏆"),
-				AchievedAt:    time.Now(),
-				CreatedAt:     time.Now(),
-			}
-			s.store.CreateJourneyMilestone(ctx, milestone)
-		}
+		return s.awardMilestone(ctx, userID, MilestoneMonthStreak, "30-Day Streak",
+			"Incredible! You've maintained a 30-day streak! 🏆")
 	}
-}
 
-func (s *service) checkMoodStabilityMilestone(ctx context.Context, userID string) {
-	stats, err := s.store.GetJourneyStats(ctx, userID)
-	if err != nil {
-		return
-	}
+	return nil
+}
 
+func (s *service) checkMoodStabilityMilestone(ctx context.Context, userID string, stats *JourneyStats) error {
 	// Check if user has good average mood for at least 14 days
 	if stats.TotalEntries >= 14 && stats.AverageMood >= 4.0 {
-		exists, err := s.store.CheckMilestoneExists(ctx, userID, MilestoneMoodStable)
-		if err == nil && !exists {
-			milestone := &JourneyMilestone{
-				UserID:        userID,
-				MilestoneType: MilestoneMoodStable,
-				Title:         "Mood Stability",
-				Description:   stringPtr("You're maintaining great mental wellness! Keep it up! 💙"),
-				AchievedAt:    time.Now(),
-				CreatedAt:     time.Now(),
-			}
-			s.store.CreateJourneyMilestone(ctx, milestone)
-		}
+		return s.awardMilestone(ctx, userID, MilestoneMoodStable, "Mood Stability",
+			"You're maintaining great mental wellness! Keep it up! 💙")
 	}
+
+	return nil
+}
+
+func (s *service) checkFirstGoalMilestone(ctx context.Context, userID string) error {
+	return s.awardMilestone(ctx, userID, MilestoneFirstGoal, "First Goal Set",
+		"Great job setting your first goal! 🎯")
 }
 
-func (s *service) checkFirstGoalMilestone(ctx context.Context, userID string) {
-	exists, err := s.store.CheckMilestoneExists(ctx, userID, MilestoneFirstGoal)
+// awardMilestone creates the milestone unless the user already has it
+func (s *service) awardMilestone(ctx context.Context, userID, milestoneType, title, description string) error {
+	exists, err := s.store.CheckMilestoneExists(ctx, userID, milestoneType)
 	if err != nil || exists {
-		return
+		return err
 	}
 
 	milestone := &JourneyMilestone{
 		UserID:        userID,
-		MilestoneType: MilestoneFirstGoal,
-		Title:         "First Goal Set",
-		Description:   stringPtr("Great job setting your first goal! 🎯"),
+		MilestoneType: milestoneType,
+		Title:         title,
+		Description:   stringPtr(description),
 		AchievedAt:    time.Now(),
 		CreatedAt:     time.Now(),
 	}
 
-	s.store.CreateJourneyMilestone(ctx, milestone)
+	return s.store.CreateJourneyMilestone(ctx, milestone)
 }
 
 // Helper function
@@ -11,15 +11,20 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/lib/pq"
+	"github.com/perinatal-mental-health-app/backend/internal/encryption"
+	"github.com/perinatal-mental-health-app/backend/internal/events"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
 )
 
 type store struct {
-	db *pgxpool.Pool
+	db     *pgxpool.Pool
+	cipher encryption.Cipher
 }
 
-func NewStore(db *pgxpool.Pool) Store {
+func NewStore(db *pgxpool.Pool, cipher encryption.Cipher) Store {
 	return &store{
-		db: db,
+		db:     db,
+		cipher: cipher,
 	}
 }
 
@@ -50,6 +55,12 @@ func (s *store) CreateJourneyEntry(ctx context.Context, userID string, entryDate
 		UpdatedAt:     time.Now(),
 	}
 
+	// Seal free-text fields; the caller gets the plaintext entry back
+	sealed := *entry
+	if err := s.cipher.EncryptFields(ctx, &sealed); err != nil {
+		return nil, fmt.Errorf("failed to encrypt journey entry: %w", err)
+	}
+
 	query := `
 		INSERT INTO journey_entries (
 			id, user_id, entry_date, mood_rating, anxiety_level, sleep_quality, 
@@ -60,17 +71,36 @@ func (s *store) CreateJourneyEntry(ctx context.Context, userID string, entryDate
 		)
 	`
 
-	_, err = s.db.Exec(ctx, query,
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, query,
 		entry.ID, entry.UserID, entry.EntryDate, entry.MoodRating,
 		entry.AnxietyLevel, entry.SleepQuality, entry.EnergyLevel,
-		entry.Notes, pq.Array(entry.Activities), pq.Array(entry.Symptoms),
-		entry.GratitudeNote, entry.IsPrivate, entry.CreatedAt, entry.UpdatedAt,
+		sealed.Notes, pq.Array(entry.Activities), pq.Array(entry.Symptoms),
+		sealed.GratitudeNote, entry.IsPrivate, entry.CreatedAt, entry.UpdatedAt,
 	)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to create journey entry: %w", err)
 	}
 
+	err = events.Publish(ctx, tx, events.JourneyEntryCreated, entry.ID, events.JourneyEntryCreatedPayload{
+		EntryID:   entry.ID,
+		UserID:    entry.UserID,
+		EntryDate: entry.EntryDate.Format("2006-01-02"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return entry, nil
 }
 
@@ -84,7 +114,7 @@ func (s *store) GetJourneyEntryByID(ctx context.Context, userID, entryID string)
 	`
 
 	row := s.db.QueryRow(ctx, query, entryID, userID)
-	return s.scanJourneyEntry(row)
+	return s.scanJourneyEntry(ctx, row)
 }
 
 func (s *store) GetJourneyEntryByDate(ctx context.Context, userID, date string) (*JourneyEntry, error) {
@@ -97,7 +127,7 @@ func (s *store) GetJourneyEntryByDate(ctx context.Context, userID, date string)
 	`
 
 	row := s.db.QueryRow(ctx, query, userID, date)
-	return s.scanJourneyEntry(row)
+	return s.scanJourneyEntry(ctx, row)
 }
 
 func (s *store) UpdateJourneyEntry(ctx context.Context, userID, entryID string, req *UpdateJourneyEntryRequest) (*JourneyEntry, error) {
@@ -131,8 +161,12 @@ func (s *store) UpdateJourneyEntry(ctx context.Context, userID, entryID string,
 	}
 
 	if req.Notes != nil {
+		notes, err := s.cipher.Encrypt(ctx, *req.Notes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt notes: %w", err)
+		}
 		setParts = append(setParts, fmt.Sprintf("notes = $%d", argIndex))
-		args = append(args, *req.Notes)
+		args = append(args, notes)
 		argIndex++
 	}
 
@@ -149,8 +183,12 @@ func (s *store) UpdateJourneyEntry(ctx context.Context, userID, entryID string,
 	}
 
 	if req.GratitudeNote != nil {
+		gratitudeNote, err := s.cipher.Encrypt(ctx, *req.GratitudeNote)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt gratitude note: %w", err)
+		}
 		setParts = append(setParts, fmt.Sprintf("gratitude_note = $%d", argIndex))
-		args = append(args, *req.GratitudeNote)
+		args = append(args, gratitudeNote)
 		argIndex++
 	}
 
@@ -175,7 +213,7 @@ func (s *store) UpdateJourneyEntry(ctx context.Context, userID, entryID string,
 	`, strings.Join(setParts, ", "))
 
 	row := s.db.QueryRow(ctx, query, args...)
-	return s.scanJourneyEntry(row)
+	return s.scanJourneyEntry(ctx, row)
 }
 
 func (s *store) DeleteJourneyEntry(ctx context.Context, userID, entryID string) error {
@@ -193,91 +231,100 @@ func (s *store) DeleteJourneyEntry(ctx context.Context, userID, entryID string)
 	return nil
 }
 
-func (s *store) ListJourneyEntries(ctx context.Context, userID string, page, pageSize int, startDate, endDate *string) (*ListJourneyEntriesResponse, error) {
-	// Count total entries
-	countQuery := `SELECT COUNT(*) FROM journey_entries WHERE user_id = $1`
-	countArgs := []interface{}{userID}
-
-	if startDate != nil && *startDate != "" {
-		countQuery += ` AND entry_date >= $2`
-		countArgs = append(countArgs, *startDate)
+// journeyEntriesKeyset orders entries newest day first
+var journeyEntriesKeyset = pagination.Keyset{SortColumn: "entry_date", SortType: "date", IDColumn: "id"}
 
-		if endDate != nil && *endDate != "" {
-			countQuery += ` AND entry_date <= $3`
-			countArgs = append(countArgs, *endDate)
-		}
-	} else if endDate != nil && *endDate != "" {
-		countQuery += ` AND entry_date <= $2`
-		countArgs = append(countArgs, *endDate)
+func (s *store) ListJourneyEntries(ctx context.Context, userID string, req *ListJourneyEntriesRequest) (*ListJourneyEntriesResponse, error) {
+	cursor, err := req.DecodeCursor()
+	if err != nil {
+		return nil, err
 	}
 
-	var total int64
-	err := s.db.QueryRow(ctx, countQuery, countArgs...).Scan(&total)
-	if err != nil {
-		return nil, fmt.Errorf("failed to count journey entries: %w", err)
+	offset := 0
+	if cursor == nil {
+		offset = (req.Page - 1) * req.PageSize
 	}
 
-	// Calculate pagination
-	offset := (page - 1) * pageSize
-	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+	whereSQL := `WHERE user_id = $1`
+	args := []interface{}{userID}
+	argIndex := 2
 
-	// Initialize empty entries slice - this ensures we never return nil
-	entries := make([]JourneyEntry, 0)
+	if req.StartDate != nil && *req.StartDate != "" {
+		whereSQL += fmt.Sprintf(` AND entry_date >= $%d`, argIndex)
+		args = append(args, *req.StartDate)
+		argIndex++
+	}
 
-	// Only query if there are entries to avoid unnecessary database calls
-	if total > 0 {
-		// Get entries
-		query := `
-			SELECT id, user_id, entry_date, mood_rating, anxiety_level, sleep_quality,
-				   energy_level, notes, activities, symptoms, gratitude_note, is_private,
-				   created_at, updated_at
-			FROM journey_entries
-			WHERE user_id = $1
-		`
-		args := []interface{}{userID}
-		argIndex := 2
-
-		if startDate != nil && *startDate != "" {
-			query += fmt.Sprintf(` AND entry_date >= $%d`, argIndex)
-			args = append(args, *startDate)
-			argIndex++
+	if req.EndDate != nil && *req.EndDate != "" {
+		whereSQL += fmt.Sprintf(` AND entry_date <= $%d`, argIndex)
+		args = append(args, *req.EndDate)
+		argIndex++
+	}
 
-			if endDate != nil && *endDate != "" {
-				query += fmt.Sprintf(` AND entry_date <= $%d`, argIndex)
-				args = append(args, *endDate)
-				argIndex++
-			}
-		} else if endDate != nil && *endDate != "" {
-			query += fmt.Sprintf(` AND entry_date <= $%d`, argIndex)
-			args = append(args, *endDate)
-			argIndex++
+	response := &ListJourneyEntriesResponse{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	// Count total entries
+	if req.WantTotal() {
+		var total int64
+		err := s.db.QueryRow(ctx, `SELECT COUNT(*) FROM journey_entries `+whereSQL, args...).Scan(&total)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count journey entries: %w", err)
 		}
 
-		query += fmt.Sprintf(` ORDER BY entry_date DESC LIMIT $%d OFFSET $%d`, argIndex, argIndex+1)
-		args = append(args, pageSize, offset)
+		totalPages := pagination.TotalPages(total, req.PageSize)
+		response.Total = &total
+		response.TotalPages = &totalPages
+	}
+
+	// Continue from the cursor position
+	if cursor != nil {
+		keysetSQL, keysetArgs := journeyEntriesKeyset.Where(cursor, argIndex)
+		whereSQL += ` AND ` + keysetSQL
+		args = append(args, keysetArgs...)
+		argIndex += len(keysetArgs)
+	}
+
+	// Get entries, reading one extra row to detect a further page
+	query := fmt.Sprintf(`
+		SELECT id, user_id, entry_date, mood_rating, anxiety_level, sleep_quality,
+			   energy_level, notes, activities, symptoms, gratitude_note, is_private,
+			   created_at, updated_at
+		FROM journey_entries
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, whereSQL, journeyEntriesKeyset.OrderBy(cursor), argIndex, argIndex+1)
+	args = append(args, req.PageSize+1, offset)
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list journey entries: %w", err)
+	}
+	defer rows.Close()
 
-		rows, err := s.db.Query(ctx, query, args...)
+	// Initialize empty entries slice - this ensures we never return nil
+	entries := make([]JourneyEntry, 0)
+	for rows.Next() {
+		entry, err := s.scanJourneyEntryFromRows(ctx, rows)
 		if err != nil {
-			return nil, fmt.Errorf("failed to list journey entries: %w", err)
+			return nil, fmt.Errorf("failed to scan journey entry: %w", err)
 		}
-		defer rows.Close()
+		entries = append(entries, *entry)
+	}
 
-		for rows.Next() {
-			entry, err := s.scanJourneyEntryFromRows(rows)
-			if err != nil {
-				return nil, fmt.Errorf("failed to scan journey entry: %w", err)
-			}
-			entries = append(entries, *entry)
-		}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
 	}
 
-	return &ListJourneyEntriesResponse{
-		Entries:    entries,
-		Total:      total,
-		Page:       page,
-		PageSize:   pageSize,
-		TotalPages: totalPages,
-	}, nil
+	response.Entries, response.NextCursor, response.PrevCursor = pagination.Paginate(entries, req.PageSize, cursor, offset,
+		func(entry JourneyEntry) pagination.Cursor {
+			return pagination.Cursor{SortValue: entry.EntryDate, ID: entry.ID}
+		})
+
+	return response, nil
 }
 
 // Journey Goals Implementation
@@ -571,9 +618,48 @@ func (s *store) GetJourneyStats(ctx context.Context, userID string) (*JourneySta
 	return stats, nil
 }
 
+// GetCheckInSummaries gets the last check-in and mood trend of each user in one query
+func (s *store) GetCheckInSummaries(ctx context.Context, userIDs []string) (map[string]CheckInSummary, error) {
+	query := `
+		SELECT
+			user_id,
+			MAX(entry_date) as last_check_in,
+			AVG(CASE WHEN NOT is_private AND entry_date >= CURRENT_DATE - INTERVAL '14 days' THEN mood_rating END) as recent_avg,
+			AVG(CASE WHEN NOT is_private AND entry_date >= CURRENT_DATE - INTERVAL '28 days' AND entry_date < CURRENT_DATE - INTERVAL '14 days' THEN mood_rating END) as previous_avg
+		FROM journey_entries
+		WHERE user_id = ANY($1::uuid[])
+		GROUP BY user_id
+	`
+
+	rows, err := s.db.Query(ctx, query, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get check-in summaries: %w", err)
+	}
+	defer rows.Close()
+
+	summaries := make(map[string]CheckInSummary)
+	for rows.Next() {
+		var summary CheckInSummary
+		var lastCheckIn time.Time
+		var recentAvg, previousAvg sql.NullFloat64
+		if err := rows.Scan(&summary.UserID, &lastCheckIn, &recentAvg, &previousAvg); err != nil {
+			return nil, fmt.Errorf("failed to scan check-in summary: %w", err)
+		}
+		summary.LastCheckIn = &lastCheckIn
+		summary.MoodTrend = moodTrend(recentAvg, previousAvg)
+		summaries[summary.UserID] = summary
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return summaries, nil
+}
+
 // Helper functions
 
-func (s *store) scanJourneyEntry(row pgx.Row) (*JourneyEntry, error) {
+func (s *store) scanJourneyEntry(ctx context.Context, row pgx.Row) (*JourneyEntry, error) {
 	entry := &JourneyEntry{}
 	var activities, symptoms pq.StringArray
 
@@ -604,10 +690,14 @@ func (s *store) scanJourneyEntry(row pgx.Row) (*JourneyEntry, error) {
 		entry.Symptoms = []string(symptoms)
 	}
 
+	if err := s.cipher.DecryptFields(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to decrypt journey entry: %w", err)
+	}
+
 	return entry, nil
 }
 
-func (s *store) scanJourneyEntryFromRows(rows pgx.Rows) (*JourneyEntry, error) {
+func (s *store) scanJourneyEntryFromRows(ctx context.Context, rows pgx.Rows) (*JourneyEntry, error) {
 	entry := &JourneyEntry{}
 	var activities, symptoms pq.StringArray
 
@@ -635,6 +725,10 @@ func (s *store) scanJourneyEntryFromRows(rows pgx.Rows) (*JourneyEntry, error) {
 		entry.Symptoms = []string(symptoms)
 	}
 
+	if err := s.cipher.DecryptFields(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to decrypt journey entry: %w", err)
+	}
+
 	return entry, nil
 }
 
@@ -711,13 +805,20 @@ func (s *store) calculateStreaks(ctx context.Context, userID string) (int, int)
 		}
 	}
 
+	return streaks(dates, time.Now())
+}
+
+// streaks returns the current and longest runs of consecutive days in dates,
+// which must be ordered newest first. The current streak may start today or
+// yesterday.
+func streaks(dates []time.Time, now time.Time) (int, int) {
 	if len(dates) == 0 {
 		return 0, 0
 	}
 
 	// Calculate current streak
 	currentStreak := 0
-	today := time.Now().Truncate(24 * time.Hour)
+	today := now.Truncate(24 * time.Hour)
 	expectedDate := today
 
 	for _, date := range dates {
@@ -775,7 +876,16 @@ func (s *store) calculateMoodTrend(ctx context.Context, userID string) string {
 
 	var recentAvg, previousAvg sql.NullFloat64
 	err := s.db.QueryRow(ctx, query, userID).Scan(&recentAvg, &previousAvg)
-	if err != nil || !recentAvg.Valid || !previousAvg.Valid {
+	if err != nil {
+		return "stable"
+	}
+
+	return moodTrend(recentAvg, previousAvg)
+}
+
+// moodTrend compares the average mood of the last 14 days with the 14 days before
+func moodTrend(recentAvg, previousAvg sql.NullFloat64) string {
+	if !recentAvg.Valid || !previousAvg.Valid {
 		return "stable"
 	}
 
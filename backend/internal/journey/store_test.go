@@ -0,0 +1,41 @@
+package journey
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStreaks(t *testing.T) {
+	now := time.Date(2024, 3, 15, 18, 30, 0, 0, time.UTC)
+	daysAgo := func(days ...int) []time.Time {
+		dates := make([]time.Time, len(days))
+		for i, d := range days {
+			dates[i] = now.AddDate(0, 0, -d)
+		}
+		return dates
+	}
+
+	tests := []struct {
+		name        string
+		dates       []time.Time
+		wantCurrent int
+		wantLongest int
+	}{
+		{"no entries", nil, 0, 0},
+		{"today only", daysAgo(0), 1, 1},
+		{"three days up to today", daysAgo(0, 1, 2), 3, 3},
+		{"streak ending yesterday", daysAgo(1, 2), 2, 2},
+		{"last entry two days ago", daysAgo(2, 3, 4), 0, 3},
+		{"gap breaks current streak", daysAgo(0, 1, 3, 4, 5), 2, 3},
+		{"longest streak earlier", daysAgo(0, 5, 6, 7, 8), 1, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, longest := streaks(tt.dates, now)
+			if current != tt.wantCurrent || longest != tt.wantLongest {
+				t.Errorf("streaks() = (%d, %d), want (%d, %d)", current, longest, tt.wantCurrent, tt.wantLongest)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Package mail sends transactional email to users.
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	config "github.com/perinatal-mental-health-app/backend/internal/configs"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"go.uber.org/zap"
+)
+
+// Message is a plain-text email to one recipient
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers email. Senders are called from background jobs, so a failed
+// send is retried with the job.
+type Sender interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// SenderFromConfig returns an SMTP sender when MAIL_SMTP_HOST is set. Without
+// it, messages are only logged, and nothing reaches the recipient.
+func SenderFromConfig(cfg *config.Config) (Sender, error) {
+	if cfg.MailSMTPHost == "" {
+		logger.Error("MAIL_SMTP_HOST is not set; emails will be logged without their contents instead of sent")
+		return logSender{}, nil
+	}
+	if cfg.MailFrom == "" {
+		return nil, fmt.Errorf("MAIL_FROM is required when MAIL_SMTP_HOST is set")
+	}
+	if (cfg.MailSMTPUsername == "") != (cfg.MailSMTPPassword == "") {
+		return nil, fmt.Errorf("set both or neither of MAIL_SMTP_USERNAME and MAIL_SMTP_PASSWORD")
+	}
+
+	sender := &smtpSender{
+		addr: net.JoinHostPort(cfg.MailSMTPHost, strconv.Itoa(cfg.MailSMTPPort)),
+		from: cfg.MailFrom,
+	}
+	if cfg.MailSMTPUsername != "" {
+		sender.auth = smtp.PlainAuth("", cfg.MailSMTPUsername, cfg.MailSMTPPassword, cfg.MailSMTPHost)
+	}
+
+	return sender, nil
+}
+
+// smtpSender delivers messages through an SMTP relay. The connection is
+// upgraded with STARTTLS when the relay offers it, which PlainAuth requires
+// for anything but localhost.
+type smtpSender struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// Send delivers the message
+func (s *smtpSender) Send(ctx context.Context, message Message) error {
+	data, err := formatMessage(s.from, message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if err := smtp.SendMail(s.addr, s.auth, s.from, []string{message.To}, data); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+// logSender writes who each message is for to the log instead of sending it.
+// Bodies can hold sign-in links and tokens, so they are never logged.
+type logSender struct{}
+
+// NewLogSender returns a Sender for demo mode, where no email is delivered
+func NewLogSender() Sender {
+	return logSender{}
+}
+
+// Send logs the message's recipient and subject
+func (logSender) Send(ctx context.Context, message Message) error {
+	logger.Info("Email not sent; no mail server is configured",
+		zap.String("to", message.To),
+		zap.String("subject", message.Subject),
+	)
+	return nil
+}
+
+// Helper functions
+
+// formatMessage builds the RFC 5322 message for one recipient. Header values
+// can't contain line breaks, which would let them add headers of their own.
+func formatMessage(from string, message Message, now time.Time) ([]byte, error) {
+	for _, value := range []string{from, message.To, message.Subject} {
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, fmt.Errorf("email header contains a line break")
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + message.To + "\r\n")
+	b.WriteString("Subject: " + message.Subject + "\r\n")
+	b.WriteString("Date: " + now.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(message.Body, "\r\n", "\n"), "\n", "\r\n"))
+
+	return []byte(b.String()), nil
+}
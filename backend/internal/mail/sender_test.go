@@ -0,0 +1,41 @@
+package mail
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatMessage(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	data, err := formatMessage("no-reply@example.com", Message{
+		To:      "parent@example.com",
+		Subject: "Confirm your new email address",
+		Body:    "Your code is 1234.\nIt expires in a day.",
+	}, now)
+	if err != nil {
+		t.Fatalf("formatMessage() error = %v", err)
+	}
+
+	got := string(data)
+	for _, want := range []string{
+		"From: no-reply@example.com\r\n",
+		"To: parent@example.com\r\n",
+		"Subject: Confirm your new email address\r\n",
+		"\r\n\r\nYour code is 1234.\r\nIt expires in a day.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatMessage() = %q, want it to contain %q", got, want)
+		}
+	}
+
+	for _, message := range []Message{
+		{To: "parent@example.com\r\nBcc: someone@example.com", Subject: "Hello"},
+		{To: "parent@example.com", Subject: "Hello\nBcc: someone@example.com"},
+	} {
+		if _, err := formatMessage("no-reply@example.com", message, now); err == nil {
+			t.Errorf("formatMessage(%+v) succeeded, want an error for the line break", message)
+		}
+	}
+}
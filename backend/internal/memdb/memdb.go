@@ -0,0 +1,222 @@
+// Package memdb holds the rows that the in-memory stores share between modules.
+// It stands in for the users, user_profiles and catalog tables that the Postgres
+// stores join on, so a user registered through auth can be found by referrals
+// and a service created by an admin can be referred to.
+package memdb
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Catalog item types, matching referral types
+const (
+	ItemService      = "service"
+	ItemResource     = "resource"
+	ItemSupportGroup = "support_group"
+)
+
+// User mirrors a row in the users table
+type User struct {
+	ID                  string
+	Email               string
+	FullName            string
+	Role                string
+	PasswordHash        string
+	IsActive            bool
+	AccountStatus       string // Defaults from IsActive on insert
+	DeletionScheduledAt *time.Time
+	LastLoginAt         *time.Time
+	TokensRevokedAt     *time.Time
+	IsGuest             bool
+	GuestExpiresAt      *time.Time
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// Profile mirrors a row in the user_profiles table. Values are kept in plain text.
+type Profile struct {
+	UserID           string
+	PhoneNumber      *string
+	DateOfBirth      *time.Time
+	Address          *string
+	EmergencyContact *string
+	Preferences      *string // JSON string
+	PerinatalDetails *string // JSON string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// Item is the part of a service, resource or support group that other modules read
+type Item struct {
+	Type           string
+	ID             string
+	Title          string
+	Description    string
+	IsActive       bool
+	OrganisationID *string
+}
+
+// DB is a set of tables guarded by a single lock
+type DB struct {
+	mu       sync.RWMutex
+	users    map[string]User
+	profiles map[string]Profile
+	items    map[string]Item
+}
+
+func New() *DB {
+	return &DB{
+		users:    make(map[string]User),
+		profiles: make(map[string]Profile),
+		items:    make(map[string]Item),
+	}
+}
+
+// InsertUser adds a user and their profile. Emails are unique, as in Postgres.
+func (db *DB) InsertUser(user User, profile Profile) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, exists := db.users[user.ID]; exists {
+		return fmt.Errorf("user %s already exists", user.ID)
+	}
+	for _, existing := range db.users {
+		if existing.Email == user.Email {
+			return fmt.Errorf("email already registered")
+		}
+	}
+
+	if user.AccountStatus == "" {
+		user.AccountStatus = "active"
+		if !user.IsActive {
+			user.AccountStatus = "suspended"
+		}
+	}
+	profile.UserID = user.ID
+	db.users[user.ID] = user
+	db.profiles[user.ID] = profile
+
+	return nil
+}
+
+// User returns the user with the given ID
+func (db *DB) User(userID string) (User, bool) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	user, ok := db.users[userID]
+	return user, ok
+}
+
+// UserByEmail returns the user with the given email
+func (db *DB) UserByEmail(email string) (User, bool) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	for _, user := range db.users {
+		if user.Email == email {
+			return user, true
+		}
+	}
+	return User{}, false
+}
+
+// Users returns every user in no particular order
+func (db *DB) Users() []User {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	users := make([]User, 0, len(db.users))
+	for _, user := range db.users {
+		users = append(users, user)
+	}
+	return users
+}
+
+// UpdateUser applies update to the user with the given ID. Returning false from
+// update leaves the row unchanged, like an UPDATE whose WHERE clause did not match.
+func (db *DB) UpdateUser(userID string, update func(*User) bool) (User, bool) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	user, ok := db.users[userID]
+	if !ok || !update(&user) {
+		return User{}, false
+	}
+	db.users[userID] = user
+
+	return user, true
+}
+
+// DeleteUserIf removes the user with the given ID and their profile if match
+// returns true. Rows other modules keep for the user are not cascaded.
+func (db *DB) DeleteUserIf(userID string, match func(User) bool) bool {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	user, ok := db.users[userID]
+	if !ok || !match(user) {
+		return false
+	}
+	delete(db.users, userID)
+	delete(db.profiles, userID)
+
+	return true
+}
+
+// Profile returns the profile of the user with the given ID
+func (db *DB) Profile(userID string) (Profile, bool) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	profile, ok := db.profiles[userID]
+	return profile, ok
+}
+
+// UpdateProfile applies update to the profile of the user with the given ID
+func (db *DB) UpdateProfile(userID string, update func(*Profile)) (Profile, bool) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	profile, ok := db.profiles[userID]
+	if !ok {
+		return Profile{}, false
+	}
+	update(&profile)
+	db.profiles[userID] = profile
+
+	return profile, true
+}
+
+// PutItem adds or replaces a catalog item
+func (db *DB) PutItem(item Item) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.items[itemKey(item.Type, item.ID)] = item
+}
+
+// Item returns the catalog item of the given type and ID
+func (db *DB) Item(itemType, itemID string) (Item, bool) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	item, ok := db.items[itemKey(itemType, itemID)]
+	return item, ok
+}
+
+// DeleteItem removes a catalog item
+func (db *DB) DeleteItem(itemType, itemID string) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	delete(db.items, itemKey(itemType, itemID))
+}
+
+// Helper functions
+
+func itemKey(itemType, itemID string) string {
+	return itemType + "/" + itemID
+}
@@ -0,0 +1,239 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/audit"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"go.uber.org/zap"
+)
+
+// Audit records the request in the audit log, whatever the outcome. Place it
+// before RoleMiddleware to capture denied attempts. targetParam names the path
+// parameter holding the target ID; when it is empty on a POST, the ID is taken
+// from the "id" field of the response.
+//
+// The entry is written once the handler sets the response status, before any of
+// the body reaches the client. If it can't be written the client gets a 500
+// instead, so nothing is disclosed without a record; a write the handler has
+// already committed stands.
+//
+// For a successful write to a target with a snapshot, the fields whose values
+// changed are recorded. Other writes record the top-level JSON field names of
+// the request body. Values are never recorded.
+func Audit(recorder audit.Recorder, snapshots audit.Snapshots, action, targetType, targetParam string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			var requestFields []string
+			if req.Method != http.MethodGet && req.Body != nil {
+				body, err := io.ReadAll(req.Body)
+				if err != nil {
+					return c.JSON(http.StatusBadRequest, map[string]string{
+						"error": "Invalid request format",
+					})
+				}
+				req.Body = io.NopCloser(bytes.NewReader(body))
+				requestFields = jsonFieldNames(body)
+			}
+
+			// Use a fresh context so a disconnected client cannot skip the record
+			saveCtx, cancel := context.WithTimeout(context.WithoutCancel(req.Context()), 5*time.Second)
+			defer cancel()
+
+			snapshot := snapshots[targetType]
+			var before interface{}
+			if snapshot != nil && targetParam != "" && req.Method != http.MethodGet {
+				// A target that can't be loaded is left to the handler to reject
+				before, _ = snapshot(saveCtx, c.Param(targetParam))
+			}
+
+			record := func(status int, body []byte) error {
+				targetID := ""
+				if targetParam != "" {
+					targetID = c.Param(targetParam)
+				} else if body != nil {
+					targetID = createdID(body)
+				}
+
+				fieldsChanged := requestFields
+				if status < http.StatusOK || status >= http.StatusMultipleChoices {
+					fieldsChanged = nil
+				} else if before != nil {
+					// A target the write removed has no fields left to compare
+					fieldsChanged = nil
+					if after, err := snapshot(saveCtx, targetID); err == nil {
+						fieldsChanged = audit.ChangedFields(before, after)
+					}
+				}
+
+				requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+				if requestID == "" {
+					requestID = req.Header.Get(echo.HeaderXRequestID)
+				}
+
+				err := recorder.Record(saveCtx, &audit.Entry{
+					ActorID:       contextString(c, "user_id"),
+					ActorRole:     contextString(c, "user_role"),
+					Action:        action,
+					TargetType:    targetType,
+					TargetID:      targetID,
+					FieldsChanged: fieldsChanged,
+					RequestID:     requestID,
+					IPAddress:     c.RealIP(),
+					UserAgent:     req.UserAgent(),
+					StatusCode:    status,
+				})
+				if err != nil {
+					logger.Error("Failed to write audit entry",
+						zap.Error(err),
+						zap.String("action", action),
+						zap.String("request_id", requestID),
+					)
+				}
+				return err
+			}
+
+			writer := &auditWriter{
+				ResponseWriter: c.Response().Writer,
+				record:         record,
+				// Creates are held back until the new ID can be read from the body
+				hold: targetParam == "" && req.Method == http.MethodPost,
+			}
+			c.Response().Writer = writer
+
+			handlerErr := next(c)
+			if handlerErr != nil {
+				c.Error(handlerErr)
+			}
+
+			writer.finish(c.Response().Status)
+			if writer.failed {
+				c.Response().Status = http.StatusInternalServerError
+			}
+
+			return nil
+		}
+	}
+}
+
+// Helper functions
+
+func contextString(c echo.Context, key string) string {
+	if value, ok := c.Get(key).(string); ok {
+		return value
+	}
+	return ""
+}
+
+// jsonFieldNames returns the sorted top-level keys of a JSON object body
+func jsonFieldNames(body []byte) []string {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// createdID reads the "id" of the resource returned by a create handler
+func createdID(body []byte) string {
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(body, &created); err != nil {
+		return ""
+	}
+	return created.ID
+}
+
+// auditWriter records the audit entry when the response status is set and
+// replaces the response with a 500 if that fails. With hold set, the status and
+// body are kept back until finish so the entry can include the created ID.
+type auditWriter struct {
+	http.ResponseWriter
+	record func(status int, body []byte) error
+	hold   bool
+
+	status   int
+	body     bytes.Buffer
+	recorded bool
+	failed   bool
+}
+
+func (w *auditWriter) WriteHeader(status int) {
+	if w.recorded {
+		return
+	}
+	if w.hold {
+		w.status = status
+		return
+	}
+
+	w.recorded = true
+	if err := w.record(status, nil); err != nil {
+		w.fail()
+		return
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *auditWriter) Write(b []byte) (int, error) {
+	switch {
+	case w.failed:
+		return len(b), nil
+	case w.hold:
+		return w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// finish records the entry for a held response, or for a handler that never
+// wrote one, and sends what was held back
+func (w *auditWriter) finish(status int) {
+	if w.recorded {
+		return
+	}
+	w.recorded = true
+
+	if w.status != 0 {
+		status = w.status
+	}
+	if err := w.record(status, w.body.Bytes()); err != nil {
+		w.fail()
+		return
+	}
+
+	if w.hold && w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+		w.ResponseWriter.Write(w.body.Bytes())
+	}
+}
+
+// fail answers 500 in place of the handler's response
+func (w *auditWriter) fail() {
+	w.failed = true
+
+	header := w.Header()
+	for key := range header {
+		if key != echo.HeaderXRequestID {
+			header.Del(key)
+		}
+	}
+	header.Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	w.ResponseWriter.WriteHeader(http.StatusInternalServerError)
+	w.ResponseWriter.Write([]byte(`{"error":"Failed to record audit entry"}` + "\n"))
+}
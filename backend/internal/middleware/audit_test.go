@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/audit"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+)
+
+type entryRecorder struct {
+	entries []*audit.Entry
+	err     error
+}
+
+func (r *entryRecorder) Record(ctx context.Context, entry *audit.Entry) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+type contact struct {
+	Name  string `json:"name"`
+	Phone string `json:"phone"`
+}
+
+func newAuditedServer(recorder audit.Recorder, contacts map[string]*contact) *echo.Echo {
+	logger.Init()
+	e := echo.New()
+	snapshots := audit.Snapshots{
+		audit.TargetUser: func(ctx context.Context, id string) (interface{}, error) {
+			if found, ok := contacts[id]; ok {
+				copied := *found
+				return &copied, nil
+			}
+			return nil, errors.New("not found")
+		},
+	}
+
+	e.GET("/contacts/:id", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, contacts[c.Param("id")])
+	}, Audit(recorder, snapshots, audit.ActionUserRead, audit.TargetUser, "id"))
+	e.PUT("/contacts/:id", func(c echo.Context) error {
+		var update contact
+		if err := c.Bind(&update); err != nil {
+			return err
+		}
+		contacts[c.Param("id")] = &update
+		return c.JSON(http.StatusOK, update)
+	}, Audit(recorder, snapshots, audit.ActionUserUpdate, audit.TargetUser, "id"))
+	e.POST("/contacts", func(c echo.Context) error {
+		return c.JSON(http.StatusCreated, map[string]string{"id": "contact-2"})
+	}, Audit(recorder, snapshots, audit.ActionUserCreate, audit.TargetUser, ""))
+	return e
+}
+
+func send(e *echo.Echo, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuditRecordsChangedFields(t *testing.T) {
+	recorder := &entryRecorder{}
+	contacts := map[string]*contact{"contact-1": {Name: "Sam", Phone: "0113"}}
+	e := newAuditedServer(recorder, contacts)
+
+	if rec := send(e, http.MethodPut, "/contacts/contact-1", `{"name":"Sam","phone":"0114"}`); rec.Code != http.StatusOK {
+		t.Fatalf("update = %d, want 200", rec.Code)
+	}
+	if rec := send(e, http.MethodPost, "/contacts", `{"name":"Alex","phone":"0115"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("create = %d, want 201", rec.Code)
+	}
+
+	if len(recorder.entries) != 2 {
+		t.Fatalf("recorded %d entries, want 2", len(recorder.entries))
+	}
+	if update := recorder.entries[0]; !reflect.DeepEqual(update.FieldsChanged, []string{"phone"}) || update.TargetID != "contact-1" {
+		t.Errorf("update entry = %+v, want only phone changed on contact-1", update)
+	}
+	if create := recorder.entries[1]; !reflect.DeepEqual(create.FieldsChanged, []string{"name", "phone"}) || create.TargetID != "contact-2" {
+		t.Errorf("create entry = %+v, want the fields set on contact-2", create)
+	}
+}
+
+func TestAuditFailsClosed(t *testing.T) {
+	recorder := &entryRecorder{err: errors.New("database unavailable")}
+	e := newAuditedServer(recorder, map[string]*contact{"contact-1": {Name: "Sam", Phone: "0113"}})
+
+	for _, rec := range []*httptest.ResponseRecorder{
+		send(e, http.MethodGet, "/contacts/contact-1", ""),
+		send(e, http.MethodPost, "/contacts", `{"name":"Alex"}`),
+	} {
+		if rec.Code != http.StatusInternalServerError || strings.Contains(rec.Body.String(), "Sam") || strings.Contains(rec.Body.String(), "contact-2") {
+			t.Errorf("response without an audit record = %d %s, want 500 with no data", rec.Code, rec.Body)
+		}
+	}
+}
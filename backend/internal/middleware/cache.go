@@ -0,0 +1,194 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/httpcache"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	headerETag        = "ETag"
+	headerIfNoneMatch = "If-None-Match"
+)
+
+// CatalogCache adds ETag and Cache-Control headers to a public catalog route and
+// answers If-None-Match with 304 Not Modified. Weak ETags come from the collection
+// version, plus the row's updated_at on detail routes, and are checked before the
+// handler runs, so a revalidation costs a primary-key lookup or two. Versions are
+// read through the request's pinned reader, so the ETag describes the same
+// database the handler renders from. Strong policies hash the rendered body
+// instead.
+func CatalogCache(versions httpcache.Store, collection string, policy httpcache.Policy) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			method := c.Request().Method
+			if method != http.MethodGet && method != http.MethodHead {
+				return next(c)
+			}
+
+			if policy.Strong {
+				return serveWithStrongETag(c, next, policy)
+			}
+
+			version, err := versions.GetVersion(c.Request().Context(), collection)
+			if err != nil {
+				logger.Error("Failed to get catalog version", zap.String("collection", collection), zap.Error(err))
+				return next(c)
+			}
+
+			var updatedAt *time.Time
+			if policy.Row {
+				updatedAt, err = versions.GetUpdatedAt(c.Request().Context(), collection, c.Param("id"))
+				if err != nil {
+					logger.Error("Failed to get catalog record updated_at", zap.String("collection", collection), zap.Error(err))
+					return next(c)
+				}
+			}
+
+			etag := weakETag(version, updatedAt, c.Request().URL.RequestURI())
+			header := c.Response().Header()
+			header.Set(echo.HeaderCacheControl, policy.CacheControl())
+			header.Set(headerETag, etag)
+
+			if etagMatches(c.Request().Header.Get(headerIfNoneMatch), etag) {
+				return c.NoContent(http.StatusNotModified)
+			}
+
+			c.Response().Writer = &cacheHeaderWriter{ResponseWriter: c.Response().Writer}
+			return next(c)
+		}
+	}
+}
+
+// BumpCollectionVersion invalidates cached catalog responses after a successful
+// admin write to any of the given collections.
+func BumpCollectionVersion(versions httpcache.Store, collections ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := next(c)
+
+			method := c.Request().Method
+			if method == http.MethodGet || method == http.MethodHead {
+				return err
+			}
+
+			status := c.Response().Status
+			if err != nil || status < http.StatusOK || status >= http.StatusMultipleChoices {
+				return err
+			}
+
+			// The write has already been committed, so don't tie the bump to the request context
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			for _, collection := range collections {
+				if _, bumpErr := versions.BumpVersion(ctx, collection); bumpErr != nil {
+					logger.Error("Failed to bump catalog version", zap.String("collection", collection), zap.Error(bumpErr))
+				}
+			}
+
+			return nil
+		}
+	}
+}
+
+// Helper functions
+
+func serveWithStrongETag(c echo.Context, next echo.HandlerFunc, policy httpcache.Policy) error {
+	original := c.Response().Writer
+	buffered := &bufferedWriter{ResponseWriter: original, status: http.StatusOK}
+	c.Response().Writer = buffered
+
+	if err := next(c); err != nil {
+		c.Error(err)
+	}
+
+	c.Response().Writer = original
+	header := original.Header()
+
+	if buffered.status != http.StatusOK {
+		header.Set(echo.HeaderCacheControl, "no-store")
+		original.WriteHeader(buffered.status)
+		_, err := original.Write(buffered.body.Bytes())
+		return err
+	}
+
+	sum := sha256.Sum256(buffered.body.Bytes())
+	etag := fmt.Sprintf("%q", hex.EncodeToString(sum[:16]))
+	header.Set(echo.HeaderCacheControl, policy.CacheControl())
+	header.Set(headerETag, etag)
+
+	if etagMatches(c.Request().Header.Get(headerIfNoneMatch), etag) {
+		header.Del(echo.HeaderContentType)
+		header.Del(echo.HeaderContentLength)
+		original.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	original.WriteHeader(http.StatusOK)
+	_, err := original.Write(buffered.body.Bytes())
+	return err
+}
+
+func weakETag(version *httpcache.CollectionVersion, updatedAt *time.Time, requestURI string) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s|%d|%d|%s", version.Collection, version.Version, version.UpdatedAt.UnixNano(), requestURI)
+	if updatedAt != nil {
+		fmt.Fprintf(hash, "|%d", updatedAt.UnixNano())
+	}
+	return fmt.Sprintf(`W/"%s-%d-%s"`, version.Collection, version.Version, hex.EncodeToString(hash.Sum(nil))[:16])
+}
+
+// etagMatches applies the weak comparison If-None-Match requires
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	target := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == target {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheHeaderWriter strips caching headers from error responses so they are never reused
+type cacheHeaderWriter struct {
+	http.ResponseWriter
+}
+
+func (w *cacheHeaderWriter) WriteHeader(status int) {
+	if status != http.StatusOK {
+		w.Header().Del(headerETag)
+		w.Header().Set(echo.HeaderCacheControl, "no-store")
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// bufferedWriter holds the response until its strong ETag is known
+type bufferedWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/httpcache"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+)
+
+// rowVersions adds fixed record timestamps to the in-memory version store
+type rowVersions struct {
+	httpcache.Store
+	updatedAt map[string]time.Time
+}
+
+func (s *rowVersions) GetUpdatedAt(ctx context.Context, collection, id string) (*time.Time, error) {
+	updatedAt, ok := s.updatedAt[id]
+	if !ok {
+		return nil, nil
+	}
+	return &updatedAt, nil
+}
+
+func newCatalogServer(versions httpcache.Store) *echo.Echo {
+	logger.Init()
+	e := echo.New()
+	list := func(c echo.Context) error {
+		return c.JSON(http.StatusOK, []string{"group"})
+	}
+	e.GET("/groups", list, CatalogCache(versions, httpcache.CollectionSupportGroups, httpcache.PolicyList))
+	e.GET("/groups/:id", list, CatalogCache(versions, httpcache.CollectionSupportGroups, httpcache.PolicyDetail))
+	e.POST("/groups/join", func(c echo.Context) error {
+		return c.NoContent(http.StatusCreated)
+	}, BumpCollectionVersion(versions, httpcache.CollectionSupportGroups))
+	e.POST("/groups/full", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusConflict, "Group is full")
+	}, BumpCollectionVersion(versions, httpcache.CollectionSupportGroups))
+	return e
+}
+
+func serve(e *echo.Echo, method, path, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set(headerIfNoneMatch, ifNoneMatch)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCatalogCacheRevalidation(t *testing.T) {
+	e := newCatalogServer(httpcache.NewMemoryStore())
+
+	first := serve(e, http.MethodGet, "/groups", "")
+	etag := first.Header().Get(headerETag)
+	if first.Code != http.StatusOK || etag == "" || first.Header().Get(echo.HeaderCacheControl) != httpcache.PolicyList.CacheControl() {
+		t.Fatalf("first request = %d with ETag %q, want 200 with caching headers", first.Code, etag)
+	}
+
+	if rec := serve(e, http.MethodGet, "/groups", etag); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("revalidation = %d, want 304 with no body", rec.Code)
+	}
+	if rec := serve(e, http.MethodGet, "/groups?page=2", etag); rec.Code != http.StatusOK {
+		t.Errorf("another page with the same ETag = %d, want 200", rec.Code)
+	}
+
+	// A failed write leaves cached copies valid
+	serve(e, http.MethodPost, "/groups/full", "")
+	if rec := serve(e, http.MethodGet, "/groups", etag); rec.Code != http.StatusNotModified {
+		t.Errorf("revalidation after a failed write = %d, want 304", rec.Code)
+	}
+
+	serve(e, http.MethodPost, "/groups/join", "")
+	rec := serve(e, http.MethodGet, "/groups", etag)
+	if rec.Code != http.StatusOK || rec.Header().Get(headerETag) == etag {
+		t.Errorf("revalidation after a write = %d with ETag %q, want 200 with a new ETag", rec.Code, rec.Header().Get(headerETag))
+	}
+}
+
+func TestCatalogCacheDetailFollowsRow(t *testing.T) {
+	versions := &rowVersions{
+		Store:     httpcache.NewMemoryStore(),
+		updatedAt: map[string]time.Time{"group-1": time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	e := newCatalogServer(versions)
+
+	etag := serve(e, http.MethodGet, "/groups/group-1", "").Header().Get(headerETag)
+	if rec := serve(e, http.MethodGet, "/groups/group-1", etag); rec.Code != http.StatusNotModified {
+		t.Fatalf("revalidation = %d, want 304", rec.Code)
+	}
+
+	versions.updatedAt["group-1"] = versions.updatedAt["group-1"].Add(time.Second)
+	if rec := serve(e, http.MethodGet, "/groups/group-1", etag); rec.Code != http.StatusOK {
+		t.Errorf("revalidation after the row changed = %d, want 200", rec.Code)
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	tests := []struct {
+		ifNoneMatch string
+		want        bool
+	}{
+		{"", false},
+		{`W/"v1-abc"`, true},
+		{`"v1-abc"`, true},
+		{`"other", W/"v1-abc"`, true},
+		{"*", true},
+		{`W/"v2-abc"`, false},
+	}
+
+	for _, tt := range tests {
+		if got := etagMatches(tt.ifNoneMatch, `W/"v1-abc"`); got != tt.want {
+			t.Errorf("etagMatches(%q) = %v, want %v", tt.ifNoneMatch, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/careteam"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"go.uber.org/zap"
+)
+
+// CareTeamAccess limits a route about one user, named by the param path
+// parameter, to that user, their care team and staff holding break-glass access.
+// Staff accounts are not gated. Must run after JWTMiddleware; place Audit before
+// it so denied attempts are recorded.
+func CareTeamAccess(checker careteam.AccessChecker, param string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userID, ok := c.Get("user_id").(string)
+			if !ok || userID == "" {
+				return c.JSON(http.StatusUnauthorized, map[string]string{
+					"error": "User not authenticated",
+				})
+			}
+
+			if _, err := checker.CheckAccess(c.Request().Context(), userID, c.Param(param)); err != nil {
+				if errors.Is(err, careteam.ErrNoAccess) {
+					return c.JSON(http.StatusForbidden, map[string]string{
+						"error": err.Error(),
+					})
+				}
+				logger.Error("Failed to check care-team access", zap.String("user_id", userID), zap.Error(err))
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Failed to check care-team access",
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
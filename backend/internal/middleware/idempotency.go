@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/idempotency"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// HeaderIdempotencyKey is the request header clients use to make a POST safe to retry
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed is set on responses served from the idempotency store
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// IdempotencyMiddleware replays the stored response when a request is retried with
+// the same Idempotency-Key. Keys are scoped per user, so it must run after the JWT
+// middleware. Requests without the header, and anonymous requests, which have no
+// user to scope the key to, pass through untouched.
+func IdempotencyMiddleware(store idempotency.Store, ttl time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(HeaderIdempotencyKey)
+			userID, _ := c.Get("user_id").(string)
+			if key == "" || userID == "" {
+				return next(c)
+			}
+
+			if len(key) > maxIdempotencyKeyLength {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "Idempotency-Key must be at most 255 characters",
+				})
+			}
+
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "Invalid request format",
+				})
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+			fingerprint := requestFingerprint(c.Request().Method, c.Request().URL.Path, body)
+			ctx := c.Request().Context()
+
+			existing, reserved, err := store.Reserve(ctx, userID, key, fingerprint, ttl)
+			if err != nil {
+				logger.Error("Failed to reserve idempotency key", zap.Error(err))
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Failed to process idempotency key",
+				})
+			}
+
+			if !reserved {
+				if existing.Fingerprint != fingerprint {
+					return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+						"error": "Idempotency-Key has already been used with a different request",
+					})
+				}
+
+				if !existing.IsCompleted() {
+					return c.JSON(http.StatusConflict, map[string]string{
+						"error": "A request with this Idempotency-Key is still being processed",
+					})
+				}
+
+				contentType := echo.MIMEApplicationJSONCharsetUTF8
+				if existing.ContentType != nil && *existing.ContentType != "" {
+					contentType = *existing.ContentType
+				}
+
+				c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+				return c.Blob(*existing.StatusCode, contentType, existing.ResponseBody)
+			}
+
+			// Use a fresh context so a cancelled request still settles the key
+			saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+			defer cancel()
+			release := func() {
+				if err := store.Release(saveCtx, userID, key); err != nil {
+					logger.Error("Failed to release idempotency key", zap.Error(err))
+				}
+			}
+
+			// A panicking handler must not leave the key pending until it expires
+			defer func() {
+				if r := recover(); r != nil {
+					release()
+					panic(r)
+				}
+			}()
+
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+
+			handlerErr := next(c)
+			if handlerErr != nil {
+				c.Error(handlerErr)
+			}
+
+			status := c.Response().Status
+			if status >= http.StatusInternalServerError {
+				// Server errors are not cached so the client can retry with the same key
+				release()
+				return nil
+			}
+
+			contentType := c.Response().Header().Get(echo.HeaderContentType)
+			if err := store.Complete(saveCtx, userID, key, status, contentType, recorder.body.Bytes()); err != nil {
+				logger.Error("Failed to store idempotent response", zap.Error(err))
+			}
+
+			return nil
+		}
+	}
+}
+
+// Helper functions
+
+func requestFingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(path))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder copies everything written to the client so it can be replayed
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/perinatal-mental-health-app/backend/internal/idempotency"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+)
+
+// newIdempotentServer serves POST /entries, counting how often the handler
+// runs. The user ID comes from the X-User header in place of a JWT.
+func newIdempotentServer(handler echo.HandlerFunc) *echo.Echo {
+	logger.Init()
+	e := echo.New()
+	authenticate := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if userID := c.Request().Header.Get("X-User"); userID != "" {
+				c.Set("user_id", userID)
+			}
+			return next(c)
+		}
+	}
+	e.POST("/entries", handler, authenticate, IdempotencyMiddleware(idempotency.NewMemoryStore(), time.Hour))
+	return e
+}
+
+func post(e *echo.Echo, userID, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/entries", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if userID != "" {
+		req.Header.Set("X-User", userID)
+	}
+	if key != "" {
+		req.Header.Set(HeaderIdempotencyKey, key)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	calls := 0
+	e := newIdempotentServer(func(c echo.Context) error {
+		calls++
+		return c.JSON(http.StatusCreated, map[string]int{"call": calls})
+	})
+
+	first := post(e, "user-1", "key-1", `{"mood":3}`)
+	retry := post(e, "user-1", "key-1", `{"mood":3}`)
+	if calls != 1 || retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Errorf("retry = %d %s after %d calls, want the first response replayed", retry.Code, retry.Body, calls)
+	}
+	if retry.Header().Get(HeaderIdempotentReplayed) != "true" {
+		t.Error("replayed response is missing the Idempotent-Replayed header")
+	}
+
+	if rec := post(e, "user-1", "key-1", `{"mood":5}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("reusing a key for a different body = %d, want 422", rec.Code)
+	}
+
+	// Keys belong to one user, and anonymous callers have nothing to scope them to
+	post(e, "user-2", "key-1", `{"mood":3}`)
+	post(e, "", "key-1", `{"mood":3}`)
+	post(e, "", "key-1", `{"mood":3}`)
+	if calls != 4 {
+		t.Errorf("handler ran %d times, want once more for another user and for each anonymous request", calls)
+	}
+}
+
+func TestIdempotencyReleasesFailedRequests(t *testing.T) {
+	fail := true
+	e := newIdempotentServer(func(c echo.Context) error {
+		if fail {
+			panic("handler failed")
+		}
+		return c.JSON(http.StatusCreated, map[string]string{"status": "created"})
+	})
+	e.Use(echomiddleware.Recover())
+
+	if rec := post(e, "user-1", "key-1", `{}`); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("panicking handler = %d, want 500", rec.Code)
+	}
+
+	fail = false
+	if rec := post(e, "user-1", "key-1", `{}`); rec.Code != http.StatusCreated || rec.Header().Get(HeaderIdempotentReplayed) != "" {
+		t.Errorf("retry after a panic = %d, want the request run again", rec.Code)
+	}
+}
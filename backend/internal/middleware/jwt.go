@@ -8,7 +8,19 @@ import (
 	"github.com/perinatal-mental-health-app/backend/internal/auth"
 )
 
+// JWTMiddleware requires a full account. Guest tokens are refused, as are
+// refresh tokens, which only the refresh endpoint accepts.
 func JWTMiddleware(jwtService *auth.JWTService) echo.MiddlewareFunc {
+	return authenticate(jwtService, false)
+}
+
+// GuestJWTMiddleware is for the routes open to guests as well as full accounts.
+// It sets "guest" in the context for guest tokens.
+func GuestJWTMiddleware(jwtService *auth.JWTService) echo.MiddlewareFunc {
+	return authenticate(jwtService, true)
+}
+
+func authenticate(jwtService *auth.JWTService, allowGuests bool) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			// Get the Authorization header
@@ -42,10 +54,23 @@ func JWTMiddleware(jwtService *auth.JWTService) echo.MiddlewareFunc {
 				})
 			}
 
+			if err := jwtService.CheckRevoked(c.Request().Context(), claims); err != nil {
+				return c.JSON(http.StatusUnauthorized, map[string]string{
+					"error": "Invalid token",
+				})
+			}
+
+			if claims.Scope == auth.ScopeGuest && !allowGuests {
+				return c.JSON(http.StatusForbidden, map[string]string{
+					"error": "This feature needs a full account",
+				})
+			}
+
 			// Set user information in context
 			c.Set("user_id", claims.UserID)
 			c.Set("user_email", claims.Email)
 			c.Set("user_role", claims.Role)
+			c.Set("guest", claims.Scope == auth.ScopeGuest)
 
 			return next(c)
 		}
@@ -61,10 +86,14 @@ func OptionalJWTMiddleware(jwtService *auth.JWTService) echo.MiddlewareFunc {
 				tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 				if tokenString != "" {
 					claims, err := jwtService.ValidateToken(tokenString)
+					if err == nil {
+						err = jwtService.CheckRevoked(c.Request().Context(), claims)
+					}
 					if err == nil {
 						c.Set("user_id", claims.UserID)
 						c.Set("user_email", claims.Email)
 						c.Set("user_role", claims.Role)
+						c.Set("guest", claims.Scope == auth.ScopeGuest)
 					}
 				}
 			}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"go.uber.org/zap"
+)
+
+// OrganisationScope loads the organisations the authenticated caller belongs to
+// and stores the resulting scope in the context for admin and stats handlers.
+// An organisation_id query parameter narrows the scope to that organisation.
+// Must run after JWTMiddleware.
+func OrganisationScope(resolver organisations.ScopeResolver) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userID, ok := c.Get("user_id").(string)
+			if !ok || userID == "" {
+				return c.JSON(http.StatusUnauthorized, map[string]string{
+					"error": "User not authenticated",
+				})
+			}
+
+			scope, err := resolver.ResolveScope(c.Request().Context(), userID, c.QueryParam("organisation_id"))
+			if err != nil {
+				if errors.Is(err, organisations.ErrOutOfScope) {
+					return c.JSON(http.StatusForbidden, map[string]string{
+						"error": err.Error(),
+					})
+				}
+				logger.Error("Failed to resolve organisation scope", zap.String("user_id", userID), zap.Error(err))
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Failed to resolve organisation scope",
+				})
+			}
+
+			c.Set(organisations.ScopeContextKey, scope)
+			return next(c)
+		}
+	}
+}
+
+// SuperAdminMiddleware restricts access to super admins. Must run after OrganisationScope.
+func SuperAdminMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !organisations.ScopeFromContext(c).IsSuperAdmin {
+				return c.JSON(http.StatusForbidden, map[string]string{
+					"error": "Insufficient permissions",
+				})
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/db"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"go.uber.org/zap"
+)
+
+// ReadYourWrites sends reads to the primary database for window after the same
+// client last wrote, so it sees its own changes while the replica catches up.
+// Writes always run against the primary. Clients are told apart by their
+// Authorization header, so anonymous reads always use the replica. Writes are
+// recorded in the primary so a read served by another instance also sees them;
+// window should be at least the replica's maximum lag.
+//
+// Each request's reads are pinned to one database, so a response and the
+// catalog version in its ETag always come from the same place.
+func ReadYourWrites(handle *db.Handle, window time.Duration) echo.MiddlewareFunc {
+	writes := &recentWrites{
+		window: window,
+		last:   make(map[string]time.Time),
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			ctx := req.Context()
+			client := clientKey(req)
+
+			method := req.Method
+			if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+				if client != "" && wroteRecently(ctx, handle, writes, client) {
+					ctx = db.WithPrimary(ctx)
+				}
+				c.SetRequest(req.WithContext(handle.PinReader(ctx)))
+				return next(c)
+			}
+
+			c.SetRequest(req.WithContext(handle.PinReader(db.WithPrimary(ctx))))
+			err := next(c)
+
+			// Failed writes can still have committed part of their work
+			if client != "" {
+				saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+				defer cancel()
+
+				if writes.record(client) {
+					if pruneErr := handle.PruneWrites(saveCtx, time.Now().Add(-window)); pruneErr != nil {
+						logger.Error("Failed to prune recent writes", zap.Error(pruneErr))
+					}
+				}
+				if recordErr := handle.RecordWrite(saveCtx, client); recordErr != nil {
+					logger.Error("Failed to record write for read-your-writes", zap.Error(recordErr))
+				}
+			}
+
+			return err
+		}
+	}
+}
+
+// Helper functions
+
+// wroteRecently checks this instance's writes first, then the shared record.
+// Reads that would use the primary anyway skip the lookup, and a failed lookup
+// errs towards the primary.
+func wroteRecently(ctx context.Context, handle *db.Handle, writes *recentWrites, client string) bool {
+	if writes.recent(client) {
+		return true
+	}
+	if handle.Reader(ctx) == db.Querier(handle.Primary()) {
+		return false
+	}
+
+	recent, err := handle.WroteSince(ctx, client, time.Now().Add(-writes.window))
+	if err != nil {
+		logger.Error("Failed to check recent writes; reading from the primary", zap.Error(err))
+		return true
+	}
+	return recent
+}
+
+// recentWrites remembers when each client last wrote
+type recentWrites struct {
+	window time.Duration
+
+	mu     sync.Mutex
+	last   map[string]time.Time
+	pruned time.Time
+}
+
+// record notes the client's write and reports whether old entries were pruned,
+// which happens at most once per window
+func (w *recentWrites) record(client string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	w.last[client] = now
+
+	// Drop clients whose window has passed so the map doesn't grow unbounded
+	if now.Sub(w.pruned) <= w.window {
+		return false
+	}
+	for key, at := range w.last {
+		if now.Sub(at) > w.window {
+			delete(w.last, key)
+		}
+	}
+	w.pruned = now
+	return true
+}
+
+func (w *recentWrites) recent(client string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	at, ok := w.last[client]
+	return ok && time.Since(at) <= w.window
+}
+
+// clientKey identifies a client by a hash of its credentials, or "" if it sent none
+func clientKey(req *http.Request) string {
+	authorization := req.Header.Get(echo.HeaderAuthorization)
+	if authorization == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(authorization))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,93 @@
+package onboarding
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// importRow is one row of an import file
+type importRow struct {
+	email        string
+	name         string
+	role         string
+	organisation string
+	err          error
+}
+
+// columnAliases maps accepted header names to the column they fill
+var columnAliases = map[string]string{
+	"email":           "email",
+	"name":            "name",
+	"full_name":       "name",
+	"role":            "role",
+	"organisation":    "organisation",
+	"organisation_id": "organisation",
+}
+
+// decodeRows reads a CSV file with a header row naming the email, name, role
+// and (optionally) organisation columns in any order
+func decodeRows(body io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: missing header row", ErrInvalidFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		column, ok := columnAliases[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidFile, name)
+		}
+		if _, seen := positions[column]; seen {
+			return nil, fmt.Errorf("%w: duplicate column %q", ErrInvalidFile, column)
+		}
+		positions[column] = i
+	}
+	for _, column := range []string{"email", "name", "role"} {
+		if _, ok := positions[column]; !ok {
+			return nil, fmt.Errorf("%w: missing %s column", ErrInvalidFile, column)
+		}
+	}
+
+	var rows []importRow
+	for {
+		cells, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if len(rows) == MaxImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidFile, MaxImportRows)
+		}
+		if err != nil {
+			// Quoting errors only affect the row they occur on
+			if _, ok := err.(*csv.ParseError); !ok {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+			}
+			rows = append(rows, importRow{err: err})
+			continue
+		}
+
+		cell := func(column string) string {
+			if i, ok := positions[column]; ok && i < len(cells) {
+				return strings.TrimSpace(cells[i])
+			}
+			return ""
+		}
+		rows = append(rows, importRow{
+			email:        strings.ToLower(cell("email")),
+			name:         cell("name"),
+			role:         cell("role"),
+			organisation: cell("organisation"),
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package onboarding
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+)
+
+// maxImportBytes caps the size of an uploaded import file
+const maxImportBytes = 2 << 20
+
+type handler struct {
+	service Service
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// ImportUsers creates staff accounts from the CSV file in the request body
+// (admin only). With dry_run=true the report is produced without writing anything.
+func (h *handler) ImportUsers(c echo.Context) error {
+	dryRun := false
+	if d := c.QueryParam("dry_run"); d != "" {
+		parsed, err := strconv.ParseBool(d)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "dry_run must be true or false",
+			})
+		}
+		dryRun = parsed
+	}
+
+	body := http.MaxBytesReader(c.Response(), c.Request().Body, maxImportBytes)
+	report, err := h.service.Import(c.Request().Context(), organisations.ScopeFromContext(c), getUserIDFromContext(c), dryRun, body)
+	if err != nil {
+		return c.JSON(statusForError(err), map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
+// AcceptInvitation sets the password of an imported account
+func (h *handler) AcceptInvitation(c echo.Context) error {
+	var req AcceptInvitationRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	if err := h.service.AcceptInvitation(c.Request().Context(), &req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Invitation accepted, you can now sign in",
+	})
+}
+
+// Helper functions
+
+func getUserIDFromContext(c echo.Context) string {
+	if userID, ok := c.Get("user_id").(string); ok {
+		return userID
+	}
+	return ""
+}
+
+func statusForError(err error) int {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrInvalidFile):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,48 @@
+package onboarding
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+)
+
+// Service defines the interface for bulk onboarding business logic
+type Service interface {
+	// Import creates an account for each new email in a CSV file with email,
+	// name, role and organisation columns, and queues its invitation email.
+	// With dryRun every row is checked and the report says what would happen,
+	// but nothing is written.
+	Import(ctx context.Context, scope organisations.Scope, actorID string, dryRun bool, body io.Reader) (*ImportReport, error)
+	// SendInvitation is the handler for JobSendInvitation
+	SendInvitation(ctx context.Context, job SendInvitationJob) error
+	AcceptInvitation(ctx context.Context, req *AcceptInvitationRequest) error
+}
+
+// Store defines the interface for bulk onboarding data persistence
+type Store interface {
+	// FindAccount returns the account with the email, ignoring case, or nil if there is none
+	FindAccount(ctx context.Context, email string) (*Account, error)
+	// FindOrganisation returns the active organisation with the given ID, ODS
+	// code or name, or ErrOrganisationNotFound
+	FindOrganisation(ctx context.Context, ref string) (*Organisation, error)
+	// CreateAccount creates a user and profile without a password, adds them
+	// to the organisation if one is given, and stores the invitation
+	CreateAccount(ctx context.Context, account *NewAccount, invitation *Invitation) error
+	AddMember(ctx context.Context, organisationID, userID string) error
+	// ReplaceInvitation stores a new invitation, withdrawing any the user hasn't accepted
+	ReplaceInvitation(ctx context.Context, invitation *Invitation) error
+	// GetInvitation returns the invitation with the token hash, or ErrInvalidInvitation
+	GetInvitation(ctx context.Context, tokenHash string) (*Invitation, error)
+	// AcceptInvitation sets the user's password and marks the invitation used,
+	// failing with ErrInvalidInvitation if it already was
+	AcceptInvitation(ctx context.Context, invitation *Invitation, passwordHash string, at time.Time) error
+}
+
+// Handler defines the interface for bulk onboarding HTTP handlers
+type Handler interface {
+	ImportUsers(c echo.Context) error
+	AcceptInvitation(c echo.Context) error
+}
@@ -0,0 +1,187 @@
+package onboarding
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+)
+
+// memoryStore keeps invitations and organisation membership in memory for
+// tests and demo mode, with accounts in the shared memdb
+type memoryStore struct {
+	db *memdb.DB
+
+	mu            sync.RWMutex
+	organisations map[string]Organisation
+	members       map[string]map[string]bool // organisation ID to user IDs
+	invitations   map[string]Invitation
+}
+
+func NewMemoryStore(db *memdb.DB) Store {
+	return &memoryStore{
+		db:            db,
+		organisations: make(map[string]Organisation),
+		members:       make(map[string]map[string]bool),
+		invitations:   make(map[string]Invitation),
+	}
+}
+
+// FindAccount returns the account with the email, ignoring case
+func (s *memoryStore) FindAccount(ctx context.Context, email string) (*Account, error) {
+	for _, user := range s.db.Users() {
+		if !strings.EqualFold(user.Email, email) {
+			continue
+		}
+
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+
+		account := &Account{
+			ID:              user.ID,
+			Email:           user.Email,
+			Role:            user.Role,
+			Status:          user.AccountStatus,
+			OrganisationIDs: []string{},
+		}
+		for organisationID, members := range s.members {
+			if members[user.ID] {
+				account.OrganisationIDs = append(account.OrganisationIDs, organisationID)
+			}
+		}
+		if user.PasswordHash == "" {
+			for _, invitation := range s.invitations {
+				if invitation.UserID == user.ID && invitation.AcceptedAt == nil {
+					account.InvitationPending = true
+					account.InvitationOrganisationID = invitation.OrganisationID
+				}
+			}
+		}
+		return account, nil
+	}
+
+	return nil, nil
+}
+
+// FindOrganisation returns the organisation with the given ID or name
+func (s *memoryStore) FindOrganisation(ctx context.Context, ref string) (*Organisation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if organisation, ok := s.organisations[ref]; ok {
+		return &organisation, nil
+	}
+	for _, organisation := range s.organisations {
+		if strings.EqualFold(organisation.Name, ref) {
+			return &organisation, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrOrganisationNotFound, ref)
+}
+
+// CreateAccount creates a user without a password, their membership and their invitation
+func (s *memoryStore) CreateAccount(ctx context.Context, account *NewAccount, invitation *Invitation) error {
+	err := s.db.InsertUser(memdb.User{
+		ID:        account.ID,
+		Email:     account.Email,
+		FullName:  account.FullName,
+		Role:      account.Role,
+		IsActive:  true,
+		CreatedAt: account.CreatedAt,
+		UpdatedAt: account.CreatedAt,
+	}, memdb.Profile{})
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if account.OrganisationID != nil {
+		s.addMember(*account.OrganisationID, account.ID)
+	}
+	s.invitations[invitation.ID] = *invitation
+
+	return nil
+}
+
+// AddMember adds an existing account to an organisation
+func (s *memoryStore) AddMember(ctx context.Context, organisationID, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.addMember(organisationID, userID)
+	return nil
+}
+
+// ReplaceInvitation stores a new invitation, withdrawing any the user hasn't accepted
+func (s *memoryStore) ReplaceInvitation(ctx context.Context, invitation *Invitation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, existing := range s.invitations {
+		if existing.UserID == invitation.UserID && existing.AcceptedAt == nil {
+			delete(s.invitations, id)
+		}
+	}
+	s.invitations[invitation.ID] = *invitation
+
+	return nil
+}
+
+// GetInvitation returns the invitation with the token hash
+func (s *memoryStore) GetInvitation(ctx context.Context, tokenHash string) (*Invitation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, invitation := range s.invitations {
+		if invitation.TokenHash == tokenHash {
+			return &invitation, nil
+		}
+	}
+
+	return nil, ErrInvalidInvitation
+}
+
+// AcceptInvitation sets the user's password and marks the invitation used
+func (s *memoryStore) AcceptInvitation(ctx context.Context, invitation *Invitation, passwordHash string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.invitations[invitation.ID]
+	if !ok || stored.AcceptedAt != nil {
+		return ErrInvalidInvitation
+	}
+	stored.AcceptedAt = &at
+	s.invitations[invitation.ID] = stored
+
+	s.db.UpdateUser(invitation.UserID, func(user *memdb.User) bool {
+		user.PasswordHash = passwordHash
+		user.UpdatedAt = at
+		return true
+	})
+
+	return nil
+}
+
+// Helper functions
+
+// addOrganisation makes an organisation available to imports
+func (s *memoryStore) addOrganisation(organisation Organisation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.organisations[organisation.ID] = organisation
+}
+
+// addMember must be called with the lock held
+func (s *memoryStore) addMember(organisationID, userID string) {
+	if s.members[organisationID] == nil {
+		s.members[organisationID] = make(map[string]bool)
+	}
+	s.members[organisationID][userID] = true
+}
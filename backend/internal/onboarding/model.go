@@ -0,0 +1,129 @@
+// Package onboarding creates staff accounts in bulk from a CSV file and sends
+// each new account an invitation to choose a password.
+package onboarding
+
+import (
+	"errors"
+	"time"
+)
+
+// Row outcomes in an import report
+const (
+	ActionCreate    = "create"     // New account with an invitation
+	ActionAddMember = "add_member" // Existing account added to the organisation
+	ActionReinvite  = "reinvite"   // Existing account that never accepted its invitation gets a new one
+	ActionSkip      = "skip"       // Existing account with nothing to change
+	ActionError     = "error"
+)
+
+// Roles that can be onboarded in bulk. Service users sign up themselves.
+var Roles = []string{"nhs_staff", "professional", "charity"}
+
+const (
+	// MaxImportRows caps the number of rows in one import file
+	MaxImportRows = 1000
+	// InvitationTTL is how long an invitation can be accepted for
+	InvitationTTL = 7 * 24 * time.Hour
+)
+
+// JobSendInvitation issues an invitation token and emails it to the invited
+// member of staff. It runs on the default queue.
+const JobSendInvitation = "onboarding.send_invitation"
+
+var (
+	// ErrInvalidFile is returned when an import file can't be read as a whole.
+	// Problems with individual rows are reported per row instead.
+	ErrInvalidFile = errors.New("invalid import file")
+
+	// ErrOrganisationNotFound is returned for an organisation that matches no
+	// ID, ODS code or name
+	ErrOrganisationNotFound = errors.New("organisation not found")
+
+	// ErrInvalidInvitation is returned for invitation tokens that are unknown,
+	// expired or already used
+	ErrInvalidInvitation = errors.New("invalid or expired invitation")
+)
+
+// Account is an existing account matched by email
+type Account struct {
+	ID              string
+	Email           string
+	Role            string
+	Status          string
+	OrganisationIDs []string
+	// InvitationPending is set when the account was created by an import and
+	// its invitation has not been accepted
+	InvitationPending bool
+	// InvitationOrganisationID is the organisation the pending invitation was
+	// issued for, if any
+	InvitationOrganisationID *string
+}
+
+// Organisation is an organisation rows can name, by ID, ODS code or name
+type Organisation struct {
+	ID   string
+	Name string
+}
+
+// NewAccount is an account created by an import, without a password
+type NewAccount struct {
+	ID             string
+	Email          string
+	FullName       string
+	Role           string
+	OrganisationID *string
+	CreatedAt      time.Time
+}
+
+// Invitation lets the holder of its token set the password of an imported account
+type Invitation struct {
+	ID             string     `json:"id" db:"id"`
+	UserID         string     `json:"user_id" db:"user_id"`
+	TokenHash      string     `json:"-" db:"token_hash"`
+	OrganisationID *string    `json:"organisation_id,omitempty" db:"organisation_id"`
+	InvitedBy      *string    `json:"invited_by,omitempty" db:"invited_by"`
+	ExpiresAt      time.Time  `json:"expires_at" db:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty" db:"accepted_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+// ImportReport describes what an import did, or would do on a dry run
+type ImportReport struct {
+	DryRun              bool        `json:"dry_run"`
+	Total               int         `json:"total"`
+	Created             int         `json:"created"`
+	AddedToOrganisation int         `json:"added_to_organisation"`
+	Reinvited           int         `json:"reinvited"`
+	Skipped             int         `json:"skipped"`
+	Failed              int         `json:"failed"`
+	Rows                []RowResult `json:"rows"`
+}
+
+// RowResult is the outcome for one row of an import file
+type RowResult struct {
+	// Row is the 1-based position of the row in the file, not counting the CSV header
+	Row    int     `json:"row"`
+	Email  string  `json:"email,omitempty"`
+	Action string  `json:"action"`
+	UserID *string `json:"user_id,omitempty"`
+	// InvitationExpiresAt is set when an invitation is emailed to the account
+	InvitationExpiresAt *time.Time `json:"invitation_expires_at,omitempty"`
+	Detail              *string    `json:"detail,omitempty"`
+	Error               *string    `json:"error,omitempty"`
+}
+
+// SendInvitationJob is the payload for emailing an invitation. The token is
+// generated when the job runs, so it is never stored in the queue.
+type SendInvitationJob struct {
+	UserID         string    `json:"user_id"`
+	Email          string    `json:"email"`
+	OrganisationID *string   `json:"organisation_id,omitempty"`
+	InvitedBy      *string   `json:"invited_by,omitempty"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// AcceptInvitationRequest represents an invited user choosing their password
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8"`
+}
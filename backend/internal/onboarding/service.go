@@ -0,0 +1,349 @@
+package onboarding
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	netmail "net/mail"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/perinatal-mental-health-app/backend/internal/jobs"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"github.com/perinatal-mental-health-app/backend/internal/mail"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type service struct {
+	store  Store
+	queue  jobs.Enqueuer
+	mailer mail.Sender
+}
+
+func NewService(store Store, queue jobs.Enqueuer, sender mail.Sender) Service {
+	return &service{
+		store:  store,
+		queue:  queue,
+		mailer: sender,
+	}
+}
+
+// Import creates an account for each new email in a CSV file and queues its
+// invitation email. Rows are independent: one failing row doesn't stop the rest.
+func (s *service) Import(ctx context.Context, scope organisations.Scope, actorID string, dryRun bool, body io.Reader) (*ImportReport, error) {
+	rows, err := decodeRows(body)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Rows:   make([]RowResult, 0, len(rows)),
+	}
+	firstRow := make(map[string]int, len(rows))
+	organisationsByRef := make(map[string]*Organisation)
+
+	for i, row := range rows {
+		result := RowResult{Row: i + 1, Email: row.email, Action: ActionError}
+
+		err := row.err
+		if err == nil {
+			if first, duplicate := firstRow[row.email]; duplicate {
+				err = fmt.Errorf("email %s already used on row %d", row.email, first)
+			} else {
+				firstRow[row.email] = result.Row
+				err = validateRow(row)
+			}
+		}
+
+		var organisation *Organisation
+		if err == nil {
+			organisation, err = s.resolveOrganisation(ctx, scope, row.organisation, organisationsByRef)
+		}
+		if err == nil {
+			err = s.applyRow(ctx, scope, actorID, dryRun, row, organisation, &result)
+		}
+
+		if err != nil {
+			message := err.Error()
+			result.Error = &message
+			result.Action = ActionError
+		}
+		switch result.Action {
+		case ActionCreate:
+			report.Created++
+		case ActionAddMember:
+			report.AddedToOrganisation++
+		case ActionReinvite:
+			report.Reinvited++
+		case ActionSkip:
+			report.Skipped++
+		default:
+			report.Failed++
+		}
+
+		report.Rows = append(report.Rows, result)
+	}
+
+	return report, nil
+}
+
+// SendInvitation is the job handler that issues an invitation token and emails
+// it to the invited member of staff. It replaces any invitation they haven't
+// accepted, so a retry withdraws the token from the failed attempt.
+func (s *service) SendInvitation(ctx context.Context, job SendInvitationJob) error {
+	now := time.Now()
+	if !job.ExpiresAt.After(now) {
+		logger.Info("Skipped expired invitation", zap.String("user_id", job.UserID))
+		return nil
+	}
+
+	// The invitation may have been accepted, or the account changed, since
+	// the job was queued
+	account, err := s.store.FindAccount(ctx, job.Email)
+	if err != nil {
+		return err
+	}
+	if account == nil || account.ID != job.UserID || !account.InvitationPending {
+		logger.Info("Skipped invitation that is no longer pending", zap.String("user_id", job.UserID))
+		return nil
+	}
+
+	invitation, token, err := newInvitation(job.UserID, job.OrganisationID, job.InvitedBy, now, job.ExpiresAt)
+	if err != nil {
+		return err
+	}
+	if err := s.store.ReplaceInvitation(ctx, invitation); err != nil {
+		return err
+	}
+
+	err = s.mailer.Send(ctx, mail.Message{
+		To:      job.Email,
+		Subject: "You've been invited to the perinatal mental health service",
+		Body: fmt.Sprintf("An account has been set up for you. Choose your password with this code "+
+			"before %s:\n\n%s", job.ExpiresAt.Format(time.RFC1123), token),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to email invitation: %w", err)
+	}
+
+	logger.Info("Sent invitation", zap.String("user_id", job.UserID))
+	return nil
+}
+
+// AcceptInvitation sets the password of an imported account
+func (s *service) AcceptInvitation(ctx context.Context, req *AcceptInvitationRequest) error {
+	if len(req.Password) < 8 {
+		return fmt.Errorf("password must be at least 8 characters")
+	}
+
+	invitation, err := s.store.GetInvitation(ctx, hashToken(strings.TrimSpace(req.Token)))
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if invitation.AcceptedAt != nil || !invitation.ExpiresAt.After(now) {
+		return ErrInvalidInvitation
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password")
+	}
+
+	return s.store.AcceptInvitation(ctx, invitation, string(passwordHash), now)
+}
+
+// Helper functions
+
+// applyRow works out what a valid row needs and, unless this is a dry run, does it
+func (s *service) applyRow(ctx context.Context, scope organisations.Scope, actorID string, dryRun bool, row importRow, organisation *Organisation, result *RowResult) error {
+	var organisationID *string
+	if organisation != nil {
+		organisationID = &organisation.ID
+	}
+
+	account, err := s.store.FindAccount(ctx, row.email)
+	if err != nil {
+		return err
+	}
+
+	if account == nil {
+		result.Action = ActionCreate
+		if dryRun {
+			return nil
+		}
+
+		now := time.Now()
+		newAccount := &NewAccount{
+			ID:             uuid.New().String(),
+			Email:          row.email,
+			FullName:       row.name,
+			Role:           row.role,
+			OrganisationID: organisationID,
+			CreatedAt:      now,
+		}
+		// The account is created with an invitation nobody holds the token
+		// for; the queued email replaces it with one that is sent
+		invitation, _, err := newInvitation(newAccount.ID, organisationID, invitedBy(actorID), now, now.Add(InvitationTTL))
+		if err != nil {
+			return err
+		}
+		if err := s.store.CreateAccount(ctx, newAccount, invitation); err != nil {
+			return err
+		}
+
+		result.UserID = &newAccount.ID
+		return s.queueInvitation(ctx, newAccount.ID, newAccount.Email, organisationID, actorID, result)
+	}
+
+	// Existing accounts keep their role; imports never change what someone can do
+	result.UserID = &account.ID
+	if account.Role == "service_user" {
+		return fmt.Errorf("email belongs to a service user account")
+	}
+	if account.Role != row.role {
+		detail := fmt.Sprintf("existing account keeps its %s role", account.Role)
+		result.Detail = &detail
+	}
+
+	switch {
+	case organisation != nil && !slices.Contains(account.OrganisationIDs, organisation.ID):
+		result.Action = ActionAddMember
+		if dryRun {
+			return nil
+		}
+		return s.store.AddMember(ctx, organisation.ID, account.ID)
+
+	case account.InvitationPending:
+		// Only the organisations an invitation was issued for can send it again
+		if !invitationInScope(scope, account.InvitationOrganisationID) {
+			return fmt.Errorf("%w: pending invitation is for another organisation", organisations.ErrOutOfScope)
+		}
+		result.Action = ActionReinvite
+		if dryRun {
+			return nil
+		}
+		return s.queueInvitation(ctx, account.ID, account.Email, organisationID, actorID, result)
+
+	default:
+		result.Action = ActionSkip
+		if result.Detail == nil {
+			detail := "account already exists"
+			result.Detail = &detail
+		}
+		return nil
+	}
+}
+
+// queueInvitation queues the email that sends an account its invitation
+func (s *service) queueInvitation(ctx context.Context, userID, email string, organisationID *string, actorID string, result *RowResult) error {
+	job := SendInvitationJob{
+		UserID:         userID,
+		Email:          email,
+		OrganisationID: organisationID,
+		InvitedBy:      invitedBy(actorID),
+		ExpiresAt:      time.Now().Add(InvitationTTL),
+	}
+	if _, err := s.queue.Enqueue(ctx, JobSendInvitation, job, nil); err != nil {
+		return fmt.Errorf("failed to queue invitation: %w", err)
+	}
+
+	result.InvitationExpiresAt = &job.ExpiresAt
+	return nil
+}
+
+// resolveOrganisation finds the organisation a row names, defaulting to the
+// caller's only organisation when the cell is blank
+func (s *service) resolveOrganisation(ctx context.Context, scope organisations.Scope, ref string, cache map[string]*Organisation) (*Organisation, error) {
+	if ref == "" {
+		switch {
+		case scope.All:
+			return nil, nil
+		case len(scope.OrganisationIDs) == 1:
+			ref = scope.OrganisationIDs[0]
+		default:
+			return nil, fmt.Errorf("organisation is required")
+		}
+	}
+
+	organisation, ok := cache[ref]
+	if !ok {
+		var err error
+		if organisation, err = s.store.FindOrganisation(ctx, ref); err != nil {
+			return nil, err
+		}
+		cache[ref] = organisation
+	}
+	if !scope.Allows(organisation.ID) {
+		return nil, organisations.ErrOutOfScope
+	}
+
+	return organisation, nil
+}
+
+// invitationInScope reports whether the caller can reissue an invitation.
+// Invitations issued without an organisation belong to super admins.
+func invitationInScope(scope organisations.Scope, organisationID *string) bool {
+	if organisationID == nil {
+		return scope.All
+	}
+	return scope.Allows(*organisationID)
+}
+
+func validateRow(row importRow) error {
+	if address, err := netmail.ParseAddress(row.email); err != nil || address.Address != row.email {
+		return fmt.Errorf("invalid email address")
+	}
+	if len(row.name) < 2 || len(row.name) > 100 {
+		return fmt.Errorf("name must be between 2 and 100 characters")
+	}
+	if !slices.Contains(Roles, row.role) {
+		return fmt.Errorf("invalid role: %s (must be one of %s)", row.role, strings.Join(Roles, ", "))
+	}
+	return nil
+}
+
+// newInvitation returns an invitation and the token that accepts it. Only a
+// hash of the token is stored.
+func newInvitation(userID string, organisationID, invitedBy *string, now, expiresAt time.Time) (*Invitation, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	invitation := &Invitation{
+		ID:             uuid.New().String(),
+		UserID:         userID,
+		TokenHash:      hashToken(token),
+		OrganisationID: organisationID,
+		InvitedBy:      invitedBy,
+		ExpiresAt:      expiresAt,
+		CreatedAt:      now,
+	}
+
+	return invitation, token, nil
+}
+
+// invitedBy is the actor recorded on an invitation; the admin CLI may have none
+func invitedBy(actorID string) *string {
+	if actorID == "" {
+		return nil
+	}
+	return &actorID
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,226 @@
+package onboarding
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/jobs"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"github.com/perinatal-mental-health-app/backend/internal/mail"
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	trust     = "aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa"
+	charity   = "bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb"
+	admin     = "11111111-1111-1111-1111-111111111111"
+	midwife   = "22222222-2222-2222-2222-222222222222"
+	serviceID = "33333333-3333-3333-3333-333333333333"
+)
+
+// queuedInvitations keeps the invitation emails a test queues
+type queuedInvitations []SendInvitationJob
+
+func (q *queuedInvitations) Enqueue(ctx context.Context, jobType string, payload interface{}, opts *jobs.EnqueueOptions) (*jobs.Job, error) {
+	*q = append(*q, payload.(SendInvitationJob))
+	return &jobs.Job{JobType: jobType}, nil
+}
+
+// recordingSender keeps the emails a test sends
+type recordingSender struct {
+	sent []mail.Message
+}
+
+func (r *recordingSender) Send(ctx context.Context, message mail.Message) error {
+	r.sent = append(r.sent, message)
+	return nil
+}
+
+// lastToken returns the invitation token at the end of the last email sent
+func (r *recordingSender) lastToken() string {
+	lines := strings.Split(r.sent[len(r.sent)-1].Body, "\n")
+	return lines[len(lines)-1]
+}
+
+func newTestService(t *testing.T) (Service, *memdb.DB, *queuedInvitations, *recordingSender) {
+	t.Helper()
+
+	logger.Init()
+	db := memdb.New()
+	now := time.Now()
+	for _, u := range []memdb.User{
+		{ID: admin, FullName: "Ada Admin", Email: "admin@example.nhs.uk", Role: "nhs_staff", PasswordHash: "x", IsActive: true},
+		{ID: midwife, FullName: "Mo Midwife", Email: "Mo.Midwife@example.nhs.uk", Role: "professional", PasswordHash: "x", IsActive: true},
+		{ID: serviceID, FullName: "Sam Parent", Email: "sam@example.com", Role: "service_user", PasswordHash: "x", IsActive: true},
+	} {
+		u.CreatedAt, u.UpdatedAt = now, now
+		if err := db.InsertUser(u, memdb.Profile{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store := NewMemoryStore(db).(*memoryStore)
+	store.addOrganisation(Organisation{ID: trust, Name: "North Trust"})
+	store.addOrganisation(Organisation{ID: charity, Name: "Bumps Together"})
+
+	queue, sender := &queuedInvitations{}, &recordingSender{}
+	return NewService(store, queue, sender), db, queue, sender
+}
+
+const importFile = "\ufeffEmail,Name,Role,Organisation\n" +
+	"new.starter@example.nhs.uk,New Starter,nhs_staff,North Trust\n" +
+	"mo.midwife@example.nhs.uk,Mo Midwife,nhs_staff,North Trust\n" +
+	"admin@example.nhs.uk,Ada Admin,nhs_staff,\n" +
+	"NEW.STARTER@example.nhs.uk,New Starter,nhs_staff,North Trust\n" +
+	"sam@example.com,Sam Parent,professional,\n" +
+	"peer@example.org,Pat Peer,volunteer,Bumps Together\n" +
+	"lost@example.org,Lee Lost,charity,Nowhere\n"
+
+func TestImport(t *testing.T) {
+	ctx := context.Background()
+	svc, db, queue, sender := newTestService(t)
+
+	wantActions := []string{ActionCreate, ActionAddMember, ActionSkip, ActionError, ActionError, ActionError, ActionError}
+	check := func(report *ImportReport) {
+		t.Helper()
+		if len(report.Rows) != len(wantActions) {
+			t.Fatalf("Import() returned %d rows, want %d", len(report.Rows), len(wantActions))
+		}
+		for i, row := range report.Rows {
+			if row.Action != wantActions[i] {
+				t.Errorf("row %d action = %s (error %v), want %s", row.Row, row.Action, row.Error, wantActions[i])
+			}
+		}
+		if report.Created != 1 || report.AddedToOrganisation != 1 || report.Skipped != 1 || report.Failed != 4 {
+			t.Errorf("Import() totals = %+v", report)
+		}
+	}
+
+	dryRun, err := svc.Import(ctx, organisations.Scope{All: true}, admin, true, strings.NewReader(importFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(dryRun)
+	if _, ok := db.UserByEmail("new.starter@example.nhs.uk"); ok || len(*queue) != 0 {
+		t.Fatal("dry run created an account")
+	}
+
+	report, err := svc.Import(ctx, organisations.Scope{All: true}, admin, false, strings.NewReader(importFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(report)
+	if report.Rows[0].InvitationExpiresAt == nil || report.Rows[0].UserID == nil || len(*queue) != 1 {
+		t.Fatalf("created row = %+v with %d invitations queued, want a user ID and one invitation", report.Rows[0], len(*queue))
+	}
+
+	// The token is only issued by the queued job, which emails it
+	if err := svc.SendInvitation(ctx, (*queue)[0]); err != nil {
+		t.Fatalf("SendInvitation() error = %v", err)
+	}
+	if len(sender.sent) != 1 || sender.sent[0].To != "new.starter@example.nhs.uk" {
+		t.Fatalf("emails sent = %+v, want the invitation", sender.sent)
+	}
+	first := sender.lastToken()
+	if detail := report.Rows[1].Detail; detail == nil || !strings.Contains(*detail, "professional") {
+		t.Errorf("add_member row detail = %v, want the existing role kept", detail)
+	}
+
+	// Importing the same file again reinvites the account that hasn't signed in
+	again, err := svc.Import(ctx, organisations.Scope{All: true}, admin, false, strings.NewReader(importFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Rows[0].Action != ActionReinvite || again.Rows[1].Action != ActionSkip || len(*queue) != 2 {
+		t.Errorf("second import actions = %s, %s, want reinvite, skip", again.Rows[0].Action, again.Rows[1].Action)
+	}
+	if err := svc.SendInvitation(ctx, (*queue)[1]); err != nil {
+		t.Fatalf("SendInvitation() error = %v", err)
+	}
+
+	// The first invitation was withdrawn by the second
+	stale := &AcceptInvitationRequest{Token: first, Password: "correct horse"}
+	if err := svc.AcceptInvitation(ctx, stale); !errors.Is(err, ErrInvalidInvitation) {
+		t.Errorf("AcceptInvitation() with a withdrawn token error = %v, want ErrInvalidInvitation", err)
+	}
+	accept := &AcceptInvitationRequest{Token: sender.lastToken(), Password: "correct horse"}
+	if err := svc.AcceptInvitation(ctx, accept); err != nil {
+		t.Fatal(err)
+	}
+
+	// A retried job for an accepted invitation sends nothing
+	if err := svc.SendInvitation(ctx, (*queue)[1]); err != nil || len(sender.sent) != 2 {
+		t.Errorf("SendInvitation() after accepting = %v with %d emails sent, want nothing sent", err, len(sender.sent))
+	}
+	created, _ := db.UserByEmail("new.starter@example.nhs.uk")
+	if bcrypt.CompareHashAndPassword([]byte(created.PasswordHash), []byte("correct horse")) != nil {
+		t.Error("AcceptInvitation() did not set the password")
+	}
+	if err := svc.AcceptInvitation(ctx, accept); !errors.Is(err, ErrInvalidInvitation) {
+		t.Errorf("AcceptInvitation() twice error = %v, want ErrInvalidInvitation", err)
+	}
+}
+
+func TestImportScope(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, _ := newTestService(t)
+	scope := organisations.Scope{OrganisationIDs: []string{trust}}
+
+	file := "email,name,role,organisation_id\n" +
+		"one@example.nhs.uk,Nurse One,nhs_staff,\n" +
+		"two@example.org,Peer Two,charity," + charity + "\n"
+	report, err := svc.Import(ctx, scope, admin, true, strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Rows[0].Action != ActionCreate {
+		t.Errorf("blank organisation row = %+v, want the caller's only organisation used", report.Rows[0])
+	}
+	if report.Rows[1].Error == nil || !strings.Contains(*report.Rows[1].Error, organisations.ErrOutOfScope.Error()) {
+		t.Errorf("other organisation row = %+v, want out of scope", report.Rows[1])
+	}
+
+	if _, err := svc.Import(ctx, scope, admin, true, strings.NewReader("email,role\nx@example.org,charity\n")); !errors.Is(err, ErrInvalidFile) {
+		t.Errorf("Import() without a name column error = %v, want ErrInvalidFile", err)
+	}
+}
+
+func TestReinviteOnlyWithinTheInvitingOrganisation(t *testing.T) {
+	ctx := context.Background()
+	svc, _, queue, _ := newTestService(t)
+
+	// Invited by the charity, then added to the trust as well
+	row := "email,name,role,organisation\npeer@example.org,Pat Peer,charity,"
+	imports := []struct {
+		scope  organisations.Scope
+		org    string
+		action string
+	}{
+		{organisations.Scope{OrganisationIDs: []string{charity}}, charity, ActionCreate},
+		{organisations.Scope{All: true}, trust, ActionAddMember},
+		{organisations.Scope{OrganisationIDs: []string{trust}}, trust, ActionError},
+		{organisations.Scope{OrganisationIDs: []string{charity}}, charity, ActionReinvite},
+	}
+	for i, tt := range imports {
+		report, err := svc.Import(ctx, tt.scope, admin, false, strings.NewReader(row+tt.org+"\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := report.Rows[0]
+		if got.Action != tt.action {
+			t.Errorf("import %d action = %s (error %v), want %s", i+1, got.Action, got.Error, tt.action)
+		}
+		if tt.action == ActionError && (got.Error == nil || !strings.Contains(*got.Error, organisations.ErrOutOfScope.Error())) {
+			t.Errorf("import %d error = %v, want out of scope", i+1, got.Error)
+		}
+	}
+
+	if len(*queue) != 2 {
+		t.Errorf("%d invitations queued, want the first and the charity's reinvite", len(*queue))
+	}
+}
@@ -0,0 +1,250 @@
+package onboarding
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/perinatal-mental-health-app/backend/internal/events"
+)
+
+type store struct {
+	db *pgxpool.Pool
+}
+
+func NewStore(db *pgxpool.Pool) Store {
+	return &store{
+		db: db,
+	}
+}
+
+// FindAccount returns the account with the email, ignoring case
+func (s *store) FindAccount(ctx context.Context, email string) (*Account, error) {
+	query := `
+		SELECT u.id, u.email, u.role, u.account_status,
+		       COALESCE(array_agg(m.organisation_id::text) FILTER (WHERE m.organisation_id IS NOT NULL), '{}'),
+		       COALESCE(u.password_hash, '') = '' AND EXISTS (
+		           SELECT 1 FROM user_invitations i WHERE i.user_id = u.id AND i.accepted_at IS NULL
+		       ),
+		       (SELECT i.organisation_id::text FROM user_invitations i
+		        WHERE i.user_id = u.id AND i.accepted_at IS NULL
+		        ORDER BY i.created_at DESC
+		        LIMIT 1)
+		FROM users u
+		LEFT JOIN organisation_members m ON m.user_id = u.id
+		WHERE lower(u.email) = lower($1)
+		GROUP BY u.id
+		LIMIT 1
+	`
+
+	var account Account
+	err := s.db.QueryRow(ctx, query, email).Scan(
+		&account.ID,
+		&account.Email,
+		&account.Role,
+		&account.Status,
+		&account.OrganisationIDs,
+		&account.InvitationPending,
+		&account.InvitationOrganisationID,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find account: %w", err)
+	}
+
+	return &account, nil
+}
+
+// FindOrganisation returns the active organisation with the given ID, ODS code
+// or name, preferring them in that order
+func (s *store) FindOrganisation(ctx context.Context, ref string) (*Organisation, error) {
+	query := `
+		SELECT id, name
+		FROM organisations
+		WHERE is_active = true
+		  AND (id::text = $1 OR lower(ods_code) = lower($1) OR lower(name) = lower($1))
+		ORDER BY id::text = $1 DESC, lower(ods_code) = lower($1) DESC NULLS LAST
+		LIMIT 1
+	`
+
+	var organisation Organisation
+	if err := s.db.QueryRow(ctx, query, ref).Scan(&organisation.ID, &organisation.Name); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("%w: %s", ErrOrganisationNotFound, ref)
+		}
+		return nil, fmt.Errorf("failed to find organisation: %w", err)
+	}
+
+	return &organisation, nil
+}
+
+// CreateAccount creates a user and profile without a password, their
+// organisation membership and their invitation in one transaction
+func (s *store) CreateAccount(ctx context.Context, account *NewAccount, invitation *Invitation) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// An empty hash never matches a password, so the account can't be used
+	// until the invitation is accepted
+	_, err = tx.Exec(ctx, `
+		INSERT INTO users (id, email, full_name, role, password_hash, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, '', true, $5, $5)
+	`, account.ID, account.Email, account.FullName, account.Role, account.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO user_profiles (user_id, created_at, updated_at)
+		VALUES ($1, $2, $2)
+	`, account.ID, account.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create user profile: %w", err)
+	}
+
+	if account.OrganisationID != nil {
+		if err := addMember(ctx, tx, *account.OrganisationID, account.ID, account.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	if err := insertInvitation(ctx, tx, invitation); err != nil {
+		return err
+	}
+
+	err = events.Publish(ctx, tx, events.UserRegistered, account.ID, events.UserRegisteredPayload{
+		UserID: account.ID,
+		Role:   account.Role,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// AddMember adds an existing account to an organisation as a member
+func (s *store) AddMember(ctx context.Context, organisationID, userID string) error {
+	return addMember(ctx, s.db, organisationID, userID, time.Now())
+}
+
+// ReplaceInvitation stores a new invitation, withdrawing any the user hasn't accepted
+func (s *store) ReplaceInvitation(ctx context.Context, invitation *Invitation) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM user_invitations WHERE user_id = $1 AND accepted_at IS NULL`, invitation.UserID); err != nil {
+		return fmt.Errorf("failed to withdraw invitations: %w", err)
+	}
+	if err := insertInvitation(ctx, tx, invitation); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetInvitation returns the invitation with the token hash
+func (s *store) GetInvitation(ctx context.Context, tokenHash string) (*Invitation, error) {
+	query := `
+		SELECT id, user_id, token_hash, organisation_id, invited_by, expires_at, accepted_at, created_at
+		FROM user_invitations
+		WHERE token_hash = $1
+	`
+
+	var invitation Invitation
+	err := s.db.QueryRow(ctx, query, tokenHash).Scan(
+		&invitation.ID,
+		&invitation.UserID,
+		&invitation.TokenHash,
+		&invitation.OrganisationID,
+		&invitation.InvitedBy,
+		&invitation.ExpiresAt,
+		&invitation.AcceptedAt,
+		&invitation.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrInvalidInvitation
+		}
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+
+	return &invitation, nil
+}
+
+// AcceptInvitation sets the user's password and marks the invitation used
+func (s *store) AcceptInvitation(ctx context.Context, invitation *Invitation, passwordHash string, at time.Time) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `
+		UPDATE user_invitations SET accepted_at = $2 WHERE id = $1 AND accepted_at IS NULL
+	`, invitation.ID, at)
+	if err != nil {
+		return fmt.Errorf("failed to accept invitation: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrInvalidInvitation
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE users SET password_hash = $1, updated_at = $2 WHERE id = $3
+	`, passwordHash, at, invitation.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to set password: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Helper functions
+
+func addMember(ctx context.Context, db events.Execer, organisationID, userID string, at time.Time) error {
+	_, err := db.Exec(ctx, `
+		INSERT INTO organisation_members (id, organisation_id, user_id, role, created_at, updated_at)
+		VALUES ($1, $2, $3, 'member', $4, $4)
+		ON CONFLICT (organisation_id, user_id) DO NOTHING
+	`, uuid.New(), organisationID, userID, at)
+	if err != nil {
+		return fmt.Errorf("failed to add organisation member: %w", err)
+	}
+	return nil
+}
+
+func insertInvitation(ctx context.Context, tx pgx.Tx, invitation *Invitation) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO user_invitations (id, user_id, token_hash, organisation_id, invited_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, invitation.ID, invitation.UserID, invitation.TokenHash, invitation.OrganisationID,
+		invitation.InvitedBy, invitation.ExpiresAt, invitation.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create invitation: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is a JSON Schema (draft 2020-12) object as used by OpenAPI 3.1
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 interface{}        `json:"type,omitempty"` // string or []string for nullable types
+	Format               string             `json:"format,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	rawJSONType   = reflect.TypeOf(json.RawMessage{})
+	byteSliceType = reflect.TypeOf([]byte{})
+)
+
+// reflector converts Go types to schemas, registering named structs as components
+type reflector struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newReflector() *reflector {
+	return &reflector{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// SchemaName returns the component name used for a named struct type, e.g.
+// "services.ServicesModel". Names are qualified by package because several
+// modules declare types with the same name.
+func SchemaName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return path.Base(t.PkgPath()) + "." + t.Name()
+}
+
+func (r *reflector) schemaFor(v interface{}) *Schema {
+	return r.schemaOf(reflect.TypeOf(v))
+}
+
+func (r *reflector) schemaOf(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawJSONType:
+		return &Schema{}
+	case t == byteSliceType:
+		return &Schema{Type: "string", Format: "byte"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return nullable(r.schemaOf(t.Elem()))
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: r.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaOf(t.Elem())}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		return r.ref(t)
+	}
+
+	return &Schema{}
+}
+
+// ref registers a named struct as a component and returns a reference to it
+func (r *reflector) ref(t reflect.Type) *Schema {
+	name, ok := r.names[t]
+	if !ok {
+		name = SchemaName(t)
+		r.names[t] = name
+		// Reserve the name before recursing so self-referencing types terminate
+		r.schemas[name] = &Schema{}
+		*r.schemas[name] = *r.structSchema(t)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func (r *reflector) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	r.addFields(schema, t)
+	return schema
+}
+
+func (r *reflector) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+
+		// Embedded structs without a json name are flattened, as encoding/json does
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				r.addFields(schema, embedded)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := r.schemaOf(field.Type)
+		if applyValidation(property, field.Tag.Get("validate")) {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = property
+	}
+}
+
+// applyValidation maps go-playground/validator rules onto the schema and
+// reports whether the field is required
+func applyValidation(schema *Schema, tag string) bool {
+	if tag == "" || schema.Ref != "" {
+		return strings.Contains(tag, "required")
+	}
+
+	target := schema
+	if items := schema.Items; items != nil && strings.Contains(tag, "dive") {
+		target = items
+	}
+
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "required":
+			required = true
+		case "dive":
+			continue
+		case "email":
+			target.Format = "email"
+		case "url":
+			target.Format = "uri"
+		case "uuid", "uuid4":
+			target.Format = "uuid"
+		case "oneof":
+			for _, option := range strings.Fields(value) {
+				target.Enum = append(target.Enum, option)
+			}
+		case "min", "max", "gte", "lte":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			applyBound(schema, key == "min" || key == "gte", n)
+		}
+	}
+	return required
+}
+
+func applyBound(schema *Schema, lower bool, n float64) {
+	length := int(n)
+	switch primaryType(schema) {
+	case "string":
+		if lower {
+			schema.MinLength = &length
+		} else {
+			schema.MaxLength = &length
+		}
+	case "array":
+		if lower {
+			schema.MinItems = &length
+		} else {
+			schema.MaxItems = &length
+		}
+	case "integer", "number":
+		if lower {
+			schema.Minimum = &n
+		} else {
+			schema.Maximum = &n
+		}
+	}
+}
+
+// nullable allows null in addition to the schema's own type
+func nullable(schema *Schema) *Schema {
+	switch typ := schema.Type.(type) {
+	case string:
+		schema.Type = []string{typ, "null"}
+		return schema
+	case nil:
+		if schema.Ref == "" {
+			return schema
+		}
+	}
+	return &Schema{OneOf: []*Schema{schema, {Type: "null"}}}
+}
+
+func primaryType(schema *Schema) string {
+	switch typ := schema.Type.(type) {
+	case string:
+		return typ
+	case []string:
+		return typ[0]
+	}
+	return ""
+}
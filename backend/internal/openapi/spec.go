@@ -0,0 +1,264 @@
+package openapi
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Version is the OpenAPI version emitted by Build
+const Version = "3.1.0"
+
+// Document is the subset of an OpenAPI 3.1 document the API describes
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Server is a base URL the paths are relative to
+type Server struct {
+	URL string `json:"url"`
+}
+
+// PathItem holds the operations registered on a path, keyed by lower-case method
+type PathItem map[string]*OperationObject
+
+// OperationObject is a single documented endpoint
+type OperationObject struct {
+	OperationID string                    `json:"operationId"`
+	Summary     string                    `json:"summary,omitempty"`
+	Tags        []string                  `json:"tags,omitempty"`
+	Parameters  []Parameter               `json:"parameters,omitempty"`
+	RequestBody *RequestBody              `json:"requestBody,omitempty"`
+	Responses   map[string]ResponseObject `json:"responses"`
+	Security    []map[string][]string     `json:"security,omitempty"`
+	Roles       []string                  `json:"x-roles,omitempty"`
+}
+
+// Parameter is a path or query parameter
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody describes a JSON request payload
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// ResponseObject describes a response for one status code
+type ResponseObject struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType wraps the schema of a body
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the reusable schemas and security schemes
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme describes how authenticated operations are authorised
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Operation declares how a registered route is documented. Request and Response
+// are zero values of the Go types the handler binds and returns.
+type Operation struct {
+	Method   string
+	Path     string // Echo path relative to the server URL, e.g. /services/:id
+	Summary  string
+	Tag      string
+	Auth     bool
+	Roles    []string
+	Query    []Parameter
+	Request  interface{}
+	Response interface{}
+	Status   int // Success status, defaults to 200
+}
+
+// Query parameter constructors
+func QueryString(name string) Parameter {
+	return Parameter{Name: name, In: "query", Schema: &Schema{Type: "string"}}
+}
+
+func QueryInt(name string) Parameter {
+	return Parameter{Name: name, In: "query", Schema: &Schema{Type: "integer"}}
+}
+
+func QueryBool(name string) Parameter {
+	return Parameter{Name: name, In: "query", Schema: &Schema{Type: "boolean"}}
+}
+
+// Common query parameter sets
+var (
+	PageParams   = []Parameter{QueryInt("page"), QueryInt("page_size")}
+	CursorParams = []Parameter{QueryString("cursor"), QueryBool("include_total")}
+)
+
+// MessageResponse is the body of handlers that reply with a confirmation message
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+// ErrorResponse is the body returned for every error status
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+const bearerScheme = "bearerAuth"
+
+var pathParamPattern = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+// Build renders the operations into an OpenAPI document. Extra models are
+// added to the component schemas even if no operation references them.
+func Build(info Info, serverURL string, operations []Operation, models ...interface{}) *Document {
+	reflector := newReflector()
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Servers: []Server{{URL: serverURL}},
+		Paths:   make(map[string]*PathItem),
+		Components: Components{
+			SecuritySchemes: map[string]SecurityScheme{
+				bearerScheme: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	errorSchema := reflector.schemaFor(ErrorResponse{})
+
+	for _, op := range operations {
+		path := PathFromEcho(op.Path)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+
+		status := op.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		operation := &OperationObject{
+			OperationID: operationID(op.Method, path),
+			Summary:     op.Summary,
+			Responses: map[string]ResponseObject{
+				"default": {
+					Description: "Error",
+					Content:     jsonContent(errorSchema),
+				},
+			},
+			Roles: op.Roles,
+		}
+		if op.Tag != "" {
+			operation.Tags = []string{op.Tag}
+		}
+
+		for _, match := range pathParamPattern.FindAllStringSubmatch(op.Path, -1) {
+			operation.Parameters = append(operation.Parameters, Parameter{
+				Name:     match[1],
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			})
+		}
+		operation.Parameters = append(operation.Parameters, op.Query...)
+
+		if op.Request != nil {
+			operation.RequestBody = &RequestBody{
+				Required: true,
+				Content:  jsonContent(reflector.schemaFor(op.Request)),
+			}
+		}
+
+		success := ResponseObject{Description: http.StatusText(status)}
+		if op.Response != nil {
+			success.Content = jsonContent(reflector.schemaFor(op.Response))
+		}
+		operation.Responses[strconv.Itoa(status)] = success
+
+		if op.Auth {
+			operation.Security = []map[string][]string{{bearerScheme: {}}}
+		}
+
+		(*item)[strings.ToLower(op.Method)] = operation
+	}
+
+	for _, model := range models {
+		reflector.schemaFor(model)
+	}
+	doc.Components.Schemas = reflector.schemas
+
+	return doc
+}
+
+// PathFromEcho converts an Echo route path to OpenAPI templating
+func PathFromEcho(path string) string {
+	return pathParamPattern.ReplaceAllString(path, "{$1}")
+}
+
+// Operations returns the "METHOD path" keys of every documented operation, sorted
+func (d *Document) Operations() []string {
+	var keys []string
+	for path, item := range d.Paths {
+		for method := range *item {
+			keys = append(keys, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Helper functions
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{
+		"application/json": {Schema: schema},
+	}
+}
+
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '-' || r == '_' || r == '{' || r == '}'
+	}) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// Handler serves the document as JSON
+func Handler(doc *Document) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, doc)
+	}
+}
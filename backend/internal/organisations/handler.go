@@ -0,0 +1,162 @@
+package organisations
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	service Service
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// ListOrganisations retrieves the organisations the caller belongs to, or all of them for super admins
+func (h *handler) ListOrganisations(c echo.Context) error {
+	organisations, err := h.service.ListOrganisations(c.Request().Context(), ScopeFromContext(c))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, organisations)
+}
+
+// GetOrganisation retrieves a single organisation
+func (h *handler) GetOrganisation(c echo.Context) error {
+	organisation, err := h.service.GetOrganisation(c.Request().Context(), ScopeFromContext(c), c.Param("id"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, organisation)
+}
+
+// GetMyScope reports which organisations the caller's admin views are scoped to
+func (h *handler) GetMyScope(c echo.Context) error {
+	return c.JSON(http.StatusOK, ScopeFromContext(c))
+}
+
+// CreateOrganisation creates a new organisation (super admin only)
+func (h *handler) CreateOrganisation(c echo.Context) error {
+	var req CreateOrganisationRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	organisation, err := h.service.CreateOrganisation(c.Request().Context(), &req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusCreated, organisation)
+}
+
+// UpdateOrganisation updates an organisation (super admin only)
+func (h *handler) UpdateOrganisation(c echo.Context) error {
+	var req UpdateOrganisationRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	organisation, err := h.service.UpdateOrganisation(c.Request().Context(), c.Param("id"), &req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, organisation)
+}
+
+// ListMembers retrieves the staff members of an organisation
+func (h *handler) ListMembers(c echo.Context) error {
+	members, err := h.service.ListMembers(c.Request().Context(), ScopeFromContext(c), c.Param("id"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, members)
+}
+
+// AddMember adds a staff member to an organisation (super admin or organisation admin)
+func (h *handler) AddMember(c echo.Context) error {
+	var req AddMemberRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	member, err := h.service.AddMember(c.Request().Context(), ScopeFromContext(c), getUserIDFromContext(c), c.Param("id"), &req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, member)
+}
+
+// RemoveMember removes a staff member from an organisation (super admin or organisation admin)
+func (h *handler) RemoveMember(c echo.Context) error {
+	err := h.service.RemoveMember(c.Request().Context(), ScopeFromContext(c), getUserIDFromContext(c), c.Param("id"), c.Param("user_id"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Member removed from organisation successfully",
+	})
+}
+
+// Helper functions
+
+func getUserIDFromContext(c echo.Context) string {
+	if userID, ok := c.Get("user_id").(string); ok {
+		return userID
+	}
+	return ""
+}
+
+func errorResponse(c echo.Context, err error) error {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, ErrOutOfScope):
+		status = http.StatusForbidden
+	case strings.Contains(err.Error(), "not found"):
+		status = http.StatusNotFound
+	}
+
+	return c.JSON(status, map[string]string{
+		"error": err.Error(),
+	})
+}
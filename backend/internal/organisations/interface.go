@@ -0,0 +1,55 @@
+package organisations
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ScopeResolver works out which organisations a caller may see
+type ScopeResolver interface {
+	// ResolveScope returns the caller's scope, narrowed to organisationID when
+	// one is given. It fails with ErrOutOfScope if the caller may not see it.
+	ResolveScope(ctx context.Context, userID, organisationID string) (*Scope, error)
+}
+
+// Service defines the interface for organisation business logic
+type Service interface {
+	ScopeResolver
+	ListOrganisations(ctx context.Context, scope Scope) (*ListOrganisationsResponse, error)
+	GetOrganisation(ctx context.Context, scope Scope, organisationID string) (*Organisation, error)
+	CreateOrganisation(ctx context.Context, req *CreateOrganisationRequest) (*Organisation, error)
+	UpdateOrganisation(ctx context.Context, organisationID string, req *UpdateOrganisationRequest) (*Organisation, error)
+	ListMembers(ctx context.Context, scope Scope, organisationID string) (*ListMembersResponse, error)
+	AddMember(ctx context.Context, scope Scope, callerID, organisationID string, req *AddMemberRequest) (*Member, error)
+	RemoveMember(ctx context.Context, scope Scope, callerID, organisationID, userID string) error
+}
+
+// Store defines the interface for organisation data persistence
+type Store interface {
+	GetScope(ctx context.Context, userID string) (*Scope, error)
+	ListOrganisations(ctx context.Context, scope Scope) ([]Organisation, error)
+	GetOrganisation(ctx context.Context, organisationID string) (*Organisation, error)
+	CreateOrganisation(ctx context.Context, req *CreateOrganisationRequest) (*Organisation, error)
+	UpdateOrganisation(ctx context.Context, organisationID string, req *UpdateOrganisationRequest) (*Organisation, error)
+	ListMembers(ctx context.Context, organisationID string) ([]Member, error)
+	GetMemberRole(ctx context.Context, organisationID, userID string) (string, error)
+	AddMember(ctx context.Context, organisationID string, req *AddMemberRequest) (*Member, error)
+	RemoveMember(ctx context.Context, organisationID, userID string) error
+}
+
+// Handler defines the interface for organisation HTTP handlers
+type Handler interface {
+	ListOrganisations(c echo.Context) error
+	GetOrganisation(c echo.Context) error
+	GetMyScope(c echo.Context) error
+
+	// Super admin only methods
+	CreateOrganisation(c echo.Context) error
+	UpdateOrganisation(c echo.Context) error
+
+	// Organisation admin methods
+	ListMembers(c echo.Context) error
+	AddMember(c echo.Context) error
+	RemoveMember(c echo.Context) error
+}
@@ -0,0 +1,127 @@
+package organisations
+
+import (
+	"fmt"
+	"time"
+)
+
+// Organisation represents an NHS trust, charity or GP practice
+type Organisation struct {
+	ID               string    `json:"id" db:"id"`
+	Name             string    `json:"name" db:"name"`
+	OrganisationType string    `json:"organisation_type" db:"organisation_type"`
+	ODSCode          *string   `json:"ods_code,omitempty" db:"ods_code"`
+	ContactEmail     *string   `json:"contact_email,omitempty" db:"contact_email"`
+	IsActive         bool      `json:"is_active" db:"is_active"`
+	MemberCount      int       `json:"member_count" db:"member_count"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// OrganisationType represents valid organisation types
+type OrganisationType string
+
+const (
+	TypeNHSTrust   OrganisationType = "nhs_trust"
+	TypeCharity    OrganisationType = "charity"
+	TypeGPPractice OrganisationType = "gp_practice"
+	TypeOther      OrganisationType = "other"
+)
+
+// MemberRole represents a staff member's role within an organisation
+type MemberRole string
+
+const (
+	MemberRoleMember MemberRole = "member"
+	MemberRoleAdmin  MemberRole = "admin"
+)
+
+// Member represents a staff member's membership of an organisation
+type Member struct {
+	ID             string    `json:"id" db:"id"`
+	OrganisationID string    `json:"organisation_id" db:"organisation_id"`
+	UserID         string    `json:"user_id" db:"user_id"`
+	Role           string    `json:"role" db:"role"`
+	FullName       string    `json:"full_name" db:"full_name"`
+	Email          string    `json:"email" db:"email"`
+	UserRole       string    `json:"user_role" db:"user_role"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// CreateOrganisationRequest represents the request to create an organisation
+type CreateOrganisationRequest struct {
+	Name             string  `json:"name" validate:"required,min=2,max=255"`
+	OrganisationType string  `json:"organisation_type" validate:"required,oneof=nhs_trust charity gp_practice other"`
+	ODSCode          *string `json:"ods_code,omitempty" validate:"omitempty,max=20"`
+	ContactEmail     *string `json:"contact_email,omitempty" validate:"omitempty,email"`
+}
+
+// UpdateOrganisationRequest represents the request to update an organisation
+type UpdateOrganisationRequest struct {
+	Name             *string `json:"name,omitempty" validate:"omitempty,min=2,max=255"`
+	OrganisationType *string `json:"organisation_type,omitempty" validate:"omitempty,oneof=nhs_trust charity gp_practice other"`
+	ODSCode          *string `json:"ods_code,omitempty" validate:"omitempty,max=20"`
+	ContactEmail     *string `json:"contact_email,omitempty" validate:"omitempty,email"`
+	IsActive         *bool   `json:"is_active,omitempty"`
+}
+
+// AddMemberRequest represents the request to add a staff member to an organisation
+type AddMemberRequest struct {
+	UserID string `json:"user_id" validate:"required"`
+	Role   string `json:"role,omitempty" validate:"omitempty,oneof=member admin"`
+}
+
+// ListOrganisationsResponse represents the response for listing organisations
+type ListOrganisationsResponse struct {
+	Organisations []Organisation `json:"organisations"`
+	Total         int            `json:"total"`
+}
+
+// ListMembersResponse represents the response for listing organisation members
+type ListMembersResponse struct {
+	Members []Member `json:"members"`
+	Total   int      `json:"total"`
+}
+
+// Scope limits admin queries to the organisations a staff member belongs to.
+// Super admins get an unrestricted scope unless they pick an organisation.
+type Scope struct {
+	All             bool     `json:"all"`
+	OrganisationIDs []string `json:"organisation_ids"`
+	IsSuperAdmin    bool     `json:"is_super_admin"`
+}
+
+// Allows reports whether the scope covers the given organisation
+func (s Scope) Allows(organisationID string) bool {
+	if s.All {
+		return true
+	}
+	for _, id := range s.OrganisationIDs {
+		if id == organisationID {
+			return true
+		}
+	}
+	return false
+}
+
+// Covers reports whether a row owned by organisationID is within the scope.
+// Like Condition, rows without an owner are only covered by an unrestricted scope.
+func (s Scope) Covers(organisationID *string) bool {
+	if s.All {
+		return true
+	}
+	return organisationID != nil && s.Allows(*organisationID)
+}
+
+// Condition returns a SQL condition restricting column to the scope's
+// organisations, or an empty string when the scope is unrestricted
+func (s Scope) Condition(column string, argIndex int) (string, []interface{}) {
+	if s.All {
+		return "", nil
+	}
+	ids := s.OrganisationIDs
+	if ids == nil {
+		ids = []string{}
+	}
+	return fmt.Sprintf("%s = ANY($%d::uuid[])", column, argIndex), []interface{}{ids}
+}
@@ -0,0 +1,22 @@
+package organisations
+
+import (
+	"errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ScopeContextKey is the echo context key holding the caller's Scope
+const ScopeContextKey = "organisation_scope"
+
+// ErrOutOfScope is returned when a caller asks for an organisation they do not belong to
+var ErrOutOfScope = errors.New("organisation is outside your scope")
+
+// ScopeFromContext returns the scope set by the organisation scope middleware.
+// Without one the scope is empty, so scoped queries match nothing.
+func ScopeFromContext(c echo.Context) Scope {
+	if scope, ok := c.Get(ScopeContextKey).(*Scope); ok && scope != nil {
+		return *scope
+	}
+	return Scope{}
+}
@@ -0,0 +1,153 @@
+package organisations
+
+import (
+	"context"
+	"fmt"
+)
+
+type service struct {
+	store Store
+}
+
+func NewService(store Store) Service {
+	return &service{
+		store: store,
+	}
+}
+
+// ResolveScope returns the organisations the caller may see, narrowed to a single
+// organisation when one is requested
+func (s *service) ResolveScope(ctx context.Context, userID, organisationID string) (*Scope, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user ID is required")
+	}
+
+	scope, err := s.store.GetScope(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if organisationID == "" {
+		return scope, nil
+	}
+
+	if !scope.Allows(organisationID) {
+		return nil, ErrOutOfScope
+	}
+
+	return &Scope{
+		OrganisationIDs: []string{organisationID},
+		IsSuperAdmin:    scope.IsSuperAdmin,
+	}, nil
+}
+
+// ListOrganisations retrieves the organisations within the caller's scope
+func (s *service) ListOrganisations(ctx context.Context, scope Scope) (*ListOrganisationsResponse, error) {
+	organisations, err := s.store.ListOrganisations(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if organisations == nil {
+		organisations = []Organisation{}
+	}
+
+	return &ListOrganisationsResponse{
+		Organisations: organisations,
+		Total:         len(organisations),
+	}, nil
+}
+
+// GetOrganisation retrieves an organisation the caller may see
+func (s *service) GetOrganisation(ctx context.Context, scope Scope, organisationID string) (*Organisation, error) {
+	if organisationID == "" {
+		return nil, fmt.Errorf("organisation ID is required")
+	}
+
+	if !scope.Allows(organisationID) {
+		return nil, ErrOutOfScope
+	}
+
+	return s.store.GetOrganisation(ctx, organisationID)
+}
+
+// CreateOrganisation creates a new organisation
+func (s *service) CreateOrganisation(ctx context.Context, req *CreateOrganisationRequest) (*Organisation, error) {
+	return s.store.CreateOrganisation(ctx, req)
+}
+
+// UpdateOrganisation updates an organisation
+func (s *service) UpdateOrganisation(ctx context.Context, organisationID string, req *UpdateOrganisationRequest) (*Organisation, error) {
+	if organisationID == "" {
+		return nil, fmt.Errorf("organisation ID is required")
+	}
+
+	return s.store.UpdateOrganisation(ctx, organisationID, req)
+}
+
+// ListMembers retrieves the staff members of an organisation the caller may see
+func (s *service) ListMembers(ctx context.Context, scope Scope, organisationID string) (*ListMembersResponse, error) {
+	if !scope.Allows(organisationID) {
+		return nil, ErrOutOfScope
+	}
+
+	members, err := s.store.ListMembers(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if members == nil {
+		members = []Member{}
+	}
+
+	return &ListMembersResponse{
+		Members: members,
+		Total:   len(members),
+	}, nil
+}
+
+// AddMember adds a staff member to an organisation. Only super admins and the
+// organisation's own admins may manage its members.
+func (s *service) AddMember(ctx context.Context, scope Scope, callerID, organisationID string, req *AddMemberRequest) (*Member, error) {
+	if err := s.checkCanManage(ctx, scope, callerID, organisationID); err != nil {
+		return nil, err
+	}
+
+	return s.store.AddMember(ctx, organisationID, req)
+}
+
+// RemoveMember removes a staff member from an organisation
+func (s *service) RemoveMember(ctx context.Context, scope Scope, callerID, organisationID, userID string) error {
+	if userID == "" {
+		return fmt.Errorf("user ID is required")
+	}
+
+	if err := s.checkCanManage(ctx, scope, callerID, organisationID); err != nil {
+		return err
+	}
+
+	return s.store.RemoveMember(ctx, organisationID, userID)
+}
+
+// Helper functions
+
+func (s *service) checkCanManage(ctx context.Context, scope Scope, callerID, organisationID string) error {
+	if organisationID == "" {
+		return fmt.Errorf("organisation ID is required")
+	}
+
+	if scope.IsSuperAdmin {
+		return nil
+	}
+
+	role, err := s.store.GetMemberRole(ctx, organisationID, callerID)
+	if err != nil {
+		return err
+	}
+
+	if role != string(MemberRoleAdmin) {
+		return ErrOutOfScope
+	}
+
+	return nil
+}
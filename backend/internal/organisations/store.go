@@ -0,0 +1,334 @@
+package organisations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type store struct {
+	db *pgxpool.Pool
+}
+
+func NewStore(db *pgxpool.Pool) Store {
+	return &store{
+		db: db,
+	}
+}
+
+const organisationColumns = `o.id, o.name, o.organisation_type, o.ods_code, o.contact_email, o.is_active,
+	       (SELECT COUNT(*) FROM organisation_members m WHERE m.organisation_id = o.id) as member_count,
+	       o.created_at, o.updated_at`
+
+// GetScope loads the caller's super admin flag and active organisation memberships
+func (s *store) GetScope(ctx context.Context, userID string) (*Scope, error) {
+	query := `
+		SELECT u.is_super_admin,
+		       COALESCE(array_agg(m.organisation_id::text) FILTER (WHERE o.is_active = true), '{}')
+		FROM users u
+		LEFT JOIN organisation_members m ON m.user_id = u.id
+		LEFT JOIN organisations o ON o.id = m.organisation_id
+		WHERE u.id = $1 AND u.is_active = true
+		GROUP BY u.id
+	`
+
+	scope := &Scope{}
+	err := s.db.QueryRow(ctx, query, userID).Scan(&scope.IsSuperAdmin, &scope.OrganisationIDs)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get organisation scope: %w", err)
+	}
+
+	scope.All = scope.IsSuperAdmin
+	return scope, nil
+}
+
+// ListOrganisations retrieves the organisations within the scope
+func (s *store) ListOrganisations(ctx context.Context, scope Scope) ([]Organisation, error) {
+	whereSQL := ""
+	condition, args := scope.Condition("o.id", 1)
+	if condition != "" {
+		whereSQL = "WHERE " + condition
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM organisations o
+		%s
+		ORDER BY o.name ASC
+	`, organisationColumns, whereSQL)
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query organisations: %w", err)
+	}
+	defer rows.Close()
+
+	var organisations []Organisation
+	for rows.Next() {
+		organisation, err := scanOrganisation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan organisation: %w", err)
+		}
+		organisations = append(organisations, *organisation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return organisations, nil
+}
+
+// GetOrganisation retrieves an organisation by ID
+func (s *store) GetOrganisation(ctx context.Context, organisationID string) (*Organisation, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM organisations o
+		WHERE o.id = $1
+	`, organisationColumns)
+
+	organisation, err := scanOrganisation(s.db.QueryRow(ctx, query, organisationID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("organisation not found")
+		}
+		return nil, fmt.Errorf("failed to get organisation: %w", err)
+	}
+
+	return organisation, nil
+}
+
+// CreateOrganisation creates a new organisation
+func (s *store) CreateOrganisation(ctx context.Context, req *CreateOrganisationRequest) (*Organisation, error) {
+	organisationID := uuid.New()
+	now := time.Now()
+
+	query := `
+		INSERT INTO organisations (id, name, organisation_type, ods_code, contact_email, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, true, $6, $6)
+	`
+
+	_, err := s.db.Exec(ctx, query, organisationID, req.Name, req.OrganisationType, req.ODSCode, req.ContactEmail, now)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("an organisation with this ODS code already exists")
+		}
+		return nil, fmt.Errorf("failed to create organisation: %w", err)
+	}
+
+	return s.GetOrganisation(ctx, organisationID.String())
+}
+
+// UpdateOrganisation updates an organisation
+func (s *store) UpdateOrganisation(ctx context.Context, organisationID string, req *UpdateOrganisationRequest) (*Organisation, error) {
+	var setParts []string
+	var args []interface{}
+	argIndex := 1
+
+	if req.Name != nil {
+		setParts = append(setParts, fmt.Sprintf("name = $%d", argIndex))
+		args = append(args, *req.Name)
+		argIndex++
+	}
+
+	if req.OrganisationType != nil {
+		setParts = append(setParts, fmt.Sprintf("organisation_type = $%d", argIndex))
+		args = append(args, *req.OrganisationType)
+		argIndex++
+	}
+
+	if req.ODSCode != nil {
+		setParts = append(setParts, fmt.Sprintf("ods_code = NULLIF($%d, '')", argIndex))
+		args = append(args, *req.ODSCode)
+		argIndex++
+	}
+
+	if req.ContactEmail != nil {
+		setParts = append(setParts, fmt.Sprintf("contact_email = $%d", argIndex))
+		args = append(args, *req.ContactEmail)
+		argIndex++
+	}
+
+	if req.IsActive != nil {
+		setParts = append(setParts, fmt.Sprintf("is_active = $%d", argIndex))
+		args = append(args, *req.IsActive)
+		argIndex++
+	}
+
+	if len(setParts) == 0 {
+		return s.GetOrganisation(ctx, organisationID)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE organisations
+		SET %s
+		WHERE id = $%d
+	`, strings.Join(setParts, ", "), argIndex)
+
+	args = append(args, organisationID)
+
+	result, err := s.db.Exec(ctx, query, args...)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("an organisation with this ODS code already exists")
+		}
+		return nil, fmt.Errorf("failed to update organisation: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return nil, fmt.Errorf("organisation not found")
+	}
+
+	return s.GetOrganisation(ctx, organisationID)
+}
+
+// ListMembers retrieves the staff members of an organisation
+func (s *store) ListMembers(ctx context.Context, organisationID string) ([]Member, error) {
+	query := `
+		SELECT m.id, m.organisation_id, m.user_id, m.role, u.full_name, u.email, u.role, m.created_at
+		FROM organisation_members m
+		JOIN users u ON m.user_id = u.id
+		WHERE m.organisation_id = $1
+		ORDER BY u.full_name ASC
+	`
+
+	rows, err := s.db.Query(ctx, query, organisationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query organisation members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []Member
+	for rows.Next() {
+		var member Member
+		err := rows.Scan(
+			&member.ID,
+			&member.OrganisationID,
+			&member.UserID,
+			&member.Role,
+			&member.FullName,
+			&member.Email,
+			&member.UserRole,
+			&member.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan organisation member: %w", err)
+		}
+		members = append(members, member)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return members, nil
+}
+
+// GetMemberRole returns the user's role in the organisation, or an empty string if they are not a member
+func (s *store) GetMemberRole(ctx context.Context, organisationID, userID string) (string, error) {
+	var role string
+	err := s.db.QueryRow(ctx, `
+		SELECT role FROM organisation_members WHERE organisation_id = $1 AND user_id = $2
+	`, organisationID, userID).Scan(&role)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get member role: %w", err)
+	}
+
+	return role, nil
+}
+
+// AddMember adds a staff member to an organisation, updating their role if they already belong
+func (s *store) AddMember(ctx context.Context, organisationID string, req *AddMemberRequest) (*Member, error) {
+	role := req.Role
+	if role == "" {
+		role = string(MemberRoleMember)
+	}
+
+	query := `
+		WITH upserted AS (
+			INSERT INTO organisation_members (id, organisation_id, user_id, role, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $5)
+			ON CONFLICT (organisation_id, user_id) DO UPDATE SET role = EXCLUDED.role
+			RETURNING id, organisation_id, user_id, role, created_at
+		)
+		SELECT m.id, m.organisation_id, m.user_id, m.role, u.full_name, u.email, u.role, m.created_at
+		FROM upserted m
+		JOIN users u ON m.user_id = u.id
+	`
+
+	var member Member
+	err := s.db.QueryRow(ctx, query, uuid.New(), organisationID, req.UserID, role, time.Now()).Scan(
+		&member.ID,
+		&member.OrganisationID,
+		&member.UserID,
+		&member.Role,
+		&member.FullName,
+		&member.Email,
+		&member.UserRole,
+		&member.CreatedAt,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, fmt.Errorf("organisation or user not found")
+		}
+		return nil, fmt.Errorf("failed to add organisation member: %w", err)
+	}
+
+	return &member, nil
+}
+
+// RemoveMember removes a staff member from an organisation
+func (s *store) RemoveMember(ctx context.Context, organisationID, userID string) error {
+	result, err := s.db.Exec(ctx, `
+		DELETE FROM organisation_members WHERE organisation_id = $1 AND user_id = $2
+	`, organisationID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove organisation member: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("membership not found")
+	}
+
+	return nil
+}
+
+// Helper functions
+
+func scanOrganisation(row pgx.Row) (*Organisation, error) {
+	var organisation Organisation
+	err := row.Scan(
+		&organisation.ID,
+		&organisation.Name,
+		&organisation.OrganisationType,
+		&organisation.ODSCode,
+		&organisation.ContactEmail,
+		&organisation.IsActive,
+		&organisation.MemberCount,
+		&organisation.CreatedAt,
+		&organisation.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &organisation, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,95 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Cursor directions
+const (
+	DirectionNext = "next"
+	DirectionPrev = "prev"
+)
+
+// ErrInvalidCursor is returned for a cursor token this API didn't issue
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is the decoded form of the opaque next_cursor and prev_cursor tokens.
+// It records the sort key of the row the page starts after (or before).
+type Cursor struct {
+	Rank      int       `json:"r,omitempty"`
+	SortValue time.Time `json:"t"`
+	ID        string    `json:"id"`
+	Direction string    `json:"d"`
+}
+
+// Params are the keyset pagination inputs shared by list requests
+type Params struct {
+	Cursor       string `json:"cursor,omitempty"`
+	IncludeTotal *bool  `json:"include_total,omitempty"`
+}
+
+// ParseQuery reads the cursor and include_total query parameters
+func ParseQuery(c echo.Context) Params {
+	params := Params{
+		Cursor: c.QueryParam("cursor"),
+	}
+
+	if it := c.QueryParam("include_total"); it != "" {
+		if parsed, err := strconv.ParseBool(it); err == nil {
+			params.IncludeTotal = &parsed
+		}
+	}
+
+	return params
+}
+
+// WantTotal reports whether the COUNT(*) query should run. Page-number requests
+// keep returning the total unless asked not to; cursor requests skip it by default.
+func (p Params) WantTotal() bool {
+	if p.IncludeTotal != nil {
+		return *p.IncludeTotal
+	}
+	return p.Cursor == ""
+}
+
+// DecodeCursor parses the cursor token, returning nil when none was given
+func (p Params) DecodeCursor() (*Cursor, error) {
+	if p.Cursor == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(p.Cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	// The ID is cast to uuid in the keyset condition, so reject anything else here
+	if _, err := uuid.Parse(cursor.ID); err != nil || (cursor.Direction != DirectionNext && cursor.Direction != DirectionPrev) {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+// Encode returns the opaque token for the cursor
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// IsPrev reports whether the cursor pages backwards
+func (c *Cursor) IsPrev() bool {
+	return c != nil && c.Direction == DirectionPrev
+}
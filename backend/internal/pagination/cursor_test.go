@@ -0,0 +1,116 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := Cursor{
+		Rank:      1,
+		SortValue: time.Date(2026, 3, 15, 9, 30, 0, 0, time.UTC),
+		ID:        "6f1c2a8e-4b7d-4f3a-9c55-0d2e8b7a1f10",
+		Direction: DirectionPrev,
+	}
+
+	decoded, err := Params{Cursor: cursor.Encode()}.DecodeCursor()
+	if err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+	if decoded.Rank != cursor.Rank || !decoded.SortValue.Equal(cursor.SortValue) || decoded.ID != cursor.ID || !decoded.IsPrev() {
+		t.Errorf("DecodeCursor() = %+v, want %+v", decoded, cursor)
+	}
+
+	if decoded, err := (Params{}).DecodeCursor(); decoded != nil || err != nil {
+		t.Errorf("DecodeCursor() without a cursor = (%v, %v), want (nil, nil)", decoded, err)
+	}
+}
+
+func TestDecodeCursorRejectsBadInput(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "not a cursor!"},
+		{"not json", encode("cursor")},
+		{"missing id", encode(`{"t":"2026-03-15T09:30:00Z","d":"next"}`)},
+		{"id not a uuid", encode(`{"t":"2026-03-15T09:30:00Z","id":"1' OR '1'='1","d":"next"}`)},
+		{"unknown direction", encode(`{"t":"2026-03-15T09:30:00Z","id":"6f1c2a8e-4b7d-4f3a-9c55-0d2e8b7a1f10","d":"up"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := (Params{Cursor: tt.cursor}).DecodeCursor(); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor() error = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestKeysetBindsDatesInUTC(t *testing.T) {
+	// Late evening in New York is already the next day in UTC
+	newYork := time.FixedZone("EST", -5*60*60)
+	cursor := &Cursor{SortValue: time.Date(2026, 3, 14, 22, 0, 0, 0, newYork), ID: "6f1c2a8e-4b7d-4f3a-9c55-0d2e8b7a1f10", Direction: DirectionNext}
+
+	where, args := Keyset{SortColumn: "entry_date", SortType: "date", IDColumn: "id"}.Where(cursor, 2)
+	if where != "(entry_date, id) < ($2::date, $3::uuid)" {
+		t.Errorf("Where() = %q", where)
+	}
+	if args[0] != "2026-03-15" {
+		t.Errorf("Where() date argument = %v, want 2026-03-15", args[0])
+	}
+}
+
+func TestPaginateWindowRoundTrip(t *testing.T) {
+	base := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	ids := []string{
+		"00000000-0000-4000-8000-000000000005",
+		"00000000-0000-4000-8000-000000000004",
+		"00000000-0000-4000-8000-000000000003",
+		"00000000-0000-4000-8000-000000000002",
+		"00000000-0000-4000-8000-000000000001",
+	}
+	items := make([]Cursor, len(ids))
+	for i, id := range ids {
+		items[i] = Cursor{SortValue: base.Add(-time.Duration(i) * time.Hour), ID: id}
+	}
+	key := func(c Cursor) Cursor { return c }
+
+	page := func(token *string) ([]Cursor, *string, *string) {
+		var cursor *Cursor
+		if token != nil {
+			decoded, err := Params{Cursor: *token}.DecodeCursor()
+			if err != nil {
+				t.Fatalf("DecodeCursor() error = %v", err)
+			}
+			cursor = decoded
+		}
+		return Paginate(Window(items, cursor, 0, 2, key), 2, cursor, 0, key)
+	}
+
+	first, next, prev := page(nil)
+	if len(first) != 2 || first[0].ID != ids[0] || next == nil || prev != nil {
+		t.Fatalf("first page = %v, next %v, prev %v", first, next, prev)
+	}
+
+	second, next, prev := page(next)
+	if len(second) != 2 || second[0].ID != ids[2] || next == nil || prev == nil {
+		t.Fatalf("second page = %v, next %v, prev %v", second, next, prev)
+	}
+
+	back, _, prev := page(prev)
+	if len(back) != 2 || back[0].ID != ids[0] || back[1].ID != ids[1] || prev != nil {
+		t.Errorf("previous page = %v, prev %v, want the first page again", back, prev)
+	}
+
+	last, next, _ := page(next)
+	if len(last) != 1 || last[0].ID != ids[4] || next != nil {
+		t.Errorf("last page = %v, next %v", last, next)
+	}
+}
@@ -0,0 +1,156 @@
+package pagination
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Keyset describes the columns a list is ordered on, all descending. RankColumn is
+// an optional leading integer expression, such as featured-first ordering; IDColumn
+// breaks ties so the order is stable. SortType casts the cursor value when the sort
+// column is not a timestamp, e.g. "date"; date cursors are bound as their UTC
+// calendar day so the comparison doesn't depend on the session time zone.
+type Keyset struct {
+	RankColumn string
+	SortColumn string
+	SortType   string
+	IDColumn   string
+}
+
+// Where returns the condition selecting rows after the cursor in its direction,
+// with its arguments numbered from argIndex.
+func (k Keyset) Where(cursor *Cursor, argIndex int) (string, []interface{}) {
+	operator := "<"
+	if cursor.IsPrev() {
+		operator = ">"
+	}
+
+	sortCast := ""
+	var sortValue interface{} = cursor.SortValue
+	if k.SortType != "" {
+		sortCast = "::" + k.SortType
+	}
+	if k.SortType == "date" {
+		sortValue = cursor.SortValue.UTC().Format(time.DateOnly)
+	}
+
+	if k.RankColumn != "" {
+		return fmt.Sprintf("(%s, %s, %s) %s ($%d, $%d%s, $%d::uuid)",
+				k.RankColumn, k.SortColumn, k.IDColumn, operator, argIndex, argIndex+1, sortCast, argIndex+2),
+			[]interface{}{cursor.Rank, sortValue, cursor.ID}
+	}
+
+	return fmt.Sprintf("(%s, %s) %s ($%d%s, $%d::uuid)",
+			k.SortColumn, k.IDColumn, operator, argIndex, sortCast, argIndex+1),
+		[]interface{}{sortValue, cursor.ID}
+}
+
+// OrderBy returns the ORDER BY expression list. Backward pages are read in
+// ascending order and flipped by Paginate.
+func (k Keyset) OrderBy(cursor *Cursor) string {
+	direction := "DESC"
+	if cursor.IsPrev() {
+		direction = "ASC"
+	}
+
+	if k.RankColumn != "" {
+		return fmt.Sprintf("%s %s, %s %s, %s %s", k.RankColumn, direction, k.SortColumn, direction, k.IDColumn, direction)
+	}
+	return fmt.Sprintf("%s %s, %s %s", k.SortColumn, direction, k.IDColumn, direction)
+}
+
+// Paginate trims a result fetched with LIMIT pageSize+1 to the page, restores
+// descending order for backward pages and builds the surrounding cursors.
+// offset is the OFFSET used for page-number requests.
+func Paginate[T any](items []T, pageSize int, cursor *Cursor, offset int, key func(T) Cursor) ([]T, *string, *string) {
+	hasMore := len(items) > pageSize
+	if hasMore {
+		items = items[:pageSize]
+	}
+
+	hasNext, hasPrev := hasMore, cursor != nil || offset > 0
+	if cursor.IsPrev() {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+		hasNext, hasPrev = true, hasMore
+	}
+
+	if len(items) == 0 {
+		return items, nil, nil
+	}
+
+	var next, prev *string
+	if hasNext {
+		c := key(items[len(items)-1])
+		c.Direction = DirectionNext
+		token := c.Encode()
+		next = &token
+	}
+	if hasPrev {
+		c := key(items[0])
+		c.Direction = DirectionPrev
+		token := c.Encode()
+		prev = &token
+	}
+
+	return items, next, prev
+}
+
+// TotalPages returns the page count for a total, matching the page-number responses
+func TotalPages(total int64, pageSize int) int {
+	return int((total + int64(pageSize) - 1) / int64(pageSize))
+}
+
+// Compare orders two cursors by rank, sort value and ID, the same way the
+// keyset row comparison does in SQL
+func Compare(a, b Cursor) int {
+	switch {
+	case a.Rank != b.Rank:
+		if a.Rank < b.Rank {
+			return -1
+		}
+		return 1
+	case !a.SortValue.Equal(b.SortValue):
+		if a.SortValue.Before(b.SortValue) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a.ID, b.ID)
+}
+
+// Window selects the rows a keyset query would fetch from items that are
+// already sorted by key in descending order: the rows past the cursor in its
+// direction, skipping offset, with one extra row for Paginate. It is used by
+// the in-memory stores.
+func Window[T any](items []T, cursor *Cursor, offset, pageSize int, key func(T) Cursor) []T {
+	var selected []T
+	switch {
+	case cursor == nil:
+		selected = append(selected, items...)
+	case cursor.IsPrev():
+		for i := len(items) - 1; i >= 0; i-- {
+			if Compare(key(items[i]), *cursor) > 0 {
+				selected = append(selected, items[i])
+			}
+		}
+	default:
+		for _, item := range items {
+			if Compare(key(item), *cursor) < 0 {
+				selected = append(selected, item)
+			}
+		}
+	}
+
+	if offset >= len(selected) {
+		return nil
+	}
+	selected = selected[offset:]
+	if len(selected) > pageSize+1 {
+		selected = selected[:pageSize+1]
+	}
+
+	return selected
+}
@@ -0,0 +1,61 @@
+// Package perinatal describes where a user is in the perinatal period, from
+// pregnancy to a year after birth, so content can be matched to it.
+package perinatal
+
+import (
+	"errors"
+	"time"
+)
+
+// Stage is a point in the perinatal period. Catalog items are tagged with the
+// stages they are relevant to; an item with no stages suits everyone.
+type Stage string
+
+const (
+	StageFirstTrimester  Stage = "first_trimester"  // Less than 13 weeks pregnant
+	StageSecondTrimester Stage = "second_trimester" // 13 to 26 weeks
+	StageThirdTrimester  Stage = "third_trimester"  // 27 weeks until birth
+	StagePostnatal       Stage = "postnatal"        // The year after birth
+	StageAfterLoss       Stage = "after_loss"       // The year after a pregnancy or baby loss
+	StageNone            Stage = "none"             // Outside the perinatal period, or not known
+)
+
+// Stages lists the stages catalog items can be tagged with and filtered by
+var Stages = []Stage{StageFirstTrimester, StageSecondTrimester, StageThirdTrimester, StagePostnatal, StageAfterLoss}
+
+const (
+	// PregnancyLength is the time from the start of the last period to the due date
+	PregnancyLength = 280 * 24 * time.Hour
+	// PostTermAllowance is how long after the due date a pregnancy is assumed
+	// to continue if no birth has been recorded
+	PostTermAllowance = 3 * 7 * 24 * time.Hour
+	// PeriodWeeks is how long the postnatal and after-loss stages last
+	PeriodWeeks = 52
+	// MaxBabies bounds the number of babies in one pregnancy
+	MaxBabies = 8
+	// MaxBirthDates bounds the number of recorded births
+	MaxBirthDates = 10
+)
+
+var ErrInvalidDetails = errors.New("invalid perinatal details")
+
+// Details are the pregnancy and birth dates a user has shared. Only dates are
+// significant; times are discarded.
+type Details struct {
+	DueDate    *time.Time  `json:"due_date,omitempty"`    // Estimated due date of the current pregnancy
+	BirthDates []time.Time `json:"birth_dates,omitempty"` // Babies born, including earlier children
+	LossDate   *time.Time  `json:"loss_date,omitempty"`   // Most recent pregnancy or baby loss
+	// BabyCount is the number of babies expected or born in the latest
+	// pregnancy, 2 or more for a multiple birth. Zero means one.
+	BabyCount int `json:"baby_count,omitempty" validate:"omitempty,min=1,max=8"`
+}
+
+// Progress is the stage computed from a user's details on a given day
+type Progress struct {
+	Stage          Stage `json:"stage"`
+	Trimester      *int  `json:"trimester,omitempty"`
+	WeeksPregnant  *int  `json:"weeks_pregnant,omitempty"`
+	WeeksPostnatal *int  `json:"weeks_postnatal,omitempty"`
+	WeeksSinceLoss *int  `json:"weeks_since_loss,omitempty"`
+	MultipleBirth  bool  `json:"multiple_birth"`
+}
@@ -0,0 +1,176 @@
+package perinatal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Normalise checks details a user has entered and returns a copy with times
+// discarded and birth dates in order, oldest first
+func Normalise(details *Details, now time.Time) (*Details, error) {
+	today := dateOf(now)
+	normalised := &Details{BabyCount: details.BabyCount}
+
+	if details.BabyCount < 0 || details.BabyCount > MaxBabies {
+		return nil, fmt.Errorf("%w: baby_count must be between 1 and %d", ErrInvalidDetails, MaxBabies)
+	}
+
+	if details.DueDate != nil {
+		due := dateOf(*details.DueDate)
+		if due.After(today.Add(PregnancyLength)) || due.Before(today.AddDate(-1, 0, 0)) {
+			return nil, fmt.Errorf("%w: due_date must be within the next 40 weeks or the past year", ErrInvalidDetails)
+		}
+		normalised.DueDate = &due
+	}
+
+	if len(details.BirthDates) > MaxBirthDates {
+		return nil, fmt.Errorf("%w: at most %d birth dates can be recorded", ErrInvalidDetails, MaxBirthDates)
+	}
+	seen := make(map[time.Time]bool)
+	for _, birthDate := range details.BirthDates {
+		date := dateOf(birthDate)
+		if date.After(today) {
+			return nil, fmt.Errorf("%w: birth_dates can't be in the future", ErrInvalidDetails)
+		}
+		// Twins share a birth date
+		if !seen[date] {
+			seen[date] = true
+			normalised.BirthDates = append(normalised.BirthDates, date)
+		}
+	}
+	sort.Slice(normalised.BirthDates, func(i, j int) bool {
+		return normalised.BirthDates[i].Before(normalised.BirthDates[j])
+	})
+
+	if details.LossDate != nil {
+		loss := dateOf(*details.LossDate)
+		if loss.After(today) {
+			return nil, fmt.Errorf("%w: loss_date can't be in the future", ErrInvalidDetails)
+		}
+		normalised.LossDate = &loss
+	}
+
+	return normalised, nil
+}
+
+// Compute works out the stage a user is at on the day of now. A pregnancy is
+// current from its start until a birth or loss is recorded, or until
+// PostTermAllowance after the due date; otherwise the most recent birth or
+// loss decides the stage for PeriodWeeks afterwards.
+func Compute(details *Details, now time.Time) Progress {
+	progress := Progress{Stage: StageNone}
+	if details == nil {
+		return progress
+	}
+	progress.MultipleBirth = details.BabyCount >= 2
+
+	today := dateOf(now)
+	latestBirth := latest(details.BirthDates)
+	var loss *time.Time
+	if details.LossDate != nil {
+		date := dateOf(*details.LossDate)
+		loss = &date
+	}
+
+	if details.DueDate != nil {
+		due := dateOf(*details.DueDate)
+		start := due.Add(-PregnancyLength)
+		ended := (latestBirth != nil && !latestBirth.Before(start)) || (loss != nil && !loss.Before(start))
+		if !ended && !today.Before(start) && !today.After(due.Add(PostTermAllowance)) {
+			weeks := weeksBetween(start, today)
+			trimester := 1
+			progress.Stage = StageFirstTrimester
+			switch {
+			case weeks >= 27:
+				trimester = 3
+				progress.Stage = StageThirdTrimester
+			case weeks >= 13:
+				trimester = 2
+				progress.Stage = StageSecondTrimester
+			}
+			progress.Trimester = &trimester
+			progress.WeeksPregnant = &weeks
+			return progress
+		}
+	}
+
+	switch {
+	case latestBirth != nil && (loss == nil || latestBirth.After(*loss)):
+		if weeks := weeksBetween(*latestBirth, today); weeks < PeriodWeeks {
+			progress.Stage = StagePostnatal
+			progress.WeeksPostnatal = &weeks
+		}
+	case loss != nil:
+		if weeks := weeksBetween(*loss, today); weeks < PeriodWeeks {
+			progress.Stage = StageAfterLoss
+			progress.WeeksSinceLoss = &weeks
+		}
+	}
+
+	return progress
+}
+
+// ParseStage reads a stage used to filter catalog items
+func ParseStage(value string) (Stage, error) {
+	for _, stage := range Stages {
+		if string(stage) == value {
+			return stage, nil
+		}
+	}
+	return "", fmt.Errorf("invalid stage: %s", value)
+}
+
+// ValidateStages checks the stages a catalog item is tagged with
+func ValidateStages(stages []string) error {
+	for _, stage := range stages {
+		if _, err := ParseStage(stage); err != nil {
+			return fmt.Errorf("invalid perinatal stage: %s (must be one of %s)", stage, stageNames())
+		}
+	}
+	return nil
+}
+
+// Matches reports whether an item tagged with itemStages suits someone at stage.
+// Items without stages suit everyone.
+func Matches(itemStages []string, stage Stage) bool {
+	if len(itemStages) == 0 {
+		return true
+	}
+	for _, itemStage := range itemStages {
+		if itemStage == string(stage) {
+			return true
+		}
+	}
+	return false
+}
+
+// Helper functions
+
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func weeksBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours()/24) / 7
+}
+
+func latest(dates []time.Time) *time.Time {
+	var result *time.Time
+	for _, date := range dates {
+		date := dateOf(date)
+		if result == nil || date.After(*result) {
+			result = &date
+		}
+	}
+	return result
+}
+
+func stageNames() string {
+	names := make([]string, len(Stages))
+	for i, stage := range Stages {
+		names[i] = string(stage)
+	}
+	return strings.Join(names, ", ")
+}
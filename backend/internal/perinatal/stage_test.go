@@ -0,0 +1,97 @@
+package perinatal
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCompute(t *testing.T) {
+	now := time.Date(2025, time.June, 1, 15, 30, 0, 0, time.UTC)
+	day := func(daysFromNow int) *time.Time {
+		date := now.AddDate(0, 0, daysFromNow)
+		return &date
+	}
+
+	tests := []struct {
+		name    string
+		details *Details
+		stage   Stage
+		weeks   int // Weeks pregnant, postnatal or since loss
+	}{
+		{"nothing shared", nil, StageNone, 0},
+		{"early pregnancy", &Details{DueDate: day(280 - 8*7)}, StageFirstTrimester, 8},
+		{"second trimester", &Details{DueDate: day(280 - 20*7 - 3)}, StageSecondTrimester, 20},
+		{"third trimester", &Details{DueDate: day(10)}, StageThirdTrimester, 38},
+		{"overdue", &Details{DueDate: day(-7)}, StageThirdTrimester, 41},
+		{"long past due with no birth", &Details{DueDate: day(-60)}, StageNone, 0},
+		{"born early", &Details{DueDate: day(30), BirthDates: []time.Time{*day(-14)}}, StagePostnatal, 2},
+		{"postnatal", &Details{BirthDates: []time.Time{*day(-200), *day(-3000)}}, StagePostnatal, 28},
+		{"beyond the first year", &Details{BirthDates: []time.Time{*day(-400)}}, StageNone, 0},
+		{"loss in pregnancy", &Details{DueDate: day(150), LossDate: day(-21)}, StageAfterLoss, 3},
+		{"pregnant again after a loss", &Details{DueDate: day(200), LossDate: day(-120)}, StageFirstTrimester, 11},
+		{"loss after birth", &Details{BirthDates: []time.Time{*day(-30)}, LossDate: day(-7)}, StageAfterLoss, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress := Compute(tt.details, now)
+			if progress.Stage != tt.stage {
+				t.Fatalf("Compute() stage = %s, want %s", progress.Stage, tt.stage)
+			}
+
+			var weeks *int
+			switch tt.stage {
+			case StageFirstTrimester, StageSecondTrimester, StageThirdTrimester:
+				weeks = progress.WeeksPregnant
+			case StagePostnatal:
+				weeks = progress.WeeksPostnatal
+			case StageAfterLoss:
+				weeks = progress.WeeksSinceLoss
+			}
+			if tt.stage != StageNone && (weeks == nil || *weeks != tt.weeks) {
+				t.Errorf("Compute() = %+v, want %d weeks", progress, tt.weeks)
+			}
+		})
+	}
+}
+
+func TestNormalise(t *testing.T) {
+	now := time.Date(2025, time.June, 1, 9, 0, 0, 0, time.UTC)
+	twins := time.Date(2025, time.March, 3, 23, 50, 0, 0, time.UTC)
+	older := time.Date(2022, time.January, 10, 0, 0, 0, 0, time.UTC)
+
+	details, err := Normalise(&Details{BirthDates: []time.Time{twins, older, twins.Add(15 * time.Minute), twins}, BabyCount: 2}, now)
+	if err != nil {
+		t.Fatalf("Normalise() error = %v", err)
+	}
+	// Twins either side of midnight keep both dates
+	if len(details.BirthDates) != 3 || !details.BirthDates[0].Equal(older) || details.BirthDates[1].Hour() != 0 {
+		t.Errorf("Normalise() birth dates = %v, want three dates oldest first", details.BirthDates)
+	}
+
+	future := now.AddDate(0, 0, 2)
+	farFuture := now.AddDate(1, 0, 0)
+	for _, invalid := range []*Details{
+		{BirthDates: []time.Time{future}},
+		{LossDate: &future},
+		{DueDate: &farFuture},
+		{BabyCount: MaxBabies + 1},
+	} {
+		if _, err := Normalise(invalid, now); !errors.Is(err, ErrInvalidDetails) {
+			t.Errorf("Normalise(%+v) error = %v, want ErrInvalidDetails", invalid, err)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	if !Matches(nil, StagePostnatal) {
+		t.Error("untagged item doesn't match, want it to suit every stage")
+	}
+	if Matches([]string{"first_trimester"}, StagePostnatal) {
+		t.Error("first trimester item matches postnatal")
+	}
+	if err := ValidateStages([]string{"postnatal", "toddler"}); err == nil {
+		t.Error("ValidateStages() accepted an unknown stage")
+	}
+}
@@ -12,10 +12,16 @@ type Service interface {
 	GetPrivacyPreferences(ctx context.Context, userID string) (*PrivacyPreferences, error)
 	UpdatePrivacyPreferences(ctx context.Context, userID string, req *UpdatePrivacyPreferencesRequest) error
 	RequestDataDownload(ctx context.Context, userID string) error
+	ProcessDataDownload(ctx context.Context, job DataDownloadJob) error
 	RequestAccountDeletion(ctx context.Context, userID, reason string) error
 	GetDataRetentionInfo() *DataRetentionInfo
 	ExportUserData(ctx context.Context, userID string) (*DataExportResponse, error)
 	GetDataRequests(ctx context.Context, userID string) ([]DataRequest, error)
+
+	// Operator methods, used by the admin CLI
+	GetDataRequest(ctx context.Context, requestID string) (*DataRequest, error)
+	ListDataRequests(ctx context.Context, status string) ([]DataRequest, error)
+	ResolveDataRequest(ctx context.Context, requestID, status string, processedBy, notes *string) error
 }
 
 // Store defines the interface for privacy data persistence
@@ -28,6 +34,9 @@ type Store interface {
 	CreateDataRequest(ctx context.Context, request *DataRequest) error
 	GetDataRequestsByUser(ctx context.Context, userID string) ([]DataRequest, error)
 	UpdateDataRequestStatus(ctx context.Context, requestID, status string, notes *string) error
+	GetDataRequest(ctx context.Context, requestID string) (*DataRequest, error)
+	ListDataRequests(ctx context.Context, status string) ([]DataRequest, error)
+	ResolveDataRequest(ctx context.Context, requestID, status string, processedBy, notes *string) error
 }
 
 // Handler defines the interface for privacy HTTP handlers
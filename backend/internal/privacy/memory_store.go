@@ -0,0 +1,246 @@
+package privacy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+)
+
+// memoryStore keeps privacy settings and data requests in memory for tests and
+// demo mode. User and profile data is read from the shared tables.
+type memoryStore struct {
+	db           *memdb.DB
+	mu           sync.RWMutex
+	preferences  map[string]PrivacyPreferences
+	dataRequests map[string]DataRequest
+}
+
+func NewMemoryStore(db *memdb.DB) Store {
+	return &memoryStore{
+		db:           db,
+		preferences:  make(map[string]PrivacyPreferences),
+		dataRequests: make(map[string]DataRequest),
+	}
+}
+
+// GetPrivacyPreferences retrieves user's privacy preferences
+func (s *memoryStore) GetPrivacyPreferences(ctx context.Context, userID string) (*PrivacyPreferences, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	preferences, ok := s.preferences[userID]
+	if !ok {
+		return nil, fmt.Errorf("privacy preferences not found")
+	}
+
+	return &preferences, nil
+}
+
+// CreatePrivacyPreferences creates default privacy preferences for a user
+func (s *memoryStore) CreatePrivacyPreferences(ctx context.Context, preferences *PrivacyPreferences) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.preferences[preferences.UserID]; exists {
+		return fmt.Errorf("failed to create privacy preferences: already exist for user")
+	}
+	s.preferences[preferences.UserID] = *preferences
+
+	return nil
+}
+
+// UpdatePrivacyPreferences updates user's privacy preferences
+func (s *memoryStore) UpdatePrivacyPreferences(ctx context.Context, userID string, req *UpdatePrivacyPreferencesRequest) error {
+	if req.DataTrackingEnabled == nil && req.DataSharingEnabled == nil && req.CookiesEnabled == nil &&
+		req.MarketingEmailsEnabled == nil && req.AnalyticsEnabled == nil {
+		return fmt.Errorf("no fields to update")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	preferences, ok := s.preferences[userID]
+	if !ok {
+		return fmt.Errorf("privacy preferences not found")
+	}
+
+	if req.DataTrackingEnabled != nil {
+		preferences.DataTrackingEnabled = *req.DataTrackingEnabled
+	}
+	if req.DataSharingEnabled != nil {
+		preferences.DataSharingEnabled = *req.DataSharingEnabled
+	}
+	if req.CookiesEnabled != nil {
+		preferences.CookiesEnabled = *req.CookiesEnabled
+	}
+	if req.MarketingEmailsEnabled != nil {
+		preferences.MarketingEmailsEnabled = *req.MarketingEmailsEnabled
+	}
+	if req.AnalyticsEnabled != nil {
+		preferences.AnalyticsEnabled = *req.AnalyticsEnabled
+	}
+	preferences.UpdatedAt = time.Now()
+	s.preferences[userID] = preferences
+
+	return nil
+}
+
+// GetUserData retrieves user data for export
+func (s *memoryStore) GetUserData(ctx context.Context, userID string) (map[string]interface{}, error) {
+	row, ok := s.db.User(userID)
+	if !ok {
+		return nil, fmt.Errorf("failed to get user data: user not found")
+	}
+
+	user := map[string]interface{}{
+		"id":         row.ID,
+		"email":      row.Email,
+		"full_name":  row.FullName,
+		"role":       row.Role,
+		"is_active":  row.IsActive,
+		"created_at": row.CreatedAt,
+		"updated_at": row.UpdatedAt,
+	}
+
+	if row.LastLoginAt != nil {
+		user["last_login_at"] = *row.LastLoginAt
+	}
+
+	return user, nil
+}
+
+// GetUserProfileData retrieves user profile data for export
+func (s *memoryStore) GetUserProfileData(ctx context.Context, userID string) (map[string]interface{}, error) {
+	row, ok := s.db.Profile(userID)
+	if !ok {
+		return map[string]interface{}{"user_id": userID}, nil
+	}
+
+	profile := map[string]interface{}{
+		"user_id":    row.UserID,
+		"created_at": row.CreatedAt,
+		"updated_at": row.UpdatedAt,
+	}
+
+	optional := map[string]*string{
+		"phone_number":      row.PhoneNumber,
+		"address":           row.Address,
+		"emergency_contact": row.EmergencyContact,
+		"preferences":       row.Preferences,
+	}
+	for field, value := range optional {
+		if value != nil {
+			profile[field] = *value
+		}
+	}
+	if row.DateOfBirth != nil {
+		profile["date_of_birth"] = row.DateOfBirth.Format("2006-01-02")
+	}
+
+	return profile, nil
+}
+
+// CreateDataRequest creates a new data request
+func (s *memoryStore) CreateDataRequest(ctx context.Context, request *DataRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.dataRequests[request.ID]; exists {
+		return fmt.Errorf("failed to create data request: duplicate id")
+	}
+	s.dataRequests[request.ID] = *request
+
+	return nil
+}
+
+// GetDataRequestsByUser retrieves all data requests for a user, newest first
+func (s *memoryStore) GetDataRequestsByUser(ctx context.Context, userID string) ([]DataRequest, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var requests []DataRequest
+	for _, request := range s.dataRequests {
+		if request.UserID == userID {
+			requests = append(requests, request)
+		}
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].RequestedAt.After(requests[j].RequestedAt)
+	})
+
+	return requests, nil
+}
+
+// UpdateDataRequestStatus updates the status of a data request
+func (s *memoryStore) UpdateDataRequestStatus(ctx context.Context, requestID, status string, notes *string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	request, ok := s.dataRequests[requestID]
+	if !ok {
+		return fmt.Errorf("data request not found")
+	}
+
+	request.Status = status
+	request.Notes = notes
+	request.UpdatedAt = time.Now()
+	s.dataRequests[requestID] = request
+
+	return nil
+}
+
+// GetDataRequest retrieves a data request by ID
+func (s *memoryStore) GetDataRequest(ctx context.Context, requestID string) (*DataRequest, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	request, ok := s.dataRequests[requestID]
+	if !ok {
+		return nil, fmt.Errorf("data request not found")
+	}
+
+	return &request, nil
+}
+
+// ListDataRequests retrieves all data requests, optionally filtered by status, oldest first
+func (s *memoryStore) ListDataRequests(ctx context.Context, status string) ([]DataRequest, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var requests []DataRequest
+	for _, request := range s.dataRequests {
+		if status == "" || request.Status == status {
+			requests = append(requests, request)
+		}
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].RequestedAt.Before(requests[j].RequestedAt)
+	})
+
+	return requests, nil
+}
+
+// ResolveDataRequest sets the final status of a data request and who processed it
+func (s *memoryStore) ResolveDataRequest(ctx context.Context, requestID, status string, processedBy, notes *string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	request, ok := s.dataRequests[requestID]
+	if !ok {
+		return fmt.Errorf("data request not found")
+	}
+
+	now := time.Now()
+	request.Status = status
+	request.ProcessedBy = processedBy
+	request.Notes = notes
+	request.ProcessedAt = &now
+	request.UpdatedAt = now
+	s.dataRequests[requestID] = request
+
+	return nil
+}
@@ -74,3 +74,15 @@ const (
 	StatusCompleted  = "completed"
 	StatusRejected   = "rejected"
 )
+
+// Background job types and the queue they run on
+const (
+	JobQueue             = "privacy"
+	JobCompileDataExport = "privacy.compile_data_export"
+)
+
+// DataDownloadJob is the payload for a queued data export
+type DataDownloadJob struct {
+	RequestID string `json:"request_id"`
+	UserID    string `json:"user_id"`
+}
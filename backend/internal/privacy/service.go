@@ -7,15 +7,18 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/perinatal-mental-health-app/backend/internal/jobs"
 )
 
 type service struct {
 	store Store
+	queue jobs.Enqueuer
 }
 
-func NewService(store Store) Service {
+func NewService(store Store, queue jobs.Enqueuer) Service {
 	return &service{
 		store: store,
+		queue: queue,
 	}
 }
 
@@ -72,15 +75,8 @@ func (s *service) UpdatePrivacyPreferences(ctx context.Context, userID string, r
 	return s.store.UpdatePrivacyPreferences(ctx, userID, req)
 }
 
-// RequestDataDownload handles data download requests
+// RequestDataDownload records the request and queues a job to compile the export
 func (s *service) RequestDataDownload(ctx context.Context, userID string) error {
-	// TODO: Implement actual data download logic
-	// This should:
-	// 1. Create a data request record
-	// 2. Queue a background job to compile user data
-	// 3. Send email with download link when ready
-	// 4. Schedule automatic deletion of the download after 30 days
-
 	request := &DataRequest{
 		ID:          uuid.New().String(),
 		UserID:      userID,
@@ -96,13 +92,33 @@ func (s *service) RequestDataDownload(ctx context.Context, userID string) error
 		return fmt.Errorf("failed to create data download request: %w", err)
 	}
 
-	// TODO: Queue background job to process the request
-	// TODO: Send confirmation email to user
-	fmt.Printf("Data download requested for user: %s (Request ID: %s)\n", userID, request.ID)
+	_, err = s.queue.Enqueue(ctx, JobCompileDataExport, DataDownloadJob{
+		RequestID: request.ID,
+		UserID:    userID,
+	}, &jobs.EnqueueOptions{Queue: JobQueue})
+	if err != nil {
+		return fmt.Errorf("failed to queue data download request: %w", err)
+	}
 
 	return nil
 }
 
+// ProcessDataDownload compiles the export for a queued download request
+func (s *service) ProcessDataDownload(ctx context.Context, job DataDownloadJob) error {
+	if err := s.store.UpdateDataRequestStatus(ctx, job.RequestID, StatusProcessing, nil); err != nil {
+		return err
+	}
+
+	export, err := s.ExportUserData(ctx, job.UserID)
+	if err != nil {
+		return err
+	}
+
+	// TODO: Store the export, email the user a download link and schedule its deletion after 30 days
+	notes := fmt.Sprintf("Export compiled at %s", export.ExportDate.UTC().Format(time.RFC3339))
+	return s.store.UpdateDataRequestStatus(ctx, job.RequestID, StatusCompleted, &notes)
+}
+
 // RequestAccountDeletion handles account deletion requests
 func (s *service) RequestAccountDeletion(ctx context.Context, userID, reason string) error {
 	request := &DataRequest{
@@ -170,3 +186,45 @@ func (s *service) ExportUserData(ctx context.Context, userID string) (*DataExpor
 func (s *service) GetDataRequests(ctx context.Context, userID string) ([]DataRequest, error) {
 	return s.store.GetDataRequestsByUser(ctx, userID)
 }
+
+// GetDataRequest retrieves a single data request
+func (s *service) GetDataRequest(ctx context.Context, requestID string) (*DataRequest, error) {
+	return s.store.GetDataRequest(ctx, requestID)
+}
+
+// ListDataRequests retrieves every user's data requests, optionally with one status, oldest first
+func (s *service) ListDataRequests(ctx context.Context, status string) ([]DataRequest, error) {
+	if status != "" && !isValidRequestStatus(status) {
+		return nil, fmt.Errorf("invalid status: %s", status)
+	}
+	return s.store.ListDataRequests(ctx, status)
+}
+
+// ResolveDataRequest closes an open data request as completed or rejected
+func (s *service) ResolveDataRequest(ctx context.Context, requestID, status string, processedBy, notes *string) error {
+	if status != StatusCompleted && status != StatusRejected {
+		return fmt.Errorf("a data request can only be resolved as %s or %s", StatusCompleted, StatusRejected)
+	}
+
+	request, err := s.store.GetDataRequest(ctx, requestID)
+	if err != nil {
+		return err
+	}
+
+	if request.Status == StatusCompleted || request.Status == StatusRejected {
+		return fmt.Errorf("data request is already %s", request.Status)
+	}
+
+	return s.store.ResolveDataRequest(ctx, requestID, status, processedBy, notes)
+}
+
+// Helper functions
+
+func isValidRequestStatus(status string) bool {
+	switch status {
+	case StatusPending, StatusProcessing, StatusCompleted, StatusRejected:
+		return true
+	default:
+		return false
+	}
+}
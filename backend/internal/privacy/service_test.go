@@ -0,0 +1,135 @@
+package privacy
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/jobs"
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+)
+
+const parent = "7d0c4a4e-2f7b-4b8e-9a51-3f6c1d2e8a10"
+
+func newTestService(t *testing.T) (Service, jobs.Service) {
+	t.Helper()
+	db := memdb.New()
+	now := time.Now()
+	phone := "07700 900000"
+	user := memdb.User{ID: parent, FullName: "Sam Parent", Email: "parent@example.com", Role: "service_user", IsActive: true, CreatedAt: now, UpdatedAt: now}
+	if err := db.InsertUser(user, memdb.Profile{PhoneNumber: &phone}); err != nil {
+		t.Fatal(err)
+	}
+
+	queue := jobs.NewService(jobs.NewMemoryStore())
+	return NewService(NewMemoryStore(db), queue), queue
+}
+
+func TestPrivacyPreferencesStartFromDefaults(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t)
+
+	preferences, err := svc.GetPrivacyPreferences(ctx, parent)
+	if err != nil {
+		t.Fatalf("GetPrivacyPreferences() error = %v", err)
+	}
+	if !preferences.DataTrackingEnabled || preferences.DataSharingEnabled || preferences.MarketingEmailsEnabled {
+		t.Errorf("default preferences = %+v, want tracking on and sharing and marketing off", preferences)
+	}
+
+	// Updating before the preferences exist creates them first, then changes only what was sent
+	other := "0b8e6f3a-5c2d-4e1f-8a7b-9c0d1e2f3a4b"
+	sharing := true
+	if err := svc.UpdatePrivacyPreferences(ctx, other, &UpdatePrivacyPreferencesRequest{DataSharingEnabled: &sharing}); err != nil {
+		t.Fatalf("UpdatePrivacyPreferences() error = %v", err)
+	}
+	updated, _ := svc.GetPrivacyPreferences(ctx, other)
+	if !updated.DataSharingEnabled || !updated.DataTrackingEnabled || updated.MarketingEmailsEnabled {
+		t.Errorf("updated preferences = %+v, want sharing on and the other defaults kept", updated)
+	}
+
+	if err := svc.UpdatePrivacyPreferences(ctx, parent, &UpdatePrivacyPreferencesRequest{}); err == nil {
+		t.Error("UpdatePrivacyPreferences() with nothing to change succeeded")
+	}
+}
+
+func TestDataDownloadIsQueuedAndCompiled(t *testing.T) {
+	ctx := context.Background()
+	svc, queue := newTestService(t)
+
+	if err := svc.RequestDataDownload(ctx, parent); err != nil {
+		t.Fatalf("RequestDataDownload() error = %v", err)
+	}
+
+	requests, _ := svc.GetDataRequests(ctx, parent)
+	if len(requests) != 1 || requests[0].Status != StatusPending || requests[0].RequestType != RequestTypeDataDownload {
+		t.Fatalf("data requests = %+v, want one pending download", requests)
+	}
+
+	queued, err := queue.ListJobs(ctx, &jobs.ListJobsRequest{Queue: JobQueue, JobType: JobCompileDataExport, Page: 1, PageSize: 10})
+	if err != nil || len(queued.Jobs) != 1 {
+		t.Fatalf("ListJobs() = (%v, %v), want one export job", queued, err)
+	}
+	if payload := string(queued.Jobs[0].Payload); !strings.Contains(payload, requests[0].ID) || !strings.Contains(payload, parent) {
+		t.Errorf("job payload = %s, want the request and user IDs", payload)
+	}
+
+	if err := svc.ProcessDataDownload(ctx, DataDownloadJob{RequestID: requests[0].ID, UserID: parent}); err != nil {
+		t.Fatalf("ProcessDataDownload() error = %v", err)
+	}
+	processed, _ := svc.GetDataRequest(ctx, requests[0].ID)
+	if processed.Status != StatusCompleted || processed.Notes == nil {
+		t.Errorf("processed request = %+v, want completed with notes", processed)
+	}
+}
+
+func TestExportUserData(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t)
+
+	export, err := svc.ExportUserData(ctx, parent)
+	if err != nil {
+		t.Fatalf("ExportUserData() error = %v", err)
+	}
+	if user := export.UserData.(map[string]interface{}); user["email"] != "parent@example.com" {
+		t.Errorf("exported user = %v", user)
+	}
+	if profile := export.ProfileData.(map[string]interface{}); profile["phone_number"] != "07700 900000" {
+		t.Errorf("exported profile = %v", profile)
+	}
+
+	if _, err := svc.ExportUserData(ctx, "6a1f0e2d-0000-4000-8000-000000000000"); err == nil {
+		t.Error("ExportUserData() for an unknown user succeeded")
+	}
+}
+
+func TestResolveDataRequest(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t)
+
+	if err := svc.RequestAccountDeletion(ctx, parent, "moving abroad"); err != nil {
+		t.Fatalf("RequestAccountDeletion() error = %v", err)
+	}
+	open, _ := svc.ListDataRequests(ctx, StatusPending)
+	if len(open) != 1 || open[0].RequestType != RequestTypeAccountDeletion {
+		t.Fatalf("pending requests = %+v, want the deletion request", open)
+	}
+	requestID := open[0].ID
+
+	if err := svc.ResolveDataRequest(ctx, requestID, StatusProcessing, nil, nil); err == nil {
+		t.Error("ResolveDataRequest() accepted a status that doesn't close the request")
+	}
+
+	operator := "operator"
+	if err := svc.ResolveDataRequest(ctx, requestID, StatusRejected, &operator, nil); err != nil {
+		t.Fatalf("ResolveDataRequest() error = %v", err)
+	}
+	if err := svc.ResolveDataRequest(ctx, requestID, StatusCompleted, &operator, nil); err == nil {
+		t.Error("ResolveDataRequest() reopened a resolved request")
+	}
+
+	if _, err := svc.ListDataRequests(ctx, "archived"); err == nil {
+		t.Error("ListDataRequests() accepted an unknown status")
+	}
+}
@@ -9,15 +9,18 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/perinatal-mental-health-app/backend/internal/encryption"
 )
 
 type store struct {
-	db *pgxpool.Pool
+	db     *pgxpool.Pool
+	cipher encryption.Cipher
 }
 
-func NewStore(db *pgxpool.Pool) Store {
+func NewStore(db *pgxpool.Pool, cipher encryption.Cipher) Store {
 	return &store{
-		db: db,
+		db:     db,
+		cipher: cipher,
 	}
 }
 
@@ -198,8 +201,7 @@ func (s *store) GetUserProfileData(ctx context.Context, userID string) (map[stri
 	`
 
 	var profileUserID string
-	var phoneNumber, address, emergencyContact, preferences *string
-	var dateOfBirth *time.Time
+	var phoneNumber, dateOfBirth, address, emergencyContact, preferences *string
 	var createdAt, updatedAt time.Time
 
 	err := s.db.QueryRow(ctx, query, userID).Scan(
@@ -226,17 +228,22 @@ func (s *store) GetUserProfileData(ctx context.Context, userID string) (map[stri
 		"updated_at": updatedAt,
 	}
 
-	if phoneNumber != nil {
-		profile["phone_number"] = *phoneNumber
+	// Sensitive profile fields are sealed at rest
+	sealed := map[string]*string{
+		"phone_number":      phoneNumber,
+		"date_of_birth":     dateOfBirth,
+		"address":           address,
+		"emergency_contact": emergencyContact,
 	}
-	if dateOfBirth != nil {
-		profile["date_of_birth"] = *dateOfBirth
-	}
-	if address != nil {
-		profile["address"] = *address
-	}
-	if emergencyContact != nil {
-		profile["emergency_contact"] = *emergencyContact
+	for field, value := range sealed {
+		if value == nil {
+			continue
+		}
+		plain, err := s.cipher.Decrypt(ctx, *value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt %s: %w", field, err)
+		}
+		profile[field] = plain
 	}
 	if preferences != nil {
 		profile["preferences"] = *preferences
@@ -334,3 +341,98 @@ func (s *store) UpdateDataRequestStatus(ctx context.Context, requestID, status s
 
 	return nil
 }
+
+// GetDataRequest retrieves a data request by ID
+func (s *store) GetDataRequest(ctx context.Context, requestID string) (*DataRequest, error) {
+	query := `
+		SELECT id, user_id, request_type, status, reason, requested_at, processed_at, 
+			   processed_by, notes, created_at, updated_at
+		FROM data_requests
+		WHERE id = $1
+	`
+
+	request, err := scanDataRequest(s.db.QueryRow(ctx, query, requestID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("data request not found")
+		}
+		return nil, fmt.Errorf("failed to get data request: %w", err)
+	}
+
+	return request, nil
+}
+
+// ListDataRequests retrieves all data requests, optionally filtered by status, oldest first
+func (s *store) ListDataRequests(ctx context.Context, status string) ([]DataRequest, error) {
+	query := `
+		SELECT id, user_id, request_type, status, reason, requested_at, processed_at, 
+			   processed_by, notes, created_at, updated_at
+		FROM data_requests
+		WHERE $1 = '' OR status = $1
+		ORDER BY requested_at ASC
+	`
+
+	rows, err := s.db.Query(ctx, query, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list data requests: %w", err)
+	}
+	defer rows.Close()
+
+	var requests []DataRequest
+	for rows.Next() {
+		request, err := scanDataRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan data request: %w", err)
+		}
+		requests = append(requests, *request)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return requests, nil
+}
+
+// ResolveDataRequest sets the final status of a data request and who processed it
+func (s *store) ResolveDataRequest(ctx context.Context, requestID, status string, processedBy, notes *string) error {
+	query := `
+		UPDATE data_requests 
+		SET status = $1, processed_by = $2, notes = $3, processed_at = $4, updated_at = $4
+		WHERE id = $5
+	`
+
+	result, err := s.db.Exec(ctx, query, status, processedBy, notes, time.Now(), requestID)
+	if err != nil {
+		return fmt.Errorf("failed to resolve data request: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("data request not found")
+	}
+
+	return nil
+}
+
+// Helper functions
+
+func scanDataRequest(row pgx.Row) (*DataRequest, error) {
+	var request DataRequest
+	err := row.Scan(
+		&request.ID,
+		&request.UserID,
+		&request.RequestType,
+		&request.Status,
+		&request.Reason,
+		&request.RequestedAt,
+		&request.ProcessedAt,
+		&request.ProcessedBy,
+		&request.Notes,
+		&request.CreatedAt,
+		&request.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
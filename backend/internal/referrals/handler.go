@@ -1,10 +1,14 @@
 package referrals
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/careteam"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
 )
 
 type handler struct {
@@ -36,7 +40,11 @@ func (h *handler) CreateReferral(c echo.Context) error {
 
 	referral, err := h.service.CreateReferral(c.Request().Context(), userID, &req)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{
+		status := http.StatusBadRequest
+		if errors.Is(err, careteam.ErrNoAccess) {
+			status = http.StatusForbidden
+		}
+		return c.JSON(status, map[string]string{
 			"error": err.Error(),
 		})
 	}
@@ -81,6 +89,7 @@ func (h *handler) ListSentReferrals(c echo.Context) error {
 		Status:       c.QueryParam("status"),
 		ReferralType: c.QueryParam("referral_type"),
 		IsUrgent:     isUrgent,
+		Params:       pagination.ParseQuery(c),
 	}
 
 	referrals, err := h.service.ListReferralsSent(c.Request().Context(), userID, req)
@@ -130,6 +139,7 @@ func (h *handler) ListReceivedReferrals(c echo.Context) error {
 		Status:       c.QueryParam("status"),
 		ReferralType: c.QueryParam("referral_type"),
 		IsUrgent:     isUrgent,
+		Params:       pagination.ParseQuery(c),
 	}
 
 	referrals, err := h.service.ListReferralsReceived(c.Request().Context(), userID, req)
@@ -296,7 +306,7 @@ func (h *handler) SearchUsers(c echo.Context) error {
 		Limit: limit,
 	}
 
-	users, err := h.service.SearchUsers(c.Request().Context(), req)
+	users, err := h.service.SearchUsers(c.Request().Context(), userID, req)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": err.Error(),
@@ -325,6 +335,18 @@ func (h *handler) GetReferralStats(c echo.Context) error {
 	return c.JSON(http.StatusOK, stats)
 }
 
+// GetOrganisationReferralStats retrieves referral statistics for the caller's organisations (admin only)
+func (h *handler) GetOrganisationReferralStats(c echo.Context) error {
+	stats, err := h.service.GetOrganisationReferralStats(c.Request().Context(), organisations.ScopeFromContext(c))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, stats)
+}
+
 // GetReferralsByItem gets referrals for a specific item
 func (h *handler) GetReferralsByItem(c echo.Context) error {
 	userID := getUserIDFromContext(c)
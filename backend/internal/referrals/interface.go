@@ -3,6 +3,8 @@ package referrals
 import (
 	"context"
 	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/events"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
 )
 
 // Service defines the interface for referrals business logic
@@ -13,10 +15,16 @@ type Service interface {
 	GetReferral(ctx context.Context, referralID string, userID string) (*Referral, error)
 	UpdateReferral(ctx context.Context, referralID string, userID string, req *UpdateReferralRequest) (*Referral, error)
 	UpdateReferralStatus(ctx context.Context, referralID string, userID string, status string) error
-	SearchUsers(ctx context.Context, req *UserSearchRequest) (*UserSearchResponse, error)
+	SearchUsers(ctx context.Context, searcherID string, req *UserSearchRequest) (*UserSearchResponse, error)
 	GetReferralStats(ctx context.Context, userID string) (*ReferralStats, error)
+	GetOrganisationReferralStats(ctx context.Context, scope organisations.Scope) (*ReferralStats, error)
 	GetReferralsByItem(ctx context.Context, itemID string, itemType string, userID string) ([]Referral, error)
+	// ListOutstandingReferrals returns the pending and viewed referrals sent to
+	// any of the given users, urgent ones first
+	ListOutstandingReferrals(ctx context.Context, referredTo []string) ([]Referral, error)
 	DeleteReferral(ctx context.Context, referralID string, userID string) error
+	// OnUserDeactivated is the events.UserDeactivated subscriber
+	OnUserDeactivated(ctx context.Context, event events.UserDeactivatedPayload) error
 }
 
 // Store defines the interface for referrals data persistence
@@ -30,8 +38,12 @@ type Store interface {
 	UpdateReferralStatus(ctx context.Context, referralID string, status string) error
 	DeleteReferral(ctx context.Context, referralID string) error
 	SearchUsers(ctx context.Context, req *UserSearchRequest) (*UserSearchResponse, error)
-	GetReferralStats(ctx context.Context, userID string) (*ReferralStats, error)
+	GetReferralStats(ctx context.Context, filter *ReferralStatsFilter) (*ReferralStats, error)
 	GetReferralsByItem(ctx context.Context, itemID string, itemType string) ([]Referral, error)
+	ListOutstandingReferrals(ctx context.Context, referredTo []string) ([]Referral, error)
+	// CancelOutstandingReferrals cancels the pending and viewed referrals sent to
+	// a user, returning how many were cancelled
+	CancelOutstandingReferrals(ctx context.Context, referredTo string) (int, error)
 	CheckDuplicateReferral(ctx context.Context, referredBy, referredTo, itemID, itemType string) (bool, error)
 
 	// Validation helpers
@@ -52,5 +64,6 @@ type Handler interface {
 	DeleteReferral(c echo.Context) error
 	SearchUsers(c echo.Context) error
 	GetReferralStats(c echo.Context) error
+	GetOrganisationReferralStats(c echo.Context) error
 	GetReferralsByItem(c echo.Context) error
 }
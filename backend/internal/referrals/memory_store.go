@@ -0,0 +1,485 @@
+package referrals
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
+)
+
+// memoryStore keeps referrals in memory for tests and demo mode. Names and item
+// titles are read from the shared rows. Staff organisation membership is not
+// modelled, so a scope only matches referrals to its organisations' items. It
+// does not publish domain events.
+type memoryStore struct {
+	db        *memdb.DB
+	mu        sync.RWMutex
+	referrals map[string]Referral
+}
+
+func NewMemoryStore(db *memdb.DB) Store {
+	return &memoryStore{
+		db:        db,
+		referrals: make(map[string]Referral),
+	}
+}
+
+// CreateReferral creates a new referral
+func (s *memoryStore) CreateReferral(ctx context.Context, referral *Referral) (*Referral, error) {
+	s.mu.Lock()
+	if _, exists := s.referrals[referral.ID]; exists {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("failed to create referral: duplicate id %s", referral.ID)
+	}
+	s.referrals[referral.ID] = *referral
+	s.mu.Unlock()
+
+	return s.GetReferralWithDetails(ctx, referral.ID)
+}
+
+// GetReferralByID retrieves a referral by ID
+func (s *memoryStore) GetReferralByID(ctx context.Context, referralID string) (*Referral, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	referral, ok := s.referrals[referralID]
+	if !ok {
+		return nil, fmt.Errorf("referral not found")
+	}
+
+	return &referral, nil
+}
+
+// GetReferralWithDetails retrieves a referral with user and item details
+func (s *memoryStore) GetReferralWithDetails(ctx context.Context, referralID string) (*Referral, error) {
+	referral, err := s.GetReferralByID(ctx, referralID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.withDetails(referral) {
+		return nil, fmt.Errorf("referral not found")
+	}
+
+	return referral, nil
+}
+
+// ListReferralsSent retrieves sent referrals for a user
+func (s *memoryStore) ListReferralsSent(ctx context.Context, referredBy string, req *ListReferralsRequest) (*ListReferralsResponse, error) {
+	return s.list(req, func(referral Referral) bool {
+		return referral.ReferredBy == referredBy
+	})
+}
+
+// ListReferralsReceived retrieves received referrals for a user
+func (s *memoryStore) ListReferralsReceived(ctx context.Context, referredTo string, req *ListReferralsRequest) (*ListReferralsResponse, error) {
+	return s.list(req, func(referral Referral) bool {
+		return referral.ReferredTo == referredTo
+	})
+}
+
+// UpdateReferral updates a referral
+func (s *memoryStore) UpdateReferral(ctx context.Context, referralID string, req *UpdateReferralRequest) (*Referral, error) {
+	var metadata *string
+	if req.Metadata != nil {
+		metadataJSON, err := json.Marshal(req.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+		}
+		metadataStr := string(metadataJSON)
+		metadata = &metadataStr
+	}
+
+	err := s.update(referralID, func(referral *Referral) {
+		if req.Status != nil {
+			referral.Status = *req.Status
+		}
+		if req.Reason != nil {
+			referral.Reason = *req.Reason
+		}
+		if req.IsUrgent != nil {
+			referral.IsUrgent = *req.IsUrgent
+		}
+		if metadata != nil {
+			referral.Metadata = metadata
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetReferralWithDetails(ctx, referralID)
+}
+
+// UpdateReferralStatus updates only the status of a referral
+func (s *memoryStore) UpdateReferralStatus(ctx context.Context, referralID string, status string) error {
+	return s.update(referralID, func(referral *Referral) {
+		referral.Status = status
+	})
+}
+
+// DeleteReferral deletes a referral
+func (s *memoryStore) DeleteReferral(ctx context.Context, referralID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.referrals[referralID]; !ok {
+		return fmt.Errorf("referral not found")
+	}
+	delete(s.referrals, referralID)
+
+	return nil
+}
+
+// SearchUsers searches active users by name, email or exact phone number
+func (s *memoryStore) SearchUsers(ctx context.Context, req *UserSearchRequest) (*UserSearchResponse, error) {
+	query := strings.ToLower(req.Query)
+
+	var users []UserSearchResult
+	for _, user := range s.db.Users() {
+		if !user.IsActive || user.IsGuest || (req.Role != "" && user.Role != req.Role) {
+			continue
+		}
+		if req.ServiceUserIDs != nil && user.Role == "service_user" && !slices.Contains(req.ServiceUserIDs, user.ID) {
+			continue
+		}
+
+		profile, _ := s.db.Profile(user.ID)
+		phoneMatch := profile.PhoneNumber != nil && *profile.PhoneNumber == req.Query
+		if !strings.Contains(strings.ToLower(user.FullName), query) &&
+			!strings.Contains(strings.ToLower(user.Email), query) && !phoneMatch {
+			continue
+		}
+
+		users = append(users, UserSearchResult{
+			ID:          user.ID,
+			FullName:    user.FullName,
+			Email:       user.Email,
+			Role:        user.Role,
+			PhoneNumber: profile.PhoneNumber,
+			CreatedAt:   user.CreatedAt,
+		})
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].FullName < users[j].FullName
+	})
+
+	total := int64(len(users))
+	if len(users) > req.Limit {
+		users = users[:req.Limit]
+	}
+
+	return &UserSearchResponse{
+		Users: users,
+		Total: total,
+		Query: req.Query,
+	}, nil
+}
+
+// GetReferralStats retrieves statistics for the referrals selected by the filter
+func (s *memoryStore) GetReferralStats(ctx context.Context, filter *ReferralStatsFilter) (*ReferralStats, error) {
+	stats := &ReferralStats{
+		ReferralsByType:   make(map[string]int64),
+		ReferralsByStatus: make(map[string]int64),
+	}
+
+	referrals := s.filter(func(referral Referral) bool {
+		if filter.ReferredBy != "" && referral.ReferredBy != filter.ReferredBy {
+			return false
+		}
+		if filter.Scope == nil || filter.Scope.All {
+			return true
+		}
+		if referral.ReferralType != string(TypeService) && referral.ReferralType != string(TypeSupportGroup) {
+			return false
+		}
+		item, ok := s.db.Item(referral.ReferralType, referral.ItemID)
+		return ok && item.OrganisationID != nil && filter.Scope.Covers(item.OrganisationID)
+	})
+
+	referrers := make(map[string]*ReferrerStats)
+	for _, referral := range referrals {
+		stats.TotalReferrals++
+		switch ReferralStatus(referral.Status) {
+		case StatusPending:
+			stats.PendingReferrals++
+		case StatusAccepted:
+			stats.AcceptedReferrals++
+		case StatusDeclined:
+			stats.DeclinedReferrals++
+		}
+		if referral.IsUrgent {
+			stats.UrgentReferrals++
+		}
+		stats.ReferralsByType[referral.ReferralType]++
+		stats.ReferralsByStatus[referral.Status]++
+
+		referrer, ok := referrers[referral.ReferredBy]
+		if !ok {
+			referrer = &ReferrerStats{ReferrerID: referral.ReferredBy}
+			if referral.ReferrerName != nil {
+				referrer.ReferrerName = *referral.ReferrerName
+			}
+			referrers[referral.ReferredBy] = referrer
+		}
+		referrer.TotalSent++
+		switch ReferralStatus(referral.Status) {
+		case StatusAccepted:
+			referrer.Accepted++
+		case StatusDeclined:
+			referrer.Declined++
+		case StatusPending:
+			referrer.Pending++
+		}
+	}
+
+	if stats.TotalReferrals > 0 {
+		stats.AcceptanceRate = float64(stats.AcceptedReferrals) / float64(stats.TotalReferrals) * 100
+	}
+
+	stats.RecentReferrals = referrals
+	if len(stats.RecentReferrals) > 5 {
+		stats.RecentReferrals = stats.RecentReferrals[:5]
+	}
+
+	// Organisation views also rank the staff sending the referrals
+	if filter.Scope != nil {
+		for _, referrer := range referrers {
+			stats.TopReferrers = append(stats.TopReferrers, *referrer)
+		}
+		sort.Slice(stats.TopReferrers, func(i, j int) bool {
+			if stats.TopReferrers[i].TotalSent != stats.TopReferrers[j].TotalSent {
+				return stats.TopReferrers[i].TotalSent > stats.TopReferrers[j].TotalSent
+			}
+			return stats.TopReferrers[i].ReferrerID < stats.TopReferrers[j].ReferrerID
+		})
+		if len(stats.TopReferrers) > 5 {
+			stats.TopReferrers = stats.TopReferrers[:5]
+		}
+	}
+
+	return stats, nil
+}
+
+// GetReferralsByItem gets all referrals for a specific item
+func (s *memoryStore) GetReferralsByItem(ctx context.Context, itemID string, itemType string) ([]Referral, error) {
+	return s.filter(func(referral Referral) bool {
+		return referral.ItemID == itemID && referral.ReferralType == itemType
+	}), nil
+}
+
+// ListOutstandingReferrals gets pending and viewed referrals sent to any of the users
+func (s *memoryStore) ListOutstandingReferrals(ctx context.Context, referredTo []string) ([]Referral, error) {
+	referrals := s.filter(func(referral Referral) bool {
+		return slices.Contains(referredTo, referral.ReferredTo) &&
+			(referral.Status == string(StatusPending) || referral.Status == string(StatusViewed))
+	})
+	sort.SliceStable(referrals, func(i, j int) bool {
+		return referrals[i].IsUrgent && !referrals[j].IsUrgent
+	})
+
+	return referrals, nil
+}
+
+// CancelOutstandingReferrals cancels the pending and viewed referrals sent to a user
+func (s *memoryStore) CancelOutstandingReferrals(ctx context.Context, referredTo string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cancelled := 0
+	now := time.Now()
+	for id, referral := range s.referrals {
+		if referral.ReferredTo == referredTo &&
+			(referral.Status == string(StatusPending) || referral.Status == string(StatusViewed)) {
+			referral.Status = string(StatusCancelled)
+			referral.UpdatedAt = now
+			s.referrals[id] = referral
+			cancelled++
+		}
+	}
+
+	return cancelled, nil
+}
+
+// CheckDuplicateReferral checks if a similar referral was made in the last 30 days
+func (s *memoryStore) CheckDuplicateReferral(ctx context.Context, referredBy, referredTo, itemID, itemType string) (bool, error) {
+	since := time.Now().AddDate(0, 0, -30)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, referral := range s.referrals {
+		if referral.ReferredBy == referredBy && referral.ReferredTo == referredTo &&
+			referral.ItemID == itemID && referral.ReferralType == itemType &&
+			referral.Status != string(StatusDeclined) && referral.CreatedAt.After(since) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ValidateUserExists checks if a user exists and is active
+func (s *memoryStore) ValidateUserExists(ctx context.Context, userID string) error {
+	if user, ok := s.db.User(userID); !ok || !user.IsActive {
+		return fmt.Errorf("user not found or inactive")
+	}
+	return nil
+}
+
+// ValidateUserCanReceiveReferrals checks if user can receive referrals (service_user role)
+func (s *memoryStore) ValidateUserCanReceiveReferrals(ctx context.Context, userID string) error {
+	user, ok := s.db.User(userID)
+	if !ok || !user.IsActive || user.IsGuest {
+		return fmt.Errorf("user not found")
+	}
+
+	if user.Role != "service_user" {
+		return fmt.Errorf("user cannot receive referrals (role: %s)", user.Role)
+	}
+
+	return nil
+}
+
+// ValidateUserCanMakeReferrals checks if user can make referrals (professional/nhs_staff roles)
+func (s *memoryStore) ValidateUserCanMakeReferrals(ctx context.Context, userID string) error {
+	user, ok := s.db.User(userID)
+	if !ok || !user.IsActive {
+		return fmt.Errorf("user not found")
+	}
+
+	if user.Role != "professional" && user.Role != "nhs_staff" {
+		return fmt.Errorf("user cannot make referrals (role: %s)", user.Role)
+	}
+
+	return nil
+}
+
+// ValidateItemExists checks that the referred item exists and is active
+func (s *memoryStore) ValidateItemExists(ctx context.Context, itemID string, itemType string) error {
+	switch itemType {
+	case memdb.ItemService, memdb.ItemResource, memdb.ItemSupportGroup:
+	default:
+		return fmt.Errorf("invalid referral type: %s", itemType)
+	}
+
+	if item, ok := s.db.Item(itemType, itemID); !ok || !item.IsActive {
+		return fmt.Errorf("%s not found or inactive", itemType)
+	}
+
+	return nil
+}
+
+// Helper functions
+
+// list pages through the matching referrals, newest first
+func (s *memoryStore) list(req *ListReferralsRequest, match func(Referral) bool) (*ListReferralsResponse, error) {
+	cursor, err := req.DecodeCursor()
+	if err != nil {
+		return nil, err
+	}
+
+	offset := 0
+	if cursor == nil {
+		offset = (req.Page - 1) * req.PageSize
+	}
+
+	referrals := s.filter(func(referral Referral) bool {
+		if !match(referral) {
+			return false
+		}
+		if req.Status != "" && referral.Status != req.Status {
+			return false
+		}
+		if req.ReferralType != "" && referral.ReferralType != req.ReferralType {
+			return false
+		}
+		return req.IsUrgent == nil || referral.IsUrgent == *req.IsUrgent
+	})
+
+	response := &ListReferralsResponse{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	if req.WantTotal() {
+		total := int64(len(referrals))
+		totalPages := pagination.TotalPages(total, req.PageSize)
+		response.Total = &total
+		response.TotalPages = &totalPages
+	}
+
+	window := pagination.Window(referrals, cursor, offset, req.PageSize, referralCursor)
+	response.Referrals, response.NextCursor, response.PrevCursor = pagination.Paginate(window, req.PageSize, cursor, offset, referralCursor)
+
+	return response, nil
+}
+
+// filter returns the matching referrals with details, newest first. Referrals
+// whose sender or recipient is missing are skipped, as the inner joins would.
+func (s *memoryStore) filter(match func(Referral) bool) []Referral {
+	s.mu.RLock()
+	var referrals []Referral
+	for _, referral := range s.referrals {
+		referrals = append(referrals, referral)
+	}
+	s.mu.RUnlock()
+
+	var matched []Referral
+	for _, referral := range referrals {
+		if s.withDetails(&referral) && match(referral) {
+			matched = append(matched, referral)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return pagination.Compare(referralCursor(matched[i]), referralCursor(matched[j])) > 0
+	})
+	return matched
+}
+
+// withDetails fills in the joined names and item details, reporting false when
+// either user is missing
+func (s *memoryStore) withDetails(referral *Referral) bool {
+	referrer, ok := s.db.User(referral.ReferredBy)
+	if !ok {
+		return false
+	}
+	recipient, ok := s.db.User(referral.ReferredTo)
+	if !ok {
+		return false
+	}
+
+	title, description := "Unknown Item", ""
+	if item, ok := s.db.Item(referral.ReferralType, referral.ItemID); ok {
+		title, description = item.Title, item.Description
+	}
+
+	referral.ReferrerName = &referrer.FullName
+	referral.RecipientName = &recipient.FullName
+	referral.ItemTitle = &title
+	referral.ItemDescription = &description
+	return true
+}
+
+// update applies fn to a referral
+func (s *memoryStore) update(referralID string, fn func(*Referral)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	referral, ok := s.referrals[referralID]
+	if !ok {
+		return fmt.Errorf("referral not found")
+	}
+	fn(&referral)
+	referral.UpdatedAt = time.Now()
+	s.referrals[referralID] = referral
+
+	return nil
+}
@@ -2,6 +2,9 @@ package referrals
 
 import (
 	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
 )
 
 // Referral represents a referral in the system
@@ -11,8 +14,8 @@ type Referral struct {
 	ReferredTo   string    `json:"referred_to" db:"referred_to"`     // Parent user ID
 	ReferralType string    `json:"referral_type" db:"referral_type"` // 'service', 'resource', 'support_group'
 	ItemID       string    `json:"item_id" db:"item_id"`             // ID of the service/resource/support group
-	Reason       string    `json:"reason" db:"reason"`
-	Status       string    `json:"status" db:"status"` // 'pending', 'accepted', 'declined', 'viewed'
+	Reason       string    `json:"reason" db:"reason" encrypt:"true"`
+	Status       string    `json:"status" db:"status"` // 'pending', 'accepted', 'declined', 'viewed', 'cancelled'
 	IsUrgent     bool      `json:"is_urgent" db:"is_urgent"`
 	Metadata     *string   `json:"metadata,omitempty" db:"metadata"` // JSON string for additional data
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
@@ -33,6 +36,8 @@ const (
 	StatusAccepted ReferralStatus = "accepted"
 	StatusDeclined ReferralStatus = "declined"
 	StatusViewed   ReferralStatus = "viewed"
+	// StatusCancelled is set when the recipient's account is deactivated
+	StatusCancelled ReferralStatus = "cancelled"
 )
 
 // ReferralType represents valid referral types
@@ -69,15 +74,18 @@ type ListReferralsRequest struct {
 	Status       string `json:"status,omitempty"`
 	ReferralType string `json:"referral_type,omitempty"`
 	IsUrgent     *bool  `json:"is_urgent,omitempty"`
+	pagination.Params
 }
 
 // ListReferralsResponse represents the response for listing referrals
 type ListReferralsResponse struct {
 	Referrals  []Referral `json:"referrals"`
-	Total      int64      `json:"total"`
+	Total      *int64     `json:"total,omitempty"`
 	Page       int        `json:"page"`
 	PageSize   int        `json:"page_size"`
-	TotalPages int        `json:"total_pages"`
+	TotalPages *int       `json:"total_pages,omitempty"`
+	NextCursor *string    `json:"next_cursor,omitempty"`
+	PrevCursor *string    `json:"prev_cursor,omitempty"`
 }
 
 // UserSearchRequest represents the request for searching users
@@ -85,6 +93,10 @@ type UserSearchRequest struct {
 	Query string `json:"query" validate:"required,min=3"`
 	Role  string `json:"role,omitempty"`
 	Limit int    `json:"limit" validate:"min=1,max=50"`
+
+	// ServiceUserIDs, when not nil, limits the service users found to these IDs.
+	// Users with other roles are unaffected.
+	ServiceUserIDs []string `json:"-"`
 }
 
 // UserSearchResult represents a user in search results
@@ -93,7 +105,7 @@ type UserSearchResult struct {
 	FullName    string    `json:"full_name" db:"full_name"`
 	Email       string    `json:"email" db:"email"`
 	Role        string    `json:"role" db:"role"`
-	PhoneNumber *string   `json:"phone_number,omitempty" db:"phone_number"`
+	PhoneNumber *string   `json:"phone_number,omitempty" db:"phone_number" encrypt:"true"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 }
 
@@ -119,6 +131,12 @@ type ReferralStats struct {
 	AverageResponseTime float64          `json:"average_response_time_hours"`
 }
 
+// ReferralStatsFilter selects the referrals counted in ReferralStats
+type ReferralStatsFilter struct {
+	ReferredBy string               // Only referrals sent by this user
+	Scope      *organisations.Scope // Only referrals involving these organisations
+}
+
 // ReferrerStats represents statistics for individual referrers
 type ReferrerStats struct {
 	ReferrerID   string `json:"referrer_id" db:"referrer_id"`
@@ -140,6 +158,8 @@ func (r *Referral) GetDisplayStatus() string {
 		return "Declined"
 	case StatusViewed:
 		return "Viewed"
+	case StatusCancelled:
+		return "Cancelled"
 	default:
 		return r.Status
 	}
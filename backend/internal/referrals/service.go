@@ -8,15 +8,22 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/perinatal-mental-health-app/backend/internal/careteam"
+	"github.com/perinatal-mental-health-app/backend/internal/events"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"go.uber.org/zap"
 )
 
 type service struct {
-	store Store
+	store    Store
+	careTeam careteam.Service
 }
 
-func NewService(store Store) Service {
+func NewService(store Store, careTeam careteam.Service) Service {
 	return &service{
-		store: store,
+		store:    store,
+		careTeam: careTeam,
 	}
 }
 
@@ -36,6 +43,11 @@ func (s *service) CreateReferral(ctx context.Context, referredBy string, req *Cr
 		return nil, fmt.Errorf("recipient cannot receive referrals: %w", err)
 	}
 
+	// Only service users in the referrer's care team (or under break-glass access) can be referred
+	if _, err := s.careTeam.CheckAccess(ctx, referredBy, req.ReferredTo); err != nil {
+		return nil, err
+	}
+
 	// Validate item exists
 	if err := s.store.ValidateItemExists(ctx, req.ItemID, req.ReferralType); err != nil {
 		return nil, fmt.Errorf("item validation failed: %w", err)
@@ -215,7 +227,7 @@ func (s *service) UpdateReferralStatus(ctx context.Context, referralID string, u
 }
 
 // SearchUsers searches for users that can receive referrals
-func (s *service) SearchUsers(ctx context.Context, req *UserSearchRequest) (*UserSearchResponse, error) {
+func (s *service) SearchUsers(ctx context.Context, searcherID string, req *UserSearchRequest) (*UserSearchResponse, error) {
 	if req.Limit < 1 || req.Limit > 50 {
 		req.Limit = 20
 	}
@@ -231,6 +243,13 @@ func (s *service) SearchUsers(ctx context.Context, req *UserSearchRequest) (*Use
 		req.Role = "service_user"
 	}
 
+	// Only service users in the searcher's care team are found
+	serviceUserIDs, err := s.careTeam.ServiceUserIDs(ctx, searcherID)
+	if err != nil {
+		return nil, err
+	}
+	req.ServiceUserIDs = append([]string{}, serviceUserIDs...)
+
 	return s.store.SearchUsers(ctx, req)
 }
 
@@ -240,7 +259,12 @@ func (s *service) GetReferralStats(ctx context.Context, userID string) (*Referra
 		return nil, fmt.Errorf("invalid user ID")
 	}
 
-	return s.store.GetReferralStats(ctx, userID)
+	return s.store.GetReferralStats(ctx, &ReferralStatsFilter{ReferredBy: userID})
+}
+
+// GetOrganisationReferralStats retrieves referral statistics across the caller's organisations
+func (s *service) GetOrganisationReferralStats(ctx context.Context, scope organisations.Scope) (*ReferralStats, error) {
+	return s.store.GetReferralStats(ctx, &ReferralStatsFilter{Scope: &scope})
 }
 
 // GetReferralsByItem gets referrals for a specific item (with access control)
@@ -274,6 +298,16 @@ func (s *service) GetReferralsByItem(ctx context.Context, itemID string, itemTyp
 	return accessibleReferrals, nil
 }
 
+// ListOutstandingReferrals returns the pending and viewed referrals sent to
+// any of the given users, urgent ones first
+func (s *service) ListOutstandingReferrals(ctx context.Context, referredTo []string) ([]Referral, error) {
+	if len(referredTo) == 0 {
+		return []Referral{}, nil
+	}
+
+	return s.store.ListOutstandingReferrals(ctx, referredTo)
+}
+
 // DeleteReferral deletes a referral with access control
 func (s *service) DeleteReferral(ctx context.Context, referralID string, userID string) error {
 	if referralID == "" {
@@ -298,6 +332,21 @@ func (s *service) DeleteReferral(ctx context.Context, referralID string, userID
 	return s.store.DeleteReferral(ctx, referralID)
 }
 
+// OnUserDeactivated cancels the outstanding referrals sent to a user whose
+// account is no longer active. Referrals they made stay with their recipients.
+func (s *service) OnUserDeactivated(ctx context.Context, event events.UserDeactivatedPayload) error {
+	cancelled, err := s.store.CancelOutstandingReferrals(ctx, event.UserID)
+	if err != nil {
+		return err
+	}
+
+	if cancelled > 0 {
+		logger.Info("Cancelled outstanding referrals for deactivated user", zap.String("user_id", event.UserID), zap.Int("referrals", cancelled))
+	}
+
+	return nil
+}
+
 // Helper functions
 
 // isValidReferralType validates referral type
@@ -364,10 +413,11 @@ func validateReferralRequest(req *CreateReferralRequest) error {
 func validateStatusTransition(currentStatus, newStatus string) error {
 	// Define allowed transitions
 	allowedTransitions := map[string][]string{
-		"pending":  {"accepted", "declined", "viewed"},
-		"accepted": {"declined"},             // Can change mind
-		"declined": {"accepted"},             // Can change mind
-		"viewed":   {"accepted", "declined"}, // Can take action after viewing
+		"pending":   {"accepted", "declined", "viewed"},
+		"accepted":  {"declined"},             // Can change mind
+		"declined":  {"accepted"},             // Can change mind
+		"viewed":    {"accepted", "declined"}, // Can take action after viewing
+		"cancelled": {},                       // Final
 	}
 
 	if currentStatus == newStatus {
@@ -0,0 +1,167 @@
+package referrals
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/careteam"
+	"github.com/perinatal-mental-health-app/backend/internal/events"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+)
+
+func TestValidateStatusTransition(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		next    string
+		wantErr bool
+	}{
+		{"unchanged", "pending", "pending", false},
+		{"pending to accepted", "pending", "accepted", false},
+		{"pending to declined", "pending", "declined", false},
+		{"pending to viewed", "pending", "viewed", false},
+		{"viewed to accepted", "viewed", "accepted", false},
+		{"viewed to declined", "viewed", "declined", false},
+		{"accepted to declined", "accepted", "declined", false},
+		{"declined to accepted", "declined", "accepted", false},
+		{"viewed back to pending", "viewed", "pending", true},
+		{"accepted back to pending", "accepted", "pending", true},
+		{"accepted to viewed", "accepted", "viewed", true},
+		{"cancelled is final", "cancelled", "accepted", true},
+		{"unknown current status", "archived", "accepted", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateStatusTransition(tt.current, tt.next)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateStatusTransition(%q, %q) error = %v, wantErr %v", tt.current, tt.next, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateReferral(t *testing.T) {
+	db := memdb.New()
+	now := time.Now()
+	for _, u := range []memdb.User{
+		{ID: "11111111-1111-1111-1111-111111111111", FullName: "Pat Professional", Email: "pro@example.com", Role: "professional", IsActive: true},
+		{ID: "22222222-2222-2222-2222-222222222222", FullName: "Sam Parent", Email: "parent@example.com", Role: "service_user", IsActive: true},
+		{ID: "33333333-3333-3333-3333-333333333333", FullName: "Inactive Parent", Email: "gone@example.com", Role: "service_user", IsActive: false},
+		{ID: "66666666-6666-6666-6666-666666666666", FullName: "Other Parent", Email: "other@example.com", Role: "service_user", IsActive: true},
+	} {
+		u.CreatedAt, u.UpdatedAt = now, now
+		if err := db.InsertUser(u, memdb.Profile{UserID: u.ID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	serviceID := "44444444-4444-4444-4444-444444444444"
+	db.PutItem(memdb.Item{Type: memdb.ItemService, ID: serviceID, Title: "Talking Therapies", IsActive: true})
+
+	const (
+		professional = "11111111-1111-1111-1111-111111111111"
+		parent       = "22222222-2222-2222-2222-222222222222"
+		inactive     = "33333333-3333-3333-3333-333333333333"
+		unlinked     = "66666666-6666-6666-6666-666666666666"
+	)
+	request := func(to, itemID string) *CreateReferralRequest {
+		return &CreateReferralRequest{ReferredTo: to, ReferralType: "service", ItemID: itemID, Reason: "Recommended after our appointment"}
+	}
+
+	careTeam := careteam.NewService(careteam.NewMemoryStore(db))
+	if _, err := careTeam.Assign(context.Background(), professional, &careteam.AssignRequest{
+		ProfessionalID: professional, ServiceUserID: parent, Reason: "Health visitor",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := NewService(NewMemoryStore(db), careTeam)
+	tests := []struct {
+		name    string
+		from    string
+		req     *CreateReferralRequest
+		wantErr string
+	}{
+		{"professional refers parent", professional, request(parent, serviceID), ""},
+		{"duplicate within 30 days", professional, request(parent, serviceID), "similar referral already exists"},
+		{"parent cannot refer", parent, request(parent, serviceID), "referrer validation failed"},
+		{"professional cannot receive", professional, request(professional, serviceID), "cannot receive referrals"},
+		{"inactive recipient", professional, request(inactive, serviceID), "recipient validation failed"},
+		{"parent outside care team", professional, request(unlinked, serviceID), careteam.ErrNoAccess.Error()},
+		{"unknown item", professional, request(parent, "55555555-5555-5555-5555-555555555555"), "item validation failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			referral, err := svc.CreateReferral(context.Background(), tt.from, tt.req)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("CreateReferral() error = %v", err)
+				}
+				if referral.Status != string(StatusPending) || referral.ItemTitle == nil || *referral.ItemTitle != "Talking Therapies" {
+					t.Errorf("CreateReferral() = %+v, want pending referral with item title", referral)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CreateReferral() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOnUserDeactivatedCancelsOutstandingReferrals(t *testing.T) {
+	logger.Init()
+	ctx := context.Background()
+	db := memdb.New()
+	now := time.Now()
+	for _, u := range []memdb.User{
+		{ID: "11111111-1111-1111-1111-111111111111", FullName: "Pat Professional", Email: "pro@example.com", Role: "professional", IsActive: true},
+		{ID: "22222222-2222-2222-2222-222222222222", FullName: "Sam Parent", Email: "parent@example.com", Role: "service_user", IsActive: true},
+		{ID: "66666666-6666-6666-6666-666666666666", FullName: "Other Parent", Email: "other@example.com", Role: "service_user", IsActive: true},
+	} {
+		u.CreatedAt, u.UpdatedAt = now, now
+		if err := db.InsertUser(u, memdb.Profile{UserID: u.ID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	serviceID := "44444444-4444-4444-4444-444444444444"
+	db.PutItem(memdb.Item{Type: memdb.ItemService, ID: serviceID, Title: "Talking Therapies", IsActive: true})
+
+	store := NewMemoryStore(db)
+	parent := "22222222-2222-2222-2222-222222222222"
+	for i, referral := range []Referral{
+		{ReferredTo: parent, Status: string(StatusPending)},
+		{ReferredTo: parent, Status: string(StatusViewed)},
+		{ReferredTo: parent, Status: string(StatusAccepted)},
+		{ReferredTo: "66666666-6666-6666-6666-666666666666", Status: string(StatusPending)},
+	} {
+		referral.ID = string(rune('a'+i)) + "0000000-0000-0000-0000-000000000000"
+		referral.ReferredBy = "11111111-1111-1111-1111-111111111111"
+		referral.ReferralType = string(TypeService)
+		referral.ItemID = serviceID
+		referral.CreatedAt, referral.UpdatedAt = now, now
+		if _, err := store.CreateReferral(ctx, &referral); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	svc := NewService(store, nil)
+	if err := svc.OnUserDeactivated(ctx, events.UserDeactivatedPayload{UserID: parent}); err != nil {
+		t.Fatalf("OnUserDeactivated() error = %v", err)
+	}
+
+	want := []ReferralStatus{StatusCancelled, StatusCancelled, StatusAccepted, StatusPending}
+	for i, status := range want {
+		referral, err := store.GetReferralByID(ctx, string(rune('a'+i))+"0000000-0000-0000-0000-000000000000")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if referral.Status != string(status) {
+			t.Errorf("referral %d status = %s, want %s", i, referral.Status, status)
+		}
+	}
+}
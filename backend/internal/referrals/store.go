@@ -8,16 +8,21 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/perinatal-mental-health-app/backend/internal/db"
+	"github.com/perinatal-mental-health-app/backend/internal/encryption"
+	"github.com/perinatal-mental-health-app/backend/internal/events"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
 )
 
 type store struct {
-	db *pgxpool.Pool
+	db     *db.Handle
+	cipher encryption.Cipher
 }
 
-func NewStore(db *pgxpool.Pool) Store {
+func NewStore(db *db.Handle, cipher encryption.Cipher) Store {
 	return &store{
-		db: db,
+		db:     db,
+		cipher: cipher,
 	}
 }
 
@@ -28,6 +33,11 @@ func (s *store) CreateReferral(ctx context.Context, referral *Referral) (*Referr
 		metadataJSON = referral.Metadata
 	}
 
+	reason, err := s.cipher.Encrypt(ctx, referral.Reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt referral reason: %w", err)
+	}
+
 	query := `
 		INSERT INTO referrals (id, referred_by, referred_to, referral_type, item_id, reason, 
 		                      status, is_urgent, metadata, created_at, updated_at)
@@ -36,10 +46,16 @@ func (s *store) CreateReferral(ctx context.Context, referral *Referral) (*Referr
 		          status, is_urgent, metadata, created_at, updated_at
 	`
 
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	var result Referral
-	err := s.db.QueryRow(ctx, query,
+	err = tx.QueryRow(ctx, query,
 		referral.ID, referral.ReferredBy, referral.ReferredTo, referral.ReferralType,
-		referral.ItemID, referral.Reason, referral.Status, referral.IsUrgent,
+		referral.ItemID, reason, referral.Status, referral.IsUrgent,
 		metadataJSON, referral.CreatedAt, referral.UpdatedAt,
 	).Scan(
 		&result.ID, &result.ReferredBy, &result.ReferredTo, &result.ReferralType,
@@ -51,6 +67,22 @@ func (s *store) CreateReferral(ctx context.Context, referral *Referral) (*Referr
 		return nil, fmt.Errorf("failed to create referral: %w", err)
 	}
 
+	err = events.Publish(ctx, tx, events.ReferralCreated, result.ID, events.ReferralCreatedPayload{
+		ReferralID:   result.ID,
+		ReferredBy:   result.ReferredBy,
+		ReferredTo:   result.ReferredTo,
+		ReferralType: result.ReferralType,
+		ItemID:       result.ItemID,
+		IsUrgent:     result.IsUrgent,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	// Get the referral with user details
 	return s.GetReferralWithDetails(ctx, result.ID)
 }
@@ -78,6 +110,10 @@ func (s *store) GetReferralByID(ctx context.Context, referralID string) (*Referr
 		return nil, fmt.Errorf("failed to get referral: %w", err)
 	}
 
+	if err := s.cipher.DecryptFields(ctx, &referral); err != nil {
+		return nil, fmt.Errorf("failed to decrypt referral: %w", err)
+	}
+
 	return &referral, nil
 }
 
@@ -126,12 +162,27 @@ func (s *store) GetReferralWithDetails(ctx context.Context, referralID string) (
 		return nil, fmt.Errorf("failed to get referral with details: %w", err)
 	}
 
+	if err := s.cipher.DecryptFields(ctx, &referral); err != nil {
+		return nil, fmt.Errorf("failed to decrypt referral: %w", err)
+	}
+
 	return &referral, nil
 }
 
+// referralsKeyset is the stable sort order used for cursor pagination
+var referralsKeyset = pagination.Keyset{SortColumn: "r.created_at", IDColumn: "r.id"}
+
 // ListReferralsSent retrieves sent referrals for a user
 func (s *store) ListReferralsSent(ctx context.Context, referredBy string, req *ListReferralsRequest) (*ListReferralsResponse, error) {
-	offset := (req.Page - 1) * req.PageSize
+	cursor, err := req.DecodeCursor()
+	if err != nil {
+		return nil, err
+	}
+
+	offset := 0
+	if cursor == nil {
+		offset = (req.Page - 1) * req.PageSize
+	}
 
 	var whereClause []string
 	var args []interface{}
@@ -161,12 +212,32 @@ func (s *store) ListReferralsSent(ctx context.Context, referredBy string, req *L
 
 	whereSQL := "WHERE " + strings.Join(whereClause, " AND ")
 
+	response := &ListReferralsResponse{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
 	// Count total
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM referrals r %s", whereSQL)
-	var total int64
-	err := s.db.QueryRow(ctx, countQuery, args...).Scan(&total)
-	if err != nil {
-		return nil, fmt.Errorf("failed to count referrals: %w", err)
+	if req.WantTotal() {
+		countQuery := fmt.Sprintf("SELECT COUNT(*) FROM referrals r %s", whereSQL)
+		var total int64
+		err := s.db.QueryRow(ctx, countQuery, args...).Scan(&total)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count referrals: %w", err)
+		}
+
+		totalPages := pagination.TotalPages(total, req.PageSize)
+		response.Total = &total
+		response.TotalPages = &totalPages
+	}
+
+	// Continue from the cursor position
+	if cursor != nil {
+		keysetSQL, keysetArgs := referralsKeyset.Where(cursor, argIndex)
+		whereClause = append(whereClause, keysetSQL)
+		args = append(args, keysetArgs...)
+		argIndex += len(keysetArgs)
+		whereSQL = "WHERE " + strings.Join(whereClause, " AND ")
 	}
 
 	// Get referrals with details
@@ -196,11 +267,12 @@ func (s *store) ListReferralsSent(ctx context.Context, referredBy string, req *L
 	LEFT JOIN resources res ON r.referral_type = 'resource' AND r.item_id::uuid = res.id
 	LEFT JOIN support_groups sg ON r.referral_type = 'support_group' AND r.item_id::uuid = sg.id
 	%s
-	ORDER BY r.created_at DESC
+	ORDER BY %s
 	LIMIT $%d OFFSET $%d
-`, whereSQL, argIndex, argIndex+1)
+`, whereSQL, referralsKeyset.OrderBy(cursor), argIndex, argIndex+1)
 
-	args = append(args, req.PageSize, offset)
+	// Read one extra row to detect a further page
+	args = append(args, req.PageSize+1, offset)
 
 	rows, err := s.db.Query(ctx, query, args...)
 	if err != nil {
@@ -220,6 +292,9 @@ func (s *store) ListReferralsSent(ctx context.Context, referredBy string, req *L
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan referral: %w", err)
 		}
+		if err := s.cipher.DecryptFields(ctx, &referral); err != nil {
+			return nil, fmt.Errorf("failed to decrypt referral: %w", err)
+		}
 		referrals = append(referrals, referral)
 	}
 
@@ -227,20 +302,22 @@ func (s *store) ListReferralsSent(ctx context.Context, referredBy string, req *L
 		return nil, fmt.Errorf("row iteration error: %w", err)
 	}
 
-	totalPages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+	response.Referrals, response.NextCursor, response.PrevCursor = pagination.Paginate(referrals, req.PageSize, cursor, offset, referralCursor)
 
-	return &ListReferralsResponse{
-		Referrals:  referrals,
-		Total:      total,
-		Page:       req.Page,
-		PageSize:   req.PageSize,
-		TotalPages: totalPages,
-	}, nil
+	return response, nil
 }
 
 // ListReferralsReceived retrieves received referrals for a user
 func (s *store) ListReferralsReceived(ctx context.Context, referredTo string, req *ListReferralsRequest) (*ListReferralsResponse, error) {
-	offset := (req.Page - 1) * req.PageSize
+	cursor, err := req.DecodeCursor()
+	if err != nil {
+		return nil, err
+	}
+
+	offset := 0
+	if cursor == nil {
+		offset = (req.Page - 1) * req.PageSize
+	}
 
 	var whereClause []string
 	var args []interface{}
@@ -270,12 +347,32 @@ func (s *store) ListReferralsReceived(ctx context.Context, referredTo string, re
 
 	whereSQL := "WHERE " + strings.Join(whereClause, " AND ")
 
+	response := &ListReferralsResponse{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
 	// Count total
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM referrals r %s", whereSQL)
-	var total int64
-	err := s.db.QueryRow(ctx, countQuery, args...).Scan(&total)
-	if err != nil {
-		return nil, fmt.Errorf("failed to count referrals: %w", err)
+	if req.WantTotal() {
+		countQuery := fmt.Sprintf("SELECT COUNT(*) FROM referrals r %s", whereSQL)
+		var total int64
+		err := s.db.QueryRow(ctx, countQuery, args...).Scan(&total)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count referrals: %w", err)
+		}
+
+		totalPages := pagination.TotalPages(total, req.PageSize)
+		response.Total = &total
+		response.TotalPages = &totalPages
+	}
+
+	// Continue from the cursor position
+	if cursor != nil {
+		keysetSQL, keysetArgs := referralsKeyset.Where(cursor, argIndex)
+		whereClause = append(whereClause, keysetSQL)
+		args = append(args, keysetArgs...)
+		argIndex += len(keysetArgs)
+		whereSQL = "WHERE " + strings.Join(whereClause, " AND ")
 	}
 
 	// Get referrals with details
@@ -305,11 +402,12 @@ func (s *store) ListReferralsReceived(ctx context.Context, referredTo string, re
 		LEFT JOIN resources res ON r.referral_type = 'resource' AND r.item_id::uuid = res.id
 		LEFT JOIN support_groups sg ON r.referral_type = 'support_group' AND r.item_id::uuid = sg.id
 		%s
-		ORDER BY r.created_at DESC
+		ORDER BY %s
 		LIMIT $%d OFFSET $%d
-	`, whereSQL, argIndex, argIndex+1)
+	`, whereSQL, referralsKeyset.OrderBy(cursor), argIndex, argIndex+1)
 
-	args = append(args, req.PageSize, offset)
+	// Read one extra row to detect a further page
+	args = append(args, req.PageSize+1, offset)
 
 	rows, err := s.db.Query(ctx, query, args...)
 	if err != nil {
@@ -329,6 +427,9 @@ func (s *store) ListReferralsReceived(ctx context.Context, referredTo string, re
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan referral: %w", err)
 		}
+		if err := s.cipher.DecryptFields(ctx, &referral); err != nil {
+			return nil, fmt.Errorf("failed to decrypt referral: %w", err)
+		}
 		referrals = append(referrals, referral)
 	}
 
@@ -336,15 +437,14 @@ func (s *store) ListReferralsReceived(ctx context.Context, referredTo string, re
 		return nil, fmt.Errorf("row iteration error: %w", err)
 	}
 
-	totalPages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+	response.Referrals, response.NextCursor, response.PrevCursor = pagination.Paginate(referrals, req.PageSize, cursor, offset, referralCursor)
 
-	return &ListReferralsResponse{
-		Referrals:  referrals,
-		Total:      total,
-		Page:       req.Page,
-		PageSize:   req.PageSize,
-		TotalPages: totalPages,
-	}, nil
+	return response, nil
+}
+
+// referralCursor returns the keyset position of a referral
+func referralCursor(referral Referral) pagination.Cursor {
+	return pagination.Cursor{SortValue: referral.CreatedAt, ID: referral.ID}
 }
 
 // UpdateReferral updates a referral
@@ -360,8 +460,12 @@ func (s *store) UpdateReferral(ctx context.Context, referralID string, req *Upda
 	}
 
 	if req.Reason != nil {
+		reason, err := s.cipher.Encrypt(ctx, *req.Reason)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt referral reason: %w", err)
+		}
 		setParts = append(setParts, fmt.Sprintf("reason = $%d", argIndex))
-		args = append(args, *req.Reason)
+		args = append(args, reason)
 		argIndex++
 	}
 
@@ -400,8 +504,19 @@ func (s *store) UpdateReferral(ctx context.Context, referralID string, req *Upda
 
 	args = append(args, referralID)
 
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	previousStatus, err := lockReferralStatus(ctx, tx, referralID)
+	if err != nil {
+		return nil, err
+	}
+
 	var referral Referral
-	err := s.db.QueryRow(ctx, query, args...).Scan(
+	err = tx.QueryRow(ctx, query, args...).Scan(
 		&referral.ID, &referral.ReferredBy, &referral.ReferredTo, &referral.ReferralType,
 		&referral.ItemID, &referral.Reason, &referral.Status, &referral.IsUrgent,
 		&referral.Metadata, &referral.CreatedAt, &referral.UpdatedAt,
@@ -414,6 +529,14 @@ func (s *store) UpdateReferral(ctx context.Context, referralID string, req *Upda
 		return nil, fmt.Errorf("failed to update referral: %w", err)
 	}
 
+	if err := publishStatusChange(ctx, tx, &referral, previousStatus); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return s.GetReferralWithDetails(ctx, referral.ID)
 }
 
@@ -423,15 +546,35 @@ func (s *store) UpdateReferralStatus(ctx context.Context, referralID string, sta
 		UPDATE referrals 
 		SET status = $1, updated_at = $2
 		WHERE id = $3
+		RETURNING id, referred_by, referred_to, referral_type, item_id, status
 	`
 
-	result, err := s.db.Exec(ctx, query, status, time.Now(), referralID)
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	previousStatus, err := lockReferralStatus(ctx, tx, referralID)
+	if err != nil {
+		return err
+	}
+
+	var referral Referral
+	err = tx.QueryRow(ctx, query, status, time.Now(), referralID).Scan(
+		&referral.ID, &referral.ReferredBy, &referral.ReferredTo, &referral.ReferralType,
+		&referral.ItemID, &referral.Status,
+	)
 	if err != nil {
 		return fmt.Errorf("failed to update referral status: %w", err)
 	}
 
-	if result.RowsAffected() == 0 {
-		return fmt.Errorf("referral not found")
+	if err := publishStatusChange(ctx, tx, &referral, previousStatus); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
@@ -459,11 +602,14 @@ func (s *store) SearchUsers(ctx context.Context, req *UserSearchRequest) (*UserS
 	var args []interface{}
 	argIndex := 1
 
-	// Search in name and email
+	// Search in name and email, or match a phone number exactly via its blind index
 	searchQuery := "%" + strings.ToLower(req.Query) + "%"
-	whereClause = append(whereClause, fmt.Sprintf("(LOWER(u.full_name) LIKE $%d OR LOWER(u.email) LIKE $%d)", argIndex, argIndex))
-	args = append(args, searchQuery)
-	argIndex++
+	phoneIndex := s.cipher.BlindIndex(encryption.NormalizePhone(req.Query))
+	whereClause = append(whereClause, fmt.Sprintf(
+		"(LOWER(u.full_name) LIKE $%d OR LOWER(u.email) LIKE $%d OR u.id IN (SELECT user_id FROM user_profiles WHERE phone_number_bidx = $%d))",
+		argIndex, argIndex, argIndex+1))
+	args = append(args, searchQuery, phoneIndex)
+	argIndex += 2
 
 	// Filter by role if specified
 	if req.Role != "" {
@@ -472,8 +618,15 @@ func (s *store) SearchUsers(ctx context.Context, req *UserSearchRequest) (*UserS
 		argIndex++
 	}
 
-	// Only active users
-	whereClause = append(whereClause, "u.is_active = true")
+	// Limit service users to those the searcher may see
+	if req.ServiceUserIDs != nil {
+		whereClause = append(whereClause, fmt.Sprintf("(u.role <> 'service_user' OR u.id = ANY($%d::uuid[]))", argIndex))
+		args = append(args, req.ServiceUserIDs)
+		argIndex++
+	}
+
+	// Only active users; guests can't be referred
+	whereClause = append(whereClause, "u.is_active = true", "u.is_guest = false")
 
 	whereSQL := "WHERE " + strings.Join(whereClause, " AND ")
 
@@ -512,6 +665,9 @@ func (s *store) SearchUsers(ctx context.Context, req *UserSearchRequest) (*UserS
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
 		}
+		if err := s.cipher.DecryptFields(ctx, &user); err != nil {
+			return nil, fmt.Errorf("failed to decrypt user: %w", err)
+		}
 		users = append(users, user)
 	}
 
@@ -526,24 +682,25 @@ func (s *store) SearchUsers(ctx context.Context, req *UserSearchRequest) (*UserS
 	}, nil
 }
 
-// GetReferralStats retrieves referral statistics for a user
-func (s *store) GetReferralStats(ctx context.Context, userID string) (*ReferralStats, error) {
+// GetReferralStats retrieves statistics for the referrals selected by the filter
+func (s *store) GetReferralStats(ctx context.Context, filter *ReferralStatsFilter) (*ReferralStats, error) {
 	stats := &ReferralStats{
 		ReferralsByType:   make(map[string]int64),
 		ReferralsByStatus: make(map[string]int64),
 	}
 
+	whereSQL, args := referralStatsWhere(filter)
+
 	// Get basic counts (sent referrals)
-	err := s.db.QueryRow(ctx, `
+	err := s.db.Reader(ctx).QueryRow(ctx, `
 		SELECT 
 			COUNT(*) as total,
 			COUNT(CASE WHEN status = 'pending' THEN 1 END) as pending,
 			COUNT(CASE WHEN status = 'accepted' THEN 1 END) as accepted,
 			COUNT(CASE WHEN status = 'declined' THEN 1 END) as declined,
 			COUNT(CASE WHEN is_urgent = true THEN 1 END) as urgent
-		FROM referrals 
-		WHERE referred_by = $1
-	`, userID).Scan(
+		FROM referrals r
+		`+whereSQL, args...).Scan(
 		&stats.TotalReferrals, &stats.PendingReferrals, &stats.AcceptedReferrals,
 		&stats.DeclinedReferrals, &stats.UrgentReferrals,
 	)
@@ -557,12 +714,12 @@ func (s *store) GetReferralStats(ctx context.Context, userID string) (*ReferralS
 	}
 
 	// Get referrals by type
-	rows, err := s.db.Query(ctx, `
+	rows, err := s.db.Reader(ctx).Query(ctx, `
 		SELECT referral_type, COUNT(*) 
-		FROM referrals 
-		WHERE referred_by = $1 
+		FROM referrals r
+		`+whereSQL+`
 		GROUP BY referral_type
-	`, userID)
+	`, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get referrals by type: %w", err)
 	}
@@ -579,12 +736,12 @@ func (s *store) GetReferralStats(ctx context.Context, userID string) (*ReferralS
 	}
 
 	// Get referrals by status
-	rows, err = s.db.Query(ctx, `
+	rows, err = s.db.Reader(ctx).Query(ctx, `
 		SELECT status, COUNT(*) 
-		FROM referrals 
-		WHERE referred_by = $1 
+		FROM referrals r
+		`+whereSQL+`
 		GROUP BY status
-	`, userID)
+	`, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get referrals by status: %w", err)
 	}
@@ -626,12 +783,12 @@ func (s *store) GetReferralStats(ctx context.Context, userID string) (*ReferralS
 		LEFT JOIN services s ON r.referral_type = 'service' AND r.item_id::uuid = s.id
 		LEFT JOIN resources res ON r.referral_type = 'resource' AND r.item_id::uuid = res.id
 		LEFT JOIN support_groups sg ON r.referral_type = 'support_group' AND r.item_id::uuid = sg.id
-		WHERE r.referred_by = $1
+		` + whereSQL + `
 		ORDER BY r.created_at DESC
 		LIMIT 5
 	`
 
-	rows, err = s.db.Query(ctx, recentQuery, userID)
+	rows, err = s.db.Reader(ctx).Query(ctx, recentQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get recent referrals: %w", err)
 	}
@@ -648,9 +805,53 @@ func (s *store) GetReferralStats(ctx context.Context, userID string) (*ReferralS
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan recent referral: %w", err)
 		}
+		if err := s.cipher.DecryptFields(ctx, &referral); err != nil {
+			return nil, fmt.Errorf("failed to decrypt referral: %w", err)
+		}
 		stats.RecentReferrals = append(stats.RecentReferrals, referral)
 	}
 
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	// Organisation views also rank the staff sending the referrals
+	if filter.Scope != nil {
+		rows, err = s.db.Reader(ctx).Query(ctx, `
+			SELECT r.referred_by, u.full_name,
+			       COUNT(*) as total_sent,
+			       COUNT(CASE WHEN r.status = 'accepted' THEN 1 END) as accepted,
+			       COUNT(CASE WHEN r.status = 'declined' THEN 1 END) as declined,
+			       COUNT(CASE WHEN r.status = 'pending' THEN 1 END) as pending
+			FROM referrals r
+			JOIN users u ON r.referred_by = u.id
+			`+whereSQL+`
+			GROUP BY r.referred_by, u.full_name
+			ORDER BY total_sent DESC
+			LIMIT 5
+		`, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get top referrers: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var referrer ReferrerStats
+			err := rows.Scan(
+				&referrer.ReferrerID, &referrer.ReferrerName, &referrer.TotalSent,
+				&referrer.Accepted, &referrer.Declined, &referrer.Pending,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to scan referrer stats: %w", err)
+			}
+			stats.TopReferrers = append(stats.TopReferrers, referrer)
+		}
+
+		if err = rows.Err(); err != nil {
+			return nil, fmt.Errorf("row iteration error: %w", err)
+		}
+	}
+
 	return stats, nil
 }
 
@@ -686,6 +887,65 @@ func (s *store) GetReferralsByItem(ctx context.Context, itemID string, itemType
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan referral: %w", err)
 		}
+		if err := s.cipher.DecryptFields(ctx, &referral); err != nil {
+			return nil, fmt.Errorf("failed to decrypt referral: %w", err)
+		}
+		referrals = append(referrals, referral)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return referrals, nil
+}
+
+// ListOutstandingReferrals gets pending and viewed referrals sent to any of the
+// users in one query, urgent ones first
+func (s *store) ListOutstandingReferrals(ctx context.Context, referredTo []string) ([]Referral, error) {
+	query := `
+		SELECT r.id, r.referred_by, r.referred_to, r.referral_type, r.item_id, r.reason,
+		       r.status, r.is_urgent, r.metadata, r.created_at, r.updated_at,
+		       referrer.full_name as referrer_name,
+		       recipient.full_name as recipient_name,
+		       COALESCE(
+		           CASE
+		               WHEN r.referral_type = 'service' THEN s.name
+		               WHEN r.referral_type = 'resource' THEN res.title
+		               WHEN r.referral_type = 'support_group' THEN sg.name
+		           END, 'Unknown Item'
+		       ) as item_title
+		FROM referrals r
+		JOIN users referrer ON r.referred_by = referrer.id
+		JOIN users recipient ON r.referred_to = recipient.id
+		LEFT JOIN services s ON r.referral_type = 'service' AND r.item_id::uuid = s.id
+		LEFT JOIN resources res ON r.referral_type = 'resource' AND r.item_id::uuid = res.id
+		LEFT JOIN support_groups sg ON r.referral_type = 'support_group' AND r.item_id::uuid = sg.id
+		WHERE r.referred_to = ANY($1::uuid[]) AND r.status IN ('pending', 'viewed')
+		ORDER BY r.is_urgent DESC, r.created_at DESC, r.id DESC
+	`
+
+	rows, err := s.db.Query(ctx, query, referredTo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outstanding referrals: %w", err)
+	}
+	defer rows.Close()
+
+	var referrals []Referral
+	for rows.Next() {
+		var referral Referral
+		err := rows.Scan(
+			&referral.ID, &referral.ReferredBy, &referral.ReferredTo, &referral.ReferralType,
+			&referral.ItemID, &referral.Reason, &referral.Status, &referral.IsUrgent,
+			&referral.Metadata, &referral.CreatedAt, &referral.UpdatedAt,
+			&referral.ReferrerName, &referral.RecipientName, &referral.ItemTitle,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan referral: %w", err)
+		}
+		if err := s.cipher.DecryptFields(ctx, &referral); err != nil {
+			return nil, fmt.Errorf("failed to decrypt referral: %w", err)
+		}
 		referrals = append(referrals, referral)
 	}
 
@@ -696,6 +956,66 @@ func (s *store) GetReferralsByItem(ctx context.Context, itemID string, itemType
 	return referrals, nil
 }
 
+// CancelOutstandingReferrals cancels the pending and viewed referrals sent to a
+// user, publishing a status change for each
+func (s *store) CancelOutstandingReferrals(ctx context.Context, referredTo string) (int, error) {
+	query := `
+		WITH outstanding AS (
+			SELECT id, status FROM referrals
+			WHERE referred_to = $1 AND status IN ('pending', 'viewed')
+			FOR UPDATE
+		)
+		UPDATE referrals r
+		SET status = $2, updated_at = $3
+		FROM outstanding
+		WHERE r.id = outstanding.id
+		RETURNING r.id, r.referred_by, r.referred_to, r.referral_type, r.item_id, r.status, outstanding.status
+	`
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, query, referredTo, StatusCancelled, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to cancel referrals: %w", err)
+	}
+
+	var cancelled []Referral
+	var previousStatuses []string
+	for rows.Next() {
+		var referral Referral
+		var previousStatus string
+		err := rows.Scan(&referral.ID, &referral.ReferredBy, &referral.ReferredTo, &referral.ReferralType,
+			&referral.ItemID, &referral.Status, &previousStatus)
+		if err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan referral: %w", err)
+		}
+		cancelled = append(cancelled, referral)
+		previousStatuses = append(previousStatuses, previousStatus)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	for i := range cancelled {
+		if err := publishStatusChange(ctx, tx, &cancelled[i], previousStatuses[i]); err != nil {
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(cancelled), nil
+}
+
 // CheckDuplicateReferral checks if a similar referral already exists
 func (s *store) CheckDuplicateReferral(ctx context.Context, referredBy, referredTo, itemID, itemType string) (bool, error) {
 	query := `
@@ -735,7 +1055,7 @@ func (s *store) ValidateUserExists(ctx context.Context, userID string) error {
 
 // ValidateUserCanReceiveReferrals checks if user can receive referrals (service_user role)
 func (s *store) ValidateUserCanReceiveReferrals(ctx context.Context, userID string) error {
-	query := `SELECT role FROM users WHERE id = $1 AND is_active = true`
+	query := `SELECT role FROM users WHERE id = $1 AND is_active = true AND is_guest = false`
 
 	var role string
 	err := s.db.QueryRow(ctx, query, userID).Scan(&role)
@@ -799,3 +1119,66 @@ func (s *store) ValidateItemExists(ctx context.Context, itemID string, itemType
 
 	return nil
 }
+
+// referralStatsWhere builds the WHERE clause selecting the referrals counted in
+// the stats. A scope matches referrals sent by the organisations' staff or made
+// to the services and support groups they own.
+func referralStatsWhere(filter *ReferralStatsFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	argIndex := 1
+
+	if filter.ReferredBy != "" {
+		conditions = append(conditions, fmt.Sprintf("r.referred_by = $%d", argIndex))
+		args = append(args, filter.ReferredBy)
+		argIndex++
+	}
+
+	if filter.Scope != nil {
+		if condition, scopeArgs := filter.Scope.Condition("organisation_id", argIndex); condition != "" {
+			conditions = append(conditions, fmt.Sprintf(`(
+			r.referred_by IN (SELECT user_id FROM organisation_members WHERE %[1]s)
+			OR (r.referral_type = 'service' AND r.item_id IN (SELECT id::text FROM services WHERE %[1]s))
+			OR (r.referral_type = 'support_group' AND r.item_id IN (SELECT id::text FROM support_groups WHERE %[1]s)))`, condition))
+			args = append(args, scopeArgs...)
+			argIndex++
+		}
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// lockReferralStatus reads the current status and locks the row until the transaction ends
+func lockReferralStatus(ctx context.Context, tx pgx.Tx, referralID string) (string, error) {
+	var status string
+	err := tx.QueryRow(ctx, `SELECT status FROM referrals WHERE id = $1 FOR UPDATE`, referralID).Scan(&status)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", fmt.Errorf("referral not found")
+		}
+		return "", fmt.Errorf("failed to get referral status: %w", err)
+	}
+
+	return status, nil
+}
+
+// publishStatusChange records a status_changed event if the status moved
+func publishStatusChange(ctx context.Context, tx pgx.Tx, referral *Referral, previousStatus string) error {
+	if referral.Status == previousStatus {
+		return nil
+	}
+
+	return events.Publish(ctx, tx, events.ReferralStatusChanged, referral.ID, events.ReferralStatusChangedPayload{
+		ReferralID:     referral.ID,
+		ReferredBy:     referral.ReferredBy,
+		ReferredTo:     referral.ReferredTo,
+		ReferralType:   referral.ReferralType,
+		ItemID:         referral.ItemID,
+		PreviousStatus: previousStatus,
+		Status:         referral.Status,
+	})
+}
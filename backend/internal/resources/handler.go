@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
 )
 
 type handler struct {
@@ -49,7 +50,9 @@ func (h *handler) ListResources(c echo.Context) error {
 		ResourceType:   c.QueryParam("resource_type"),
 		TargetAudience: c.QueryParam("target_audience"),
 		Tags:           c.QueryParam("tags"),
+		Stage:          c.QueryParam("stage"),
 		Featured:       featuredPtr,
+		Params:         pagination.ParseQuery(c),
 	}
 
 	resources, err := h.service.ListResources(c.Request().Context(), req)
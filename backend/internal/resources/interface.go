@@ -20,6 +20,7 @@ type Service interface {
 	// Admin/Staff only methods
 	CreateResource(ctx context.Context, req *CreateResourceRequest) (*Resource, error)
 	UpdateResource(ctx context.Context, resourceID string, req *UpdateResourceRequest) (*Resource, error)
+	ValidateResource(req *CreateResourceRequest) error
 	DeleteResource(ctx context.Context, resourceID string) error
 	ToggleResourceFeatured(ctx context.Context, resourceID string) error
 }
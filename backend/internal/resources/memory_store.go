@@ -0,0 +1,341 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
+	"github.com/perinatal-mental-health-app/backend/internal/perinatal"
+)
+
+// memoryStore keeps resources in memory for tests and demo mode. Titles are
+// mirrored into the shared catalog so referrals can find them.
+type memoryStore struct {
+	db        *memdb.DB
+	mu        sync.RWMutex
+	resources map[string]Resource
+}
+
+func NewMemoryStore(db *memdb.DB) Store {
+	return &memoryStore{
+		db:        db,
+		resources: make(map[string]Resource),
+	}
+}
+
+// ListResources retrieves a paginated list of active resources, featured first
+func (s *memoryStore) ListResources(ctx context.Context, req *ListResourcesRequest) (*ListResourcesResponse, error) {
+	cursor, err := req.DecodeCursor()
+	if err != nil {
+		return nil, err
+	}
+
+	offset := 0
+	if cursor == nil {
+		offset = (req.Page - 1) * req.PageSize
+	}
+
+	search := strings.ToLower(req.Search)
+	matched := s.filter(func(resource Resource) bool {
+		if search != "" && !strings.Contains(strings.ToLower(resource.Title), search) &&
+			!strings.Contains(strings.ToLower(resource.Description), search) &&
+			!strings.Contains(strings.ToLower(resource.Content), search) {
+			return false
+		}
+		if req.ResourceType != "" && resource.ResourceType != req.ResourceType {
+			return false
+		}
+		if req.TargetAudience != "" && resource.TargetAudience != req.TargetAudience {
+			return false
+		}
+		if req.Tags != "" && !containsTag(resource.Tags, req.Tags) {
+			return false
+		}
+		if req.Stage != "" && !perinatal.Matches(resource.PerinatalStages, perinatal.Stage(req.Stage)) {
+			return false
+		}
+		return req.Featured == nil || resource.IsFeatured == *req.Featured
+	})
+	sort.Slice(matched, func(i, j int) bool {
+		return pagination.Compare(resourceCursor(matched[i]), resourceCursor(matched[j])) > 0
+	})
+
+	response := &ListResourcesResponse{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	if req.WantTotal() {
+		total := int64(len(matched))
+		totalPages := pagination.TotalPages(total, req.PageSize)
+		response.Total = &total
+		response.TotalPages = &totalPages
+	}
+
+	window := pagination.Window(matched, cursor, offset, req.PageSize, resourceCursor)
+	response.Resources, response.NextCursor, response.PrevCursor = pagination.Paginate(window, req.PageSize, cursor, offset, resourceCursor)
+
+	return response, nil
+}
+
+// GetResourceByID retrieves an active resource by ID
+func (s *memoryStore) GetResourceByID(ctx context.Context, resourceID string) (*Resource, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	resource, ok := s.resources[resourceID]
+	if !ok || !resource.IsActive {
+		return nil, fmt.Errorf("resource not found")
+	}
+
+	return copyResource(resource), nil
+}
+
+// GetFeaturedResources retrieves featured resources, newest first
+func (s *memoryStore) GetFeaturedResources(ctx context.Context, limit int) ([]Resource, error) {
+	featured := s.filter(func(resource Resource) bool {
+		return resource.IsFeatured
+	})
+	sort.Slice(featured, func(i, j int) bool {
+		return featured[i].CreatedAt.After(featured[j].CreatedAt)
+	})
+
+	return limitResources(featured, limit), nil
+}
+
+// SearchResources searches resources by query
+func (s *memoryStore) SearchResources(ctx context.Context, query string, page, pageSize int) (*ListResourcesResponse, error) {
+	return s.ListResources(ctx, &ListResourcesRequest{Page: page, PageSize: pageSize, Search: query})
+}
+
+// GetResourcesByTag retrieves resources by tag
+func (s *memoryStore) GetResourcesByTag(ctx context.Context, tag string, page, pageSize int) (*ListResourcesResponse, error) {
+	return s.ListResources(ctx, &ListResourcesRequest{Page: page, PageSize: pageSize, Tags: tag})
+}
+
+// GetResourcesByAudience retrieves resources by target audience
+func (s *memoryStore) GetResourcesByAudience(ctx context.Context, audience string, page, pageSize int) (*ListResourcesResponse, error) {
+	return s.ListResources(ctx, &ListResourcesRequest{Page: page, PageSize: pageSize, TargetAudience: audience})
+}
+
+// IncrementViewCount increments the view count for a resource
+func (s *memoryStore) IncrementViewCount(ctx context.Context, resourceID string) error {
+	return s.update(resourceID, true, func(resource *Resource) {
+		resource.ViewCount++
+	})
+}
+
+// GetResourceStats retrieves resource statistics
+func (s *memoryStore) GetResourceStats(ctx context.Context) (*ResourceStats, error) {
+	stats := &ResourceStats{
+		ResourcesByType:     make(map[string]int64),
+		ResourcesByAudience: make(map[string]int64),
+	}
+
+	tagCounts := make(map[string]int64)
+	for _, resource := range s.filter(func(Resource) bool { return true }) {
+		stats.TotalResources++
+		if resource.IsFeatured {
+			stats.FeaturedResources++
+		}
+		stats.TotalViews += int64(resource.ViewCount)
+		stats.ResourcesByType[resource.ResourceType]++
+		stats.ResourcesByAudience[resource.TargetAudience]++
+		for _, tag := range resource.Tags {
+			tagCounts[tag]++
+		}
+	}
+
+	for tag, count := range tagCounts {
+		stats.PopularTags = append(stats.PopularTags, TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(stats.PopularTags, func(i, j int) bool {
+		if stats.PopularTags[i].Count != stats.PopularTags[j].Count {
+			return stats.PopularTags[i].Count > stats.PopularTags[j].Count
+		}
+		return stats.PopularTags[i].Tag < stats.PopularTags[j].Tag
+	})
+	if len(stats.PopularTags) > 10 {
+		stats.PopularTags = stats.PopularTags[:10]
+	}
+
+	return stats, nil
+}
+
+// GetPopularResources retrieves popular resources by view count
+func (s *memoryStore) GetPopularResources(ctx context.Context, limit int) ([]Resource, error) {
+	popular := s.filter(func(Resource) bool { return true })
+	sort.Slice(popular, func(i, j int) bool {
+		if popular[i].ViewCount != popular[j].ViewCount {
+			return popular[i].ViewCount > popular[j].ViewCount
+		}
+		return popular[i].CreatedAt.After(popular[j].CreatedAt)
+	})
+
+	return limitResources(popular, limit), nil
+}
+
+// CreateResource creates a new resource (admin only)
+func (s *memoryStore) CreateResource(ctx context.Context, req *CreateResourceRequest) (*Resource, error) {
+	now := time.Now()
+	resource := Resource{
+		ID:                uuid.New().String(),
+		Title:             req.Title,
+		Description:       req.Description,
+		Content:           req.Content,
+		ResourceType:      req.ResourceType,
+		URL:               req.URL,
+		Author:            req.Author,
+		Tags:              append([]string{}, req.Tags...),
+		PerinatalStages:   append([]string{}, req.PerinatalStages...),
+		TargetAudience:    req.TargetAudience,
+		EstimatedReadTime: req.EstimatedReadTime,
+		IsFeatured:        req.IsFeatured,
+		IsActive:          true,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+
+	s.mu.Lock()
+	s.resources[resource.ID] = resource
+	s.mu.Unlock()
+	s.publishItem(resource)
+
+	return copyResource(resource), nil
+}
+
+// UpdateResource updates a resource (admin only)
+func (s *memoryStore) UpdateResource(ctx context.Context, resourceID string, req *UpdateResourceRequest) (*Resource, error) {
+	err := s.update(resourceID, true, func(resource *Resource) {
+		if req.Title != nil {
+			resource.Title = *req.Title
+		}
+		if req.Description != nil {
+			resource.Description = *req.Description
+		}
+		if req.Content != nil {
+			resource.Content = *req.Content
+		}
+		if req.ResourceType != nil {
+			resource.ResourceType = *req.ResourceType
+		}
+		if req.URL != nil {
+			resource.URL = req.URL
+		}
+		if req.Author != nil {
+			resource.Author = req.Author
+		}
+		if req.Tags != nil {
+			resource.Tags = append([]string{}, req.Tags...)
+		}
+		if req.PerinatalStages != nil {
+			resource.PerinatalStages = append([]string{}, req.PerinatalStages...)
+		}
+		if req.TargetAudience != nil {
+			resource.TargetAudience = *req.TargetAudience
+		}
+		if req.EstimatedReadTime != nil {
+			resource.EstimatedReadTime = req.EstimatedReadTime
+		}
+		if req.IsFeatured != nil {
+			resource.IsFeatured = *req.IsFeatured
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetResourceByID(ctx, resourceID)
+}
+
+// DeleteResource soft deletes a resource (admin only)
+func (s *memoryStore) DeleteResource(ctx context.Context, resourceID string) error {
+	return s.update(resourceID, false, func(resource *Resource) {
+		resource.IsActive = false
+	})
+}
+
+// ToggleResourceFeatured toggles the featured status of a resource (admin only)
+func (s *memoryStore) ToggleResourceFeatured(ctx context.Context, resourceID string) error {
+	return s.update(resourceID, true, func(resource *Resource) {
+		resource.IsFeatured = !resource.IsFeatured
+	})
+}
+
+// Helper functions
+
+// filter returns copies of the active resources that match
+func (s *memoryStore) filter(match func(Resource) bool) []Resource {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var resources []Resource
+	for _, resource := range s.resources {
+		if resource.IsActive && match(resource) {
+			resources = append(resources, *copyResource(resource))
+		}
+	}
+	return resources
+}
+
+// update applies fn to a resource, optionally only when it is active
+func (s *memoryStore) update(resourceID string, activeOnly bool, fn func(*Resource)) error {
+	s.mu.Lock()
+	resource, ok := s.resources[resourceID]
+	if !ok || (activeOnly && !resource.IsActive) {
+		s.mu.Unlock()
+		return fmt.Errorf("resource not found")
+	}
+	fn(&resource)
+	resource.UpdatedAt = time.Now()
+	s.resources[resourceID] = resource
+	s.mu.Unlock()
+
+	s.publishItem(resource)
+	return nil
+}
+
+func (s *memoryStore) publishItem(resource Resource) {
+	s.db.PutItem(memdb.Item{
+		Type:        memdb.ItemResource,
+		ID:          resource.ID,
+		Title:       resource.Title,
+		Description: resource.Description,
+		IsActive:    resource.IsActive,
+	})
+}
+
+func resourceCursor(resource Resource) pagination.Cursor {
+	rank := 0
+	if resource.IsFeatured {
+		rank = 1
+	}
+	return pagination.Cursor{Rank: rank, SortValue: resource.CreatedAt, ID: resource.ID}
+}
+
+func copyResource(resource Resource) *Resource {
+	resource.Tags = append([]string{}, resource.Tags...)
+	return &resource
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func limitResources(resources []Resource, limit int) []Resource {
+	if len(resources) > limit {
+		return resources[:limit]
+	}
+	return resources
+}
@@ -3,6 +3,8 @@ package resources
 import (
 	"fmt"
 	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
 )
 
 // Resource represents a mental health resource
@@ -15,6 +17,7 @@ type Resource struct {
 	URL               *string   `json:"url,omitempty" db:"url"`
 	Author            *string   `json:"author,omitempty" db:"author"`
 	Tags              []string  `json:"tags" db:"tags"`
+	PerinatalStages   []string  `json:"perinatal_stages" db:"perinatal_stages"` // Empty suits every stage
 	TargetAudience    string    `json:"target_audience" db:"target_audience"`
 	EstimatedReadTime *int      `json:"estimated_read_time,omitempty" db:"estimated_read_time"`
 	IsFeatured        bool      `json:"is_featured" db:"is_featured"`
@@ -54,7 +57,9 @@ type ListResourcesRequest struct {
 	ResourceType   string `json:"resource_type,omitempty"`
 	TargetAudience string `json:"target_audience,omitempty"`
 	Tags           string `json:"tags,omitempty"`
+	Stage          string `json:"stage,omitempty" validate:"omitempty,oneof=first_trimester second_trimester third_trimester postnatal after_loss"`
 	Featured       *bool  `json:"featured,omitempty"`
+	pagination.Params
 }
 
 // CreateResourceRequest represents the request to create a new resource
@@ -66,6 +71,7 @@ type CreateResourceRequest struct {
 	URL               *string  `json:"url,omitempty" validate:"omitempty,url"`
 	Author            *string  `json:"author,omitempty" validate:"omitempty,max=255"`
 	Tags              []string `json:"tags,omitempty"`
+	PerinatalStages   []string `json:"perinatal_stages,omitempty" validate:"omitempty,dive,oneof=first_trimester second_trimester third_trimester postnatal after_loss"`
 	TargetAudience    string   `json:"target_audience" validate:"required,oneof=new_mothers professionals general partners families"`
 	EstimatedReadTime *int     `json:"estimated_read_time,omitempty" validate:"omitempty,min=1,max=180"`
 	IsFeatured        bool     `json:"is_featured"`
@@ -80,6 +86,7 @@ type UpdateResourceRequest struct {
 	URL               *string  `json:"url,omitempty" validate:"omitempty,url"`
 	Author            *string  `json:"author,omitempty" validate:"omitempty,max=255"`
 	Tags              []string `json:"tags,omitempty"`
+	PerinatalStages   []string `json:"perinatal_stages,omitempty" validate:"omitempty,dive,oneof=first_trimester second_trimester third_trimester postnatal after_loss"`
 	TargetAudience    *string  `json:"target_audience,omitempty" validate:"omitempty,oneof=new_mothers professionals general partners families"`
 	EstimatedReadTime *int     `json:"estimated_read_time,omitempty" validate:"omitempty,min=1,max=180"`
 	IsFeatured        *bool    `json:"is_featured,omitempty"`
@@ -88,10 +95,12 @@ type UpdateResourceRequest struct {
 // ListResourcesResponse represents the response for listing resources
 type ListResourcesResponse struct {
 	Resources  []Resource `json:"resources"`
-	Total      int64      `json:"total"`
+	Total      *int64     `json:"total,omitempty"`
 	Page       int        `json:"page"`
 	PageSize   int        `json:"page_size"`
-	TotalPages int        `json:"total_pages"`
+	TotalPages *int       `json:"total_pages,omitempty"`
+	NextCursor *string    `json:"next_cursor,omitempty"`
+	PrevCursor *string    `json:"prev_cursor,omitempty"`
 }
 
 // ResourceStats represents resource statistics
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"strings"
+
+	"github.com/perinatal-mental-health-app/backend/internal/perinatal"
 )
 
 type service struct {
@@ -35,6 +37,12 @@ func (s *service) ListResources(ctx context.Context, req *ListResourcesRequest)
 		return nil, fmt.Errorf("invalid target audience: %s", req.TargetAudience)
 	}
 
+	if req.Stage != "" {
+		if _, err := perinatal.ParseStage(req.Stage); err != nil {
+			return nil, err
+		}
+	}
+
 	return s.store.ListResources(ctx, req)
 }
 
@@ -142,22 +150,31 @@ func (s *service) GetPopularResources(ctx context.Context, limit int) ([]Resourc
 
 // CreateResource creates a new resource (admin only)
 func (s *service) CreateResource(ctx context.Context, req *CreateResourceRequest) (*Resource, error) {
+	if err := s.ValidateResource(req); err != nil {
+		return nil, err
+	}
+
+	return s.store.CreateResource(ctx, req)
+}
+
+// ValidateResource checks a new resource without saving it
+func (s *service) ValidateResource(req *CreateResourceRequest) error {
 	// Validate resource type
 	if !isValidResourceType(req.ResourceType) {
-		return nil, fmt.Errorf("invalid resource type: %s", req.ResourceType)
+		return fmt.Errorf("invalid resource type: %s", req.ResourceType)
 	}
 
 	// Validate target audience
 	if !isValidTargetAudience(req.TargetAudience) {
-		return nil, fmt.Errorf("invalid target audience: %s", req.TargetAudience)
+		return fmt.Errorf("invalid target audience: %s", req.TargetAudience)
 	}
 
-	// Additional validation
-	if err := validateResourceRequest(req); err != nil {
-		return nil, err
+	if err := perinatal.ValidateStages(req.PerinatalStages); err != nil {
+		return err
 	}
 
-	return s.store.CreateResource(ctx, req)
+	// Additional validation
+	return validateResourceRequest(req)
 }
 
 // UpdateResource updates a resource (admin only)
@@ -176,6 +193,10 @@ func (s *service) UpdateResource(ctx context.Context, resourceID string, req *Up
 		return nil, fmt.Errorf("invalid target audience: %s", *req.TargetAudience)
 	}
 
+	if err := perinatal.ValidateStages(req.PerinatalStages); err != nil {
+		return nil, err
+	}
+
 	return s.store.UpdateResource(ctx, resourceID, req)
 }
 
@@ -0,0 +1,147 @@
+package resources
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+)
+
+func newArticle(title string, tags []string, stages ...string) *CreateResourceRequest {
+	return &CreateResourceRequest{
+		Title:           title,
+		Description:     "A short guide",
+		Content:         "Guide content",
+		ResourceType:    "article",
+		Tags:            tags,
+		PerinatalStages: stages,
+		TargetAudience:  "new_mothers",
+	}
+}
+
+func TestCreateResourceValidation(t *testing.T) {
+	link := "https://example.com/guide"
+	notHTTP := "ftp://example.com/guide"
+
+	tests := []struct {
+		name    string
+		change  func(req *CreateResourceRequest)
+		wantErr string
+	}{
+		{"valid article", func(req *CreateResourceRequest) {}, ""},
+		{"external link with URL", func(req *CreateResourceRequest) { req.ResourceType, req.URL = "external_link", &link }, ""},
+		{"external link without URL", func(req *CreateResourceRequest) { req.ResourceType = "external_link" }, "URL is required"},
+		{"URL not http", func(req *CreateResourceRequest) { req.URL = &notHTTP }, "valid HTTP or HTTPS URL"},
+		{"unknown type", func(req *CreateResourceRequest) { req.ResourceType = "podcast" }, "invalid resource type"},
+		{"unknown audience", func(req *CreateResourceRequest) { req.TargetAudience = "everyone" }, "invalid target audience"},
+		{"unknown stage", func(req *CreateResourceRequest) { req.PerinatalStages = []string{"toddler"} }, "toddler"},
+		{"blank content", func(req *CreateResourceRequest) { req.Content = "  " }, "content is required"},
+		{"too many tags", func(req *CreateResourceRequest) { req.Tags = strings.Split("a,b,c,d,e,f,g,h,i,j,k", ",") }, "more than 10 tags"},
+		{"blank tag", func(req *CreateResourceRequest) { req.Tags = []string{"sleep", " "} }, "tags cannot be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(NewMemoryStore(memdb.New()))
+			req := newArticle("Sleep and mood", nil)
+			tt.change(req)
+
+			resource, err := svc.CreateResource(context.Background(), req)
+			if tt.wantErr == "" {
+				if err != nil || !resource.IsActive {
+					t.Errorf("CreateResource() = (%+v, %v), want an active resource", resource, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CreateResource() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestListResourcesFilters(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(NewMemoryStore(memdb.New()))
+
+	for _, req := range []*CreateResourceRequest{
+		newArticle("Sleep in pregnancy", []string{"sleep"}, "second_trimester"),
+		newArticle("Feeding your baby", []string{"feeding"}, "postnatal"),
+		newArticle("Looking after yourself", []string{"sleep", "wellbeing"}),
+	} {
+		if _, err := svc.CreateResource(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	titles := func(resources []Resource) map[string]bool {
+		found := map[string]bool{}
+		for _, resource := range resources {
+			found[resource.Title] = true
+		}
+		return found
+	}
+
+	// Resources without stages suit every stage
+	postnatal, err := svc.ListResources(ctx, &ListResourcesRequest{Stage: "postnatal"})
+	if err != nil {
+		t.Fatalf("ListResources() error = %v", err)
+	}
+	if got := titles(postnatal.Resources); len(got) != 2 || !got["Feeding your baby"] || !got["Looking after yourself"] {
+		t.Errorf("postnatal resources = %v", got)
+	}
+
+	sleep, _ := svc.GetResourcesByTag(ctx, " sleep ", 1, 10)
+	if got := titles(sleep.Resources); len(got) != 2 || !got["Sleep in pregnancy"] || !got["Looking after yourself"] {
+		t.Errorf("resources tagged sleep = %v", got)
+	}
+
+	for name, req := range map[string]*ListResourcesRequest{
+		"type":     {ResourceType: "podcast"},
+		"audience": {TargetAudience: "everyone"},
+		"stage":    {Stage: "toddler"},
+	} {
+		if _, err := svc.ListResources(ctx, req); err == nil {
+			t.Errorf("ListResources() with an unknown %s succeeded", name)
+		}
+	}
+
+	if _, err := svc.SearchResources(ctx, " a ", 1, 10); err == nil {
+		t.Error("SearchResources() accepted a one-character query")
+	}
+}
+
+func TestDeletedResourcesAreHidden(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(NewMemoryStore(memdb.New()))
+
+	kept, _ := svc.CreateResource(ctx, newArticle("Kept", nil))
+	removed, _ := svc.CreateResource(ctx, newArticle("Removed", nil))
+
+	for i := 0; i < 3; i++ {
+		if err := svc.IncrementViewCount(ctx, removed.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := svc.IncrementViewCount(ctx, kept.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.DeleteResource(ctx, removed.ID); err != nil {
+		t.Fatalf("DeleteResource() error = %v", err)
+	}
+
+	if _, err := svc.GetResource(ctx, removed.ID); err == nil {
+		t.Error("GetResource() returned a deleted resource")
+	}
+	if popular, _ := svc.GetPopularResources(ctx, 10); len(popular) != 1 || popular[0].ID != kept.ID {
+		t.Errorf("GetPopularResources() = %v, want only the kept resource", popular)
+	}
+	if stats, _ := svc.GetResourceStats(ctx); stats.TotalResources != 1 || stats.TotalViews != 1 {
+		t.Errorf("GetResourceStats() = %+v, want one resource with one view", stats)
+	}
+	if err := svc.ToggleResourceFeatured(ctx, removed.ID); err == nil {
+		t.Error("ToggleResourceFeatured() changed a deleted resource")
+	}
+}
@@ -8,14 +8,15 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/perinatal-mental-health-app/backend/internal/db"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
 )
 
 type store struct {
-	db *pgxpool.Pool
+	db *db.Handle
 }
 
-func NewStore(db *pgxpool.Pool) Store {
+func NewStore(db *db.Handle) Store {
 	return &store{
 		db: db,
 	}
@@ -46,9 +47,25 @@ func scanStringArray(src interface{}) ([]string, error) {
 	}
 }
 
-// ListResources retrieves a paginated list of resources with filtering
+// resourcesKeyset mirrors the featured-first ordering of ListResources
+var resourcesKeyset = pagination.Keyset{
+	RankColumn: "CASE WHEN is_featured THEN 1 ELSE 0 END",
+	SortColumn: "created_at",
+	IDColumn:   "id",
+}
+
+// ListResources retrieves a paginated list of resources with filtering. A cursor in
+// the request switches from LIMIT/OFFSET to keyset pagination.
 func (s *store) ListResources(ctx context.Context, req *ListResourcesRequest) (*ListResourcesResponse, error) {
-	offset := (req.Page - 1) * req.PageSize
+	cursor, err := req.DecodeCursor()
+	if err != nil {
+		return nil, err
+	}
+
+	offset := 0
+	if cursor == nil {
+		offset = (req.Page - 1) * req.PageSize
+	}
 
 	var whereClause []string
 	var args []interface{}
@@ -85,6 +102,13 @@ func (s *store) ListResources(ctx context.Context, req *ListResourcesRequest) (*
 		argIndex++
 	}
 
+	// Add perinatal stage filter; untagged resources suit every stage
+	if req.Stage != "" {
+		whereClause = append(whereClause, fmt.Sprintf("(cardinality(perinatal_stages) = 0 OR $%d = ANY(perinatal_stages))", argIndex))
+		args = append(args, req.Stage)
+		argIndex++
+	}
+
 	// Add featured filter
 	if req.Featured != nil {
 		whereClause = append(whereClause, fmt.Sprintf("is_featured = $%d", argIndex))
@@ -98,36 +122,55 @@ func (s *store) ListResources(ctx context.Context, req *ListResourcesRequest) (*
 		whereSQL = "WHERE " + strings.Join(whereClause, " AND ")
 	}
 
+	response := &ListResourcesResponse{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
 	// Count total resources
-	countQuery := fmt.Sprintf(`
-		SELECT COUNT(*) 
-		FROM resources 
-		%s
-	`, whereSQL)
+	if req.WantTotal() {
+		countQuery := fmt.Sprintf(`
+			SELECT COUNT(*) 
+			FROM resources 
+			%s
+		`, whereSQL)
+
+		var total int64
+		err := s.db.Reader(ctx).QueryRow(ctx, countQuery, args...).Scan(&total)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count resources: %w", err)
+		}
 
-	var total int64
-	err := s.db.QueryRow(ctx, countQuery, args...).Scan(&total)
-	if err != nil {
-		return nil, fmt.Errorf("failed to count resources: %w", err)
+		totalPages := pagination.TotalPages(total, req.PageSize)
+		response.Total = &total
+		response.TotalPages = &totalPages
+	}
+
+	// Continue from the cursor position
+	if cursor != nil {
+		keysetSQL, keysetArgs := resourcesKeyset.Where(cursor, argIndex)
+		whereClause = append(whereClause, keysetSQL)
+		args = append(args, keysetArgs...)
+		argIndex += len(keysetArgs)
+		whereSQL = "WHERE " + strings.Join(whereClause, " AND ")
 	}
 
 	// Get resources with pagination - using COALESCE for NULL arrays
 	query := fmt.Sprintf(`
 		SELECT id, title, description, content, resource_type, url, author, 
-			   COALESCE(tags, '{}') as tags,
+			   COALESCE(tags, '{}') as tags, perinatal_stages,
 			   target_audience, estimated_read_time, is_featured, is_active, view_count,
 			   created_at, updated_at
 		FROM resources
 		%s
-		ORDER BY 
-			CASE WHEN is_featured THEN 0 ELSE 1 END,
-			created_at DESC
+		ORDER BY %s
 		LIMIT $%d OFFSET $%d
-	`, whereSQL, argIndex, argIndex+1)
+	`, whereSQL, resourcesKeyset.OrderBy(cursor), argIndex, argIndex+1)
 
-	args = append(args, req.PageSize, offset)
+	// Read one extra row to detect a further page
+	args = append(args, req.PageSize+1, offset)
 
-	rows, err := s.db.Query(ctx, query, args...)
+	rows, err := s.db.Reader(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query resources: %w", err)
 	}
@@ -147,6 +190,7 @@ func (s *store) ListResources(ctx context.Context, req *ListResourcesRequest) (*
 			&resource.URL,
 			&resource.Author,
 			&tags, // pgx can scan directly into []string
+			&resource.PerinatalStages,
 			&resource.TargetAudience,
 			&resource.EstimatedReadTime,
 			&resource.IsFeatured,
@@ -173,22 +217,23 @@ func (s *store) ListResources(ctx context.Context, req *ListResourcesRequest) (*
 		return nil, fmt.Errorf("row iteration error: %w", err)
 	}
 
-	totalPages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+	response.Resources, response.NextCursor, response.PrevCursor = pagination.Paginate(resources, req.PageSize, cursor, offset,
+		func(resource Resource) pagination.Cursor {
+			rank := 0
+			if resource.IsFeatured {
+				rank = 1
+			}
+			return pagination.Cursor{Rank: rank, SortValue: resource.CreatedAt, ID: resource.ID}
+		})
 
-	return &ListResourcesResponse{
-		Resources:  resources,
-		Total:      total,
-		Page:       req.Page,
-		PageSize:   req.PageSize,
-		TotalPages: totalPages,
-	}, nil
+	return response, nil
 }
 
 // GetResourceByID retrieves a resource by ID (UUID string)
 func (s *store) GetResourceByID(ctx context.Context, resourceID string) (*Resource, error) {
 	query := `
 		SELECT id, title, description, content, resource_type, url, author, 
-			   COALESCE(tags, '{}') as tags,
+			   COALESCE(tags, '{}') as tags, perinatal_stages,
 			   target_audience, estimated_read_time, is_featured, is_active, view_count,
 			   created_at, updated_at
 		FROM resources
@@ -198,7 +243,7 @@ func (s *store) GetResourceByID(ctx context.Context, resourceID string) (*Resour
 	var resource Resource
 	var tags []string
 
-	err := s.db.QueryRow(ctx, query, resourceID).Scan(
+	err := s.db.Reader(ctx).QueryRow(ctx, query, resourceID).Scan(
 		&resource.ID,
 		&resource.Title,
 		&resource.Description,
@@ -207,6 +252,7 @@ func (s *store) GetResourceByID(ctx context.Context, resourceID string) (*Resour
 		&resource.URL,
 		&resource.Author,
 		&tags,
+		&resource.PerinatalStages,
 		&resource.TargetAudience,
 		&resource.EstimatedReadTime,
 		&resource.IsFeatured,
@@ -236,7 +282,7 @@ func (s *store) GetResourceByID(ctx context.Context, resourceID string) (*Resour
 func (s *store) GetFeaturedResources(ctx context.Context, limit int) ([]Resource, error) {
 	query := `
 		SELECT id, title, description, content, resource_type, url, author, 
-			   COALESCE(tags, '{}') as tags,
+			   COALESCE(tags, '{}') as tags, perinatal_stages,
 			   target_audience, estimated_read_time, is_featured, is_active, view_count,
 			   created_at, updated_at
 		FROM resources
@@ -245,7 +291,7 @@ func (s *store) GetFeaturedResources(ctx context.Context, limit int) ([]Resource
 		LIMIT $1
 	`
 
-	rows, err := s.db.Query(ctx, query, limit)
+	rows, err := s.db.Reader(ctx).Query(ctx, query, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query featured resources: %w", err)
 	}
@@ -265,6 +311,7 @@ func (s *store) GetFeaturedResources(ctx context.Context, limit int) ([]Resource
 			&resource.URL,
 			&resource.Author,
 			&tags,
+			&resource.PerinatalStages,
 			&resource.TargetAudience,
 			&resource.EstimatedReadTime,
 			&resource.IsFeatured,
@@ -348,26 +395,26 @@ func (s *store) GetResourceStats(ctx context.Context) (*ResourceStats, error) {
 	var totalResources, featuredResources, totalViews int64
 
 	// Count total resources
-	err := s.db.QueryRow(ctx, "SELECT COUNT(*) FROM resources WHERE is_active = true").Scan(&totalResources)
+	err := s.db.Reader(ctx).QueryRow(ctx, "SELECT COUNT(*) FROM resources WHERE is_active = true").Scan(&totalResources)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count total resources: %w", err)
 	}
 
 	// Count featured resources
-	err = s.db.QueryRow(ctx, "SELECT COUNT(*) FROM resources WHERE is_active = true AND is_featured = true").Scan(&featuredResources)
+	err = s.db.Reader(ctx).QueryRow(ctx, "SELECT COUNT(*) FROM resources WHERE is_active = true AND is_featured = true").Scan(&featuredResources)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count featured resources: %w", err)
 	}
 
 	// Sum total views
-	err = s.db.QueryRow(ctx, "SELECT COALESCE(SUM(view_count), 0) FROM resources WHERE is_active = true").Scan(&totalViews)
+	err = s.db.Reader(ctx).QueryRow(ctx, "SELECT COALESCE(SUM(view_count), 0) FROM resources WHERE is_active = true").Scan(&totalViews)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sum total views: %w", err)
 	}
 
 	// Get resources by type
 	resourcesByType := make(map[string]int64)
-	rows, err := s.db.Query(ctx, `
+	rows, err := s.db.Reader(ctx).Query(ctx, `
 		SELECT resource_type, COUNT(*) 
 		FROM resources 
 		WHERE is_active = true 
@@ -390,7 +437,7 @@ func (s *store) GetResourceStats(ctx context.Context) (*ResourceStats, error) {
 
 	// Get resources by audience
 	resourcesByAudience := make(map[string]int64)
-	rows, err = s.db.Query(ctx, `
+	rows, err = s.db.Reader(ctx).Query(ctx, `
 		SELECT target_audience, COUNT(*) 
 		FROM resources 
 		WHERE is_active = true 
@@ -413,7 +460,7 @@ func (s *store) GetResourceStats(ctx context.Context) (*ResourceStats, error) {
 
 	// Get popular tags - handle NULL arrays properly
 	var popularTags []TagCount
-	rows, err = s.db.Query(ctx, `
+	rows, err = s.db.Reader(ctx).Query(ctx, `
 		SELECT unnest(COALESCE(tags, '{}')) as tag, COUNT(*) as count
 		FROM resources 
 		WHERE is_active = true AND tags IS NOT NULL AND array_length(tags, 1) > 0
@@ -449,7 +496,7 @@ func (s *store) GetResourceStats(ctx context.Context) (*ResourceStats, error) {
 func (s *store) GetPopularResources(ctx context.Context, limit int) ([]Resource, error) {
 	query := `
 		SELECT id, title, description, content, resource_type, url, author, 
-			   COALESCE(tags, '{}') as tags,
+			   COALESCE(tags, '{}') as tags, perinatal_stages,
 			   target_audience, estimated_read_time, is_featured, is_active, view_count,
 			   created_at, updated_at
 		FROM resources
@@ -458,7 +505,7 @@ func (s *store) GetPopularResources(ctx context.Context, limit int) ([]Resource,
 		LIMIT $1
 	`
 
-	rows, err := s.db.Query(ctx, query, limit)
+	rows, err := s.db.Reader(ctx).Query(ctx, query, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query popular resources: %w", err)
 	}
@@ -478,6 +525,7 @@ func (s *store) GetPopularResources(ctx context.Context, limit int) ([]Resource,
 			&resource.URL,
 			&resource.Author,
 			&tags,
+			&resource.PerinatalStages,
 			&resource.TargetAudience,
 			&resource.EstimatedReadTime,
 			&resource.IsFeatured,
@@ -510,19 +558,23 @@ func (s *store) CreateResource(ctx context.Context, req *CreateResourceRequest)
 	resourceID := uuid.New()
 	now := time.Now()
 
-	// Ensure tags is not nil
+	// Ensure tags and stages are not nil
 	tags := req.Tags
 	if tags == nil {
 		tags = []string{}
 	}
+	stages := req.PerinatalStages
+	if stages == nil {
+		stages = []string{}
+	}
 
 	query := `
 		INSERT INTO resources (id, title, description, content, resource_type, url, author, 
 							  tags, target_audience, estimated_read_time, is_featured, 
-							  is_active, view_count, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+							  is_active, view_count, created_at, updated_at, perinatal_stages)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		RETURNING id, title, description, content, resource_type, url, author, 
-				  COALESCE(tags, '{}') as tags,
+				  COALESCE(tags, '{}') as tags, perinatal_stages,
 				  target_audience, estimated_read_time, is_featured, is_active, view_count,
 				  created_at, updated_at
 	`
@@ -546,6 +598,7 @@ func (s *store) CreateResource(ctx context.Context, req *CreateResourceRequest)
 		0,    // view_count
 		now,  // created_at
 		now,  // updated_at
+		stages,
 	).Scan(
 		&resource.ID,
 		&resource.Title,
@@ -555,6 +608,7 @@ func (s *store) CreateResource(ctx context.Context, req *CreateResourceRequest)
 		&resource.URL,
 		&resource.Author,
 		&returnedTags,
+		&resource.PerinatalStages,
 		&resource.TargetAudience,
 		&resource.EstimatedReadTime,
 		&resource.IsFeatured,
@@ -625,6 +679,12 @@ func (s *store) UpdateResource(ctx context.Context, resourceID string, req *Upda
 		argIndex++
 	}
 
+	if req.PerinatalStages != nil {
+		setParts = append(setParts, fmt.Sprintf("perinatal_stages = $%d", argIndex))
+		args = append(args, req.PerinatalStages)
+		argIndex++
+	}
+
 	if req.TargetAudience != nil {
 		setParts = append(setParts, fmt.Sprintf("target_audience = $%d", argIndex))
 		args = append(args, *req.TargetAudience)
@@ -658,7 +718,7 @@ func (s *store) UpdateResource(ctx context.Context, resourceID string, req *Upda
 		SET %s
 		WHERE id = $%d AND is_active = true
 		RETURNING id, title, description, content, resource_type, url, author, 
-				  COALESCE(tags, '{}') as tags,
+				  COALESCE(tags, '{}') as tags, perinatal_stages,
 				  target_audience, estimated_read_time, is_featured, is_active, view_count,
 				  created_at, updated_at
 	`, strings.Join(setParts, ", "), argIndex)
@@ -677,6 +737,7 @@ func (s *store) UpdateResource(ctx context.Context, resourceID string, req *Upda
 		&resource.URL,
 		&resource.Author,
 		&tags,
+		&resource.PerinatalStages,
 		&resource.TargetAudience,
 		&resource.EstimatedReadTime,
 		&resource.IsFeatured,
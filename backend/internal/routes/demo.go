@@ -0,0 +1,199 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/audit"
+	"github.com/perinatal-mental-health-app/backend/internal/auth"
+	"github.com/perinatal-mental-health-app/backend/internal/bookmarks"
+	"github.com/perinatal-mental-health-app/backend/internal/careteam"
+	"github.com/perinatal-mental-health-app/backend/internal/catalog"
+	config "github.com/perinatal-mental-health-app/backend/internal/configs"
+	"github.com/perinatal-mental-health-app/backend/internal/emergencycontacts"
+	"github.com/perinatal-mental-health-app/backend/internal/feedback"
+	"github.com/perinatal-mental-health-app/backend/internal/health"
+	"github.com/perinatal-mental-health-app/backend/internal/httpcache"
+	"github.com/perinatal-mental-health-app/backend/internal/idempotency"
+	"github.com/perinatal-mental-health-app/backend/internal/jobs"
+	"github.com/perinatal-mental-health-app/backend/internal/journey"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"github.com/perinatal-mental-health-app/backend/internal/mail"
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+	custommiddleware "github.com/perinatal-mental-health-app/backend/internal/middleware"
+	"github.com/perinatal-mental-health-app/backend/internal/onboarding"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/privacy"
+	"github.com/perinatal-mental-health-app/backend/internal/referrals"
+	"github.com/perinatal-mental-health-app/backend/internal/resources"
+	"github.com/perinatal-mental-health-app/backend/internal/services"
+	"github.com/perinatal-mental-health-app/backend/internal/support_groups"
+	"github.com/perinatal-mental-health-app/backend/internal/supporters"
+	"github.com/perinatal-mental-health-app/backend/internal/user"
+	"go.uber.org/zap"
+)
+
+// DemoPassword is the password of the seeded demo accounts
+const DemoPassword = "demo-password"
+
+// RegisterDemo mounts the user-facing API on in-memory stores seeded with demo
+// accounts and catalog entries. Nothing is persisted. The admin modules that
+// need Postgres (audit log, organisations, encryption, webhooks) are not mounted,
+// audit entries are written to the log, and every staff member sees all
+// organisations. The returned worker runs the jobs the API enqueues.
+func RegisterDemo(e *echo.Echo, cfg *config.Config, queues map[string]int) (*jobs.Worker, error) {
+	v1 := e.Group(apiPrefix)
+
+	e.GET("/health", health.Health)
+	v1.GET("/openapi.json", serveAPIDocument(e))
+
+	db := memdb.New()
+	stores := apiStores{
+		Auth:              auth.NewMemoryStore(db),
+		Bookmarks:         bookmarks.NewMemoryStore(db),
+		User:              user.NewMemoryStore(db),
+		Privacy:           privacy.NewMemoryStore(db),
+		Services:          services.NewMemoryStore(db),
+		Resources:         resources.NewMemoryStore(db),
+		SupportGroups:     support_groups.NewMemoryStore(db),
+		Catalog:           catalog.NewMemoryStore(),
+		CareTeam:          careteam.NewMemoryStore(db),
+		EmergencyContacts: emergencycontacts.NewMemoryStore(),
+		Onboarding:        onboarding.NewMemoryStore(db),
+		Supporters:        supporters.NewMemoryStore(db),
+		Referrals:         referrals.NewMemoryStore(db),
+		Feedback:          feedback.NewMemoryStore(),
+		Journey:           journey.NewMemoryStore(),
+	}
+
+	jobsStore := jobs.NewMemoryStore()
+	jobsService := jobs.NewService(jobsStore)
+
+	// Demo mode never delivers email; messages are only logged
+	mailer := mail.NewLogSender()
+
+	jwtService := auth.NewJWTService(cfg.JWTSecret)
+	jwtService.UseRevocations(stores.Auth)
+	if err := seedDemo(context.Background(), auth.NewService(stores.Auth, *jwtService, jobsService), stores); err != nil {
+		return nil, fmt.Errorf("failed to seed demo data: %w", err)
+	}
+
+	var recorder logRecorder
+	snapshots := audit.Snapshots{}
+	idempotencyStore := idempotency.NewMemoryStore()
+	registerAPI(v1, apiDeps{
+		jwtService: jwtService,
+		audited: func(action, targetType, targetParam string) echo.MiddlewareFunc {
+			return custommiddleware.Audit(recorder, snapshots, action, targetType, targetParam)
+		},
+		snapshots:       snapshots,
+		idempotent:      custommiddleware.IdempotencyMiddleware(idempotencyStore, cfg.IdempotencyTTL),
+		orgScoped:       custommiddleware.OrganisationScope(allOrganisations{}),
+		catalogVersions: httpcache.NewMemoryStore(),
+		jobs:            jobsService,
+		mailer:          mailer,
+		stores:          stores,
+	})
+
+	worker := jobs.NewWorker(jobsStore, queues, cfg.JobPollInterval)
+	privacyService := privacy.NewService(stores.Privacy, jobsService)
+	worker.Register(privacy.JobCompileDataExport, jobs.Handle(privacyService.ProcessDataDownload))
+	journeyService := journey.NewService(stores.Journey, jobsService)
+	worker.Register(journey.JobCheckMilestones, jobs.Handle(journeyService.CheckMilestones))
+	userService := user.NewService(stores.User, jobsService, careteam.NewService(stores.CareTeam), mailer)
+	worker.Register(user.JobAnonymiseAccount, jobs.Handle(userService.AnonymiseAccount))
+	worker.Register(user.JobSendEmailChange, jobs.Handle(userService.SendEmailChange))
+	onboardingService := onboarding.NewService(stores.Onboarding, jobsService, mailer)
+	worker.Register(onboarding.JobSendInvitation, jobs.Handle(onboardingService.SendInvitation))
+	authService := auth.NewService(stores.Auth, *jwtService, jobsService)
+	worker.Register(auth.JobExpireGuest, jobs.Handle(authService.ExpireGuest))
+	worker.Every(idempotency.TaskDeleteExpired, idempotency.DeleteExpiredEvery, deleteExpiredKeys(idempotencyStore))
+
+	return worker, nil
+}
+
+// logRecorder writes audit entries to the application log
+type logRecorder struct{}
+
+func (logRecorder) Record(ctx context.Context, entry *audit.Entry) error {
+	logger.Info("Audit",
+		zap.String("actor_id", entry.ActorID),
+		zap.String("action", entry.Action),
+		zap.String("target_type", entry.TargetType),
+		zap.String("target_id", entry.TargetID),
+		zap.Int("status_code", entry.StatusCode),
+	)
+	return nil
+}
+
+// allOrganisations gives every caller an unrestricted scope
+type allOrganisations struct{}
+
+func (allOrganisations) ResolveScope(ctx context.Context, userID, organisationID string) (*organisations.Scope, error) {
+	return &organisations.Scope{All: true, IsSuperAdmin: true}, nil
+}
+
+// seedDemo creates one account per role, a care team linking the parent to
+// the professional and a few catalog entries
+func seedDemo(ctx context.Context, authService auth.Service, stores apiStores) error {
+	accounts := []auth.RegisterRequest{
+		{Email: "parent@demo.local", FullName: "Demo Parent", Role: "service_user"},
+		{Email: "professional@demo.local", FullName: "Demo Professional", Role: "professional"},
+		{Email: "staff@demo.local", FullName: "Demo NHS Staff", Role: "nhs_staff"},
+	}
+	userIDs := make(map[string]string, len(accounts))
+	for _, account := range accounts {
+		account.Password = DemoPassword
+		registered, err := authService.Register(ctx, &account)
+		if err != nil {
+			return err
+		}
+		userIDs[account.Role] = registered.User.ID
+	}
+
+	// The demo professional cares for the demo parent
+	_, err := careteam.NewService(stores.CareTeam).Assign(ctx, userIDs["nhs_staff"], &careteam.AssignRequest{
+		ProfessionalID: userIDs["professional"],
+		ServiceUserID:  userIDs["service_user"],
+		Reason:         "Demo care team",
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = stores.Services.CreateService(ctx, &services.CreateServiceRequest{
+		Name:         "Perinatal Mental Health Team",
+		Description:  "Specialist community support for mental health during pregnancy and the first year after birth.",
+		ProviderName: "Demo NHS Trust",
+		ServiceType:  "hybrid",
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = stores.Resources.CreateResource(ctx, &resources.CreateResourceRequest{
+		Title:          "Sleep and your mood",
+		Description:    "How broken sleep affects how you feel, and small things that help.",
+		Content:        "Sleep loss is one of the most common triggers for low mood after birth.",
+		ResourceType:   "article",
+		Tags:           []string{"sleep", "postnatal"},
+		TargetAudience: "new_mothers",
+		IsFeatured:     true,
+	})
+	if err != nil {
+		return err
+	}
+
+	maxMembers := 12
+	groupURL := "https://example.org/new-parents-circle"
+	_, err = stores.SupportGroups.CreateSupportGroup(ctx, &support_groups.CreateSupportGroupRequest{
+		Name:        "New Parents Circle",
+		Description: "A weekly online group for parents in the first months after birth.",
+		Category:    "postnatal",
+		Platform:    "online",
+		URL:         &groupURL,
+		MaxMembers:  &maxMembers,
+	})
+	return err
+}
@@ -0,0 +1,386 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	config "github.com/perinatal-mental-health-app/backend/internal/configs"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+)
+
+func newDemoServer(t *testing.T) *echo.Echo {
+	t.Helper()
+
+	logger.Init()
+	e := echo.New()
+	if _, err := RegisterDemo(e, &config.Config{JWTSecret: "test-secret", IdempotencyTTL: time.Hour, JobPollInterval: time.Second}, map[string]int{"default": 1}); err != nil {
+		t.Fatalf("register demo: %v", err)
+	}
+	return e
+}
+
+// do sends a JSON request and decodes the JSON response into out when it is not nil
+func do(t *testing.T, e *echo.Echo, method, path, token string, body, out interface{}) int {
+	t.Helper()
+
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatalf("encode body: %v", err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &payload)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: decode response %q: %v", method, path, rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+func login(t *testing.T, e *echo.Echo, email string) (token, userID string) {
+	t.Helper()
+
+	var resp struct {
+		Token string `json:"token"`
+		User  struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	}
+	code := do(t, e, http.MethodPost, "/api/v1/auth/login", "", map[string]string{"email": email, "password": DemoPassword}, &resp)
+	if code != http.StatusOK {
+		t.Fatalf("login %s: status %d", email, code)
+	}
+	return resp.Token, resp.User.ID
+}
+
+func TestDemoLogin(t *testing.T) {
+	e := newDemoServer(t)
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		want     int
+	}{
+		{"parent", "parent@demo.local", DemoPassword, http.StatusOK},
+		{"professional", "professional@demo.local", DemoPassword, http.StatusOK},
+		{"staff", "staff@demo.local", DemoPassword, http.StatusOK},
+		{"wrong password", "parent@demo.local", "not-the-password", http.StatusUnauthorized},
+		{"unknown account", "nobody@demo.local", DemoPassword, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := do(t, e, http.MethodPost, "/api/v1/auth/login", "", map[string]string{"email": tt.email, "password": tt.password}, nil)
+			if code != tt.want {
+				t.Errorf("status = %d, want %d", code, tt.want)
+			}
+		})
+	}
+}
+
+func TestDemoCatalog(t *testing.T) {
+	e := newDemoServer(t)
+
+	tests := []struct {
+		path  string
+		field string
+	}{
+		{"/api/v1/services", "services"},
+		{"/api/v1/resources", "resources"},
+		{"/api/v1/support-groups", "support_groups"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var resp map[string]json.RawMessage
+			if code := do(t, e, http.MethodGet, tt.path, "", nil, &resp); code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", code, http.StatusOK)
+			}
+			var items []map[string]interface{}
+			if err := json.Unmarshal(resp[tt.field], &items); err != nil {
+				t.Fatalf("decode %s: %v", tt.field, err)
+			}
+			if len(items) != 1 {
+				t.Errorf("got %d %s, want the 1 seeded entry", len(items), tt.field)
+			}
+		})
+	}
+}
+
+func TestDemoSupportGroupMembership(t *testing.T) {
+	e := newDemoServer(t)
+	token, _ := login(t, e, "parent@demo.local")
+
+	var groups struct {
+		SupportGroups []struct {
+			ID string `json:"id"`
+		} `json:"support_groups"`
+	}
+	do(t, e, http.MethodGet, "/api/v1/support-groups", "", nil, &groups)
+	if len(groups.SupportGroups) == 0 {
+		t.Fatal("no seeded support group")
+	}
+	groupID := groups.SupportGroups[0].ID
+
+	join := map[string]string{"group_id": groupID}
+	if code := do(t, e, http.MethodPost, "/api/v1/support-groups/join", token, join, nil); code != http.StatusOK {
+		t.Fatalf("join: status %d", code)
+	}
+	if code := do(t, e, http.MethodPost, "/api/v1/support-groups/join", token, join, nil); code != http.StatusBadRequest {
+		t.Errorf("second join: status %d, want %d", code, http.StatusBadRequest)
+	}
+
+	var mine struct {
+		Groups []map[string]interface{} `json:"groups"`
+	}
+	if code := do(t, e, http.MethodGet, "/api/v1/my-groups", token, nil, &mine); code != http.StatusOK || len(mine.Groups) != 1 {
+		t.Errorf("my-groups: status %d with %d groups, want 1 group", code, len(mine.Groups))
+	}
+
+	if code := do(t, e, http.MethodDelete, "/api/v1/support-groups/"+groupID+"/leave", token, nil, nil); code != http.StatusOK {
+		t.Errorf("leave: status %d", code)
+	}
+}
+
+func TestDemoReferrals(t *testing.T) {
+	e := newDemoServer(t)
+	professionalToken, _ := login(t, e, "professional@demo.local")
+	parentToken, parentID := login(t, e, "parent@demo.local")
+
+	var services struct {
+		Services []struct {
+			ID string `json:"id"`
+		} `json:"services"`
+	}
+	do(t, e, http.MethodGet, "/api/v1/services", "", nil, &services)
+	if len(services.Services) == 0 {
+		t.Fatal("no seeded service")
+	}
+
+	referral := map[string]interface{}{
+		"referred_to":   parentID,
+		"referral_type": "service",
+		"item_id":       services.Services[0].ID,
+		"reason":        "Specialist support for low mood after birth",
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"professional refers parent", professionalToken, http.StatusCreated},
+		{"duplicate referral", professionalToken, http.StatusBadRequest},
+		{"parent cannot refer", parentToken, http.StatusForbidden},
+		{"anonymous", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := do(t, e, http.MethodPost, "/api/v1/referrals", tt.token, referral, nil); code != tt.want {
+				t.Errorf("status = %d, want %d", code, tt.want)
+			}
+		})
+	}
+
+	var received struct {
+		Referrals []map[string]interface{} `json:"referrals"`
+	}
+	if code := do(t, e, http.MethodGet, "/api/v1/referrals/received", parentToken, nil, &received); code != http.StatusOK || len(received.Referrals) != 1 {
+		t.Errorf("received: status %d with %d referrals, want 1 referral", code, len(received.Referrals))
+	}
+}
+
+func TestDemoCareTeamGatesProfiles(t *testing.T) {
+	e := newDemoServer(t)
+	professionalToken, _ := login(t, e, "professional@demo.local")
+	staffToken, _ := login(t, e, "staff@demo.local")
+	parentToken, parentID := login(t, e, "parent@demo.local")
+	profile := "/api/v1/users/" + parentID + "/profile"
+
+	if code := do(t, e, http.MethodGet, profile, professionalToken, nil, nil); code != http.StatusOK {
+		t.Errorf("care-team professional: status = %d, want %d", code, http.StatusOK)
+	}
+	if code := do(t, e, http.MethodGet, profile, staffToken, nil, nil); code != http.StatusForbidden {
+		t.Errorf("staff outside care team: status = %d, want %d", code, http.StatusForbidden)
+	}
+
+	breakGlass := map[string]string{"service_user_id": parentID, "reason": "Safeguarding call from the ward"}
+	if code := do(t, e, http.MethodPost, "/api/v1/care-team/break-glass", staffToken, breakGlass, nil); code != http.StatusCreated {
+		t.Fatalf("break-glass: status = %d, want %d", code, http.StatusCreated)
+	}
+	if code := do(t, e, http.MethodGet, profile, staffToken, nil, nil); code != http.StatusOK {
+		t.Errorf("staff with break-glass: status = %d, want %d", code, http.StatusOK)
+	}
+
+	var team struct {
+		Relationships []struct {
+			ID string `json:"id"`
+		} `json:"relationships"`
+	}
+	if code := do(t, e, http.MethodGet, "/api/v1/me/care-team", parentToken, nil, &team); code != http.StatusOK || len(team.Relationships) != 1 {
+		t.Fatalf("care team: status %d with %d relationships, want 1", code, len(team.Relationships))
+	}
+	if code := do(t, e, http.MethodPost, "/api/v1/me/care-team/"+team.Relationships[0].ID+"/end", parentToken, map[string]string{}, nil); code != http.StatusOK {
+		t.Fatalf("end: status = %d, want %d", code, http.StatusOK)
+	}
+	if code := do(t, e, http.MethodGet, profile, professionalToken, nil, nil); code != http.StatusForbidden {
+		t.Errorf("after leaving care team: status = %d, want %d", code, http.StatusForbidden)
+	}
+}
+
+func TestDemoAccountSuspension(t *testing.T) {
+	e := newDemoServer(t)
+	staffToken, _ := login(t, e, "staff@demo.local")
+	professionalToken, _ := login(t, e, "professional@demo.local")
+	parentToken, parentID := login(t, e, "parent@demo.local")
+	users := "/api/v1/users/" + parentID
+	credentials := map[string]string{"email": "parent@demo.local", "password": DemoPassword}
+
+	if code := do(t, e, http.MethodPost, users+"/suspend", staffToken, map[string]string{"reason": "Reported for abuse"}, nil); code != http.StatusOK {
+		t.Fatalf("suspend: status = %d, want %d", code, http.StatusOK)
+	}
+	if code := do(t, e, http.MethodGet, "/api/v1/me", parentToken, nil, nil); code != http.StatusUnauthorized {
+		t.Errorf("suspended user's token: status = %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code := do(t, e, http.MethodPost, "/api/v1/auth/login", "", credentials, nil); code == http.StatusOK {
+		t.Error("suspended user logged in")
+	}
+	if code := do(t, e, http.MethodPost, users+"/suspend", staffToken, map[string]string{"reason": "Reported again"}, nil); code != http.StatusConflict {
+		t.Errorf("suspend twice: status = %d, want %d", code, http.StatusConflict)
+	}
+	if code := do(t, e, http.MethodPost, users+"/schedule-deletion", professionalToken, map[string]string{"reason": "Requested by the user"}, nil); code != http.StatusForbidden {
+		t.Errorf("professional scheduling deletion: status = %d, want %d", code, http.StatusForbidden)
+	}
+
+	var status struct {
+		Status  string `json:"status"`
+		History []struct {
+			ToStatus string `json:"to_status"`
+			Reason   string `json:"reason"`
+		} `json:"history"`
+	}
+	if code := do(t, e, http.MethodPost, users+"/reactivate", staffToken, map[string]string{"reason": "Report was unfounded"}, &status); code != http.StatusOK {
+		t.Fatalf("reactivate: status = %d, want %d", code, http.StatusOK)
+	}
+	if status.Status != "active" || len(status.History) != 2 || status.History[0].Reason != "Reported for abuse" {
+		t.Errorf("reactivate = %+v, want active after two changes", status)
+	}
+	login(t, e, "parent@demo.local")
+}
+
+func TestDemoJourney(t *testing.T) {
+	e := newDemoServer(t)
+	token, _ := login(t, e, "parent@demo.local")
+
+	entry := map[string]interface{}{"mood_rating": 4, "notes": "Slept a little better"}
+	if code := do(t, e, http.MethodPost, "/api/v1/journey/entries", token, entry, nil); code != http.StatusCreated {
+		t.Fatalf("create entry: status %d", code)
+	}
+	if code := do(t, e, http.MethodPost, "/api/v1/journey/entries", token, entry, nil); code == http.StatusCreated {
+		t.Error("second entry for the same day was accepted")
+	}
+
+	var stats struct {
+		TotalEntries  int `json:"total_entries"`
+		CurrentStreak int `json:"current_streak"`
+	}
+	if code := do(t, e, http.MethodGet, "/api/v1/journey/stats", token, nil, &stats); code != http.StatusOK {
+		t.Fatalf("stats: status %d", code)
+	}
+	if stats.TotalEntries != 1 || stats.CurrentStreak != 1 {
+		t.Errorf("stats = %+v, want 1 entry and a streak of 1", stats)
+	}
+}
+
+func TestDemoGuestUpgrade(t *testing.T) {
+	e := newDemoServer(t)
+
+	var guest struct {
+		Token string `json:"token"`
+		User  struct {
+			ID      string `json:"id"`
+			IsGuest bool   `json:"is_guest"`
+		} `json:"user"`
+	}
+	if code := do(t, e, http.MethodPost, "/api/v1/auth/guest", "", nil, &guest); code != http.StatusCreated || !guest.User.IsGuest {
+		t.Fatalf("start guest session: status %d, user %+v", code, guest.User)
+	}
+
+	var services struct {
+		Services []struct {
+			ID string `json:"id"`
+		} `json:"services"`
+	}
+	do(t, e, http.MethodGet, "/api/v1/services", "", nil, &services)
+	if len(services.Services) == 0 {
+		t.Fatal("no seeded service")
+	}
+
+	// Guests can keep a journal and bookmark, but nothing needing an account
+	bookmark := map[string]string{"item_type": "service", "item_id": services.Services[0].ID}
+	if code := do(t, e, http.MethodPost, "/api/v1/bookmarks", guest.Token, bookmark, nil); code != http.StatusCreated {
+		t.Errorf("guest bookmark: status %d", code)
+	}
+	entry := map[string]interface{}{"mood_rating": 2, "notes": "Just looking for now"}
+	if code := do(t, e, http.MethodPost, "/api/v1/journey/entries", guest.Token, entry, nil); code != http.StatusCreated {
+		t.Errorf("guest journal entry: status %d", code)
+	}
+	if code := do(t, e, http.MethodGet, "/api/v1/me", guest.Token, nil, nil); code != http.StatusForbidden {
+		t.Errorf("guest profile: status = %d, want %d", code, http.StatusForbidden)
+	}
+	staffOnly := map[string]string{"email": "guest-upgrade@demo.local", "password": "a-long-password", "full_name": "New Parent", "role": "nhs_staff"}
+	if code := do(t, e, http.MethodPost, "/api/v1/auth/register", guest.Token, staffOnly, nil); code == http.StatusCreated {
+		t.Error("guest upgraded to a staff account")
+	}
+
+	var registered struct {
+		Token string `json:"token"`
+		User  struct {
+			ID      string `json:"id"`
+			IsGuest bool   `json:"is_guest"`
+		} `json:"user"`
+	}
+	account := map[string]string{"email": "guest-upgrade@demo.local", "password": "a-long-password", "full_name": "New Parent", "role": "service_user"}
+	if code := do(t, e, http.MethodPost, "/api/v1/auth/register", guest.Token, account, &registered); code != http.StatusCreated {
+		t.Fatalf("upgrade: status %d", code)
+	}
+	if registered.User.ID != guest.User.ID || registered.User.IsGuest {
+		t.Fatalf("upgrade = %+v, want the guest's ID as a full account", registered.User)
+	}
+
+	// The journal and bookmarks carry over to the full account
+	var bookmarks struct {
+		Bookmarks []map[string]interface{} `json:"bookmarks"`
+	}
+	if code := do(t, e, http.MethodGet, "/api/v1/bookmarks", registered.Token, nil, &bookmarks); code != http.StatusOK || len(bookmarks.Bookmarks) != 1 {
+		t.Errorf("bookmarks after upgrade: status %d with %d bookmarks, want 1", code, len(bookmarks.Bookmarks))
+	}
+	var stats struct {
+		TotalEntries int `json:"total_entries"`
+	}
+	if code := do(t, e, http.MethodGet, "/api/v1/journey/stats", registered.Token, nil, &stats); code != http.StatusOK || stats.TotalEntries != 1 {
+		t.Errorf("journey after upgrade: status %d with %d entries, want 1", code, stats.TotalEntries)
+	}
+	if code := do(t, e, http.MethodGet, "/api/v1/me", registered.Token, nil, nil); code != http.StatusOK {
+		t.Errorf("profile after upgrade: status %d", code)
+	}
+	credentials := map[string]string{"email": "guest-upgrade@demo.local", "password": "a-long-password"}
+	if code := do(t, e, http.MethodPost, "/api/v1/auth/login", "", credentials, nil); code != http.StatusOK {
+		t.Errorf("login after upgrade: status %d", code)
+	}
+}
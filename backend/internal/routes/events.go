@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/perinatal-mental-health-app/backend/internal/careteam"
+	db2 "github.com/perinatal-mental-health-app/backend/internal/db"
+	"github.com/perinatal-mental-health-app/backend/internal/encryption"
+	"github.com/perinatal-mental-health-app/backend/internal/events"
+	"github.com/perinatal-mental-health-app/backend/internal/httpcache"
+	"github.com/perinatal-mental-health-app/backend/internal/jobs"
+	"github.com/perinatal-mental-health-app/backend/internal/referrals"
+	"github.com/perinatal-mental-health-app/backend/internal/support_groups"
+	"github.com/perinatal-mental-health-app/backend/internal/webhooks"
+)
+
+// RegisterSubscribers wires the in-process domain event subscribers into the
+// dispatcher. Subscriber names are stored with each delivered event, so renaming
+// one causes it to receive events it has already handled.
+func RegisterSubscribers(dispatcher *events.Dispatcher, db *pgxpool.Pool, keyring *encryption.Keyring) {
+	// Subscribers act on events just written, so they read from the primary
+	primary := db2.NewHandle(db, nil, 0)
+	supportGroupsService := support_groups.NewService(support_groups.NewStore(primary))
+	catalogVersions := httpcache.NewStore(primary)
+	dispatcher.Subscribe(events.UserDeactivated, "support_groups.end_memberships", events.On(func(ctx context.Context, payload events.UserDeactivatedPayload) error {
+		if err := supportGroupsService.OnUserDeactivated(ctx, payload); err != nil {
+			return err
+		}
+		// Ended memberships change member counts in the cached catalog
+		_, err := catalogVersions.BumpVersion(ctx, httpcache.CollectionSupportGroups)
+		return err
+	}))
+
+	referralsService := referrals.NewService(referrals.NewStore(primary, keyring), careteam.NewService(careteam.NewStore(db, keyring)))
+	dispatcher.Subscribe(events.UserDeactivated, "referrals.cancel_outstanding", events.On(referralsService.OnUserDeactivated))
+
+	// Partner webhooks; deliveries are sent by the job worker
+	webhooksService := webhooks.NewService(webhooks.NewStore(db), keyring, jobs.NewService(jobs.NewStore(db)))
+	for _, eventType := range webhooks.EventTypes {
+		dispatcher.Subscribe(eventType, "webhooks.fan_out", webhooksService.OnEvent)
+	}
+}
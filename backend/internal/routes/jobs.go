@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/perinatal-mental-health-app/backend/internal/auth"
+	"github.com/perinatal-mental-health-app/backend/internal/careteam"
+	"github.com/perinatal-mental-health-app/backend/internal/encryption"
+	"github.com/perinatal-mental-health-app/backend/internal/idempotency"
+	"github.com/perinatal-mental-health-app/backend/internal/jobs"
+	"github.com/perinatal-mental-health-app/backend/internal/journey"
+	"github.com/perinatal-mental-health-app/backend/internal/mail"
+	"github.com/perinatal-mental-health-app/backend/internal/onboarding"
+	"github.com/perinatal-mental-health-app/backend/internal/privacy"
+	"github.com/perinatal-mental-health-app/backend/internal/user"
+	"github.com/perinatal-mental-health-app/backend/internal/webhooks"
+)
+
+// RegisterJobs wires the background job handlers into the worker. It builds its
+// own services so it can be used by both the API server and cmd/worker.
+func RegisterJobs(worker *jobs.Worker, db *pgxpool.Pool, keyring *encryption.Keyring, mailer mail.Sender) {
+	jobsService := jobs.NewService(jobs.NewStore(db))
+
+	privacyService := privacy.NewService(privacy.NewStore(db, keyring), jobsService)
+	worker.Register(privacy.JobCompileDataExport, jobs.Handle(privacyService.ProcessDataDownload))
+
+	journeyService := journey.NewService(journey.NewStore(db, keyring), jobsService)
+	worker.Register(journey.JobCheckMilestones, jobs.Handle(journeyService.CheckMilestones))
+
+	userService := user.NewService(user.NewStore(db, keyring), jobsService, careteam.NewService(careteam.NewStore(db, keyring)), mailer)
+	worker.Register(user.JobAnonymiseAccount, jobs.Handle(userService.AnonymiseAccount))
+	worker.Register(user.JobSendEmailChange, jobs.Handle(userService.SendEmailChange))
+
+	onboardingService := onboarding.NewService(onboarding.NewStore(db), jobsService, mailer)
+	worker.Register(onboarding.JobSendInvitation, jobs.Handle(onboardingService.SendInvitation))
+
+	// Expiring guests needs no token signing, so no JWT secret is configured
+	authService := auth.NewService(auth.NewStore(db, keyring), *auth.NewJWTService(""), jobsService)
+	worker.Register(auth.JobExpireGuest, jobs.Handle(authService.ExpireGuest))
+
+	webhooksService := webhooks.NewService(webhooks.NewStore(db), keyring, jobsService)
+	worker.Register(webhooks.JobDeliver, jobs.Handle(webhooksService.Deliver))
+
+	worker.Every(idempotency.TaskDeleteExpired, idempotency.DeleteExpiredEvery, deleteExpiredKeys(idempotency.NewStore(db, keyring)))
+}
+
+// deleteExpiredKeys purges idempotency records whose replay window has passed
+func deleteExpiredKeys(store idempotency.Store) jobs.TaskFunc {
+	return func(ctx context.Context) error {
+		_, err := store.DeleteExpired(ctx)
+		return err
+	}
+}
@@ -0,0 +1,394 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/perinatal-mental-health-app/backend/internal/audit"
+	"github.com/perinatal-mental-health-app/backend/internal/auth"
+	"github.com/perinatal-mental-health-app/backend/internal/bookmarks"
+	"github.com/perinatal-mental-health-app/backend/internal/careteam"
+	"github.com/perinatal-mental-health-app/backend/internal/caseload"
+	"github.com/perinatal-mental-health-app/backend/internal/catalog"
+	config "github.com/perinatal-mental-health-app/backend/internal/configs"
+	db2 "github.com/perinatal-mental-health-app/backend/internal/db"
+	"github.com/perinatal-mental-health-app/backend/internal/emergencycontacts"
+	"github.com/perinatal-mental-health-app/backend/internal/encryption"
+	"github.com/perinatal-mental-health-app/backend/internal/feedback"
+	"github.com/perinatal-mental-health-app/backend/internal/health"
+	"github.com/perinatal-mental-health-app/backend/internal/hsds"
+	"github.com/perinatal-mental-health-app/backend/internal/jobs"
+	"github.com/perinatal-mental-health-app/backend/internal/journey"
+	"github.com/perinatal-mental-health-app/backend/internal/onboarding"
+	"github.com/perinatal-mental-health-app/backend/internal/openapi"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/perinatal"
+	"github.com/perinatal-mental-health-app/backend/internal/privacy"
+	"github.com/perinatal-mental-health-app/backend/internal/referrals"
+	"github.com/perinatal-mental-health-app/backend/internal/resources"
+	"github.com/perinatal-mental-health-app/backend/internal/services"
+	"github.com/perinatal-mental-health-app/backend/internal/support_groups"
+	"github.com/perinatal-mental-health-app/backend/internal/supporters"
+	"github.com/perinatal-mental-health-app/backend/internal/user"
+	"github.com/perinatal-mental-health-app/backend/internal/webhooks"
+)
+
+// APIDocument builds the OpenAPI description of every /api/v1 route that
+// Register mounts. The operations come from the registered routes; apiOperations
+// only adds what echo can't know about a route, such as its request and
+// response models.
+func APIDocument() *openapi.Document {
+	e := echo.New()
+	master, _ := encryption.NewMasterKey(make([]byte, 32))
+	keyring := encryption.NewKeyring(encryption.NewStore(nil), []encryption.MasterKey{master}, make([]byte, 32))
+	// Routes are only registered to be described, so nothing sends email
+	Register(e, db2.NewHandle(nil, nil, 0), &config.Config{}, keyring, encryption.NewReencryptor(encryption.NewStore(nil), keyring), nil)
+
+	return documentRoutes(e.Routes())
+}
+
+// serveAPIDocument serves the description of the routes registered on e. The
+// document is built on first request, once every route is mounted.
+func serveAPIDocument(e *echo.Echo) echo.HandlerFunc {
+	document := sync.OnceValue(func() *openapi.Document {
+		return documentRoutes(e.Routes())
+	})
+
+	return func(c echo.Context) error {
+		return openapi.Handler(document())(c)
+	}
+}
+
+// documentRoutes describes each /api/v1 route with its apiOperations entry
+func documentRoutes(routes []*echo.Route) *openapi.Document {
+	info := openapi.Info{
+		Title:       "Perinatal Mental Health API",
+		Description: "Backend API for the perinatal mental health app",
+		Version:     "1.0.0",
+	}
+
+	described := apiOperations()
+	var operations []openapi.Operation
+	for _, route := range routes {
+		path, ok := strings.CutPrefix(route.Path, apiPrefix)
+		if !ok || route.Method == echo.RouteNotFound {
+			continue
+		}
+
+		operation := described[route.Method+" "+path]
+		operation.Method, operation.Path = route.Method, path
+		operations = append(operations, operation)
+	}
+
+	return openapi.Build(info, apiPrefix, operations, apiModels()...)
+}
+
+// apiModels lists models that are part of the API but not referenced directly
+// by an operation, e.g. request types that are only bound from query parameters
+func apiModels() []interface{} {
+	return []interface{}{
+		openapi.MessageResponse{},
+		health.Response{},
+		audit.ListEntriesRequest{},
+		caseload.ListCaseloadRequest{},
+		jobs.ListJobsRequest{},
+		feedback.UpdateFeedbackRequest{},
+		journey.ListJourneyEntriesRequest{},
+		referrals.ListReferralsRequest{},
+		referrals.UserSearchRequest{},
+		resources.ListResourcesRequest{},
+		services.ListServicesRequest{},
+		services.SearchServicesRequest{},
+		user.ChangePasswordRequest{},
+		webhooks.ListDeliveriesRequest{},
+	}
+}
+
+// Inline list bodies returned as {"<items>": [...], "total": n}
+type (
+	userList struct {
+		Users []user.UserResponse `json:"users"`
+		Total int                 `json:"total"`
+	}
+	dataRequestList struct {
+		Requests []privacy.DataRequest `json:"requests"`
+		Total    int                   `json:"total"`
+	}
+	resourceList struct {
+		Resources []resources.Resource `json:"resources"`
+		Total     int                  `json:"total"`
+	}
+	groupList struct {
+		Groups []support_groups.SupportGroup `json:"groups"`
+		Total  int                           `json:"total"`
+	}
+	memberList struct {
+		Members []support_groups.GroupMembership `json:"members"`
+		Total   int                              `json:"total"`
+	}
+	referralList struct {
+		Referrals []referrals.Referral `json:"referrals"`
+		Total     int                  `json:"total"`
+	}
+	goalList struct {
+		Goals []journey.JourneyGoal `json:"goals"`
+		Total int                   `json:"total"`
+	}
+	milestoneList struct {
+		Milestones []journey.JourneyMilestone `json:"milestones"`
+		Total      int                        `json:"total"`
+	}
+)
+
+// Inline request bodies bound by handlers
+type (
+	referralStatusRequest struct {
+		Status string `json:"status" validate:"required,oneof=pending accepted declined viewed"`
+	}
+	feedbackStatusRequest struct {
+		IsActive bool `json:"is_active"`
+	}
+)
+
+// apiOperations describes each route, keyed by its method and echo path
+// relative to /api/v1. The contract tests fail when a registered route has no
+// entry or an entry names a route that isn't registered.
+func apiOperations() map[string]openapi.Operation {
+	message := openapi.MessageResponse{}
+	staff := []string{"nhs_staff", "professional"}
+
+	page := openapi.PageParams
+	withCursor := func(params ...openapi.Parameter) []openapi.Parameter {
+		out := append([]openapi.Parameter{}, page...)
+		out = append(out, params...)
+		return append(out, openapi.CursorParams...)
+	}
+	withPage := func(params ...openapi.Parameter) []openapi.Parameter {
+		return append(append([]openapi.Parameter{}, params...), page...)
+	}
+	q := openapi.QueryString
+	limit := openapi.QueryInt("limit")
+	orgFilter := q("organisation_id")
+	auditFilters := []openapi.Parameter{q("actor_id"), q("action"), q("target_type"), q("target_id"), q("request_id"), q("from"), q("to")}
+
+	return map[string]openapi.Operation{
+		// Docs
+		"GET /openapi.json": {Summary: "OpenAPI description of this API", Tag: "docs"},
+
+		// Audit
+		"GET /admin/audit":        {Summary: "List audit log entries (super admin)", Tag: "audit", Auth: true, Roles: staff, Query: withCursor(auditFilters...), Response: audit.ListEntriesResponse{}},
+		"GET /admin/audit/export": {Summary: "Export audit log entries as JSON or CSV (super admin)", Tag: "audit", Auth: true, Roles: staff, Query: append([]openapi.Parameter{q("format")}, auditFilters...), Response: []audit.Entry{}},
+		"GET /admin/audit/verify": {Summary: "Verify the audit log hash chain (super admin)", Tag: "audit", Auth: true, Roles: staff, Response: audit.VerifyChainResponse{}},
+
+		// Organisations
+		"GET /admin/organisations":                         {Summary: "List the caller's organisations, or all for super admins", Tag: "organisations", Auth: true, Roles: staff, Response: organisations.ListOrganisationsResponse{}},
+		"GET /admin/organisations/scope":                   {Summary: "Show the organisations admin views are scoped to", Tag: "organisations", Auth: true, Roles: staff, Query: []openapi.Parameter{orgFilter}, Response: organisations.Scope{}},
+		"GET /admin/organisations/:id":                     {Summary: "Get an organisation", Tag: "organisations", Auth: true, Roles: staff, Response: organisations.Organisation{}},
+		"POST /admin/organisations":                        {Summary: "Create an organisation (super admin)", Tag: "organisations", Auth: true, Roles: staff, Request: organisations.CreateOrganisationRequest{}, Response: organisations.Organisation{}, Status: http.StatusCreated},
+		"PUT /admin/organisations/:id":                     {Summary: "Update an organisation (super admin)", Tag: "organisations", Auth: true, Roles: staff, Request: organisations.UpdateOrganisationRequest{}, Response: organisations.Organisation{}},
+		"GET /admin/organisations/:id/members":             {Summary: "List an organisation's staff", Tag: "organisations", Auth: true, Roles: staff, Response: organisations.ListMembersResponse{}},
+		"POST /admin/organisations/:id/members":            {Summary: "Add a staff member to an organisation (super admin or organisation admin)", Tag: "organisations", Auth: true, Roles: staff, Request: organisations.AddMemberRequest{}, Response: organisations.Member{}, Status: http.StatusCreated},
+		"DELETE /admin/organisations/:id/members/:user_id": {Summary: "Remove a staff member from an organisation (super admin or organisation admin)", Tag: "organisations", Auth: true, Roles: staff, Response: message},
+
+		// Field encryption
+		"GET /admin/encryption/status":  {Summary: "Show data keys and re-encryption progress (super admin)", Tag: "encryption", Auth: true, Roles: staff, Response: encryption.KeyStatusResponse{}},
+		"POST /admin/encryption/rotate": {Summary: "Rotate the active data key (super admin)", Tag: "encryption", Auth: true, Roles: staff, Response: encryption.RotateKeyResponse{}},
+
+		// Background jobs
+		"GET /admin/jobs":            {Summary: "List background jobs (super admin)", Tag: "jobs", Auth: true, Roles: staff, Query: withCursor(q("queue"), q("status"), q("job_type")), Response: jobs.ListJobsResponse{}},
+		"GET /admin/jobs/stats":      {Summary: "Count jobs per queue and status (super admin)", Tag: "jobs", Auth: true, Roles: staff, Response: jobs.QueueStatsResponse{}},
+		"GET /admin/jobs/:id":        {Summary: "Get a background job (super admin)", Tag: "jobs", Auth: true, Roles: staff, Response: jobs.Job{}},
+		"POST /admin/jobs/:id/retry": {Summary: "Retry a dead-lettered job (super admin)", Tag: "jobs", Auth: true, Roles: staff, Response: jobs.Job{}},
+
+		// Webhooks
+		"GET /admin/webhooks":                                     {Summary: "List webhook subscriptions for the caller's organisations", Tag: "webhooks", Auth: true, Roles: staff, Query: []openapi.Parameter{orgFilter}, Response: webhooks.ListSubscriptionsResponse{}},
+		"POST /admin/webhooks":                                    {Summary: "Create a webhook subscription; the signing secret is only returned here", Tag: "webhooks", Auth: true, Roles: staff, Request: webhooks.CreateSubscriptionRequest{}, Response: webhooks.SubscriptionSecretResponse{}, Status: http.StatusCreated},
+		"GET /admin/webhooks/:id":                                 {Summary: "Get a webhook subscription", Tag: "webhooks", Auth: true, Roles: staff, Response: webhooks.Subscription{}},
+		"PUT /admin/webhooks/:id":                                 {Summary: "Update a webhook subscription", Tag: "webhooks", Auth: true, Roles: staff, Request: webhooks.UpdateSubscriptionRequest{}, Response: webhooks.Subscription{}},
+		"DELETE /admin/webhooks/:id":                              {Summary: "Delete a webhook subscription", Tag: "webhooks", Auth: true, Roles: staff, Response: message},
+		"POST /admin/webhooks/:id/rotate-secret":                  {Summary: "Rotate the signing secret; the previous secret keeps signing for 24 hours", Tag: "webhooks", Auth: true, Roles: staff, Response: webhooks.SubscriptionSecretResponse{}},
+		"POST /admin/webhooks/:id/ping":                           {Summary: "Send a signed test delivery", Tag: "webhooks", Auth: true, Roles: staff, Response: webhooks.Delivery{}, Status: http.StatusAccepted},
+		"GET /admin/webhooks/:id/deliveries":                      {Summary: "List a subscription's delivery log", Tag: "webhooks", Auth: true, Roles: staff, Query: withCursor(q("status")), Response: webhooks.ListDeliveriesResponse{}},
+		"POST /admin/webhooks/:id/deliveries/:delivery_id/replay": {Summary: "Send a past delivery again", Tag: "webhooks", Auth: true, Roles: staff, Response: webhooks.Delivery{}, Status: http.StatusAccepted},
+
+		// Auth
+		"POST /auth/register":        {Summary: "Register a new account, keeping a guest's data when sent with their guest token", Tag: "auth", Request: auth.RegisterRequest{}, Response: auth.AuthResponse{}, Status: http.StatusCreated},
+		"POST /auth/guest":           {Summary: "Start an anonymous guest session", Tag: "auth", Response: auth.AuthResponse{}, Status: http.StatusCreated},
+		"POST /auth/login":           {Summary: "Log in", Tag: "auth", Request: auth.LoginRequest{}, Response: auth.AuthResponse{}},
+		"POST /auth/refresh":         {Summary: "Refresh an access token", Tag: "auth", Request: auth.RefreshTokenRequest{}, Response: auth.AuthResponse{}},
+		"POST /auth/forgot-password": {Summary: "Start a password reset", Tag: "auth", Request: auth.ForgotPasswordRequest{}, Response: message},
+		"POST /auth/reset-password":  {Summary: "Reset a password with a reset token", Tag: "auth", Request: auth.ResetPasswordRequest{}, Response: message},
+		"POST /auth/change-password": {Summary: "Change the current user's password", Tag: "auth", Auth: true, Request: auth.ChangePasswordRequest{}, Response: message},
+
+		// Users
+		"POST /users":                       {Summary: "Create a user", Tag: "users", Request: user.CreateUserRequest{}, Response: user.UserResponse{}, Status: http.StatusCreated},
+		"GET /users":                        {Summary: "List users", Tag: "users", Auth: true, Roles: staff, Query: withPage(q("role"), q("status")), Response: user.ListUsersResponse{}},
+		"GET /users/search":                 {Summary: "Search users; service users only within the caller's care team", Tag: "users", Auth: true, Roles: staff, Query: []openapi.Parameter{q("q"), limit, q("role")}, Response: userList{}},
+		"GET /users/:id":                    {Summary: "Get a user", Tag: "users", Auth: true, Response: user.UserResponse{}},
+		"GET /users/:id/profile":            {Summary: "Get a user's profile", Tag: "users", Auth: true, Response: user.UserProfileResponse{}},
+		"PUT /users/:id":                    {Summary: "Update a user", Tag: "users", Auth: true, Roles: staff, Request: user.UpdateUserRequest{}, Response: user.UserResponse{}},
+		"DELETE /users/:id":                 {Summary: "Deactivate a user", Tag: "users", Auth: true, Roles: staff, Request: user.AccountStatusRequest{}, Response: message},
+		"GET /users/:id/status":             {Summary: "Get a user's account status and its history", Tag: "users", Auth: true, Roles: staff, Response: user.AccountStatusResponse{}},
+		"POST /users/:id/suspend":           {Summary: "Suspend a user", Tag: "users", Auth: true, Roles: staff, Request: user.AccountStatusRequest{}, Response: user.AccountStatusResponse{}},
+		"POST /users/:id/reactivate":        {Summary: "Reactivate a suspended user or cancel their deletion", Tag: "users", Auth: true, Roles: staff, Request: user.AccountStatusRequest{}, Response: user.AccountStatusResponse{}},
+		"POST /users/:id/schedule-deletion": {Summary: "Schedule a user's erasure after the grace period", Tag: "users", Auth: true, Roles: []string{"nhs_staff"}, Request: user.AccountStatusRequest{}, Response: user.AccountStatusResponse{}},
+
+		// Staff onboarding
+		"POST /admin/users/import": {Summary: "Create staff accounts from a CSV file with email, name, role and organisation columns sent as the body, and email their invitations", Tag: "users", Auth: true, Roles: staff, Query: []openapi.Parameter{openapi.QueryBool("dry_run")}, Response: onboarding.ImportReport{}},
+		"POST /invitations/accept": {Summary: "Choose a password for an imported staff account", Tag: "users", Request: onboarding.AcceptInvitationRequest{}, Response: message},
+
+		// Staff user administration
+		"PUT /admin/users/:id/role":   {Summary: "Change a user's role and revoke their tokens", Tag: "users", Auth: true, Roles: []string{"nhs_staff"}, Request: user.ChangeRoleRequest{}, Response: user.UserResponse{}},
+		"POST /admin/users/:id/email": {Summary: "Start changing a user's email; a token to confirm it is emailed to the new address", Tag: "users", Auth: true, Roles: []string{"nhs_staff"}, Request: user.ChangeEmailRequest{}, Response: user.EmailChangeResponse{}, Status: http.StatusAccepted},
+		"POST /email-changes/confirm": {Summary: "Confirm an email change from the new address", Tag: "users", Request: user.ConfirmEmailChangeRequest{}, Response: user.UserResponse{}},
+		"GET /admin/users/:id/merge":  {Summary: "Preview merging a duplicate account into another", Tag: "users", Auth: true, Roles: []string{"nhs_staff"}, Query: []openapi.Parameter{q("into")}, Response: user.MergePreviewResponse{}},
+		"POST /admin/users/:id/merge": {Summary: "Merge a duplicate account into another and close it", Tag: "users", Auth: true, Roles: []string{"nhs_staff"}, Request: user.MergeUsersRequest{}, Response: user.AccountMerge{}},
+
+		// Current user
+		"GET /me":                 {Summary: "Get the current user's profile", Tag: "me", Auth: true, Response: user.UserProfileResponse{}},
+		"PUT /me":                 {Summary: "Update the current user", Tag: "me", Auth: true, Request: user.UpdateUserRequest{}, Response: user.UserResponse{}},
+		"POST /me/last-login":     {Summary: "Record a login", Tag: "me", Auth: true, Response: message},
+		"GET /me/preferences":     {Summary: "Get the current user's preferences", Tag: "me", Auth: true, Response: user.Preferences{}},
+		"PUT /me/preferences":     {Summary: "Replace the current user's preferences", Tag: "me", Auth: true, Request: user.Preferences{}, Response: user.Preferences{}},
+		"GET /me/perinatal":       {Summary: "Get the current user's pregnancy and birth details and stage", Tag: "me", Auth: true, Response: user.PerinatalResponse{}},
+		"PUT /me/perinatal":       {Summary: "Replace the current user's pregnancy and birth details", Tag: "me", Auth: true, Request: perinatal.Details{}, Response: user.PerinatalResponse{}},
+		"GET /preferences/schema": {Summary: "JSON Schema for preferences documents", Tag: "docs"},
+
+		// Care teams
+		"GET /me/care-team":             {Summary: "List the current user's care team", Tag: "care-team", Auth: true, Response: careteam.ListRelationshipsResponse{}},
+		"POST /me/care-team/:id/accept": {Summary: "Accept a care-team invitation", Tag: "care-team", Auth: true, Response: careteam.Relationship{}},
+		"PUT /me/care-team/:id":         {Summary: "Change what is shared with a care-team member", Tag: "care-team", Auth: true, Request: careteam.UpdateSharingRequest{}, Response: careteam.Relationship{}},
+		"POST /me/care-team/:id/end":    {Summary: "Decline an invitation or remove a care-team member", Tag: "care-team", Auth: true, Request: careteam.EndRelationshipRequest{}, Response: careteam.Relationship{}},
+		"GET /care-team":                {Summary: "List the caller's care-team relationships", Tag: "care-team", Auth: true, Roles: staff, Query: []openapi.Parameter{q("status")}, Response: careteam.ListRelationshipsResponse{}},
+		"POST /care-team/invitations":   {Summary: "Invite a service user to add the caller to their care team", Tag: "care-team", Auth: true, Roles: staff, Request: careteam.InviteRequest{}, Response: careteam.Relationship{}, Status: http.StatusCreated},
+		"POST /care-team/:id/end":       {Summary: "Withdraw an invitation or leave a care team", Tag: "care-team", Auth: true, Roles: staff, Request: careteam.EndRelationshipRequest{}, Response: careteam.Relationship{}},
+		"POST /care-team/break-glass":   {Summary: "Declare time-limited emergency access to a service user", Tag: "care-team", Auth: true, Roles: staff, Request: careteam.BreakGlassRequest{}, Response: careteam.BreakGlassGrant{}, Status: http.StatusCreated},
+		"GET /caseload":                 {Summary: "List the caller's caseload with check-ins, outstanding referrals and group sessions", Tag: "care-team", Auth: true, Roles: staff, Query: []openapi.Parameter{openapi.QueryBool("urgent"), openapi.QueryInt("no_check_in_days"), q("sort"), q("order")}, Response: caseload.ListCaseloadResponse{}},
+		"GET /admin/care-team":          {Summary: "List care-team relationships (super admin)", Tag: "care-team", Auth: true, Roles: staff, Query: []openapi.Parameter{q("professional_id"), q("service_user_id"), q("status")}, Response: careteam.ListRelationshipsResponse{}},
+		"POST /admin/care-team":         {Summary: "Assign a professional to a service user's care team (super admin)", Tag: "care-team", Auth: true, Roles: staff, Request: careteam.AssignRequest{}, Response: careteam.Relationship{}, Status: http.StatusCreated},
+		"POST /admin/care-team/:id/end": {Summary: "End a care-team relationship (super admin)", Tag: "care-team", Auth: true, Roles: staff, Request: careteam.EndRelationshipRequest{}, Response: careteam.Relationship{}},
+
+		// Emergency contacts
+		"GET /me/emergency-contacts":        {Summary: "List the current user's emergency contacts", Tag: "emergency-contacts", Auth: true, Response: emergencycontacts.ListContactsResponse{}},
+		"POST /me/emergency-contacts":       {Summary: "Add an emergency contact", Tag: "emergency-contacts", Auth: true, Request: emergencycontacts.CreateContactRequest{}, Response: emergencycontacts.Contact{}, Status: http.StatusCreated},
+		"GET /me/emergency-contacts/:id":    {Summary: "Get an emergency contact", Tag: "emergency-contacts", Auth: true, Response: emergencycontacts.Contact{}},
+		"PUT /me/emergency-contacts/:id":    {Summary: "Update an emergency contact and the care team's permission to contact them", Tag: "emergency-contacts", Auth: true, Request: emergencycontacts.UpdateContactRequest{}, Response: emergencycontacts.Contact{}},
+		"DELETE /me/emergency-contacts/:id": {Summary: "Remove an emergency contact", Tag: "emergency-contacts", Auth: true, Response: message},
+		"GET /users/:id/emergency-contacts": {Summary: "List the emergency contacts a service user lets their care team get in touch with", Tag: "emergency-contacts", Auth: true, Roles: staff, Response: emergencycontacts.ListContactsResponse{}},
+
+		// Supporters
+		"GET /me/supporters":             {Summary: "List the current user's supporters and invitations", Tag: "supporters", Auth: true, Response: supporters.ListLinksResponse{}},
+		"POST /me/supporters":            {Summary: "Create an invitation code for a partner or family member", Tag: "supporters", Auth: true, Request: supporters.InviteRequest{}, Response: supporters.InvitationResponse{}, Status: http.StatusCreated},
+		"PUT /me/supporters/:id":         {Summary: "Change what the current user shares with a supporter", Tag: "supporters", Auth: true, Request: supporters.UpdateSharesRequest{}, Response: supporters.Link{}},
+		"POST /me/supporters/:id/revoke": {Summary: "Remove a supporter or withdraw an invitation", Tag: "supporters", Auth: true, Response: supporters.Link{}},
+		"GET /me/crisis-plan":            {Summary: "Get the current user's crisis plan", Tag: "supporters", Auth: true, Response: supporters.CrisisPlan{}},
+		"PUT /me/crisis-plan":            {Summary: "Replace the current user's crisis plan", Tag: "supporters", Auth: true, Request: supporters.UpdateCrisisPlanRequest{}, Response: supporters.CrisisPlan{}},
+		"GET /supporting":                {Summary: "List the people the current user supports", Tag: "supporters", Auth: true, Response: supporters.ListLinksResponse{}},
+		"POST /supporting/accept":        {Summary: "Become a supporter with an invitation code", Tag: "supporters", Auth: true, Request: supporters.AcceptRequest{}, Response: supporters.Link{}},
+		"GET /supporting/:id":            {Summary: "See what a person the current user supports shares with them", Tag: "supporters", Auth: true, Response: supporters.SupporterView{}},
+		"POST /supporting/:id/revoke":    {Summary: "Stop being someone's supporter", Tag: "supporters", Auth: true, Response: supporters.Link{}},
+
+		// Privacy
+		"GET /privacy/preferences":               {Summary: "Get privacy preferences", Tag: "privacy", Auth: true, Response: privacy.PrivacyPreferences{}},
+		"PUT /privacy/preferences":               {Summary: "Update privacy preferences", Tag: "privacy", Auth: true, Request: privacy.UpdatePrivacyPreferencesRequest{}, Response: message},
+		"POST /privacy/request-data-download":    {Summary: "Request a copy of personal data", Tag: "privacy", Auth: true, Response: message},
+		"POST /privacy/request-account-deletion": {Summary: "Request account deletion", Tag: "privacy", Auth: true, Request: privacy.AccountDeletionRequest{}, Response: message},
+		"GET /privacy/data-retention-info":       {Summary: "Get data retention information", Tag: "privacy", Auth: true, Response: privacy.DataRetentionInfo{}},
+		"GET /privacy/export-data":               {Summary: "Export personal data", Tag: "privacy", Auth: true, Response: privacy.DataExportResponse{}},
+		"GET /privacy/data-requests":             {Summary: "List data requests", Tag: "privacy", Auth: true, Response: dataRequestList{}},
+
+		// Services
+		"GET /services":              {Summary: "List services", Tag: "services", Query: withCursor(q("service_type"), q("location"), q("search"), q("stage")), Response: services.ListServicesResponse{}},
+		"GET /services/search":       {Summary: "Search services", Tag: "services", Query: withPage(q("q")), Response: services.ListServicesResponse{}},
+		"GET /services/:id":          {Summary: "Get a service", Tag: "services", Response: services.ServicesModel{}},
+		"GET /services/featured":     {Summary: "List featured services", Tag: "services", Query: []openapi.Parameter{limit}, Response: services.ListServicesResponse{}},
+		"POST /admin/services":       {Summary: "Create a service", Tag: "services", Auth: true, Roles: staff, Request: services.CreateServiceRequest{}, Response: services.ServicesModel{}, Status: http.StatusCreated},
+		"PUT /admin/services/:id":    {Summary: "Update a service", Tag: "services", Auth: true, Roles: staff, Request: services.UpdateServiceRequest{}, Response: services.ServicesModel{}},
+		"DELETE /admin/services/:id": {Summary: "Delete a service", Tag: "services", Auth: true, Roles: staff, Response: message},
+		"GET /admin/services/stats":  {Summary: "Get statistics for the caller's organisations' services", Tag: "services", Auth: true, Roles: staff, Query: []openapi.Parameter{orgFilter}, Response: services.ServiceStats{}},
+
+		// Resources
+		"GET /resources":                            {Summary: "List resources", Tag: "resources", Query: withCursor(openapi.QueryBool("featured"), q("search"), q("resource_type"), q("target_audience"), q("tags"), q("stage")), Response: resources.ListResourcesResponse{}},
+		"GET /resources/search":                     {Summary: "Search resources", Tag: "resources", Query: withPage(q("q")), Response: resources.ListResourcesResponse{}},
+		"GET /resources/:id":                        {Summary: "Get a resource", Tag: "resources", Response: resources.Resource{}},
+		"GET /resources/featured":                   {Summary: "List featured resources", Tag: "resources", Query: []openapi.Parameter{limit}, Response: resourceList{}},
+		"GET /resources/popular":                    {Summary: "List popular resources", Tag: "resources", Query: []openapi.Parameter{limit}, Response: resourceList{}},
+		"GET /resources/by-tag":                     {Summary: "List resources with a tag", Tag: "resources", Query: withPage(q("tag")), Response: resources.ListResourcesResponse{}},
+		"GET /resources/by-audience":                {Summary: "List resources for an audience", Tag: "resources", Query: withPage(q("audience")), Response: resources.ListResourcesResponse{}},
+		"POST /resources/:id/view":                  {Summary: "Record a resource view", Tag: "resources", Response: message},
+		"POST /admin/resources":                     {Summary: "Create a resource", Tag: "resources", Auth: true, Roles: staff, Request: resources.CreateResourceRequest{}, Response: resources.Resource{}, Status: http.StatusCreated},
+		"PUT /admin/resources/:id":                  {Summary: "Update a resource", Tag: "resources", Auth: true, Roles: staff, Request: resources.UpdateResourceRequest{}, Response: resources.Resource{}},
+		"DELETE /admin/resources/:id":               {Summary: "Delete a resource", Tag: "resources", Auth: true, Roles: staff, Response: message},
+		"POST /admin/resources/:id/toggle-featured": {Summary: "Toggle whether a resource is featured", Tag: "resources", Auth: true, Roles: staff, Response: message},
+		"GET /admin/resources/stats":                {Summary: "Get resource statistics", Tag: "resources", Auth: true, Roles: staff, Response: resources.ResourceStats{}},
+
+		// Support groups
+		"GET /support-groups":                               {Summary: "List support groups", Tag: "support-groups", Query: withPage(q("category"), q("platform"), q("stage"), q("search")), Response: support_groups.ListSupportGroupsResponse{}},
+		"GET /support-groups/search":                        {Summary: "Search support groups", Tag: "support-groups", Query: withPage(q("q")), Response: support_groups.ListSupportGroupsResponse{}},
+		"GET /support-groups/:id":                           {Summary: "Get a support group", Tag: "support-groups", Response: support_groups.SupportGroup{}},
+		"GET /support-groups/by-category":                   {Summary: "List support groups in a category", Tag: "support-groups", Query: withPage(q("category")), Response: support_groups.ListSupportGroupsResponse{}},
+		"GET /support-groups/by-platform":                   {Summary: "List support groups on a platform", Tag: "support-groups", Query: withPage(q("platform")), Response: support_groups.ListSupportGroupsResponse{}},
+		"POST /support-groups/join":                         {Summary: "Join a support group", Tag: "support-groups", Auth: true, Request: support_groups.JoinGroupRequest{}, Response: message},
+		"DELETE /support-groups/:id/leave":                  {Summary: "Leave a support group", Tag: "support-groups", Auth: true, Response: message},
+		"GET /support-groups/:id/members":                   {Summary: "List members of a support group", Tag: "support-groups", Auth: true, Response: memberList{}},
+		"GET /my-groups":                                    {Summary: "List the current user's support groups", Tag: "support-groups", Auth: true, Response: groupList{}},
+		"POST /admin/support-groups":                        {Summary: "Create a support group", Tag: "support-groups", Auth: true, Roles: staff, Request: support_groups.CreateSupportGroupRequest{}, Response: support_groups.SupportGroup{}, Status: http.StatusCreated},
+		"PUT /admin/support-groups/:id":                     {Summary: "Update a support group", Tag: "support-groups", Auth: true, Roles: staff, Request: support_groups.UpdateSupportGroupRequest{}, Response: support_groups.SupportGroup{}},
+		"DELETE /admin/support-groups/:id":                  {Summary: "Delete a support group", Tag: "support-groups", Auth: true, Roles: staff, Response: message},
+		"DELETE /admin/support-groups/:id/members/:user_id": {Summary: "Remove a member from a support group", Tag: "support-groups", Auth: true, Roles: staff, Response: message},
+		"GET /admin/support-groups/stats":                   {Summary: "Get support group statistics", Tag: "support-groups", Auth: true, Roles: staff, Response: support_groups.SupportGroupStats{}},
+
+		// Catalog import and export
+		"POST /admin/catalog/:collection/import": {Summary: "Create or update services, resources or support groups from a CSV or JSON file sent as the body, matched by external_ref", Tag: "catalog", Auth: true, Roles: staff, Query: []openapi.Parameter{q("format"), openapi.QueryBool("dry_run")}, Response: catalog.ImportReport{}},
+		"GET /admin/catalog/:collection/export":  {Summary: "Export every active item in a collection as CSV or JSON in the import format", Tag: "catalog", Auth: true, Roles: staff, Query: []openapi.Parameter{q("format")}, Response: []map[string]interface{}{}},
+
+		// Open Referral HSDS
+		"GET /hsds/services":     {Summary: "List active services in the Open Referral HSDS format", Tag: "hsds", Query: []openapi.Parameter{openapi.QueryInt("page"), openapi.QueryInt("per_page")}, Response: hsds.ServicePage{}},
+		"GET /hsds/services/:id": {Summary: "Get a service in the Open Referral HSDS format", Tag: "hsds", Response: hsds.ServiceRecord{}},
+
+		// Referrals
+		"POST /referrals":             {Summary: "Create a referral", Tag: "referrals", Auth: true, Roles: staff, Request: referrals.CreateReferralRequest{}, Response: referrals.Referral{}, Status: http.StatusCreated},
+		"GET /referrals":              {Summary: "List sent referrals for staff, received referrals otherwise", Tag: "referrals", Auth: true, Query: withCursor(openapi.QueryBool("is_urgent"), q("status"), q("referral_type")), Response: referrals.ListReferralsResponse{}},
+		"GET /referrals/sent":         {Summary: "List sent referrals", Tag: "referrals", Auth: true, Roles: staff, Query: withCursor(openapi.QueryBool("is_urgent"), q("status"), q("referral_type")), Response: referrals.ListReferralsResponse{}},
+		"GET /referrals/received":     {Summary: "List received referrals", Tag: "referrals", Auth: true, Query: withCursor(openapi.QueryBool("is_urgent"), q("status"), q("referral_type")), Response: referrals.ListReferralsResponse{}},
+		"GET /referrals/:id":          {Summary: "Get a referral", Tag: "referrals", Auth: true, Response: referrals.Referral{}},
+		"PUT /referrals/:id":          {Summary: "Update a referral", Tag: "referrals", Auth: true, Request: referrals.UpdateReferralRequest{}, Response: referrals.Referral{}},
+		"PUT /referrals/:id/status":   {Summary: "Update a referral's status", Tag: "referrals", Auth: true, Request: referralStatusRequest{}, Response: message},
+		"DELETE /referrals/:id":       {Summary: "Delete a referral", Tag: "referrals", Auth: true, Roles: staff, Response: message},
+		"GET /referrals/users/search": {Summary: "Search users to refer", Tag: "referrals", Auth: true, Roles: staff, Query: []openapi.Parameter{q("q"), limit, q("role")}, Response: referrals.UserSearchResponse{}},
+		"GET /referrals/by-item":      {Summary: "List referrals for an item", Tag: "referrals", Auth: true, Query: []openapi.Parameter{q("item_id"), q("item_type")}, Response: referralList{}},
+		"GET /referrals/stats":        {Summary: "Get referral statistics", Tag: "referrals", Auth: true, Roles: staff, Response: referrals.ReferralStats{}},
+		"GET /admin/referrals/stats":  {Summary: "Get referral statistics for the caller's organisations", Tag: "referrals", Auth: true, Roles: staff, Query: []openapi.Parameter{orgFilter}, Response: referrals.ReferralStats{}},
+
+		// Feedback
+		"POST /feedback":                 {Summary: "Submit feedback", Tag: "feedback", Request: feedback.CreateFeedbackRequest{}, Response: feedback.Feedback{}, Status: http.StatusCreated},
+		"GET /my-feedback":               {Summary: "List the current user's feedback", Tag: "feedback", Auth: true, Query: withCursor(), Response: feedback.ListFeedbackResponse{}},
+		"GET /admin/feedback":            {Summary: "List feedback about the caller's organisations", Tag: "feedback", Auth: true, Roles: staff, Query: withCursor(q("category"), openapi.QueryInt("rating"), orgFilter), Response: feedback.ListFeedbackResponse{}},
+		"GET /admin/feedback/stats":      {Summary: "Get feedback statistics", Tag: "feedback", Auth: true, Roles: staff, Response: feedback.FeedbackStats{}},
+		"GET /admin/feedback/:id":        {Summary: "Get feedback", Tag: "feedback", Auth: true, Roles: staff, Response: feedback.Feedback{}},
+		"PUT /admin/feedback/:id/status": {Summary: "Activate or deactivate feedback", Tag: "feedback", Auth: true, Roles: staff, Request: feedbackStatusRequest{}, Response: message},
+
+		// Journey
+		"POST /journey/entries":       {Summary: "Create a journey entry", Tag: "journey", Auth: true, Request: journey.CreateJourneyEntryRequest{}, Response: journey.JourneyEntry{}, Status: http.StatusCreated},
+		"GET /journey/entries":        {Summary: "List journey entries", Tag: "journey", Auth: true, Query: withCursor(q("start_date"), q("end_date")), Response: journey.ListJourneyEntriesResponse{}},
+		"GET /journey/entries/today":  {Summary: "Get today's journey entry", Tag: "journey", Auth: true, Response: journey.JourneyEntry{}},
+		"GET /journey/entries/:id":    {Summary: "Get a journey entry", Tag: "journey", Auth: true, Response: journey.JourneyEntry{}},
+		"PUT /journey/entries/:id":    {Summary: "Update a journey entry", Tag: "journey", Auth: true, Request: journey.UpdateJourneyEntryRequest{}, Response: journey.JourneyEntry{}},
+		"DELETE /journey/entries/:id": {Summary: "Delete a journey entry", Tag: "journey", Auth: true, Response: message},
+		"POST /journey/goals":         {Summary: "Create a journey goal", Tag: "journey", Auth: true, Request: journey.CreateJourneyGoalRequest{}, Response: journey.JourneyGoal{}, Status: http.StatusCreated},
+		"GET /journey/goals":          {Summary: "List journey goals", Tag: "journey", Auth: true, Query: []openapi.Parameter{q("status")}, Response: goalList{}},
+		"PUT /journey/goals/:id":      {Summary: "Update a journey goal", Tag: "journey", Auth: true, Request: journey.UpdateJourneyGoalRequest{}, Response: journey.JourneyGoal{}},
+		"DELETE /journey/goals/:id":   {Summary: "Delete a journey goal", Tag: "journey", Auth: true, Response: message},
+		"GET /journey/stats":          {Summary: "Get journey statistics", Tag: "journey", Auth: true, Response: journey.JourneyStats{}},
+		"GET /journey/insights":       {Summary: "Get journey insights", Tag: "journey", Auth: true, Response: journey.JourneyInsights{}},
+		"GET /journey/milestones":     {Summary: "List journey milestones", Tag: "journey", Auth: true, Query: []openapi.Parameter{limit}, Response: milestoneList{}},
+
+		// Bookmarks
+		"GET /bookmarks":        {Summary: "List the current user's bookmarks", Tag: "bookmarks", Auth: true, Query: []openapi.Parameter{q("item_type")}, Response: bookmarks.ListBookmarksResponse{}},
+		"POST /bookmarks":       {Summary: "Bookmark a service, resource or support group", Tag: "bookmarks", Auth: true, Request: bookmarks.CreateBookmarkRequest{}, Response: bookmarks.Bookmark{}, Status: http.StatusCreated},
+		"DELETE /bookmarks/:id": {Summary: "Remove a bookmark", Tag: "bookmarks", Auth: true, Response: message},
+	}
+}
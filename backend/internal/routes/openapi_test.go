@@ -0,0 +1,165 @@
+package routes
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	config "github.com/perinatal-mental-health-app/backend/internal/configs"
+	db2 "github.com/perinatal-mental-health-app/backend/internal/db"
+	"github.com/perinatal-mental-health-app/backend/internal/encryption"
+	"github.com/perinatal-mental-health-app/backend/internal/openapi"
+)
+
+func newTestServer(t *testing.T) *echo.Echo {
+	t.Helper()
+
+	e := echo.New()
+	master, err := encryption.NewMasterKey(make([]byte, 32))
+	if err != nil {
+		t.Fatalf("master key: %v", err)
+	}
+	keyring := encryption.NewKeyring(encryption.NewStore(nil), []encryption.MasterKey{master}, make([]byte, 32))
+	Register(e, db2.NewHandle(nil, nil, 0), &config.Config{JWTSecret: "test-secret", IdempotencyTTL: time.Hour}, keyring, encryption.NewReencryptor(encryption.NewStore(nil), keyring), nil)
+	return e
+}
+
+// Every route registered under /api/v1 must have an apiOperations entry, and
+// every entry must name a registered route
+func TestOpenAPICoversRegisteredRoutes(t *testing.T) {
+	e := newTestServer(t)
+	described := apiOperations()
+
+	registered := make(map[string]bool)
+	for _, route := range e.Routes() {
+		if !strings.HasPrefix(route.Path, apiPrefix+"/") || route.Method == echo.RouteNotFound {
+			continue
+		}
+		key := route.Method + " " + strings.TrimPrefix(route.Path, apiPrefix)
+		registered[key] = true
+
+		if _, ok := described[key]; !ok {
+			t.Errorf("route %s has no apiOperations entry", key)
+		}
+	}
+
+	for key := range described {
+		if !registered[key] {
+			t.Errorf("apiOperations describes %s but no such route is registered", key)
+		}
+	}
+
+	if got, want := len(APIDocument().Operations()), len(registered); got != want {
+		t.Errorf("document has %d operations, want one per registered route (%d)", got, want)
+	}
+}
+
+// Every exported *Request and *Response model must appear in the component schemas
+func TestOpenAPICoversModels(t *testing.T) {
+	doc := APIDocument()
+
+	files, err := filepath.Glob("../*/model.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fset := token.NewFileSet()
+	for _, file := range files {
+		parsed, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatalf("parse %s: %v", file, err)
+		}
+
+		for _, decl := range parsed.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if _, isStruct := typeSpec.Type.(*ast.StructType); !isStruct || !typeSpec.Name.IsExported() {
+					continue
+				}
+
+				name := typeSpec.Name.Name
+				if !strings.HasSuffix(name, "Request") && !strings.HasSuffix(name, "Response") {
+					continue
+				}
+
+				schema := parsed.Name.Name + "." + name
+				if _, ok := doc.Components.Schemas[schema]; !ok {
+					t.Errorf("model %s is missing from the OpenAPI component schemas", schema)
+				}
+			}
+		}
+	}
+}
+
+func TestOpenAPIReferencesResolve(t *testing.T) {
+	doc := APIDocument()
+
+	body, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const prefix = "#/components/schemas/"
+	for _, part := range strings.Split(string(body), `"$ref":"`)[1:] {
+		ref := part[:strings.Index(part, `"`)]
+		if _, ok := doc.Components.Schemas[strings.TrimPrefix(ref, prefix)]; !ok {
+			t.Errorf("unresolved schema reference %s", ref)
+		}
+	}
+}
+
+func TestOpenAPIEndpoint(t *testing.T) {
+	e := newTestServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	var doc struct {
+		OpenAPI string                     `json:"openapi"`
+		Paths   map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if doc.OpenAPI != openapi.Version {
+		t.Errorf("expected openapi %s, got %q", openapi.Version, doc.OpenAPI)
+	}
+	if _, ok := doc.Paths["/services/{id}"]; !ok {
+		t.Error("expected /services/{id} in paths")
+	}
+}
+
+// The checked-in copy under docs/ must match what the handlers generate
+func TestOpenAPIDocsUpToDate(t *testing.T) {
+	committed, err := os.ReadFile("../../../docs/api-docs/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generated, err := json.MarshalIndent(APIDocument(), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.TrimSpace(string(committed)) != strings.TrimSpace(string(generated)) {
+		t.Error("docs/api-docs/openapi.json is stale; run `make openapi`")
+	}
+}
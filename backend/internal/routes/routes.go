@@ -1,48 +1,267 @@
 package routes
 
 import (
+	"context"
+
 	"github.com/perinatal-mental-health-app/backend/internal/feedback"
+	"github.com/perinatal-mental-health-app/backend/internal/mail"
 	"github.com/perinatal-mental-health-app/backend/internal/user"
 	"net/http"
 	"strconv"
 
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/audit"
 	"github.com/perinatal-mental-health-app/backend/internal/auth"
+	"github.com/perinatal-mental-health-app/backend/internal/bookmarks"
+	"github.com/perinatal-mental-health-app/backend/internal/careteam"
+	"github.com/perinatal-mental-health-app/backend/internal/caseload"
+	"github.com/perinatal-mental-health-app/backend/internal/catalog"
 	config "github.com/perinatal-mental-health-app/backend/internal/configs"
+	db2 "github.com/perinatal-mental-health-app/backend/internal/db"
+	"github.com/perinatal-mental-health-app/backend/internal/emergencycontacts"
+	"github.com/perinatal-mental-health-app/backend/internal/encryption"
 	"github.com/perinatal-mental-health-app/backend/internal/health"
+	"github.com/perinatal-mental-health-app/backend/internal/hsds"
+	"github.com/perinatal-mental-health-app/backend/internal/httpcache"
+	"github.com/perinatal-mental-health-app/backend/internal/idempotency"
+	"github.com/perinatal-mental-health-app/backend/internal/jobs"
 	"github.com/perinatal-mental-health-app/backend/internal/journey"
 	custommiddleware "github.com/perinatal-mental-health-app/backend/internal/middleware"
+	"github.com/perinatal-mental-health-app/backend/internal/onboarding"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
 	"github.com/perinatal-mental-health-app/backend/internal/privacy"
 	"github.com/perinatal-mental-health-app/backend/internal/referrals"
 	"github.com/perinatal-mental-health-app/backend/internal/resources"
 	"github.com/perinatal-mental-health-app/backend/internal/services"
 	"github.com/perinatal-mental-health-app/backend/internal/support_groups"
+	"github.com/perinatal-mental-health-app/backend/internal/supporters"
+	"github.com/perinatal-mental-health-app/backend/internal/webhooks"
 )
 
-func Register(e *echo.Echo, db *pgxpool.Pool, cfg *config.Config) {
-	v1 := e.Group("/api/v1")
+// apiPrefix is where the versioned API is mounted
+const apiPrefix = "/api/v1"
+
+func Register(e *echo.Echo, dbHandle *db2.Handle, cfg *config.Config, keyring *encryption.Keyring, reencryptor *encryption.Reencryptor, mailer mail.Sender) {
+	// Stores outside the catalog and stats read paths always use the primary
+	db := dbHandle.Primary()
+
+	v1 := e.Group(apiPrefix)
+
+	// Reads just after a client's own write go to the primary while the replica catches up
+	if dbHandle.HasReplica() {
+		v1.Use(custommiddleware.ReadYourWrites(dbHandle, cfg.DBReplicaMaxLag))
+	}
 
 	e.GET("/health", health.Health)
 
-	// Initialize JWT service
+	// Machine-readable API description generated from the registered routes
+	v1.GET("/openapi.json", serveAPIDocument(e))
+
+	// Initialize JWT service; tokens are checked against the user's last revocation
+	authStore := auth.NewStore(db, keyring)
 	jwtService := auth.NewJWTService(cfg.JWTSecret)
+	jwtService.UseRevocations(authStore)
+
+	// Idempotency-Key support for endpoints mobile clients retry
+	idempotencyStore := idempotency.NewStore(db, keyring)
+	idempotent := custommiddleware.IdempotencyMiddleware(idempotencyStore, cfg.IdempotencyTTL)
+
+	// Collection versions back the ETags on the public catalog
+	catalogVersions := httpcache.NewStore(dbHandle)
+
+	// --- Organisations ---
+	organisationsStore := organisations.NewStore(db)
+	organisationsService := organisations.NewService(organisationsStore)
+	organisationsHandler := organisations.NewHandler(organisationsService)
+
+	// orgScoped limits admin and stats views to the caller's organisations
+	orgScoped := custommiddleware.OrganisationScope(organisationsService)
+
+	// --- Audit ---
+	auditStore := audit.NewStore(db)
+	auditService := audit.NewService(auditStore)
+	auditHandler := audit.NewHandler(auditService)
+
+	// audited records sensitive reads and privileged writes in the audit log.
+	// Snapshot loaders are added as each target's store is created.
+	snapshots := audit.Snapshots{}
+	audited := func(action, targetType, targetParam string) echo.MiddlewareFunc {
+		return custommiddleware.Audit(auditService, snapshots, action, targetType, targetParam)
+	}
+
+	// Admin routes for the audit log (require super admin)
+	adminAudit := v1.Group("/admin/audit")
+	adminAudit.Use(custommiddleware.JWTMiddleware(jwtService))
+	adminAudit.Use(custommiddleware.RoleMiddleware("nhs_staff", "professional"))
+	adminAudit.Use(orgScoped)
+	adminAudit.GET("", auditHandler.ListEntries, audited(audit.ActionAuditList, audit.TargetAuditLog, ""), custommiddleware.SuperAdminMiddleware())
+	adminAudit.GET("/export", auditHandler.ExportEntries, audited(audit.ActionAuditExport, audit.TargetAuditLog, ""), custommiddleware.SuperAdminMiddleware())
+	adminAudit.GET("/verify", auditHandler.VerifyChain, audited(audit.ActionAuditVerify, audit.TargetAuditLog, ""), custommiddleware.SuperAdminMiddleware())
+
+	// Admin routes for organisations (require staff/professional role)
+	snapshots[audit.TargetOrganisation] = snapshotOf(organisationsStore.GetOrganisation)
+	adminOrganisations := v1.Group("/admin/organisations")
+	adminOrganisations.Use(custommiddleware.JWTMiddleware(jwtService))
+	adminOrganisations.Use(custommiddleware.RoleMiddleware("nhs_staff", "professional"))
+	adminOrganisations.Use(orgScoped)
+	adminOrganisations.GET("", organisationsHandler.ListOrganisations)
+	adminOrganisations.GET("/scope", organisationsHandler.GetMyScope)
+	adminOrganisations.GET("/:id", organisationsHandler.GetOrganisation)
+	adminOrganisations.POST("", organisationsHandler.CreateOrganisation, audited(audit.ActionOrganisationCreate, audit.TargetOrganisation, ""), custommiddleware.SuperAdminMiddleware())
+	adminOrganisations.PUT("/:id", organisationsHandler.UpdateOrganisation, audited(audit.ActionOrganisationUpdate, audit.TargetOrganisation, "id"), custommiddleware.SuperAdminMiddleware())
+	adminOrganisations.GET("/:id/members", organisationsHandler.ListMembers)
+	adminOrganisations.POST("/:id/members", organisationsHandler.AddMember, audited(audit.ActionOrganisationMemberAdd, audit.TargetOrganisation, "id"))
+	adminOrganisations.DELETE("/:id/members/:user_id", organisationsHandler.RemoveMember, audited(audit.ActionOrganisationMemberRemove, audit.TargetUser, "user_id"))
+
+	// --- Field encryption ---
+	encryptionService := encryption.NewService(encryption.NewStore(db), keyring, reencryptor)
+	encryptionHandler := encryption.NewHandler(encryptionService)
+
+	// Admin routes for data key management (require super admin)
+	adminEncryption := v1.Group("/admin/encryption")
+	adminEncryption.Use(custommiddleware.JWTMiddleware(jwtService))
+	adminEncryption.Use(custommiddleware.RoleMiddleware("nhs_staff", "professional"))
+	adminEncryption.Use(orgScoped)
+	adminEncryption.GET("/status", encryptionHandler.GetKeyStatus, custommiddleware.SuperAdminMiddleware())
+	adminEncryption.POST("/rotate", encryptionHandler.RotateKey, audited(audit.ActionEncryptionRotateKey, audit.TargetEncryptionKey, ""), custommiddleware.SuperAdminMiddleware())
+
+	// --- Background jobs ---
+	jobsStore := jobs.NewStore(db)
+	jobsService := jobs.NewService(jobsStore)
+	jobsHandler := jobs.NewHandler(jobsService)
+
+	// Admin routes for the job queue (require super admin)
+	adminJobs := v1.Group("/admin/jobs")
+	adminJobs.Use(custommiddleware.JWTMiddleware(jwtService))
+	adminJobs.Use(custommiddleware.RoleMiddleware("nhs_staff", "professional"))
+	adminJobs.Use(orgScoped)
+	adminJobs.GET("", jobsHandler.ListJobs, custommiddleware.SuperAdminMiddleware())
+	adminJobs.GET("/stats", jobsHandler.GetQueueStats, custommiddleware.SuperAdminMiddleware())
+	adminJobs.GET("/:id", jobsHandler.GetJob, custommiddleware.SuperAdminMiddleware())
+	adminJobs.POST("/:id/retry", jobsHandler.RetryJob, audited(audit.ActionJobRetry, audit.TargetJob, "id"), custommiddleware.SuperAdminMiddleware())
+
+	// --- Webhooks ---
+	webhooksStore := webhooks.NewStore(db)
+	webhooksService := webhooks.NewService(webhooksStore, keyring, jobsService)
+	webhooksHandler := webhooks.NewHandler(webhooksService)
+	snapshots[audit.TargetWebhook] = snapshotOf(webhooksStore.GetSubscription)
+
+	// Admin routes for partner webhooks (scoped to the caller's organisations)
+	adminWebhooks := v1.Group("/admin/webhooks")
+	adminWebhooks.Use(custommiddleware.JWTMiddleware(jwtService))
+	adminWebhooks.Use(custommiddleware.RoleMiddleware("nhs_staff", "professional"))
+	adminWebhooks.Use(orgScoped)
+	adminWebhooks.GET("", webhooksHandler.ListSubscriptions)
+	adminWebhooks.POST("", webhooksHandler.CreateSubscription, audited(audit.ActionWebhookCreate, audit.TargetWebhook, ""))
+	adminWebhooks.GET("/:id", webhooksHandler.GetSubscription)
+	adminWebhooks.PUT("/:id", webhooksHandler.UpdateSubscription, audited(audit.ActionWebhookUpdate, audit.TargetWebhook, "id"))
+	adminWebhooks.DELETE("/:id", webhooksHandler.DeleteSubscription, audited(audit.ActionWebhookDelete, audit.TargetWebhook, "id"))
+	adminWebhooks.POST("/:id/rotate-secret", webhooksHandler.RotateSecret, audited(audit.ActionWebhookRotateSecret, audit.TargetWebhook, "id"))
+	adminWebhooks.POST("/:id/ping", webhooksHandler.SendPing, audited(audit.ActionWebhookPing, audit.TargetWebhook, "id"))
+	adminWebhooks.GET("/:id/deliveries", webhooksHandler.ListDeliveries)
+	adminWebhooks.POST("/:id/deliveries/:delivery_id/replay", webhooksHandler.ReplayDelivery, audited(audit.ActionWebhookReplay, audit.TargetWebhook, "id"))
+
+	registerAPI(v1, apiDeps{
+		jwtService:      jwtService,
+		audited:         audited,
+		snapshots:       snapshots,
+		idempotent:      idempotent,
+		orgScoped:       orgScoped,
+		catalogVersions: catalogVersions,
+		jobs:            jobsService,
+		mailer:          mailer,
+		stores: apiStores{
+			Auth:              authStore,
+			Bookmarks:         bookmarks.NewStore(db),
+			User:              user.NewStore(db, keyring),
+			Privacy:           privacy.NewStore(db, keyring),
+			Services:          services.NewStore(dbHandle),
+			Resources:         resources.NewStore(dbHandle),
+			SupportGroups:     support_groups.NewStore(dbHandle),
+			Catalog:           catalog.NewStore(db),
+			CareTeam:          careteam.NewStore(db, keyring),
+			EmergencyContacts: emergencycontacts.NewStore(db, keyring),
+			Onboarding:        onboarding.NewStore(db),
+			Supporters:        supporters.NewStore(db, keyring),
+			Referrals:         referrals.NewStore(dbHandle, keyring),
+			Feedback:          feedback.NewStore(dbHandle),
+			Journey:           journey.NewStore(db, keyring),
+		},
+	})
+}
+
+// apiStores are the stores behind the user-facing routes
+type apiStores struct {
+	Auth              auth.Store
+	Bookmarks         bookmarks.Store
+	User              user.Store
+	Privacy           privacy.Store
+	Services          services.Store
+	Resources         resources.Store
+	SupportGroups     support_groups.Store
+	Catalog           catalog.Store
+	CareTeam          careteam.Store
+	EmergencyContacts emergencycontacts.Store
+	Onboarding        onboarding.Store
+	Supporters        supporters.Store
+	Referrals         referrals.Store
+	Feedback          feedback.Store
+	Journey           journey.Store
+}
+
+// apiDeps are the shared middleware and stores the user-facing routes are built from
+type apiDeps struct {
+	jwtService      *auth.JWTService
+	audited         func(action, targetType, targetParam string) echo.MiddlewareFunc
+	snapshots       audit.Snapshots
+	idempotent      echo.MiddlewareFunc
+	orgScoped       echo.MiddlewareFunc
+	catalogVersions httpcache.Store
+	jobs            jobs.Enqueuer
+	mailer          mail.Sender
+	stores          apiStores
+}
+
+// registerAPI mounts the user-facing routes. It is shared by the Postgres-backed
+// server and demo mode, which differ only in the stores they pass.
+func registerAPI(v1 *echo.Group, deps apiDeps) {
+	jwtService := deps.jwtService
+	audited := deps.audited
+	idempotent := deps.idempotent
+	orgScoped := deps.orgScoped
+	catalogVersions := deps.catalogVersions
+
+	// Audit entries for writes to these targets record the fields that changed
+	deps.snapshots[audit.TargetUser] = snapshotOf(deps.stores.User.GetUserByID)
+	deps.snapshots[audit.TargetCareTeam] = snapshotOf(deps.stores.CareTeam.GetRelationship)
+	deps.snapshots[audit.TargetService] = snapshotOf(deps.stores.Services.GetServiceByUUID)
+	deps.snapshots[audit.TargetResource] = snapshotOf(deps.stores.Resources.GetResourceByID)
+	deps.snapshots[audit.TargetSupportGroup] = snapshotOf(deps.stores.SupportGroups.GetSupportGroupByID)
+	deps.snapshots[audit.TargetReferral] = snapshotOf(deps.stores.Referrals.GetReferralByID)
+	deps.snapshots[audit.TargetFeedback] = snapshotOf(deps.stores.Feedback.GetFeedbackByID)
+	deps.snapshots[audit.TargetSupporterLink] = snapshotOf(deps.stores.Supporters.GetLink)
 
 	// --- Auth ---
-	authStore := auth.NewStore(db)
-	authService := auth.NewService(authStore, *jwtService)
+	authService := auth.NewService(deps.stores.Auth, *jwtService, deps.jobs)
 	authHandler := auth.NewHandler(authService)
 
-	// Public auth routes
-	v1.POST("/auth/register", authHandler.Register)
+	// Public auth routes. Registering with a guest token upgrades the guest.
+	v1.POST("/auth/register", authHandler.Register, custommiddleware.OptionalJWTMiddleware(jwtService))
+	v1.POST("/auth/guest", authHandler.StartGuestSession)
 	v1.POST("/auth/login", authHandler.Login)
 	v1.POST("/auth/refresh", authHandler.RefreshToken)
 	v1.POST("/auth/forgot-password", authHandler.ForgotPassword)
 	v1.POST("/auth/reset-password", authHandler.ResetPassword)
 
+	// --- Care teams ---
+	careTeamService := careteam.NewService(deps.stores.CareTeam)
+	careTeamHandler := careteam.NewHandler(careTeamService)
+
+	// careTeamGated limits routes about one user to their care team
+	careTeamGated := custommiddleware.CareTeamAccess(careTeamService, "id")
+
 	// --- Users ---
-	userStore := user.NewStore(db)
-	userService := user.NewService(userStore)
+	userService := user.NewService(deps.stores.User, deps.jobs, careTeamService, deps.mailer)
 	userHandler := user.NewHandler(userService)
 
 	// Public user routes
@@ -51,12 +270,41 @@ func Register(e *echo.Echo, db *pgxpool.Pool, cfg *config.Config) {
 	// Protected user routes (require JWT authentication)
 	users := v1.Group("/users")
 	users.Use(custommiddleware.JWTMiddleware(jwtService))
-	users.GET("", userHandler.ListUsers, custommiddleware.RoleMiddleware("nhs_staff", "professional"))
-	users.GET("/search", userHandler.SearchUsers) // Added missing search endpoint
-	users.GET("/:id", userHandler.GetUser)
-	users.GET("/:id/profile", userHandler.GetUserProfile)
-	users.PUT("/:id", userHandler.UpdateUser, custommiddleware.RoleMiddleware("nhs_staff", "professional"))
-	users.DELETE("/:id", userHandler.DeactivateUser, custommiddleware.RoleMiddleware("nhs_staff", "professional"))
+	users.GET("", userHandler.ListUsers, audited(audit.ActionUserList, audit.TargetUser, ""), custommiddleware.RoleMiddleware("nhs_staff", "professional"), orgScoped)
+	users.GET("/search", userHandler.SearchUsers, audited(audit.ActionUserSearch, audit.TargetUser, ""), custommiddleware.RoleMiddleware("nhs_staff", "professional"))
+	users.GET("/:id", userHandler.GetUser, audited(audit.ActionUserRead, audit.TargetUser, "id"), careTeamGated)
+	users.GET("/:id/profile", userHandler.GetUserProfile, audited(audit.ActionUserProfileRead, audit.TargetUser, "id"), careTeamGated)
+	users.PUT("/:id", userHandler.UpdateUser, audited(audit.ActionUserUpdate, audit.TargetUser, "id"), custommiddleware.RoleMiddleware("nhs_staff", "professional"), orgScoped)
+	users.DELETE("/:id", userHandler.DeactivateUser, audited(audit.ActionUserDeactivate, audit.TargetUser, "id"), custommiddleware.RoleMiddleware("nhs_staff", "professional"), orgScoped)
+
+	// Account lifecycle; only NHS staff can schedule erasure
+	users.GET("/:id/status", userHandler.GetAccountStatus, audited(audit.ActionUserStatusRead, audit.TargetUser, "id"), custommiddleware.RoleMiddleware("nhs_staff", "professional"), orgScoped)
+	users.POST("/:id/suspend", userHandler.SuspendUser, audited(audit.ActionUserSuspend, audit.TargetUser, "id"), custommiddleware.RoleMiddleware("nhs_staff", "professional"), orgScoped)
+	users.POST("/:id/reactivate", userHandler.ReactivateUser, audited(audit.ActionUserReactivate, audit.TargetUser, "id"), custommiddleware.RoleMiddleware("nhs_staff", "professional"), orgScoped)
+	users.POST("/:id/schedule-deletion", userHandler.ScheduleDeletion, audited(audit.ActionUserScheduleDeletion, audit.TargetUser, "id"), custommiddleware.RoleMiddleware("nhs_staff"), orgScoped)
+
+	// --- Staff onboarding ---
+	onboardingService := onboarding.NewService(deps.stores.Onboarding, deps.jobs, deps.mailer)
+	onboardingHandler := onboarding.NewHandler(onboardingService)
+
+	// Invited staff choose their password with the token from their invitation
+	v1.POST("/invitations/accept", onboardingHandler.AcceptInvitation)
+
+	// Admin route for bulk staff imports (require staff/professional role)
+	adminUsers := v1.Group("/admin/users")
+	adminUsers.Use(custommiddleware.JWTMiddleware(jwtService))
+	adminUsers.Use(custommiddleware.RoleMiddleware("nhs_staff", "professional"))
+	adminUsers.Use(orgScoped)
+	adminUsers.POST("/import", onboardingHandler.ImportUsers, audited(audit.ActionUserImport, audit.TargetUser, ""))
+
+	// Role and email changes and merging duplicate accounts are for NHS staff only
+	adminUsers.PUT("/:id/role", userHandler.UpdateUserRole, audited(audit.ActionUserRoleUpdate, audit.TargetUser, "id"), custommiddleware.RoleMiddleware("nhs_staff"))
+	adminUsers.POST("/:id/email", userHandler.RequestEmailChange, audited(audit.ActionUserEmailChange, audit.TargetUser, "id"), custommiddleware.RoleMiddleware("nhs_staff"))
+	adminUsers.GET("/:id/merge", userHandler.PreviewMerge, audited(audit.ActionUserMergePreview, audit.TargetUser, "id"), custommiddleware.RoleMiddleware("nhs_staff"))
+	adminUsers.POST("/:id/merge", userHandler.MergeUsers, audited(audit.ActionUserMerge, audit.TargetUser, "id"), custommiddleware.RoleMiddleware("nhs_staff"))
+
+	// The new address confirms an email change with the token sent to it
+	v1.POST("/email-changes/confirm", userHandler.ConfirmEmailChange)
 
 	// Auth routes that need to be with users context
 	v1.POST("/auth/change-password", authHandler.ChangePassword, custommiddleware.JWTMiddleware(jwtService))
@@ -67,12 +315,53 @@ func Register(e *echo.Echo, db *pgxpool.Pool, cfg *config.Config) {
 	me.GET("", userHandler.GetCurrentUserProfile)
 	me.PUT("", userHandler.UpdateCurrentUser)
 	me.POST("/last-login", userHandler.UpdateLastLogin)
-	me.GET("/preferences", userHandler.GetUserPreferences)    // Added missing preferences endpoint
-	me.PUT("/preferences", userHandler.UpdateUserPreferences) // Added missing preferences endpoint
+	me.GET("/preferences", userHandler.GetUserPreferences)
+	me.PUT("/preferences", userHandler.UpdateUserPreferences)
+	me.GET("/perinatal", userHandler.GetPerinatal)
+	me.PUT("/perinatal", userHandler.UpdatePerinatal)
+
+	// Clients validate preferences locally against the same schema as the server
+	v1.GET("/preferences/schema", userHandler.GetPreferencesSchema)
+
+	// Care-team routes for service users
+	me.GET("/care-team", careTeamHandler.ListMyCareTeam)
+	me.POST("/care-team/:id/accept", careTeamHandler.Accept, audited(audit.ActionCareTeamAccept, audit.TargetCareTeam, "id"))
+	me.PUT("/care-team/:id", careTeamHandler.UpdateSharing, audited(audit.ActionCareTeamUpdateSharing, audit.TargetCareTeam, "id"))
+	me.POST("/care-team/:id/end", careTeamHandler.End, audited(audit.ActionCareTeamEnd, audit.TargetCareTeam, "id"))
+
+	// Care-team routes for professionals and NHS staff
+	careTeam := v1.Group("/care-team")
+	careTeam.Use(custommiddleware.JWTMiddleware(jwtService))
+	careTeam.Use(custommiddleware.RoleMiddleware(careteam.CareTeamRoles...))
+	careTeam.GET("", careTeamHandler.ListForProfessional)
+	careTeam.POST("/invitations", careTeamHandler.Invite, audited(audit.ActionCareTeamInvite, audit.TargetCareTeam, ""))
+	careTeam.POST("/:id/end", careTeamHandler.End, audited(audit.ActionCareTeamEnd, audit.TargetCareTeam, "id"))
+	careTeam.POST("/break-glass", careTeamHandler.BreakGlass, audited(audit.ActionCareTeamBreakGlass, audit.TargetBreakGlass, ""))
+
+	// Admin care-team routes (require super admin)
+	adminCareTeam := v1.Group("/admin/care-team")
+	adminCareTeam.Use(custommiddleware.JWTMiddleware(jwtService))
+	adminCareTeam.Use(custommiddleware.RoleMiddleware("nhs_staff", "professional"))
+	adminCareTeam.Use(orgScoped)
+	adminCareTeam.GET("", careTeamHandler.ListRelationships, audited(audit.ActionCareTeamList, audit.TargetCareTeam, ""), custommiddleware.SuperAdminMiddleware())
+	adminCareTeam.POST("", careTeamHandler.Assign, audited(audit.ActionCareTeamAssign, audit.TargetCareTeam, ""), custommiddleware.SuperAdminMiddleware())
+	adminCareTeam.POST("/:id/end", careTeamHandler.AdminEnd, audited(audit.ActionCareTeamEnd, audit.TargetCareTeam, "id"), custommiddleware.SuperAdminMiddleware())
+
+	// --- Emergency contacts ---
+	emergencyContactsService := emergencycontacts.NewService(deps.stores.EmergencyContacts, careTeamService)
+	emergencyContactsHandler := emergencycontacts.NewHandler(emergencyContactsService)
+
+	me.GET("/emergency-contacts", emergencyContactsHandler.ListContacts)
+	me.POST("/emergency-contacts", emergencyContactsHandler.CreateContact)
+	me.GET("/emergency-contacts/:id", emergencyContactsHandler.GetContact)
+	me.PUT("/emergency-contacts/:id", emergencyContactsHandler.UpdateContact)
+	me.DELETE("/emergency-contacts/:id", emergencyContactsHandler.DeleteContact)
+
+	// Care-team members see only the contacts the service user agreed they may get in touch with
+	users.GET("/:id/emergency-contacts", emergencyContactsHandler.ListForUser, audited(audit.ActionEmergencyContactList, audit.TargetUser, "id"), custommiddleware.RoleMiddleware(careteam.CareTeamRoles...), careTeamGated)
 
 	// --- Privacy & GDPR ---
-	privacyStore := privacy.NewStore(db)
-	privacyService := privacy.NewService(privacyStore)
+	privacyService := privacy.NewService(deps.stores.Privacy, deps.jobs)
 	privacyHandler := privacy.NewHandler(privacyService)
 
 	// Privacy routes (require authentication)
@@ -80,21 +369,20 @@ func Register(e *echo.Echo, db *pgxpool.Pool, cfg *config.Config) {
 	privacyGroup.Use(custommiddleware.JWTMiddleware(jwtService))
 	privacyGroup.GET("/preferences", privacyHandler.GetPrivacyPreferences)
 	privacyGroup.PUT("/preferences", privacyHandler.UpdatePrivacyPreferences)
-	privacyGroup.POST("/request-data-download", privacyHandler.RequestDataDownload)
-	privacyGroup.POST("/request-account-deletion", privacyHandler.RequestAccountDeletion)
+	privacyGroup.POST("/request-data-download", privacyHandler.RequestDataDownload, audited(audit.ActionPrivacyDataDownload, audit.TargetPrivacy, ""))
+	privacyGroup.POST("/request-account-deletion", privacyHandler.RequestAccountDeletion, audited(audit.ActionPrivacyAccountDeletion, audit.TargetPrivacy, ""))
 	privacyGroup.GET("/data-retention-info", privacyHandler.GetDataRetentionInfo)
-	privacyGroup.GET("/export-data", privacyHandler.ExportUserData)
+	privacyGroup.GET("/export-data", privacyHandler.ExportUserData, audited(audit.ActionPrivacyExport, audit.TargetPrivacy, ""))
 	privacyGroup.GET("/data-requests", privacyHandler.GetDataRequests)
 
 	// --- Services ---
-	servicesStore := services.NewStore(db)
-	servicesService := services.NewService(servicesStore)
+	servicesService := services.NewService(deps.stores.Services)
 	servicesHandler := services.NewHandler(servicesService)
 
 	// Public service routes
-	v1.GET("/services", servicesHandler.ListServices)
-	v1.GET("/services/search", servicesHandler.SearchServices)
-	v1.GET("/services/:id", servicesHandler.GetService)
+	v1.GET("/services", servicesHandler.ListServices, custommiddleware.CatalogCache(catalogVersions, httpcache.CollectionServices, httpcache.PolicyList))
+	v1.GET("/services/search", servicesHandler.SearchServices, custommiddleware.CatalogCache(catalogVersions, httpcache.CollectionServices, httpcache.PolicySearch))
+	v1.GET("/services/:id", servicesHandler.GetService, custommiddleware.CatalogCache(catalogVersions, httpcache.CollectionServices, httpcache.PolicyDetail))
 
 	// Featured services endpoint
 	v1.GET("/services/featured", func(c echo.Context) error {
@@ -113,30 +401,31 @@ func Register(e *echo.Echo, db *pgxpool.Pool, cfg *config.Config) {
 		}
 
 		return c.JSON(http.StatusOK, servicesList)
-	})
+	}, custommiddleware.CatalogCache(catalogVersions, httpcache.CollectionServices, httpcache.PolicyFeatured))
 
 	// Admin routes for services (require staff/professional role)
 	adminServices := v1.Group("/admin/services")
 	adminServices.Use(custommiddleware.JWTMiddleware(jwtService))
 	adminServices.Use(custommiddleware.RoleMiddleware("nhs_staff", "professional"))
-	adminServices.POST("", servicesHandler.CreateService)
-	adminServices.PUT("/:id", servicesHandler.UpdateService)
-	adminServices.DELETE("/:id", servicesHandler.DeleteService)
+	adminServices.Use(orgScoped)
+	adminServices.Use(custommiddleware.BumpCollectionVersion(catalogVersions, httpcache.CollectionServices))
+	adminServices.POST("", servicesHandler.CreateService, audited(audit.ActionServiceCreate, audit.TargetService, ""))
+	adminServices.PUT("/:id", servicesHandler.UpdateService, audited(audit.ActionServiceUpdate, audit.TargetService, "id"))
+	adminServices.DELETE("/:id", servicesHandler.DeleteService, audited(audit.ActionServiceDelete, audit.TargetService, "id"))
 	adminServices.GET("/stats", servicesHandler.GetServiceStats)
 
 	// --- Resources ---
-	resourcesStore := resources.NewStore(db)
-	resourcesService := resources.NewService(resourcesStore)
+	resourcesService := resources.NewService(deps.stores.Resources)
 	resourcesHandler := resources.NewHandler(resourcesService)
 
 	// Public resource routes
-	v1.GET("/resources", resourcesHandler.ListResources)
-	v1.GET("/resources/search", resourcesHandler.SearchResources)
-	v1.GET("/resources/:id", resourcesHandler.GetResource)
-	v1.GET("/resources/featured", resourcesHandler.GetFeaturedResources)
-	v1.GET("/resources/popular", resourcesHandler.GetPopularResources)
-	v1.GET("/resources/by-tag", resourcesHandler.GetResourcesByTag)
-	v1.GET("/resources/by-audience", resourcesHandler.GetResourcesByAudience)
+	v1.GET("/resources", resourcesHandler.ListResources, custommiddleware.CatalogCache(catalogVersions, httpcache.CollectionResources, httpcache.PolicyList))
+	v1.GET("/resources/search", resourcesHandler.SearchResources, custommiddleware.CatalogCache(catalogVersions, httpcache.CollectionResources, httpcache.PolicySearch))
+	v1.GET("/resources/:id", resourcesHandler.GetResource, custommiddleware.CatalogCache(catalogVersions, httpcache.CollectionResources, httpcache.PolicyDetail))
+	v1.GET("/resources/featured", resourcesHandler.GetFeaturedResources, custommiddleware.CatalogCache(catalogVersions, httpcache.CollectionResources, httpcache.PolicyFeatured))
+	v1.GET("/resources/popular", resourcesHandler.GetPopularResources, custommiddleware.CatalogCache(catalogVersions, httpcache.CollectionResources, httpcache.PolicyPopular))
+	v1.GET("/resources/by-tag", resourcesHandler.GetResourcesByTag, custommiddleware.CatalogCache(catalogVersions, httpcache.CollectionResources, httpcache.PolicyList))
+	v1.GET("/resources/by-audience", resourcesHandler.GetResourcesByAudience, custommiddleware.CatalogCache(catalogVersions, httpcache.CollectionResources, httpcache.PolicyList))
 
 	// Resource interaction routes (require authentication for tracking)
 	resourcesAuth := v1.Group("/resources")
@@ -147,29 +436,31 @@ func Register(e *echo.Echo, db *pgxpool.Pool, cfg *config.Config) {
 	adminResources := v1.Group("/admin/resources")
 	adminResources.Use(custommiddleware.JWTMiddleware(jwtService))
 	adminResources.Use(custommiddleware.RoleMiddleware("nhs_staff", "professional"))
-	adminResources.POST("", resourcesHandler.CreateResource)
-	adminResources.PUT("/:id", resourcesHandler.UpdateResource)
-	adminResources.DELETE("/:id", resourcesHandler.DeleteResource)
-	adminResources.POST("/:id/toggle-featured", resourcesHandler.ToggleResourceFeatured)
+	adminResources.Use(custommiddleware.BumpCollectionVersion(catalogVersions, httpcache.CollectionResources))
+	adminResources.POST("", resourcesHandler.CreateResource, audited(audit.ActionResourceCreate, audit.TargetResource, ""))
+	adminResources.PUT("/:id", resourcesHandler.UpdateResource, audited(audit.ActionResourceUpdate, audit.TargetResource, "id"))
+	adminResources.DELETE("/:id", resourcesHandler.DeleteResource, audited(audit.ActionResourceDelete, audit.TargetResource, "id"))
+	adminResources.POST("/:id/toggle-featured", resourcesHandler.ToggleResourceFeatured, audited(audit.ActionResourceToggleFeatured, audit.TargetResource, "id"))
 	adminResources.GET("/stats", resourcesHandler.GetResourceStats)
 
 	// --- Support Groups ---
-	supportGroupsStore := support_groups.NewStore(db)
-	supportGroupsService := support_groups.NewService(supportGroupsStore)
+	supportGroupsService := support_groups.NewService(deps.stores.SupportGroups)
 	supportGroupsHandler := support_groups.NewHandler(supportGroupsService)
 
 	// Public support group routes
-	v1.GET("/support-groups", supportGroupsHandler.ListSupportGroups)
-	v1.GET("/support-groups/search", supportGroupsHandler.SearchSupportGroups)
-	v1.GET("/support-groups/:id", supportGroupsHandler.GetSupportGroup)
-	v1.GET("/support-groups/by-category", supportGroupsHandler.GetSupportGroupsByCategory)
-	v1.GET("/support-groups/by-platform", supportGroupsHandler.GetSupportGroupsByPlatform)
+	v1.GET("/support-groups", supportGroupsHandler.ListSupportGroups, custommiddleware.CatalogCache(catalogVersions, httpcache.CollectionSupportGroups, httpcache.PolicyList))
+	v1.GET("/support-groups/search", supportGroupsHandler.SearchSupportGroups, custommiddleware.CatalogCache(catalogVersions, httpcache.CollectionSupportGroups, httpcache.PolicySearch))
+	v1.GET("/support-groups/:id", supportGroupsHandler.GetSupportGroup, custommiddleware.CatalogCache(catalogVersions, httpcache.CollectionSupportGroups, httpcache.PolicyDetail))
+	v1.GET("/support-groups/by-category", supportGroupsHandler.GetSupportGroupsByCategory, custommiddleware.CatalogCache(catalogVersions, httpcache.CollectionSupportGroups, httpcache.PolicyList))
+	v1.GET("/support-groups/by-platform", supportGroupsHandler.GetSupportGroupsByPlatform, custommiddleware.CatalogCache(catalogVersions, httpcache.CollectionSupportGroups, httpcache.PolicyList))
 
 	// Protected support group routes (require authentication)
 	supportGroupsAuth := v1.Group("/support-groups")
 	supportGroupsAuth.Use(custommiddleware.JWTMiddleware(jwtService))
-	supportGroupsAuth.POST("/join", supportGroupsHandler.JoinGroup)
-	supportGroupsAuth.DELETE("/:id/leave", supportGroupsHandler.LeaveGroup)
+	// Membership changes alter member counts in the cached catalog
+	bumpSupportGroups := custommiddleware.BumpCollectionVersion(catalogVersions, httpcache.CollectionSupportGroups)
+	supportGroupsAuth.POST("/join", supportGroupsHandler.JoinGroup, idempotent, bumpSupportGroups)
+	supportGroupsAuth.DELETE("/:id/leave", supportGroupsHandler.LeaveGroup, bumpSupportGroups)
 	supportGroupsAuth.GET("/:id/members", supportGroupsHandler.GetGroupMembers)
 
 	// User's support groups
@@ -179,15 +470,36 @@ func Register(e *echo.Echo, db *pgxpool.Pool, cfg *config.Config) {
 	adminSupportGroups := v1.Group("/admin/support-groups")
 	adminSupportGroups.Use(custommiddleware.JWTMiddleware(jwtService))
 	adminSupportGroups.Use(custommiddleware.RoleMiddleware("nhs_staff", "professional"))
-	adminSupportGroups.POST("", supportGroupsHandler.CreateSupportGroup)
-	adminSupportGroups.PUT("/:id", supportGroupsHandler.UpdateSupportGroup)
-	adminSupportGroups.DELETE("/:id", supportGroupsHandler.DeleteSupportGroup)
-	adminSupportGroups.DELETE("/:id/members/:user_id", supportGroupsHandler.RemoveUserFromGroup)
+	adminSupportGroups.Use(orgScoped)
+	adminSupportGroups.Use(custommiddleware.BumpCollectionVersion(catalogVersions, httpcache.CollectionSupportGroups))
+	adminSupportGroups.POST("", supportGroupsHandler.CreateSupportGroup, audited(audit.ActionSupportGroupCreate, audit.TargetSupportGroup, ""))
+	adminSupportGroups.PUT("/:id", supportGroupsHandler.UpdateSupportGroup, audited(audit.ActionSupportGroupUpdate, audit.TargetSupportGroup, "id"))
+	adminSupportGroups.DELETE("/:id", supportGroupsHandler.DeleteSupportGroup, audited(audit.ActionSupportGroupDelete, audit.TargetSupportGroup, "id"))
+	adminSupportGroups.DELETE("/:id/members/:user_id", supportGroupsHandler.RemoveUserFromGroup, audited(audit.ActionSupportGroupMemberRemove, audit.TargetUser, "user_id"))
 	adminSupportGroups.GET("/stats", supportGroupsHandler.GetSupportGroupStats)
 
+	// --- Catalog import and export ---
+	catalogService := catalog.NewService(deps.stores.Catalog, catalogVersions, servicesService, resourcesService, supportGroupsService)
+	catalogHandler := catalog.NewHandler(catalogService)
+
+	// Admin routes for bulk catalog files (require staff/professional role)
+	adminCatalog := v1.Group("/admin/catalog")
+	adminCatalog.Use(custommiddleware.JWTMiddleware(jwtService))
+	adminCatalog.Use(custommiddleware.RoleMiddleware("nhs_staff", "professional"))
+	adminCatalog.Use(orgScoped)
+	adminCatalog.POST("/:collection/import", catalogHandler.ImportCatalog, audited(audit.ActionCatalogImport, audit.TargetCatalog, "collection"))
+	adminCatalog.GET("/:collection/export", catalogHandler.ExportCatalog, audited(audit.ActionCatalogExport, audit.TargetCatalog, "collection"))
+
+	// --- Open Referral HSDS ---
+	hsdsService := hsds.NewService(servicesService, catalogService)
+	hsdsHandler := hsds.NewHandler(hsdsService)
+
+	// Public HSDS routes for regional directories
+	v1.GET("/hsds/services", hsdsHandler.ListServices, custommiddleware.CatalogCache(catalogVersions, httpcache.CollectionServices, httpcache.PolicyList))
+	v1.GET("/hsds/services/:id", hsdsHandler.GetService, custommiddleware.CatalogCache(catalogVersions, httpcache.CollectionServices, httpcache.PolicyDetail))
+
 	// --- Referrals ---
-	referralsStore := referrals.NewStore(db)
-	referralsService := referrals.NewService(referralsStore)
+	referralsService := referrals.NewService(deps.stores.Referrals, careTeamService)
 	referralsHandler := referrals.NewHandler(referralsService)
 
 	// Protected referral routes (require authentication)
@@ -195,23 +507,23 @@ func Register(e *echo.Echo, db *pgxpool.Pool, cfg *config.Config) {
 	referralsGroup.Use(custommiddleware.JWTMiddleware(jwtService))
 
 	// Create referral (professionals/NHS staff only)
-	referralsGroup.POST("", referralsHandler.CreateReferral, custommiddleware.RoleMiddleware("nhs_staff", "professional"))
+	referralsGroup.POST("", referralsHandler.CreateReferral, audited(audit.ActionReferralCreate, audit.TargetReferral, ""), custommiddleware.RoleMiddleware("nhs_staff", "professional"), idempotent)
 
 	// List referrals
 	referralsGroup.GET("/sent", referralsHandler.ListSentReferrals, custommiddleware.RoleMiddleware("nhs_staff", "professional"))
 	referralsGroup.GET("/received", referralsHandler.ListReceivedReferrals)
 
 	// Individual referral operations
-	referralsGroup.GET("/:id", referralsHandler.GetReferral)
-	referralsGroup.PUT("/:id", referralsHandler.UpdateReferral)
-	referralsGroup.PUT("/:id/status", referralsHandler.UpdateReferralStatus)
-	referralsGroup.DELETE("/:id", referralsHandler.DeleteReferral, custommiddleware.RoleMiddleware("nhs_staff", "professional"))
+	referralsGroup.GET("/:id", referralsHandler.GetReferral, audited(audit.ActionReferralRead, audit.TargetReferral, "id"))
+	referralsGroup.PUT("/:id", referralsHandler.UpdateReferral, audited(audit.ActionReferralUpdate, audit.TargetReferral, "id"))
+	referralsGroup.PUT("/:id/status", referralsHandler.UpdateReferralStatus, audited(audit.ActionReferralStatusUpdate, audit.TargetReferral, "id"))
+	referralsGroup.DELETE("/:id", referralsHandler.DeleteReferral, audited(audit.ActionReferralDelete, audit.TargetReferral, "id"), custommiddleware.RoleMiddleware("nhs_staff", "professional"))
 
 	// Search users for referrals (professionals/NHS staff only)
-	referralsGroup.GET("/users/search", referralsHandler.SearchUsers, custommiddleware.RoleMiddleware("nhs_staff", "professional"))
+	referralsGroup.GET("/users/search", referralsHandler.SearchUsers, audited(audit.ActionUserSearch, audit.TargetUser, ""), custommiddleware.RoleMiddleware("nhs_staff", "professional"))
 
 	// Get referrals by item
-	referralsGroup.GET("/by-item", referralsHandler.GetReferralsByItem)
+	referralsGroup.GET("/by-item", referralsHandler.GetReferralsByItem, audited(audit.ActionReferralListByItem, audit.TargetReferral, ""))
 
 	// Referral statistics
 	referralsGroup.GET("/stats", referralsHandler.GetReferralStats, custommiddleware.RoleMiddleware("nhs_staff", "professional"))
@@ -244,15 +556,15 @@ func Register(e *echo.Echo, db *pgxpool.Pool, cfg *config.Config) {
 	adminReferrals := v1.Group("/admin/referrals")
 	adminReferrals.Use(custommiddleware.JWTMiddleware(jwtService))
 	adminReferrals.Use(custommiddleware.RoleMiddleware("nhs_staff", "professional"))
-	adminReferrals.GET("/stats", referralsHandler.GetReferralStats)
+	adminReferrals.Use(orgScoped)
+	adminReferrals.GET("/stats", referralsHandler.GetOrganisationReferralStats)
 
 	// --- Enhanced Feedback Routes ---
-	feedbackStore := feedback.NewStore(db)
-	feedbackService := feedback.NewService(feedbackStore)
+	feedbackService := feedback.NewService(deps.stores.Feedback)
 	feedbackHandler := feedback.NewHandler(feedbackService)
 
 	// Public feedback submission (anonymous allowed)
-	v1.POST("/feedback", feedbackHandler.CreateFeedback, custommiddleware.OptionalJWTMiddleware(jwtService))
+	v1.POST("/feedback", feedbackHandler.CreateFeedback, custommiddleware.OptionalJWTMiddleware(jwtService), idempotent)
 
 	// User's own feedback (require authentication)
 	userFeedback := v1.Group("/my-feedback")
@@ -263,22 +575,22 @@ func Register(e *echo.Echo, db *pgxpool.Pool, cfg *config.Config) {
 	adminFeedback := v1.Group("/admin/feedback")
 	adminFeedback.Use(custommiddleware.JWTMiddleware(jwtService))
 	adminFeedback.Use(custommiddleware.RoleMiddleware("nhs_staff", "professional"))
-	adminFeedback.GET("", feedbackHandler.ListFeedback)                    // List all feedback
-	adminFeedback.GET("/stats", feedbackHandler.GetFeedbackStats)          // Get feedback statistics
-	adminFeedback.GET("/:id", feedbackHandler.GetFeedback)                 // Get single feedback
-	adminFeedback.PUT("/:id/status", feedbackHandler.UpdateFeedbackStatus) // Update feedback status
+	adminFeedback.Use(orgScoped)
+	adminFeedback.GET("", feedbackHandler.ListFeedback, audited(audit.ActionFeedbackList, audit.TargetFeedback, ""))                              // List all feedback
+	adminFeedback.GET("/stats", feedbackHandler.GetFeedbackStats)                                                                                 // Get feedback statistics
+	adminFeedback.GET("/:id", feedbackHandler.GetFeedback, audited(audit.ActionFeedbackRead, audit.TargetFeedback, "id"))                         // Get single feedback
+	adminFeedback.PUT("/:id/status", feedbackHandler.UpdateFeedbackStatus, audited(audit.ActionFeedbackStatusUpdate, audit.TargetFeedback, "id")) // Update feedback status
 
 	// --- Journey ---
-	journeyStore := journey.NewStore(db)
-	journeyService := journey.NewService(journeyStore)
+	journeyService := journey.NewService(deps.stores.Journey, deps.jobs)
 	journeyHandler := journey.NewHandler(journeyService)
 
-	// Protected journey routes (require authentication)
+	// Protected journey routes (require authentication; guests can keep a journal)
 	journeyGroup := v1.Group("/journey")
-	journeyGroup.Use(custommiddleware.JWTMiddleware(jwtService))
+	journeyGroup.Use(custommiddleware.GuestJWTMiddleware(jwtService))
 
 	// Journey Entries
-	journeyGroup.POST("/entries", journeyHandler.CreateJourneyEntry)
+	journeyGroup.POST("/entries", journeyHandler.CreateJourneyEntry, idempotent)
 	journeyGroup.GET("/entries", journeyHandler.ListJourneyEntries)
 	journeyGroup.GET("/entries/today", journeyHandler.GetTodaysEntry)
 	journeyGroup.GET("/entries/:id", journeyHandler.GetJourneyEntry)
@@ -295,4 +607,57 @@ func Register(e *echo.Echo, db *pgxpool.Pool, cfg *config.Config) {
 	journeyGroup.GET("/stats", journeyHandler.GetJourneyStats)
 	journeyGroup.GET("/insights", journeyHandler.GetJourneyInsights)
 	journeyGroup.GET("/milestones", journeyHandler.ListJourneyMilestones)
+
+	// --- Bookmarks ---
+	bookmarksService := bookmarks.NewService(deps.stores.Bookmarks)
+	bookmarksHandler := bookmarks.NewHandler(bookmarksService)
+
+	// Bookmark routes (require authentication; open to guests)
+	bookmarksGroup := v1.Group("/bookmarks")
+	bookmarksGroup.Use(custommiddleware.GuestJWTMiddleware(jwtService))
+	bookmarksGroup.GET("", bookmarksHandler.ListBookmarks)
+	bookmarksGroup.POST("", bookmarksHandler.CreateBookmark)
+	bookmarksGroup.DELETE("/:id", bookmarksHandler.DeleteBookmark)
+
+	// --- Caseload ---
+	caseloadService := caseload.NewService(careTeamService, journeyService, referralsService, supportGroupsService)
+	caseloadHandler := caseload.NewHandler(caseloadService)
+
+	// Caseload overview (professionals/NHS staff only)
+	caseloadGroup := v1.Group("/caseload")
+	caseloadGroup.Use(custommiddleware.JWTMiddleware(jwtService))
+	caseloadGroup.GET("", caseloadHandler.ListCaseload, audited(audit.ActionCaseloadList, audit.TargetCareTeam, ""), custommiddleware.RoleMiddleware(careteam.CareTeamRoles...))
+
+	// --- Supporters ---
+	supportersService := supporters.NewService(deps.stores.Supporters, journeyService, supportGroupsService)
+	supportersHandler := supporters.NewHandler(supportersService)
+
+	// Service users invite partners and family and choose what they see
+	me.GET("/supporters", supportersHandler.ListSupporters)
+	me.POST("/supporters", supportersHandler.Invite, audited(audit.ActionSupporterInvite, audit.TargetSupporterLink, ""))
+	me.PUT("/supporters/:id", supportersHandler.UpdateShares, audited(audit.ActionSupporterUpdateShares, audit.TargetSupporterLink, "id"))
+	me.POST("/supporters/:id/revoke", supportersHandler.Revoke, audited(audit.ActionSupporterRevoke, audit.TargetSupporterLink, "id"))
+	me.GET("/crisis-plan", supportersHandler.GetCrisisPlan)
+	me.PUT("/crisis-plan", supportersHandler.UpdateCrisisPlan)
+
+	// Supporters see only what the person they support shares with them
+	supporting := v1.Group("/supporting")
+	supporting.Use(custommiddleware.JWTMiddleware(jwtService))
+	supporting.GET("", supportersHandler.ListSupporting)
+	supporting.POST("/accept", supportersHandler.Accept, audited(audit.ActionSupporterAccept, audit.TargetSupporterLink, ""))
+	supporting.GET("/:id", supportersHandler.GetView, audited(audit.ActionSupporterView, audit.TargetSupporterLink, "id"))
+	supporting.POST("/:id/revoke", supportersHandler.Revoke, audited(audit.ActionSupporterRevoke, audit.TargetSupporterLink, "id"))
+}
+
+// Helper functions
+
+// snapshotOf adapts a store lookup into an audit snapshot
+func snapshotOf[T any](get func(ctx context.Context, id string) (*T, error)) audit.Snapshot {
+	return func(ctx context.Context, id string) (interface{}, error) {
+		value, err := get(ctx, id)
+		if err != nil || value == nil {
+			return nil, err
+		}
+		return value, nil
+	}
 }
@@ -1,24 +1,27 @@
 package services
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
 )
 
-type Handler struct {
-	service *Service
+type handler struct {
+	service Service
 }
 
-func NewHandler(service *Service) *Handler {
-	return &Handler{
+func NewHandler(service Service) Handler {
+	return &handler{
 		service: service,
 	}
 }
 
 // ListServices retrieves a paginated list of services with optional filters
-func (h *Handler) ListServices(c echo.Context) error {
+func (h *handler) ListServices(c echo.Context) error {
 	// Parse query parameters
 	page := 1
 	if p := c.QueryParam("page"); p != "" {
@@ -50,7 +53,16 @@ func (h *Handler) ListServices(c echo.Context) error {
 	}
 
 	// Regular list with filters
-	services, err := h.service.ListServices(c.Request().Context(), page, pageSize, serviceType, location, false)
+	req := &ListServicesRequest{
+		Page:        page,
+		PageSize:    pageSize,
+		ServiceType: serviceType,
+		Location:    location,
+		Stage:       c.QueryParam("stage"),
+		Params:      pagination.ParseQuery(c),
+	}
+
+	services, err := h.service.ListServices(c.Request().Context(), req)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": err.Error(),
@@ -61,7 +73,7 @@ func (h *Handler) ListServices(c echo.Context) error {
 }
 
 // GetService retrieves a service by ID
-func (h *Handler) GetService(c echo.Context) error {
+func (h *handler) GetService(c echo.Context) error {
 	serviceIDStr := c.Param("id")
 
 	service, err := h.service.GetService(c.Request().Context(), serviceIDStr)
@@ -75,7 +87,7 @@ func (h *Handler) GetService(c echo.Context) error {
 }
 
 // SearchServices handles search requests
-func (h *Handler) SearchServices(c echo.Context) error {
+func (h *handler) SearchServices(c echo.Context) error {
 	query := c.QueryParam("q")
 	if query == "" {
 		return c.JSON(http.StatusBadRequest, map[string]string{
@@ -109,7 +121,7 @@ func (h *Handler) SearchServices(c echo.Context) error {
 }
 
 // CreateService creates a new service (admin only)
-func (h *Handler) CreateService(c echo.Context) error {
+func (h *handler) CreateService(c echo.Context) error {
 	var req CreateServiceRequest
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
@@ -123,7 +135,12 @@ func (h *Handler) CreateService(c echo.Context) error {
 		})
 	}
 
-	service, err := h.service.CreateService(c.Request().Context(), &req)
+	service, err := h.service.CreateService(c.Request().Context(), organisations.ScopeFromContext(c), &req)
+	if errors.Is(err, organisations.ErrOutOfScope) {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": err.Error(),
+		})
+	}
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": err.Error(),
@@ -134,7 +151,7 @@ func (h *Handler) CreateService(c echo.Context) error {
 }
 
 // UpdateService updates a service (admin only)
-func (h *Handler) UpdateService(c echo.Context) error {
+func (h *handler) UpdateService(c echo.Context) error {
 	serviceIDStr := c.Param("id")
 	serviceID, err := strconv.Atoi(serviceIDStr)
 	if err != nil {
@@ -156,7 +173,12 @@ func (h *Handler) UpdateService(c echo.Context) error {
 		})
 	}
 
-	service, err := h.service.UpdateService(c.Request().Context(), serviceID, &req)
+	service, err := h.service.UpdateService(c.Request().Context(), organisations.ScopeFromContext(c), serviceID, &req)
+	if errors.Is(err, organisations.ErrOutOfScope) {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": err.Error(),
+		})
+	}
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{
 			"error": err.Error(),
@@ -167,16 +189,13 @@ func (h *Handler) UpdateService(c echo.Context) error {
 }
 
 // DeleteService deactivates a service (admin only)
-func (h *Handler) DeleteService(c echo.Context) error {
-	serviceIDStr := c.Param("id")
-	serviceID, err := strconv.Atoi(serviceIDStr)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Invalid service ID",
+func (h *handler) DeleteService(c echo.Context) error {
+	err := h.service.DeleteService(c.Request().Context(), organisations.ScopeFromContext(c), c.Param("id"))
+	if errors.Is(err, organisations.ErrOutOfScope) {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": err.Error(),
 		})
 	}
-
-	err = h.service.DeleteService(c.Request().Context(), serviceID)
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{
 			"error": err.Error(),
@@ -189,8 +208,8 @@ func (h *Handler) DeleteService(c echo.Context) error {
 }
 
 // GetServiceStats retrieves service statistics (admin only)
-func (h *Handler) GetServiceStats(c echo.Context) error {
-	stats, err := h.service.GetServiceStats(c.Request().Context())
+func (h *handler) GetServiceStats(c echo.Context) error {
+	stats, err := h.service.GetServiceStats(c.Request().Context(), organisations.ScopeFromContext(c))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": err.Error(),
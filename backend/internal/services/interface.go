@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+)
+
+// Service defines the interface for services business logic
+type Service interface {
+	ListServices(ctx context.Context, req *ListServicesRequest) (*ListServicesResponse, error)
+	GetService(ctx context.Context, serviceID string) (*ServicesModel, error)
+	SearchServices(ctx context.Context, query string, page, pageSize int) (*ListServicesResponse, error)
+	GetFeaturedServices(ctx context.Context, limit int) (*ListServicesResponse, error)
+	GetServicesByType(ctx context.Context, serviceType string, page, pageSize int) (*ListServicesResponse, error)
+	GetServiceStats(ctx context.Context, scope organisations.Scope) (*ServiceStats, error)
+
+	// Admin/Staff only methods
+	CreateService(ctx context.Context, scope organisations.Scope, req *CreateServiceRequest) (*ServicesModel, error)
+	UpdateService(ctx context.Context, scope organisations.Scope, serviceID int, req *UpdateServiceRequest) (*ServicesModel, error)
+	UpdateServiceByUUID(ctx context.Context, scope organisations.Scope, serviceID string, req *UpdateServiceRequest) (*ServicesModel, error)
+	ValidateService(scope organisations.Scope, req *CreateServiceRequest) error
+	DeleteService(ctx context.Context, scope organisations.Scope, serviceID string) error
+}
+
+// Store defines the interface for services data persistence
+type Store interface {
+	ListServices(ctx context.Context, req *ListServicesRequest) (*ListServicesResponse, error)
+	GetServiceByUUID(ctx context.Context, serviceID string) (*ServicesModel, error)
+	SearchServices(ctx context.Context, query string, page, pageSize int) (*ListServicesResponse, error)
+	GetServiceStats(ctx context.Context, scope organisations.Scope) (*ServiceStats, error)
+
+	// Admin/Staff only methods
+	CreateService(ctx context.Context, req *CreateServiceRequest) (*ServicesModel, error)
+	UpdateService(ctx context.Context, serviceID int, req *UpdateServiceRequest) (*ServicesModel, error)
+	UpdateServiceByUUID(ctx context.Context, serviceID string, req *UpdateServiceRequest) (*ServicesModel, error)
+	DeactivateService(ctx context.Context, serviceID int) error
+	DeactivateServiceByUUID(ctx context.Context, serviceID string) error
+}
+
+// Handler defines the interface for services HTTP handlers
+type Handler interface {
+	ListServices(c echo.Context) error
+	GetService(c echo.Context) error
+	SearchServices(c echo.Context) error
+	CreateService(c echo.Context) error
+	UpdateService(c echo.Context) error
+	DeleteService(c echo.Context) error
+	GetServiceStats(c echo.Context) error
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
+	"github.com/perinatal-mental-health-app/backend/internal/perinatal"
+)
+
+// memoryStore keeps services in memory for tests and demo mode. Names are
+// mirrored into the shared catalog so referrals can find them.
+type memoryStore struct {
+	db       *memdb.DB
+	mu       sync.RWMutex
+	services map[string]ServicesModel
+}
+
+func NewMemoryStore(db *memdb.DB) Store {
+	return &memoryStore{
+		db:       db,
+		services: make(map[string]ServicesModel),
+	}
+}
+
+// ListServices retrieves a paginated list of active services, newest first
+func (s *memoryStore) ListServices(ctx context.Context, req *ListServicesRequest) (*ListServicesResponse, error) {
+	cursor, err := req.DecodeCursor()
+	if err != nil {
+		return nil, err
+	}
+
+	offset := 0
+	if cursor == nil {
+		offset = (req.Page - 1) * req.PageSize
+	}
+
+	location := strings.ToLower(req.Location)
+	matched := s.filter(func(service ServicesModel) bool {
+		if !service.IsActive {
+			return false
+		}
+		if req.ServiceType != "" && service.ServiceType != req.ServiceType {
+			return false
+		}
+		if req.Stage != "" && !perinatal.Matches(service.PerinatalStages, perinatal.Stage(req.Stage)) {
+			return false
+		}
+		return location == "" || (service.Address != nil && strings.Contains(strings.ToLower(*service.Address), location))
+	})
+	sort.Slice(matched, func(i, j int) bool {
+		return pagination.Compare(serviceCursor(matched[i]), serviceCursor(matched[j])) > 0
+	})
+
+	response := &ListServicesResponse{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	if req.WantTotal() {
+		total := int64(len(matched))
+		totalPages := pagination.TotalPages(total, req.PageSize)
+		response.Total = &total
+		response.TotalPages = &totalPages
+	}
+
+	window := pagination.Window(matched, cursor, offset, req.PageSize, serviceCursor)
+	response.Services, response.NextCursor, response.PrevCursor = pagination.Paginate(window, req.PageSize, cursor, offset, serviceCursor)
+
+	return response, nil
+}
+
+// GetServiceByUUID retrieves an active service by ID
+func (s *memoryStore) GetServiceByUUID(ctx context.Context, serviceID string) (*ServicesModel, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	service, ok := s.services[serviceID]
+	if !ok || !service.IsActive {
+		return nil, fmt.Errorf("service not found")
+	}
+
+	return &service, nil
+}
+
+// SearchServices searches active services, ranking name matches first
+func (s *memoryStore) SearchServices(ctx context.Context, query string, page, pageSize int) (*ListServicesResponse, error) {
+	query = strings.ToLower(query)
+	rank := func(service ServicesModel) int {
+		switch {
+		case strings.Contains(strings.ToLower(service.Name), query):
+			return 1
+		case strings.Contains(strings.ToLower(service.ServiceType), query):
+			return 2
+		case strings.Contains(strings.ToLower(service.ProviderName), query):
+			return 3
+		case strings.Contains(strings.ToLower(service.Description), query):
+			return 4
+		}
+		return 0
+	}
+
+	matched := s.filter(func(service ServicesModel) bool {
+		return service.IsActive && rank(service) > 0
+	})
+	sort.Slice(matched, func(i, j int) bool {
+		if rank(matched[i]) != rank(matched[j]) {
+			return rank(matched[i]) < rank(matched[j])
+		}
+		return matched[i].CreatedAt.After(matched[j].CreatedAt)
+	})
+
+	total := int64(len(matched))
+	var services []ServicesModel
+	for i := (page - 1) * pageSize; i < len(matched) && len(services) < pageSize; i++ {
+		services = append(services, matched[i])
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+
+	return &ListServicesResponse{
+		Services:   services,
+		Total:      &total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: &totalPages,
+	}, nil
+}
+
+// GetServiceStats retrieves statistics for the services owned by the scope's organisations
+func (s *memoryStore) GetServiceStats(ctx context.Context, scope organisations.Scope) (*ServiceStats, error) {
+	stats := &ServiceStats{
+		ServicesByType: make(map[string]int64),
+	}
+
+	for _, service := range s.filter(func(service ServicesModel) bool { return scope.Covers(service.OrganisationID) }) {
+		stats.TotalServices++
+		if !service.IsActive {
+			stats.InactiveServices++
+			continue
+		}
+		stats.ActiveServices++
+		stats.ServicesByType[service.ServiceType]++
+	}
+
+	return stats, nil
+}
+
+// CreateService creates a new service
+func (s *memoryStore) CreateService(ctx context.Context, req *CreateServiceRequest) (*ServicesModel, error) {
+	now := time.Now()
+	service := ServicesModel{
+		ID:                  uuid.New().String(),
+		Name:                req.Name,
+		Description:         req.Description,
+		ProviderName:        req.ProviderName,
+		ContactEmail:        req.ContactEmail,
+		ContactPhone:        req.ContactPhone,
+		WebsiteURL:          req.WebsiteURL,
+		Address:             req.Address,
+		ServiceType:         req.ServiceType,
+		EligibilityCriteria: req.EligibilityCriteria,
+		OrganisationID:      req.OrganisationID,
+		PerinatalStages:     append([]string{}, req.PerinatalStages...),
+		IsActive:            true,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+
+	s.save(service)
+
+	return &service, nil
+}
+
+// UpdateService is kept for the integer ID route and always fails, as in Postgres
+func (s *memoryStore) UpdateService(ctx context.Context, serviceID int, req *UpdateServiceRequest) (*ServicesModel, error) {
+	return nil, fmt.Errorf("UpdateService with int ID is deprecated, use UpdateServiceByUUID instead")
+}
+
+// UpdateServiceByUUID updates an active service
+func (s *memoryStore) UpdateServiceByUUID(ctx context.Context, serviceID string, req *UpdateServiceRequest) (*ServicesModel, error) {
+	s.mu.RLock()
+	service, ok := s.services[serviceID]
+	s.mu.RUnlock()
+	if !ok || !service.IsActive {
+		return nil, fmt.Errorf("service not found")
+	}
+
+	if req.Name != nil {
+		service.Name = *req.Name
+	}
+	if req.Description != nil {
+		service.Description = *req.Description
+	}
+	if req.ProviderName != nil {
+		service.ProviderName = *req.ProviderName
+	}
+	if req.ContactEmail != nil {
+		service.ContactEmail = req.ContactEmail
+	}
+	if req.ContactPhone != nil {
+		service.ContactPhone = req.ContactPhone
+	}
+	if req.WebsiteURL != nil {
+		service.WebsiteURL = req.WebsiteURL
+	}
+	if req.Address != nil {
+		service.Address = req.Address
+	}
+	if req.ServiceType != nil {
+		service.ServiceType = *req.ServiceType
+	}
+	if req.EligibilityCriteria != nil {
+		service.EligibilityCriteria = req.EligibilityCriteria
+	}
+	if req.OrganisationID != nil {
+		service.OrganisationID = req.OrganisationID
+	}
+	if req.PerinatalStages != nil {
+		service.PerinatalStages = append([]string{}, req.PerinatalStages...)
+	}
+	service.UpdatedAt = time.Now()
+
+	s.save(service)
+
+	return &service, nil
+}
+
+// DeactivateService is kept for the integer ID route and always fails, as in Postgres
+func (s *memoryStore) DeactivateService(ctx context.Context, serviceID int) error {
+	return fmt.Errorf("DeactivateService with int ID is deprecated, use DeactivateServiceByUUID instead")
+}
+
+// DeactivateServiceByUUID soft deletes a service
+func (s *memoryStore) DeactivateServiceByUUID(ctx context.Context, serviceID string) error {
+	s.mu.RLock()
+	service, ok := s.services[serviceID]
+	s.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("service not found")
+	}
+
+	service.IsActive = false
+	service.UpdatedAt = time.Now()
+	s.save(service)
+
+	return nil
+}
+
+// Helper functions
+
+// filter returns the services that match
+func (s *memoryStore) filter(match func(ServicesModel) bool) []ServicesModel {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var services []ServicesModel
+	for _, service := range s.services {
+		if match(service) {
+			services = append(services, service)
+		}
+	}
+	return services
+}
+
+// save stores the service and mirrors it into the shared catalog
+func (s *memoryStore) save(service ServicesModel) {
+	s.mu.Lock()
+	s.services[service.ID] = service
+	s.mu.Unlock()
+
+	s.db.PutItem(memdb.Item{
+		Type:           memdb.ItemService,
+		ID:             service.ID,
+		Title:          service.Name,
+		Description:    service.Description,
+		IsActive:       service.IsActive,
+		OrganisationID: service.OrganisationID,
+	})
+}
+
+func serviceCursor(service ServicesModel) pagination.Cursor {
+	return pagination.Cursor{SortValue: service.CreatedAt, ID: service.ID}
+}
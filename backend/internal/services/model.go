@@ -2,6 +2,8 @@ package services
 
 import (
 	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
 )
 
 // ServicesModel represents a mental health service
@@ -17,6 +19,8 @@ type ServicesModel struct {
 	ServiceType         string    `json:"service_type" db:"service_type"`
 	AvailabilityHours   string    `json:"availability_hours,omitempty" db:"availability_hours"`
 	EligibilityCriteria *string   `json:"eligibility_criteria,omitempty" db:"eligibility_criteria"`
+	OrganisationID      *string   `json:"organisation_id,omitempty" db:"organisation_id"`
+	PerinatalStages     []string  `json:"perinatal_stages" db:"perinatal_stages"` // Empty suits every stage
 	IsActive            bool      `json:"is_active" db:"is_active"`
 	CreatedAt           time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt           time.Time `json:"updated_at" db:"updated_at"`
@@ -24,37 +28,54 @@ type ServicesModel struct {
 
 // CreateServiceRequest represents the request to create a new service
 type CreateServiceRequest struct {
-	Name                string  `json:"name" validate:"required,min=2,max=255"`
-	Description         string  `json:"description" validate:"required"`
-	ProviderName        string  `json:"provider_name" validate:"required,min=2,max=255"`
-	ContactEmail        *string `json:"contact_email,omitempty" validate:"omitempty,email"`
-	ContactPhone        *string `json:"contact_phone,omitempty"`
-	WebsiteURL          *string `json:"website_url,omitempty" validate:"omitempty,url"`
-	Address             *string `json:"address,omitempty"`
-	ServiceType         string  `json:"service_type" validate:"required,oneof=online in_person hybrid"`
-	EligibilityCriteria *string `json:"eligibility_criteria,omitempty"`
+	Name                string   `json:"name" validate:"required,min=2,max=255"`
+	Description         string   `json:"description" validate:"required"`
+	ProviderName        string   `json:"provider_name" validate:"required,min=2,max=255"`
+	ContactEmail        *string  `json:"contact_email,omitempty" validate:"omitempty,email"`
+	ContactPhone        *string  `json:"contact_phone,omitempty"`
+	WebsiteURL          *string  `json:"website_url,omitempty" validate:"omitempty,url"`
+	Address             *string  `json:"address,omitempty"`
+	ServiceType         string   `json:"service_type" validate:"required,oneof=online in_person hybrid"`
+	EligibilityCriteria *string  `json:"eligibility_criteria,omitempty"`
+	OrganisationID      *string  `json:"organisation_id,omitempty"`
+	PerinatalStages     []string `json:"perinatal_stages,omitempty" validate:"omitempty,dive,oneof=first_trimester second_trimester third_trimester postnatal after_loss"`
 }
 
 // UpdateServiceRequest represents the request to update a service
 type UpdateServiceRequest struct {
-	Name                *string `json:"name,omitempty" validate:"omitempty,min=2,max=255"`
-	Description         *string `json:"description,omitempty"`
-	ProviderName        *string `json:"provider_name,omitempty" validate:"omitempty,min=2,max=255"`
-	ContactEmail        *string `json:"contact_email,omitempty" validate:"omitempty,email"`
-	ContactPhone        *string `json:"contact_phone,omitempty"`
-	WebsiteURL          *string `json:"website_url,omitempty" validate:"omitempty,url"`
-	Address             *string `json:"address,omitempty"`
-	ServiceType         *string `json:"service_type,omitempty" validate:"omitempty,oneof=online in_person hybrid"`
-	EligibilityCriteria *string `json:"eligibility_criteria,omitempty"`
+	Name                *string  `json:"name,omitempty" validate:"omitempty,min=2,max=255"`
+	Description         *string  `json:"description,omitempty"`
+	ProviderName        *string  `json:"provider_name,omitempty" validate:"omitempty,min=2,max=255"`
+	ContactEmail        *string  `json:"contact_email,omitempty" validate:"omitempty,email"`
+	ContactPhone        *string  `json:"contact_phone,omitempty"`
+	WebsiteURL          *string  `json:"website_url,omitempty" validate:"omitempty,url"`
+	Address             *string  `json:"address,omitempty"`
+	ServiceType         *string  `json:"service_type,omitempty" validate:"omitempty,oneof=online in_person hybrid"`
+	EligibilityCriteria *string  `json:"eligibility_criteria,omitempty"`
+	OrganisationID      *string  `json:"organisation_id,omitempty"`
+	PerinatalStages     []string `json:"perinatal_stages,omitempty" validate:"omitempty,dive,oneof=first_trimester second_trimester third_trimester postnatal after_loss"`
+}
+
+// ListServicesRequest represents the request for listing services
+type ListServicesRequest struct {
+	Page        int    `json:"page" validate:"min=1"`
+	PageSize    int    `json:"page_size" validate:"min=1,max=100"`
+	ServiceType string `json:"service_type,omitempty" validate:"omitempty,oneof=online in_person hybrid"`
+	Location    string `json:"location,omitempty"`
+	NHSReferral bool   `json:"nhs_referral,omitempty"`
+	Stage       string `json:"stage,omitempty" validate:"omitempty,oneof=first_trimester second_trimester third_trimester postnatal after_loss"`
+	pagination.Params
 }
 
 // ListServicesResponse represents the response for listing services
 type ListServicesResponse struct {
 	Services   []ServicesModel `json:"services"`
-	Total      int64           `json:"total"`
+	Total      *int64          `json:"total,omitempty"`
 	Page       int             `json:"page"`
 	PageSize   int             `json:"page_size"`
-	TotalPages int             `json:"total_pages"`
+	TotalPages *int            `json:"total_pages,omitempty"`
+	NextCursor *string         `json:"next_cursor,omitempty"`
+	PrevCursor *string         `json:"prev_cursor,omitempty"`
 }
 
 // SearchServicesRequest represents the request for searching services
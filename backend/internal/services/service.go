@@ -4,57 +4,90 @@ import (
 	"context"
 	"fmt"
 	"strings"
+
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/perinatal"
 )
 
-type Service struct {
-	store *Store
+type service struct {
+	store Store
 }
 
-func NewService(store *Store) *Service {
-	return &Service{
+func NewService(store Store) Service {
+	return &service{
 		store: store,
 	}
 }
 
 // ListServices retrieves a paginated list of services
-func (s *Service) ListServices(ctx context.Context, page, pageSize int, serviceType, location string, nhsReferral bool) (*ListServicesResponse, error) {
-	if page < 1 {
-		page = 1
+func (s *service) ListServices(ctx context.Context, req *ListServicesRequest) (*ListServicesResponse, error) {
+	if req.Page < 1 {
+		req.Page = 1
 	}
-	if pageSize < 1 || pageSize > 100 {
-		pageSize = 20
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
 	}
 
 	// Validate service type if provided
-	if serviceType != "" && !isValidServiceType(serviceType) {
-		return nil, fmt.Errorf("invalid service type: %s", serviceType)
+	if req.ServiceType != "" && !isValidServiceType(req.ServiceType) {
+		return nil, fmt.Errorf("invalid service type: %s", req.ServiceType)
+	}
+
+	if req.Stage != "" {
+		if _, err := perinatal.ParseStage(req.Stage); err != nil {
+			return nil, err
+		}
 	}
 
-	return s.store.ListServices(ctx, page, pageSize, serviceType, location, nhsReferral)
+	return s.store.ListServices(ctx, req)
 }
 
 // GetService retrieves a service by ID
-func (s *Service) GetService(ctx context.Context, serviceID string) (*ServicesModel, error) {
+func (s *service) GetService(ctx context.Context, serviceID string) (*ServicesModel, error) {
 	return s.store.GetServiceByUUID(ctx, serviceID)
 }
 
-// CreateService creates a new service
-func (s *Service) CreateService(ctx context.Context, req *CreateServiceRequest) (*ServicesModel, error) {
-	// Validate service type
-	if !isValidServiceType(req.ServiceType) {
-		return nil, fmt.Errorf("invalid service type: %s", req.ServiceType)
+// CreateService creates a new service owned by one of the caller's organisations
+func (s *service) CreateService(ctx context.Context, scope organisations.Scope, req *CreateServiceRequest) (*ServicesModel, error) {
+	if err := s.ValidateService(scope, req); err != nil {
+		return nil, err
 	}
 
-	// Additional validation
-	if err := validateServiceRequest(req); err != nil {
+	organisationID, err := owningOrganisation(scope, req.OrganisationID)
+	if err != nil {
 		return nil, err
 	}
+	req.OrganisationID = organisationID
 
 	return s.store.CreateService(ctx, req)
 }
 
+// ValidateService checks a new service without saving it
+func (s *service) ValidateService(scope organisations.Scope, req *CreateServiceRequest) error {
+	if _, err := owningOrganisation(scope, req.OrganisationID); err != nil {
+		return err
+	}
+
+	// Validate service type
+	if !isValidServiceType(req.ServiceType) {
+		return fmt.Errorf("invalid service type: %s", req.ServiceType)
+	}
+
+	if err := perinatal.ValidateStages(req.PerinatalStages); err != nil {
+		return err
+	}
+
+	// Additional validation
+	return validateServiceRequest(req)
+}
+
 // UpdateService updates a service
-func (s *Service) UpdateService(ctx context.Context, serviceID int, req *UpdateServiceRequest) (*ServicesModel, error) {
+func (s *service) UpdateService(ctx context.Context, scope organisations.Scope, serviceID int, req *UpdateServiceRequest) (*ServicesModel, error) {
+	// Ownership can only move to another organisation within the caller's scope
+	if req.OrganisationID != nil && !scope.Allows(*req.OrganisationID) {
+		return nil, organisations.ErrOutOfScope
+	}
+
 	// Validate service type if provided
 	if req.ServiceType != nil && !isValidServiceType(*req.ServiceType) {
 		return nil, fmt.Errorf("invalid service type: %s", *req.ServiceType)
@@ -63,13 +96,40 @@ func (s *Service) UpdateService(ctx context.Context, serviceID int, req *UpdateS
 	return s.store.UpdateService(ctx, serviceID, req)
 }
 
-// DeleteService deactivates a service
-func (s *Service) DeleteService(ctx context.Context, serviceID int) error {
-	return s.store.DeactivateService(ctx, serviceID)
+// UpdateServiceByUUID updates a service within the caller's scope
+func (s *service) UpdateServiceByUUID(ctx context.Context, scope organisations.Scope, serviceID string, req *UpdateServiceRequest) (*ServicesModel, error) {
+	if _, err := s.getInScope(ctx, scope, serviceID); err != nil {
+		return nil, err
+	}
+
+	// Ownership can only move to another organisation within the caller's scope
+	if req.OrganisationID != nil && !scope.Allows(*req.OrganisationID) {
+		return nil, organisations.ErrOutOfScope
+	}
+
+	// Validate service type if provided
+	if req.ServiceType != nil && !isValidServiceType(*req.ServiceType) {
+		return nil, fmt.Errorf("invalid service type: %s", *req.ServiceType)
+	}
+
+	if err := perinatal.ValidateStages(req.PerinatalStages); err != nil {
+		return nil, err
+	}
+
+	return s.store.UpdateServiceByUUID(ctx, serviceID, req)
+}
+
+// DeleteService deactivates a service within the caller's scope
+func (s *service) DeleteService(ctx context.Context, scope organisations.Scope, serviceID string) error {
+	if _, err := s.getInScope(ctx, scope, serviceID); err != nil {
+		return err
+	}
+
+	return s.store.DeactivateServiceByUUID(ctx, serviceID)
 }
 
 // SearchServices searches for services by query
-func (s *Service) SearchServices(ctx context.Context, query string, page, pageSize int) (*ListServicesResponse, error) {
+func (s *service) SearchServices(ctx context.Context, query string, page, pageSize int) (*ListServicesResponse, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -90,13 +150,13 @@ func (s *Service) SearchServices(ctx context.Context, query string, page, pageSi
 	return s.store.SearchServices(ctx, query, page, pageSize)
 }
 
-// GetServiceStats retrieves service statistics
-func (s *Service) GetServiceStats(ctx context.Context) (*ServiceStats, error) {
-	return s.store.GetServiceStats(ctx)
+// GetServiceStats retrieves statistics for the services within the caller's scope
+func (s *service) GetServiceStats(ctx context.Context, scope organisations.Scope) (*ServiceStats, error) {
+	return s.store.GetServiceStats(ctx, scope)
 }
 
 // GetFeaturedServices retrieves a limited number of featured services
-func (s *Service) GetFeaturedServices(ctx context.Context, limit int) (*ListServicesResponse, error) {
+func (s *service) GetFeaturedServices(ctx context.Context, limit int) (*ListServicesResponse, error) {
 	if limit < 1 {
 		limit = 5
 	}
@@ -106,11 +166,27 @@ func (s *Service) GetFeaturedServices(ctx context.Context, limit int) (*ListServ
 
 	// For now, just return the most recent services as "featured"
 	// In the future, you might want to add a "is_featured" field to the database
-	return s.store.ListServices(ctx, 1, limit, "", "", false)
+	return s.store.ListServices(ctx, &ListServicesRequest{
+		Page:     1,
+		PageSize: limit,
+	})
 }
 
 // Helper functions
 
+func (s *service) getInScope(ctx context.Context, scope organisations.Scope, serviceID string) (*ServicesModel, error) {
+	service, err := s.store.GetServiceByUUID(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !scope.Covers(service.OrganisationID) {
+		return nil, organisations.ErrOutOfScope
+	}
+
+	return service, nil
+}
+
 // isValidServiceType validates service type
 func isValidServiceType(serviceType string) bool {
 	validTypes := []string{"online", "in_person", "hybrid"}
@@ -165,11 +241,36 @@ func validateServiceRequest(req *CreateServiceRequest) error {
 	return nil
 }
 
+// owningOrganisation checks the requested owner is within the scope. Staff who
+// belong to a single organisation own what they create by default.
+func owningOrganisation(scope organisations.Scope, organisationID *string) (*string, error) {
+	if organisationID != nil {
+		if !scope.Allows(*organisationID) {
+			return nil, organisations.ErrOutOfScope
+		}
+		return organisationID, nil
+	}
+
+	if scope.All {
+		return nil, nil
+	}
+
+	if len(scope.OrganisationIDs) != 1 {
+		return nil, fmt.Errorf("organisation_id is required")
+	}
+
+	return &scope.OrganisationIDs[0], nil
+}
+
 // GetServicesByType retrieves services filtered by type
-func (s *Service) GetServicesByType(ctx context.Context, serviceType string, page, pageSize int) (*ListServicesResponse, error) {
+func (s *service) GetServicesByType(ctx context.Context, serviceType string, page, pageSize int) (*ListServicesResponse, error) {
 	if !isValidServiceType(serviceType) {
 		return nil, fmt.Errorf("invalid service type: %s", serviceType)
 	}
 
-	return s.store.ListServices(ctx, page, pageSize, serviceType, "", false)
+	return s.store.ListServices(ctx, &ListServicesRequest{
+		Page:        page,
+		PageSize:    pageSize,
+		ServiceType: serviceType,
+	})
 }
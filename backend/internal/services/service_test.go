@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+)
+
+const (
+	leedsTrust = "4b1d9a7e-1c2f-4e3a-8b5d-6f7a8b9c0d1e"
+	yorkTrust  = "9e8d7c6b-5a4f-4e3d-8c2b-1a0f9e8d7c6b"
+)
+
+var (
+	allScope   = organisations.Scope{All: true}
+	leedsScope = organisations.Scope{OrganisationIDs: []string{leedsTrust}}
+)
+
+func newRequest(name string) *CreateServiceRequest {
+	phone := "0113 000 0000"
+	return &CreateServiceRequest{
+		Name:         name,
+		Description:  "Perinatal mental health support",
+		ProviderName: "NHS",
+		ContactPhone: &phone,
+		ServiceType:  "in_person",
+	}
+}
+
+func TestCreateServiceOwnership(t *testing.T) {
+	york := yorkTrust
+	tests := []struct {
+		name      string
+		scope     organisations.Scope
+		owner     *string
+		wantOwner string
+		wantErr   error
+	}{
+		{"staff in one organisation own it by default", leedsScope, nil, leedsTrust, nil},
+		{"staff can't create for another organisation", leedsScope, &york, "", organisations.ErrOutOfScope},
+		{"super admins choose the owner", allScope, &york, yorkTrust, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(NewMemoryStore(memdb.New()))
+			req := newRequest("Perinatal Team")
+			req.OrganisationID = tt.owner
+
+			created, err := svc.CreateService(context.Background(), tt.scope, req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("CreateService() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateService() error = %v", err)
+			}
+			if created.OrganisationID == nil || *created.OrganisationID != tt.wantOwner {
+				t.Errorf("CreateService() owner = %v, want %s", created.OrganisationID, tt.wantOwner)
+			}
+		})
+	}
+
+	twoTrusts := organisations.Scope{OrganisationIDs: []string{leedsTrust, yorkTrust}}
+	if _, err := NewService(NewMemoryStore(memdb.New())).CreateService(context.Background(), twoTrusts, newRequest("Perinatal Team")); err == nil {
+		t.Error("CreateService() picked an owner for staff in two organisations")
+	}
+}
+
+func TestCreateServiceValidation(t *testing.T) {
+	svc := NewService(NewMemoryStore(memdb.New()))
+
+	noContact := newRequest("Perinatal Team")
+	noContact.ContactPhone = nil
+	if _, err := svc.CreateService(context.Background(), allScope, noContact); err == nil || !strings.Contains(err.Error(), "contact method") {
+		t.Errorf("CreateService() without contact details error = %v", err)
+	}
+
+	badType := newRequest("Perinatal Team")
+	badType.ServiceType = "telepathy"
+	if _, err := svc.CreateService(context.Background(), allScope, badType); err == nil {
+		t.Error("CreateService() accepted an unknown service type")
+	}
+}
+
+func TestServiceChangesStayWithinScope(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(NewMemoryStore(memdb.New()))
+
+	york := yorkTrust
+	yorkRequest := newRequest("York Perinatal Team")
+	yorkRequest.OrganisationID = &york
+	yorkService, err := svc.CreateService(ctx, allScope, yorkRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leedsService, err := svc.CreateService(ctx, leedsScope, newRequest("Leeds Perinatal Team"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	renamed := "Renamed"
+	if _, err := svc.UpdateServiceByUUID(ctx, leedsScope, yorkService.ID, &UpdateServiceRequest{Name: &renamed}); !errors.Is(err, organisations.ErrOutOfScope) {
+		t.Errorf("UpdateServiceByUUID() of another organisation's service error = %v, want ErrOutOfScope", err)
+	}
+	if _, err := svc.UpdateServiceByUUID(ctx, leedsScope, leedsService.ID, &UpdateServiceRequest{OrganisationID: &york}); !errors.Is(err, organisations.ErrOutOfScope) {
+		t.Errorf("UpdateServiceByUUID() handing a service to another organisation error = %v, want ErrOutOfScope", err)
+	}
+
+	if err := svc.DeleteService(ctx, leedsScope, yorkService.ID); !errors.Is(err, organisations.ErrOutOfScope) {
+		t.Errorf("DeleteService() of another organisation's service error = %v, want ErrOutOfScope", err)
+	}
+	if _, err := svc.GetService(ctx, yorkService.ID); err != nil {
+		t.Errorf("service out of scope was removed: %v", err)
+	}
+
+	if err := svc.DeleteService(ctx, leedsScope, leedsService.ID); err != nil {
+		t.Fatalf("DeleteService() error = %v", err)
+	}
+	if _, err := svc.GetService(ctx, leedsService.ID); err == nil {
+		t.Error("GetService() returned a deleted service")
+	}
+
+	stats, _ := svc.GetServiceStats(ctx, leedsScope)
+	if stats.TotalServices != 1 || stats.InactiveServices != 1 {
+		t.Errorf("GetServiceStats() for Leeds = %+v, want only its deleted service", stats)
+	}
+}
+
+func TestSearchServicesRanksNameMatchesFirst(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(NewMemoryStore(memdb.New()))
+
+	described := newRequest("Community Team")
+	described.Description = "Includes a birth trauma clinic"
+	for _, req := range []*CreateServiceRequest{described, newRequest("Birth Trauma Clinic")} {
+		if _, err := svc.CreateService(ctx, allScope, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := svc.SearchServices(ctx, "  birth trauma ", 1, 10)
+	if err != nil {
+		t.Fatalf("SearchServices() error = %v", err)
+	}
+	if len(results.Services) != 2 || results.Services[0].Name != "Birth Trauma Clinic" {
+		t.Errorf("SearchServices() = %v, want the name match first", results.Services)
+	}
+
+	if _, err := svc.SearchServices(ctx, "b", 1, 10); err == nil {
+		t.Error("SearchServices() accepted a one-character query")
+	}
+}
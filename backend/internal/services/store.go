@@ -8,22 +8,36 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/perinatal-mental-health-app/backend/internal/db"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
 )
 
-type Store struct {
-	db *pgxpool.Pool
+type store struct {
+	db *db.Handle
 }
 
-func NewStore(db *pgxpool.Pool) *Store {
-	return &Store{
+func NewStore(db *db.Handle) Store {
+	return &store{
 		db: db,
 	}
 }
 
-// ListServices retrieves a paginated list of services with filtering
-func (s *Store) ListServices(ctx context.Context, page, pageSize int, serviceType, location string, nhsReferral bool) (*ListServicesResponse, error) {
-	offset := (page - 1) * pageSize
+// servicesKeyset is the stable sort order used for cursor pagination
+var servicesKeyset = pagination.Keyset{SortColumn: "created_at", IDColumn: "id"}
+
+// ListServices retrieves a paginated list of services with filtering. A cursor in
+// the request switches from LIMIT/OFFSET to keyset pagination.
+func (s *store) ListServices(ctx context.Context, req *ListServicesRequest) (*ListServicesResponse, error) {
+	cursor, err := req.DecodeCursor()
+	if err != nil {
+		return nil, err
+	}
+
+	offset := 0
+	if cursor == nil {
+		offset = (req.Page - 1) * req.PageSize
+	}
 
 	var whereClause []string
 	var args []interface{}
@@ -33,16 +47,23 @@ func (s *Store) ListServices(ctx context.Context, page, pageSize int, serviceTyp
 	whereClause = append(whereClause, "is_active = true")
 
 	// Add service type filter
-	if serviceType != "" {
+	if req.ServiceType != "" {
 		whereClause = append(whereClause, fmt.Sprintf("service_type = $%d", argIndex))
-		args = append(args, serviceType)
+		args = append(args, req.ServiceType)
 		argIndex++
 	}
 
 	// Add location filter (search in address field)
-	if location != "" {
+	if req.Location != "" {
 		whereClause = append(whereClause, fmt.Sprintf("address ILIKE $%d", argIndex))
-		args = append(args, "%"+location+"%")
+		args = append(args, "%"+req.Location+"%")
+		argIndex++
+	}
+
+	// Add perinatal stage filter; untagged services suit every stage
+	if req.Stage != "" {
+		whereClause = append(whereClause, fmt.Sprintf("(cardinality(perinatal_stages) = 0 OR $%d = ANY(perinatal_stages))", argIndex))
+		args = append(args, req.Stage)
 		argIndex++
 	}
 
@@ -52,33 +73,53 @@ func (s *Store) ListServices(ctx context.Context, page, pageSize int, serviceTyp
 		whereSQL = "WHERE " + strings.Join(whereClause, " AND ")
 	}
 
+	response := &ListServicesResponse{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
 	// Count total services
-	countQuery := fmt.Sprintf(`
-		SELECT COUNT(*) 
-		FROM services 
-		%s
-	`, whereSQL)
+	if req.WantTotal() {
+		countQuery := fmt.Sprintf(`
+			SELECT COUNT(*) 
+			FROM services 
+			%s
+		`, whereSQL)
+
+		var total int64
+		err := s.db.Reader(ctx).QueryRow(ctx, countQuery, args...).Scan(&total)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count services: %w", err)
+		}
 
-	var total int64
-	err := s.db.QueryRow(ctx, countQuery, args...).Scan(&total)
-	if err != nil {
-		return nil, fmt.Errorf("failed to count services: %w", err)
+		totalPages := pagination.TotalPages(total, req.PageSize)
+		response.Total = &total
+		response.TotalPages = &totalPages
+	}
+
+	// Continue from the cursor position
+	if cursor != nil {
+		keysetSQL, keysetArgs := servicesKeyset.Where(cursor, argIndex)
+		whereClause = append(whereClause, keysetSQL)
+		args = append(args, keysetArgs...)
+		argIndex += len(keysetArgs)
+		whereSQL = "WHERE " + strings.Join(whereClause, " AND ")
 	}
 
-	// Get services with pagination
+	// Get services with pagination, reading one extra row to detect a further page
 	query := fmt.Sprintf(`
 		SELECT id, name, description, provider_name, contact_email, contact_phone, 
 			   website_url, address, service_type, availability_hours, eligibility_criteria,
-			   is_active, created_at, updated_at
+			   organisation_id, is_active, created_at, updated_at, perinatal_stages
 		FROM services
 		%s
-		ORDER BY created_at DESC
+		ORDER BY %s
 		LIMIT $%d OFFSET $%d
-	`, whereSQL, argIndex, argIndex+1)
+	`, whereSQL, servicesKeyset.OrderBy(cursor), argIndex, argIndex+1)
 
-	args = append(args, pageSize, offset)
+	args = append(args, req.PageSize+1, offset)
 
-	rows, err := s.db.Query(ctx, query, args...)
+	rows, err := s.db.Reader(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query services: %w", err)
 	}
@@ -101,9 +142,11 @@ func (s *Store) ListServices(ctx context.Context, page, pageSize int, serviceTyp
 			&service.ServiceType,
 			&availabilityHours,
 			&service.EligibilityCriteria,
+			&service.OrganisationID,
 			&service.IsActive,
 			&service.CreatedAt,
 			&service.UpdatedAt,
+			&service.PerinatalStages,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan service: %w", err)
@@ -121,23 +164,20 @@ func (s *Store) ListServices(ctx context.Context, page, pageSize int, serviceTyp
 		return nil, fmt.Errorf("row iteration error: %w", err)
 	}
 
-	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+	response.Services, response.NextCursor, response.PrevCursor = pagination.Paginate(services, req.PageSize, cursor, offset,
+		func(service ServicesModel) pagination.Cursor {
+			return pagination.Cursor{SortValue: service.CreatedAt, ID: service.ID}
+		})
 
-	return &ListServicesResponse{
-		Services:   services,
-		Total:      total,
-		Page:       page,
-		PageSize:   pageSize,
-		TotalPages: totalPages,
-	}, nil
+	return response, nil
 }
 
 // GetServiceByUUID retrieves a service by UUID string
-func (s *Store) GetServiceByUUID(ctx context.Context, serviceID string) (*ServicesModel, error) {
+func (s *store) GetServiceByUUID(ctx context.Context, serviceID string) (*ServicesModel, error) {
 	query := `
 		SELECT id, name, description, provider_name, contact_email, contact_phone, 
 			   website_url, address, service_type, availability_hours, eligibility_criteria,
-			   is_active, created_at, updated_at
+			   organisation_id, is_active, created_at, updated_at, perinatal_stages
 		FROM services
 		WHERE id = $1 AND is_active = true
 	`
@@ -145,7 +185,7 @@ func (s *Store) GetServiceByUUID(ctx context.Context, serviceID string) (*Servic
 	var service ServicesModel
 	var availabilityHours *string
 
-	err := s.db.QueryRow(ctx, query, serviceID).Scan(
+	err := s.db.Reader(ctx).QueryRow(ctx, query, serviceID).Scan(
 		&service.ID,
 		&service.Name,
 		&service.Description,
@@ -157,9 +197,11 @@ func (s *Store) GetServiceByUUID(ctx context.Context, serviceID string) (*Servic
 		&service.ServiceType,
 		&availabilityHours,
 		&service.EligibilityCriteria,
+		&service.OrganisationID,
 		&service.IsActive,
 		&service.CreatedAt,
 		&service.UpdatedAt,
+		&service.PerinatalStages,
 	)
 
 	if err != nil {
@@ -178,18 +220,23 @@ func (s *Store) GetServiceByUUID(ctx context.Context, serviceID string) (*Servic
 }
 
 // CreateService creates a new service
-func (s *Store) CreateService(ctx context.Context, req *CreateServiceRequest) (*ServicesModel, error) {
+func (s *store) CreateService(ctx context.Context, req *CreateServiceRequest) (*ServicesModel, error) {
 	serviceID := uuid.New()
 	now := time.Now()
 
+	stages := req.PerinatalStages
+	if stages == nil {
+		stages = []string{}
+	}
+
 	query := `
 		INSERT INTO services (id, name, description, provider_name, contact_email, contact_phone, 
 							  website_url, address, service_type, availability_hours, eligibility_criteria,
-							  is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+							  organisation_id, is_active, created_at, updated_at, perinatal_stages)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		RETURNING id, name, description, provider_name, contact_email, contact_phone, 
 				  website_url, address, service_type, availability_hours, eligibility_criteria,
-				  is_active, created_at, updated_at
+				  organisation_id, is_active, created_at, updated_at, perinatal_stages
 	`
 
 	var service ServicesModel
@@ -207,9 +254,11 @@ func (s *Store) CreateService(ctx context.Context, req *CreateServiceRequest) (*
 		req.ServiceType,
 		nil, // availability_hours (JSON)
 		req.EligibilityCriteria,
+		req.OrganisationID,
 		true, // is_active
 		now,  // created_at
 		now,  // updated_at
+		stages,
 	).Scan(
 		&service.ID,
 		&service.Name,
@@ -222,9 +271,11 @@ func (s *Store) CreateService(ctx context.Context, req *CreateServiceRequest) (*
 		&service.ServiceType,
 		&availabilityHours,
 		&service.EligibilityCriteria,
+		&service.OrganisationID,
 		&service.IsActive,
 		&service.CreatedAt,
 		&service.UpdatedAt,
+		&service.PerinatalStages,
 	)
 
 	if err != nil {
@@ -240,13 +291,13 @@ func (s *Store) CreateService(ctx context.Context, req *CreateServiceRequest) (*
 }
 
 // UpdateService updates a service - Fixed to handle UUID properly
-func (s *Store) UpdateService(ctx context.Context, serviceID int, req *UpdateServiceRequest) (*ServicesModel, error) {
+func (s *store) UpdateService(ctx context.Context, serviceID int, req *UpdateServiceRequest) (*ServicesModel, error) {
 	// Note: This method signature expects int but our ID is UUID string
 	return nil, fmt.Errorf("UpdateService with int ID is deprecated, use UpdateServiceByUUID instead")
 }
 
 // UpdateServiceByUUID updates a service by UUID
-func (s *Store) UpdateServiceByUUID(ctx context.Context, serviceID string, req *UpdateServiceRequest) (*ServicesModel, error) {
+func (s *store) UpdateServiceByUUID(ctx context.Context, serviceID string, req *UpdateServiceRequest) (*ServicesModel, error) {
 	var setParts []string
 	var args []interface{}
 	argIndex := 1
@@ -305,6 +356,18 @@ func (s *Store) UpdateServiceByUUID(ctx context.Context, serviceID string, req *
 		argIndex++
 	}
 
+	if req.OrganisationID != nil {
+		setParts = append(setParts, fmt.Sprintf("organisation_id = $%d", argIndex))
+		args = append(args, *req.OrganisationID)
+		argIndex++
+	}
+
+	if req.PerinatalStages != nil {
+		setParts = append(setParts, fmt.Sprintf("perinatal_stages = $%d", argIndex))
+		args = append(args, req.PerinatalStages)
+		argIndex++
+	}
+
 	if len(setParts) == 0 {
 		return s.GetServiceByUUID(ctx, serviceID)
 	}
@@ -319,7 +382,7 @@ func (s *Store) UpdateServiceByUUID(ctx context.Context, serviceID string, req *
 		WHERE id = $%d AND is_active = true
 		RETURNING id, name, description, provider_name, contact_email, contact_phone, 
 				  website_url, address, service_type, availability_hours, eligibility_criteria,
-				  is_active, created_at, updated_at
+				  organisation_id, is_active, created_at, updated_at, perinatal_stages
 	`, strings.Join(setParts, ", "), argIndex)
 
 	args = append(args, serviceID)
@@ -339,9 +402,11 @@ func (s *Store) UpdateServiceByUUID(ctx context.Context, serviceID string, req *
 		&service.ServiceType,
 		&availabilityHours,
 		&service.EligibilityCriteria,
+		&service.OrganisationID,
 		&service.IsActive,
 		&service.CreatedAt,
 		&service.UpdatedAt,
+		&service.PerinatalStages,
 	)
 
 	if err != nil {
@@ -360,13 +425,13 @@ func (s *Store) UpdateServiceByUUID(ctx context.Context, serviceID string, req *
 }
 
 // DeactivateService soft deletes a service - Fixed to handle UUID properly
-func (s *Store) DeactivateService(ctx context.Context, serviceID int) error {
+func (s *store) DeactivateService(ctx context.Context, serviceID int) error {
 	// Note: This method signature expects int but our ID is UUID string
 	return fmt.Errorf("DeactivateService with int ID is deprecated, use DeactivateServiceByUUID instead")
 }
 
 // DeactivateServiceByUUID soft deletes a service by UUID
-func (s *Store) DeactivateServiceByUUID(ctx context.Context, serviceID string) error {
+func (s *store) DeactivateServiceByUUID(ctx context.Context, serviceID string) error {
 	query := `
 		UPDATE services 
 		SET is_active = false, updated_at = $1
@@ -387,7 +452,7 @@ func (s *Store) DeactivateServiceByUUID(ctx context.Context, serviceID string) e
 
 // SearchServices searches services by name or description
 // SearchServices searches services by name, description, provider_name, or service_type
-func (s *Store) SearchServices(ctx context.Context, query string, page, pageSize int) (*ListServicesResponse, error) {
+func (s *store) SearchServices(ctx context.Context, query string, page, pageSize int) (*ListServicesResponse, error) {
 	offset := (page - 1) * pageSize
 
 	searchQuery := "%" + strings.ToLower(query) + "%"
@@ -404,7 +469,7 @@ func (s *Store) SearchServices(ctx context.Context, query string, page, pageSize
 	`
 
 	var total int64
-	err := s.db.QueryRow(ctx, countSQL, searchQuery).Scan(&total)
+	err := s.db.Reader(ctx).QueryRow(ctx, countSQL, searchQuery).Scan(&total)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count search results: %w", err)
 	}
@@ -413,7 +478,7 @@ func (s *Store) SearchServices(ctx context.Context, query string, page, pageSize
 	searchSQL := `
 		SELECT id, name, description, provider_name, contact_email, contact_phone, 
 			   website_url, address, service_type, availability_hours, eligibility_criteria,
-			   is_active, created_at, updated_at
+			   organisation_id, is_active, created_at, updated_at, perinatal_stages
 		FROM services
 		WHERE is_active = true 
 		AND (LOWER(name) LIKE $1 
@@ -431,7 +496,7 @@ func (s *Store) SearchServices(ctx context.Context, query string, page, pageSize
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := s.db.Query(ctx, searchSQL, searchQuery, pageSize, offset)
+	rows, err := s.db.Reader(ctx).Query(ctx, searchSQL, searchQuery, pageSize, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search services: %w", err)
 	}
@@ -454,9 +519,11 @@ func (s *Store) SearchServices(ctx context.Context, query string, page, pageSize
 			&service.ServiceType,
 			&availabilityHours,
 			&service.EligibilityCriteria,
+			&service.OrganisationID,
 			&service.IsActive,
 			&service.CreatedAt,
 			&service.UpdatedAt,
+			&service.PerinatalStages,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan service: %w", err)
@@ -478,44 +545,51 @@ func (s *Store) SearchServices(ctx context.Context, query string, page, pageSize
 
 	return &ListServicesResponse{
 		Services:   services,
-		Total:      total,
+		Total:      &total,
 		Page:       page,
 		PageSize:   pageSize,
-		TotalPages: totalPages,
+		TotalPages: &totalPages,
 	}, nil
 }
 
-// GetServiceStats retrieves service statistics
-func (s *Store) GetServiceStats(ctx context.Context) (*ServiceStats, error) {
+// GetServiceStats retrieves statistics for the services owned by the scope's organisations
+func (s *store) GetServiceStats(ctx context.Context, scope organisations.Scope) (*ServiceStats, error) {
+	// Restrict every count to the caller's organisations
+	scopeSQL := ""
+	condition, args := scope.Condition("organisation_id", 1)
+	if condition != "" {
+		scopeSQL = " AND " + condition
+	}
+
 	// Get total services count
 	var totalServices, activeServices, inactiveServices int64
 
 	// Count all services
-	err := s.db.QueryRow(ctx, "SELECT COUNT(*) FROM services").Scan(&totalServices)
+	err := s.db.Reader(ctx).QueryRow(ctx, "SELECT COUNT(*) FROM services WHERE true"+scopeSQL, args...).Scan(&totalServices)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count total services: %w", err)
 	}
 
 	// Count active services
-	err = s.db.QueryRow(ctx, "SELECT COUNT(*) FROM services WHERE is_active = true").Scan(&activeServices)
+	err = s.db.Reader(ctx).QueryRow(ctx, "SELECT COUNT(*) FROM services WHERE is_active = true"+scopeSQL, args...).Scan(&activeServices)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count active services: %w", err)
 	}
 
 	// Count inactive services
-	err = s.db.QueryRow(ctx, "SELECT COUNT(*) FROM services WHERE is_active = false").Scan(&inactiveServices)
+	err = s.db.Reader(ctx).QueryRow(ctx, "SELECT COUNT(*) FROM services WHERE is_active = false"+scopeSQL, args...).Scan(&inactiveServices)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count inactive services: %w", err)
 	}
 
 	// Get services by type
 	servicesByType := make(map[string]int64)
-	rows, err := s.db.Query(ctx, `
+	rows, err := s.db.Reader(ctx).Query(ctx, `
 		SELECT service_type, COUNT(*) 
 		FROM services 
-		WHERE is_active = true 
+		WHERE is_active = true`+scopeSQL+`
 		GROUP BY service_type
-	`)
+	`, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get services by type: %w", err)
 	}
@@ -2,10 +2,12 @@
 package support_groups
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
 )
 
 type handler struct {
@@ -37,6 +39,7 @@ func (h *handler) ListSupportGroups(c echo.Context) error {
 
 	category := c.QueryParam("category")
 	platform := c.QueryParam("platform")
+	stage := c.QueryParam("stage")
 	search := c.QueryParam("search")
 
 	// If search query is provided, use search functionality
@@ -51,7 +54,7 @@ func (h *handler) ListSupportGroups(c echo.Context) error {
 	}
 
 	// Regular list with filters
-	groups, err := h.service.ListSupportGroups(c.Request().Context(), page, pageSize, category, platform)
+	groups, err := h.service.ListSupportGroups(c.Request().Context(), page, pageSize, category, platform, stage)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": err.Error(),
@@ -310,7 +313,12 @@ func (h *handler) CreateSupportGroup(c echo.Context) error {
 		})
 	}
 
-	group, err := h.service.CreateSupportGroup(c.Request().Context(), &req)
+	group, err := h.service.CreateSupportGroup(c.Request().Context(), organisations.ScopeFromContext(c), &req)
+	if errors.Is(err, organisations.ErrOutOfScope) {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": err.Error(),
+		})
+	}
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": err.Error(),
@@ -342,7 +350,12 @@ func (h *handler) UpdateSupportGroup(c echo.Context) error {
 		})
 	}
 
-	group, err := h.service.UpdateSupportGroup(c.Request().Context(), groupID, &req)
+	group, err := h.service.UpdateSupportGroup(c.Request().Context(), organisations.ScopeFromContext(c), groupID, &req)
+	if errors.Is(err, organisations.ErrOutOfScope) {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": err.Error(),
+		})
+	}
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{
 			"error": err.Error(),
@@ -361,7 +374,12 @@ func (h *handler) DeleteSupportGroup(c echo.Context) error {
 		})
 	}
 
-	err := h.service.DeleteSupportGroup(c.Request().Context(), groupID)
+	err := h.service.DeleteSupportGroup(c.Request().Context(), organisations.ScopeFromContext(c), groupID)
+	if errors.Is(err, organisations.ErrOutOfScope) {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": err.Error(),
+		})
+	}
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{
 			"error": err.Error(),
@@ -389,7 +407,12 @@ func (h *handler) RemoveUserFromGroup(c echo.Context) error {
 		})
 	}
 
-	err := h.service.RemoveUserFromGroup(c.Request().Context(), userID, groupID)
+	err := h.service.RemoveUserFromGroup(c.Request().Context(), organisations.ScopeFromContext(c), userID, groupID)
+	if errors.Is(err, organisations.ErrOutOfScope) {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": err.Error(),
+		})
+	}
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": err.Error(),
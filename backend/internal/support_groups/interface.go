@@ -4,17 +4,21 @@ package support_groups
 import (
 	"context"
 	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/events"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
 )
 
 // Service defines the interface for support groups business logic
 // Change all int groupID parameters to string
 type Service interface {
-	ListSupportGroups(ctx context.Context, page, pageSize int, category, platform string) (*ListSupportGroupsResponse, error)
+	ListSupportGroups(ctx context.Context, page, pageSize int, category, platform, stage string) (*ListSupportGroupsResponse, error)
 	GetSupportGroup(ctx context.Context, groupID string) (*SupportGroup, error) // Changed
 	SearchSupportGroups(ctx context.Context, query string, page, pageSize int) (*ListSupportGroupsResponse, error)
 	GetSupportGroupsByCategory(ctx context.Context, category string, page, pageSize int) (*ListSupportGroupsResponse, error)
 	GetSupportGroupsByPlatform(ctx context.Context, platform string, page, pageSize int) (*ListSupportGroupsResponse, error)
 	GetUserGroups(ctx context.Context, userID string) ([]SupportGroup, error)
+	// GetGroupsForUsers returns the active groups of each of the users, keyed by user ID
+	GetGroupsForUsers(ctx context.Context, userIDs []string) (map[string][]SupportGroup, error)
 	JoinGroup(ctx context.Context, userID string, groupID string) error             // Changed
 	LeaveGroup(ctx context.Context, userID string, groupID string) error            // Changed
 	GetGroupMembers(ctx context.Context, groupID string) ([]GroupMembership, error) // Changed
@@ -22,25 +26,31 @@ type Service interface {
 	GetSupportGroupStats(ctx context.Context) (*SupportGroupStats, error)
 
 	// Admin/Staff only methods
-	CreateSupportGroup(ctx context.Context, req *CreateSupportGroupRequest) (*SupportGroup, error)
-	UpdateSupportGroup(ctx context.Context, groupID string, req *UpdateSupportGroupRequest) (*SupportGroup, error) // Changed
-	DeleteSupportGroup(ctx context.Context, groupID string) error                                                  // Changed
-	RemoveUserFromGroup(ctx context.Context, userID string, groupID string) error                                  // Changed
+	CreateSupportGroup(ctx context.Context, scope organisations.Scope, req *CreateSupportGroupRequest) (*SupportGroup, error)
+	UpdateSupportGroup(ctx context.Context, scope organisations.Scope, groupID string, req *UpdateSupportGroupRequest) (*SupportGroup, error)
+	ValidateSupportGroup(scope organisations.Scope, req *CreateSupportGroupRequest) error
+	DeleteSupportGroup(ctx context.Context, scope organisations.Scope, groupID string) error
+	RemoveUserFromGroup(ctx context.Context, scope organisations.Scope, userID string, groupID string) error
+
+	// Event subscribers
+	OnUserDeactivated(ctx context.Context, event events.UserDeactivatedPayload) error
 }
 
 // Store defines the interface for support groups data persistence
 type Store interface {
-	ListSupportGroups(ctx context.Context, page, pageSize int, category, platform string) (*ListSupportGroupsResponse, error)
+	ListSupportGroups(ctx context.Context, page, pageSize int, category, platform, stage string) (*ListSupportGroupsResponse, error)
 	GetSupportGroupByID(ctx context.Context, groupID string) (*SupportGroup, error) // Changed
 	SearchSupportGroups(ctx context.Context, query string, page, pageSize int) (*ListSupportGroupsResponse, error)
 	GetSupportGroupsByCategory(ctx context.Context, category string, page, pageSize int) (*ListSupportGroupsResponse, error)
 	GetSupportGroupsByPlatform(ctx context.Context, platform string, page, pageSize int) (*ListSupportGroupsResponse, error)
 	GetUserGroups(ctx context.Context, userID string) ([]SupportGroup, error)
+	GetGroupsForUsers(ctx context.Context, userIDs []string) (map[string][]SupportGroup, error)
 	GetGroupMembers(ctx context.Context, groupID string) ([]GroupMembership, error) // Changed
 	IsUserMember(ctx context.Context, userID string, groupID string) (bool, error)  // Changed
 	JoinGroup(ctx context.Context, userID string, groupID string) error             // Changed
 	LeaveGroup(ctx context.Context, userID string, groupID string) error            // Changed
 	RemoveUserFromGroup(ctx context.Context, userID string, groupID string) error   // Changed
+	LeaveAllGroups(ctx context.Context, userID string) (int, error)
 	GetSupportGroupStats(ctx context.Context) (*SupportGroupStats, error)
 
 	// Admin/Staff only methods
@@ -0,0 +1,412 @@
+package support_groups
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+	"github.com/perinatal-mental-health-app/backend/internal/perinatal"
+)
+
+// memoryStore keeps support groups and memberships in memory for tests and
+// demo mode. It does not publish domain events.
+type memoryStore struct {
+	db          *memdb.DB
+	mu          sync.RWMutex
+	groups      map[string]SupportGroup
+	memberships []membershipRow
+}
+
+// membershipRow is a GroupMembership keyed by the group's UUID
+type membershipRow struct {
+	GroupMembership
+	groupID string
+}
+
+func NewMemoryStore(db *memdb.DB) Store {
+	return &memoryStore{
+		db:     db,
+		groups: make(map[string]SupportGroup),
+	}
+}
+
+// ListSupportGroups retrieves a paginated list of active support groups, newest first
+func (s *memoryStore) ListSupportGroups(ctx context.Context, page, pageSize int, category, platform, stage string) (*ListSupportGroupsResponse, error) {
+	groups := s.filter(func(group SupportGroup) bool {
+		return (category == "" || group.Category == category) && (platform == "" || group.Platform == platform) &&
+			(stage == "" || perinatal.Matches(group.PerinatalStages, perinatal.Stage(stage)))
+	})
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].CreatedAt.After(groups[j].CreatedAt)
+	})
+
+	return pageGroups(groups, page, pageSize), nil
+}
+
+// GetSupportGroupByID retrieves an active support group by ID
+func (s *memoryStore) GetSupportGroupByID(ctx context.Context, groupID string) (*SupportGroup, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	group, ok := s.groups[groupID]
+	if !ok || !group.IsActive {
+		return nil, fmt.Errorf("support group not found")
+	}
+
+	return &group, nil
+}
+
+// SearchSupportGroups searches support groups by name, description and category
+func (s *memoryStore) SearchSupportGroups(ctx context.Context, query string, page, pageSize int) (*ListSupportGroupsResponse, error) {
+	query = strings.ToLower(query)
+	rank := func(group SupportGroup) int {
+		switch {
+		case strings.Contains(strings.ToLower(group.Name), query):
+			return 1
+		case strings.Contains(strings.ToLower(group.Category), query):
+			return 2
+		default:
+			return 3
+		}
+	}
+
+	groups := s.filter(func(group SupportGroup) bool {
+		return strings.Contains(strings.ToLower(group.Name), query) ||
+			strings.Contains(strings.ToLower(group.Description), query) ||
+			strings.Contains(strings.ToLower(group.Category), query)
+	})
+	sort.Slice(groups, func(i, j int) bool {
+		if ri, rj := rank(groups[i]), rank(groups[j]); ri != rj {
+			return ri < rj
+		}
+		return groups[i].CreatedAt.After(groups[j].CreatedAt)
+	})
+
+	return pageGroups(groups, page, pageSize), nil
+}
+
+// GetSupportGroupsByCategory retrieves support groups by category
+func (s *memoryStore) GetSupportGroupsByCategory(ctx context.Context, category string, page, pageSize int) (*ListSupportGroupsResponse, error) {
+	return s.ListSupportGroups(ctx, page, pageSize, category, "", "")
+}
+
+// GetSupportGroupsByPlatform retrieves support groups by platform
+func (s *memoryStore) GetSupportGroupsByPlatform(ctx context.Context, platform string, page, pageSize int) (*ListSupportGroupsResponse, error) {
+	return s.ListSupportGroups(ctx, page, pageSize, "", platform, "")
+}
+
+// GetUserGroups retrieves all active groups a user is a member of, most recently joined first
+func (s *memoryStore) GetUserGroups(ctx context.Context, userID string) ([]SupportGroup, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var rows []membershipRow
+	for _, row := range s.memberships {
+		if row.UserID == userID && row.IsActive && s.groups[row.groupID].IsActive {
+			rows = append(rows, row)
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].JoinedAt.After(rows[j].JoinedAt)
+	})
+
+	var groups []SupportGroup
+	for _, row := range rows {
+		groups = append(groups, s.groups[row.groupID])
+	}
+	return groups, nil
+}
+
+// GetGroupsForUsers retrieves the active groups of each user, most recently joined first
+func (s *memoryStore) GetGroupsForUsers(ctx context.Context, userIDs []string) (map[string][]SupportGroup, error) {
+	groups := make(map[string][]SupportGroup, len(userIDs))
+	for _, userID := range userIDs {
+		userGroups, err := s.GetUserGroups(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if len(userGroups) > 0 {
+			groups[userID] = userGroups
+		}
+	}
+
+	return groups, nil
+}
+
+// GetGroupMembers retrieves the active members of a support group, earliest first
+func (s *memoryStore) GetGroupMembers(ctx context.Context, groupID string) ([]GroupMembership, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var members []GroupMembership
+	for _, row := range s.memberships {
+		if row.groupID == groupID && row.IsActive {
+			members = append(members, row.GroupMembership)
+		}
+	}
+	return members, nil
+}
+
+// IsUserMember checks if a user is a member of a support group
+func (s *memoryStore) IsUserMember(ctx context.Context, userID string, groupID string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, row := range s.memberships {
+		if row.UserID == userID && row.groupID == groupID && row.IsActive {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// JoinGroup adds a user to a support group
+func (s *memoryStore) JoinGroup(ctx context.Context, userID string, groupID string) error {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.memberships = append(s.memberships, membershipRow{
+		GroupMembership: GroupMembership{
+			ID:        uuid.New().String(),
+			UserID:    userID,
+			JoinedAt:  now,
+			IsActive:  true,
+			Role:      "member",
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		groupID: groupID,
+	})
+	return nil
+}
+
+// LeaveGroup removes a user from a support group (soft delete)
+func (s *memoryStore) LeaveGroup(ctx context.Context, userID string, groupID string) error {
+	ended := s.endMemberships(func(row membershipRow) bool {
+		return row.UserID == userID && row.groupID == groupID
+	})
+	if len(ended) == 0 {
+		return fmt.Errorf("membership not found")
+	}
+	return nil
+}
+
+// LeaveAllGroups ends every active membership the user has, returning how many were ended
+func (s *memoryStore) LeaveAllGroups(ctx context.Context, userID string) (int, error) {
+	ended := s.endMemberships(func(row membershipRow) bool {
+		return row.UserID == userID && row.IsActive
+	})
+	return len(ended), nil
+}
+
+// RemoveUserFromGroup removes a user from a group (admin action)
+func (s *memoryStore) RemoveUserFromGroup(ctx context.Context, userID string, groupID string) error {
+	return s.LeaveGroup(ctx, userID, groupID)
+}
+
+// GetSupportGroupStats retrieves support group statistics
+func (s *memoryStore) GetSupportGroupStats(ctx context.Context) (*SupportGroupStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats := &SupportGroupStats{
+		TotalGroups:      int64(len(s.groups)),
+		GroupsByCategory: make(map[string]int64),
+		GroupsByPlatform: make(map[string]int64),
+	}
+
+	memberCounts := make(map[string]int)
+	for _, row := range s.memberships {
+		if row.IsActive {
+			stats.TotalMembers++
+			memberCounts[row.groupID]++
+		}
+	}
+
+	var active []SupportGroup
+	for _, group := range s.groups {
+		if !group.IsActive {
+			continue
+		}
+		stats.ActiveGroups++
+		stats.GroupsByCategory[group.Category]++
+		stats.GroupsByPlatform[group.Platform]++
+		active = append(active, group)
+	}
+
+	sort.Slice(active, func(i, j int) bool {
+		if ci, cj := memberCounts[active[i].ID], memberCounts[active[j].ID]; ci != cj {
+			return ci > cj
+		}
+		return active[i].CreatedAt.After(active[j].CreatedAt)
+	})
+	if len(active) > 5 {
+		active = active[:5]
+	}
+	stats.PopularGroups = active
+
+	return stats, nil
+}
+
+// CreateSupportGroup creates a new support group (admin only)
+func (s *memoryStore) CreateSupportGroup(ctx context.Context, req *CreateSupportGroupRequest) (*SupportGroup, error) {
+	now := time.Now()
+	group := SupportGroup{
+		ID:              uuid.New().String(),
+		Name:            req.Name,
+		Description:     req.Description,
+		Category:        req.Category,
+		Platform:        req.Platform,
+		DoctorInfo:      req.DoctorInfo,
+		URL:             req.URL,
+		Guidelines:      req.Guidelines,
+		MeetingTime:     req.MeetingTime,
+		MaxMembers:      req.MaxMembers,
+		OrganisationID:  req.OrganisationID,
+		PerinatalStages: append([]string{}, req.PerinatalStages...),
+		IsActive:        true,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	s.mu.Lock()
+	s.groups[group.ID] = group
+	s.mu.Unlock()
+	s.publishItem(group)
+
+	return &group, nil
+}
+
+// UpdateSupportGroup updates a support group (admin only)
+func (s *memoryStore) UpdateSupportGroup(ctx context.Context, groupID string, req *UpdateSupportGroupRequest) (*SupportGroup, error) {
+	return s.update(groupID, true, func(group *SupportGroup) {
+		if req.Name != nil {
+			group.Name = *req.Name
+		}
+		if req.Description != nil {
+			group.Description = *req.Description
+		}
+		if req.Category != nil {
+			group.Category = *req.Category
+		}
+		if req.Platform != nil {
+			group.Platform = *req.Platform
+		}
+		if req.DoctorInfo != nil {
+			group.DoctorInfo = req.DoctorInfo
+		}
+		if req.URL != nil {
+			group.URL = req.URL
+		}
+		if req.Guidelines != nil {
+			group.Guidelines = req.Guidelines
+		}
+		if req.MeetingTime != nil {
+			group.MeetingTime = req.MeetingTime
+		}
+		if req.MaxMembers != nil {
+			group.MaxMembers = req.MaxMembers
+		}
+		if req.OrganisationID != nil {
+			group.OrganisationID = req.OrganisationID
+		}
+		if req.PerinatalStages != nil {
+			group.PerinatalStages = append([]string{}, req.PerinatalStages...)
+		}
+	})
+}
+
+// DeleteSupportGroup soft deletes a support group (admin only)
+func (s *memoryStore) DeleteSupportGroup(ctx context.Context, groupID string) error {
+	_, err := s.update(groupID, false, func(group *SupportGroup) {
+		group.IsActive = false
+	})
+	return err
+}
+
+// Helper functions
+
+// filter returns the active groups that match
+func (s *memoryStore) filter(match func(SupportGroup) bool) []SupportGroup {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var groups []SupportGroup
+	for _, group := range s.groups {
+		if group.IsActive && match(group) {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// update applies fn to a group, optionally only when it is active
+func (s *memoryStore) update(groupID string, activeOnly bool, fn func(*SupportGroup)) (*SupportGroup, error) {
+	s.mu.Lock()
+	group, ok := s.groups[groupID]
+	if !ok || (activeOnly && !group.IsActive) {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("support group not found")
+	}
+	fn(&group)
+	group.UpdatedAt = time.Now()
+	s.groups[groupID] = group
+	s.mu.Unlock()
+
+	s.publishItem(group)
+	return &group, nil
+}
+
+// endMemberships marks the matching memberships inactive and returns their group IDs
+func (s *memoryStore) endMemberships(match func(membershipRow) bool) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var groupIDs []string
+	for i, row := range s.memberships {
+		if match(row) {
+			s.memberships[i].IsActive = false
+			s.memberships[i].UpdatedAt = time.Now()
+			groupIDs = append(groupIDs, row.groupID)
+		}
+	}
+	return groupIDs
+}
+
+func (s *memoryStore) publishItem(group SupportGroup) {
+	s.db.PutItem(memdb.Item{
+		Type:           memdb.ItemSupportGroup,
+		ID:             group.ID,
+		Title:          group.Name,
+		Description:    group.Description,
+		IsActive:       group.IsActive,
+		OrganisationID: group.OrganisationID,
+	})
+}
+
+func pageGroups(groups []SupportGroup, page, pageSize int) *ListSupportGroupsResponse {
+	total := int64(len(groups))
+	start := (page - 1) * pageSize
+	if start > len(groups) {
+		start = len(groups)
+	}
+	end := start + pageSize
+	if end > len(groups) {
+		end = len(groups)
+	}
+
+	return &ListSupportGroupsResponse{
+		SupportGroups: groups[start:end],
+		Total:         total,
+		Page:          page,
+		PageSize:      pageSize,
+		TotalPages:    int((total + int64(pageSize) - 1) / int64(pageSize)),
+	}
+}
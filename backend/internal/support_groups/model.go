@@ -7,19 +7,21 @@ import (
 
 // SupportGroup represents a support group
 type SupportGroup struct {
-	ID          string    `json:"id" db:"id"`
-	Name        string    `json:"name" db:"name"`
-	Description string    `json:"description" db:"description"`
-	Category    string    `json:"category" db:"category"`
-	Platform    string    `json:"platform" db:"platform"`
-	DoctorInfo  *string   `json:"doctor_info,omitempty" db:"doctor_info"`
-	URL         *string   `json:"url,omitempty" db:"url"`
-	Guidelines  *string   `json:"guidelines,omitempty" db:"guidelines"`
-	MeetingTime *string   `json:"meeting_time,omitempty" db:"meeting_time"`
-	MaxMembers  *int      `json:"max_members,omitempty" db:"max_members"`
-	IsActive    bool      `json:"is_active" db:"is_active"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID              string    `json:"id" db:"id"`
+	Name            string    `json:"name" db:"name"`
+	Description     string    `json:"description" db:"description"`
+	Category        string    `json:"category" db:"category"`
+	Platform        string    `json:"platform" db:"platform"`
+	DoctorInfo      *string   `json:"doctor_info,omitempty" db:"doctor_info"`
+	URL             *string   `json:"url,omitempty" db:"url"`
+	Guidelines      *string   `json:"guidelines,omitempty" db:"guidelines"`
+	MeetingTime     *string   `json:"meeting_time,omitempty" db:"meeting_time"`
+	MaxMembers      *int      `json:"max_members,omitempty" db:"max_members"`
+	OrganisationID  *string   `json:"organisation_id,omitempty" db:"organisation_id"`
+	PerinatalStages []string  `json:"perinatal_stages" db:"perinatal_stages"` // Empty suits every stage
+	IsActive        bool      `json:"is_active" db:"is_active"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
 }
 
 // GroupCategory represents valid group categories
@@ -45,28 +47,32 @@ const (
 
 // CreateSupportGroupRequest represents the request to create a support group
 type CreateSupportGroupRequest struct {
-	Name        string  `json:"name" validate:"required,min=2,max=255"`
-	Description string  `json:"description" validate:"required"`
-	Category    string  `json:"category" validate:"required,oneof=postnatal prenatal anxiety depression partner_support general"`
-	Platform    string  `json:"platform" validate:"required,oneof=online in_person hybrid"`
-	DoctorInfo  *string `json:"doctor_info,omitempty"`
-	URL         *string `json:"url,omitempty" validate:"omitempty,url"`
-	Guidelines  *string `json:"guidelines,omitempty"`
-	MeetingTime *string `json:"meeting_time,omitempty"`
-	MaxMembers  *int    `json:"max_members,omitempty" validate:"omitempty,min=2,max=100"`
+	Name            string   `json:"name" validate:"required,min=2,max=255"`
+	Description     string   `json:"description" validate:"required"`
+	Category        string   `json:"category" validate:"required,oneof=postnatal prenatal anxiety depression partner_support general"`
+	Platform        string   `json:"platform" validate:"required,oneof=online in_person hybrid"`
+	DoctorInfo      *string  `json:"doctor_info,omitempty"`
+	URL             *string  `json:"url,omitempty" validate:"omitempty,url"`
+	Guidelines      *string  `json:"guidelines,omitempty"`
+	MeetingTime     *string  `json:"meeting_time,omitempty"`
+	MaxMembers      *int     `json:"max_members,omitempty" validate:"omitempty,min=2,max=100"`
+	OrganisationID  *string  `json:"organisation_id,omitempty"`
+	PerinatalStages []string `json:"perinatal_stages,omitempty" validate:"omitempty,dive,oneof=first_trimester second_trimester third_trimester postnatal after_loss"`
 }
 
 // UpdateSupportGroupRequest represents the request to update a support group
 type UpdateSupportGroupRequest struct {
-	Name        *string `json:"name,omitempty" validate:"omitempty,min=2,max=255"`
-	Description *string `json:"description,omitempty"`
-	Category    *string `json:"category,omitempty" validate:"omitempty,oneof=postnatal prenatal anxiety depression partner_support general"`
-	Platform    *string `json:"platform,omitempty" validate:"omitempty,oneof=online in_person hybrid"`
-	DoctorInfo  *string `json:"doctor_info,omitempty"`
-	URL         *string `json:"url,omitempty" validate:"omitempty,url"`
-	Guidelines  *string `json:"guidelines,omitempty"`
-	MeetingTime *string `json:"meeting_time,omitempty"`
-	MaxMembers  *int    `json:"max_members,omitempty" validate:"omitempty,min=2,max=100"`
+	Name            *string  `json:"name,omitempty" validate:"omitempty,min=2,max=255"`
+	Description     *string  `json:"description,omitempty"`
+	Category        *string  `json:"category,omitempty" validate:"omitempty,oneof=postnatal prenatal anxiety depression partner_support general"`
+	Platform        *string  `json:"platform,omitempty" validate:"omitempty,oneof=online in_person hybrid"`
+	DoctorInfo      *string  `json:"doctor_info,omitempty"`
+	URL             *string  `json:"url,omitempty" validate:"omitempty,url"`
+	Guidelines      *string  `json:"guidelines,omitempty"`
+	MeetingTime     *string  `json:"meeting_time,omitempty"`
+	MaxMembers      *int     `json:"max_members,omitempty" validate:"omitempty,min=2,max=100"`
+	OrganisationID  *string  `json:"organisation_id,omitempty"`
+	PerinatalStages []string `json:"perinatal_stages,omitempty" validate:"omitempty,dive,oneof=first_trimester second_trimester third_trimester postnatal after_loss"`
 }
 
 // ListSupportGroupsResponse represents the response for listing support groups
@@ -5,6 +5,11 @@ import (
 	"context"
 	"fmt"
 	"github.com/google/uuid"
+	"github.com/perinatal-mental-health-app/backend/internal/events"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/perinatal"
+	"go.uber.org/zap"
 	"strings"
 )
 
@@ -19,7 +24,7 @@ func NewService(store Store) Service {
 }
 
 // ListSupportGroups retrieves a paginated list of support groups
-func (s *service) ListSupportGroups(ctx context.Context, page, pageSize int, category, platform string) (*ListSupportGroupsResponse, error) {
+func (s *service) ListSupportGroups(ctx context.Context, page, pageSize int, category, platform, stage string) (*ListSupportGroupsResponse, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -37,7 +42,14 @@ func (s *service) ListSupportGroups(ctx context.Context, page, pageSize int, cat
 		return nil, fmt.Errorf("invalid platform: %s", platform)
 	}
 
-	return s.store.ListSupportGroups(ctx, page, pageSize, category, platform)
+	// Validate stage if provided
+	if stage != "" {
+		if _, err := perinatal.ParseStage(stage); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.store.ListSupportGroups(ctx, page, pageSize, category, platform, stage)
 }
 
 // GetSupportGroup retrieves a support group by ID
@@ -112,6 +124,15 @@ func (s *service) GetUserGroups(ctx context.Context, userID string) ([]SupportGr
 	return s.store.GetUserGroups(ctx, userID)
 }
 
+// GetGroupsForUsers retrieves the groups of several users at once
+func (s *service) GetGroupsForUsers(ctx context.Context, userIDs []string) (map[string][]SupportGroup, error) {
+	if len(userIDs) == 0 {
+		return map[string][]SupportGroup{}, nil
+	}
+
+	return s.store.GetGroupsForUsers(ctx, userIDs)
+}
+
 // JoinGroup adds a user to a support group
 func (s *service) JoinGroup(ctx context.Context, userID string, groupID string) error {
 	if userID == "" {
@@ -218,31 +239,68 @@ func (s *service) GetSupportGroupStats(ctx context.Context) (*SupportGroupStats,
 }
 
 // CreateSupportGroup creates a new support group (admin only)
-func (s *service) CreateSupportGroup(ctx context.Context, req *CreateSupportGroupRequest) (*SupportGroup, error) {
+func (s *service) CreateSupportGroup(ctx context.Context, scope organisations.Scope, req *CreateSupportGroupRequest) (*SupportGroup, error) {
+	// The group is owned by one of the caller's organisations
+	if req.OrganisationID != nil {
+		if !scope.Allows(*req.OrganisationID) {
+			return nil, organisations.ErrOutOfScope
+		}
+	} else if !scope.All {
+		if len(scope.OrganisationIDs) != 1 {
+			return nil, fmt.Errorf("organisation_id is required")
+		}
+		req.OrganisationID = &scope.OrganisationIDs[0]
+	}
+
+	if err := s.ValidateSupportGroup(scope, req); err != nil {
+		return nil, err
+	}
+
+	return s.store.CreateSupportGroup(ctx, req)
+}
+
+// ValidateSupportGroup checks a new support group without saving it
+func (s *service) ValidateSupportGroup(scope organisations.Scope, req *CreateSupportGroupRequest) error {
+	if req.OrganisationID != nil && !scope.Allows(*req.OrganisationID) {
+		return organisations.ErrOutOfScope
+	}
+	if req.OrganisationID == nil && !scope.All && len(scope.OrganisationIDs) != 1 {
+		return fmt.Errorf("organisation_id is required")
+	}
+
 	// Validate category
 	if !isValidCategory(req.Category) {
-		return nil, fmt.Errorf("invalid category: %s", req.Category)
+		return fmt.Errorf("invalid category: %s", req.Category)
 	}
 
 	// Validate platform
 	if !isValidPlatform(req.Platform) {
-		return nil, fmt.Errorf("invalid platform: %s", req.Platform)
+		return fmt.Errorf("invalid platform: %s", req.Platform)
 	}
 
-	// Additional validation
-	if err := validateSupportGroupRequest(req); err != nil {
-		return nil, err
+	if err := perinatal.ValidateStages(req.PerinatalStages); err != nil {
+		return err
 	}
 
-	return s.store.CreateSupportGroup(ctx, req)
+	// Additional validation
+	return validateSupportGroupRequest(req)
 }
 
 // UpdateSupportGroup updates a support group (admin only)
-func (s *service) UpdateSupportGroup(ctx context.Context, groupID string, req *UpdateSupportGroupRequest) (*SupportGroup, error) {
+func (s *service) UpdateSupportGroup(ctx context.Context, scope organisations.Scope, groupID string, req *UpdateSupportGroupRequest) (*SupportGroup, error) {
 	if !isValidUUID(groupID) {
 		return nil, fmt.Errorf("invalid group ID")
 	}
 
+	if err := s.checkOwnership(ctx, scope, groupID); err != nil {
+		return nil, err
+	}
+
+	// Ownership can only move to another organisation within the caller's scope
+	if req.OrganisationID != nil && !scope.Allows(*req.OrganisationID) {
+		return nil, organisations.ErrOutOfScope
+	}
+
 	// Validate category if provided
 	if req.Category != nil && !isValidCategory(*req.Category) {
 		return nil, fmt.Errorf("invalid category: %s", *req.Category)
@@ -253,20 +311,28 @@ func (s *service) UpdateSupportGroup(ctx context.Context, groupID string, req *U
 		return nil, fmt.Errorf("invalid platform: %s", *req.Platform)
 	}
 
+	if err := perinatal.ValidateStages(req.PerinatalStages); err != nil {
+		return nil, err
+	}
+
 	return s.store.UpdateSupportGroup(ctx, groupID, req)
 }
 
 // DeleteSupportGroup soft deletes a support group (admin only)
-func (s *service) DeleteSupportGroup(ctx context.Context, groupID string) error {
+func (s *service) DeleteSupportGroup(ctx context.Context, scope organisations.Scope, groupID string) error {
 	if !isValidUUID(groupID) {
 		return fmt.Errorf("invalid group ID")
 	}
 
+	if err := s.checkOwnership(ctx, scope, groupID); err != nil {
+		return err
+	}
+
 	return s.store.DeleteSupportGroup(ctx, groupID)
 }
 
 // RemoveUserFromGroup removes a user from a group (admin only)
-func (s *service) RemoveUserFromGroup(ctx context.Context, userID string, groupID string) error {
+func (s *service) RemoveUserFromGroup(ctx context.Context, scope organisations.Scope, userID string, groupID string) error {
 	if userID == "" {
 		return fmt.Errorf("invalid user ID")
 	}
@@ -274,6 +340,10 @@ func (s *service) RemoveUserFromGroup(ctx context.Context, userID string, groupI
 		return fmt.Errorf("invalid group ID")
 	}
 
+	if err := s.checkOwnership(ctx, scope, groupID); err != nil {
+		return err
+	}
+
 	// Check if user is a member
 	isMember, err := s.store.IsUserMember(ctx, userID, groupID)
 	if err != nil {
@@ -287,6 +357,20 @@ func (s *service) RemoveUserFromGroup(ctx context.Context, userID string, groupI
 	return s.store.RemoveUserFromGroup(ctx, userID, groupID)
 }
 
+// OnUserDeactivated ends a deactivated user's group memberships
+func (s *service) OnUserDeactivated(ctx context.Context, event events.UserDeactivatedPayload) error {
+	left, err := s.store.LeaveAllGroups(ctx, event.UserID)
+	if err != nil {
+		return err
+	}
+
+	if left > 0 {
+		logger.Info("Ended group memberships for deactivated user", zap.String("user_id", event.UserID), zap.Int("groups", left))
+	}
+
+	return nil
+}
+
 // Helper functions
 
 // isValidCategory validates category
@@ -352,3 +436,21 @@ func isValidUUID(u string) bool {
 	_, err := uuid.Parse(u)
 	return err == nil
 }
+
+// checkOwnership ensures the group belongs to one of the caller's organisations
+func (s *service) checkOwnership(ctx context.Context, scope organisations.Scope, groupID string) error {
+	if scope.All {
+		return nil
+	}
+
+	group, err := s.store.GetSupportGroupByID(ctx, groupID)
+	if err != nil {
+		return err
+	}
+
+	if group.OrganisationID == nil || !scope.Allows(*group.OrganisationID) {
+		return organisations.ErrOutOfScope
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package support_groups
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+)
+
+var allScope = organisations.Scope{All: true}
+
+func TestJoinGroupCapacity(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+
+	tests := []struct {
+		name       string
+		maxMembers *int
+		existing   int
+		leaving    int
+		wantErr    string
+	}{
+		{"no limit", nil, 50, 0, ""},
+		{"room left", intPtr(3), 2, 0, ""},
+		{"full", intPtr(3), 3, 0, "maximum capacity"},
+		{"place freed by a leaver", intPtr(3), 3, 1, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			svc := NewService(NewMemoryStore(memdb.New()))
+
+			group, err := svc.CreateSupportGroup(ctx, allScope, &CreateSupportGroupRequest{
+				Name:        "Evening Circle",
+				Description: "Peer support",
+				Category:    "postnatal",
+				Platform:    "in_person",
+				MaxMembers:  tt.maxMembers,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 0; i < tt.existing; i++ {
+				if err := svc.JoinGroup(ctx, fmt.Sprintf("member-%d", i), group.ID); err != nil {
+					t.Fatalf("seeding member %d: %v", i, err)
+				}
+			}
+			for i := 0; i < tt.leaving; i++ {
+				if err := svc.LeaveGroup(ctx, fmt.Sprintf("member-%d", i), group.ID); err != nil {
+					t.Fatalf("member %d leaving: %v", i, err)
+				}
+			}
+
+			err = svc.JoinGroup(ctx, "newcomer", group.ID)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("JoinGroup() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("JoinGroup() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJoinGroupRules(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(NewMemoryStore(memdb.New()))
+
+	group, err := svc.CreateSupportGroup(ctx, allScope, &CreateSupportGroupRequest{
+		Name: "Morning Circle", Description: "Peer support", Category: "prenatal", Platform: "in_person",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.JoinGroup(ctx, "member", group.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		userID  string
+		groupID string
+		wantErr string
+	}{
+		{"already a member", "member", group.ID, "already a member"},
+		{"missing user", "", group.ID, "invalid user ID"},
+		{"malformed group ID", "member", "not-a-uuid", "invalid group ID"},
+		{"unknown group", "member", "6f1c2a44-0000-4000-8000-000000000000", "group not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.JoinGroup(ctx, tt.userID, tt.groupID)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("JoinGroup() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestListSupportGroupsByStage(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(NewMemoryStore(memdb.New()))
+
+	for _, req := range []CreateSupportGroupRequest{
+		{Name: "Bump Club", Description: "Peer support", Category: "prenatal", Platform: "in_person", PerinatalStages: []string{"second_trimester", "third_trimester"}},
+		{Name: "New Parents", Description: "Peer support", Category: "postnatal", Platform: "in_person", PerinatalStages: []string{"postnatal"}},
+		{Name: "Open Circle", Description: "Peer support", Category: "general", Platform: "in_person"},
+	} {
+		if _, err := svc.CreateSupportGroup(ctx, allScope, &req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	groups, err := svc.ListSupportGroups(ctx, 1, 20, "", "", "postnatal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, group := range groups.SupportGroups {
+		names = append(names, group.Name)
+	}
+	if len(names) != 2 || strings.Contains(strings.Join(names, ","), "Bump Club") {
+		t.Errorf("ListSupportGroups(postnatal) = %v, want the postnatal and untagged groups", names)
+	}
+
+	if _, err := svc.ListSupportGroups(ctx, 1, 20, "", "", "toddler"); err == nil {
+		t.Error("ListSupportGroups() accepted an unknown stage")
+	}
+	if _, err := svc.CreateSupportGroup(ctx, allScope, &CreateSupportGroupRequest{Name: "Later", Description: "Peer support", Category: "general", Platform: "in_person", PerinatalStages: []string{"toddler"}}); err == nil {
+		t.Error("CreateSupportGroup() accepted an unknown stage")
+	}
+}
@@ -8,21 +8,22 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/perinatal-mental-health-app/backend/internal/db"
+	"github.com/perinatal-mental-health-app/backend/internal/events"
 )
 
 type store struct {
-	db *pgxpool.Pool
+	db *db.Handle
 }
 
-func NewStore(db *pgxpool.Pool) Store {
+func NewStore(db *db.Handle) Store {
 	return &store{
 		db: db,
 	}
 }
 
 // ListSupportGroups retrieves a paginated list of support groups with filtering
-func (s *store) ListSupportGroups(ctx context.Context, page, pageSize int, category, platform string) (*ListSupportGroupsResponse, error) {
+func (s *store) ListSupportGroups(ctx context.Context, page, pageSize int, category, platform, stage string) (*ListSupportGroupsResponse, error) {
 	offset := (page - 1) * pageSize
 
 	var whereClause []string
@@ -46,6 +47,13 @@ func (s *store) ListSupportGroups(ctx context.Context, page, pageSize int, categ
 		argIndex++
 	}
 
+	// Add perinatal stage filter; untagged groups suit every stage
+	if stage != "" {
+		whereClause = append(whereClause, fmt.Sprintf("(cardinality(perinatal_stages) = 0 OR $%d = ANY(perinatal_stages))", argIndex))
+		args = append(args, stage)
+		argIndex++
+	}
+
 	// Build WHERE clause
 	whereSQL := ""
 	if len(whereClause) > 0 {
@@ -60,7 +68,7 @@ func (s *store) ListSupportGroups(ctx context.Context, page, pageSize int, categ
 	`, whereSQL)
 
 	var total int64
-	err := s.db.QueryRow(ctx, countQuery, args...).Scan(&total)
+	err := s.db.Reader(ctx).QueryRow(ctx, countQuery, args...).Scan(&total)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count support groups: %w", err)
 	}
@@ -68,7 +76,7 @@ func (s *store) ListSupportGroups(ctx context.Context, page, pageSize int, categ
 	// Get support groups with pagination
 	query := fmt.Sprintf(`
 		SELECT id, name, description, category, platform, doctor_info, url, guidelines,
-			   meeting_time, max_members, is_active, created_at, updated_at
+			   meeting_time, max_members, organisation_id, is_active, created_at, updated_at, perinatal_stages
 		FROM support_groups
 		%s
 		ORDER BY created_at DESC
@@ -77,7 +85,7 @@ func (s *store) ListSupportGroups(ctx context.Context, page, pageSize int, categ
 
 	args = append(args, pageSize, offset)
 
-	rows, err := s.db.Query(ctx, query, args...)
+	rows, err := s.db.Reader(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query support groups: %w", err)
 	}
@@ -98,9 +106,11 @@ func (s *store) ListSupportGroups(ctx context.Context, page, pageSize int, categ
 			&group.Guidelines,
 			&group.MeetingTime,
 			&group.MaxMembers,
+			&group.OrganisationID,
 			&group.IsActive,
 			&group.CreatedAt,
 			&group.UpdatedAt,
+			&group.PerinatalStages,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan support group: %w", err)
@@ -128,14 +138,14 @@ func (s *store) ListSupportGroups(ctx context.Context, page, pageSize int, categ
 func (s *store) GetSupportGroupByID(ctx context.Context, groupID string) (*SupportGroup, error) {
 	query := `
 		SELECT id, name, description, category, platform, doctor_info, url, guidelines,
-			   meeting_time, max_members, is_active, created_at, updated_at
+			   meeting_time, max_members, organisation_id, is_active, created_at, updated_at, perinatal_stages
 		FROM support_groups
 		WHERE id = $1 AND is_active = true
 	`
 
 	var group SupportGroup
 
-	err := s.db.QueryRow(ctx, query, groupID).Scan(
+	err := s.db.Reader(ctx).QueryRow(ctx, query, groupID).Scan(
 		&group.ID,
 		&group.Name,
 		&group.Description,
@@ -146,9 +156,11 @@ func (s *store) GetSupportGroupByID(ctx context.Context, groupID string) (*Suppo
 		&group.Guidelines,
 		&group.MeetingTime,
 		&group.MaxMembers,
+		&group.OrganisationID,
 		&group.IsActive,
 		&group.CreatedAt,
 		&group.UpdatedAt,
+		&group.PerinatalStages,
 	)
 
 	if err != nil {
@@ -178,7 +190,7 @@ func (s *store) SearchSupportGroups(ctx context.Context, query string, page, pag
 	`
 
 	var total int64
-	err := s.db.QueryRow(ctx, countSQL, searchQuery).Scan(&total)
+	err := s.db.Reader(ctx).QueryRow(ctx, countSQL, searchQuery).Scan(&total)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count search results: %w", err)
 	}
@@ -186,7 +198,7 @@ func (s *store) SearchSupportGroups(ctx context.Context, query string, page, pag
 	// Get matching support groups
 	searchSQL := `
 		SELECT id, name, description, category, platform, doctor_info, url, guidelines,
-			   meeting_time, max_members, is_active, created_at, updated_at
+			   meeting_time, max_members, organisation_id, is_active, created_at, updated_at, perinatal_stages
 		FROM support_groups
 		WHERE is_active = true 
 		AND (LOWER(name) LIKE $1 
@@ -202,7 +214,7 @@ func (s *store) SearchSupportGroups(ctx context.Context, query string, page, pag
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := s.db.Query(ctx, searchSQL, searchQuery, pageSize, offset)
+	rows, err := s.db.Reader(ctx).Query(ctx, searchSQL, searchQuery, pageSize, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search support groups: %w", err)
 	}
@@ -223,9 +235,11 @@ func (s *store) SearchSupportGroups(ctx context.Context, query string, page, pag
 			&group.Guidelines,
 			&group.MeetingTime,
 			&group.MaxMembers,
+			&group.OrganisationID,
 			&group.IsActive,
 			&group.CreatedAt,
 			&group.UpdatedAt,
+			&group.PerinatalStages,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan support group: %w", err)
@@ -251,20 +265,20 @@ func (s *store) SearchSupportGroups(ctx context.Context, query string, page, pag
 
 // GetSupportGroupsByCategory retrieves support groups by category
 func (s *store) GetSupportGroupsByCategory(ctx context.Context, category string, page, pageSize int) (*ListSupportGroupsResponse, error) {
-	return s.ListSupportGroups(ctx, page, pageSize, category, "")
+	return s.ListSupportGroups(ctx, page, pageSize, category, "", "")
 }
 
 // GetSupportGroupsByPlatform retrieves support groups by platform
 func (s *store) GetSupportGroupsByPlatform(ctx context.Context, platform string, page, pageSize int) (*ListSupportGroupsResponse, error) {
-	return s.ListSupportGroups(ctx, page, pageSize, "", platform)
+	return s.ListSupportGroups(ctx, page, pageSize, "", platform, "")
 }
 
 // GetUserGroups retrieves all groups a user is a member of
 func (s *store) GetUserGroups(ctx context.Context, userID string) ([]SupportGroup, error) {
 	query := `
 		SELECT sg.id, sg.name, sg.description, sg.category, sg.platform, sg.doctor_info, 
-			   sg.url, sg.guidelines, sg.meeting_time, sg.max_members, sg.is_active, 
-			   sg.created_at, sg.updated_at
+			   sg.url, sg.guidelines, sg.meeting_time, sg.max_members, sg.organisation_id, sg.is_active, 
+			   sg.created_at, sg.updated_at, sg.perinatal_stages
 		FROM support_groups sg
 		INNER JOIN group_memberships gm ON sg.id = gm.group_id
 		WHERE gm.user_id = $1 AND gm.is_active = true AND sg.is_active = true
@@ -292,9 +306,11 @@ func (s *store) GetUserGroups(ctx context.Context, userID string) ([]SupportGrou
 			&group.Guidelines,
 			&group.MeetingTime,
 			&group.MaxMembers,
+			&group.OrganisationID,
 			&group.IsActive,
 			&group.CreatedAt,
 			&group.UpdatedAt,
+			&group.PerinatalStages,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan group: %w", err)
@@ -310,6 +326,62 @@ func (s *store) GetUserGroups(ctx context.Context, userID string) ([]SupportGrou
 	return groups, nil
 }
 
+// GetGroupsForUsers retrieves the active groups of each user in one query, most
+// recently joined first
+func (s *store) GetGroupsForUsers(ctx context.Context, userIDs []string) (map[string][]SupportGroup, error) {
+	query := `
+		SELECT gm.user_id, sg.id, sg.name, sg.description, sg.category, sg.platform, sg.doctor_info,
+			   sg.url, sg.guidelines, sg.meeting_time, sg.max_members, sg.organisation_id, sg.is_active,
+			   sg.created_at, sg.updated_at, sg.perinatal_stages
+		FROM support_groups sg
+		INNER JOIN group_memberships gm ON sg.id = gm.group_id
+		WHERE gm.user_id = ANY($1::uuid[]) AND gm.is_active = true AND sg.is_active = true
+		ORDER BY gm.user_id, gm.joined_at DESC
+	`
+
+	rows, err := s.db.Query(ctx, query, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get groups for users: %w", err)
+	}
+	defer rows.Close()
+
+	groups := make(map[string][]SupportGroup)
+	for rows.Next() {
+		var userID string
+		var group SupportGroup
+
+		err := rows.Scan(
+			&userID,
+			&group.ID,
+			&group.Name,
+			&group.Description,
+			&group.Category,
+			&group.Platform,
+			&group.DoctorInfo,
+			&group.URL,
+			&group.Guidelines,
+			&group.MeetingTime,
+			&group.MaxMembers,
+			&group.OrganisationID,
+			&group.IsActive,
+			&group.CreatedAt,
+			&group.UpdatedAt,
+			&group.PerinatalStages,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan group: %w", err)
+		}
+
+		groups[userID] = append(groups[userID], group)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return groups, nil
+}
+
 // GetGroupMembers retrieves all members of a support group
 func (s *store) GetGroupMembers(ctx context.Context, groupID string) ([]GroupMembership, error) {
 	query := `
@@ -380,11 +452,29 @@ func (s *store) JoinGroup(ctx context.Context, userID string, groupID string) er
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 
-	_, err := s.db.Exec(ctx, query, userID, groupID, now, true, "member", now, now)
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, query, userID, groupID, now, true, "member", now, now)
 	if err != nil {
 		return fmt.Errorf("failed to join group: %w", err)
 	}
 
+	err = events.Publish(ctx, tx, events.GroupJoined, groupID, events.GroupMembershipPayload{
+		UserID:  userID,
+		GroupID: groupID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return nil
 }
 
@@ -396,7 +486,13 @@ func (s *store) LeaveGroup(ctx context.Context, userID string, groupID string) e
 		WHERE user_id = $2 AND group_id = $3
 	`
 
-	result, err := s.db.Exec(ctx, query, time.Now(), userID, groupID)
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, query, time.Now(), userID, groupID)
 	if err != nil {
 		return fmt.Errorf("failed to leave group: %w", err)
 	}
@@ -405,9 +501,73 @@ func (s *store) LeaveGroup(ctx context.Context, userID string, groupID string) e
 		return fmt.Errorf("membership not found")
 	}
 
+	err = events.Publish(ctx, tx, events.GroupLeft, groupID, events.GroupMembershipPayload{
+		UserID:  userID,
+		GroupID: groupID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return nil
 }
 
+// LeaveAllGroups ends every active membership the user has, returning how many were ended
+func (s *store) LeaveAllGroups(ctx context.Context, userID string) (int, error) {
+	query := `
+		UPDATE group_memberships 
+		SET is_active = false, updated_at = $1
+		WHERE user_id = $2 AND is_active = true
+		RETURNING group_id
+	`
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, query, time.Now(), userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to leave groups: %w", err)
+	}
+
+	var groupIDs []string
+	for rows.Next() {
+		var groupID string
+		if err := rows.Scan(&groupID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan membership: %w", err)
+		}
+		groupIDs = append(groupIDs, groupID)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	for _, groupID := range groupIDs {
+		err = events.Publish(ctx, tx, events.GroupLeft, groupID, events.GroupMembershipPayload{
+			UserID:  userID,
+			GroupID: groupID,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(groupIDs), nil
+}
+
 // RemoveUserFromGroup removes a user from a group (admin action)
 func (s *store) RemoveUserFromGroup(ctx context.Context, userID string, groupID string) error {
 	return s.LeaveGroup(ctx, userID, groupID) // Same implementation for now
@@ -419,26 +579,26 @@ func (s *store) GetSupportGroupStats(ctx context.Context) (*SupportGroupStats, e
 	var totalGroups, activeGroups, totalMembers int64
 
 	// Count total groups
-	err := s.db.QueryRow(ctx, "SELECT COUNT(*) FROM support_groups").Scan(&totalGroups)
+	err := s.db.Reader(ctx).QueryRow(ctx, "SELECT COUNT(*) FROM support_groups").Scan(&totalGroups)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count total groups: %w", err)
 	}
 
 	// Count active groups
-	err = s.db.QueryRow(ctx, "SELECT COUNT(*) FROM support_groups WHERE is_active = true").Scan(&activeGroups)
+	err = s.db.Reader(ctx).QueryRow(ctx, "SELECT COUNT(*) FROM support_groups WHERE is_active = true").Scan(&activeGroups)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count active groups: %w", err)
 	}
 
 	// Count total active members
-	err = s.db.QueryRow(ctx, "SELECT COUNT(*) FROM group_memberships WHERE is_active = true").Scan(&totalMembers)
+	err = s.db.Reader(ctx).QueryRow(ctx, "SELECT COUNT(*) FROM group_memberships WHERE is_active = true").Scan(&totalMembers)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count total members: %w", err)
 	}
 
 	// Get groups by category
 	groupsByCategory := make(map[string]int64)
-	rows, err := s.db.Query(ctx, `
+	rows, err := s.db.Reader(ctx).Query(ctx, `
 		SELECT category, COUNT(*) 
 		FROM support_groups 
 		WHERE is_active = true 
@@ -461,7 +621,7 @@ func (s *store) GetSupportGroupStats(ctx context.Context) (*SupportGroupStats, e
 
 	// Get groups by platform
 	groupsByPlatform := make(map[string]int64)
-	rows, err = s.db.Query(ctx, `
+	rows, err = s.db.Reader(ctx).Query(ctx, `
 		SELECT platform, COUNT(*) 
 		FROM support_groups 
 		WHERE is_active = true 
@@ -485,19 +645,19 @@ func (s *store) GetSupportGroupStats(ctx context.Context) (*SupportGroupStats, e
 	// Get popular groups (most members)
 	popularGroupsQuery := `
 		SELECT sg.id, sg.name, sg.description, sg.category, sg.platform, sg.doctor_info, 
-			   sg.url, sg.guidelines, sg.meeting_time, sg.max_members, sg.is_active, 
-			   sg.created_at, sg.updated_at
+			   sg.url, sg.guidelines, sg.meeting_time, sg.max_members, sg.organisation_id, sg.is_active, 
+			   sg.created_at, sg.updated_at, sg.perinatal_stages
 		FROM support_groups sg
 		LEFT JOIN group_memberships gm ON sg.id = gm.group_id AND gm.is_active = true
 		WHERE sg.is_active = true
 		GROUP BY sg.id, sg.name, sg.description, sg.category, sg.platform, sg.doctor_info, 
-				 sg.url, sg.guidelines, sg.meeting_time, sg.max_members, sg.is_active, 
-				 sg.created_at, sg.updated_at
+				 sg.url, sg.guidelines, sg.meeting_time, sg.max_members, sg.organisation_id, sg.is_active, 
+				 sg.created_at, sg.updated_at, sg.perinatal_stages
 		ORDER BY COUNT(gm.id) DESC
 		LIMIT 5
 	`
 
-	rows, err = s.db.Query(ctx, popularGroupsQuery)
+	rows, err = s.db.Reader(ctx).Query(ctx, popularGroupsQuery)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get popular groups: %w", err)
 	}
@@ -518,9 +678,11 @@ func (s *store) GetSupportGroupStats(ctx context.Context) (*SupportGroupStats, e
 			&group.Guidelines,
 			&group.MeetingTime,
 			&group.MaxMembers,
+			&group.OrganisationID,
 			&group.IsActive,
 			&group.CreatedAt,
 			&group.UpdatedAt,
+			&group.PerinatalStages,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan popular group: %w", err)
@@ -543,12 +705,17 @@ func (s *store) GetSupportGroupStats(ctx context.Context) (*SupportGroupStats, e
 func (s *store) CreateSupportGroup(ctx context.Context, req *CreateSupportGroupRequest) (*SupportGroup, error) {
 	now := time.Now()
 
+	stages := req.PerinatalStages
+	if stages == nil {
+		stages = []string{}
+	}
+
 	query := `
 		INSERT INTO support_groups (name, description, category, platform, doctor_info, url, 
-									guidelines, meeting_time, max_members, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+									guidelines, meeting_time, max_members, organisation_id, is_active, created_at, updated_at, perinatal_stages)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		RETURNING id, name, description, category, platform, doctor_info, url, guidelines,
-				  meeting_time, max_members, is_active, created_at, updated_at
+				  meeting_time, max_members, organisation_id, is_active, created_at, updated_at, perinatal_stages
 	`
 
 	var group SupportGroup
@@ -563,9 +730,11 @@ func (s *store) CreateSupportGroup(ctx context.Context, req *CreateSupportGroupR
 		req.Guidelines,
 		req.MeetingTime,
 		req.MaxMembers,
+		req.OrganisationID,
 		true, // is_active
 		now,  // created_at
 		now,  // updated_at
+		stages,
 	).Scan(
 		&group.ID,
 		&group.Name,
@@ -577,9 +746,11 @@ func (s *store) CreateSupportGroup(ctx context.Context, req *CreateSupportGroupR
 		&group.Guidelines,
 		&group.MeetingTime,
 		&group.MaxMembers,
+		&group.OrganisationID,
 		&group.IsActive,
 		&group.CreatedAt,
 		&group.UpdatedAt,
+		&group.PerinatalStages,
 	)
 
 	if err != nil {
@@ -649,6 +820,18 @@ func (s *store) UpdateSupportGroup(ctx context.Context, groupID string, req *Upd
 		argIndex++
 	}
 
+	if req.OrganisationID != nil {
+		setParts = append(setParts, fmt.Sprintf("organisation_id = $%d", argIndex))
+		args = append(args, *req.OrganisationID)
+		argIndex++
+	}
+
+	if req.PerinatalStages != nil {
+		setParts = append(setParts, fmt.Sprintf("perinatal_stages = $%d", argIndex))
+		args = append(args, req.PerinatalStages)
+		argIndex++
+	}
+
 	// If no fields to update, just return the existing group
 	if len(setParts) == 0 {
 		return s.GetSupportGroupByID(ctx, groupID)
@@ -664,7 +847,7 @@ func (s *store) UpdateSupportGroup(ctx context.Context, groupID string, req *Upd
 		SET %s
 		WHERE id = $%d AND is_active = true
 		RETURNING id, name, description, category, platform, doctor_info, url, guidelines,
-				  meeting_time, max_members, is_active, created_at, updated_at
+				  meeting_time, max_members, organisation_id, is_active, created_at, updated_at, perinatal_stages
 	`, strings.Join(setParts, ", "), argIndex)
 
 	args = append(args, groupID)
@@ -682,9 +865,11 @@ func (s *store) UpdateSupportGroup(ctx context.Context, groupID string, req *Upd
 		&group.Guidelines,
 		&group.MeetingTime,
 		&group.MaxMembers,
+		&group.OrganisationID,
 		&group.IsActive,
 		&group.CreatedAt,
 		&group.UpdatedAt,
+		&group.PerinatalStages,
 	)
 
 	if err != nil {
@@ -0,0 +1,161 @@
+package supporters
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	service Service
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// Invite creates an invitation code for a partner or family member
+func (h *handler) Invite(c echo.Context) error {
+	var req InviteRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	invitation, err := h.service.Invite(c.Request().Context(), getUserIDFromContext(c), &req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, invitation)
+}
+
+// ListSupporters lists the current user's supporters and invitations
+func (h *handler) ListSupporters(c echo.Context) error {
+	links, err := h.service.ListSupporters(c.Request().Context(), getUserIDFromContext(c))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, links)
+}
+
+// UpdateShares changes what the current user shares with a supporter
+func (h *handler) UpdateShares(c echo.Context) error {
+	var req UpdateSharesRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	link, err := h.service.UpdateShares(c.Request().Context(), getUserIDFromContext(c), c.Param("id"), &req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, link)
+}
+
+// GetCrisisPlan retrieves the current user's crisis plan
+func (h *handler) GetCrisisPlan(c echo.Context) error {
+	plan, err := h.service.GetCrisisPlan(c.Request().Context(), getUserIDFromContext(c))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, plan)
+}
+
+// UpdateCrisisPlan replaces the current user's crisis plan
+func (h *handler) UpdateCrisisPlan(c echo.Context) error {
+	var req UpdateCrisisPlanRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	plan, err := h.service.UpdateCrisisPlan(c.Request().Context(), getUserIDFromContext(c), &req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, plan)
+}
+
+// Accept links the current user as a supporter with an invitation code
+func (h *handler) Accept(c echo.Context) error {
+	var req AcceptRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	link, err := h.service.Accept(c.Request().Context(), getUserIDFromContext(c), &req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, link)
+}
+
+// ListSupporting lists the people the current user supports
+func (h *handler) ListSupporting(c echo.Context) error {
+	links, err := h.service.ListSupporting(c.Request().Context(), getUserIDFromContext(c))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, links)
+}
+
+// GetView returns what a person the current user supports shares with them
+func (h *handler) GetView(c echo.Context) error {
+	view, err := h.service.GetView(c.Request().Context(), getUserIDFromContext(c), c.Param("id"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, view)
+}
+
+// Revoke ends a supporter link from either side
+func (h *handler) Revoke(c echo.Context) error {
+	link, err := h.service.Revoke(c.Request().Context(), getUserIDFromContext(c), c.Param("id"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, link)
+}
+
+// Helper functions
+
+func getUserIDFromContext(c echo.Context) string {
+	if userID, ok := c.Get("user_id").(string); ok {
+		return userID
+	}
+	return ""
+}
+
+func errorResponse(c echo.Context, err error) error {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, ErrNotServiceUser):
+		status = http.StatusForbidden
+	case errors.Is(err, ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrAlreadyLinked), errors.Is(err, ErrStatusChanged):
+		status = http.StatusConflict
+	}
+
+	return c.JSON(status, map[string]string{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,61 @@
+package supporters
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Service defines the interface for supporter link business logic
+type Service interface {
+	// Service users
+	Invite(ctx context.Context, serviceUserID string, req *InviteRequest) (*InvitationResponse, error)
+	ListSupporters(ctx context.Context, serviceUserID string) (*ListLinksResponse, error)
+	UpdateShares(ctx context.Context, serviceUserID, linkID string, req *UpdateSharesRequest) (*Link, error)
+	GetCrisisPlan(ctx context.Context, userID string) (*CrisisPlan, error)
+	UpdateCrisisPlan(ctx context.Context, userID string, req *UpdateCrisisPlanRequest) (*CrisisPlan, error)
+
+	// Supporters
+	Accept(ctx context.Context, supporterID string, req *AcceptRequest) (*Link, error)
+	ListSupporting(ctx context.Context, supporterID string) (*ListLinksResponse, error)
+	GetView(ctx context.Context, supporterID, linkID string) (*SupporterView, error)
+
+	// Revoke ends a link the caller is a party to, or withdraws a pending invitation
+	Revoke(ctx context.Context, userID, linkID string) (*Link, error)
+}
+
+// Store defines the interface for supporter link data persistence
+type Store interface {
+	CreateLink(ctx context.Context, link *Link) (*Link, error)
+	GetLink(ctx context.Context, linkID string) (*Link, error)
+	// GetLinkByCode returns the link with the invitation code hash, or ErrNotFound
+	GetLinkByCode(ctx context.Context, codeHash string) (*Link, error)
+	ListLinks(ctx context.Context, filter *LinkFilter) ([]Link, error)
+	// UpdateLink saves the link if its status is still fromStatus, and fails
+	// with ErrStatusChanged otherwise. Activating a second link between the
+	// same people fails with ErrAlreadyLinked.
+	UpdateLink(ctx context.Context, link *Link, fromStatus string) (*Link, error)
+	// GetCrisisPlan returns the user's crisis plan, or nil if they have none
+	GetCrisisPlan(ctx context.Context, userID string) (*CrisisPlan, error)
+	SaveCrisisPlan(ctx context.Context, plan *CrisisPlan) (*CrisisPlan, error)
+	// GetUserRole returns the role of an active user, or ErrNotFound
+	GetUserRole(ctx context.Context, userID string) (string, error)
+}
+
+// Handler defines the interface for supporter link HTTP handlers
+type Handler interface {
+	// Service user methods
+	Invite(c echo.Context) error
+	ListSupporters(c echo.Context) error
+	UpdateShares(c echo.Context) error
+	GetCrisisPlan(c echo.Context) error
+	UpdateCrisisPlan(c echo.Context) error
+
+	// Supporter methods
+	Accept(c echo.Context) error
+	ListSupporting(c echo.Context) error
+	GetView(c echo.Context) error
+
+	// Revoke is shared by both parties
+	Revoke(c echo.Context) error
+}
@@ -0,0 +1,169 @@
+package supporters
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+)
+
+// memoryStore keeps supporter links and crisis plans in memory for tests and
+// demo mode. Names and roles are read from the shared users.
+type memoryStore struct {
+	db    *memdb.DB
+	mu    sync.RWMutex
+	links map[string]Link
+	plans map[string]CrisisPlan
+}
+
+func NewMemoryStore(db *memdb.DB) Store {
+	return &memoryStore{
+		db:    db,
+		links: make(map[string]Link),
+		plans: make(map[string]CrisisPlan),
+	}
+}
+
+// CreateLink creates a pending link
+func (s *memoryStore) CreateLink(ctx context.Context, link *Link) (*Link, error) {
+	s.mu.Lock()
+	stored := *link
+	stored.Shares = slices.Clone(link.Shares)
+	s.links[link.ID] = stored
+	s.mu.Unlock()
+
+	return s.GetLink(ctx, link.ID)
+}
+
+// GetLink retrieves a link with both parties' names
+func (s *memoryStore) GetLink(ctx context.Context, linkID string) (*Link, error) {
+	s.mu.RLock()
+	link, ok := s.links[linkID]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return s.present(link), nil
+}
+
+// GetLinkByCode retrieves the link with the invitation code hash
+func (s *memoryStore) GetLinkByCode(ctx context.Context, codeHash string) (*Link, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, link := range s.links {
+		if link.CodeHash == codeHash {
+			return s.present(link), nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// ListLinks retrieves links, newest first
+func (s *memoryStore) ListLinks(ctx context.Context, filter *LinkFilter) ([]Link, error) {
+	s.mu.RLock()
+	links := []Link{}
+	for _, link := range s.links {
+		if (filter.ServiceUserID == "" || link.ServiceUserID == filter.ServiceUserID) &&
+			(filter.SupporterID == "" || (link.SupporterID != nil && *link.SupporterID == filter.SupporterID)) &&
+			(len(filter.Statuses) == 0 || slices.Contains(filter.Statuses, link.Status)) {
+			links = append(links, *s.present(link))
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].CreatedAt.Equal(links[j].CreatedAt) {
+			return links[i].ID > links[j].ID
+		}
+		return links[i].CreatedAt.After(links[j].CreatedAt)
+	})
+
+	return links, nil
+}
+
+// UpdateLink saves the supporter, status, shares and revocation of a link
+func (s *memoryStore) UpdateLink(ctx context.Context, link *Link, fromStatus string) (*Link, error) {
+	s.mu.Lock()
+	existing, ok := s.links[link.ID]
+	if !ok || existing.Status != fromStatus {
+		s.mu.Unlock()
+		return nil, ErrStatusChanged
+	}
+
+	// At most one active link between the same two people, as in Postgres
+	if link.Status == StatusActive && link.SupporterID != nil {
+		for id, other := range s.links {
+			if id != link.ID && other.Status == StatusActive && other.ServiceUserID == link.ServiceUserID &&
+				other.SupporterID != nil && *other.SupporterID == *link.SupporterID {
+				s.mu.Unlock()
+				return nil, ErrAlreadyLinked
+			}
+		}
+	}
+
+	existing.SupporterID = link.SupporterID
+	existing.Status = link.Status
+	existing.Shares = slices.Clone(link.Shares)
+	existing.AcceptedAt = link.AcceptedAt
+	existing.RevokedAt = link.RevokedAt
+	existing.RevokedBy = link.RevokedBy
+	existing.UpdatedAt = time.Now()
+	s.links[link.ID] = existing
+	s.mu.Unlock()
+
+	return s.GetLink(ctx, link.ID)
+}
+
+// GetCrisisPlan retrieves a user's crisis plan
+func (s *memoryStore) GetCrisisPlan(ctx context.Context, userID string) (*CrisisPlan, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	plan, ok := s.plans[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &plan, nil
+}
+
+// SaveCrisisPlan creates or replaces a user's crisis plan
+func (s *memoryStore) SaveCrisisPlan(ctx context.Context, plan *CrisisPlan) (*CrisisPlan, error) {
+	s.mu.Lock()
+	s.plans[plan.UserID] = *plan
+	s.mu.Unlock()
+
+	return s.GetCrisisPlan(ctx, plan.UserID)
+}
+
+// GetUserRole returns the role of an active user
+func (s *memoryStore) GetUserRole(ctx context.Context, userID string) (string, error) {
+	user, ok := s.db.User(userID)
+	if !ok || !user.IsActive {
+		return "", ErrNotFound
+	}
+
+	return user.Role, nil
+}
+
+// Helper functions
+
+// present returns a copy of the link with both parties' names
+func (s *memoryStore) present(link Link) *Link {
+	link.Shares = slices.Clone(link.Shares)
+	if serviceUser, ok := s.db.User(link.ServiceUserID); ok {
+		link.ServiceUserName = serviceUser.FullName
+	}
+	if link.SupporterID != nil {
+		if supporter, ok := s.db.User(*link.SupporterID); ok {
+			name := supporter.FullName
+			link.SupporterName = &name
+		}
+	}
+	return &link
+}
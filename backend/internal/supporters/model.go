@@ -0,0 +1,157 @@
+// Package supporters links a service user to a partner or family member, who
+// gets a supporter view limited to what the service user chooses to share.
+package supporters
+
+import (
+	"errors"
+	"time"
+)
+
+// Who a supporter is to the service user
+const (
+	RelationshipPartner = "partner"
+	RelationshipFamily  = "family"
+)
+
+// Link statuses. A link stays pending until the supporter enters its
+// invitation code; either side can revoke it at any time.
+const (
+	StatusPending = "pending"
+	StatusActive  = "active"
+	StatusRevoked = "revoked"
+)
+
+// What a service user can share with a supporter
+const (
+	ShareMoodTrend     = "mood_trend"
+	ShareGroupSessions = "group_sessions"
+	ShareCrisisPlan    = "crisis_plan"
+)
+
+// Shares lists every share in the order they appear in responses
+var Shares = []string{ShareMoodTrend, ShareGroupSessions, ShareCrisisPlan}
+
+// InvitationTTL is how long an invitation code can be used for
+const InvitationTTL = 7 * 24 * time.Hour
+
+const roleServiceUser = "service_user"
+
+var (
+	// ErrNotFound is returned for links that don't exist or that the caller
+	// isn't a party to
+	ErrNotFound = errors.New("supporter link not found")
+
+	// ErrInvalidCode is returned for invitation codes that are unknown, expired or already used
+	ErrInvalidCode = errors.New("invalid or expired invitation code")
+
+	// ErrAlreadyLinked is returned when the supporter already supports the service user
+	ErrAlreadyLinked = errors.New("you are already a supporter of this person")
+
+	// ErrStatusChanged is returned when a link changed status between being
+	// read and being saved
+	ErrStatusChanged = errors.New("supporter link was changed by someone else; reload and try again")
+
+	// ErrNotServiceUser is returned when a staff account tries to invite or
+	// become a supporter
+	ErrNotServiceUser = errors.New("supporter links are only available to personal accounts")
+)
+
+// Link connects a service user to a partner or family member
+type Link struct {
+	ID              string  `json:"id" db:"id"`
+	ServiceUserID   string  `json:"service_user_id" db:"service_user_id"`
+	ServiceUserName string  `json:"service_user_name" db:"service_user_name"`
+	SupporterID     *string `json:"supporter_id,omitempty" db:"supporter_id"` // Set when the invitation is accepted
+	SupporterName   *string `json:"supporter_name,omitempty" db:"supporter_name"`
+	Relationship    string  `json:"relationship" db:"relationship"`
+	Status          string  `json:"status" db:"status"`
+	// Shares is what the service user lets the supporter see
+	Shares        []string   `json:"shares" db:"shares"`
+	CodeHash      string     `json:"-" db:"code_hash"`
+	CodeExpiresAt time.Time  `json:"code_expires_at" db:"code_expires_at"`
+	AcceptedAt    *time.Time `json:"accepted_at,omitempty" db:"accepted_at"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	RevokedBy     *string    `json:"revoked_by,omitempty" db:"revoked_by"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CrisisPlan is what a service user wants the people close to them to know
+// and do in a crisis. Supporters only see it when it is shared with them.
+type CrisisPlan struct {
+	UserID               string     `json:"user_id" db:"user_id"`
+	WarningSigns         *string    `json:"warning_signs,omitempty" db:"warning_signs" encrypt:"true"`
+	CopingStrategies     *string    `json:"coping_strategies,omitempty" db:"coping_strategies" encrypt:"true"`
+	HowToHelp            *string    `json:"how_to_help,omitempty" db:"how_to_help" encrypt:"true"`
+	ProfessionalContacts *string    `json:"professional_contacts,omitempty" db:"professional_contacts" encrypt:"true"`
+	UpdatedAt            *time.Time `json:"updated_at,omitempty" db:"updated_at"` // Unset until the plan is first saved
+}
+
+// GroupSession is a support group the service user belongs to
+type GroupSession struct {
+	GroupID     string  `json:"group_id"`
+	Name        string  `json:"name"`
+	Platform    string  `json:"platform"`
+	MeetingTime *string `json:"meeting_time,omitempty"`
+}
+
+// SupporterView is what a supporter sees of the service user they support.
+// Sections the service user doesn't share are left out.
+type SupporterView struct {
+	LinkID          string   `json:"link_id"`
+	ServiceUserID   string   `json:"service_user_id"`
+	ServiceUserName string   `json:"service_user_name"`
+	Relationship    string   `json:"relationship"`
+	Shares          []string `json:"shares"`
+	// ResourceAudience is the resources audience written for this supporter
+	ResourceAudience string `json:"resource_audience"`
+
+	// MoodTrend leaves out private journey entries
+	MoodTrend        *string        `json:"mood_trend,omitempty"`
+	LastCheckIn      *time.Time     `json:"last_check_in,omitempty"`
+	UpcomingSessions []GroupSession `json:"upcoming_sessions,omitempty"`
+	CrisisPlan       *CrisisPlan    `json:"crisis_plan,omitempty"`
+}
+
+// LinkFilter selects links for listing
+type LinkFilter struct {
+	ServiceUserID string
+	SupporterID   string
+	Statuses      []string
+}
+
+// InviteRequest represents a service user inviting a partner or family member
+type InviteRequest struct {
+	Relationship string   `json:"relationship" validate:"required,oneof=partner family"`
+	Shares       []string `json:"shares"`
+}
+
+// InvitationResponse is a new link and the code the supporter accepts it with.
+// The code is only ever returned here.
+type InvitationResponse struct {
+	Link Link   `json:"link"`
+	Code string `json:"code"`
+}
+
+// AcceptRequest represents a supporter entering an invitation code
+type AcceptRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// UpdateSharesRequest replaces what a service user shares with a supporter
+type UpdateSharesRequest struct {
+	Shares []string `json:"shares"`
+}
+
+// UpdateCrisisPlanRequest replaces a service user's crisis plan
+type UpdateCrisisPlanRequest struct {
+	WarningSigns         *string `json:"warning_signs,omitempty" validate:"omitempty,max=2000"`
+	CopingStrategies     *string `json:"coping_strategies,omitempty" validate:"omitempty,max=2000"`
+	HowToHelp            *string `json:"how_to_help,omitempty" validate:"omitempty,max=2000"`
+	ProfessionalContacts *string `json:"professional_contacts,omitempty" validate:"omitempty,max=2000"`
+}
+
+// ListLinksResponse represents a list of supporter links
+type ListLinksResponse struct {
+	Links []Link `json:"links"`
+}
@@ -0,0 +1,357 @@
+package supporters
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/perinatal-mental-health-app/backend/internal/journey"
+	"github.com/perinatal-mental-health-app/backend/internal/resources"
+	"github.com/perinatal-mental-health-app/backend/internal/support_groups"
+)
+
+// codeAlphabet leaves out letters that are easily mistaken for digits, so
+// codes can be read out or typed by hand
+const codeAlphabet = "ABCDEFGHJKMNPQRSTVWXYZ23456789"
+
+const codeLength = 12
+
+type service struct {
+	store         Store
+	journey       journey.Service
+	supportGroups support_groups.Service
+}
+
+func NewService(store Store, journeyService journey.Service, supportGroupsService support_groups.Service) Service {
+	return &service{
+		store:         store,
+		journey:       journeyService,
+		supportGroups: supportGroupsService,
+	}
+}
+
+// Invite creates a pending link and the code the supporter accepts it with
+func (s *service) Invite(ctx context.Context, serviceUserID string, req *InviteRequest) (*InvitationResponse, error) {
+	if req.Relationship != RelationshipPartner && req.Relationship != RelationshipFamily {
+		return nil, fmt.Errorf("relationship must be partner or family")
+	}
+	shares, err := normaliseShares(req.Shares)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireServiceUser(ctx, serviceUserID); err != nil {
+		return nil, err
+	}
+
+	code, err := newCode()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	link, err := s.store.CreateLink(ctx, &Link{
+		ID:            uuid.New().String(),
+		ServiceUserID: serviceUserID,
+		Relationship:  req.Relationship,
+		Status:        StatusPending,
+		Shares:        shares,
+		CodeHash:      hashCode(code),
+		CodeExpiresAt: now.Add(InvitationTTL),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &InvitationResponse{Link: *link, Code: code}, nil
+}
+
+// ListSupporters lists every link of a service user, including revoked ones,
+// so they can see who has had access
+func (s *service) ListSupporters(ctx context.Context, serviceUserID string) (*ListLinksResponse, error) {
+	links, err := s.store.ListLinks(ctx, &LinkFilter{ServiceUserID: serviceUserID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListLinksResponse{Links: links}, nil
+}
+
+// UpdateShares replaces what the service user shares with a supporter
+func (s *service) UpdateShares(ctx context.Context, serviceUserID, linkID string, req *UpdateSharesRequest) (*Link, error) {
+	shares, err := normaliseShares(req.Shares)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := s.store.GetLink(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	if link.ServiceUserID != serviceUserID {
+		return nil, ErrNotFound
+	}
+	if link.Status == StatusRevoked {
+		return nil, fmt.Errorf("sharing can't be changed on a revoked link")
+	}
+
+	link.Shares = shares
+	return s.store.UpdateLink(ctx, link, link.Status)
+}
+
+// GetCrisisPlan returns the user's crisis plan, which is empty until first saved
+func (s *service) GetCrisisPlan(ctx context.Context, userID string) (*CrisisPlan, error) {
+	plan, err := s.store.GetCrisisPlan(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil {
+		return &CrisisPlan{UserID: userID}, nil
+	}
+
+	return plan, nil
+}
+
+// UpdateCrisisPlan replaces the user's crisis plan
+func (s *service) UpdateCrisisPlan(ctx context.Context, userID string, req *UpdateCrisisPlanRequest) (*CrisisPlan, error) {
+	now := time.Now()
+	plan := &CrisisPlan{UserID: userID, UpdatedAt: &now}
+
+	sections := []struct {
+		name  string
+		value *string
+		field **string
+	}{
+		{"warning_signs", req.WarningSigns, &plan.WarningSigns},
+		{"coping_strategies", req.CopingStrategies, &plan.CopingStrategies},
+		{"how_to_help", req.HowToHelp, &plan.HowToHelp},
+		{"professional_contacts", req.ProfessionalContacts, &plan.ProfessionalContacts},
+	}
+	for _, section := range sections {
+		if section.value == nil {
+			continue
+		}
+		value := strings.TrimSpace(*section.value)
+		if len(value) > 2000 {
+			return nil, fmt.Errorf("%s must be at most 2000 characters", section.name)
+		}
+		if value != "" {
+			*section.field = &value
+		}
+	}
+
+	return s.store.SaveCrisisPlan(ctx, plan)
+}
+
+// Accept makes the caller the supporter on the link the code belongs to
+func (s *service) Accept(ctx context.Context, supporterID string, req *AcceptRequest) (*Link, error) {
+	code := normaliseCode(req.Code)
+	if code == "" {
+		return nil, fmt.Errorf("code is required")
+	}
+	if err := s.requireServiceUser(ctx, supporterID); err != nil {
+		return nil, err
+	}
+
+	link, err := s.store.GetLinkByCode(ctx, hashCode(code))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrInvalidCode
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if link.Status != StatusPending || !link.CodeExpiresAt.After(now) {
+		return nil, ErrInvalidCode
+	}
+	if link.ServiceUserID == supporterID {
+		return nil, fmt.Errorf("you can't be your own supporter")
+	}
+
+	link.SupporterID = &supporterID
+	link.Status = StatusActive
+	link.AcceptedAt = &now
+
+	updated, err := s.store.UpdateLink(ctx, link, StatusPending)
+	if errors.Is(err, ErrStatusChanged) {
+		return nil, ErrInvalidCode
+	}
+	return updated, err
+}
+
+// ListSupporting lists the active links where the caller is the supporter
+func (s *service) ListSupporting(ctx context.Context, supporterID string) (*ListLinksResponse, error) {
+	links, err := s.store.ListLinks(ctx, &LinkFilter{SupporterID: supporterID, Statuses: []string{StatusActive}})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListLinksResponse{Links: links}, nil
+}
+
+// GetView returns what the service user shares with the supporter. Only the
+// shared sections are loaded.
+func (s *service) GetView(ctx context.Context, supporterID, linkID string) (*SupporterView, error) {
+	link, err := s.store.GetLink(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	if link.Status != StatusActive || link.SupporterID == nil || *link.SupporterID != supporterID {
+		return nil, ErrNotFound
+	}
+
+	view := &SupporterView{
+		LinkID:           link.ID,
+		ServiceUserID:    link.ServiceUserID,
+		ServiceUserName:  link.ServiceUserName,
+		Relationship:     link.Relationship,
+		Shares:           link.Shares,
+		ResourceAudience: string(resources.AudienceFamilies),
+	}
+	if link.Relationship == RelationshipPartner {
+		view.ResourceAudience = string(resources.AudiencePartners)
+	}
+
+	if slices.Contains(link.Shares, ShareMoodTrend) {
+		checkIns, err := s.journey.GetCheckInSummaries(ctx, []string{link.ServiceUserID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load check-ins: %w", err)
+		}
+		if checkIn, ok := checkIns[link.ServiceUserID]; ok {
+			trend := checkIn.MoodTrend
+			view.MoodTrend = &trend
+			view.LastCheckIn = checkIn.LastCheckIn
+		}
+	}
+
+	if slices.Contains(link.Shares, ShareGroupSessions) {
+		groups, err := s.supportGroups.GetGroupsForUsers(ctx, []string{link.ServiceUserID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load support groups: %w", err)
+		}
+		view.UpcomingSessions = []GroupSession{}
+		for _, group := range groups[link.ServiceUserID] {
+			view.UpcomingSessions = append(view.UpcomingSessions, GroupSession{
+				GroupID:     group.ID,
+				Name:        group.Name,
+				Platform:    group.Platform,
+				MeetingTime: group.MeetingTime,
+			})
+		}
+	}
+
+	if slices.Contains(link.Shares, ShareCrisisPlan) {
+		if view.CrisisPlan, err = s.GetCrisisPlan(ctx, link.ServiceUserID); err != nil {
+			return nil, err
+		}
+	}
+
+	return view, nil
+}
+
+// Revoke ends a link. Either party can revoke an active link; only the
+// service user can withdraw a pending invitation.
+func (s *service) Revoke(ctx context.Context, userID, linkID string) (*Link, error) {
+	link, err := s.store.GetLink(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	isSupporter := link.SupporterID != nil && *link.SupporterID == userID
+	if link.ServiceUserID != userID && !isSupporter {
+		return nil, ErrNotFound
+	}
+	if link.Status == StatusRevoked {
+		return nil, fmt.Errorf("link is already revoked")
+	}
+
+	fromStatus := link.Status
+	now := time.Now()
+	link.Status = StatusRevoked
+	link.RevokedAt = &now
+	link.RevokedBy = &userID
+
+	return s.store.UpdateLink(ctx, link, fromStatus)
+}
+
+// Helper functions
+
+func (s *service) requireServiceUser(ctx context.Context, userID string) error {
+	role, err := s.store.GetUserRole(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if role != roleServiceUser {
+		return ErrNotServiceUser
+	}
+	return nil
+}
+
+// normaliseShares checks the shares and returns them once each, in a fixed order
+func normaliseShares(shares []string) ([]string, error) {
+	for _, share := range shares {
+		if !slices.Contains(Shares, share) {
+			return nil, fmt.Errorf("invalid share: %s (must be one of %s)", share, strings.Join(Shares, ", "))
+		}
+	}
+
+	normalised := []string{}
+	for _, share := range Shares {
+		if slices.Contains(shares, share) {
+			normalised = append(normalised, share)
+		}
+	}
+	return normalised, nil
+}
+
+// newCode returns a random invitation code grouped in fours, like ABCD-EFGH-JKMN
+func newCode() (string, error) {
+	// Bytes past the last whole multiple of the alphabet are dropped, so every
+	// character is equally likely
+	limit := 256 - 256%len(codeAlphabet)
+
+	var code strings.Builder
+	random := make([]byte, codeLength)
+	for n := 0; n < codeLength; {
+		if _, err := rand.Read(random); err != nil {
+			return "", fmt.Errorf("failed to generate invitation code: %w", err)
+		}
+		for _, b := range random {
+			if int(b) >= limit || n == codeLength {
+				continue
+			}
+			if n > 0 && n%4 == 0 {
+				code.WriteByte('-')
+			}
+			code.WriteByte(codeAlphabet[int(b)%len(codeAlphabet)])
+			n++
+		}
+	}
+	return code.String(), nil
+}
+
+// normaliseCode ignores case, spaces and dashes so codes can be typed loosely
+func normaliseCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '-' || r == ' ':
+			return -1
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		}
+		return r
+	}, strings.TrimSpace(code))
+}
+
+func hashCode(code string) string {
+	sum := sha256.Sum256([]byte(normaliseCode(code)))
+	return hex.EncodeToString(sum[:])
+}
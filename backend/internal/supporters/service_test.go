@@ -0,0 +1,189 @@
+package supporters
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/jobs"
+	"github.com/perinatal-mental-health-app/backend/internal/journey"
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+	"github.com/perinatal-mental-health-app/backend/internal/support_groups"
+)
+
+const (
+	parent       = "11111111-1111-1111-1111-111111111111"
+	partner      = "22222222-2222-2222-2222-222222222222"
+	grandparent  = "33333333-3333-3333-3333-333333333333"
+	professional = "44444444-4444-4444-4444-444444444444"
+)
+
+func strPtr(s string) *string { return &s }
+
+func newTestService(t *testing.T) Service {
+	t.Helper()
+	ctx := context.Background()
+
+	db := memdb.New()
+	now := time.Now()
+	for _, u := range []memdb.User{
+		{ID: parent, FullName: "Sam Parent", Email: "sam@example.com", Role: "service_user", IsActive: true},
+		{ID: partner, FullName: "Chris Partner", Email: "chris@example.com", Role: "service_user", IsActive: true},
+		{ID: grandparent, FullName: "Jo Grandparent", Email: "jo@example.com", Role: "service_user", IsActive: true},
+		{ID: professional, FullName: "Pat Professional", Email: "pro@example.com", Role: "professional", IsActive: true},
+	} {
+		u.CreatedAt, u.UpdatedAt = now, now
+		if err := db.InsertUser(u, memdb.Profile{UserID: u.ID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	journeyStore := journey.NewMemoryStore()
+	for _, entry := range []struct {
+		daysAgo   int
+		mood      int
+		isPrivate bool
+	}{
+		{2, 4, false},
+		{20, 2, false},
+		{1, 1, true},
+	} {
+		req := &journey.CreateJourneyEntryRequest{MoodRating: entry.mood, IsPrivate: entry.isPrivate}
+		if _, err := journeyStore.CreateJourneyEntry(ctx, parent, now.AddDate(0, 0, -entry.daysAgo), req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	groupsStore := support_groups.NewMemoryStore(db)
+	group, err := groupsStore.CreateSupportGroup(ctx, &support_groups.CreateSupportGroupRequest{
+		Name: "New Parents Circle", Description: "Weekly group", Category: "postnatal", Platform: "online", MeetingTime: strPtr("Tuesdays 10:00"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := groupsStore.JoinGroup(ctx, parent, group.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewService(
+		NewMemoryStore(db),
+		journey.NewService(journeyStore, jobs.NewService(jobs.NewMemoryStore())),
+		support_groups.NewService(groupsStore),
+	)
+}
+
+func TestSupporterLink(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t)
+
+	if _, err := svc.Invite(ctx, parent, &InviteRequest{Relationship: "friend"}); err == nil {
+		t.Error("Invite() with an unknown relationship succeeded")
+	}
+	if _, err := svc.Invite(ctx, parent, &InviteRequest{Relationship: RelationshipPartner, Shares: []string{"journal"}}); err == nil {
+		t.Error("Invite() with an unknown share succeeded")
+	}
+	if _, err := svc.Invite(ctx, professional, &InviteRequest{Relationship: RelationshipPartner}); !errors.Is(err, ErrNotServiceUser) {
+		t.Errorf("Invite() by a professional error = %v, want ErrNotServiceUser", err)
+	}
+
+	invitation, err := svc.Invite(ctx, parent, &InviteRequest{
+		Relationship: RelationshipPartner,
+		Shares:       []string{ShareGroupSessions, ShareMoodTrend, ShareMoodTrend},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if invitation.Link.Status != StatusPending || len(invitation.Link.Shares) != 2 || invitation.Link.Shares[0] != ShareMoodTrend {
+		t.Errorf("Invite() = %+v, want a pending link sharing each share once in a fixed order", invitation.Link)
+	}
+
+	if _, err := svc.Accept(ctx, parent, &AcceptRequest{Code: invitation.Code}); err == nil {
+		t.Error("Accept() by the service user themselves succeeded")
+	}
+	if _, err := svc.Accept(ctx, partner, &AcceptRequest{Code: "AAAA-BBBB-CCCC"}); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("Accept() with an unknown code error = %v, want ErrInvalidCode", err)
+	}
+
+	// Codes can be typed in lower case without the dashes
+	typed := strings.ToLower(strings.ReplaceAll(invitation.Code, "-", " "))
+	link, err := svc.Accept(ctx, partner, &AcceptRequest{Code: typed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link.Status != StatusActive || link.SupporterName == nil || *link.SupporterName != "Chris Partner" {
+		t.Errorf("Accept() = %+v, want an active link with the supporter's name", link)
+	}
+	if _, err := svc.Accept(ctx, grandparent, &AcceptRequest{Code: invitation.Code}); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("Accept() with a used code error = %v, want ErrInvalidCode", err)
+	}
+
+	second, err := svc.Invite(ctx, parent, &InviteRequest{Relationship: RelationshipPartner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Accept(ctx, partner, &AcceptRequest{Code: second.Code}); !errors.Is(err, ErrAlreadyLinked) {
+		t.Errorf("Accept() of a second link error = %v, want ErrAlreadyLinked", err)
+	}
+
+	view, err := svc.GetView(ctx, partner, link.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if view.MoodTrend == nil || *view.MoodTrend != "improving" {
+		t.Errorf("GetView() mood trend = %v, want improving without the private entry", view.MoodTrend)
+	}
+	if len(view.UpcomingSessions) != 1 || view.CrisisPlan != nil || view.ResourceAudience != "partners" {
+		t.Errorf("GetView() = %+v, want group sessions and partner resources but no crisis plan", view)
+	}
+	if _, err := svc.GetView(ctx, grandparent, link.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetView() by someone else error = %v, want ErrNotFound", err)
+	}
+
+	// Sharing the crisis plan instead of the mood trend changes the view straight away
+	if _, err := svc.UpdateCrisisPlan(ctx, parent, &UpdateCrisisPlanRequest{HowToHelp: strPtr("  Take the baby for a walk  "), WarningSigns: strPtr(" ")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.UpdateShares(ctx, partner, link.ID, &UpdateSharesRequest{Shares: Shares}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateShares() by the supporter error = %v, want ErrNotFound", err)
+	}
+	if _, err := svc.UpdateShares(ctx, parent, link.ID, &UpdateSharesRequest{Shares: []string{ShareCrisisPlan}}); err != nil {
+		t.Fatal(err)
+	}
+	view, err = svc.GetView(ctx, partner, link.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if view.MoodTrend != nil || view.UpcomingSessions != nil || view.CrisisPlan == nil {
+		t.Fatalf("GetView() after UpdateShares = %+v, want only the crisis plan", view)
+	}
+	if view.CrisisPlan.HowToHelp == nil || *view.CrisisPlan.HowToHelp != "Take the baby for a walk" || view.CrisisPlan.WarningSigns != nil {
+		t.Errorf("GetView() crisis plan = %+v, want trimmed sections with blanks left out", view.CrisisPlan)
+	}
+
+	// Either side can revoke
+	revoked, err := svc.Revoke(ctx, partner, link.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revoked.Status != StatusRevoked || revoked.RevokedBy == nil || *revoked.RevokedBy != partner {
+		t.Errorf("Revoke() = %+v, want revoked by the supporter", revoked)
+	}
+	if _, err := svc.GetView(ctx, partner, link.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetView() after Revoke() error = %v, want ErrNotFound", err)
+	}
+	if supporting, _ := svc.ListSupporting(ctx, partner); len(supporting.Links) != 0 {
+		t.Errorf("ListSupporting() after Revoke() = %+v, want none", supporting.Links)
+	}
+	if _, err := svc.Revoke(ctx, parent, second.Link.ID); err != nil {
+		t.Errorf("Revoke() of a pending invitation error = %v", err)
+	}
+	if _, err := svc.Accept(ctx, partner, &AcceptRequest{Code: second.Code}); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("Accept() of a withdrawn invitation error = %v, want ErrInvalidCode", err)
+	}
+
+	if own, _ := svc.ListSupporters(ctx, parent); len(own.Links) != 2 {
+		t.Errorf("ListSupporters() = %d links, want both, including revoked ones", len(own.Links))
+	}
+}
@@ -0,0 +1,261 @@
+package supporters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/perinatal-mental-health-app/backend/internal/encryption"
+)
+
+type store struct {
+	db     *pgxpool.Pool
+	cipher encryption.Cipher
+}
+
+func NewStore(db *pgxpool.Pool, cipher encryption.Cipher) Store {
+	return &store{
+		db:     db,
+		cipher: cipher,
+	}
+}
+
+const linkColumns = `l.id, l.service_user_id, service_user.full_name, l.supporter_id, supporter.full_name,
+	l.relationship, l.status, l.shares, l.code_hash, l.code_expires_at, l.accepted_at, l.revoked_at,
+	l.revoked_by, l.created_at, l.updated_at`
+
+const linkTables = `supporter_links l
+	JOIN users service_user ON service_user.id = l.service_user_id
+	LEFT JOIN users supporter ON supporter.id = l.supporter_id`
+
+// CreateLink creates a pending link
+func (s *store) CreateLink(ctx context.Context, link *Link) (*Link, error) {
+	query := `
+		INSERT INTO supporter_links (id, service_user_id, relationship, status, shares, code_hash,
+		                             code_expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := s.db.Exec(ctx, query,
+		link.ID, link.ServiceUserID, link.Relationship, link.Status, link.Shares, link.CodeHash,
+		link.CodeExpiresAt, link.CreatedAt, link.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create supporter link: %w", err)
+	}
+
+	return s.GetLink(ctx, link.ID)
+}
+
+// GetLink retrieves a link with both parties' names
+func (s *store) GetLink(ctx context.Context, linkID string) (*Link, error) {
+	if _, err := uuid.Parse(linkID); err != nil {
+		return nil, ErrNotFound
+	}
+
+	return s.getLink(ctx, `l.id = $1`, linkID)
+}
+
+// GetLinkByCode retrieves the link with the invitation code hash
+func (s *store) GetLinkByCode(ctx context.Context, codeHash string) (*Link, error) {
+	return s.getLink(ctx, `l.code_hash = $1`, codeHash)
+}
+
+// ListLinks retrieves links, newest first
+func (s *store) ListLinks(ctx context.Context, filter *LinkFilter) ([]Link, error) {
+	var whereClause []string
+	var args []interface{}
+	argIndex := 1
+
+	if filter.ServiceUserID != "" {
+		whereClause = append(whereClause, fmt.Sprintf("l.service_user_id = $%d", argIndex))
+		args = append(args, filter.ServiceUserID)
+		argIndex++
+	}
+
+	if filter.SupporterID != "" {
+		whereClause = append(whereClause, fmt.Sprintf("l.supporter_id = $%d", argIndex))
+		args = append(args, filter.SupporterID)
+		argIndex++
+	}
+
+	if len(filter.Statuses) > 0 {
+		whereClause = append(whereClause, fmt.Sprintf("l.status = ANY($%d)", argIndex))
+		args = append(args, filter.Statuses)
+		argIndex++
+	}
+
+	whereSQL := ""
+	if len(whereClause) > 0 {
+		whereSQL = "WHERE " + strings.Join(whereClause, " AND ")
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM %s %s ORDER BY l.created_at DESC, l.id DESC`, linkColumns, linkTables, whereSQL)
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list supporter links: %w", err)
+	}
+	defer rows.Close()
+
+	links := []Link{}
+	for rows.Next() {
+		var link Link
+		if err := scanLink(rows, &link); err != nil {
+			return nil, fmt.Errorf("failed to scan supporter link: %w", err)
+		}
+		links = append(links, link)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return links, nil
+}
+
+// UpdateLink saves the supporter, status, shares and revocation of a link
+func (s *store) UpdateLink(ctx context.Context, link *Link, fromStatus string) (*Link, error) {
+	query := `
+		UPDATE supporter_links
+		SET supporter_id = $2, status = $3, shares = $4, accepted_at = $5, revoked_at = $6, revoked_by = $7
+		WHERE id = $1 AND status = $8
+	`
+
+	result, err := s.db.Exec(ctx, query,
+		link.ID, link.SupporterID, link.Status, link.Shares, link.AcceptedAt, link.RevokedAt, link.RevokedBy, fromStatus,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrAlreadyLinked
+		}
+		return nil, fmt.Errorf("failed to update supporter link: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return nil, ErrStatusChanged
+	}
+
+	return s.GetLink(ctx, link.ID)
+}
+
+// GetCrisisPlan retrieves a user's crisis plan
+func (s *store) GetCrisisPlan(ctx context.Context, userID string) (*CrisisPlan, error) {
+	query := `
+		SELECT user_id, warning_signs, coping_strategies, how_to_help, professional_contacts, updated_at
+		FROM crisis_plans
+		WHERE user_id = $1
+	`
+
+	var plan CrisisPlan
+	err := s.db.QueryRow(ctx, query, userID).Scan(
+		&plan.UserID,
+		&plan.WarningSigns,
+		&plan.CopingStrategies,
+		&plan.HowToHelp,
+		&plan.ProfessionalContacts,
+		&plan.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get crisis plan: %w", err)
+	}
+	if err := s.cipher.DecryptFields(ctx, &plan); err != nil {
+		return nil, fmt.Errorf("failed to decrypt crisis plan: %w", err)
+	}
+
+	return &plan, nil
+}
+
+// SaveCrisisPlan creates or replaces a user's crisis plan
+func (s *store) SaveCrisisPlan(ctx context.Context, plan *CrisisPlan) (*CrisisPlan, error) {
+	sealed := *plan
+	if err := s.cipher.EncryptFields(ctx, &sealed); err != nil {
+		return nil, fmt.Errorf("failed to encrypt crisis plan: %w", err)
+	}
+
+	query := `
+		INSERT INTO crisis_plans (user_id, warning_signs, coping_strategies, how_to_help, professional_contacts, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE
+		SET warning_signs = EXCLUDED.warning_signs, coping_strategies = EXCLUDED.coping_strategies,
+		    how_to_help = EXCLUDED.how_to_help, professional_contacts = EXCLUDED.professional_contacts,
+		    updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := s.db.Exec(ctx, query,
+		sealed.UserID, sealed.WarningSigns, sealed.CopingStrategies, sealed.HowToHelp,
+		sealed.ProfessionalContacts, sealed.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save crisis plan: %w", err)
+	}
+
+	return s.GetCrisisPlan(ctx, plan.UserID)
+}
+
+// GetUserRole returns the role of an active user
+func (s *store) GetUserRole(ctx context.Context, userID string) (string, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return "", ErrNotFound
+	}
+
+	var role string
+	err := s.db.QueryRow(ctx, `SELECT role FROM users WHERE id = $1 AND is_active = true`, userID).Scan(&role)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("failed to get user role: %w", err)
+	}
+
+	return role, nil
+}
+
+// Helper functions
+
+func (s *store) getLink(ctx context.Context, where string, arg string) (*Link, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE %s`, linkColumns, linkTables, where)
+
+	var link Link
+	if err := scanLink(s.db.QueryRow(ctx, query, arg), &link); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get supporter link: %w", err)
+	}
+
+	return &link, nil
+}
+
+func scanLink(row pgx.Row, link *Link) error {
+	return row.Scan(
+		&link.ID,
+		&link.ServiceUserID,
+		&link.ServiceUserName,
+		&link.SupporterID,
+		&link.SupporterName,
+		&link.Relationship,
+		&link.Status,
+		&link.Shares,
+		&link.CodeHash,
+		&link.CodeExpiresAt,
+		&link.AcceptedAt,
+		&link.RevokedAt,
+		&link.RevokedBy,
+		&link.CreatedAt,
+		&link.UpdatedAt,
+	)
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
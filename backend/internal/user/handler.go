@@ -1,10 +1,15 @@
 package user
 
 import (
+	"context"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/perinatal"
 )
 
 type handler struct {
@@ -116,8 +121,13 @@ func (h *handler) UpdateUser(c echo.Context) error {
 		})
 	}
 
-	user, err := h.service.UpdateUser(c.Request().Context(), userID, &req)
+	user, err := h.service.UpdateUser(c.Request().Context(), organisations.ScopeFromContext(c), userID, &req)
 	if err != nil {
+		if errors.Is(err, organisations.ErrOutOfScope) {
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"error": err.Error(),
+			})
+		}
 		return c.JSON(http.StatusNotFound, map[string]string{
 			"error": err.Error(),
 		})
@@ -148,7 +158,8 @@ func (h *handler) UpdateCurrentUser(c echo.Context) error {
 		})
 	}
 
-	user, err := h.service.UpdateUser(c.Request().Context(), userID, &req)
+	// Users can always update their own account
+	user, err := h.service.UpdateUser(c.Request().Context(), organisations.Scope{All: true}, userID, &req)
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{
 			"error": err.Error(),
@@ -181,7 +192,13 @@ func (h *handler) ListUsers(c echo.Context) error {
 		roleFilter = &role
 	}
 
-	users, err := h.service.ListUsers(c.Request().Context(), page, pageSize, roleFilter)
+	var statusFilter *AccountStatus
+	if st := c.QueryParam("status"); st != "" {
+		status := AccountStatus(st)
+		statusFilter = &status
+	}
+
+	users, err := h.service.ListUsers(c.Request().Context(), organisations.ScopeFromContext(c), page, pageSize, roleFilter, statusFilter)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": err.Error(),
@@ -193,6 +210,13 @@ func (h *handler) ListUsers(c echo.Context) error {
 
 // SearchUsers searches for users based on query parameters
 func (h *handler) SearchUsers(c echo.Context) error {
+	searcherID := getUserIDFromContext(c)
+	if searcherID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "User not authenticated",
+		})
+	}
+
 	query := c.QueryParam("q")
 	if query == "" {
 		return c.JSON(http.StatusBadRequest, map[string]string{
@@ -213,7 +237,7 @@ func (h *handler) SearchUsers(c echo.Context) error {
 		roleFilter = &role
 	}
 
-	users, err := h.service.SearchUsers(c.Request().Context(), query, limit, roleFilter)
+	users, err := h.service.SearchUsers(c.Request().Context(), searcherID, query, limit, roleFilter)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": err.Error(),
@@ -226,7 +250,8 @@ func (h *handler) SearchUsers(c echo.Context) error {
 	})
 }
 
-// DeactivateUser deactivates a user account
+// DeactivateUser suspends a user account. The reason is optional here, for
+// clients written before accounts had states.
 func (h *handler) DeactivateUser(c echo.Context) error {
 	userID := c.Param("id")
 	if userID == "" {
@@ -235,11 +260,21 @@ func (h *handler) DeactivateUser(c echo.Context) error {
 		})
 	}
 
-	err := h.service.DeactivateUser(c.Request().Context(), userID)
+	var req AccountStatusRequest
+	if c.Request().ContentLength > 0 {
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid request body",
+			})
+		}
+	}
+	if req.Reason == "" {
+		req.Reason = DeactivationReason
+	}
+
+	_, err := h.service.SuspendUser(c.Request().Context(), organisations.ScopeFromContext(c), userID, getUserIDFromContext(c), &req)
 	if err != nil {
-		return c.JSON(http.StatusNotFound, map[string]string{
-			"error": err.Error(),
-		})
+		return statusErrorResponse(c, err)
 	}
 
 	return c.JSON(http.StatusOK, map[string]string{
@@ -247,6 +282,117 @@ func (h *handler) DeactivateUser(c echo.Context) error {
 	})
 }
 
+// SuspendUser suspends a user account
+func (h *handler) SuspendUser(c echo.Context) error {
+	return h.changeStatus(c, h.service.SuspendUser)
+}
+
+// ReactivateUser returns a suspended or pending-deletion account to active
+func (h *handler) ReactivateUser(c echo.Context) error {
+	return h.changeStatus(c, h.service.ReactivateUser)
+}
+
+// ScheduleDeletion marks an account for erasure after the grace period
+func (h *handler) ScheduleDeletion(c echo.Context) error {
+	return h.changeStatus(c, h.service.ScheduleDeletion)
+}
+
+// GetAccountStatus returns an account's status and its history
+func (h *handler) GetAccountStatus(c echo.Context) error {
+	status, err := h.service.GetAccountStatus(c.Request().Context(), organisations.ScopeFromContext(c), c.Param("id"))
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, status)
+}
+
+// UpdateUserRole changes a user's role
+func (h *handler) UpdateUserRole(c echo.Context) error {
+	var req ChangeRoleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	user, err := h.service.UpdateUserRole(c.Request().Context(), organisations.ScopeFromContext(c), c.Param("id"), getUserIDFromContext(c), req.Role)
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
+
+// RequestEmailChange starts changing a user's email address. The confirmation
+// is emailed to the new address in the background.
+func (h *handler) RequestEmailChange(c echo.Context) error {
+	var req ChangeEmailRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	change, err := h.service.RequestEmailChange(c.Request().Context(), organisations.ScopeFromContext(c), c.Param("id"), getUserIDFromContext(c), &req)
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusAccepted, change)
+}
+
+// ConfirmEmailChange applies an email change with the token sent to the new address
+func (h *handler) ConfirmEmailChange(c echo.Context) error {
+	var req ConfirmEmailChangeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	user, err := h.service.ConfirmEmailChange(c.Request().Context(), &req)
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
+
+// PreviewMerge shows what merging the user in the path into another would move
+func (h *handler) PreviewMerge(c echo.Context) error {
+	into := c.QueryParam("into")
+	if into == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "into is required",
+		})
+	}
+
+	preview, err := h.service.PreviewMerge(c.Request().Context(), organisations.ScopeFromContext(c), c.Param("id"), into, getUserIDFromContext(c))
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, preview)
+}
+
+// MergeUsers merges the user in the path into another account
+func (h *handler) MergeUsers(c echo.Context) error {
+	var req MergeUsersRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	merge, err := h.service.MergeUsers(c.Request().Context(), organisations.ScopeFromContext(c), c.Param("id"), getUserIDFromContext(c), &req)
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, merge)
+}
+
 // UpdateLastLogin updates the user's last login time
 func (h *handler) UpdateLastLogin(c echo.Context) error {
 	userID := getUserIDFromContext(c)
@@ -296,22 +442,116 @@ func (h *handler) UpdateUserPreferences(c echo.Context) error {
 		})
 	}
 
-	var preferences map[string]interface{}
-	if err := c.Bind(&preferences); err != nil {
+	document, err := io.ReadAll(c.Request().Body)
+	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": "Invalid request format",
 		})
 	}
 
-	err := h.service.UpdateUserPreferences(c.Request().Context(), userID, preferences)
+	preferences, err := h.service.UpdateUserPreferences(c.Request().Context(), userID, document)
 	if err != nil {
+		if errors.Is(err, ErrInvalidPreferences) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": err.Error(),
 		})
 	}
 
-	return c.JSON(http.StatusOK, map[string]string{
-		"message": "Preferences updated successfully",
+	return c.JSON(http.StatusOK, preferences)
+}
+
+// GetPreferencesSchema serves the JSON Schema preferences documents must follow
+func (h *handler) GetPreferencesSchema(c echo.Context) error {
+	return c.Blob(http.StatusOK, "application/schema+json", PreferencesSchema())
+}
+
+// GetPerinatal gets the current user's perinatal details and stage
+func (h *handler) GetPerinatal(c echo.Context) error {
+	userID := getUserIDFromContext(c)
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "User not authenticated",
+		})
+	}
+
+	response, err := h.service.GetPerinatal(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// UpdatePerinatal replaces the current user's perinatal details
+func (h *handler) UpdatePerinatal(c echo.Context) error {
+	userID := getUserIDFromContext(c)
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "User not authenticated",
+		})
+	}
+
+	var details perinatal.Details
+	if err := c.Bind(&details); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	response, err := h.service.UpdatePerinatal(c.Request().Context(), userID, &details)
+	if err != nil {
+		if errors.Is(err, perinatal.ErrInvalidDetails) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// Helper functions
+
+// changeStatus binds a status change request for the user in the path and
+// applies it with the given service method
+func (h *handler) changeStatus(c echo.Context, apply func(ctx context.Context, scope organisations.Scope, userID, actorID string, req *AccountStatusRequest) (*AccountStatusResponse, error)) error {
+	var req AccountStatusRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	status, err := apply(c.Request().Context(), organisations.ScopeFromContext(c), c.Param("id"), getUserIDFromContext(c), &req)
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, status)
+}
+
+func statusErrorResponse(c echo.Context, err error) error {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, ErrUserNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrOwnAccount), errors.Is(err, ErrHigherRole), errors.Is(err, organisations.ErrOutOfScope):
+		status = http.StatusForbidden
+	case errors.Is(err, ErrInvalidTransition), errors.Is(err, ErrStatusChanged), errors.Is(err, ErrEmailTaken):
+		status = http.StatusConflict
+	}
+
+	return c.JSON(status, map[string]string{
+		"error": err.Error(),
 	})
 }
 
@@ -2,7 +2,12 @@ package user
 
 import (
 	"context"
+	"encoding/json"
+	"time"
+
 	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/perinatal"
 )
 
 // Service defines the interface for user business logic
@@ -10,14 +15,36 @@ type Service interface {
 	CreateUser(ctx context.Context, req *CreateUserRequest) (*UserResponse, error)
 	GetUser(ctx context.Context, userID string) (*UserResponse, error)
 	GetUserProfile(ctx context.Context, userID string) (*UserProfileResponse, error)
-	UpdateUser(ctx context.Context, userID string, req *UpdateUserRequest) (*UserResponse, error)
-	ListUsers(ctx context.Context, page, pageSize int, role *UserRole) (*ListUsersResponse, error)
-	SearchUsers(ctx context.Context, query string, limit int, role *UserRole) ([]UserResponse, error)
+	UpdateUser(ctx context.Context, scope organisations.Scope, userID string, req *UpdateUserRequest) (*UserResponse, error)
+	ListUsers(ctx context.Context, scope organisations.Scope, page, pageSize int, role *UserRole, status *AccountStatus) (*ListUsersResponse, error)
+	SearchUsers(ctx context.Context, searcherID, query string, limit int, role *UserRole) ([]UserResponse, error)
 	GetUserByEmail(ctx context.Context, email string) (*UserResponse, error)
 	UpdateLastLogin(ctx context.Context, userID string) error
-	DeactivateUser(ctx context.Context, userID string) error
-	GetUserPreferences(ctx context.Context, userID string) (map[string]interface{}, error)
-	UpdateUserPreferences(ctx context.Context, userID string, preferences map[string]interface{}) error
+	// SuspendUser, ReactivateUser and ScheduleDeletion record the change against
+	// actorID, which is empty for the admin CLI. Staff can't change their own
+	// account, or suspend or erase one whose role outranks theirs.
+	SuspendUser(ctx context.Context, scope organisations.Scope, userID, actorID string, req *AccountStatusRequest) (*AccountStatusResponse, error)
+	ReactivateUser(ctx context.Context, scope organisations.Scope, userID, actorID string, req *AccountStatusRequest) (*AccountStatusResponse, error)
+	ScheduleDeletion(ctx context.Context, scope organisations.Scope, userID, actorID string, req *AccountStatusRequest) (*AccountStatusResponse, error)
+	GetAccountStatus(ctx context.Context, scope organisations.Scope, userID string) (*AccountStatusResponse, error)
+	// AnonymiseAccount is the handler for JobAnonymiseAccount
+	AnonymiseAccount(ctx context.Context, job AnonymiseAccountJob) error
+	// UpdateUserRole, RequestEmailChange, PreviewMerge and MergeUsers are staff
+	// administration of accounts in scope; actorID is empty for the admin CLI
+	UpdateUserRole(ctx context.Context, scope organisations.Scope, userID, actorID string, role UserRole) (*UserResponse, error)
+	RequestEmailChange(ctx context.Context, scope organisations.Scope, userID, actorID string, req *ChangeEmailRequest) (*EmailChangeResponse, error)
+	// SendEmailChange is the handler for JobSendEmailChange
+	SendEmailChange(ctx context.Context, job SendEmailChangeJob) error
+	ConfirmEmailChange(ctx context.Context, req *ConfirmEmailChangeRequest) (*UserResponse, error)
+	PreviewMerge(ctx context.Context, scope organisations.Scope, mergedID, survivingID, actorID string) (*MergePreviewResponse, error)
+	MergeUsers(ctx context.Context, scope organisations.Scope, mergedID, actorID string, req *MergeUsersRequest) (*AccountMerge, error)
+	GetUserPreferences(ctx context.Context, userID string) (*Preferences, error)
+	UpdateUserPreferences(ctx context.Context, userID string, document json.RawMessage) (*Preferences, error)
+	GetPerinatal(ctx context.Context, userID string) (*PerinatalResponse, error)
+	// UpdatePerinatal replaces the user's details; empty details clear them
+	UpdatePerinatal(ctx context.Context, userID string, details *perinatal.Details) (*PerinatalResponse, error)
+	// GetPerinatalStage is for modules personalising content to the user's stage
+	GetPerinatalStage(ctx context.Context, userID string) (*perinatal.Progress, error)
 }
 
 // Store defines the interface for user data persistence
@@ -28,12 +55,36 @@ type Store interface {
 	GetUserProfile(ctx context.Context, userID string) (*UserProfile, error)
 	UpdateUser(ctx context.Context, userID string, req *UpdateUserRequest) (*User, error)
 	UpdateUserProfile(ctx context.Context, userID string, req *UpdateUserRequest) (*UserProfile, error)
-	ListUsers(ctx context.Context, page, pageSize int, role *UserRole) (*ListUsersResponse, error)
-	SearchUsers(ctx context.Context, query string, limit int, role *UserRole) ([]User, error)
+	ListUsers(ctx context.Context, scope organisations.Scope, page, pageSize int, role *UserRole, status *AccountStatus) (*ListUsersResponse, error)
+	// UserInScope reports whether the user is staff of one of the scope's
+	// organisations or in an open care-team relationship with its staff
+	UserInScope(ctx context.Context, scope organisations.Scope, userID string) (bool, error)
+	// SearchUsers finds active users by name or email. When serviceUserIDs is
+	// not nil, service users outside it are left out.
+	SearchUsers(ctx context.Context, query string, limit int, role *UserRole, serviceUserIDs []string) ([]User, error)
 	UpdateLastLogin(ctx context.Context, userID string) error
-	DeactivateUser(ctx context.Context, userID string) error
-	GetUserPreferences(ctx context.Context, userID string) (map[string]interface{}, error)
-	UpdateUserPreferences(ctx context.Context, userID string, preferences map[string]interface{}) error
+	// GetAccountStatus finds accounts in any state, unlike GetUserByID
+	GetAccountStatus(ctx context.Context, userID string) (*AccountStatusResponse, error)
+	// UpdateAccountStatus fails with ErrStatusChanged if the account is no
+	// longer in change.FromStatus
+	UpdateAccountStatus(ctx context.Context, change *StatusChange, deletionScheduledAt *time.Time) error
+	AnonymiseUser(ctx context.Context, change *StatusChange) error
+	ListStatusChanges(ctx context.Context, userID string) ([]StatusChange, error)
+	// UpdateUserRole revokes the user's tokens, which carry the old role
+	UpdateUserRole(ctx context.Context, userID string, role UserRole) (*User, error)
+	// SaveEmailChange replaces any pending email change for the user
+	SaveEmailChange(ctx context.Context, change *EmailChange) error
+	// ConfirmEmailChange applies the pending change with the token hash and
+	// revokes the user's tokens
+	ConfirmEmailChange(ctx context.Context, tokenHash string, now time.Time) (*User, error)
+	CountMergeRecords(ctx context.Context, mergedID, survivingID string) ([]MergeCount, error)
+	// MergeUsers moves the merged account's records to the surviving one, closes
+	// the merged account with change and fills in merge.Records. It fails with
+	// ErrStatusChanged if either account changed status since it was read.
+	MergeUsers(ctx context.Context, merge *AccountMerge, change *StatusChange) error
+	GetUserPreferences(ctx context.Context, userID string) (json.RawMessage, error)
+	UpdateUserPreferences(ctx context.Context, userID string, preferences *Preferences) error
+	UpdatePerinatalDetails(ctx context.Context, userID string, details *perinatal.Details) error
 }
 
 // Handler defines the interface for user HTTP handlers
@@ -47,7 +98,19 @@ type Handler interface {
 	ListUsers(c echo.Context) error
 	SearchUsers(c echo.Context) error
 	DeactivateUser(c echo.Context) error
+	SuspendUser(c echo.Context) error
+	ReactivateUser(c echo.Context) error
+	ScheduleDeletion(c echo.Context) error
+	GetAccountStatus(c echo.Context) error
+	UpdateUserRole(c echo.Context) error
+	RequestEmailChange(c echo.Context) error
+	ConfirmEmailChange(c echo.Context) error
+	PreviewMerge(c echo.Context) error
+	MergeUsers(c echo.Context) error
 	UpdateLastLogin(c echo.Context) error
 	GetUserPreferences(c echo.Context) error
 	UpdateUserPreferences(c echo.Context) error
+	GetPreferencesSchema(c echo.Context) error
+	GetPerinatal(c echo.Context) error
+	UpdatePerinatal(c echo.Context) error
 }
@@ -0,0 +1,486 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/perinatal"
+)
+
+// memoryStore keeps users in memory for tests and demo mode. Domain events are
+// not published, so leaving the active state doesn't cascade to other modules.
+type memoryStore struct {
+	db           *memdb.DB
+	mu           sync.RWMutex
+	changes      []StatusChange
+	emailChanges map[string]EmailChange
+}
+
+func NewMemoryStore(db *memdb.DB) Store {
+	return &memoryStore{db: db, emailChanges: make(map[string]EmailChange)}
+}
+
+// CreateUser creates a new user with an empty profile
+func (s *memoryStore) CreateUser(ctx context.Context, req *CreateUserRequest) (*User, error) {
+	now := time.Now()
+	row := memdb.User{
+		ID:            uuid.New().String(),
+		Email:         req.Email,
+		FullName:      req.FullName,
+		Role:          string(req.Role),
+		IsActive:      true,
+		AccountStatus: string(StatusActive),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	err := s.db.InsertUser(row, memdb.Profile{CreatedAt: now, UpdatedAt: now})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return userFromRow(row), nil
+}
+
+// GetUserByID retrieves an active user by their ID
+func (s *memoryStore) GetUserByID(ctx context.Context, userID string) (*User, error) {
+	row, ok := s.db.User(userID)
+	if !ok || !row.IsActive {
+		return nil, ErrUserNotFound
+	}
+	return userFromRow(row), nil
+}
+
+// GetUserByEmail retrieves an active user by their email
+func (s *memoryStore) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	row, ok := s.db.UserByEmail(email)
+	if !ok || !row.IsActive {
+		return nil, ErrUserNotFound
+	}
+	return userFromRow(row), nil
+}
+
+// GetUserProfile retrieves a user's profile information
+func (s *memoryStore) GetUserProfile(ctx context.Context, userID string) (*UserProfile, error) {
+	row, ok := s.db.Profile(userID)
+	if !ok {
+		return nil, fmt.Errorf("user profile not found")
+	}
+	return profileFromRow(row), nil
+}
+
+// UpdateUser updates user information
+func (s *memoryStore) UpdateUser(ctx context.Context, userID string, req *UpdateUserRequest) (*User, error) {
+	if req.FullName == nil {
+		return s.GetUserByID(ctx, userID)
+	}
+
+	row, ok := s.db.UpdateUser(userID, func(row *memdb.User) bool {
+		if !row.IsActive {
+			return false
+		}
+		row.FullName = *req.FullName
+		row.UpdatedAt = time.Now()
+		return true
+	})
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+
+	return userFromRow(row), nil
+}
+
+// UpdateUserProfile updates user profile information
+func (s *memoryStore) UpdateUserProfile(ctx context.Context, userID string, req *UpdateUserRequest) (*UserProfile, error) {
+	if req.PhoneNumber == nil && req.Address == nil {
+		return s.GetUserProfile(ctx, userID)
+	}
+
+	row, ok := s.db.UpdateProfile(userID, func(row *memdb.Profile) {
+		if req.PhoneNumber != nil {
+			row.PhoneNumber = req.PhoneNumber
+		}
+		if req.Address != nil {
+			row.Address = req.Address
+		}
+		row.UpdatedAt = time.Now()
+	})
+	if !ok {
+		return nil, fmt.Errorf("user profile not found")
+	}
+
+	return profileFromRow(row), nil
+}
+
+// ListUsers retrieves a paginated list of active users, newest first. Demo
+// mode has no organisations, so a restricted scope matches nobody.
+func (s *memoryStore) ListUsers(ctx context.Context, scope organisations.Scope, page, pageSize int, role *UserRole, status *AccountStatus) (*ListUsersResponse, error) {
+	var matched []memdb.User
+	for _, row := range s.db.Users() {
+		inStatus := row.IsActive
+		if status != nil {
+			inStatus = row.AccountStatus == string(*status)
+		}
+		if inStatus && !row.IsGuest && (role == nil || row.Role == string(*role)) && scope.All {
+			matched = append(matched, row)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].CreatedAt.After(matched[j].CreatedAt)
+	})
+
+	total := int64(len(matched))
+	var users []UserResponse
+	for i := (page - 1) * pageSize; i < len(matched) && len(users) < pageSize; i++ {
+		user := userFromRow(matched[i])
+		users = append(users, UserResponse{
+			ID:          user.ID,
+			Email:       user.Email,
+			FullName:    user.FullName,
+			Role:        user.Role,
+			IsActive:    user.IsActive,
+			Status:      user.Status,
+			LastLoginAt: user.LastLoginAt,
+			CreatedAt:   user.CreatedAt,
+		})
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+
+	return &ListUsersResponse{
+		Users:      users,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// UserInScope reports whether the user is in scope. Demo mode has no
+// organisations, so a restricted scope matches nobody.
+func (s *memoryStore) UserInScope(ctx context.Context, scope organisations.Scope, userID string) (bool, error) {
+	return scope.All, nil
+}
+
+// SearchUsers searches active users by name or email
+func (s *memoryStore) SearchUsers(ctx context.Context, query string, limit int, role *UserRole, serviceUserIDs []string) ([]User, error) {
+	query = strings.ToLower(query)
+
+	var users []User
+	for _, row := range s.db.Users() {
+		if !row.IsActive || row.IsGuest || (role != nil && row.Role != string(*role)) {
+			continue
+		}
+		if serviceUserIDs != nil && row.Role == string(RoleServiceUser) && !slices.Contains(serviceUserIDs, row.ID) {
+			continue
+		}
+		if strings.Contains(strings.ToLower(row.FullName), query) || strings.Contains(strings.ToLower(row.Email), query) {
+			users = append(users, *userFromRow(row))
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].FullName < users[j].FullName
+	})
+	if len(users) > limit {
+		users = users[:limit]
+	}
+
+	return users, nil
+}
+
+// UpdateLastLogin updates the user's last login time
+func (s *memoryStore) UpdateLastLogin(ctx context.Context, userID string) error {
+	now := time.Now()
+	s.db.UpdateUser(userID, func(row *memdb.User) bool {
+		if !row.IsActive {
+			return false
+		}
+		row.LastLoginAt = &now
+		row.UpdatedAt = now
+		return true
+	})
+	return nil
+}
+
+// GetAccountStatus returns an account's status whatever state it is in
+func (s *memoryStore) GetAccountStatus(ctx context.Context, userID string) (*AccountStatusResponse, error) {
+	row, ok := s.db.User(userID)
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+
+	return &AccountStatusResponse{
+		UserID:              row.ID,
+		Role:                UserRole(row.Role),
+		Status:              AccountStatus(row.AccountStatus),
+		DeletionScheduledAt: row.DeletionScheduledAt,
+	}, nil
+}
+
+// UpdateAccountStatus moves an account to a new status and records the change.
+// Leaving the active state revokes the account's tokens.
+func (s *memoryStore) UpdateAccountStatus(ctx context.Context, change *StatusChange, deletionScheduledAt *time.Time) error {
+	return s.transition(change, func(row *memdb.User) {
+		if change.FromStatus == StatusActive {
+			row.TokensRevokedAt = &change.CreatedAt
+		}
+		row.DeletionScheduledAt = deletionScheduledAt
+	})
+}
+
+// AnonymiseUser erases an account that is pending deletion. Only the shared user
+// and profile rows are scrubbed; journey data lives in its own store.
+func (s *memoryStore) AnonymiseUser(ctx context.Context, change *StatusChange) error {
+	err := s.transition(change, func(row *memdb.User) {
+		row.Email = anonymisedEmail(row.ID)
+		row.FullName = AnonymisedName
+		row.PasswordHash = ""
+		row.TokensRevokedAt = &change.CreatedAt
+		row.DeletionScheduledAt = nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.db.UpdateProfile(change.UserID, func(row *memdb.Profile) {
+		*row = memdb.Profile{UserID: row.UserID, CreatedAt: row.CreatedAt, UpdatedAt: change.CreatedAt}
+	})
+
+	return nil
+}
+
+// ListStatusChanges lists an account's status changes, oldest first
+func (s *memoryStore) ListStatusChanges(ctx context.Context, userID string) ([]StatusChange, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	changes := []StatusChange{}
+	for _, change := range s.changes {
+		if change.UserID == userID {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes, nil
+}
+
+// UpdateUserRole changes an active user's role and revokes their tokens
+func (s *memoryStore) UpdateUserRole(ctx context.Context, userID string, role UserRole) (*User, error) {
+	now := time.Now()
+	row, ok := s.db.UpdateUser(userID, func(row *memdb.User) bool {
+		if !row.IsActive {
+			return false
+		}
+		row.Role = string(role)
+		row.TokensRevokedAt = &now
+		row.UpdatedAt = now
+		return true
+	})
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return userFromRow(row), nil
+}
+
+// SaveEmailChange creates or replaces the pending email change for a user
+func (s *memoryStore) SaveEmailChange(ctx context.Context, change *EmailChange) error {
+	s.mu.Lock()
+	s.emailChanges[change.UserID] = *change
+	s.mu.Unlock()
+
+	return nil
+}
+
+// ConfirmEmailChange moves an active user to the new address of the pending
+// change with the token hash and revokes their tokens
+func (s *memoryStore) ConfirmEmailChange(ctx context.Context, tokenHash string, now time.Time) (*User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var change *EmailChange
+	for _, pending := range s.emailChanges {
+		if pending.TokenHash == tokenHash && pending.ExpiresAt.After(now) {
+			change = &pending
+			break
+		}
+	}
+	if change == nil {
+		return nil, ErrInvalidEmailToken
+	}
+
+	if existing, ok := s.db.UserByEmail(change.NewEmail); ok && existing.ID != change.UserID {
+		return nil, ErrEmailTaken
+	}
+	row, ok := s.db.UpdateUser(change.UserID, func(row *memdb.User) bool {
+		if !row.IsActive {
+			return false
+		}
+		row.Email = change.NewEmail
+		row.TokensRevokedAt = &now
+		row.UpdatedAt = now
+		return true
+	})
+	if !ok {
+		return nil, ErrInvalidEmailToken
+	}
+	delete(s.emailChanges, change.UserID)
+
+	return userFromRow(row), nil
+}
+
+// CountMergeRecords counts nothing: journal, referral, group and feedback data
+// live in their own stores in demo mode
+func (s *memoryStore) CountMergeRecords(ctx context.Context, mergedID, survivingID string) ([]MergeCount, error) {
+	return []MergeCount{}, nil
+}
+
+// MergeUsers closes the merged account and records the change. As with
+// CountMergeRecords, no records move between the in-memory stores.
+func (s *memoryStore) MergeUsers(ctx context.Context, merge *AccountMerge, change *StatusChange) error {
+	if surviving, ok := s.db.User(merge.SurvivingUserID); !ok || surviving.AccountStatus != string(StatusActive) {
+		return ErrStatusChanged
+	}
+
+	err := s.transition(change, func(row *memdb.User) {
+		row.Email = mergedEmail(row.ID)
+		row.PasswordHash = ""
+		row.TokensRevokedAt = &change.CreatedAt
+		row.DeletionScheduledAt = nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.emailChanges, merge.MergedUserID)
+	s.mu.Unlock()
+
+	merge.Records = []MergeCount{}
+	return nil
+}
+
+// GetUserPreferences retrieves the stored preferences document from the profile
+func (s *memoryStore) GetUserPreferences(ctx context.Context, userID string) (json.RawMessage, error) {
+	row, ok := s.db.Profile(userID)
+	if !ok || row.Preferences == nil {
+		return nil, nil
+	}
+
+	return json.RawMessage(*row.Preferences), nil
+}
+
+// UpdateUserPreferences replaces user preferences in the profile
+func (s *memoryStore) UpdateUserPreferences(ctx context.Context, userID string, preferences *Preferences) error {
+	preferencesJSON, err := json.Marshal(preferences)
+	if err != nil {
+		return fmt.Errorf("failed to marshal preferences: %w", err)
+	}
+
+	encoded := string(preferencesJSON)
+	_, ok := s.db.UpdateProfile(userID, func(row *memdb.Profile) {
+		row.Preferences = &encoded
+		row.UpdatedAt = time.Now()
+	})
+	if !ok {
+		return fmt.Errorf("user profile not found")
+	}
+
+	return nil
+}
+
+// UpdatePerinatalDetails replaces the perinatal details on a user's profile; nil clears them
+func (s *memoryStore) UpdatePerinatalDetails(ctx context.Context, userID string, details *perinatal.Details) error {
+	var encoded *string
+	if details != nil {
+		detailsJSON, err := json.Marshal(details)
+		if err != nil {
+			return fmt.Errorf("failed to marshal perinatal details: %w", err)
+		}
+		value := string(detailsJSON)
+		encoded = &value
+	}
+
+	_, ok := s.db.UpdateProfile(userID, func(row *memdb.Profile) {
+		row.PerinatalDetails = encoded
+		row.UpdatedAt = time.Now()
+	})
+	if !ok {
+		return fmt.Errorf("user profile not found")
+	}
+
+	return nil
+}
+
+// Helper functions
+
+// transition applies a status change to the user row if it still has the status
+// the change was planned from, then records the change
+func (s *memoryStore) transition(change *StatusChange, update func(*memdb.User)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.db.User(change.UserID); !ok {
+		return ErrUserNotFound
+	}
+
+	_, ok := s.db.UpdateUser(change.UserID, func(row *memdb.User) bool {
+		if row.AccountStatus != string(change.FromStatus) {
+			return false
+		}
+		update(row)
+		row.AccountStatus = string(change.ToStatus)
+		row.IsActive = change.ToStatus == StatusActive
+		row.UpdatedAt = change.CreatedAt
+		return true
+	})
+	if !ok {
+		return ErrStatusChanged
+	}
+
+	s.changes = append(s.changes, *change)
+
+	return nil
+}
+
+func userFromRow(row memdb.User) *User {
+	return &User{
+		ID:          row.ID,
+		Email:       row.Email,
+		FullName:    row.FullName,
+		Role:        UserRole(row.Role),
+		IsActive:    row.IsActive,
+		Status:      AccountStatus(row.AccountStatus),
+		LastLoginAt: row.LastLoginAt,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+}
+
+func profileFromRow(row memdb.Profile) *UserProfile {
+	profile := &UserProfile{
+		UserID:           row.UserID,
+		PhoneNumber:      row.PhoneNumber,
+		DateOfBirth:      row.DateOfBirth,
+		Address:          row.Address,
+		EmergencyContact: row.EmergencyContact,
+		Preferences:      row.Preferences,
+		CreatedAt:        row.CreatedAt,
+		UpdatedAt:        row.UpdatedAt,
+	}
+	if row.PerinatalDetails != nil {
+		var details perinatal.Details
+		if err := json.Unmarshal([]byte(*row.PerinatalDetails), &details); err == nil {
+			profile.PerinatalDetails = &details
+		}
+	}
+	return profile
+}
@@ -1,7 +1,11 @@
 package user
 
 import (
+	"errors"
+	"slices"
 	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/perinatal"
 )
 
 // UserRole represents the different roles in the system
@@ -14,28 +18,163 @@ const (
 	RoleProfessional UserRole = "professional"
 )
 
+// AccountStatus is where an account is in its lifecycle. Only active accounts
+// can sign in; is_active is kept in step with it.
+type AccountStatus string
+
+const (
+	StatusActive          AccountStatus = "active"
+	StatusSuspended       AccountStatus = "suspended"
+	StatusPendingDeletion AccountStatus = "pending_deletion"
+	StatusDeleted         AccountStatus = "deleted"
+)
+
+// accountTransitions lists the statuses each status can move to. Deleted is
+// final, and only the erasure job moves an account to it.
+var accountTransitions = map[AccountStatus][]AccountStatus{
+	StatusActive:          {StatusSuspended, StatusPendingDeletion},
+	StatusSuspended:       {StatusActive, StatusPendingDeletion},
+	StatusPendingDeletion: {StatusActive, StatusDeleted},
+}
+
+// CanTransitionTo reports whether an account may move from s to the given status
+func (s AccountStatus) CanTransitionTo(to AccountStatus) bool {
+	return slices.Contains(accountTransitions[s], to)
+}
+
+// DeletionGracePeriod is how long an account stays pending deletion before it
+// is anonymised. Staff can reactivate it until then.
+const DeletionGracePeriod = 30 * 24 * time.Hour
+
+// Background job type and the queue it runs on. Erasure shares the privacy
+// queue with data exports.
+const (
+	JobQueue            = "privacy"
+	JobAnonymiseAccount = "user.anonymise_account"
+)
+
+// JobSendEmailChange issues an email change token and emails it to the new
+// address. It runs on the default queue.
+const JobSendEmailChange = "user.send_email_change"
+
+// ErasureReason is recorded when the erasure job anonymises an account
+const ErasureReason = "Deletion grace period ended"
+
+// DeactivationReason is recorded when an account is deactivated without a reason
+const DeactivationReason = "Account deactivated"
+
+// AnonymisedName replaces the name of an erased account
+const AnonymisedName = "Deleted user"
+
+// EmailChangeTTL is how long the new address has to confirm an email change
+const EmailChangeTTL = 72 * time.Hour
+
+// Kinds of record a merge moves to the surviving account
+const (
+	MergeJourneyEntries    = "journey_entries"
+	MergeJourneyGoals      = "journey_goals"
+	MergeJourneyMilestones = "journey_milestones"
+	MergeReferralsSent     = "referrals_sent"
+	MergeReferralsReceived = "referrals_received"
+	MergeGroupMemberships  = "group_memberships"
+	MergeFeedback          = "feedback"
+)
+
+var (
+	// ErrUserNotFound is returned when an account doesn't exist
+	ErrUserNotFound = errors.New("user not found")
+
+	// ErrInvalidTransition is returned when an account can't move from its
+	// current status to the requested one
+	ErrInvalidTransition = errors.New("invalid account status transition")
+
+	// ErrStatusChanged is returned when an account changed status between
+	// being read and being saved
+	ErrStatusChanged = errors.New("account status was changed by someone else; reload and try again")
+
+	// ErrOwnAccount is returned when staff try to change the role or status of,
+	// or merge, their own account
+	ErrOwnAccount = errors.New("staff can't change the role or status of, or merge, their own account")
+
+	// ErrHigherRole is returned when staff try to suspend or erase an account
+	// whose role outranks their own
+	ErrHigherRole = errors.New("staff can't suspend or erase an account with a higher role than their own")
+
+	// ErrEmailTaken is returned when an email change would clash with another account
+	ErrEmailTaken = errors.New("email address is already registered")
+
+	// ErrInvalidEmailToken is returned for unknown and expired email change tokens
+	ErrInvalidEmailToken = errors.New("email change link is invalid or has expired")
+
+	// ErrInvalidMerge is returned when two accounts can't be merged
+	ErrInvalidMerge = errors.New("accounts can't be merged")
+)
+
 // User represents a user in the system
 type User struct {
-	ID          string     `json:"id" db:"id"`
-	Email       string     `json:"email" db:"email"`
-	FullName    string     `json:"full_name" db:"full_name"`
-	Role        UserRole   `json:"role" db:"role"`
-	IsActive    bool       `json:"is_active" db:"is_active"`
-	LastLoginAt *time.Time `json:"last_login_at,omitempty" db:"last_login_at"`
-	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	ID          string        `json:"id" db:"id"`
+	Email       string        `json:"email" db:"email"`
+	FullName    string        `json:"full_name" db:"full_name"`
+	Role        UserRole      `json:"role" db:"role"`
+	IsActive    bool          `json:"is_active" db:"is_active"`
+	Status      AccountStatus `json:"status" db:"account_status"`
+	LastLoginAt *time.Time    `json:"last_login_at,omitempty" db:"last_login_at"`
+	CreatedAt   time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// StatusChange records an account moving from one status to another
+type StatusChange struct {
+	ID         string        `json:"id" db:"id"`
+	UserID     string        `json:"user_id" db:"user_id"`
+	FromStatus AccountStatus `json:"from_status" db:"from_status"`
+	ToStatus   AccountStatus `json:"to_status" db:"to_status"`
+	Reason     string        `json:"reason" db:"reason" encrypt:"true"`
+	ActorID    *string       `json:"actor_id,omitempty" db:"actor_id"` // nil for scheduled jobs and the admin CLI
+	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
+}
+
+// EmailChange is a staff-initiated email change waiting for the new address to
+// confirm it. Only a hash of the token is stored.
+type EmailChange struct {
+	UserID      string    `json:"user_id" db:"user_id"`
+	NewEmail    string    `json:"new_email" db:"new_email"`
+	TokenHash   string    `json:"-" db:"token_hash"`
+	RequestedBy *string   `json:"requested_by,omitempty" db:"requested_by"`
+	ExpiresAt   time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// AccountMerge records a duplicate account being merged into another
+type AccountMerge struct {
+	ID              string       `json:"id" db:"id"`
+	MergedUserID    string       `json:"merged_user_id" db:"merged_user_id"`
+	SurvivingUserID string       `json:"surviving_user_id" db:"surviving_user_id"`
+	ActorID         *string      `json:"actor_id,omitempty" db:"actor_id"`
+	Records         []MergeCount `json:"records" db:"records"`
+	CreatedAt       time.Time    `json:"created_at" db:"created_at"`
+}
+
+// MergeCount is how many records of one kind move to the surviving account.
+// Records the surviving account already has an equivalent of, such as a
+// journal entry for the same day, are dropped instead.
+type MergeCount struct {
+	Kind    string `json:"kind"`
+	Moved   int    `json:"moved"`
+	Dropped int    `json:"dropped"`
 }
 
 // UserProfile represents extended user profile information
 type UserProfile struct {
-	UserID           string     `json:"user_id" db:"user_id"`
-	PhoneNumber      *string    `json:"phone_number,omitempty" db:"phone_number"`
-	DateOfBirth      *time.Time `json:"date_of_birth,omitempty" db:"date_of_birth"`
-	Address          *string    `json:"address,omitempty" db:"address"`
-	EmergencyContact *string    `json:"emergency_contact,omitempty" db:"emergency_contact"`
-	Preferences      *string    `json:"preferences,omitempty" db:"preferences"` // JSON string
-	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+	UserID           string             `json:"user_id" db:"user_id"`
+	PhoneNumber      *string            `json:"phone_number,omitempty" db:"phone_number" encrypt:"true"`
+	DateOfBirth      *time.Time         `json:"date_of_birth,omitempty" db:"date_of_birth"` // Sealed by the store as YYYY-MM-DD
+	Address          *string            `json:"address,omitempty" db:"address" encrypt:"true"`
+	EmergencyContact *string            `json:"emergency_contact,omitempty" db:"emergency_contact" encrypt:"true"` // Free text, superseded by emergencycontacts
+	Preferences      *string            `json:"preferences,omitempty" db:"preferences"`                            // JSON document, see Preferences
+	PerinatalDetails *perinatal.Details `json:"perinatal_details,omitempty" db:"perinatal_details"`                // Sealed by the store as JSON
+	CreatedAt        time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at" db:"updated_at"`
 }
 
 // CreateUserRequest represents the request to create a new user
@@ -62,23 +201,25 @@ type ChangePasswordRequest struct {
 
 // UserResponse represents the user data returned in API responses
 type UserResponse struct {
-	ID          string     `json:"id"`
-	Email       string     `json:"email"`
-	FullName    string     `json:"full_name"`
-	Role        UserRole   `json:"role"`
-	IsActive    bool       `json:"is_active"`
-	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
+	ID          string        `json:"id"`
+	Email       string        `json:"email"`
+	FullName    string        `json:"full_name"`
+	Role        UserRole      `json:"role"`
+	IsActive    bool          `json:"is_active"`
+	Status      AccountStatus `json:"status"`
+	LastLoginAt *time.Time    `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
 }
 
 // UserProfileResponse represents the user profile data returned in API responses
 type UserProfileResponse struct {
-	User             UserResponse `json:"user"`
-	PhoneNumber      *string      `json:"phone_number,omitempty"`
-	DateOfBirth      *time.Time   `json:"date_of_birth,omitempty"`
-	Address          *string      `json:"address,omitempty"`
-	EmergencyContact *string      `json:"emergency_contact,omitempty"`
-	Preferences      interface{}  `json:"preferences,omitempty"`
+	User             UserResponse       `json:"user"`
+	PhoneNumber      *string            `json:"phone_number,omitempty"`
+	DateOfBirth      *time.Time         `json:"date_of_birth,omitempty"`
+	Address          *string            `json:"address,omitempty"`
+	EmergencyContact *string            `json:"emergency_contact,omitempty"`
+	Preferences      *Preferences       `json:"preferences,omitempty"`
+	Perinatal        *PerinatalResponse `json:"perinatal,omitempty"`
 }
 
 // ListUsersResponse represents the response for listing users
@@ -89,3 +230,86 @@ type ListUsersResponse struct {
 	PageSize   int            `json:"page_size"`
 	TotalPages int            `json:"total_pages"`
 }
+
+// PerinatalResponse represents a user's pregnancy and birth details and the
+// stage they put the user at today
+type PerinatalResponse struct {
+	Details  perinatal.Details  `json:"details"`
+	Progress perinatal.Progress `json:"progress"`
+}
+
+// AccountStatusRequest represents staff changing an account's status
+type AccountStatusRequest struct {
+	Reason string `json:"reason" validate:"required,min=3,max=1000"`
+}
+
+// AccountStatusResponse represents an account's status and how it got there
+type AccountStatusResponse struct {
+	UserID              string         `json:"user_id"`
+	Role                UserRole       `json:"role"`
+	Status              AccountStatus  `json:"status"`
+	DeletionScheduledAt *time.Time     `json:"deletion_scheduled_at,omitempty"`
+	History             []StatusChange `json:"history"`
+}
+
+// ChangeRoleRequest represents staff changing a user's role
+type ChangeRoleRequest struct {
+	Role UserRole `json:"role" validate:"required"`
+}
+
+// ChangeEmailRequest represents staff changing a user's email address
+type ChangeEmailRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// EmailChangeResponse represents a requested email change. The token that
+// confirms it is only ever emailed to the new address.
+type EmailChangeResponse struct {
+	UserID    string    `json:"user_id"`
+	NewEmail  string    `json:"new_email"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ConfirmEmailChangeRequest represents the new address confirming an email change
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// MergeUsersRequest represents staff merging the account in the path into another
+type MergeUsersRequest struct {
+	Into   string `json:"into" validate:"required,uuid"`
+	Reason string `json:"reason" validate:"required,min=3,max=1000"`
+}
+
+// MergePreviewResponse represents what merging one account into another would move
+type MergePreviewResponse struct {
+	Merged    UserResponse `json:"merged"`
+	Surviving UserResponse `json:"surviving"`
+	Records   []MergeCount `json:"records"`
+}
+
+// AnonymiseAccountJob is the payload for a queued erasure
+type AnonymiseAccountJob struct {
+	UserID string `json:"user_id"`
+}
+
+// SendEmailChangeJob is the payload for emailing an email change. The token is
+// generated when the job runs, so it is never stored in the queue.
+type SendEmailChangeJob struct {
+	UserID      string    `json:"user_id"`
+	OldEmail    string    `json:"old_email"`
+	NewEmail    string    `json:"new_email"`
+	RequestedBy *string   `json:"requested_by,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// anonymisedEmail is a unique, undeliverable address for an erased account
+func anonymisedEmail(userID string) string {
+	return "deleted-" + userID + "@deleted.invalid"
+}
+
+// mergedEmail is a unique, undeliverable address for an account merged into
+// another, which frees its old address
+func mergedEmail(userID string) string {
+	return "merged-" + userID + "@merged.invalid"
+}
@@ -0,0 +1,280 @@
+package user
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata" // The runtime image has no zoneinfo, and timezones are checked with time.LoadLocation
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// PreferencesVersion is the version of the preferences document this server
+// writes. Bump it when the schema changes incompatibly and add a migration
+// from the previous version to preferencesMigrations.
+const PreferencesVersion = 1
+
+// Content topics users can hide
+const (
+	TopicPregnancyLoss   = "pregnancy_loss"
+	TopicBirthTrauma     = "birth_trauma"
+	TopicNeonatalCare    = "neonatal_care"
+	TopicSelfHarm        = "self_harm"
+	TopicSuicide         = "suicide"
+	TopicDomesticAbuse   = "domestic_abuse"
+	TopicEatingDisorders = "eating_disorders"
+)
+
+var ErrInvalidPreferences = errors.New("invalid preferences")
+
+//go:embed preferences.schema.json
+var preferencesSchemaJSON []byte
+
+var preferencesSchema = compilePreferencesSchema()
+
+// Preferences is the versioned preferences document stored for each user
+type Preferences struct {
+	Version        int                      `json:"version" validate:"required"`
+	Notifications  NotificationPreferences  `json:"notifications"`
+	ReminderTime   *string                  `json:"reminder_time"` // HH:MM local time; nil turns the reminder off
+	Language       string                   `json:"language"`
+	Timezone       string                   `json:"timezone"` // IANA name
+	ContentFilters ContentFilters           `json:"content_filters"`
+	Accessibility  AccessibilityPreferences `json:"accessibility"`
+}
+
+// NotificationPreferences controls how and when the user is contacted
+type NotificationPreferences struct {
+	Channels   NotificationChannels `json:"channels"`
+	QuietHours *QuietHours          `json:"quiet_hours"`
+}
+
+// NotificationChannels turns each delivery channel on or off
+type NotificationChannels struct {
+	Push  bool `json:"push"`
+	Email bool `json:"email"`
+	SMS   bool `json:"sms"`
+}
+
+// QuietHours is a local time window, possibly spanning midnight, in which
+// nothing is sent
+type QuietHours struct {
+	Start string `json:"start" validate:"required"`
+	End   string `json:"end" validate:"required"`
+}
+
+// ContentFilters hides content the user finds distressing
+type ContentFilters struct {
+	HiddenTopics []string `json:"hidden_topics" validate:"dive,oneof=pregnancy_loss birth_trauma neonatal_care self_harm suicide domestic_abuse eating_disorders"`
+}
+
+// AccessibilityPreferences adjusts how the app is presented
+type AccessibilityPreferences struct {
+	TextSize          string `json:"text_size" validate:"oneof=small default large extra_large"`
+	HighContrast      bool   `json:"high_contrast"`
+	ReduceMotion      bool   `json:"reduce_motion"`
+	ScreenReaderHints bool   `json:"screen_reader_hints"`
+}
+
+// DefaultPreferences returns the preferences of a user who hasn't changed any
+func DefaultPreferences() *Preferences {
+	return &Preferences{
+		Version: PreferencesVersion,
+		Notifications: NotificationPreferences{
+			Channels: NotificationChannels{Push: true, Email: true},
+		},
+		Language:       "en-GB",
+		Timezone:       "Europe/London",
+		ContentFilters: ContentFilters{HiddenTopics: []string{}},
+		Accessibility:  AccessibilityPreferences{TextSize: "default"},
+	}
+}
+
+// PreferencesSchema returns the JSON Schema preferences documents are validated against
+func PreferencesSchema() json.RawMessage {
+	return preferencesSchemaJSON
+}
+
+// ParsePreferences reads a stored preferences document, migrating documents
+// written against older versions of the schema. An empty document gives the
+// defaults.
+func ParsePreferences(stored []byte) (*Preferences, error) {
+	if len(bytes.TrimSpace(stored)) == 0 {
+		return DefaultPreferences(), nil
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(stored))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse preferences JSON: %w", err)
+	}
+	fields, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: document must be an object", ErrInvalidPreferences)
+	}
+
+	version, err := documentVersion(fields)
+	if err != nil {
+		return nil, err
+	}
+	if version > PreferencesVersion {
+		return nil, fmt.Errorf("%w: version %d is newer than this server supports", ErrInvalidPreferences, version)
+	}
+	for ; version < PreferencesVersion; version++ {
+		fields = preferencesMigrations[version](fields)
+	}
+
+	return applyPreferences(fields)
+}
+
+// DecodePreferences validates a preferences document sent by a client. It
+// must be written against the current version of the schema; settings it
+// leaves out take their default.
+func DecodePreferences(document []byte) (*Preferences, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(document))
+	if err != nil {
+		return nil, fmt.Errorf("%w: body must be a JSON document", ErrInvalidPreferences)
+	}
+	fields, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: document must be an object", ErrInvalidPreferences)
+	}
+
+	version, err := documentVersion(fields)
+	if err != nil {
+		return nil, err
+	}
+	if version != PreferencesVersion {
+		return nil, fmt.Errorf("%w: version must be %d", ErrInvalidPreferences, PreferencesVersion)
+	}
+
+	return applyPreferences(fields)
+}
+
+// preferencesMigrations[n] upgrades a version n document to version n+1
+var preferencesMigrations = []func(map[string]interface{}) map[string]interface{}{
+	migratePreferencesV0,
+}
+
+// migratePreferencesV0 upgrades the free-form documents written before
+// preferences were versioned. Settings clients are known to have used are
+// carried over; anything else is dropped.
+func migratePreferencesV0(legacy map[string]interface{}) map[string]interface{} {
+	doc := map[string]interface{}{"version": json.Number("1")}
+	for _, key := range []string{"language", "timezone", "reminder_time"} {
+		if value, ok := legacy[key].(string); ok {
+			doc[key] = value
+		}
+	}
+
+	channels := map[string]interface{}{}
+	if enabled, ok := legacy["notifications_enabled"].(bool); ok {
+		channels["push"], channels["email"] = enabled, enabled
+	}
+	if enabled, ok := legacy["notifications"].(bool); ok {
+		channels["push"], channels["email"] = enabled, enabled
+	}
+	for legacyKey, channel := range map[string]string{
+		"push_notifications":  "push",
+		"email_notifications": "email",
+		"sms_notifications":   "sms",
+	} {
+		if enabled, ok := legacy[legacyKey].(bool); ok {
+			channels[channel] = enabled
+		}
+	}
+	if len(channels) > 0 {
+		doc["notifications"] = map[string]interface{}{"channels": channels}
+	}
+
+	return doc
+}
+
+// Helper functions
+
+func compilePreferencesSchema() *jsonschema.Schema {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(preferencesSchemaJSON))
+	if err != nil {
+		panic(fmt.Sprintf("preferences schema is not valid JSON: %v", err))
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("preferences.schema.json", doc); err != nil {
+		panic(fmt.Sprintf("failed to load preferences schema: %v", err))
+	}
+	return compiler.MustCompile("preferences.schema.json")
+}
+
+// documentVersion reads the version of a document; unversioned documents are version 0
+func documentVersion(fields map[string]interface{}) (int, error) {
+	raw, ok := fields["version"]
+	if !ok {
+		return 0, nil
+	}
+
+	number, ok := raw.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("%w: version must be a number", ErrInvalidPreferences)
+	}
+	version, err := number.Int64()
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("%w: version must be a positive integer", ErrInvalidPreferences)
+	}
+	return int(version), nil
+}
+
+// applyPreferences validates a current-version document and lays it over the defaults
+func applyPreferences(fields map[string]interface{}) (*Preferences, error) {
+	if err := preferencesSchema.Validate(fields); err != nil {
+		var validationErr *jsonschema.ValidationError
+		if errors.As(err, &validationErr) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPreferences, describeValidationError(validationErr))
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPreferences, err)
+	}
+
+	encoded, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode preferences: %w", err)
+	}
+	preferences := DefaultPreferences()
+	if err := json.Unmarshal(encoded, preferences); err != nil {
+		return nil, fmt.Errorf("failed to decode preferences: %w", err)
+	}
+	if preferences.ContentFilters.HiddenTopics == nil {
+		preferences.ContentFilters.HiddenTopics = []string{}
+	}
+
+	// The schema can't know which zones exist
+	if _, err := time.LoadLocation(preferences.Timezone); err != nil || preferences.Timezone == "Local" {
+		return nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalidPreferences, preferences.Timezone)
+	}
+
+	return preferences, nil
+}
+
+// describeValidationError lists where a document failed the schema, one
+// problem per location, e.g. "/accessibility/text_size: value must be one of ..."
+func describeValidationError(err *jsonschema.ValidationError) string {
+	var problems []string
+	seen := make(map[string]bool)
+	for _, unit := range err.BasicOutput().Errors {
+		if unit.Error == nil || seen[unit.InstanceLocation] {
+			continue
+		}
+		seen[unit.InstanceLocation] = true
+
+		location := unit.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		problems = append(problems, location+": "+unit.Error.String())
+	}
+	if len(problems) == 0 {
+		return err.Error()
+	}
+	return strings.Join(problems, "; ")
+}
@@ -0,0 +1,111 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+)
+
+func TestDefaultPreferencesMatchSchema(t *testing.T) {
+	encoded, err := json.Marshal(DefaultPreferences())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := DecodePreferences(encoded)
+	if err != nil {
+		t.Fatalf("DecodePreferences(defaults) error = %v", err)
+	}
+	if decoded.Language != "en-GB" || !decoded.Notifications.Channels.Push || decoded.Notifications.Channels.SMS {
+		t.Errorf("DecodePreferences(defaults) = %+v", decoded)
+	}
+}
+
+func TestDecodePreferences(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr string
+	}{
+		{"version only", `{"version": 1}`, ""},
+		{"full document", `{"version": 1, "notifications": {"channels": {"push": false, "email": true, "sms": true}, "quiet_hours": {"start": "22:00", "end": "07:30"}}, "reminder_time": "19:00", "language": "cy", "timezone": "Europe/London", "content_filters": {"hidden_topics": ["pregnancy_loss"]}, "accessibility": {"text_size": "large", "high_contrast": true}}`, ""},
+		{"missing version", `{"language": "en-GB"}`, "version must be 1"},
+		{"future version", `{"version": 2}`, "version must be 1"},
+		{"unknown setting", `{"version": 1, "theme": "dark"}`, "/"},
+		{"bad reminder time", `{"version": 1, "reminder_time": "7pm"}`, "/reminder_time"},
+		{"quiet hours without an end", `{"version": 1, "notifications": {"quiet_hours": {"start": "22:00"}}}`, "/notifications/quiet_hours"},
+		{"unknown topic", `{"version": 1, "content_filters": {"hidden_topics": ["spiders"]}}`, "/content_filters/hidden_topics/0"},
+		{"unknown timezone", `{"version": 1, "timezone": "Mars/Olympus_Mons"}`, "unknown timezone"},
+		{"not an object", `[1]`, "must be an object"},
+		{"not JSON", `version=1`, "JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodePreferences([]byte(tt.doc))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("DecodePreferences() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidPreferences) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("DecodePreferences() error = %v, want ErrInvalidPreferences mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParsePreferencesMigratesLegacyDocuments(t *testing.T) {
+	preferences, err := ParsePreferences([]byte(`{"language": "cy", "notifications_enabled": false, "sms_notifications": true, "theme": "dark"}`))
+	if err != nil {
+		t.Fatalf("ParsePreferences() error = %v", err)
+	}
+
+	if preferences.Version != PreferencesVersion || preferences.Language != "cy" || preferences.Timezone != "Europe/London" {
+		t.Errorf("ParsePreferences() = %+v, want current version in Welsh with the default timezone", preferences)
+	}
+	if channels := preferences.Notifications.Channels; channels.Push || channels.Email || !channels.SMS {
+		t.Errorf("channels = %+v, want only SMS", channels)
+	}
+
+	if defaults, err := ParsePreferences(nil); err != nil || defaults.Accessibility.TextSize != "default" {
+		t.Errorf("ParsePreferences(nil) = %+v, %v; want defaults", defaults, err)
+	}
+}
+
+func TestUpdateUserPreferences(t *testing.T) {
+	ctx := context.Background()
+	svc, db, _ := newTestService(t)
+
+	// Unreadable documents give the defaults rather than an error
+	broken := `{"version": 1, "language": "not a language"}`
+	db.UpdateProfile(parent, func(row *memdb.Profile) { row.Preferences = &broken })
+	if preferences, err := svc.GetUserPreferences(ctx, parent); err != nil || preferences.Language != "en-GB" {
+		t.Fatalf("GetUserPreferences() = %+v, %v; want defaults", preferences, err)
+	}
+
+	if _, err := svc.UpdateUserPreferences(ctx, parent, json.RawMessage(`{"version": 1, "language": "English"}`)); !errors.Is(err, ErrInvalidPreferences) {
+		t.Fatalf("UpdateUserPreferences() error = %v, want ErrInvalidPreferences", err)
+	}
+
+	updated, err := svc.UpdateUserPreferences(ctx, parent, json.RawMessage(`{"version": 1, "reminder_time": "20:15", "accessibility": {"reduce_motion": true}}`))
+	if err != nil {
+		t.Fatalf("UpdateUserPreferences() error = %v", err)
+	}
+	if updated.ReminderTime == nil || *updated.ReminderTime != "20:15" || !updated.Accessibility.ReduceMotion || updated.Accessibility.TextSize != "default" {
+		t.Errorf("UpdateUserPreferences() = %+v, want the update laid over the defaults", updated)
+	}
+
+	profile, err := svc.GetUserProfile(ctx, parent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Preferences == nil || profile.Preferences.ReminderTime == nil || *profile.Preferences.ReminderTime != "20:15" {
+		t.Errorf("GetUserProfile() preferences = %+v, want the stored document", profile.Preferences)
+	}
+}
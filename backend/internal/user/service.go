@@ -2,16 +2,39 @@ package user
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	netmail "net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/perinatal-mental-health-app/backend/internal/careteam"
+	"github.com/perinatal-mental-health-app/backend/internal/jobs"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"github.com/perinatal-mental-health-app/backend/internal/mail"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/perinatal"
+	"go.uber.org/zap"
 )
 
 type service struct {
-	store Store
+	store    Store
+	queue    jobs.Enqueuer
+	careTeam careteam.Service
+	mailer   mail.Sender
 }
 
-func NewService(store Store) Service {
+func NewService(store Store, queue jobs.Enqueuer, careTeam careteam.Service, sender mail.Sender) Service {
 	return &service{
-		store: store,
+		store:    store,
+		queue:    queue,
+		careTeam: careTeam,
+		mailer:   sender,
 	}
 }
 
@@ -39,6 +62,7 @@ func (s *service) CreateUser(ctx context.Context, req *CreateUserRequest) (*User
 		FullName:    user.FullName,
 		Role:        user.Role,
 		IsActive:    user.IsActive,
+		Status:      user.Status,
 		LastLoginAt: user.LastLoginAt,
 		CreatedAt:   user.CreatedAt,
 	}, nil
@@ -57,6 +81,7 @@ func (s *service) GetUser(ctx context.Context, userID string) (*UserResponse, er
 		FullName:    user.FullName,
 		Role:        user.Role,
 		IsActive:    user.IsActive,
+		Status:      user.Status,
 		LastLoginAt: user.LastLoginAt,
 		CreatedAt:   user.CreatedAt,
 	}, nil
@@ -74,9 +99,15 @@ func (s *service) GetUserProfile(ctx context.Context, userID string) (*UserProfi
 		return nil, err
 	}
 
-	var preferences interface{}
+	var stored []byte
 	if profile.Preferences != nil {
-		preferences = *profile.Preferences
+		stored = []byte(*profile.Preferences)
+	}
+	preferences := s.parseStoredPreferences(userID, stored)
+
+	var perinatalResponse *PerinatalResponse
+	if profile.PerinatalDetails != nil {
+		perinatalResponse = newPerinatalResponse(profile.PerinatalDetails, time.Now())
 	}
 
 	return &UserProfileResponse{
@@ -86,6 +117,7 @@ func (s *service) GetUserProfile(ctx context.Context, userID string) (*UserProfi
 			FullName:    user.FullName,
 			Role:        user.Role,
 			IsActive:    user.IsActive,
+			Status:      user.Status,
 			LastLoginAt: user.LastLoginAt,
 			CreatedAt:   user.CreatedAt,
 		},
@@ -94,11 +126,16 @@ func (s *service) GetUserProfile(ctx context.Context, userID string) (*UserProfi
 		Address:          profile.Address,
 		EmergencyContact: profile.EmergencyContact,
 		Preferences:      preferences,
+		Perinatal:        perinatalResponse,
 	}, nil
 }
 
 // UpdateUser updates user information
-func (s *service) UpdateUser(ctx context.Context, userID string, req *UpdateUserRequest) (*UserResponse, error) {
+func (s *service) UpdateUser(ctx context.Context, scope organisations.Scope, userID string, req *UpdateUserRequest) (*UserResponse, error) {
+	if err := s.checkInScope(ctx, scope, userID); err != nil {
+		return nil, err
+	}
+
 	user, err := s.store.UpdateUser(ctx, userID, req)
 	if err != nil {
 		return nil, err
@@ -118,13 +155,15 @@ func (s *service) UpdateUser(ctx context.Context, userID string, req *UpdateUser
 		FullName:    user.FullName,
 		Role:        user.Role,
 		IsActive:    user.IsActive,
+		Status:      user.Status,
 		LastLoginAt: user.LastLoginAt,
 		CreatedAt:   user.CreatedAt,
 	}, nil
 }
 
-// ListUsers retrieves a paginated list of users
-func (s *service) ListUsers(ctx context.Context, page, pageSize int, role *UserRole) (*ListUsersResponse, error) {
+// ListUsers retrieves a paginated list of the users within the scope. Only active
+// accounts are listed unless a status is given.
+func (s *service) ListUsers(ctx context.Context, scope organisations.Scope, page, pageSize int, role *UserRole, status *AccountStatus) (*ListUsersResponse, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -135,12 +174,16 @@ func (s *service) ListUsers(ctx context.Context, page, pageSize int, role *UserR
 	if role != nil && !isValidRole(*role) {
 		return nil, fmt.Errorf("invalid role filter: %s", *role)
 	}
+	if status != nil && !isValidStatus(*status) {
+		return nil, fmt.Errorf("invalid status filter: %s", *status)
+	}
 
-	return s.store.ListUsers(ctx, page, pageSize, role)
+	return s.store.ListUsers(ctx, scope, page, pageSize, role, status)
 }
 
-// SearchUsers searches for users based on query
-func (s *service) SearchUsers(ctx context.Context, query string, limit int, role *UserRole) ([]UserResponse, error) {
+// SearchUsers searches for users based on query. Service users are only found
+// by members of their care team.
+func (s *service) SearchUsers(ctx context.Context, searcherID, query string, limit int, role *UserRole) ([]UserResponse, error) {
 	if limit < 1 || limit > 100 {
 		limit = 20
 	}
@@ -149,7 +192,12 @@ func (s *service) SearchUsers(ctx context.Context, query string, limit int, role
 		return nil, fmt.Errorf("invalid role filter: %s", *role)
 	}
 
-	users, err := s.store.SearchUsers(ctx, query, limit, role)
+	serviceUserIDs, err := s.careTeam.ServiceUserIDs(ctx, searcherID)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.store.SearchUsers(ctx, query, limit, role, append([]string{}, serviceUserIDs...))
 	if err != nil {
 		return nil, err
 	}
@@ -162,6 +210,7 @@ func (s *service) SearchUsers(ctx context.Context, query string, limit int, role
 			FullName:    user.FullName,
 			Role:        user.Role,
 			IsActive:    user.IsActive,
+			Status:      user.Status,
 			LastLoginAt: user.LastLoginAt,
 			CreatedAt:   user.CreatedAt,
 		})
@@ -183,6 +232,7 @@ func (s *service) GetUserByEmail(ctx context.Context, email string) (*UserRespon
 		FullName:    user.FullName,
 		Role:        user.Role,
 		IsActive:    user.IsActive,
+		Status:      user.Status,
 		LastLoginAt: user.LastLoginAt,
 		CreatedAt:   user.CreatedAt,
 	}, nil
@@ -193,22 +243,533 @@ func (s *service) UpdateLastLogin(ctx context.Context, userID string) error {
 	return s.store.UpdateLastLogin(ctx, userID)
 }
 
-// DeactivateUser deactivates a user account
-func (s *service) DeactivateUser(ctx context.Context, userID string) error {
-	return s.store.DeactivateUser(ctx, userID)
+// SuspendUser suspends an account. Its tokens are revoked, and it leaves its
+// groups and has its outstanding referrals cancelled.
+func (s *service) SuspendUser(ctx context.Context, scope organisations.Scope, userID, actorID string, req *AccountStatusRequest) (*AccountStatusResponse, error) {
+	return s.changeStatus(ctx, scope, userID, actorID, StatusSuspended, req.Reason, nil)
+}
+
+// ReactivateUser returns a suspended account, or one pending deletion, to
+// active. Group memberships and cancelled referrals are not restored.
+func (s *service) ReactivateUser(ctx context.Context, scope organisations.Scope, userID, actorID string, req *AccountStatusRequest) (*AccountStatusResponse, error) {
+	return s.changeStatus(ctx, scope, userID, actorID, StatusActive, req.Reason, nil)
+}
+
+// ScheduleDeletion marks an account pending deletion and queues its erasure
+// for when the grace period ends. It can be reactivated until then.
+func (s *service) ScheduleDeletion(ctx context.Context, scope organisations.Scope, userID, actorID string, req *AccountStatusRequest) (*AccountStatusResponse, error) {
+	deleteAt := time.Now().Add(DeletionGracePeriod)
+	status, err := s.changeStatus(ctx, scope, userID, actorID, StatusPendingDeletion, req.Reason, &deleteAt)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.queue.Enqueue(ctx, JobAnonymiseAccount, AnonymiseAccountJob{UserID: userID}, &jobs.EnqueueOptions{
+		Queue: JobQueue,
+		RunAt: deleteAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("account is pending deletion but erasure could not be scheduled: %w", err)
+	}
+
+	return status, nil
+}
+
+// GetAccountStatus returns an account's status and its history of changes
+func (s *service) GetAccountStatus(ctx context.Context, scope organisations.Scope, userID string) (*AccountStatusResponse, error) {
+	if err := s.checkInScope(ctx, scope, userID); err != nil {
+		return nil, err
+	}
+
+	status, err := s.store.GetAccountStatus(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status.History, err = s.store.ListStatusChanges(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// AnonymiseAccount is the job handler that erases an account once its grace
+// period has ended. Accounts that were reactivated, or rescheduled for later,
+// are left alone.
+func (s *service) AnonymiseAccount(ctx context.Context, job AnonymiseAccountJob) error {
+	status, err := s.store.GetAccountStatus(ctx, job.UserID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if status.Status != StatusPendingDeletion || status.DeletionScheduledAt == nil || status.DeletionScheduledAt.After(now) {
+		return nil
+	}
+
+	err = s.store.AnonymiseUser(ctx, &StatusChange{
+		ID:         uuid.New().String(),
+		UserID:     job.UserID,
+		FromStatus: StatusPendingDeletion,
+		ToStatus:   StatusDeleted,
+		Reason:     ErasureReason,
+		CreatedAt:  now,
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Anonymised account after deletion grace period", zap.String("user_id", job.UserID))
+	return nil
+}
+
+// UpdateUserRole changes an active user's role and revokes their tokens, which
+// carry the old role
+func (s *service) UpdateUserRole(ctx context.Context, scope organisations.Scope, userID, actorID string, role UserRole) (*UserResponse, error) {
+	if !isValidRole(role) {
+		return nil, fmt.Errorf("invalid role: %s", role)
+	}
+	if actorID != "" && actorID == userID {
+		return nil, ErrOwnAccount
+	}
+	if err := s.checkInScope(ctx, scope, userID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.store.UpdateUserRole(ctx, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserResponse{
+		ID:          user.ID,
+		Email:       user.Email,
+		FullName:    user.FullName,
+		Role:        user.Role,
+		IsActive:    user.IsActive,
+		Status:      user.Status,
+		LastLoginAt: user.LastLoginAt,
+		CreatedAt:   user.CreatedAt,
+	}, nil
+}
+
+// RequestEmailChange starts changing an active user's email address. The
+// address only changes once it is confirmed with a token that a queued job
+// emails to the new address; the old address is told about the change.
+func (s *service) RequestEmailChange(ctx context.Context, scope organisations.Scope, userID, actorID string, req *ChangeEmailRequest) (*EmailChangeResponse, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if address, err := netmail.ParseAddress(email); err != nil || address.Address != email {
+		return nil, fmt.Errorf("invalid email address")
+	}
+	if err := s.checkInScope(ctx, scope, userID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.store.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Email == email {
+		return nil, fmt.Errorf("user already has email %s", email)
+	}
+	if _, err := s.store.GetUserByEmail(ctx, email); err == nil {
+		return nil, ErrEmailTaken
+	}
+
+	job := SendEmailChangeJob{
+		UserID:    userID,
+		OldEmail:  user.Email,
+		NewEmail:  email,
+		ExpiresAt: time.Now().Add(EmailChangeTTL),
+	}
+	if actorID != "" {
+		job.RequestedBy = &actorID
+	}
+
+	if _, err := s.queue.Enqueue(ctx, JobSendEmailChange, job, nil); err != nil {
+		return nil, fmt.Errorf("failed to queue email change: %w", err)
+	}
+
+	return &EmailChangeResponse{
+		UserID:    userID,
+		NewEmail:  email,
+		ExpiresAt: job.ExpiresAt,
+	}, nil
+}
+
+// SendEmailChange is the job handler that issues the token for a requested
+// email change and emails it to the new address, then tells the old address.
+// A retry issues a new token, replacing the one from the failed attempt.
+func (s *service) SendEmailChange(ctx context.Context, job SendEmailChangeJob) error {
+	now := time.Now()
+	if !job.ExpiresAt.After(now) {
+		logger.Info("Skipped expired email change", zap.String("user_id", job.UserID))
+		return nil
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return fmt.Errorf("failed to generate email change token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	err := s.store.SaveEmailChange(ctx, &EmailChange{
+		UserID:      job.UserID,
+		NewEmail:    job.NewEmail,
+		TokenHash:   hashToken(token),
+		RequestedBy: job.RequestedBy,
+		ExpiresAt:   job.ExpiresAt,
+		CreatedAt:   now,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = s.mailer.Send(ctx, mail.Message{
+		To:      job.NewEmail,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf("Your care team asked to change the email address on your account to this one. "+
+			"Confirm the change with this code before %s:\n\n%s", job.ExpiresAt.Format(time.RFC1123), token),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to email new address: %w", err)
+	}
+
+	err = s.mailer.Send(ctx, mail.Message{
+		To:      job.OldEmail,
+		Subject: "Your email address is being changed",
+		Body: fmt.Sprintf("Your care team asked to change the email address on your account to %s. "+
+			"If you weren't expecting this, contact them before it is confirmed.", job.NewEmail),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to email old address: %w", err)
+	}
+
+	logger.Info("Sent email change confirmation", zap.String("user_id", job.UserID))
+	return nil
+}
+
+// ConfirmEmailChange applies a pending email change with the token sent to the
+// new address. The user signs in again with the new address.
+func (s *service) ConfirmEmailChange(ctx context.Context, req *ConfirmEmailChangeRequest) (*UserResponse, error) {
+	token := strings.TrimSpace(req.Token)
+	if token == "" {
+		return nil, ErrInvalidEmailToken
+	}
+
+	user, err := s.store.ConfirmEmailChange(ctx, hashToken(token), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Confirmed email change", zap.String("user_id", user.ID))
+	response := newUserResponse(user)
+	return &response, nil
+}
+
+// PreviewMerge counts what merging one account into another would move,
+// without changing anything
+func (s *service) PreviewMerge(ctx context.Context, scope organisations.Scope, mergedID, survivingID, actorID string) (*MergePreviewResponse, error) {
+	merged, surviving, err := s.mergeAccounts(ctx, scope, mergedID, survivingID, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.store.CountMergeRecords(ctx, merged.ID, surviving.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MergePreviewResponse{
+		Merged:    newUserResponse(merged),
+		Surviving: newUserResponse(surviving),
+		Records:   records,
+	}, nil
+}
+
+// MergeUsers merges a duplicate account into the one that survives. Journal
+// entries, goals, milestones, referrals, group memberships and feedback move
+// to the surviving account, and the merged account is closed: it can't sign
+// in, and its email address is freed. Its status history records the merge.
+func (s *service) MergeUsers(ctx context.Context, scope organisations.Scope, mergedID, actorID string, req *MergeUsersRequest) (*AccountMerge, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if len(reason) < 3 || len(reason) > 1000 {
+		return nil, fmt.Errorf("reason must be between 3 and 1000 characters")
+	}
+
+	merged, surviving, err := s.mergeAccounts(ctx, scope, mergedID, req.Into, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	merge := &AccountMerge{
+		ID:              uuid.New().String(),
+		MergedUserID:    merged.ID,
+		SurvivingUserID: surviving.ID,
+		CreatedAt:       now,
+	}
+	// Merging is the one way other than erasure for an account to be deleted
+	change := &StatusChange{
+		ID:         uuid.New().String(),
+		UserID:     merged.ID,
+		FromStatus: merged.Status,
+		ToStatus:   StatusDeleted,
+		Reason:     fmt.Sprintf("Merged into account %s: %s", surviving.ID, reason),
+		CreatedAt:  now,
+	}
+	if actorID != "" {
+		merge.ActorID = &actorID
+		change.ActorID = &actorID
+	}
+
+	if err := s.store.MergeUsers(ctx, merge, change); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Merged accounts", zap.String("merged_user_id", merged.ID), zap.String("surviving_user_id", surviving.ID))
+	return merge, nil
+}
+
+// GetUserPreferences retrieves user preferences, migrated to the current version
+func (s *service) GetUserPreferences(ctx context.Context, userID string) (*Preferences, error) {
+	stored, err := s.store.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.parseStoredPreferences(userID, stored), nil
+}
+
+// UpdateUserPreferences validates a preferences document against the schema
+// and replaces the user's preferences with it
+func (s *service) UpdateUserPreferences(ctx context.Context, userID string, document json.RawMessage) (*Preferences, error) {
+	preferences, err := DecodePreferences(document)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.store.UpdateUserPreferences(ctx, userID, preferences); err != nil {
+		return nil, err
+	}
+
+	return preferences, nil
+}
+
+// GetPerinatal retrieves the user's perinatal details and current stage
+func (s *service) GetPerinatal(ctx context.Context, userID string) (*PerinatalResponse, error) {
+	profile, err := s.store.GetUserProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPerinatalResponse(profile.PerinatalDetails, time.Now()), nil
+}
+
+// UpdatePerinatal validates and replaces the user's perinatal details
+func (s *service) UpdatePerinatal(ctx context.Context, userID string, details *perinatal.Details) (*PerinatalResponse, error) {
+	now := time.Now()
+	normalised, err := perinatal.Normalise(details, now)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := normalised
+	if normalised.DueDate == nil && len(normalised.BirthDates) == 0 && normalised.LossDate == nil && normalised.BabyCount == 0 {
+		stored = nil
+	}
+	if err := s.store.UpdatePerinatalDetails(ctx, userID, stored); err != nil {
+		return nil, err
+	}
+
+	return newPerinatalResponse(stored, now), nil
 }
 
-// GetUserPreferences retrieves user preferences
-func (s *service) GetUserPreferences(ctx context.Context, userID string) (map[string]interface{}, error) {
-	return s.store.GetUserPreferences(ctx, userID)
+// GetPerinatalStage computes the user's stage today; users who haven't shared
+// any details are at perinatal.StageNone
+func (s *service) GetPerinatalStage(ctx context.Context, userID string) (*perinatal.Progress, error) {
+	profile, err := s.store.GetUserProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := perinatal.Compute(profile.PerinatalDetails, time.Now())
+	return &progress, nil
 }
 
-// UpdateUserPreferences updates user preferences
-func (s *service) UpdateUserPreferences(ctx context.Context, userID string, preferences map[string]interface{}) error {
-	return s.store.UpdateUserPreferences(ctx, userID, preferences)
+// Helper functions
+
+func newPerinatalResponse(details *perinatal.Details, now time.Time) *PerinatalResponse {
+	response := &PerinatalResponse{Progress: perinatal.Compute(details, now)}
+	if details != nil {
+		response.Details = *details
+	}
+	return response
+}
+
+// parseStoredPreferences migrates a stored document, falling back to the
+// defaults if it can't be read rather than locking the user out of settings
+func (s *service) parseStoredPreferences(userID string, stored []byte) *Preferences {
+	preferences, err := ParsePreferences(stored)
+	if err != nil {
+		logger.Error("Failed to read stored preferences, using defaults", zap.String("user_id", userID), zap.Error(err))
+		return DefaultPreferences()
+	}
+	return preferences
+}
+
+// changeStatus validates and applies a staff-initiated status change
+func (s *service) changeStatus(ctx context.Context, scope organisations.Scope, userID, actorID string, to AccountStatus, reason string, deletionScheduledAt *time.Time) (*AccountStatusResponse, error) {
+	reason = strings.TrimSpace(reason)
+	if len(reason) < 3 || len(reason) > 1000 {
+		return nil, fmt.Errorf("reason must be between 3 and 1000 characters")
+	}
+	if actorID != "" && actorID == userID {
+		return nil, ErrOwnAccount
+	}
+	if err := s.checkInScope(ctx, scope, userID); err != nil {
+		return nil, err
+	}
+
+	current, err := s.store.GetAccountStatus(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if to != StatusActive {
+		if err := s.checkOutranks(ctx, scope, current, actorID); err != nil {
+			return nil, err
+		}
+	}
+	// Only the erasure job deletes accounts
+	if to == StatusDeleted || !current.Status.CanTransitionTo(to) {
+		return nil, fmt.Errorf("%w: %s account can't become %s", ErrInvalidTransition, current.Status, to)
+	}
+
+	change := &StatusChange{
+		ID:         uuid.New().String(),
+		UserID:     userID,
+		FromStatus: current.Status,
+		ToStatus:   to,
+		Reason:     reason,
+		CreatedAt:  time.Now(),
+	}
+	if actorID != "" {
+		change.ActorID = &actorID
+	}
+
+	if err := s.store.UpdateAccountStatus(ctx, change, deletionScheduledAt); err != nil {
+		return nil, err
+	}
+
+	return s.GetAccountStatus(ctx, scope, userID)
+}
+
+// mergeAccounts reads the two accounts of a merge and checks they can be
+// merged. Both must be active and have the same role, and neither can be the
+// staff member's own.
+func (s *service) mergeAccounts(ctx context.Context, scope organisations.Scope, mergedID, survivingID, actorID string) (*User, *User, error) {
+	if mergedID == survivingID {
+		return nil, nil, fmt.Errorf("%w: an account can't be merged into itself", ErrInvalidMerge)
+	}
+	if actorID != "" && (actorID == mergedID || actorID == survivingID) {
+		return nil, nil, ErrOwnAccount
+	}
+	if err := s.checkInScope(ctx, scope, mergedID, survivingID); err != nil {
+		return nil, nil, err
+	}
+
+	merged, err := s.store.GetUserByID(ctx, mergedID)
+	if err != nil {
+		return nil, nil, err
+	}
+	surviving, err := s.store.GetUserByID(ctx, survivingID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if merged.Role != surviving.Role {
+		return nil, nil, fmt.Errorf("%w: a %s account can't be merged into a %s account", ErrInvalidMerge, merged.Role, surviving.Role)
+	}
+
+	return merged, surviving, nil
+}
+
+// checkOutranks returns ErrHigherRole when the target's role outranks the
+// actor's. Super admins, who include the admin CLI's operator, and changes
+// with no actor can touch any account.
+func (s *service) checkOutranks(ctx context.Context, scope organisations.Scope, target *AccountStatusResponse, actorID string) error {
+	if actorID == "" || scope.IsSuperAdmin {
+		return nil
+	}
+
+	actor, err := s.store.GetAccountStatus(ctx, actorID)
+	if err != nil {
+		return err
+	}
+	if roleRank(target.Role) > roleRank(actor.Role) {
+		return ErrHigherRole
+	}
+
+	return nil
+}
+
+// checkInScope returns organisations.ErrOutOfScope unless every user is in scope
+func (s *service) checkInScope(ctx context.Context, scope organisations.Scope, userIDs ...string) error {
+	for _, userID := range userIDs {
+		inScope, err := s.store.UserInScope(ctx, scope, userID)
+		if err != nil {
+			return err
+		}
+		if !inScope {
+			return organisations.ErrOutOfScope
+		}
+	}
+
+	return nil
+}
+
+func newUserResponse(user *User) UserResponse {
+	return UserResponse{
+		ID:          user.ID,
+		Email:       user.Email,
+		FullName:    user.FullName,
+		Role:        user.Role,
+		IsActive:    user.IsActive,
+		Status:      user.Status,
+		LastLoginAt: user.LastLoginAt,
+		CreatedAt:   user.CreatedAt,
+	}
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// isValidStatus validates account statuses
+func isValidStatus(status AccountStatus) bool {
+	switch status {
+	case StatusActive, StatusSuspended, StatusPendingDeletion, StatusDeleted:
+		return true
+	default:
+		return false
+	}
 }
 
 // Helper function to validate user roles
+// roleRank orders roles by how much they can do; NHS staff outrank other staff,
+// who outrank service users
+func roleRank(role UserRole) int {
+	switch role {
+	case RoleNHSStaff:
+		return 2
+	case RoleProfessional, RoleCharity:
+		return 1
+	default:
+		return 0
+	}
+}
+
 func isValidRole(role UserRole) bool {
 	switch role {
 	case RoleServiceUser, RoleNHSStaff, RoleCharity, RoleProfessional:
@@ -0,0 +1,444 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/careteam"
+	"github.com/perinatal-mental-health-app/backend/internal/jobs"
+	"github.com/perinatal-mental-health-app/backend/internal/logger"
+	"github.com/perinatal-mental-health-app/backend/internal/mail"
+	"github.com/perinatal-mental-health-app/backend/internal/memdb"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/perinatal"
+)
+
+const (
+	parent = "22222222-2222-2222-2222-222222222222"
+	staff  = "44444444-4444-4444-4444-444444444444"
+)
+
+var (
+	allScope   = organisations.Scope{All: true}
+	otherTrust = organisations.Scope{OrganisationIDs: []string{"9e8d7c6b-5a4f-4e3d-8c2b-1a0f9e8d7c6b"}}
+)
+
+// recordingSender keeps the emails a test sends
+type recordingSender struct {
+	sent []mail.Message
+}
+
+func (r *recordingSender) Send(ctx context.Context, message mail.Message) error {
+	r.sent = append(r.sent, message)
+	return nil
+}
+
+func newTestService(t *testing.T) (Service, *memdb.DB, jobs.Service) {
+	t.Helper()
+
+	logger.Init()
+	db := memdb.New()
+	now := time.Now()
+	phone := "07700 900123"
+	for _, u := range []memdb.User{
+		{ID: parent, FullName: "Sam Parent", Email: "parent@example.com", Role: "service_user", IsActive: true},
+		{ID: staff, FullName: "Nia Staff", Email: "staff@example.com", Role: "nhs_staff", IsActive: true},
+	} {
+		u.CreatedAt, u.UpdatedAt = now, now
+		if err := db.InsertUser(u, memdb.Profile{UserID: u.ID, PhoneNumber: &phone}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	queue := jobs.NewService(jobs.NewMemoryStore())
+	return NewService(NewMemoryStore(db), queue, careteam.NewService(careteam.NewMemoryStore(db)), &recordingSender{}), db, queue
+}
+
+func TestSearchUsersOnlyFindsCareTeamServiceUsers(t *testing.T) {
+	ctx := context.Background()
+	_, db, queue := newTestService(t)
+
+	otherParent := "33333333-3333-3333-3333-333333333333"
+	now := time.Now()
+	other := memdb.User{ID: otherParent, FullName: "Alex Parent", Email: "alex@example.com", Role: "service_user", IsActive: true, CreatedAt: now, UpdatedAt: now}
+	if err := db.InsertUser(other, memdb.Profile{UserID: otherParent}); err != nil {
+		t.Fatal(err)
+	}
+
+	careTeam := careteam.NewService(careteam.NewMemoryStore(db))
+	if _, err := careTeam.Assign(ctx, staff, &careteam.AssignRequest{ProfessionalID: staff, ServiceUserID: parent, Reason: "Health visitor"}); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewService(NewMemoryStore(db), queue, careTeam, &recordingSender{})
+
+	found, err := svc.SearchUsers(ctx, staff, "parent", 20, nil)
+	if err != nil {
+		t.Fatalf("SearchUsers() error = %v", err)
+	}
+	if len(found) != 1 || found[0].ID != parent {
+		t.Errorf("SearchUsers() = %+v, want only the service user in the care team", found)
+	}
+
+	// Staff accounts aren't gated by the care team
+	if staffFound, _ := svc.SearchUsers(ctx, staff, "nia", 20, nil); len(staffFound) != 1 {
+		t.Errorf("SearchUsers() for staff = %+v, want the staff account", staffFound)
+	}
+}
+
+func TestStaffChangesStayWithinScope(t *testing.T) {
+	ctx := context.Background()
+	svc, db, _ := newTestService(t)
+
+	renamed := "Sam Renamed"
+	if _, err := svc.UpdateUser(ctx, otherTrust, parent, &UpdateUserRequest{FullName: &renamed}); !errors.Is(err, organisations.ErrOutOfScope) {
+		t.Errorf("UpdateUser() out of scope error = %v, want ErrOutOfScope", err)
+	}
+	if _, err := svc.SuspendUser(ctx, otherTrust, parent, staff, &AccountStatusRequest{Reason: DeactivationReason}); !errors.Is(err, organisations.ErrOutOfScope) {
+		t.Errorf("SuspendUser() out of scope error = %v, want ErrOutOfScope", err)
+	}
+	if row, _ := db.User(parent); row.FullName != "Sam Parent" || !row.IsActive {
+		t.Errorf("row after out-of-scope changes = %+v, want unchanged", row)
+	}
+
+	updated, err := svc.UpdateUser(ctx, allScope, parent, &UpdateUserRequest{FullName: &renamed})
+	if err != nil || updated.FullName != renamed {
+		t.Errorf("UpdateUser() in scope = (%+v, %v), want renamed", updated, err)
+	}
+	if _, err := svc.SuspendUser(ctx, allScope, parent, staff, &AccountStatusRequest{Reason: DeactivationReason}); err != nil {
+		t.Errorf("SuspendUser() in scope error = %v", err)
+	}
+}
+
+func TestStatusChangesCheckTheTarget(t *testing.T) {
+	ctx := context.Background()
+	svc, db, _ := newTestService(t)
+
+	professional := "55555555-5555-5555-5555-555555555555"
+	now := time.Now()
+	row := memdb.User{ID: professional, FullName: "Pat Midwife", Email: "pat@example.com", Role: "professional", IsActive: true, CreatedAt: now, UpdatedAt: now}
+	if err := db.InsertUser(row, memdb.Profile{UserID: professional}); err != nil {
+		t.Fatal(err)
+	}
+	req := &AccountStatusRequest{Reason: "Reported for abuse"}
+
+	if _, err := svc.GetAccountStatus(ctx, otherTrust, parent); !errors.Is(err, organisations.ErrOutOfScope) {
+		t.Errorf("GetAccountStatus() out of scope error = %v, want ErrOutOfScope", err)
+	}
+	if _, err := svc.ScheduleDeletion(ctx, otherTrust, parent, staff, req); !errors.Is(err, organisations.ErrOutOfScope) {
+		t.Errorf("ScheduleDeletion() out of scope error = %v, want ErrOutOfScope", err)
+	}
+	if _, err := svc.SuspendUser(ctx, allScope, staff, staff, req); !errors.Is(err, ErrOwnAccount) {
+		t.Errorf("SuspendUser() of own account error = %v, want ErrOwnAccount", err)
+	}
+	if _, err := svc.SuspendUser(ctx, allScope, staff, professional, req); !errors.Is(err, ErrHigherRole) {
+		t.Errorf("SuspendUser() of a higher role error = %v, want ErrHigherRole", err)
+	}
+	if row, _ := db.User(staff); row.AccountStatus != string(StatusActive) {
+		t.Errorf("staff status = %s, want active", row.AccountStatus)
+	}
+
+	if _, err := svc.SuspendUser(ctx, allScope, professional, staff, req); err != nil {
+		t.Fatalf("SuspendUser() of a lower role error = %v", err)
+	}
+	if _, err := svc.ReactivateUser(ctx, otherTrust, professional, staff, &AccountStatusRequest{Reason: "Report was unfounded"}); !errors.Is(err, organisations.ErrOutOfScope) {
+		t.Errorf("ReactivateUser() out of scope error = %v, want ErrOutOfScope", err)
+	}
+}
+
+func TestSuspendAndReactivate(t *testing.T) {
+	ctx := context.Background()
+	svc, db, _ := newTestService(t)
+
+	if _, err := svc.SuspendUser(ctx, allScope, parent, staff, &AccountStatusRequest{Reason: "no"}); err == nil {
+		t.Error("SuspendUser() with a short reason succeeded, want error")
+	}
+
+	suspended, err := svc.SuspendUser(ctx, allScope, parent, staff, &AccountStatusRequest{Reason: "Reported for abuse"})
+	if err != nil {
+		t.Fatalf("SuspendUser() error = %v", err)
+	}
+	if suspended.Status != StatusSuspended {
+		t.Errorf("SuspendUser() status = %s, want suspended", suspended.Status)
+	}
+	row, _ := db.User(parent)
+	if row.IsActive || row.TokensRevokedAt == nil {
+		t.Errorf("suspended row = %+v, want inactive with tokens revoked", row)
+	}
+	if _, err := svc.GetUser(ctx, parent); err == nil {
+		t.Error("GetUser() found a suspended account")
+	}
+	if _, err := svc.SuspendUser(ctx, allScope, parent, staff, &AccountStatusRequest{Reason: "Reported again"}); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("second SuspendUser() error = %v, want ErrInvalidTransition", err)
+	}
+
+	reactivated, err := svc.ReactivateUser(ctx, allScope, parent, staff, &AccountStatusRequest{Reason: "Report was unfounded"})
+	if err != nil {
+		t.Fatalf("ReactivateUser() error = %v", err)
+	}
+	if reactivated.Status != StatusActive || len(reactivated.History) != 2 {
+		t.Fatalf("ReactivateUser() = %+v, want active with two changes", reactivated)
+	}
+	first := reactivated.History[0]
+	if first.FromStatus != StatusActive || first.ToStatus != StatusSuspended || first.Reason != "Reported for abuse" || first.ActorID == nil || *first.ActorID != staff {
+		t.Errorf("first change = %+v, want active to suspended by staff", first)
+	}
+	if user, err := svc.GetUser(ctx, parent); err != nil || !user.IsActive {
+		t.Errorf("GetUser() after reactivating = %+v, %v; want active user", user, err)
+	}
+
+	if _, err := svc.ReactivateUser(ctx, allScope, "not-a-user", staff, &AccountStatusRequest{Reason: "Mistake"}); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("ReactivateUser() of an unknown user error = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestScheduledDeletion(t *testing.T) {
+	ctx := context.Background()
+	svc, db, queue := newTestService(t)
+
+	pending, err := svc.ScheduleDeletion(ctx, allScope, parent, staff, &AccountStatusRequest{Reason: "Requested by the user"})
+	if err != nil {
+		t.Fatalf("ScheduleDeletion() error = %v", err)
+	}
+	if pending.Status != StatusPendingDeletion || pending.DeletionScheduledAt == nil {
+		t.Fatalf("ScheduleDeletion() = %+v, want pending deletion with a date", pending)
+	}
+
+	queued, err := queue.ListJobs(ctx, &jobs.ListJobsRequest{Page: 1, PageSize: 10, JobType: JobAnonymiseAccount})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queued.Jobs) != 1 || queued.Jobs[0].Queue != JobQueue || !queued.Jobs[0].RunAt.Equal(*pending.DeletionScheduledAt) {
+		t.Fatalf("queued jobs = %+v, want one erasure at %s", queued.Jobs, pending.DeletionScheduledAt)
+	}
+
+	// Before the grace period ends the job leaves the account alone
+	if err := svc.AnonymiseAccount(ctx, AnonymiseAccountJob{UserID: parent}); err != nil {
+		t.Fatalf("AnonymiseAccount() error = %v", err)
+	}
+	if row, _ := db.User(parent); row.AccountStatus != string(StatusPendingDeletion) || row.Email != "parent@example.com" {
+		t.Fatalf("row before grace period ends = %+v, want untouched", row)
+	}
+
+	past := time.Now().Add(-time.Minute)
+	db.UpdateUser(parent, func(row *memdb.User) bool {
+		row.DeletionScheduledAt = &past
+		return true
+	})
+	if err := svc.AnonymiseAccount(ctx, AnonymiseAccountJob{UserID: parent}); err != nil {
+		t.Fatalf("AnonymiseAccount() error = %v", err)
+	}
+
+	row, _ := db.User(parent)
+	if row.AccountStatus != string(StatusDeleted) || row.Email == "parent@example.com" || row.FullName != AnonymisedName || row.PasswordHash != "" {
+		t.Errorf("row after erasure = %+v, want anonymised", row)
+	}
+	if profile, _ := db.Profile(parent); profile.PhoneNumber != nil {
+		t.Errorf("profile after erasure = %+v, want contact details cleared", profile)
+	}
+	if _, err := svc.ReactivateUser(ctx, allScope, parent, staff, &AccountStatusRequest{Reason: "Changed their mind"}); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("ReactivateUser() of a deleted account error = %v, want ErrInvalidTransition", err)
+	}
+
+	status, err := svc.GetAccountStatus(ctx, allScope, parent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := status.History[len(status.History)-1]; last.ToStatus != StatusDeleted || last.Reason != ErasureReason || last.ActorID != nil {
+		t.Errorf("last change = %+v, want erasure by the job", last)
+	}
+}
+
+func TestReactivateCancelsDeletion(t *testing.T) {
+	ctx := context.Background()
+	svc, db, _ := newTestService(t)
+
+	if _, err := svc.ScheduleDeletion(ctx, allScope, parent, staff, &AccountStatusRequest{Reason: "Requested by the user"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.ReactivateUser(ctx, allScope, parent, staff, &AccountStatusRequest{Reason: "Withdrew the request"}); err != nil {
+		t.Fatalf("ReactivateUser() error = %v", err)
+	}
+
+	// The queued job still runs, but finds nothing to do
+	if err := svc.AnonymiseAccount(ctx, AnonymiseAccountJob{UserID: parent}); err != nil {
+		t.Fatalf("AnonymiseAccount() error = %v", err)
+	}
+	row, _ := db.User(parent)
+	if row.AccountStatus != string(StatusActive) || row.DeletionScheduledAt != nil || row.Email != "parent@example.com" {
+		t.Errorf("row = %+v, want active and untouched", row)
+	}
+}
+
+func TestUpdatePerinatal(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestService(t)
+
+	future := time.Now().AddDate(0, 0, 3)
+	if _, err := svc.UpdatePerinatal(ctx, parent, &perinatal.Details{BirthDates: []time.Time{future}}); !errors.Is(err, perinatal.ErrInvalidDetails) {
+		t.Fatalf("UpdatePerinatal() error = %v, want ErrInvalidDetails", err)
+	}
+
+	due := time.Now().AddDate(0, 0, 60)
+	updated, err := svc.UpdatePerinatal(ctx, parent, &perinatal.Details{DueDate: &due, BabyCount: 2})
+	if err != nil {
+		t.Fatalf("UpdatePerinatal() error = %v", err)
+	}
+	if updated.Progress.Stage != perinatal.StageThirdTrimester || !updated.Progress.MultipleBirth {
+		t.Errorf("UpdatePerinatal() progress = %+v, want third trimester with twins", updated.Progress)
+	}
+
+	progress, err := svc.GetPerinatalStage(ctx, parent)
+	if err != nil || progress.Stage != perinatal.StageThirdTrimester {
+		t.Fatalf("GetPerinatalStage() = %+v, %v; want the stored stage", progress, err)
+	}
+	profile, err := svc.GetUserProfile(ctx, parent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Perinatal == nil || profile.Perinatal.Details.DueDate == nil {
+		t.Errorf("GetUserProfile() perinatal = %+v, want the stored details", profile.Perinatal)
+	}
+
+	// Clearing every field forgets the details
+	if _, err := svc.UpdatePerinatal(ctx, parent, &perinatal.Details{}); err != nil {
+		t.Fatal(err)
+	}
+	if progress, _ := svc.GetPerinatalStage(ctx, parent); progress.Stage != perinatal.StageNone {
+		t.Errorf("GetPerinatalStage() after clearing = %s, want none", progress.Stage)
+	}
+}
+
+func TestUpdateUserRoleAndEmail(t *testing.T) {
+	ctx := context.Background()
+	_, db, queue := newTestService(t)
+	sender := &recordingSender{}
+	svc := NewService(NewMemoryStore(db), queue, careteam.NewService(careteam.NewMemoryStore(db)), sender)
+
+	if _, err := svc.UpdateUserRole(ctx, allScope, staff, staff, RoleServiceUser); !errors.Is(err, ErrOwnAccount) {
+		t.Errorf("UpdateUserRole() of own account error = %v, want ErrOwnAccount", err)
+	}
+	if _, err := svc.UpdateUserRole(ctx, otherTrust, parent, staff, RoleProfessional); !errors.Is(err, organisations.ErrOutOfScope) {
+		t.Errorf("UpdateUserRole() out of scope error = %v, want ErrOutOfScope", err)
+	}
+	updated, err := svc.UpdateUserRole(ctx, allScope, parent, staff, RoleProfessional)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row, _ := db.User(parent); updated.Role != RoleProfessional || row.TokensRevokedAt == nil {
+		t.Errorf("UpdateUserRole() = %+v with row %+v, want professional with tokens revoked", updated, row)
+	}
+
+	if _, err := svc.RequestEmailChange(ctx, otherTrust, parent, staff, &ChangeEmailRequest{Email: "sam.new@example.com"}); !errors.Is(err, organisations.ErrOutOfScope) {
+		t.Errorf("RequestEmailChange() out of scope error = %v, want ErrOutOfScope", err)
+	}
+	if _, err := svc.RequestEmailChange(ctx, allScope, parent, staff, &ChangeEmailRequest{Email: "staff@example.com"}); !errors.Is(err, ErrEmailTaken) {
+		t.Errorf("RequestEmailChange() to a used address error = %v, want ErrEmailTaken", err)
+	}
+	change, err := svc.RequestEmailChange(ctx, allScope, parent, staff, &ChangeEmailRequest{Email: " Sam.New@Example.com "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change.NewEmail != "sam.new@example.com" {
+		t.Errorf("RequestEmailChange() = %+v, want the normalised address", change)
+	}
+
+	// The token is only issued by the queued job, which emails both addresses
+	queued, err := queue.ListJobs(ctx, &jobs.ListJobsRequest{Page: 1, PageSize: 10, JobType: JobSendEmailChange})
+	if err != nil || len(queued.Jobs) != 1 {
+		t.Fatalf("ListJobs() = (%v, %v), want one email change job", queued, err)
+	}
+	var job SendEmailChangeJob
+	if err := json.Unmarshal(queued.Jobs[0].Payload, &job); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SendEmailChange(ctx, job); err != nil {
+		t.Fatalf("SendEmailChange() error = %v", err)
+	}
+	if len(sender.sent) != 2 || sender.sent[0].To != "sam.new@example.com" || sender.sent[1].To != "parent@example.com" {
+		t.Fatalf("emails sent = %+v, want the new then the old address", sender.sent)
+	}
+	lines := strings.Split(sender.sent[0].Body, "\n")
+	token := lines[len(lines)-1]
+	if strings.Contains(sender.sent[1].Body, token) {
+		t.Error("the old address was sent the confirmation token")
+	}
+	if row, _ := db.User(parent); row.Email != "parent@example.com" {
+		t.Errorf("email before confirming = %s, want unchanged", row.Email)
+	}
+
+	confirmed, err := svc.ConfirmEmailChange(ctx, &ConfirmEmailChangeRequest{Token: token})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if confirmed.Email != "sam.new@example.com" {
+		t.Errorf("ConfirmEmailChange() email = %s, want the new address", confirmed.Email)
+	}
+	if _, err := svc.ConfirmEmailChange(ctx, &ConfirmEmailChangeRequest{Token: token}); !errors.Is(err, ErrInvalidEmailToken) {
+		t.Errorf("second ConfirmEmailChange() error = %v, want ErrInvalidEmailToken", err)
+	}
+}
+
+func TestMergeUsers(t *testing.T) {
+	ctx := context.Background()
+	svc, db, _ := newTestService(t)
+
+	duplicate := "33333333-3333-3333-3333-333333333333"
+	now := time.Now()
+	err := db.InsertUser(memdb.User{
+		ID: duplicate, FullName: "Sam Parent", Email: "sam.other@example.com", Role: "service_user", IsActive: true,
+		CreatedAt: now, UpdatedAt: now,
+	}, memdb.Profile{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.PreviewMerge(ctx, allScope, duplicate, duplicate, staff); !errors.Is(err, ErrInvalidMerge) {
+		t.Errorf("PreviewMerge() into itself error = %v, want ErrInvalidMerge", err)
+	}
+	if _, err := svc.PreviewMerge(ctx, allScope, duplicate, staff, staff); !errors.Is(err, ErrOwnAccount) {
+		t.Errorf("PreviewMerge() into own account error = %v, want ErrOwnAccount", err)
+	}
+	if _, err := svc.PreviewMerge(ctx, allScope, duplicate, staff, ""); !errors.Is(err, ErrInvalidMerge) {
+		t.Errorf("PreviewMerge() across roles error = %v, want ErrInvalidMerge", err)
+	}
+	if _, err := svc.PreviewMerge(ctx, otherTrust, duplicate, parent, staff); !errors.Is(err, organisations.ErrOutOfScope) {
+		t.Errorf("PreviewMerge() out of scope error = %v, want ErrOutOfScope", err)
+	}
+	preview, err := svc.PreviewMerge(ctx, allScope, duplicate, parent, staff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Merged.ID != duplicate || preview.Surviving.ID != parent {
+		t.Errorf("PreviewMerge() = %+v, want the duplicate merging into the parent", preview)
+	}
+
+	if _, err := svc.MergeUsers(ctx, allScope, duplicate, staff, &MergeUsersRequest{Into: parent, Reason: "no"}); err == nil {
+		t.Error("MergeUsers() with a short reason succeeded, want error")
+	}
+	merge, err := svc.MergeUsers(ctx, allScope, duplicate, staff, &MergeUsersRequest{Into: parent, Reason: "Same person registered twice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merge.MergedUserID != duplicate || merge.SurvivingUserID != parent || merge.ActorID == nil || *merge.ActorID != staff {
+		t.Errorf("MergeUsers() = %+v, want the merge recorded against staff", merge)
+	}
+
+	row, _ := db.User(duplicate)
+	if row.IsActive || row.AccountStatus != string(StatusDeleted) || row.Email == "sam.other@example.com" || row.TokensRevokedAt == nil {
+		t.Errorf("merged row = %+v, want closed with its email freed and tokens revoked", row)
+	}
+	status, err := svc.GetAccountStatus(ctx, allScope, duplicate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.History) != 1 || !strings.Contains(status.History[0].Reason, parent) {
+		t.Errorf("merged account history = %+v, want the merge", status.History)
+	}
+	if _, err := svc.MergeUsers(ctx, allScope, duplicate, staff, &MergeUsersRequest{Into: parent, Reason: "Same person registered twice"}); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("second MergeUsers() error = %v, want ErrUserNotFound", err)
+	}
+}
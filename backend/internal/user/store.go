@@ -3,21 +3,28 @@ package user
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/perinatal-mental-health-app/backend/internal/encryption"
+	"github.com/perinatal-mental-health-app/backend/internal/events"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/perinatal"
 )
 
 type store struct {
-	db *pgxpool.Pool
+	db     *pgxpool.Pool
+	cipher encryption.Cipher
 }
 
-func NewStore(db *pgxpool.Pool) Store {
-	return &store{db: db}
+func NewStore(db *pgxpool.Pool, cipher encryption.Cipher) Store {
+	return &store{db: db, cipher: cipher}
 }
 
 // CreateUser creates a new user in the database
@@ -28,12 +35,18 @@ func (s *store) CreateUser(ctx context.Context, req *CreateUserRequest) (*User,
 	query := `
 		INSERT INTO users (id, email, full_name, role, is_active, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, email, full_name, role, is_active, last_login_at, created_at, updated_at
+		RETURNING id, email, full_name, role, is_active, account_status, last_login_at, created_at, updated_at
 	`
 
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	user := &User{}
-	err := s.db.QueryRow(ctx, query, userID, req.Email, req.FullName, req.Role, true, now, now).
-		Scan(&user.ID, &user.Email, &user.FullName, &user.Role, &user.IsActive,
+	err = tx.QueryRow(ctx, query, userID, req.Email, req.FullName, req.Role, true, now, now).
+		Scan(&user.ID, &user.Email, &user.FullName, &user.Role, &user.IsActive, &user.Status,
 			&user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
@@ -45,30 +58,42 @@ func (s *store) CreateUser(ctx context.Context, req *CreateUserRequest) (*User,
 		INSERT INTO user_profiles (user_id, created_at, updated_at)
 		VALUES ($1, $2, $3)
 	`
-	_, err = s.db.Exec(ctx, profileQuery, userID, now, now)
+	_, err = tx.Exec(ctx, profileQuery, userID, now, now)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user profile: %w", err)
 	}
 
+	err = events.Publish(ctx, tx, events.UserRegistered, user.ID, events.UserRegisteredPayload{
+		UserID: user.ID,
+		Role:   string(user.Role),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return user, nil
 }
 
 // GetUserByID retrieves a user by their ID
 func (s *store) GetUserByID(ctx context.Context, userID string) (*User, error) {
 	query := `
-		SELECT id, email, full_name, role, is_active, last_login_at, created_at, updated_at
+		SELECT id, email, full_name, role, is_active, account_status, last_login_at, created_at, updated_at
 		FROM users
 		WHERE id = $1 AND is_active = true
 	`
 
 	user := &User{}
 	err := s.db.QueryRow(ctx, query, userID).
-		Scan(&user.ID, &user.Email, &user.FullName, &user.Role, &user.IsActive,
+		Scan(&user.ID, &user.Email, &user.FullName, &user.Role, &user.IsActive, &user.Status,
 			&user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
+			return nil, ErrUserNotFound
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
@@ -79,19 +104,19 @@ func (s *store) GetUserByID(ctx context.Context, userID string) (*User, error) {
 // GetUserByEmail retrieves a user by their email
 func (s *store) GetUserByEmail(ctx context.Context, email string) (*User, error) {
 	query := `
-		SELECT id, email, full_name, role, is_active, last_login_at, created_at, updated_at
+		SELECT id, email, full_name, role, is_active, account_status, last_login_at, created_at, updated_at
 		FROM users
 		WHERE email = $1 AND is_active = true
 	`
 
 	user := &User{}
 	err := s.db.QueryRow(ctx, query, email).
-		Scan(&user.ID, &user.Email, &user.FullName, &user.Role, &user.IsActive,
+		Scan(&user.ID, &user.Email, &user.FullName, &user.Role, &user.IsActive, &user.Status,
 			&user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
+			return nil, ErrUserNotFound
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
@@ -103,17 +128,17 @@ func (s *store) GetUserByEmail(ctx context.Context, email string) (*User, error)
 func (s *store) GetUserProfile(ctx context.Context, userID string) (*UserProfile, error) {
 	query := `
 		SELECT user_id, phone_number, date_of_birth, address, emergency_contact, 
-			   preferences, created_at, updated_at
+			   preferences, perinatal_details, created_at, updated_at
 		FROM user_profiles
 		WHERE user_id = $1
 	`
 
 	profile := &UserProfile{}
-	var preferencesJSON *string
+	var preferencesJSON, dateOfBirth, perinatalDetails *string
 
 	err := s.db.QueryRow(ctx, query, userID).
-		Scan(&profile.UserID, &profile.PhoneNumber, &profile.DateOfBirth, &profile.Address,
-			&profile.EmergencyContact, &preferencesJSON, &profile.CreatedAt, &profile.UpdatedAt)
+		Scan(&profile.UserID, &profile.PhoneNumber, &dateOfBirth, &profile.Address,
+			&profile.EmergencyContact, &preferencesJSON, &perinatalDetails, &profile.CreatedAt, &profile.UpdatedAt)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -122,6 +147,10 @@ func (s *store) GetUserProfile(ctx context.Context, userID string) (*UserProfile
 		return nil, fmt.Errorf("failed to get user profile: %w", err)
 	}
 
+	if err := s.decryptProfile(ctx, profile, dateOfBirth, perinatalDetails); err != nil {
+		return nil, err
+	}
+
 	if preferencesJSON != nil {
 		profile.Preferences = preferencesJSON
 	}
@@ -153,19 +182,19 @@ func (s *store) UpdateUser(ctx context.Context, userID string, req *UpdateUserRe
 		UPDATE users 
 		SET %s
 		WHERE id = $%d AND is_active = true
-		RETURNING id, email, full_name, role, is_active, last_login_at, created_at, updated_at
+		RETURNING id, email, full_name, role, is_active, account_status, last_login_at, created_at, updated_at
 	`, strings.Join(setParts, ", "), argIndex)
 
 	args = append(args, userID)
 
 	user := &User{}
 	err := s.db.QueryRow(ctx, query, args...).
-		Scan(&user.ID, &user.Email, &user.FullName, &user.Role, &user.IsActive,
+		Scan(&user.ID, &user.Email, &user.FullName, &user.Role, &user.IsActive, &user.Status,
 			&user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
+			return nil, ErrUserNotFound
 		}
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
@@ -180,14 +209,26 @@ func (s *store) UpdateUserProfile(ctx context.Context, userID string, req *Updat
 	argIndex := 1
 
 	if req.PhoneNumber != nil {
+		phoneNumber, err := s.cipher.Encrypt(ctx, *req.PhoneNumber)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt phone number: %w", err)
+		}
 		setParts = append(setParts, fmt.Sprintf("phone_number = $%d", argIndex))
-		args = append(args, *req.PhoneNumber)
+		args = append(args, phoneNumber)
+		argIndex++
+
+		setParts = append(setParts, fmt.Sprintf("phone_number_bidx = NULLIF($%d, '')", argIndex))
+		args = append(args, s.cipher.BlindIndex(encryption.NormalizePhone(*req.PhoneNumber)))
 		argIndex++
 	}
 
 	if req.Address != nil {
+		address, err := s.cipher.Encrypt(ctx, *req.Address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt address: %w", err)
+		}
 		setParts = append(setParts, fmt.Sprintf("address = $%d", argIndex))
-		args = append(args, *req.Address)
+		args = append(args, address)
 		argIndex++
 	}
 
@@ -204,17 +245,17 @@ func (s *store) UpdateUserProfile(ctx context.Context, userID string, req *Updat
 		SET %s
 		WHERE user_id = $%d
 		RETURNING user_id, phone_number, date_of_birth, address, emergency_contact, 
-				  preferences, created_at, updated_at
+				  preferences, perinatal_details, created_at, updated_at
 	`, strings.Join(setParts, ", "), argIndex)
 
 	args = append(args, userID)
 
 	profile := &UserProfile{}
-	var preferencesJSON *string
+	var preferencesJSON, dateOfBirth, perinatalDetails *string
 
 	err := s.db.QueryRow(ctx, query, args...).
-		Scan(&profile.UserID, &profile.PhoneNumber, &profile.DateOfBirth, &profile.Address,
-			&profile.EmergencyContact, &preferencesJSON, &profile.CreatedAt, &profile.UpdatedAt)
+		Scan(&profile.UserID, &profile.PhoneNumber, &dateOfBirth, &profile.Address,
+			&profile.EmergencyContact, &preferencesJSON, &perinatalDetails, &profile.CreatedAt, &profile.UpdatedAt)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -223,6 +264,10 @@ func (s *store) UpdateUserProfile(ctx context.Context, userID string, req *Updat
 		return nil, fmt.Errorf("failed to update user profile: %w", err)
 	}
 
+	if err := s.decryptProfile(ctx, profile, dateOfBirth, perinatalDetails); err != nil {
+		return nil, err
+	}
+
 	if preferencesJSON != nil {
 		profile.Preferences = preferencesJSON
 	}
@@ -230,8 +275,8 @@ func (s *store) UpdateUserProfile(ctx context.Context, userID string, req *Updat
 	return profile, nil
 }
 
-// ListUsers retrieves a paginated list of users
-func (s *store) ListUsers(ctx context.Context, page, pageSize int, role *UserRole) (*ListUsersResponse, error) {
+// ListUsers retrieves a paginated list of users within the scope
+func (s *store) ListUsers(ctx context.Context, scope organisations.Scope, page, pageSize int, role *UserRole, status *AccountStatus) (*ListUsersResponse, error) {
 	offset := (page - 1) * pageSize
 
 	var whereClause string
@@ -239,6 +284,14 @@ func (s *store) ListUsers(ctx context.Context, page, pageSize int, role *UserRol
 	argIndex := 1
 
 	whereClause = "WHERE is_active = true"
+	if status != nil {
+		whereClause = fmt.Sprintf("WHERE account_status = $%d", argIndex)
+		args = append(args, *status)
+		argIndex++
+	}
+
+	// Guests are not listed until they register
+	whereClause += " AND is_guest = false"
 
 	if role != nil {
 		whereClause += fmt.Sprintf(" AND role = $%d", argIndex)
@@ -246,6 +299,12 @@ func (s *store) ListUsers(ctx context.Context, page, pageSize int, role *UserRol
 		argIndex++
 	}
 
+	if condition, scopeArgs := userScopeCondition(scope, "id", argIndex); condition != "" {
+		whereClause += " AND " + condition
+		args = append(args, scopeArgs...)
+		argIndex += len(scopeArgs)
+	}
+
 	// Count total
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM users %s", whereClause)
 	var total int64
@@ -256,7 +315,7 @@ func (s *store) ListUsers(ctx context.Context, page, pageSize int, role *UserRol
 
 	// Get users
 	query := fmt.Sprintf(`
-		SELECT id, email, full_name, role, is_active, last_login_at, created_at, updated_at
+		SELECT id, email, full_name, role, is_active, account_status, last_login_at, created_at
 		FROM users
 		%s
 		ORDER BY created_at DESC
@@ -274,7 +333,7 @@ func (s *store) ListUsers(ctx context.Context, page, pageSize int, role *UserRol
 	var users []UserResponse
 	for rows.Next() {
 		var user UserResponse
-		err := rows.Scan(&user.ID, &user.Email, &user.FullName, &user.Role, &user.IsActive,
+		err := rows.Scan(&user.ID, &user.Email, &user.FullName, &user.Role, &user.IsActive, &user.Status,
 			&user.LastLoginAt, &user.CreatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
@@ -293,15 +352,33 @@ func (s *store) ListUsers(ctx context.Context, page, pageSize int, role *UserRol
 	}, nil
 }
 
+// UserInScope reports whether the user is staff of one of the scope's
+// organisations or in an open care-team relationship with its staff
+func (s *store) UserInScope(ctx context.Context, scope organisations.Scope, userID string) (bool, error) {
+	condition, args := userScopeCondition(scope, "id", 2)
+	if condition == "" {
+		return true, nil
+	}
+
+	var inScope bool
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM users WHERE id = $1 AND %s)`, condition)
+	err := s.db.QueryRow(ctx, query, append([]interface{}{userID}, args...)...).Scan(&inScope)
+	if err != nil {
+		return false, fmt.Errorf("failed to check user scope: %w", err)
+	}
+
+	return inScope, nil
+}
+
 // SearchUsers searches for users based on query
-func (s *store) SearchUsers(ctx context.Context, query string, limit int, role *UserRole) ([]User, error) {
+func (s *store) SearchUsers(ctx context.Context, query string, limit int, role *UserRole, serviceUserIDs []string) ([]User, error) {
 	var whereClause string
 	var args []interface{}
 	argIndex := 1
 
 	// Build search condition
 	searchPattern := "%" + strings.ToLower(query) + "%"
-	whereClause = "WHERE is_active = true AND (LOWER(full_name) LIKE $1 OR LOWER(email) LIKE $1)"
+	whereClause = "WHERE is_active = true AND is_guest = false AND (LOWER(full_name) LIKE $1 OR LOWER(email) LIKE $1)"
 	args = append(args, searchPattern)
 	argIndex++
 
@@ -311,8 +388,15 @@ func (s *store) SearchUsers(ctx context.Context, query string, limit int, role *
 		argIndex++
 	}
 
+	// Limit service users to those the searcher may see
+	if serviceUserIDs != nil {
+		whereClause += fmt.Sprintf(" AND (role <> 'service_user' OR id = ANY($%d::uuid[]))", argIndex)
+		args = append(args, serviceUserIDs)
+		argIndex++
+	}
+
 	sqlQuery := fmt.Sprintf(`
-		SELECT id, email, full_name, role, is_active, last_login_at, created_at, updated_at
+		SELECT id, email, full_name, role, is_active, account_status, last_login_at, created_at, updated_at
 		FROM users
 		%s
 		ORDER BY full_name ASC
@@ -330,7 +414,7 @@ func (s *store) SearchUsers(ctx context.Context, query string, limit int, role *
 	var users []User
 	for rows.Next() {
 		var user User
-		err := rows.Scan(&user.ID, &user.Email, &user.FullName, &user.Role, &user.IsActive,
+		err := rows.Scan(&user.ID, &user.Email, &user.FullName, &user.Role, &user.IsActive, &user.Status,
 			&user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
@@ -358,24 +442,369 @@ func (s *store) UpdateLastLogin(ctx context.Context, userID string) error {
 	return nil
 }
 
-// DeactivateUser soft deletes a user by setting is_active to false
-func (s *store) DeactivateUser(ctx context.Context, userID string) error {
+// GetAccountStatus returns an account's status whatever state it is in
+func (s *store) GetAccountStatus(ctx context.Context, userID string) (*AccountStatusResponse, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	query := `SELECT id, role, account_status, deletion_scheduled_at FROM users WHERE id = $1`
+
+	status := &AccountStatusResponse{}
+	err := s.db.QueryRow(ctx, query, userID).Scan(&status.UserID, &status.Role, &status.Status, &status.DeletionScheduledAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get account status: %w", err)
+	}
+
+	return status, nil
+}
+
+// UpdateAccountStatus moves an account from change.FromStatus to change.ToStatus
+// and records the change. Leaving the active state revokes the account's tokens
+// and publishes a deactivation event, which ends group memberships and cancels
+// outstanding referrals.
+func (s *store) UpdateAccountStatus(ctx context.Context, change *StatusChange, deletionScheduledAt *time.Time) error {
 	query := `
 		UPDATE users 
-		SET is_active = false, updated_at = $1
-		WHERE id = $2
+		SET account_status = $1, is_active = $2, deletion_scheduled_at = $3,
+		    tokens_revoked_at = CASE WHEN $4 THEN $5 ELSE tokens_revoked_at END,
+		    updated_at = $5
+		WHERE id = $6
 	`
 
-	_, err := s.db.Exec(ctx, query, time.Now(), userID)
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockAccountStatus(ctx, tx, change.UserID, change.FromStatus); err != nil {
+		return err
+	}
+
+	leavingActive := change.FromStatus == StatusActive
+	_, err = tx.Exec(ctx, query, change.ToStatus, change.ToStatus == StatusActive, deletionScheduledAt,
+		leavingActive, change.CreatedAt, change.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to update account status: %w", err)
+	}
+
+	if err := s.insertStatusChange(ctx, tx, change); err != nil {
+		return err
+	}
+
+	if leavingActive {
+		err = events.Publish(ctx, tx, events.UserDeactivated, change.UserID, events.UserDeactivatedPayload{UserID: change.UserID})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// AnonymiseUser erases an account that is pending deletion. Contact details,
+// profile fields, journey records and sessions are removed, feedback is kept
+// but detached, and the users row stays behind with placeholder values so
+// referrals and audit entries still resolve.
+func (s *store) AnonymiseUser(ctx context.Context, change *StatusChange) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockAccountStatus(ctx, tx, change.UserID, change.FromStatus); err != nil {
+		return err
+	}
+
+	// An empty hash never matches a password
+	_, err = tx.Exec(ctx, `
+		UPDATE users 
+		SET email = $1, full_name = $2, password_hash = '', account_status = $3, is_active = false,
+		    deletion_scheduled_at = NULL, tokens_revoked_at = $4, updated_at = $4
+		WHERE id = $5
+	`, anonymisedEmail(change.UserID), AnonymisedName, StatusDeleted, change.CreatedAt, change.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to anonymise user: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE user_profiles 
+		SET phone_number = NULL, phone_number_bidx = NULL, date_of_birth = NULL, address = NULL,
+		    emergency_contact = NULL, preferences = NULL, perinatal_details = NULL, updated_at = $1
+		WHERE user_id = $2
+	`, change.CreatedAt, change.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to clear user profile: %w", err)
+	}
+
+	for _, query := range []string{
+		`DELETE FROM emergency_contacts WHERE user_id = $1`,
+		`DELETE FROM bookmarks WHERE user_id = $1`,
+		`DELETE FROM crisis_plans WHERE user_id = $1`,
+		`DELETE FROM supporter_links WHERE service_user_id = $1 OR supporter_id = $1`,
+		`DELETE FROM journey_milestones WHERE user_id = $1`,
+		`DELETE FROM journey_goals WHERE user_id = $1`,
+		`DELETE FROM journey_entries WHERE user_id = $1`,
+		`DELETE FROM refresh_tokens WHERE user_id = $1`,
+		`DELETE FROM user_sessions WHERE user_id = $1`,
+		`UPDATE feedback SET user_id = NULL, anonymous = true WHERE user_id = $1`,
+	} {
+		if _, err := tx.Exec(ctx, query, change.UserID); err != nil {
+			return fmt.Errorf("failed to erase user data: %w", err)
+		}
+	}
+
+	if err := s.insertStatusChange(ctx, tx, change); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ListStatusChanges lists an account's status changes, oldest first
+func (s *store) ListStatusChanges(ctx context.Context, userID string) ([]StatusChange, error) {
+	query := `
+		SELECT id, user_id, from_status, to_status, reason, actor_id, created_at
+		FROM account_status_changes
+		WHERE user_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := s.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list account status changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []StatusChange{}
+	for rows.Next() {
+		var change StatusChange
+		err := rows.Scan(&change.ID, &change.UserID, &change.FromStatus, &change.ToStatus,
+			&change.Reason, &change.ActorID, &change.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan account status change: %w", err)
+		}
+		if err := s.cipher.DecryptFields(ctx, &change); err != nil {
+			return nil, fmt.Errorf("failed to decrypt account status change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return changes, nil
+}
+
+// UpdateUserRole changes an active user's role and revokes their tokens
+func (s *store) UpdateUserRole(ctx context.Context, userID string, role UserRole) (*User, error) {
+	query := `
+		UPDATE users 
+		SET role = $1, tokens_revoked_at = $2, updated_at = $2
+		WHERE id = $3 AND is_active = true
+		RETURNING id, email, full_name, role, is_active, account_status, last_login_at, created_at, updated_at
+	`
+
+	user := &User{}
+	err := s.db.QueryRow(ctx, query, role, time.Now(), userID).
+		Scan(&user.ID, &user.Email, &user.FullName, &user.Role, &user.IsActive, &user.Status,
+			&user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to update user role: %w", err)
+	}
+
+	return user, nil
+}
+
+// SaveEmailChange creates or replaces the pending email change for a user
+func (s *store) SaveEmailChange(ctx context.Context, change *EmailChange) error {
+	query := `
+		INSERT INTO email_changes (user_id, new_email, token_hash, requested_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE
+		SET new_email = EXCLUDED.new_email, token_hash = EXCLUDED.token_hash, requested_by = EXCLUDED.requested_by,
+		    expires_at = EXCLUDED.expires_at, created_at = EXCLUDED.created_at
+	`
+
+	_, err := s.db.Exec(ctx, query, change.UserID, change.NewEmail, change.TokenHash, change.RequestedBy,
+		change.ExpiresAt, change.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save email change: %w", err)
+	}
+
+	return nil
+}
+
+// ConfirmEmailChange moves an active user to the new address of the pending
+// change with the token hash and revokes their tokens
+func (s *store) ConfirmEmailChange(ctx context.Context, tokenHash string, now time.Time) (*User, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var userID, newEmail string
+	err = tx.QueryRow(ctx, `
+		DELETE FROM email_changes
+		WHERE token_hash = $1 AND expires_at > $2
+		RETURNING user_id, new_email
+	`, tokenHash, now).Scan(&userID, &newEmail)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrInvalidEmailToken
+		}
+		return nil, fmt.Errorf("failed to get email change: %w", err)
+	}
+
+	user := &User{}
+	err = tx.QueryRow(ctx, `
+		UPDATE users 
+		SET email = $1, tokens_revoked_at = $2, updated_at = $2
+		WHERE id = $3 AND is_active = true
+		RETURNING id, email, full_name, role, is_active, account_status, last_login_at, created_at, updated_at
+	`, newEmail, now, userID).
+		Scan(&user.ID, &user.Email, &user.FullName, &user.Role, &user.IsActive, &user.Status,
+			&user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrInvalidEmailToken
+		}
+		if isUniqueViolation(err) {
+			return nil, ErrEmailTaken
+		}
+		return nil, fmt.Errorf("failed to update email: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return user, nil
+}
+
+// CountMergeRecords counts the records merging one account into another would
+// move and drop
+func (s *store) CountMergeRecords(ctx context.Context, mergedID, survivingID string) ([]MergeCount, error) {
+	return countMergeRecords(ctx, s.db, mergedID, survivingID)
+}
+
+// MergeUsers moves the merged account's records to the surviving account in
+// one transaction, closes the merged account and records the merge
+func (s *store) MergeUsers(ctx context.Context, merge *AccountMerge, change *StatusChange) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock in a fixed order so two merges of the same accounts can't deadlock
+	locks := []struct {
+		userID string
+		status AccountStatus
+	}{
+		{merge.MergedUserID, change.FromStatus},
+		{merge.SurvivingUserID, StatusActive},
+	}
+	if locks[1].userID < locks[0].userID {
+		locks[0], locks[1] = locks[1], locks[0]
+	}
+	for _, lock := range locks {
+		if err := lockAccountStatus(ctx, tx, lock.userID, lock.status); err != nil {
+			return err
+		}
+	}
+
+	merge.Records, err = countMergeRecords(ctx, tx, merge.MergedUserID, merge.SurvivingUserID)
+	if err != nil {
+		return err
+	}
+
+	for _, step := range mergeSteps {
+		if step.duplicate != "" {
+			query := fmt.Sprintf(`DELETE FROM %s r WHERE r.%s = $1 AND %s`, step.table, step.column, step.duplicate)
+			if _, err := tx.Exec(ctx, query, merge.MergedUserID, merge.SurvivingUserID); err != nil {
+				return fmt.Errorf("failed to drop duplicate %s: %w", step.kind, err)
+			}
+		}
+
+		query := fmt.Sprintf(`UPDATE %s SET %s = $2 WHERE %s = $1`, step.table, step.column, step.column)
+		if _, err := tx.Exec(ctx, query, merge.MergedUserID, merge.SurvivingUserID); err != nil {
+			return fmt.Errorf("failed to move %s: %w", step.kind, err)
+		}
+	}
+
+	// An empty hash never matches a password
+	_, err = tx.Exec(ctx, `
+		UPDATE users 
+		SET email = $1, password_hash = '', account_status = $2, is_active = false,
+		    deletion_scheduled_at = NULL, tokens_revoked_at = $3, updated_at = $3
+		WHERE id = $4
+	`, mergedEmail(merge.MergedUserID), StatusDeleted, change.CreatedAt, merge.MergedUserID)
 	if err != nil {
-		return fmt.Errorf("failed to deactivate user: %w", err)
+		return fmt.Errorf("failed to close merged account: %w", err)
+	}
+
+	for _, query := range []string{
+		`DELETE FROM email_changes WHERE user_id = $1`,
+		`DELETE FROM refresh_tokens WHERE user_id = $1`,
+		`DELETE FROM user_sessions WHERE user_id = $1`,
+	} {
+		if _, err := tx.Exec(ctx, query, merge.MergedUserID); err != nil {
+			return fmt.Errorf("failed to close merged account: %w", err)
+		}
+	}
+
+	if err := s.insertStatusChange(ctx, tx, change); err != nil {
+		return err
+	}
+
+	records, err := json.Marshal(merge.Records)
+	if err != nil {
+		return fmt.Errorf("failed to marshal merge records: %w", err)
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO account_merges (id, merged_user_id, surviving_user_id, actor_id, records, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, merge.ID, merge.MergedUserID, merge.SurvivingUserID, merge.ActorID, records, merge.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record account merge: %w", err)
+	}
+
+	if change.FromStatus == StatusActive {
+		err = events.Publish(ctx, tx, events.UserDeactivated, merge.MergedUserID, events.UserDeactivatedPayload{UserID: merge.MergedUserID})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
 }
 
-// GetUserPreferences retrieves user preferences from user_profiles table
-func (s *store) GetUserPreferences(ctx context.Context, userID string) (map[string]interface{}, error) {
+// GetUserPreferences retrieves the stored preferences document from user_profiles table
+func (s *store) GetUserPreferences(ctx context.Context, userID string) (json.RawMessage, error) {
 	query := `
 		SELECT preferences
 		FROM user_profiles
@@ -387,25 +816,20 @@ func (s *store) GetUserPreferences(ctx context.Context, userID string) (map[stri
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			// Return empty preferences if profile doesn't exist
-			return map[string]interface{}{}, nil
+			// No document gives the default preferences
+			return nil, nil
 		}
 		return nil, fmt.Errorf("failed to get user preferences: %w", err)
 	}
 
-	preferences := make(map[string]interface{})
-	if preferencesJSON != nil && *preferencesJSON != "" {
-		err = json.Unmarshal([]byte(*preferencesJSON), &preferences)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse preferences JSON: %w", err)
-		}
+	if preferencesJSON == nil {
+		return nil, nil
 	}
-
-	return preferences, nil
+	return json.RawMessage(*preferencesJSON), nil
 }
 
-// UpdateUserPreferences updates user preferences in user_profiles table
-func (s *store) UpdateUserPreferences(ctx context.Context, userID string, preferences map[string]interface{}) error {
+// UpdateUserPreferences replaces user preferences in user_profiles table
+func (s *store) UpdateUserPreferences(ctx context.Context, userID string, preferences *Preferences) error {
 	preferencesJSON, err := json.Marshal(preferences)
 	if err != nil {
 		return fmt.Errorf("failed to marshal preferences: %w", err)
@@ -430,3 +854,185 @@ func (s *store) UpdateUserPreferences(ctx context.Context, userID string, prefer
 
 	return nil
 }
+
+// UpdatePerinatalDetails replaces the perinatal details on a user's profile; nil clears them
+func (s *store) UpdatePerinatalDetails(ctx context.Context, userID string, details *perinatal.Details) error {
+	var sealed *string
+	if details != nil {
+		encoded, err := json.Marshal(details)
+		if err != nil {
+			return fmt.Errorf("failed to marshal perinatal details: %w", err)
+		}
+		encrypted, err := s.cipher.Encrypt(ctx, string(encoded))
+		if err != nil {
+			return fmt.Errorf("failed to encrypt perinatal details: %w", err)
+		}
+		sealed = &encrypted
+	}
+
+	query := `
+		UPDATE user_profiles
+		SET perinatal_details = $1, updated_at = $2
+		WHERE user_id = $3
+	`
+
+	result, err := s.db.Exec(ctx, query, sealed, time.Now(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to update perinatal details: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("user profile not found")
+	}
+
+	return nil
+}
+
+// Helper functions
+
+// userScopeCondition restricts column, a user ID, to staff of the scope's
+// organisations and the service users in an open care-team relationship with
+// them. It returns an empty string for an unrestricted scope.
+func userScopeCondition(scope organisations.Scope, column string, argIndex int) (string, []interface{}) {
+	condition, args := scope.Condition("m.organisation_id", argIndex)
+	if condition == "" {
+		return "", nil
+	}
+
+	return fmt.Sprintf(`(%[1]s IN (SELECT m.user_id FROM organisation_members m WHERE %[2]s)
+		OR %[1]s IN (
+			SELECT c.service_user_id FROM care_team_relationships c
+			JOIN organisation_members m ON m.user_id = c.professional_id
+			WHERE c.status IN ('pending', 'active') AND %[2]s
+		))`, column, condition), args
+}
+
+// insertStatusChange records a status change within the transaction that made it
+func (s *store) insertStatusChange(ctx context.Context, tx pgx.Tx, change *StatusChange) error {
+	reason, err := s.cipher.Encrypt(ctx, change.Reason)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt status change reason: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO account_status_changes (id, user_id, from_status, to_status, reason, actor_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, change.ID, change.UserID, change.FromStatus, change.ToStatus, reason, change.ActorID, change.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record account status change: %w", err)
+	}
+
+	return nil
+}
+
+// mergeSteps are the records a merge moves, from the merged account ($1) to the
+// surviving one ($2). A record matching duplicate, an SQL condition on the
+// merged account's row r, is dropped instead because the surviving account
+// already has its equivalent.
+var mergeSteps = []struct {
+	kind      string
+	table     string
+	column    string
+	duplicate string
+}{
+	{MergeJourneyEntries, "journey_entries", "user_id",
+		`EXISTS (SELECT 1 FROM journey_entries s WHERE s.user_id = $2 AND s.entry_date = r.entry_date)`},
+	{MergeJourneyGoals, "journey_goals", "user_id", ""},
+	{MergeJourneyMilestones, "journey_milestones", "user_id",
+		`EXISTS (SELECT 1 FROM journey_milestones s WHERE s.user_id = $2 AND s.milestone_type = r.milestone_type)`},
+	{MergeReferralsSent, "referrals", "referred_by", ""},
+	{MergeReferralsReceived, "referrals", "referred_to", ""},
+	{MergeGroupMemberships, "group_memberships", "user_id",
+		`EXISTS (SELECT 1 FROM group_memberships s WHERE s.user_id = $2 AND s.group_id = r.group_id)`},
+	{MergeFeedback, "feedback", "user_id", ""},
+}
+
+// rowQuerier is a pool or transaction
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// countMergeRecords counts each merge step's records on the merged account,
+// split into those that would move and those that would be dropped
+func countMergeRecords(ctx context.Context, db rowQuerier, mergedID, survivingID string) ([]MergeCount, error) {
+	counts := make([]MergeCount, 0, len(mergeSteps))
+	for _, step := range mergeSteps {
+		count := MergeCount{Kind: step.kind}
+		var err error
+		if step.duplicate == "" {
+			query := fmt.Sprintf(`SELECT COUNT(*) FROM %s r WHERE r.%s = $1`, step.table, step.column)
+			err = db.QueryRow(ctx, query, mergedID).Scan(&count.Moved)
+		} else {
+			query := fmt.Sprintf(`
+				SELECT COUNT(*) FILTER (WHERE NOT %[3]s), COUNT(*) FILTER (WHERE %[3]s)
+				FROM %[1]s r WHERE r.%[2]s = $1
+			`, step.table, step.column, step.duplicate)
+			err = db.QueryRow(ctx, query, mergedID, survivingID).Scan(&count.Moved, &count.Dropped)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to count %s: %w", step.kind, err)
+		}
+		counts = append(counts, count)
+	}
+
+	return counts, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// lockAccountStatus locks the user row until the transaction ends and checks it
+// still has the status the change was planned from
+func lockAccountStatus(ctx context.Context, tx pgx.Tx, userID string, expected AccountStatus) error {
+	var status AccountStatus
+	err := tx.QueryRow(ctx, `SELECT account_status FROM users WHERE id = $1 FOR UPDATE`, userID).Scan(&status)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("failed to get account status: %w", err)
+	}
+	if status != expected {
+		return ErrStatusChanged
+	}
+
+	return nil
+}
+
+// decryptProfile opens the sealed profile fields. The date of birth is stored
+// sealed as YYYY-MM-DD and perinatal details as JSON; both are parsed back into
+// the profile.
+func (s *store) decryptProfile(ctx context.Context, profile *UserProfile, dateOfBirth, perinatalDetails *string) error {
+	if err := s.cipher.DecryptFields(ctx, profile); err != nil {
+		return fmt.Errorf("failed to decrypt user profile: %w", err)
+	}
+
+	if dateOfBirth != nil && *dateOfBirth != "" {
+		plain, err := s.cipher.Decrypt(ctx, *dateOfBirth)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt date of birth: %w", err)
+		}
+
+		dob, err := time.Parse("2006-01-02", plain)
+		if err != nil {
+			return fmt.Errorf("invalid stored date of birth: %w", err)
+		}
+		profile.DateOfBirth = &dob
+	}
+
+	if perinatalDetails != nil && *perinatalDetails != "" {
+		plain, err := s.cipher.Decrypt(ctx, *perinatalDetails)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt perinatal details: %w", err)
+		}
+
+		var details perinatal.Details
+		if err := json.Unmarshal([]byte(plain), &details); err != nil {
+			return fmt.Errorf("invalid stored perinatal details: %w", err)
+		}
+		profile.PerinatalDetails = &details
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package webhooks
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
+)
+
+type handler struct {
+	service Service
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// ListSubscriptions retrieves the webhook subscriptions of the caller's organisations
+func (h *handler) ListSubscriptions(c echo.Context) error {
+	subscriptions, err := h.service.ListSubscriptions(c.Request().Context(), organisations.ScopeFromContext(c))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to list webhook subscriptions",
+		})
+	}
+
+	return c.JSON(http.StatusOK, subscriptions)
+}
+
+// GetSubscription retrieves a single webhook subscription
+func (h *handler) GetSubscription(c echo.Context) error {
+	subscription, err := h.service.GetSubscription(c.Request().Context(), organisations.ScopeFromContext(c), c.Param("id"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, subscription)
+}
+
+// CreateSubscription registers a partner endpoint; the signing secret is only returned here
+func (h *handler) CreateSubscription(c echo.Context) error {
+	var req CreateSubscriptionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	response, err := h.service.CreateSubscription(c.Request().Context(), organisations.ScopeFromContext(c), getUserIDFromContext(c), &req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+// UpdateSubscription updates a webhook subscription
+func (h *handler) UpdateSubscription(c echo.Context) error {
+	var req UpdateSubscriptionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	subscription, err := h.service.UpdateSubscription(c.Request().Context(), organisations.ScopeFromContext(c), c.Param("id"), &req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, subscription)
+}
+
+// DeleteSubscription removes a webhook subscription
+func (h *handler) DeleteSubscription(c echo.Context) error {
+	if err := h.service.DeleteSubscription(c.Request().Context(), organisations.ScopeFromContext(c), c.Param("id")); err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Webhook subscription deleted successfully",
+	})
+}
+
+// RotateSecret issues a new signing secret; the old one keeps working for a day
+func (h *handler) RotateSecret(c echo.Context) error {
+	response, err := h.service.RotateSecret(c.Request().Context(), organisations.ScopeFromContext(c), c.Param("id"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// SendPing queues a signed test delivery
+func (h *handler) SendPing(c echo.Context) error {
+	delivery, err := h.service.SendPing(c.Request().Context(), organisations.ScopeFromContext(c), c.Param("id"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusAccepted, delivery)
+}
+
+// ListDeliveries retrieves a subscription's delivery log
+func (h *handler) ListDeliveries(c echo.Context) error {
+	page := 1
+	if p := c.QueryParam("page"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+			page = parsed
+		}
+	}
+
+	pageSize := 20
+	if ps := c.QueryParam("page_size"); ps != "" {
+		if parsed, err := strconv.Atoi(ps); err == nil && parsed > 0 && parsed <= 100 {
+			pageSize = parsed
+		}
+	}
+
+	status := c.QueryParam("status")
+	if status != "" && status != DeliveryPending && status != DeliverySucceeded && status != DeliveryFailed {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid status",
+		})
+	}
+
+	req := &ListDeliveriesRequest{
+		Page:     page,
+		PageSize: pageSize,
+		Status:   status,
+		Params:   pagination.ParseQuery(c),
+	}
+
+	deliveries, err := h.service.ListDeliveries(c.Request().Context(), organisations.ScopeFromContext(c), c.Param("id"), req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, deliveries)
+}
+
+// ReplayDelivery sends a past delivery again
+func (h *handler) ReplayDelivery(c echo.Context) error {
+	delivery, err := h.service.ReplayDelivery(c.Request().Context(), organisations.ScopeFromContext(c), c.Param("id"), c.Param("delivery_id"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusAccepted, delivery)
+}
+
+// Helper functions
+
+func getUserIDFromContext(c echo.Context) string {
+	if userID, ok := c.Get("user_id").(string); ok {
+		return userID
+	}
+	return ""
+}
+
+func errorResponse(c echo.Context, err error) error {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, organisations.ErrOutOfScope):
+		status = http.StatusForbidden
+	case strings.Contains(err.Error(), "not found"):
+		status = http.StatusNotFound
+	}
+
+	return c.JSON(status, map[string]string{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,59 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perinatal-mental-health-app/backend/internal/events"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+)
+
+// Service defines the interface for webhook business logic
+type Service interface {
+	ListSubscriptions(ctx context.Context, scope organisations.Scope) (*ListSubscriptionsResponse, error)
+	GetSubscription(ctx context.Context, scope organisations.Scope, subscriptionID string) (*Subscription, error)
+	CreateSubscription(ctx context.Context, scope organisations.Scope, createdBy string, req *CreateSubscriptionRequest) (*SubscriptionSecretResponse, error)
+	UpdateSubscription(ctx context.Context, scope organisations.Scope, subscriptionID string, req *UpdateSubscriptionRequest) (*Subscription, error)
+	DeleteSubscription(ctx context.Context, scope organisations.Scope, subscriptionID string) error
+	RotateSecret(ctx context.Context, scope organisations.Scope, subscriptionID string) (*SubscriptionSecretResponse, error)
+	SendPing(ctx context.Context, scope organisations.Scope, subscriptionID string) (*Delivery, error)
+	ListDeliveries(ctx context.Context, scope organisations.Scope, subscriptionID string, req *ListDeliveriesRequest) (*ListDeliveriesResponse, error)
+	ReplayDelivery(ctx context.Context, scope organisations.Scope, subscriptionID, deliveryID string) (*Delivery, error)
+
+	// OnEvent fans a domain event out to matching subscriptions (event subscriber)
+	OnEvent(ctx context.Context, event *events.Event) error
+	// Deliver posts a queued delivery to the partner (job handler)
+	Deliver(ctx context.Context, job DeliveryJob) error
+}
+
+// Store defines the interface for webhook data persistence
+type Store interface {
+	ListSubscriptions(ctx context.Context, scope organisations.Scope) ([]Subscription, error)
+	GetSubscription(ctx context.Context, subscriptionID string) (*Subscription, error)
+	CreateSubscription(ctx context.Context, subscription *Subscription) error
+	UpdateSubscription(ctx context.Context, subscriptionID string, req *UpdateSubscriptionRequest) (*Subscription, error)
+	DeleteSubscription(ctx context.Context, subscriptionID string) error
+	RotateSecret(ctx context.Context, subscriptionID, secret string, previousSecret *string, previousExpiresAt *time.Time) error
+	// MatchSubscriptions finds active subscriptions to eventType whose organisation owns the service or support group
+	MatchSubscriptions(ctx context.Context, eventType, itemType, itemID string) ([]Subscription, error)
+
+	// CreateDelivery is idempotent per subscription and event, so a redelivered event reuses its delivery
+	CreateDelivery(ctx context.Context, delivery *Delivery) error
+	GetDelivery(ctx context.Context, deliveryID string) (*Delivery, error)
+	RecordAttempt(ctx context.Context, delivery *Delivery) error
+	ListDeliveries(ctx context.Context, subscriptionID string, req *ListDeliveriesRequest) (*ListDeliveriesResponse, error)
+}
+
+// Handler defines the interface for webhook HTTP handlers
+type Handler interface {
+	ListSubscriptions(c echo.Context) error
+	GetSubscription(c echo.Context) error
+	CreateSubscription(c echo.Context) error
+	UpdateSubscription(c echo.Context) error
+	DeleteSubscription(c echo.Context) error
+	RotateSecret(c echo.Context) error
+	SendPing(c echo.Context) error
+	ListDeliveries(c echo.Context) error
+	ReplayDelivery(c echo.Context) error
+}
@@ -0,0 +1,139 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/events"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
+)
+
+// EventPing is sent by the test endpoint so partners can check their receiver
+const EventPing = "webhook.ping"
+
+// EventTypes lists the domain events partners can subscribe to
+var EventTypes = []string{
+	events.ReferralCreated,
+	events.ReferralStatusChanged,
+	events.GroupJoined,
+	events.GroupLeft,
+}
+
+// Delivery statuses. A failed delivery is retried by the job queue until its
+// attempts run out; it can also be replayed by an admin.
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// Background job type and the queue it runs on
+const (
+	JobQueue   = "webhooks"
+	JobDeliver = "webhooks.deliver"
+)
+
+// PreviousSecretTTL is how long the old secret keeps signing deliveries after a rotation
+const PreviousSecretTTL = 24 * time.Hour
+
+// Subscription is a partner endpoint that receives events for an organisation,
+// optionally narrowed to one of its services
+type Subscription struct {
+	ID                      string     `json:"id" db:"id"`
+	OrganisationID          string     `json:"organisation_id" db:"organisation_id"`
+	ServiceID               *string    `json:"service_id,omitempty" db:"service_id"`
+	URL                     string     `json:"url" db:"url"`
+	Description             *string    `json:"description,omitempty" db:"description"`
+	EventTypes              []string   `json:"event_types" db:"event_types"`
+	IsActive                bool       `json:"is_active" db:"is_active"`
+	PreviousSecretExpiresAt *time.Time `json:"previous_secret_expires_at,omitempty" db:"previous_secret_expires_at"`
+	CreatedBy               *string    `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt               time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt               time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Sealed signing secrets; never returned after creation or rotation
+	secret         string
+	previousSecret *string
+}
+
+// Delivery is one attempt log entry for sending an event to a subscription
+type Delivery struct {
+	ID             string          `json:"id" db:"id"`
+	SubscriptionID string          `json:"subscription_id" db:"subscription_id"`
+	EventID        *string         `json:"event_id,omitempty" db:"event_id"`
+	EventType      string          `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage `json:"payload" db:"payload"`
+	Status         string          `json:"status" db:"status"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	ResponseStatus *int            `json:"response_status,omitempty" db:"response_status"`
+	ResponseBody   *string         `json:"response_body,omitempty" db:"response_body"`
+	LastError      *string         `json:"last_error,omitempty" db:"last_error"`
+	DurationMS     *int            `json:"duration_ms,omitempty" db:"duration_ms"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty" db:"delivered_at"`
+	ReplayOf       *string         `json:"replay_of,omitempty" db:"replay_of"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// Envelope is the signed JSON body posted to the partner. ID is the event ID,
+// so a replayed delivery carries the same ID and receivers can de-duplicate.
+// Data holds IDs and statuses only; no personal details leave the platform.
+type Envelope struct {
+	ID        string                 `json:"id"`
+	Type      string                 `json:"type"`
+	CreatedAt time.Time              `json:"created_at"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// DeliveryJob is the payload for a queued delivery
+type DeliveryJob struct {
+	DeliveryID string `json:"delivery_id"`
+}
+
+// CreateSubscriptionRequest represents the request to create a webhook subscription
+type CreateSubscriptionRequest struct {
+	OrganisationID string   `json:"organisation_id" validate:"required"`
+	ServiceID      *string  `json:"service_id,omitempty"`
+	URL            string   `json:"url" validate:"required,url,max=2048"`
+	Description    *string  `json:"description,omitempty" validate:"omitempty,max=255"`
+	EventTypes     []string `json:"event_types" validate:"required,min=1,dive,oneof=referral.created referral.status_changed support_group.member_joined support_group.member_left"`
+}
+
+// UpdateSubscriptionRequest represents the request to update a webhook subscription
+type UpdateSubscriptionRequest struct {
+	URL         *string  `json:"url,omitempty" validate:"omitempty,url,max=2048"`
+	Description *string  `json:"description,omitempty" validate:"omitempty,max=255"`
+	EventTypes  []string `json:"event_types,omitempty" validate:"omitempty,min=1,dive,oneof=referral.created referral.status_changed support_group.member_joined support_group.member_left"`
+	IsActive    *bool    `json:"is_active,omitempty"`
+}
+
+// SubscriptionSecretResponse returns the signing secret; it is only shown once
+type SubscriptionSecretResponse struct {
+	Subscription *Subscription `json:"subscription"`
+	Secret       string        `json:"secret"`
+}
+
+// ListSubscriptionsResponse represents the response for listing webhook subscriptions
+type ListSubscriptionsResponse struct {
+	Subscriptions []Subscription `json:"subscriptions"`
+	Total         int            `json:"total"`
+}
+
+// ListDeliveriesRequest filters a subscription's delivery log
+type ListDeliveriesRequest struct {
+	Page     int    `json:"page" validate:"min=1"`
+	PageSize int    `json:"page_size" validate:"min=1,max=100"`
+	Status   string `json:"status,omitempty" validate:"omitempty,oneof=pending succeeded failed"`
+	pagination.Params
+}
+
+// ListDeliveriesResponse represents the response for listing deliveries
+type ListDeliveriesResponse struct {
+	Deliveries []Delivery `json:"deliveries"`
+	Total      *int64     `json:"total,omitempty"`
+	Page       int        `json:"page"`
+	PageSize   int        `json:"page_size"`
+	TotalPages *int       `json:"total_pages,omitempty"`
+	NextCursor *string    `json:"next_cursor,omitempty"`
+	PrevCursor *string    `json:"prev_cursor,omitempty"`
+}
@@ -0,0 +1,470 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/encryption"
+	"github.com/perinatal-mental-health-app/backend/internal/events"
+	"github.com/perinatal-mental-health-app/backend/internal/jobs"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+)
+
+const (
+	deliveryTimeout     = 10 * time.Second
+	maxLoggedBodyLength = 1024
+)
+
+type service struct {
+	store  Store
+	cipher encryption.Cipher
+	queue  jobs.Enqueuer
+	client *http.Client
+}
+
+func NewService(store Store, cipher encryption.Cipher, queue jobs.Enqueuer) Service {
+	return &service{
+		store:  store,
+		cipher: cipher,
+		queue:  queue,
+		client: &http.Client{
+			Timeout: deliveryTimeout,
+			// Partners must configure the final URL; following redirects could send payloads elsewhere
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// ListSubscriptions retrieves the subscriptions of the caller's organisations
+func (s *service) ListSubscriptions(ctx context.Context, scope organisations.Scope) (*ListSubscriptionsResponse, error) {
+	subscriptions, err := s.store.ListSubscriptions(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscriptions == nil {
+		subscriptions = []Subscription{}
+	}
+
+	return &ListSubscriptionsResponse{
+		Subscriptions: subscriptions,
+		Total:         len(subscriptions),
+	}, nil
+}
+
+// GetSubscription retrieves a subscription in the caller's scope
+func (s *service) GetSubscription(ctx context.Context, scope organisations.Scope, subscriptionID string) (*Subscription, error) {
+	return s.getInScope(ctx, scope, subscriptionID)
+}
+
+// CreateSubscription registers a partner endpoint and returns its signing secret
+func (s *service) CreateSubscription(ctx context.Context, scope organisations.Scope, createdBy string, req *CreateSubscriptionRequest) (*SubscriptionSecretResponse, error) {
+	if !scope.Allows(req.OrganisationID) {
+		return nil, organisations.ErrOutOfScope
+	}
+
+	if err := validateURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	secret, err := GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := s.cipher.Encrypt(ctx, secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt webhook secret: %w", err)
+	}
+
+	subscription := &Subscription{
+		OrganisationID: req.OrganisationID,
+		ServiceID:      req.ServiceID,
+		URL:            req.URL,
+		Description:    req.Description,
+		EventTypes:     req.EventTypes,
+		secret:         sealed,
+	}
+	if createdBy != "" {
+		subscription.CreatedBy = &createdBy
+	}
+
+	if err := s.store.CreateSubscription(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	return &SubscriptionSecretResponse{Subscription: subscription, Secret: secret}, nil
+}
+
+// UpdateSubscription changes a subscription's endpoint, events or active flag
+func (s *service) UpdateSubscription(ctx context.Context, scope organisations.Scope, subscriptionID string, req *UpdateSubscriptionRequest) (*Subscription, error) {
+	if _, err := s.getInScope(ctx, scope, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if err := validateURL(*req.URL); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.store.UpdateSubscription(ctx, subscriptionID, req)
+}
+
+// DeleteSubscription removes a subscription and its delivery log
+func (s *service) DeleteSubscription(ctx context.Context, scope organisations.Scope, subscriptionID string) error {
+	if _, err := s.getInScope(ctx, scope, subscriptionID); err != nil {
+		return err
+	}
+
+	return s.store.DeleteSubscription(ctx, subscriptionID)
+}
+
+// RotateSecret issues a new signing secret. Deliveries are signed with both
+// secrets until the previous one expires, so partners can switch over without gaps.
+func (s *service) RotateSecret(ctx context.Context, scope organisations.Scope, subscriptionID string) (*SubscriptionSecretResponse, error) {
+	subscription, err := s.getInScope(ctx, scope, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := s.cipher.Encrypt(ctx, secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt webhook secret: %w", err)
+	}
+
+	previous := subscription.secret
+	expiresAt := time.Now().Add(PreviousSecretTTL)
+	if err := s.store.RotateSecret(ctx, subscriptionID, sealed, &previous, &expiresAt); err != nil {
+		return nil, err
+	}
+
+	subscription.PreviousSecretExpiresAt = &expiresAt
+	return &SubscriptionSecretResponse{Subscription: subscription, Secret: secret}, nil
+}
+
+// SendPing queues a test delivery so partners can check their receiver
+func (s *service) SendPing(ctx context.Context, scope organisations.Scope, subscriptionID string) (*Delivery, error) {
+	subscription, err := s.getInScope(ctx, scope, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(Envelope{
+		ID:        fmt.Sprintf("ping_%d", time.Now().UnixNano()),
+		Type:      EventPing,
+		CreatedAt: time.Now().UTC(),
+		Data:      map[string]interface{}{"subscription_id": subscription.ID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode ping: %w", err)
+	}
+
+	return s.queueDelivery(ctx, &Delivery{
+		SubscriptionID: subscription.ID,
+		EventType:      EventPing,
+		Payload:        payload,
+	})
+}
+
+// ListDeliveries retrieves a subscription's delivery log
+func (s *service) ListDeliveries(ctx context.Context, scope organisations.Scope, subscriptionID string, req *ListDeliveriesRequest) (*ListDeliveriesResponse, error) {
+	if _, err := s.getInScope(ctx, scope, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	return s.store.ListDeliveries(ctx, subscriptionID, req)
+}
+
+// ReplayDelivery sends a past delivery's payload again as a new delivery
+func (s *service) ReplayDelivery(ctx context.Context, scope organisations.Scope, subscriptionID, deliveryID string) (*Delivery, error) {
+	if _, err := s.getInScope(ctx, scope, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	original, err := s.store.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if original.SubscriptionID != subscriptionID {
+		return nil, fmt.Errorf("webhook delivery not found")
+	}
+
+	return s.queueDelivery(ctx, &Delivery{
+		SubscriptionID: subscriptionID,
+		EventType:      original.EventType,
+		Payload:        original.Payload,
+		ReplayOf:       &original.ID,
+	})
+}
+
+// OnEvent creates a delivery for every subscription interested in the event.
+// Deliveries are unique per subscription and event, so a redelivered event
+// does not notify a partner twice.
+func (s *service) OnEvent(ctx context.Context, event *events.Event) error {
+	itemType, itemID, data, err := envelopeData(event)
+	if err != nil {
+		return err
+	}
+	if itemType == "" {
+		return nil
+	}
+
+	subscriptions, err := s.store.MatchSubscriptions(ctx, event.EventType, itemType, itemID)
+	if err != nil {
+		return err
+	}
+
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(Envelope{
+		ID:        event.ID,
+		Type:      event.EventType,
+		CreatedAt: event.CreatedAt.UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	for _, subscription := range subscriptions {
+		eventID := event.ID
+		delivery := &Delivery{
+			SubscriptionID: subscription.ID,
+			EventID:        &eventID,
+			EventType:      event.EventType,
+			Payload:        payload,
+		}
+		if err := s.store.CreateDelivery(ctx, delivery); err != nil {
+			return err
+		}
+
+		// An attempted delivery already has a job
+		if delivery.Status != DeliveryPending || delivery.Attempts > 0 {
+			continue
+		}
+
+		if _, err := s.queue.Enqueue(ctx, JobDeliver, DeliveryJob{DeliveryID: delivery.ID}, &jobs.EnqueueOptions{Queue: JobQueue}); err != nil {
+			return fmt.Errorf("failed to queue webhook delivery: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Deliver signs and posts a delivery. A non-2xx response or network error is
+// returned so the job queue retries with backoff.
+func (s *service) Deliver(ctx context.Context, job DeliveryJob) error {
+	delivery, err := s.store.GetDelivery(ctx, job.DeliveryID)
+	if err != nil {
+		return err
+	}
+
+	if delivery.Status == DeliverySucceeded {
+		return nil
+	}
+
+	subscription, err := s.store.GetSubscription(ctx, delivery.SubscriptionID)
+	if err != nil {
+		return err
+	}
+
+	if !subscription.IsActive {
+		message := "subscription is inactive"
+		delivery.Status = DeliveryFailed
+		delivery.LastError = &message
+		return s.store.RecordAttempt(ctx, delivery)
+	}
+
+	secrets, err := s.signingSecrets(ctx, subscription)
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "PerinatalMentalHealth-Webhooks/1.0")
+	req.Header.Set(HeaderID, delivery.ID)
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderTimestamp, fmt.Sprintf("%d", timestamp))
+	req.Header.Set(HeaderSignature, SignatureHeader(secrets, timestamp, delivery.Payload))
+
+	started := time.Now()
+	resp, sendErr := s.client.Do(req)
+	duration := int(time.Since(started).Milliseconds())
+	delivery.DurationMS = &duration
+	delivery.ResponseStatus = nil
+	delivery.ResponseBody = nil
+	delivery.LastError = nil
+
+	if sendErr == nil {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxLoggedBodyLength))
+		resp.Body.Close()
+
+		status := resp.StatusCode
+		responseBody := string(body)
+		delivery.ResponseStatus = &status
+		delivery.ResponseBody = &responseBody
+
+		if status < 200 || status > 299 {
+			sendErr = fmt.Errorf("receiver responded with status %d", status)
+		}
+	}
+
+	if sendErr != nil {
+		message := sendErr.Error()
+		delivery.Status = DeliveryFailed
+		delivery.LastError = &message
+		if err := s.store.RecordAttempt(ctx, delivery); err != nil {
+			return err
+		}
+		return sendErr
+	}
+
+	now := time.Now()
+	delivery.Status = DeliverySucceeded
+	delivery.DeliveredAt = &now
+	return s.store.RecordAttempt(ctx, delivery)
+}
+
+// Helper functions
+
+func (s *service) getInScope(ctx context.Context, scope organisations.Scope, subscriptionID string) (*Subscription, error) {
+	subscription, err := s.store.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !scope.Allows(subscription.OrganisationID) {
+		return nil, organisations.ErrOutOfScope
+	}
+
+	return subscription, nil
+}
+
+func (s *service) queueDelivery(ctx context.Context, delivery *Delivery) (*Delivery, error) {
+	if err := s.store.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.queue.Enqueue(ctx, JobDeliver, DeliveryJob{DeliveryID: delivery.ID}, &jobs.EnqueueOptions{Queue: JobQueue}); err != nil {
+		return nil, fmt.Errorf("failed to queue webhook delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// signingSecrets returns the current secret, plus the previous one while it is still valid
+func (s *service) signingSecrets(ctx context.Context, subscription *Subscription) ([]string, error) {
+	secret, err := s.cipher.Decrypt(ctx, subscription.secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt webhook secret: %w", err)
+	}
+	secrets := []string{secret}
+
+	if subscription.previousSecret != nil && subscription.PreviousSecretExpiresAt != nil &&
+		time.Now().Before(*subscription.PreviousSecretExpiresAt) {
+		previous, err := s.cipher.Decrypt(ctx, *subscription.previousSecret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt previous webhook secret: %w", err)
+		}
+		secrets = append(secrets, previous)
+	}
+
+	return secrets, nil
+}
+
+// envelopeData maps a domain event to the item it concerns and the data shared
+// with partners. Events about items without an owning organisation return an empty item type.
+func envelopeData(event *events.Event) (string, string, map[string]interface{}, error) {
+	switch event.EventType {
+	case events.ReferralCreated:
+		var payload events.ReferralCreatedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return "", "", nil, fmt.Errorf("failed to decode %s payload: %w", event.EventType, err)
+		}
+		return referralItemType(payload.ReferralType), payload.ItemID, map[string]interface{}{
+			"referral_id":   payload.ReferralID,
+			"referral_type": payload.ReferralType,
+			"item_id":       payload.ItemID,
+			"is_urgent":     payload.IsUrgent,
+		}, nil
+
+	case events.ReferralStatusChanged:
+		var payload events.ReferralStatusChangedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return "", "", nil, fmt.Errorf("failed to decode %s payload: %w", event.EventType, err)
+		}
+		return referralItemType(payload.ReferralType), payload.ItemID, map[string]interface{}{
+			"referral_id":     payload.ReferralID,
+			"referral_type":   payload.ReferralType,
+			"item_id":         payload.ItemID,
+			"previous_status": payload.PreviousStatus,
+			"status":          payload.Status,
+		}, nil
+
+	case events.GroupJoined, events.GroupLeft:
+		var payload events.GroupMembershipPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return "", "", nil, fmt.Errorf("failed to decode %s payload: %w", event.EventType, err)
+		}
+		return "support_group", payload.GroupID, map[string]interface{}{
+			"group_id": payload.GroupID,
+		}, nil
+	}
+
+	return "", "", nil, nil
+}
+
+// referralItemType returns the owning item type for referrals partners can see
+func referralItemType(referralType string) string {
+	if referralType == "service" || referralType == "support_group" {
+		return referralType
+	}
+	return ""
+}
+
+// validateURL requires HTTPS, allowing plain HTTP only for a local receiver
+func validateURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return fmt.Errorf("invalid webhook URL")
+	}
+
+	switch parsed.Scheme {
+	case "https":
+		return nil
+	case "http":
+		host := parsed.Hostname()
+		if host == "localhost" {
+			return nil
+		}
+		if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+			return nil
+		}
+		return fmt.Errorf("webhook URL must use https")
+	default:
+		return fmt.Errorf("webhook URL must use https")
+	}
+}
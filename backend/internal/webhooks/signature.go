@@ -0,0 +1,75 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Request headers sent with every delivery
+const (
+	HeaderID        = "X-Webhook-ID"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// secretPrefix makes leaked secrets easy to recognise
+const secretPrefix = "whsec_"
+
+// GenerateSecret returns a new random signing secret
+func GenerateSecret() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return secretPrefix + base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// Sign returns the v1 signature: hex HMAC-SHA256 over "<timestamp>.<body>"
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignatureHeader signs with each secret; during a rotation receivers can
+// accept either signature
+func SignatureHeader(secrets []string, timestamp int64, body []byte) string {
+	signatures := make([]string, len(secrets))
+	for i, secret := range secrets {
+		signatures[i] = Sign(secret, timestamp, body)
+	}
+	return strings.Join(signatures, ",")
+}
+
+// VerifySignature checks a delivery the way a receiver should: the timestamp
+// must be within tolerance of now and one of the signatures must match
+func VerifySignature(secret, signatureHeader, timestampHeader string, body []byte, tolerance time.Duration, now time.Time) error {
+	timestamp, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp")
+	}
+
+	age := now.Sub(time.Unix(timestamp, 0))
+	if age > tolerance || age < -tolerance {
+		return fmt.Errorf("timestamp outside tolerance")
+	}
+
+	expected := Sign(secret, timestamp, body)
+	for _, signature := range strings.Split(signatureHeader, ",") {
+		if hmac.Equal([]byte(strings.TrimSpace(signature)), []byte(expected)) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("signature mismatch")
+}
@@ -0,0 +1,136 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/perinatal-mental-health-app/backend/internal/encryption"
+)
+
+const (
+	oldSecret = "whsec_old"
+	newSecret = "whsec_new"
+)
+
+var deliveryBody = []byte(`{"event":"referral.created","data":{"referral_id":"referral-1"}}`)
+
+func TestSignIsHMACOverTimestampAndBody(t *testing.T) {
+	mac := hmac.New(sha256.New, []byte(newSecret))
+	mac.Write([]byte("1760000000." + string(deliveryBody)))
+	want := "v1=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := Sign(newSecret, 1760000000, deliveryBody); got != want {
+		t.Errorf("Sign() = %s, want %s", got, want)
+	}
+	if Sign(newSecret, 1760000001, deliveryBody) == want {
+		t.Error("Sign() ignores the timestamp")
+	}
+}
+
+func TestVerifySignatureTolerance(t *testing.T) {
+	now := time.Unix(1760000000, 0)
+	tolerance := 5 * time.Minute
+
+	tests := []struct {
+		name      string
+		sentAt    time.Time
+		timestamp string
+		body      []byte
+		wantErr   string
+	}{
+		{"just sent", now, "", deliveryBody, ""},
+		{"at the edge of the window", now.Add(-tolerance), "", deliveryBody, ""},
+		{"replayed after the window", now.Add(-tolerance - time.Second), "", deliveryBody, "timestamp outside tolerance"},
+		{"from the future", now.Add(tolerance + time.Second), "", deliveryBody, "timestamp outside tolerance"},
+		{"unreadable timestamp", now, "yesterday", deliveryBody, "invalid timestamp"},
+		{"body changed in transit", now, "", []byte(`{"event":"referral.created"}`), "signature mismatch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signature := Sign(newSecret, tt.sentAt.Unix(), deliveryBody)
+			timestamp := tt.timestamp
+			if timestamp == "" {
+				timestamp = strconv.FormatInt(tt.sentAt.Unix(), 10)
+			}
+
+			err := VerifySignature(newSecret, signature, timestamp, tt.body, tolerance, now)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("VerifySignature() error = %v, want it accepted", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("VerifySignature() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifySignatureDuringRotation(t *testing.T) {
+	now := time.Now()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	header := SignatureHeader([]string{newSecret, oldSecret}, now.Unix(), deliveryBody)
+
+	if parts := strings.Split(header, ","); len(parts) != 2 {
+		t.Fatalf("SignatureHeader() = %q, want one signature per secret", header)
+	}
+
+	// Receivers that haven't switched yet and those that have both accept the delivery
+	for _, secret := range []string{oldSecret, newSecret} {
+		if err := VerifySignature(secret, header, timestamp, deliveryBody, time.Minute, now); err != nil {
+			t.Errorf("VerifySignature(%s) during rotation error = %v", secret, err)
+		}
+	}
+	if err := VerifySignature("whsec_other", header, timestamp, deliveryBody, time.Minute, now); err == nil {
+		t.Error("VerifySignature() accepted a secret that signed nothing")
+	}
+
+	// Once the previous secret expires only the new one signs
+	after := SignatureHeader([]string{newSecret}, now.Unix(), deliveryBody)
+	if err := VerifySignature(oldSecret, after, timestamp, deliveryBody, time.Minute, now); err == nil {
+		t.Error("VerifySignature() with the old secret accepted a delivery signed after rotation")
+	}
+}
+
+// plainCipher stores secrets unsealed so the tests can set them directly
+type plainCipher struct {
+	encryption.Cipher
+}
+
+func (plainCipher) Decrypt(ctx context.Context, value string) (string, error) {
+	return value, nil
+}
+
+func TestSigningSecretsDropPreviousAfterExpiry(t *testing.T) {
+	svc := &service{cipher: plainCipher{}}
+	previous := oldSecret
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		want      []string
+	}{
+		{"within the overlap", time.Now().Add(PreviousSecretTTL), []string{newSecret, oldSecret}},
+		{"after the overlap", time.Now().Add(-time.Second), []string{newSecret}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscription := &Subscription{secret: newSecret, previousSecret: &previous, PreviousSecretExpiresAt: &tt.expiresAt}
+
+			secrets, err := svc.signingSecrets(context.Background(), subscription)
+			if err != nil {
+				t.Fatalf("signingSecrets() error = %v", err)
+			}
+			if !reflect.DeepEqual(secrets, tt.want) {
+				t.Errorf("signingSecrets() = %v, want %v", secrets, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,397 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/perinatal-mental-health-app/backend/internal/organisations"
+	"github.com/perinatal-mental-health-app/backend/internal/pagination"
+)
+
+type store struct {
+	db *pgxpool.Pool
+}
+
+func NewStore(db *pgxpool.Pool) Store {
+	return &store{
+		db: db,
+	}
+}
+
+// deliveriesKeyset is the stable sort order used for cursor pagination
+var deliveriesKeyset = pagination.Keyset{SortColumn: "created_at", IDColumn: "id"}
+
+const subscriptionColumns = `id, organisation_id, service_id, url, description, event_types, is_active,
+	       previous_secret_expires_at, created_by, created_at, updated_at, secret, previous_secret`
+
+const deliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts, response_status,
+	       response_body, last_error, duration_ms, delivered_at, replay_of, created_at, updated_at`
+
+// ListSubscriptions retrieves the subscriptions of the organisations in scope
+func (s *store) ListSubscriptions(ctx context.Context, scope organisations.Scope) ([]Subscription, error) {
+	whereSQL := ""
+	var args []interface{}
+	if condition, scopeArgs := scope.Condition("organisation_id", 1); condition != "" {
+		whereSQL = "WHERE " + condition
+		args = scopeArgs
+	}
+
+	rows, err := s.db.Query(ctx, fmt.Sprintf(`
+		SELECT %s
+		FROM webhook_subscriptions
+		%s
+		ORDER BY created_at DESC
+	`, subscriptionColumns, whereSQL), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSubscriptions(rows)
+}
+
+// GetSubscription retrieves a subscription by ID
+func (s *store) GetSubscription(ctx context.Context, subscriptionID string) (*Subscription, error) {
+	var subscription Subscription
+	err := scanSubscription(s.db.QueryRow(ctx, `SELECT `+subscriptionColumns+` FROM webhook_subscriptions WHERE id = $1`, subscriptionID), &subscription)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("webhook subscription not found")
+		}
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+
+	return &subscription, nil
+}
+
+// CreateSubscription inserts a subscription with its sealed secret. A service
+// subscription is only created if the service belongs to the organisation.
+func (s *store) CreateSubscription(ctx context.Context, subscription *Subscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (organisation_id, service_id, url, description, event_types, secret, created_by)
+		SELECT $1, $2, $3, $4, $5, $6, $7
+		WHERE $2::uuid IS NULL OR EXISTS (SELECT 1 FROM services WHERE id = $2::uuid AND organisation_id = $1::uuid)
+		RETURNING ` + subscriptionColumns
+
+	err := scanSubscription(s.db.QueryRow(ctx, query,
+		subscription.OrganisationID, subscription.ServiceID, subscription.URL, subscription.Description,
+		subscription.EventTypes, subscription.secret, subscription.CreatedBy,
+	), subscription)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("service does not belong to the organisation")
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return fmt.Errorf("organisation or service not found")
+		}
+		return fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateSubscription updates the provided fields
+func (s *store) UpdateSubscription(ctx context.Context, subscriptionID string, req *UpdateSubscriptionRequest) (*Subscription, error) {
+	var setParts []string
+	var args []interface{}
+	argIndex := 1
+
+	if req.URL != nil {
+		setParts = append(setParts, fmt.Sprintf("url = $%d", argIndex))
+		args = append(args, *req.URL)
+		argIndex++
+	}
+
+	if req.Description != nil {
+		setParts = append(setParts, fmt.Sprintf("description = $%d", argIndex))
+		args = append(args, *req.Description)
+		argIndex++
+	}
+
+	if req.EventTypes != nil {
+		setParts = append(setParts, fmt.Sprintf("event_types = $%d", argIndex))
+		args = append(args, req.EventTypes)
+		argIndex++
+	}
+
+	if req.IsActive != nil {
+		setParts = append(setParts, fmt.Sprintf("is_active = $%d", argIndex))
+		args = append(args, *req.IsActive)
+		argIndex++
+	}
+
+	if len(setParts) == 0 {
+		return s.GetSubscription(ctx, subscriptionID)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE webhook_subscriptions
+		SET %s
+		WHERE id = $%d
+		RETURNING %s
+	`, strings.Join(setParts, ", "), argIndex, subscriptionColumns)
+	args = append(args, subscriptionID)
+
+	var subscription Subscription
+	if err := scanSubscription(s.db.QueryRow(ctx, query, args...), &subscription); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("webhook subscription not found")
+		}
+		return nil, fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+
+	return &subscription, nil
+}
+
+// DeleteSubscription removes a subscription and its delivery log
+func (s *store) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	result, err := s.db.Exec(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, subscriptionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("webhook subscription not found")
+	}
+
+	return nil
+}
+
+// RotateSecret replaces the signing secret, keeping the old one until previousExpiresAt
+func (s *store) RotateSecret(ctx context.Context, subscriptionID, secret string, previousSecret *string, previousExpiresAt *time.Time) error {
+	result, err := s.db.Exec(ctx, `
+		UPDATE webhook_subscriptions
+		SET secret = $1, previous_secret = $2, previous_secret_expires_at = $3
+		WHERE id = $4
+	`, secret, previousSecret, previousExpiresAt, subscriptionID)
+	if err != nil {
+		return fmt.Errorf("failed to rotate webhook secret: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("webhook subscription not found")
+	}
+
+	return nil
+}
+
+// MatchSubscriptions finds the active subscriptions interested in an event about
+// a service or support group. Service-specific subscriptions only match that service.
+func (s *store) MatchSubscriptions(ctx context.Context, eventType, itemType, itemID string) ([]Subscription, error) {
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM webhook_subscriptions ws
+		WHERE ws.is_active = true AND $1 = ANY(ws.event_types)
+		  AND (
+		    ($2 = 'service' AND ws.organisation_id = (SELECT organisation_id FROM services WHERE id::text = $3)
+		      AND (ws.service_id IS NULL OR ws.service_id::text = $3))
+		    OR ($2 = 'support_group' AND ws.service_id IS NULL
+		      AND ws.organisation_id = (SELECT organisation_id FROM support_groups WHERE id::text = $3))
+		  )
+	`
+
+	rows, err := s.db.Query(ctx, query, eventType, itemType, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to match webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSubscriptions(rows)
+}
+
+// CreateDelivery inserts a pending delivery, or returns the existing one for the same event
+func (s *store) CreateDelivery(ctx context.Context, delivery *Delivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, replay_of)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (subscription_id, event_id) DO UPDATE SET updated_at = NOW()
+		RETURNING ` + deliveryColumns
+
+	err := scanDelivery(s.db.QueryRow(ctx, query,
+		delivery.SubscriptionID, delivery.EventID, delivery.EventType, delivery.Payload, delivery.ReplayOf,
+	), delivery)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// GetDelivery retrieves a delivery by ID
+func (s *store) GetDelivery(ctx context.Context, deliveryID string) (*Delivery, error) {
+	var delivery Delivery
+	err := scanDelivery(s.db.QueryRow(ctx, `SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE id = $1`, deliveryID), &delivery)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("webhook delivery not found")
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	return &delivery, nil
+}
+
+// RecordAttempt stores the outcome of a send
+func (s *store) RecordAttempt(ctx context.Context, delivery *Delivery) error {
+	_, err := s.db.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = attempts + 1, response_status = $2, response_body = $3,
+		    last_error = $4, duration_ms = $5, delivered_at = $6
+		WHERE id = $7
+	`, delivery.Status, delivery.ResponseStatus, delivery.ResponseBody, delivery.LastError,
+		delivery.DurationMS, delivery.DeliveredAt, delivery.ID)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook delivery attempt: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeliveries retrieves a subscription's delivery log, newest first
+func (s *store) ListDeliveries(ctx context.Context, subscriptionID string, req *ListDeliveriesRequest) (*ListDeliveriesResponse, error) {
+	cursor, err := req.DecodeCursor()
+	if err != nil {
+		return nil, err
+	}
+
+	offset := 0
+	if cursor == nil {
+		offset = (req.Page - 1) * req.PageSize
+	}
+
+	whereClause := []string{"subscription_id = $1"}
+	args := []interface{}{subscriptionID}
+	argIndex := 2
+
+	if req.Status != "" {
+		whereClause = append(whereClause, fmt.Sprintf("status = $%d", argIndex))
+		args = append(args, req.Status)
+		argIndex++
+	}
+
+	response := &ListDeliveriesResponse{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	// Count total deliveries
+	if req.WantTotal() {
+		var total int64
+		countQuery := `SELECT COUNT(*) FROM webhook_deliveries WHERE ` + strings.Join(whereClause, " AND ")
+		if err := s.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+			return nil, fmt.Errorf("failed to count webhook deliveries: %w", err)
+		}
+
+		totalPages := pagination.TotalPages(total, req.PageSize)
+		response.Total = &total
+		response.TotalPages = &totalPages
+	}
+
+	// Continue from the cursor position
+	if cursor != nil {
+		keysetSQL, keysetArgs := deliveriesKeyset.Where(cursor, argIndex)
+		whereClause = append(whereClause, keysetSQL)
+		args = append(args, keysetArgs...)
+		argIndex += len(keysetArgs)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM webhook_deliveries
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, deliveryColumns, strings.Join(whereClause, " AND "), deliveriesKeyset.OrderBy(cursor), argIndex, argIndex+1)
+
+	args = append(args, req.PageSize+1, offset)
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []Delivery
+	for rows.Next() {
+		var delivery Delivery
+		if err := scanDelivery(rows, &delivery); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	response.Deliveries, response.NextCursor, response.PrevCursor = pagination.Paginate(deliveries, req.PageSize, cursor, offset,
+		func(delivery Delivery) pagination.Cursor {
+			return pagination.Cursor{SortValue: delivery.CreatedAt, ID: delivery.ID}
+		})
+
+	return response, nil
+}
+
+// Helper functions
+
+func scanSubscription(row pgx.Row, subscription *Subscription) error {
+	return row.Scan(
+		&subscription.ID,
+		&subscription.OrganisationID,
+		&subscription.ServiceID,
+		&subscription.URL,
+		&subscription.Description,
+		&subscription.EventTypes,
+		&subscription.IsActive,
+		&subscription.PreviousSecretExpiresAt,
+		&subscription.CreatedBy,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+		&subscription.secret,
+		&subscription.previousSecret,
+	)
+}
+
+func scanSubscriptions(rows pgx.Rows) ([]Subscription, error) {
+	var subscriptions []Subscription
+	for rows.Next() {
+		var subscription Subscription
+		if err := scanSubscription(rows, &subscription); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+func scanDelivery(row pgx.Row, delivery *Delivery) error {
+	return row.Scan(
+		&delivery.ID,
+		&delivery.SubscriptionID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.ResponseStatus,
+		&delivery.ResponseBody,
+		&delivery.LastError,
+		&delivery.DurationMS,
+		&delivery.DeliveredAt,
+		&delivery.ReplayOf,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+	)
+}
@@ -0,0 +1,25 @@
+-- Migration: 004_create_idempotency_keys_table.sql
+-- Store responses for requests carrying an Idempotency-Key header so retries can be replayed
+
+CREATE TABLE idempotency_keys (
+                                  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                  user_id VARCHAR(255) NOT NULL DEFAULT '', -- Empty for anonymous callers
+                                  idempotency_key VARCHAR(255) NOT NULL,
+                                  fingerprint VARCHAR(64) NOT NULL, -- SHA-256 of method, path and body
+                                  status_code INTEGER, -- NULL while the original request is in flight
+                                  content_type VARCHAR(255),
+                                  response_body BYTEA,
+                                  expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                                  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                  updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                  UNIQUE(user_id, idempotency_key)
+);
+
+-- Create indexes for better performance
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+
+-- Create triggers to automatically update updated_at
+CREATE TRIGGER update_idempotency_keys_updated_at
+    BEFORE UPDATE ON idempotency_keys
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,20 @@
+-- Migration: 005_create_catalog_versions_table.sql
+-- Track a version per public catalog collection so HTTP caches can be invalidated on admin writes
+
+CREATE TABLE catalog_versions (
+                                  collection VARCHAR(50) PRIMARY KEY,
+                                  version BIGINT NOT NULL DEFAULT 1,
+                                  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                  updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO catalog_versions (collection) VALUES
+    ('services'),
+    ('resources'),
+    ('support_groups');
+
+-- Create triggers to automatically update updated_at
+CREATE TRIGGER update_catalog_versions_updated_at
+    BEFORE UPDATE ON catalog_versions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
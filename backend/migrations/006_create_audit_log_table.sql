@@ -0,0 +1,40 @@
+-- Migration: 006_create_audit_log_table.sql
+-- Append-only, hash-chained log of sensitive reads and privileged writes
+
+CREATE TABLE audit_log (
+                           id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                           sequence BIGINT UNIQUE NOT NULL, -- Position in the hash chain, assigned under an advisory lock
+                           actor_id VARCHAR(255) NOT NULL DEFAULT '', -- Empty for anonymous or system actions
+                           actor_role VARCHAR(50) NOT NULL DEFAULT '',
+                           action VARCHAR(100) NOT NULL,
+                           target_type VARCHAR(50) NOT NULL,
+                           target_id VARCHAR(255) NOT NULL DEFAULT '',
+                           fields_changed TEXT[] NOT NULL DEFAULT '{}', -- Field names only, never values
+                           request_id VARCHAR(255) NOT NULL DEFAULT '',
+                           ip_address VARCHAR(45) NOT NULL DEFAULT '',
+                           user_agent TEXT NOT NULL DEFAULT '',
+                           status_code INTEGER NOT NULL DEFAULT 0,
+                           prev_hash VARCHAR(64) NOT NULL, -- Hash of the previous entry, zeros for the first
+                           hash VARCHAR(64) NOT NULL, -- SHA-256 over prev_hash and this entry's fields
+                           created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for better performance
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at DESC, id DESC);
+CREATE INDEX idx_audit_log_actor_id ON audit_log(actor_id);
+CREATE INDEX idx_audit_log_target ON audit_log(target_type, target_id);
+CREATE INDEX idx_audit_log_action ON audit_log(action);
+CREATE INDEX idx_audit_log_request_id ON audit_log(request_id);
+
+-- Entries are immutable once written
+CREATE OR REPLACE FUNCTION prevent_audit_log_modification()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER prevent_audit_log_update
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_audit_log_modification();
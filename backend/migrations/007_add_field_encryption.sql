@@ -0,0 +1,30 @@
+-- Migration: 007_add_field_encryption.sql
+-- Data keys for envelope encryption of sensitive fields, and column changes so sealed values fit
+
+CREATE TABLE encryption_keys (
+                                 id UUID PRIMARY KEY,
+                                 wrapped_key BYTEA NOT NULL, -- AES-256 data key sealed with the master key
+                                 master_key_id VARCHAR(16) NOT NULL, -- Fingerprint of the wrapping master key
+                                 status VARCHAR(20) NOT NULL CHECK (status IN ('active', 'decrypt_only', 'retired')),
+                                 created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                 updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Only one key may be active at a time
+CREATE UNIQUE INDEX idx_encryption_keys_single_active ON encryption_keys(status) WHERE status = 'active';
+
+-- Sealed values are longer than the plaintext, and dates are sealed as ISO strings
+ALTER TABLE user_profiles ALTER COLUMN phone_number TYPE TEXT;
+ALTER TABLE user_profiles ALTER COLUMN date_of_birth TYPE TEXT USING to_char(date_of_birth, 'YYYY-MM-DD');
+
+-- Blind index for exact phone number lookups
+ALTER TABLE user_profiles ADD COLUMN phone_number_bidx VARCHAR(64);
+
+-- Create indexes for better performance
+CREATE INDEX idx_user_profiles_phone_number_bidx ON user_profiles(phone_number_bidx);
+
+-- Create triggers to automatically update updated_at
+CREATE TRIGGER update_encryption_keys_updated_at
+    BEFORE UPDATE ON encryption_keys
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
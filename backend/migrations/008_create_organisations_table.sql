@@ -0,0 +1,52 @@
+-- Migration: 008_create_organisations_table.sql
+-- Organisations (NHS trusts, charities, GP practices) that own services and groups and scope staff access
+
+CREATE TABLE organisations (
+                               id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                               name VARCHAR(255) NOT NULL,
+                               organisation_type VARCHAR(50) NOT NULL CHECK (organisation_type IN ('nhs_trust', 'charity', 'gp_practice', 'other')),
+                               ods_code VARCHAR(20) UNIQUE, -- NHS Organisation Data Service code, where one exists
+                               contact_email VARCHAR(255),
+                               is_active BOOLEAN DEFAULT true,
+                               created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                               updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Staff belong to one or more organisations
+CREATE TABLE organisation_members (
+                                      id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                      organisation_id UUID NOT NULL REFERENCES organisations(id) ON DELETE CASCADE,
+                                      user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                      role VARCHAR(20) NOT NULL DEFAULT 'member' CHECK (role IN ('member', 'admin')),
+                                      created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                      updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                      UNIQUE(organisation_id, user_id) -- Prevent duplicate memberships
+);
+
+-- Super admins see every organisation's data
+ALTER TABLE users ADD COLUMN is_super_admin BOOLEAN NOT NULL DEFAULT false;
+
+-- Services, support groups and feedback are owned by an organisation
+ALTER TABLE services ADD COLUMN organisation_id UUID REFERENCES organisations(id) ON DELETE SET NULL;
+ALTER TABLE support_groups ADD COLUMN organisation_id UUID REFERENCES organisations(id) ON DELETE SET NULL;
+ALTER TABLE feedback ADD COLUMN organisation_id UUID REFERENCES organisations(id) ON DELETE SET NULL;
+
+-- Create indexes for better performance
+CREATE INDEX idx_organisations_type ON organisations(organisation_type);
+CREATE INDEX idx_organisations_active ON organisations(is_active);
+CREATE INDEX idx_organisation_members_user_id ON organisation_members(user_id);
+CREATE INDEX idx_organisation_members_organisation_id ON organisation_members(organisation_id);
+CREATE INDEX idx_services_organisation_id ON services(organisation_id);
+CREATE INDEX idx_support_groups_organisation_id ON support_groups(organisation_id);
+CREATE INDEX idx_feedback_organisation_id ON feedback(organisation_id);
+
+-- Create triggers to automatically update updated_at
+CREATE TRIGGER update_organisations_updated_at
+    BEFORE UPDATE ON organisations
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_organisation_members_updated_at
+    BEFORE UPDATE ON organisation_members
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,32 @@
+-- Migration: 009_create_jobs_table.sql
+-- Background job queue; workers claim due jobs with FOR UPDATE SKIP LOCKED
+
+CREATE TABLE jobs (
+                      id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                      queue VARCHAR(50) NOT NULL DEFAULT 'default',
+                      job_type VARCHAR(100) NOT NULL,
+                      payload JSONB NOT NULL DEFAULT '{}',
+                      status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'dead')),
+                      attempts INTEGER NOT NULL DEFAULT 0,
+                      max_attempts INTEGER NOT NULL DEFAULT 5 CHECK (max_attempts > 0),
+                      run_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP, -- Not claimed before this time
+                      locked_at TIMESTAMP WITH TIME ZONE,
+                      locked_by VARCHAR(255), -- Worker that claimed the job
+                      last_error TEXT,
+                      completed_at TIMESTAMP WITH TIME ZONE,
+                      created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                      updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for better performance
+CREATE INDEX idx_jobs_due ON jobs(queue, run_at) WHERE status = 'pending';
+CREATE INDEX idx_jobs_running ON jobs(locked_at) WHERE status = 'running';
+CREATE INDEX idx_jobs_status ON jobs(status);
+CREATE INDEX idx_jobs_job_type ON jobs(job_type);
+CREATE INDEX idx_jobs_created_at ON jobs(created_at DESC, id DESC);
+
+-- Create triggers to automatically update updated_at
+CREATE TRIGGER update_jobs_updated_at
+    BEFORE UPDATE ON jobs
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,29 @@
+-- Migration: 010_create_outbox_events_table.sql
+-- Transactional outbox; domain events are written with the state change and dispatched to subscribers afterwards
+
+CREATE TABLE outbox_events (
+                               id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                               event_type VARCHAR(100) NOT NULL,
+                               aggregate_id VARCHAR(255) NOT NULL, -- ID of the entity the event is about
+                               payload JSONB NOT NULL DEFAULT '{}',
+                               status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'dispatched', 'dead')),
+                               attempts INTEGER NOT NULL DEFAULT 0,
+                               delivered_to TEXT[] NOT NULL DEFAULT '{}', -- Subscribers that have handled the event
+                               next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                               last_error TEXT,
+                               dispatched_at TIMESTAMP WITH TIME ZONE,
+                               created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                               updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for better performance
+CREATE INDEX idx_outbox_events_due ON outbox_events(next_attempt_at, created_at) WHERE status = 'pending';
+CREATE INDEX idx_outbox_events_event_type ON outbox_events(event_type);
+CREATE INDEX idx_outbox_events_aggregate_id ON outbox_events(aggregate_id);
+CREATE INDEX idx_outbox_events_dispatched_at ON outbox_events(dispatched_at) WHERE status = 'dispatched';
+
+-- Create triggers to automatically update updated_at
+CREATE TRIGGER update_outbox_events_updated_at
+    BEFORE UPDATE ON outbox_events
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,55 @@
+-- Migration: 011_create_webhooks_tables.sql
+-- Outbound webhook subscriptions for partner organisations and their delivery log
+
+CREATE TABLE webhook_subscriptions (
+                                       id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                       organisation_id UUID NOT NULL REFERENCES organisations(id) ON DELETE CASCADE,
+                                       service_id UUID REFERENCES services(id) ON DELETE CASCADE, -- Narrows referral events to one service
+                                       url TEXT NOT NULL,
+                                       description VARCHAR(255),
+                                       event_types TEXT[] NOT NULL,
+                                       secret TEXT NOT NULL, -- Encrypted signing secret
+                                       previous_secret TEXT, -- Encrypted; still signs deliveries until it expires
+                                       previous_secret_expires_at TIMESTAMP WITH TIME ZONE,
+                                       is_active BOOLEAN NOT NULL DEFAULT true,
+                                       created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+                                       created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                       updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE webhook_deliveries (
+                                    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                    subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+                                    event_id UUID, -- Outbox event; NULL for test pings and replays
+                                    event_type VARCHAR(100) NOT NULL,
+                                    payload JSONB NOT NULL,
+                                    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+                                    attempts INTEGER NOT NULL DEFAULT 0,
+                                    response_status INTEGER,
+                                    response_body TEXT, -- Truncated
+                                    last_error TEXT,
+                                    duration_ms INTEGER,
+                                    delivered_at TIMESTAMP WITH TIME ZONE,
+                                    replay_of UUID REFERENCES webhook_deliveries(id) ON DELETE SET NULL,
+                                    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                    UNIQUE(subscription_id, event_id)
+);
+
+-- Create indexes for better performance
+CREATE INDEX idx_webhook_subscriptions_organisation_id ON webhook_subscriptions(organisation_id);
+CREATE INDEX idx_webhook_subscriptions_service_id ON webhook_subscriptions(service_id);
+CREATE INDEX idx_webhook_subscriptions_event_types ON webhook_subscriptions USING GIN(event_types);
+CREATE INDEX idx_webhook_deliveries_subscription_id ON webhook_deliveries(subscription_id, created_at DESC, id DESC);
+CREATE INDEX idx_webhook_deliveries_status ON webhook_deliveries(status);
+
+-- Create triggers to automatically update updated_at
+CREATE TRIGGER update_webhook_subscriptions_updated_at
+    BEFORE UPDATE ON webhook_subscriptions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_webhook_deliveries_updated_at
+    BEFORE UPDATE ON webhook_deliveries
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,4 @@
+-- Migration: 012_add_token_revocation.sql
+-- Tokens issued before tokens_revoked_at are rejected
+
+ALTER TABLE users ADD COLUMN tokens_revoked_at TIMESTAMP WITH TIME ZONE;
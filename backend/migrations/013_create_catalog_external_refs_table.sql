@@ -0,0 +1,18 @@
+-- Migration: 013_create_catalog_external_refs_table.sql
+-- Map the references partners use for catalog items to our IDs so bulk imports can upsert
+
+CREATE TABLE catalog_external_refs (
+                                       collection VARCHAR(50) NOT NULL CHECK (collection IN ('services', 'resources', 'support_groups')),
+                                       external_ref VARCHAR(255) NOT NULL,
+                                       item_id UUID NOT NULL, -- services, resources or support_groups row, depending on collection
+                                       created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                       updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                       PRIMARY KEY (collection, external_ref),
+                                       UNIQUE (collection, item_id) -- One reference per item
+);
+
+-- Create triggers to automatically update updated_at
+CREATE TRIGGER update_catalog_external_refs_updated_at
+    BEFORE UPDATE ON catalog_external_refs
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,46 @@
+-- Migration: 014_create_care_team_tables.sql
+-- Care-team relationships between professionals and service users, and audited break-glass access
+
+CREATE TABLE care_team_relationships (
+                                         id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                         professional_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                         service_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                         status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'active', 'declined', 'ended')),
+                                         source VARCHAR(20) NOT NULL CHECK (source IN ('invite', 'admin')),
+                                         reason TEXT NOT NULL, -- Encrypted
+                                         share_journey BOOLEAN NOT NULL DEFAULT false, -- Set by the service user
+                                         starts_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                         ends_at TIMESTAMP WITH TIME ZONE, -- NULL while open-ended
+                                         created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+                                         accepted_at TIMESTAMP WITH TIME ZONE,
+                                         ended_at TIMESTAMP WITH TIME ZONE,
+                                         ended_by UUID REFERENCES users(id) ON DELETE SET NULL,
+                                         end_reason TEXT, -- Encrypted
+                                         created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                         updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                         CHECK (ends_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE TABLE break_glass_access (
+                                    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                    staff_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                    service_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                    reason TEXT NOT NULL, -- Encrypted
+                                    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                                    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A professional has at most one open relationship with each service user
+CREATE UNIQUE INDEX idx_care_team_relationships_open_pair ON care_team_relationships(professional_id, service_user_id) WHERE status IN ('pending', 'active');
+
+-- Create indexes for better performance
+CREATE INDEX idx_care_team_relationships_professional_id ON care_team_relationships(professional_id, status);
+CREATE INDEX idx_care_team_relationships_service_user_id ON care_team_relationships(service_user_id, status);
+CREATE INDEX idx_break_glass_access_staff_id ON break_glass_access(staff_id, service_user_id, expires_at);
+CREATE INDEX idx_break_glass_access_service_user_id ON break_glass_access(service_user_id);
+
+-- Create triggers to automatically update updated_at
+CREATE TRIGGER update_care_team_relationships_updated_at
+    BEFORE UPDATE ON care_team_relationships
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
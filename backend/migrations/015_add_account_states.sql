@@ -0,0 +1,22 @@
+-- Migration: 015_add_account_states.sql
+-- Account states with a history of transitions, and scheduled erasure of accounts pending deletion
+
+-- is_active is kept in step with account_status = 'active' so existing queries keep working
+ALTER TABLE users ADD COLUMN account_status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (account_status IN ('active', 'suspended', 'pending_deletion', 'deleted'));
+ALTER TABLE users ADD COLUMN deletion_scheduled_at TIMESTAMP WITH TIME ZONE; -- Set while pending deletion
+
+-- Accounts deactivated before states existed can be reactivated
+UPDATE users SET account_status = 'suspended' WHERE is_active = false;
+
+CREATE TABLE account_status_changes (
+                                        id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                        user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                        from_status VARCHAR(20) NOT NULL,
+                                        to_status VARCHAR(20) NOT NULL,
+                                        reason TEXT NOT NULL, -- Encrypted
+                                        actor_id UUID REFERENCES users(id) ON DELETE SET NULL, -- NULL for scheduled jobs and the admin CLI
+                                        created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_users_account_status ON users(account_status, deletion_scheduled_at);
+CREATE INDEX idx_account_status_changes_user_id ON account_status_changes(user_id, created_at);
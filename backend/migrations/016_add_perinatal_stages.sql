@@ -0,0 +1,17 @@
+-- Migration: 016_add_perinatal_stages.sql
+-- Pregnancy and birth details on user profiles, and the perinatal stages catalog items suit
+
+ALTER TABLE user_profiles ADD COLUMN perinatal_details TEXT; -- Encrypted JSON: due date, birth dates, loss date, baby count
+
+-- Stages an item suits; an empty list suits every stage
+ALTER TABLE services ADD COLUMN perinatal_stages TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE resources ADD COLUMN perinatal_stages TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE support_groups ADD COLUMN perinatal_stages TEXT[] NOT NULL DEFAULT '{}';
+
+-- Prenatal and postnatal groups already say which stages they are for
+UPDATE support_groups SET perinatal_stages = '{first_trimester,second_trimester,third_trimester}' WHERE category = 'prenatal';
+UPDATE support_groups SET perinatal_stages = '{postnatal}' WHERE category = 'postnatal';
+
+CREATE INDEX idx_services_perinatal_stages ON services USING GIN(perinatal_stages);
+CREATE INDEX idx_resources_perinatal_stages ON resources USING GIN(perinatal_stages);
+CREATE INDEX idx_support_groups_perinatal_stages ON support_groups USING GIN(perinatal_stages);
@@ -0,0 +1,29 @@
+-- Migration: 017_create_emergency_contacts_table.sql
+-- Structured emergency contacts, each with the user's consent for their care team to get in touch
+
+CREATE TABLE emergency_contacts (
+                                    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                    name TEXT NOT NULL, -- Encrypted
+                                    relationship TEXT NOT NULL, -- Encrypted
+                                    phone TEXT, -- Encrypted
+                                    email TEXT, -- Encrypted
+                                    preferred_channel VARCHAR(20) NOT NULL CHECK (preferred_channel IN ('phone', 'sms', 'email')),
+                                    care_team_may_contact BOOLEAN NOT NULL DEFAULT false,
+                                    contact_circumstances TEXT[] NOT NULL DEFAULT '{}', -- When the care team may get in touch
+                                    consent_notes TEXT, -- Encrypted
+                                    consent_updated_at TIMESTAMP WITH TIME ZONE,
+                                    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                    CHECK (phone IS NOT NULL OR email IS NOT NULL),
+                                    CHECK (care_team_may_contact OR cardinality(contact_circumstances) = 0)
+);
+
+-- Create indexes for better performance
+CREATE INDEX idx_emergency_contacts_user_id ON emergency_contacts(user_id, created_at);
+
+-- Create triggers to automatically update updated_at
+CREATE TRIGGER update_emergency_contacts_updated_at
+    BEFORE UPDATE ON emergency_contacts
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
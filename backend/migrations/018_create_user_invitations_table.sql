@@ -0,0 +1,16 @@
+-- Migration: 018_create_user_invitations_table.sql
+-- Invitations for staff accounts created by bulk import; only a hash of each token is stored
+
+CREATE TABLE user_invitations (
+                                  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                  token_hash VARCHAR(64) NOT NULL UNIQUE, -- SHA-256 of the token
+                                  organisation_id UUID REFERENCES organisations(id) ON DELETE SET NULL,
+                                  invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+                                  expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                                  accepted_at TIMESTAMP WITH TIME ZONE,
+                                  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for better performance
+CREATE INDEX idx_user_invitations_user_id ON user_invitations(user_id) WHERE accepted_at IS NULL;
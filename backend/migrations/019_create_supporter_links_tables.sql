@@ -0,0 +1,42 @@
+-- Migration: 019_create_supporter_links_tables.sql
+-- Partner and family supporter links with the service user's sharing choices, and crisis plans they can share
+
+CREATE TABLE supporter_links (
+                                 id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                 service_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                 supporter_id UUID REFERENCES users(id) ON DELETE CASCADE, -- NULL until the invitation is accepted
+                                 relationship VARCHAR(20) NOT NULL CHECK (relationship IN ('partner', 'family')),
+                                 status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'active', 'revoked')),
+                                 shares TEXT[] NOT NULL DEFAULT '{}', -- Set by the service user
+                                 code_hash VARCHAR(64) NOT NULL UNIQUE, -- SHA-256 of the invitation code
+                                 code_expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                                 accepted_at TIMESTAMP WITH TIME ZONE,
+                                 revoked_at TIMESTAMP WITH TIME ZONE,
+                                 revoked_by UUID REFERENCES users(id) ON DELETE SET NULL,
+                                 created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                 updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                 CHECK (status = 'pending' OR status = 'revoked' OR supporter_id IS NOT NULL),
+                                 CHECK (supporter_id IS NULL OR supporter_id <> service_user_id)
+);
+
+CREATE TABLE crisis_plans (
+                              user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+                              warning_signs TEXT, -- Encrypted
+                              coping_strategies TEXT, -- Encrypted
+                              how_to_help TEXT, -- Encrypted
+                              professional_contacts TEXT, -- Encrypted
+                              updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A supporter has at most one active link with each service user
+CREATE UNIQUE INDEX idx_supporter_links_active_pair ON supporter_links(service_user_id, supporter_id) WHERE status = 'active';
+
+-- Create indexes for better performance
+CREATE INDEX idx_supporter_links_service_user_id ON supporter_links(service_user_id, created_at);
+CREATE INDEX idx_supporter_links_supporter_id ON supporter_links(supporter_id, status);
+
+-- Create triggers to automatically update updated_at
+CREATE TRIGGER update_supporter_links_updated_at
+    BEFORE UPDATE ON supporter_links
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
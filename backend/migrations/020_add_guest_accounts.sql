@@ -0,0 +1,18 @@
+-- Migration: 020_add_guest_accounts.sql
+-- Guest sessions that can later be upgraded to full accounts, and bookmarks of catalog items
+
+-- Guests have a placeholder email and no password until they register
+ALTER TABLE users ADD COLUMN is_guest BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE users ADD COLUMN guest_expires_at TIMESTAMP WITH TIME ZONE; -- Set while a guest; deleted with their data after this
+ALTER TABLE users ADD CONSTRAINT users_guest_expiry CHECK (is_guest = (guest_expires_at IS NOT NULL));
+
+CREATE TABLE bookmarks (
+                           id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                           user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                           item_type VARCHAR(20) NOT NULL CHECK (item_type IN ('service', 'resource', 'support_group')),
+                           item_id UUID NOT NULL,
+                           created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                           UNIQUE (user_id, item_type, item_id)
+);
+
+CREATE INDEX idx_bookmarks_user_id ON bookmarks(user_id, created_at);
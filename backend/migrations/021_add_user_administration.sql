@@ -0,0 +1,24 @@
+-- Migration: 021_add_user_administration.sql
+-- Staff-initiated email changes confirmed from the new address, and a record of merged duplicate accounts
+
+CREATE TABLE email_changes (
+                               user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE, -- At most one pending change per account
+                               new_email VARCHAR(255) NOT NULL,
+                               token_hash VARCHAR(64) NOT NULL UNIQUE, -- SHA-256 of the token sent to the new address
+                               requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+                               expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                               created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE account_merges (
+                                id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                merged_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                surviving_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+                                records JSONB NOT NULL, -- How many of each kind of record moved or were dropped as duplicates
+                                created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                CHECK (merged_user_id <> surviving_user_id)
+);
+
+CREATE INDEX idx_account_merges_merged ON account_merges(merged_user_id);
+CREATE INDEX idx_account_merges_surviving ON account_merges(surviving_user_id);
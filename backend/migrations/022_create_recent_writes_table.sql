@@ -0,0 +1,9 @@
+-- Migration: 022_create_recent_writes_table.sql
+-- When each API client last wrote, so every instance sends its reads to the primary until the replica catches up
+
+CREATE TABLE recent_writes (
+                               client_key VARCHAR(64) PRIMARY KEY, -- SHA-256 of the client's Authorization header
+                               written_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_recent_writes_written_at ON recent_writes(written_at);